@@ -1,14 +1,12 @@
 package app
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis/v8"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
@@ -20,6 +18,7 @@ import (
 	"scroll-tech/bridge-history-api/internal/config"
 	"scroll-tech/bridge-history-api/internal/controller/api"
 	"scroll-tech/bridge-history-api/internal/route"
+	butils "scroll-tech/bridge-history-api/internal/utils"
 )
 
 var app *cli.App
@@ -54,24 +53,20 @@ func action(ctx *cli.Context) error {
 			log.Error("failed to close db", "err", err)
 		}
 	}()
-	opts := &redis.Options{
-		Addr:         cfg.Redis.Address,
-		Username:     cfg.Redis.Username,
-		Password:     cfg.Redis.Password,
-		MinIdleConns: cfg.Redis.MinIdleConns,
-		ReadTimeout:  time.Duration(cfg.Redis.ReadTimeoutMs * int(time.Millisecond)),
+	readDB, err := database.InitReadDB(ctx.Context, cfg.DB, db)
+	if err != nil {
+		log.Crit("failed to init read db", "err", err)
 	}
-	// Production Redis service has enabled transit_encryption.
-	if !cfg.Redis.Local {
-		opts.TLSConfig = &tls.Config{
-			MinVersion:         tls.VersionTLS12,
-			InsecureSkipVerify: true, //nolint:gosec
-		}
+	redisClient := butils.NewRedisClient(cfg.Redis)
+	api.InitController(ctx.Context, db, readDB, redisClient, cfg.Subscription)
+	if readDB != db {
+		observability.RegisterReadinessCheck("read_db", observability.DBCheck(readDB))
+	}
+	if redisClient != nil {
+		observability.RegisterReadinessCheck("redis", func(checkCtx context.Context) error {
+			return redisClient.Ping(checkCtx).Err()
+		})
 	}
-	log.Info("init redis client", "addr", opts.Addr, "user name", opts.Username, "is local", cfg.Redis.Local,
-		"min idle connections", opts.MinIdleConns, "read timeout", opts.ReadTimeout)
-	redisClient := redis.NewClient(opts)
-	api.InitController(db, redisClient)
 
 	router := gin.Default()
 	registry := prometheus.DefaultRegisterer
@@ -16,6 +16,7 @@ import (
 
 	"scroll-tech/bridge-history-api/internal/config"
 	"scroll-tech/bridge-history-api/internal/controller/fetcher"
+	butils "scroll-tech/bridge-history-api/internal/utils"
 )
 
 var app *cli.App
@@ -67,11 +68,16 @@ func action(ctx *cli.Context) error {
 	}
 
 	observability.Server(ctx, db)
+	observability.RegisterReadinessCheck("l1_rpc", observability.RPCCheck(l1Client))
+	observability.RegisterReadinessCheck("l2_rpc", observability.RPCCheck(l2Client))
 
-	l1MessageFetcher := fetcher.NewL1MessageFetcher(subCtx, cfg.L1, db, l1Client)
+	// the fetchers invalidate the api cache of the txs they update.
+	redisClient := butils.NewRedisClient(cfg.Redis)
+
+	l1MessageFetcher := fetcher.NewL1MessageFetcher(subCtx, cfg.L1, db, redisClient, l1Client)
 	go l1MessageFetcher.Start()
 
-	l2MessageFetcher := fetcher.NewL2MessageFetcher(subCtx, cfg.L2, db, l2Client)
+	l2MessageFetcher := fetcher.NewL2MessageFetcher(subCtx, cfg.L2, db, redisClient, l2Client)
 	go l2MessageFetcher.Start()
 
 	// Catch CTRL-C to ensure a graceful shutdown.
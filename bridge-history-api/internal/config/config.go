@@ -41,12 +41,19 @@ type RedisConfig struct {
 	ReadTimeoutMs int    `json:"readTimeoutMs"`
 }
 
+// SubscriptionConfig limits the tx status subscriptions of the api
+type SubscriptionConfig struct {
+	MaxAddresses   int `json:"maxAddresses"`   // max addresses of a subscription, 100 by default.
+	MaxSubscribers int `json:"maxSubscribers"` // max concurrent subscriptions, 10000 by default.
+}
+
 // Config is the configuration of the bridge history backend
 type Config struct {
-	L1    *FetcherConfig   `json:"L1"`
-	L2    *FetcherConfig   `json:"L2"`
-	DB    *database.Config `json:"db"`
-	Redis *RedisConfig     `json:"redis"`
+	L1           *FetcherConfig      `json:"L1"`
+	L2           *FetcherConfig      `json:"L2"`
+	DB           *database.Config    `json:"db"`
+	Redis        *RedisConfig        `json:"redis"`        // optional, the cache is disabled without it.
+	Subscription *SubscriptionConfig `json:"subscription"` // optional, the default limits apply without it.
 }
 
 // NewConfig returns a new instance of Config.
@@ -1,22 +1,33 @@
 package api
 
 import (
+	"context"
 	"sync"
 
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/config"
 )
 
 var (
 	// HistoryCtrler is controller instance
 	HistoryCtrler *HistoryController
+	// GraphQLCtrler is controller instance
+	GraphQLCtrler *GraphQLController
+	// SubscriptionCtrler is controller instance
+	SubscriptionCtrler *SubscriptionController
 
 	initControllerOnce sync.Once
 )
 
-// InitController inits Controller with database
-func InitController(db *gorm.DB, redis *redis.Client) {
+// InitController inits Controller with database, the background services stop once the context is done. The history
+// queries are served by readDB, the subscriptions poll the updates from the primary db as they must not miss any.
+// The subscriptions are limited by subscriptionCfg, the default limits apply if it is nil.
+func InitController(ctx context.Context, db, readDB *gorm.DB, redis *redis.Client, subscriptionCfg *config.SubscriptionConfig) {
 	initControllerOnce.Do(func() {
-		HistoryCtrler = NewHistoryController(db, redis)
+		HistoryCtrler = NewHistoryController(readDB, redis)
+		GraphQLCtrler = NewGraphQLController(readDB, redis)
+		SubscriptionCtrler = NewSubscriptionController(ctx, db, subscriptionCfg)
 	})
 }
@@ -0,0 +1,56 @@
+package api
+
+import (
+	// embed the graphql schema
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+//go:embed graphql_schema.graphql
+var graphQLSchema string
+
+const (
+	// a tx, its batch and their fields.
+	graphQLMaxDepth       = 5
+	graphQLMaxParallelism = 10
+)
+
+// GraphQLController contains the graphql service over the bridge history
+type GraphQLController struct {
+	schema *graphql.Schema
+}
+
+// graphQLRequest the request body of graphql api
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewGraphQLController return GraphQLController instance
+func NewGraphQLController(db *gorm.DB, redis *redis.Client) *GraphQLController {
+	resolver := &graphQLResolver{historyLogic: logic.NewHistoryLogic(db, redis)}
+	return &GraphQLController{
+		schema: graphql.MustParseSchema(graphQLSchema, resolver, graphql.MaxDepth(graphQLMaxDepth), graphql.MaxParallelism(graphQLMaxParallelism)),
+	}
+}
+
+// Query defines the http post method behavior
+func (c *GraphQLController) Query(ctx *gin.Context) {
+	var req graphQLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	// graphql reports the errors in the response itself.
+	ctx.JSON(http.StatusOK, c.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
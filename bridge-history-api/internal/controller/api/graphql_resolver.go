@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/orm"
+	"scroll-tech/bridge-history-api/internal/types"
+	"scroll-tech/bridge-history-api/internal/utils"
+)
+
+// graphQLLong is the Long scalar of the graphql schema.
+type graphQLLong uint64
+
+// ImplementsGraphQLType implements the graphql custom scalar interface.
+func (graphQLLong) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL implements the graphql custom scalar interface, it accepts integers and decimal strings.
+func (l *graphQLLong) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		if input < 0 {
+			return fmt.Errorf("negative Long: %d", input)
+		}
+		*l = graphQLLong(input)
+	case float64:
+		if input < 0 || input != float64(uint64(input)) {
+			return fmt.Errorf("invalid Long: %v", input)
+		}
+		*l = graphQLLong(input)
+	case string:
+		value, err := strconv.ParseUint(input, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long: %w", err)
+		}
+		*l = graphQLLong(value)
+	default:
+		return fmt.Errorf("unexpected Long type: %T", input)
+	}
+	return nil
+}
+
+type graphQLResolver struct {
+	historyLogic *logic.HistoryLogic
+}
+
+func (r *graphQLResolver) Transactions(ctx context.Context, args struct {
+	Address string
+	First   int32
+	After   *string
+}) (*transactionPageResolver, error) {
+	if args.First < 1 || args.First > 100 {
+		return nil, errors.New("first must be between 1 and 100")
+	}
+	var cursor *orm.MessageCursor
+	if args.After != nil {
+		cursor = &orm.MessageCursor{}
+		if err := utils.DecodeCursor(*args.After, cursor); err != nil {
+			return nil, err
+		}
+	}
+	page, err := r.historyLogic.GetTxsByAddress(ctx, args.Address, nil, cursor, uint64(args.First))
+	if err != nil {
+		return nil, err
+	}
+	return &transactionPageResolver{historyLogic: r.historyLogic, page: page}, nil
+}
+
+func (r *graphQLResolver) TransactionsByHashes(ctx context.Context, args struct{ Hashes []string }) ([]*transactionResolver, error) {
+	if len(args.Hashes) > 100 {
+		return nil, errors.New("at most 100 hashes")
+	}
+	txs, err := r.historyLogic.GetTxsByHashes(ctx, args.Hashes)
+	if err != nil {
+		return nil, err
+	}
+	return newTransactionResolvers(r.historyLogic, txs), nil
+}
+
+func (r *graphQLResolver) Batch(ctx context.Context, args struct{ Index graphQLLong }) (*batchResolver, error) {
+	batch, err := r.historyLogic.GetBatchByIndex(ctx, uint64(args.Index))
+	if err != nil || batch == nil {
+		return nil, err
+	}
+	return &batchResolver{batch: batch}, nil
+}
+
+func (r *graphQLResolver) TokenMapping(ctx context.Context, args struct{ Token string }) (*tokenMappingResolver, error) {
+	if !common.IsHexAddress(args.Token) {
+		return nil, fmt.Errorf("invalid token address: %s", args.Token)
+	}
+	mapping, err := r.historyLogic.GetTokenMapping(ctx, common.HexToAddress(args.Token).String())
+	if err != nil || mapping == nil {
+		return nil, err
+	}
+	return &tokenMappingResolver{mapping: mapping}, nil
+}
+
+type transactionPageResolver struct {
+	historyLogic *logic.HistoryLogic
+	page         *types.PagedResultData
+}
+
+func (r *transactionPageResolver) Results() []*transactionResolver {
+	return newTransactionResolvers(r.historyLogic, r.page.Results)
+}
+
+func (r *transactionPageResolver) NextCursor() *string {
+	if r.page.NextCursor == "" {
+		return nil
+	}
+	return &r.page.NextCursor
+}
+
+type transactionResolver struct {
+	historyLogic *logic.HistoryLogic
+	tx           *types.TxHistoryInfo
+}
+
+func newTransactionResolvers(historyLogic *logic.HistoryLogic, txs []*types.TxHistoryInfo) []*transactionResolver {
+	resolvers := make([]*transactionResolver, len(txs))
+	for i, tx := range txs {
+		resolvers[i] = &transactionResolver{historyLogic: historyLogic, tx: tx}
+	}
+	return resolvers
+}
+
+func (r *transactionResolver) Hash() string                { return r.tx.Hash }
+func (r *transactionResolver) ReplayTxHash() string        { return r.tx.ReplayTxHash }
+func (r *transactionResolver) RefundTxHash() string        { return r.tx.RefundTxHash }
+func (r *transactionResolver) MessageHash() string         { return r.tx.MessageHash }
+func (r *transactionResolver) TokenType() int32            { return int32(r.tx.TokenType) }
+func (r *transactionResolver) TokenIds() []string          { return r.tx.TokenIDs }
+func (r *transactionResolver) TokenAmounts() []string      { return r.tx.TokenAmounts }
+func (r *transactionResolver) MessageType() int32          { return int32(r.tx.MessageType) }
+func (r *transactionResolver) L1TokenAddress() string      { return r.tx.L1TokenAddress }
+func (r *transactionResolver) L2TokenAddress() string      { return r.tx.L2TokenAddress }
+func (r *transactionResolver) BlockNumber() graphQLLong    { return graphQLLong(r.tx.BlockNumber) }
+func (r *transactionResolver) BlockTimestamp() graphQLLong { return graphQLLong(r.tx.BlockTimestamp) }
+func (r *transactionResolver) TxStatus() int32             { return int32(r.tx.TxStatus) }
+
+func (r *transactionResolver) CounterpartChainTx() *counterpartChainTxResolver {
+	if r.tx.CounterpartChainTx == nil || r.tx.CounterpartChainTx.Hash == "" {
+		return nil
+	}
+	return &counterpartChainTxResolver{tx: r.tx.CounterpartChainTx}
+}
+
+func (r *transactionResolver) ClaimInfo() *claimInfoResolver {
+	if r.tx.ClaimInfo == nil {
+		return nil
+	}
+	return &claimInfoResolver{claimInfo: r.tx.ClaimInfo}
+}
+
+func (r *transactionResolver) Batch(ctx context.Context) (*batchResolver, error) {
+	if r.tx.MessageType != orm.MessageTypeL2SentMessage {
+		return nil, nil
+	}
+	batch, err := r.historyLogic.GetBatchByL2BlockNumber(ctx, r.tx.BlockNumber)
+	if err != nil || batch == nil {
+		return nil, err
+	}
+	return &batchResolver{batch: batch}, nil
+}
+
+type counterpartChainTxResolver struct {
+	tx *types.CounterpartChainTx
+}
+
+func (r *counterpartChainTxResolver) Hash() string             { return r.tx.Hash }
+func (r *counterpartChainTxResolver) BlockNumber() graphQLLong { return graphQLLong(r.tx.BlockNumber) }
+
+type claimInfoResolver struct {
+	claimInfo *types.ClaimInfo
+}
+
+func (r *claimInfoResolver) From() string        { return r.claimInfo.From }
+func (r *claimInfoResolver) To() string          { return r.claimInfo.To }
+func (r *claimInfoResolver) Value() string       { return r.claimInfo.Value }
+func (r *claimInfoResolver) Nonce() string       { return r.claimInfo.Nonce }
+func (r *claimInfoResolver) Message() string     { return r.claimInfo.Message }
+func (r *claimInfoResolver) BatchIndex() string  { return r.claimInfo.Proof.BatchIndex }
+func (r *claimInfoResolver) MerkleProof() string { return r.claimInfo.Proof.MerkleProof }
+func (r *claimInfoResolver) Claimable() bool     { return r.claimInfo.Claimable }
+
+type batchResolver struct {
+	batch *types.BatchInfo
+}
+
+func (r *batchResolver) Index() graphQLLong            { return graphQLLong(r.batch.BatchIndex) }
+func (r *batchResolver) Hash() string                  { return r.batch.BatchHash }
+func (r *batchResolver) Status() int32                 { return int32(r.batch.BatchStatus) }
+func (r *batchResolver) L1BlockNumber() graphQLLong    { return graphQLLong(r.batch.L1BlockNumber) }
+func (r *batchResolver) StartBlockNumber() graphQLLong { return graphQLLong(r.batch.StartBlockNumber) }
+func (r *batchResolver) EndBlockNumber() graphQLLong   { return graphQLLong(r.batch.EndBlockNumber) }
+
+type tokenMappingResolver struct {
+	mapping *types.TokenMapping
+}
+
+func (r *tokenMappingResolver) TokenType() int32       { return int32(r.mapping.TokenType) }
+func (r *tokenMappingResolver) L1TokenAddress() string { return r.mapping.L1TokenAddress }
+func (r *tokenMappingResolver) L2TokenAddress() string { return r.mapping.L2TokenAddress }
@@ -1,12 +1,18 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	"github.com/scroll-tech/go-ethereum/common"
 	"gorm.io/gorm"
 
 	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/orm"
 	"scroll-tech/bridge-history-api/internal/types"
+	"scroll-tech/bridge-history-api/internal/utils"
 )
 
 // HistoryController contains the query claimable txs service
@@ -29,13 +35,18 @@ func (c *HistoryController) GetL2UnclaimedWithdrawalsByAddress(ctx *gin.Context)
 		return
 	}
 
-	pagedTxs, total, err := c.historyLogic.GetL2UnclaimedWithdrawalsByAddress(ctx, req.Address, req.Page, req.PageSize)
+	filter, cursor, err := messageFilter(&req)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	resultData, err := c.historyLogic.GetL2UnclaimedWithdrawalsByAddress(ctx, req.Address, filter, cursor, req.PageSize)
 	if err != nil {
 		types.RenderFailure(ctx, types.ErrGetL2ClaimableWithdrawalsError, err)
 		return
 	}
 
-	resultData := &types.ResultData{Results: pagedTxs, Total: total}
 	types.RenderSuccess(ctx, resultData)
 }
 
@@ -47,13 +58,18 @@ func (c *HistoryController) GetL2WithdrawalsByAddress(ctx *gin.Context) {
 		return
 	}
 
-	pagedTxs, total, err := c.historyLogic.GetL2WithdrawalsByAddress(ctx, req.Address, req.Page, req.PageSize)
+	filter, cursor, err := messageFilter(&req)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	resultData, err := c.historyLogic.GetL2WithdrawalsByAddress(ctx, req.Address, filter, cursor, req.PageSize)
 	if err != nil {
 		types.RenderFailure(ctx, types.ErrGetL2WithdrawalsError, err)
 		return
 	}
 
-	resultData := &types.ResultData{Results: pagedTxs, Total: total}
 	types.RenderSuccess(ctx, resultData)
 }
 
@@ -65,16 +81,38 @@ func (c *HistoryController) GetTxsByAddress(ctx *gin.Context) {
 		return
 	}
 
-	pagedTxs, total, err := c.historyLogic.GetTxsByAddress(ctx, req.Address, req.Page, req.PageSize)
+	filter, cursor, err := messageFilter(&req)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	resultData, err := c.historyLogic.GetTxsByAddress(ctx, req.Address, filter, cursor, req.PageSize)
 	if err != nil {
 		types.RenderFailure(ctx, types.ErrGetTxsError, err)
 		return
 	}
 
-	resultData := &types.ResultData{Results: pagedTxs, Total: total}
 	types.RenderSuccess(ctx, resultData)
 }
 
+// GetL2WithdrawalProof defines the http get method behavior
+func (c *HistoryController) GetL2WithdrawalProof(ctx *gin.Context) {
+	var req types.QueryWithdrawalProofRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	proof, err := c.historyLogic.GetL2WithdrawalProof(ctx, req.MessageHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrGetL2WithdrawalProofError, err)
+		return
+	}
+
+	types.RenderSuccess(ctx, proof)
+}
+
 // PostQueryTxsByHashes defines the http post method behavior
 func (c *HistoryController) PostQueryTxsByHashes(ctx *gin.Context) {
 	var req types.QueryByHashRequest
@@ -92,3 +130,42 @@ func (c *HistoryController) PostQueryTxsByHashes(ctx *gin.Context) {
 	resultData := &types.ResultData{Results: results, Total: uint64(len(results))}
 	types.RenderSuccess(ctx, resultData)
 }
+
+// messageFilter converts the filters and the cursor of an address query into their orm form.
+func messageFilter(req *types.QueryByAddressRequest) (*orm.MessageFilter, *orm.MessageCursor, error) {
+	filter := &orm.MessageFilter{
+		TokenType:     orm.TokenType(req.TokenType),
+		TokenID:       req.TokenID,
+		MinAmount:     req.MinAmount,
+		MaxAmount:     req.MaxAmount,
+		FromTimestamp: req.FromTimestamp,
+		ToTimestamp:   req.ToTimestamp,
+	}
+	switch req.Direction {
+	case types.DirectionDeposit:
+		filter.MessageType = orm.MessageTypeL1SentMessage
+	case types.DirectionWithdrawal:
+		filter.MessageType = orm.MessageTypeL2SentMessage
+	}
+	if req.Token != "" {
+		if !common.IsHexAddress(req.Token) {
+			return nil, nil, fmt.Errorf("invalid token address: %s", req.Token)
+		}
+		filter.TokenAddress = common.HexToAddress(req.Token).String()
+	}
+	if req.FromTimestamp != nil && req.ToTimestamp != nil && *req.FromTimestamp > *req.ToTimestamp {
+		return nil, nil, errors.New("from_timestamp is greater than to_timestamp")
+	}
+	for _, status := range req.TxStatuses {
+		filter.TxStatuses = append(filter.TxStatuses, orm.TxStatusType(status))
+	}
+
+	if req.Cursor == "" {
+		return filter, nil, nil
+	}
+	var cursor orm.MessageCursor
+	if err := utils.DecodeCursor(req.Cursor, &cursor); err != nil {
+		return nil, nil, err
+	}
+	return filter, &cursor, nil
+}
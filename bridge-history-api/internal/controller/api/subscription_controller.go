@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/config"
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+const (
+	subscriptionKeepAliveInterval = 30 * time.Second
+
+	defaultSubscriptionMaxAddresses   = 100
+	defaultSubscriptionMaxSubscribers = 10000
+)
+
+// SubscriptionController contains the tx status subscription service
+type SubscriptionController struct {
+	statusNotifier *logic.StatusNotifier
+	maxAddresses   int
+}
+
+// NewSubscriptionController return SubscriptionController instance, the limits left to zero in cfg take their default
+func NewSubscriptionController(ctx context.Context, db *gorm.DB, cfg *config.SubscriptionConfig) *SubscriptionController {
+	maxAddresses, maxSubscribers := defaultSubscriptionMaxAddresses, defaultSubscriptionMaxSubscribers
+	if cfg != nil && cfg.MaxAddresses > 0 {
+		maxAddresses = cfg.MaxAddresses
+	}
+	if cfg != nil && cfg.MaxSubscribers > 0 {
+		maxSubscribers = cfg.MaxSubscribers
+	}
+
+	statusNotifier := logic.NewStatusNotifier(db, maxSubscribers)
+	statusNotifier.Start(ctx)
+	return &SubscriptionController{
+		statusNotifier: statusNotifier,
+		maxAddresses:   maxAddresses,
+	}
+}
+
+// Subscribe defines the http get method behavior, it streams the tx status transitions of the addresses as server-sent events
+func (c *SubscriptionController) Subscribe(ctx *gin.Context) {
+	var req types.SubscribeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+	if len(req.Addresses) > c.maxAddresses {
+		types.RenderFailureWithStatus(ctx, http.StatusBadRequest, types.ErrTooManySubscribedAddressesNo,
+			fmt.Errorf("too many addresses: %d, max: %d", len(req.Addresses), c.maxAddresses))
+		return
+	}
+
+	addresses := make([]string, len(req.Addresses))
+	for i, address := range req.Addresses {
+		if !common.IsHexAddress(address) {
+			types.RenderFailure(ctx, types.ErrParameterInvalidNo, fmt.Errorf("invalid address: %s", address))
+			return
+		}
+		addresses[i] = common.HexToAddress(address).String()
+	}
+
+	sub, err := c.statusNotifier.Subscribe(addresses)
+	if err != nil {
+		// the only error of Subscribe is logic.ErrTooManySubscribers.
+		types.RenderFailureWithStatus(ctx, http.StatusTooManyRequests, types.ErrTooManySubscribersNo, err)
+		return
+	}
+	defer c.statusNotifier.Unsubscribe(sub)
+
+	keepAlive := time.NewTicker(subscriptionKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-keepAlive.C:
+			ctx.SSEvent("ping", "")
+			return true
+		case event, ok := <-sub.Events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("status", event)
+			return true
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+func subscribe(t *testing.T, c *SubscriptionController, query string) (int, types.Response) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/subscribe", c.Subscribe)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/subscribe?"+query, nil))
+
+	var resp types.Response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	return recorder.Code, resp
+}
+
+func TestSubscribeLimits(t *testing.T) {
+	addresses := "address=0x1111111111111111111111111111111111111111&address=0x2222222222222222222222222222222222222222"
+
+	c := &SubscriptionController{statusNotifier: logic.NewStatusNotifier(nil, 1), maxAddresses: 1}
+	code, resp := subscribe(t, c, addresses)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, types.ErrTooManySubscribedAddressesNo, resp.ErrCode)
+
+	c = &SubscriptionController{statusNotifier: logic.NewStatusNotifier(nil, 0), maxAddresses: 2}
+	code, resp = subscribe(t, c, addresses)
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	assert.Equal(t, types.ErrTooManySubscribersNo, resp.ErrCode)
+}
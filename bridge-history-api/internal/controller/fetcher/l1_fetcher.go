@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/scroll-tech/go-ethereum/common"
@@ -35,12 +36,12 @@ type L1MessageFetcher struct {
 }
 
 // NewL1MessageFetcher creates a new L1MessageFetcher instance.
-func NewL1MessageFetcher(ctx context.Context, cfg *config.FetcherConfig, db *gorm.DB, client *ethclient.Client) *L1MessageFetcher {
+func NewL1MessageFetcher(ctx context.Context, cfg *config.FetcherConfig, db *gorm.DB, redis *redis.Client, client *ethclient.Client) *L1MessageFetcher {
 	c := &L1MessageFetcher{
 		ctx:              ctx,
 		cfg:              cfg,
 		client:           client,
-		eventUpdateLogic: logic.NewEventUpdateLogic(db, true),
+		eventUpdateLogic: logic.NewEventUpdateLogic(db, redis, true),
 		l1FetcherLogic:   logic.NewL1FetcherLogic(cfg, db, client),
 	}
 
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/scroll-tech/go-ethereum/common"
@@ -35,13 +36,13 @@ type L2MessageFetcher struct {
 }
 
 // NewL2MessageFetcher creates a new L2MessageFetcher instance.
-func NewL2MessageFetcher(ctx context.Context, cfg *config.FetcherConfig, db *gorm.DB, client *ethclient.Client) *L2MessageFetcher {
+func NewL2MessageFetcher(ctx context.Context, cfg *config.FetcherConfig, db *gorm.DB, redis *redis.Client, client *ethclient.Client) *L2MessageFetcher {
 	c := &L2MessageFetcher{
 		ctx:              ctx,
 		cfg:              cfg,
 		db:               db,
 		client:           client,
-		eventUpdateLogic: logic.NewEventUpdateLogic(db, false),
+		eventUpdateLogic: logic.NewEventUpdateLogic(db, redis, false),
 		l2FetcherLogic:   logic.NewL2FetcherLogic(cfg, db, client),
 	}
 
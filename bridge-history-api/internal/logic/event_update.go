@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/scroll-tech/go-ethereum/common"
@@ -19,17 +20,19 @@ type EventUpdateLogic struct {
 	db              *gorm.DB
 	crossMessageOrm *orm.CrossMessage
 	batchEventOrm   *orm.BatchEvent
+	cache           *HistoryCache
 
 	eventUpdateLogicL1FinalizeBatchEventL2BlockUpdateHeight prometheus.Gauge
 	eventUpdateLogicL2MessageNonceUpdateHeight              prometheus.Gauge
 }
 
-// NewEventUpdateLogic creates a EventUpdateLogic instance
-func NewEventUpdateLogic(db *gorm.DB, isL1 bool) *EventUpdateLogic {
+// NewEventUpdateLogic creates a EventUpdateLogic instance, the api cache is not invalidated if redis is nil
+func NewEventUpdateLogic(db *gorm.DB, redis *redis.Client, isL1 bool) *EventUpdateLogic {
 	b := &EventUpdateLogic{
 		db:              db,
 		crossMessageOrm: orm.NewCrossMessage(db),
 		batchEventOrm:   orm.NewBatchEvent(db),
+		cache:           NewHistoryCache(redis),
 	}
 
 	if !isL1 {
@@ -100,9 +103,35 @@ func (b *EventUpdateLogic) L1InsertOrUpdate(ctx context.Context, l1FetcherResult
 		log.Error("failed to insert failed L1 gateway transactions", "err", err)
 		return err
 	}
+
+	b.cache.InvalidateMessages(ctx, l1FetcherResult.DepositMessages)
+	b.cache.InvalidateMessages(ctx, l1FetcherResult.RevertedTxs)
+	b.invalidateRelayedMessages(ctx, l1FetcherResult.RelayedMessages)
+	// the message queue events only tell the queue indexes of the messages they update.
+	if len(l1FetcherResult.MessageQueueEvents) > 0 {
+		b.cache.InvalidateAll(ctx)
+	}
 	return nil
 }
 
+// invalidateRelayedMessages invalidates the cached txs of the relayed messages, which only tell their message hashes.
+func (b *EventUpdateLogic) invalidateRelayedMessages(ctx context.Context, relayedMessages []*orm.CrossMessage) {
+	if b.cache == nil || len(relayedMessages) == 0 {
+		return
+	}
+	messageHashes := make([]string, len(relayedMessages))
+	for i, message := range relayedMessages {
+		messageHashes[i] = message.MessageHash
+	}
+	messages, err := b.crossMessageOrm.GetMessagesByMessageHashes(ctx, messageHashes)
+	if err != nil {
+		log.Error("failed to get relayed messages, invalidate all cached txs", "err", err)
+		b.cache.InvalidateAll(ctx)
+		return
+	}
+	b.cache.InvalidateMessages(ctx, messages)
+}
+
 func (b *EventUpdateLogic) updateL2WithdrawMessageInfos(ctx context.Context, batchIndex, startBlock, endBlock uint64) error {
 	l2WithdrawMessages, err := b.crossMessageOrm.GetL2WithdrawalsByBlockRange(ctx, startBlock, endBlock)
 	if err != nil {
@@ -147,6 +176,8 @@ func (b *EventUpdateLogic) updateL2WithdrawMessageInfos(ctx context.Context, bat
 		log.Error("failed to update batch index and rollup status and merkle proof of L2 messages", "err", dbErr)
 		return dbErr
 	}
+	// the finalized withdrawals become claimable.
+	b.cache.InvalidateMessages(ctx, l2WithdrawMessages)
 
 	b.eventUpdateLogicL2MessageNonceUpdateHeight.Set(float64(withdrawTrie.NextMessageNonce - 1))
 	return nil
@@ -191,5 +222,9 @@ func (b *EventUpdateLogic) L2InsertOrUpdate(ctx context.Context, l2FetcherResult
 		log.Error("failed to insert failed L2 gateway transactions", "err", err)
 		return err
 	}
+
+	b.cache.InvalidateMessages(ctx, l2FetcherResult.WithdrawMessages)
+	b.cache.InvalidateMessages(ctx, l2FetcherResult.OtherRevertedTxs)
+	b.invalidateRelayedMessages(ctx, l2FetcherResult.RelayedMessages)
 	return nil
 }
@@ -0,0 +1,134 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/bridge-history-api/internal/orm"
+)
+
+const (
+	// the cache keys of an address embed its version and the global version, bumping a version invalidates all the
+	// cached pages at once without scanning the keys, the stale pages expire on their own.
+	cacheKeyVersion              = cacheKeyPrefixBridgeHistory + "version"
+	cacheKeyPrefixAddressVersion = cacheKeyPrefixBridgeHistory + "version:"
+)
+
+// HistoryCache caches the hot read paths of the api in redis, the fetchers invalidate the txs they update. A nil
+// HistoryCache disables the cache.
+type HistoryCache struct {
+	redis        *redis.Client
+	cacheMetrics *cacheMetrics
+}
+
+// NewHistoryCache returns a new instance of HistoryCache, nil if redis is not configured.
+func NewHistoryCache(redis *redis.Client) *HistoryCache {
+	if redis == nil {
+		return nil
+	}
+	return &HistoryCache{
+		redis:        redis,
+		cacheMetrics: initCacheMetrics(),
+	}
+}
+
+// addressKey returns the cache key of the page of the address.
+func (c *HistoryCache) addressKey(ctx context.Context, cacheKeyPrefix, address, pageKey string) (string, error) {
+	versions, err := c.redis.MGet(ctx, cacheKeyVersion, cacheKeyPrefixAddressVersion+address).Result()
+	if err != nil {
+		return "", err
+	}
+	key := cacheKeyPrefix + address
+	for _, version := range versions {
+		// a missing version is nil, which is a valid version as well.
+		if version, ok := version.(string); ok {
+			key += ":" + version
+		} else {
+			key += ":0"
+		}
+	}
+	return key + ":" + pageKey, nil
+}
+
+// get returns the cached data of the api, false on miss.
+func (c *HistoryCache) get(ctx context.Context, api, key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	data, err := c.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Error("failed to get data from Redis", "error", err)
+		}
+		c.cacheMetrics.cacheMisses.WithLabelValues(api).Inc()
+		log.Info("cache miss", "cache key", key)
+		return nil, false
+	}
+	c.cacheMetrics.cacheHits.WithLabelValues(api).Inc()
+	log.Info("cache hit", "cache key", key)
+	return data, true
+}
+
+func (c *HistoryCache) set(ctx context.Context, key string, data []byte) {
+	if c == nil {
+		return
+	}
+	if err := c.redis.Set(ctx, key, data, cacheKeyExpiredTime).Err(); err != nil {
+		log.Error("failed to set data to Redis", "error", err)
+	}
+}
+
+// InvalidateMessages invalidates the cached txs of the senders and receivers of the messages, and the cached messages
+// of their tx hashes.
+func (c *HistoryCache) InvalidateMessages(ctx context.Context, messages []*orm.CrossMessage) {
+	if c == nil || len(messages) == 0 {
+		return
+	}
+	addresses := make(map[string]struct{})
+	var hashKeys []string
+	for _, message := range messages {
+		for _, address := range []string{message.Sender, message.Receiver} {
+			if address != "" {
+				addresses[address] = struct{}{}
+			}
+		}
+		for _, hash := range []string{message.L1TxHash, message.L2TxHash} {
+			if hash != "" {
+				hashKeys = append(hashKeys, cacheKeyPrefixQueryTxsByHashes+hash)
+			}
+		}
+	}
+
+	_, err := c.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for address := range addresses {
+			pipe.Incr(ctx, cacheKeyPrefixAddressVersion+address)
+			// the versions outlive the pages they invalidate.
+			pipe.Expire(ctx, cacheKeyPrefixAddressVersion+address, 2*cacheKeyExpiredTime)
+		}
+		if len(hashKeys) > 0 {
+			pipe.Del(ctx, hashKeys...)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("failed to invalidate cached txs", "addresses", len(addresses), "error", err)
+		return
+	}
+	c.cacheMetrics.cacheInvalidations.WithLabelValues("address").Add(float64(len(addresses)))
+}
+
+// InvalidateAll invalidates the cached txs of all the addresses, for the updates which do not tell the messages they
+// update.
+func (c *HistoryCache) InvalidateAll(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	if err := c.redis.Incr(ctx, cacheKeyVersion).Err(); err != nil {
+		log.Error("failed to invalidate all cached txs", "error", err)
+		return
+	}
+	c.cacheMetrics.cacheInvalidations.WithLabelValues("all").Inc()
+}
@@ -37,140 +37,100 @@ const (
 type HistoryLogic struct {
 	crossMessageOrm *orm.CrossMessage
 	batchEventOrm   *orm.BatchEvent
-	redis           *redis.Client
+	cache           *HistoryCache
 	singleFlight    singleflight.Group
-	cacheMetrics    *cacheMetrics
 }
 
-// NewHistoryLogic returns bridge history services.
+// NewHistoryLogic returns bridge history services, the cache is disabled if redis is nil.
 func NewHistoryLogic(db *gorm.DB, redis *redis.Client) *HistoryLogic {
 	logic := &HistoryLogic{
 		crossMessageOrm: orm.NewCrossMessage(db),
 		batchEventOrm:   orm.NewBatchEvent(db),
-		redis:           redis,
-		cacheMetrics:    initCacheMetrics(),
+		cache:           NewHistoryCache(redis),
 	}
 	return logic
 }
 
-// GetL2UnclaimedWithdrawalsByAddress gets all unclaimed withdrawal txs under given address.
-func (h *HistoryLogic) GetL2UnclaimedWithdrawalsByAddress(ctx context.Context, address string, page, pageSize uint64) ([]*types.TxHistoryInfo, uint64, error) {
-	cacheKey := cacheKeyPrefixL2ClaimableWithdrawalsByAddr + address
-	pagedTxs, total, isHit, err := h.getCachedTxsInfo(ctx, cacheKey, page, pageSize)
-	if err != nil {
-		log.Error("failed to get cached tx info", "cached key", cacheKey, "page", page, "page size", pageSize, "error", err)
-		return nil, 0, err
-	}
-
-	if isHit {
-		h.cacheMetrics.cacheHits.WithLabelValues("GetL2UnclaimedWithdrawalsByAddress").Inc()
-		log.Info("cache hit", "cache key", cacheKey)
-		return pagedTxs, total, nil
-	}
-
-	h.cacheMetrics.cacheMisses.WithLabelValues("GetL2UnclaimedWithdrawalsByAddress").Inc()
-	log.Info("cache miss", "cache key", cacheKey)
-
-	result, err, _ := h.singleFlight.Do(cacheKey, func() (interface{}, error) {
-		var messages []*orm.CrossMessage
-		messages, err = h.crossMessageOrm.GetL2UnclaimedWithdrawalsByAddress(ctx, address)
-		if err != nil {
-			return nil, err
-		}
-		return messages, nil
-	})
-	if err != nil {
-		log.Error("failed to get L2 claimable withdrawals by address", "address", address, "error", err)
-		return nil, 0, err
-	}
+// GetL2UnclaimedWithdrawalsByAddress gets a page of unclaimed withdrawal txs under given address.
+func (h *HistoryLogic) GetL2UnclaimedWithdrawalsByAddress(ctx context.Context, address string, filter *orm.MessageFilter, cursor *orm.MessageCursor, pageSize uint64) (*types.PagedResultData, error) {
+	return h.getPagedTxs(ctx, "GetL2UnclaimedWithdrawalsByAddress", cacheKeyPrefixL2ClaimableWithdrawalsByAddr, address, filter, cursor, pageSize, h.crossMessageOrm.GetL2UnclaimedWithdrawalsByAddress)
+}
 
-	messages, ok := result.([]*orm.CrossMessage)
-	if !ok {
-		log.Error("unexpected type", "expected", "[]*types.TxHistoryInfo", "got", reflect.TypeOf(result), "address", address)
-		return nil, 0, errors.New("unexpected error")
-	}
+// GetL2WithdrawalsByAddress gets a page of withdrawal txs under given address.
+func (h *HistoryLogic) GetL2WithdrawalsByAddress(ctx context.Context, address string, filter *orm.MessageFilter, cursor *orm.MessageCursor, pageSize uint64) (*types.PagedResultData, error) {
+	return h.getPagedTxs(ctx, "GetL2WithdrawalsByAddress", cacheKeyPrefixL2WithdrawalsByAddr, address, filter, cursor, pageSize, h.crossMessageOrm.GetL2WithdrawalsByAddress)
+}
 
-	return h.processAndCacheTxHistoryInfo(ctx, cacheKey, messages, page, pageSize)
+// GetTxsByAddress gets a page of tx infos under given address.
+func (h *HistoryLogic) GetTxsByAddress(ctx context.Context, address string, filter *orm.MessageFilter, cursor *orm.MessageCursor, pageSize uint64) (*types.PagedResultData, error) {
+	return h.getPagedTxs(ctx, "GetTxsByAddress", cacheKeyPrefixTxsByAddr, address, filter, cursor, pageSize, h.crossMessageOrm.GetTxsByAddress)
 }
 
-// GetL2WithdrawalsByAddress gets all withdrawal txs under given address.
-func (h *HistoryLogic) GetL2WithdrawalsByAddress(ctx context.Context, address string, page, pageSize uint64) ([]*types.TxHistoryInfo, uint64, error) {
-	cacheKey := cacheKeyPrefixL2WithdrawalsByAddr + address
-	pagedTxs, total, isHit, err := h.getCachedTxsInfo(ctx, cacheKey, page, pageSize)
+type pageQuery func(ctx context.Context, sender string, filter *orm.MessageFilter, cursor *orm.MessageCursor, limit int) ([]*orm.CrossMessage, error)
+
+// getPagedTxs gets the page of txs following the cursor, each page of each filter is cached on its own.
+func (h *HistoryLogic) getPagedTxs(ctx context.Context, method, cacheKeyPrefix, address string, filter *orm.MessageFilter, cursor *orm.MessageCursor, pageSize uint64, query pageQuery) (*types.PagedResultData, error) {
+	pageKey, err := json.Marshal(struct {
+		Filter   *orm.MessageFilter `json:"f,omitempty"`
+		Cursor   *orm.MessageCursor `json:"c,omitempty"`
+		PageSize uint64             `json:"s"`
+	}{filter, cursor, pageSize})
 	if err != nil {
-		log.Error("failed to get cached tx info", "cached key", cacheKey, "page", page, "page size", pageSize, "error", err)
-		return nil, 0, err
+		log.Error("failed to marshal page key", "address", address, "error", err)
+		return nil, err
 	}
-
-	if isHit {
-		h.cacheMetrics.cacheHits.WithLabelValues("GetL2WithdrawalsByAddress").Inc()
-		log.Info("cache hit", "cache key", cacheKey)
-		return pagedTxs, total, nil
+	cacheKey := cacheKeyPrefix + address + ":" + string(pageKey)
+	if h.cache != nil {
+		if cacheKey, err = h.cache.addressKey(ctx, cacheKeyPrefix, address, string(pageKey)); err != nil {
+			log.Error("failed to get cache key", "address", address, "error", err)
+			return nil, err
+		}
 	}
 
-	h.cacheMetrics.cacheMisses.WithLabelValues("GetL2WithdrawalsByAddress").Inc()
-	log.Info("cache miss", "cache key", cacheKey)
+	if cachedData, isHit := h.cache.get(ctx, method, cacheKey); isHit {
+		var page types.PagedResultData
+		if unmarshalErr := json.Unmarshal(cachedData, &page); unmarshalErr == nil {
+			return &page, nil
+		}
+		log.Error("failed to unmarshal cached data", "cache key", cacheKey)
+	}
 
 	result, err, _ := h.singleFlight.Do(cacheKey, func() (interface{}, error) {
-		var messages []*orm.CrossMessage
-		messages, err = h.crossMessageOrm.GetL2WithdrawalsByAddress(ctx, address)
-		if err != nil {
-			return nil, err
-		}
-		return messages, nil
+		// one more message tells whether there is a next page.
+		return query(ctx, address, filter, cursor, int(pageSize)+1)
 	})
 	if err != nil {
-		log.Error("failed to get L2 withdrawals by address", "address", address, "error", err)
-		return nil, 0, err
+		log.Error("failed to get txs by address", "method", method, "address", address, "error", err)
+		return nil, err
 	}
 
 	messages, ok := result.([]*orm.CrossMessage)
 	if !ok {
-		log.Error("unexpected type", "expected", "[]*types.TxHistoryInfo", "got", reflect.TypeOf(result), "address", address)
-		return nil, 0, errors.New("unexpected error")
-	}
-
-	return h.processAndCacheTxHistoryInfo(ctx, cacheKey, messages, page, pageSize)
-}
-
-// GetTxsByAddress gets tx infos under given address.
-func (h *HistoryLogic) GetTxsByAddress(ctx context.Context, address string, page, pageSize uint64) ([]*types.TxHistoryInfo, uint64, error) {
-	cacheKey := cacheKeyPrefixTxsByAddr + address
-	pagedTxs, total, isHit, err := h.getCachedTxsInfo(ctx, cacheKey, page, pageSize)
-	if err != nil {
-		log.Error("failed to get cached tx info", "cached key", cacheKey, "page", page, "page size", pageSize, "error", err)
-		return nil, 0, err
+		log.Error("unexpected type", "expected", "[]*orm.CrossMessage", "got", reflect.TypeOf(result), "address", address)
+		return nil, errors.New("unexpected error")
 	}
 
-	if isHit {
-		h.cacheMetrics.cacheHits.WithLabelValues("GetTxsByAddress").Inc()
-		log.Info("cache hit", "cache key", cacheKey)
-		return pagedTxs, total, nil
-	}
-
-	h.cacheMetrics.cacheMisses.WithLabelValues("GetTxsByAddress").Inc()
-	log.Info("cache miss", "cache key", cacheKey)
-
-	result, err, _ := h.singleFlight.Do(cacheKey, func() (interface{}, error) {
-		var messages []*orm.CrossMessage
-		messages, err = h.crossMessageOrm.GetTxsByAddress(ctx, address)
+	page := &types.PagedResultData{Results: []*types.TxHistoryInfo{}}
+	if uint64(len(messages)) > pageSize {
+		messages = messages[:pageSize]
+		last := messages[len(messages)-1]
+		page.NextCursor, err = utils.EncodeCursor(&orm.MessageCursor{BlockTimestamp: last.BlockTimestamp, ID: last.ID})
 		if err != nil {
+			log.Error("failed to encode cursor", "address", address, "error", err)
 			return nil, err
 		}
-		return messages, nil
-	})
-	if err != nil {
-		log.Error("failed to get txs by address", "address", address, "error", err)
-		return nil, 0, err
 	}
-
-	messages, ok := result.([]*orm.CrossMessage)
-	if !ok {
-		log.Error("unexpected type", "expected", "[]*types.TxHistoryInfo", "got", reflect.TypeOf(result), "address", address)
-		return nil, 0, errors.New("unexpected error")
+	for _, message := range messages {
+		page.Results = append(page.Results, getTxHistoryInfo(message))
 	}
 
-	return h.processAndCacheTxHistoryInfo(ctx, cacheKey, messages, page, pageSize)
+	jsonData, err := json.Marshal(page)
+	if err != nil {
+		log.Error("failed to marshal data", "error", err)
+		return page, nil
+	}
+	h.cache.set(ctx, cacheKey, jsonData)
+	return page, nil
 }
 
 // GetTxsByHashes gets tx infos under given tx hashes.
@@ -187,23 +147,12 @@ func (h *HistoryLogic) GetTxsByHashes(ctx context.Context, txHashes []string) ([
 		hashesMap[hash] = struct{}{}
 
 		cacheKey := cacheKeyPrefixQueryTxsByHashes + hash
-		cachedData, err := h.redis.Get(ctx, cacheKey).Bytes()
-		if err != nil && errors.Is(err, redis.Nil) {
-			h.cacheMetrics.cacheMisses.WithLabelValues("PostQueryTxsByHashes").Inc()
-			log.Info("cache miss", "cache key", cacheKey)
-			uncachedHashes = append(uncachedHashes, hash)
-			continue
-		}
-
-		if err != nil {
-			log.Error("failed to get data from Redis", "error", err)
+		cachedData, isHit := h.cache.get(ctx, "PostQueryTxsByHashes", cacheKey)
+		if !isHit {
 			uncachedHashes = append(uncachedHashes, hash)
 			continue
 		}
 
-		h.cacheMetrics.cacheHits.WithLabelValues("PostQueryTxsByHashes").Inc()
-		log.Info("cache hit", "cache key", cacheKey)
-
 		if len(cachedData) == 0 {
 			continue
 		}
@@ -240,9 +189,7 @@ func (h *HistoryLogic) GetTxsByHashes(ctx context.Context, txHashes []string) ([
 			result, found := resultMap[hash]
 			if !found {
 				// tx hash not found, which is also a valid result, cache empty string.
-				if cacheErr := h.redis.Set(ctx, cacheKey, "", cacheKeyExpiredTime).Err(); cacheErr != nil {
-					log.Error("failed to set data to Redis", "error", cacheErr)
-				}
+				h.cache.set(ctx, cacheKey, []byte{})
 				continue
 			}
 
@@ -252,14 +199,63 @@ func (h *HistoryLogic) GetTxsByHashes(ctx context.Context, txHashes []string) ([
 				continue
 			}
 
-			if cacheErr := h.redis.Set(ctx, cacheKey, jsonData, cacheKeyExpiredTime).Err(); cacheErr != nil {
-				log.Error("failed to set data to Redis", "error", cacheErr)
-			}
+			h.cache.set(ctx, cacheKey, jsonData)
 		}
 	}
 	return results, nil
 }
 
+// GetBatchByIndex gets the batch info of the batch index, nil if the batch is not committed.
+func (h *HistoryLogic) GetBatchByIndex(ctx context.Context, batchIndex uint64) (*types.BatchInfo, error) {
+	batch, err := h.batchEventOrm.GetBatchByIndex(ctx, batchIndex)
+	if err != nil {
+		log.Error("failed to get batch by index", "index", batchIndex, "error", err)
+		return nil, err
+	}
+	return getBatchInfo(batch), nil
+}
+
+// GetBatchByL2BlockNumber gets the batch info of the batch containing the L2 block, nil if the batch is not committed.
+func (h *HistoryLogic) GetBatchByL2BlockNumber(ctx context.Context, blockNumber uint64) (*types.BatchInfo, error) {
+	batch, err := h.batchEventOrm.GetBatchByL2BlockNumber(ctx, blockNumber)
+	if err != nil {
+		log.Error("failed to get batch by L2 block number", "block number", blockNumber, "error", err)
+		return nil, err
+	}
+	return getBatchInfo(batch), nil
+}
+
+// GetTokenMapping gets the L1 and L2 addresses of the token given by either of them, nil if the token was never bridged.
+func (h *HistoryLogic) GetTokenMapping(ctx context.Context, tokenAddress string) (*types.TokenMapping, error) {
+	message, err := h.crossMessageOrm.GetMessageByTokenAddress(ctx, tokenAddress)
+	if err != nil {
+		log.Error("failed to get message by token address", "token address", tokenAddress, "error", err)
+		return nil, err
+	}
+	if message == nil {
+		return nil, nil
+	}
+	return &types.TokenMapping{
+		TokenType:      orm.TokenType(message.TokenType),
+		L1TokenAddress: message.L1TokenAddress,
+		L2TokenAddress: message.L2TokenAddress,
+	}, nil
+}
+
+func getBatchInfo(batch *orm.BatchEvent) *types.BatchInfo {
+	if batch == nil {
+		return nil
+	}
+	return &types.BatchInfo{
+		BatchIndex:       batch.BatchIndex,
+		BatchHash:        batch.BatchHash,
+		BatchStatus:      orm.BatchStatusType(batch.BatchStatus),
+		L1BlockNumber:    batch.L1BlockNumber,
+		StartBlockNumber: batch.StartBlockNumber,
+		EndBlockNumber:   batch.EndBlockNumber,
+	}
+}
+
 func getTxHistoryInfo(message *orm.CrossMessage) *types.TxHistoryInfo {
 	txHistory := &types.TxHistoryInfo{
 		MessageHash:    message.MessageHash,
@@ -305,103 +301,3 @@ func getTxHistoryInfo(message *orm.CrossMessage) *types.TxHistoryInfo {
 	}
 	return txHistory
 }
-
-func (h *HistoryLogic) getCachedTxsInfo(ctx context.Context, cacheKey string, pageNum, pageSize uint64) ([]*types.TxHistoryInfo, uint64, bool, error) {
-	start := int64((pageNum - 1) * pageSize)
-	end := start + int64(pageSize) - 1
-
-	total, err := h.redis.ZCard(ctx, cacheKey).Result()
-	if err != nil {
-		log.Error("failed to get zcard result", "error", err)
-		return nil, 0, false, err
-	}
-
-	if total == 0 {
-		return nil, 0, false, nil
-	}
-
-	values, err := h.redis.ZRange(ctx, cacheKey, start, end).Result()
-	if err != nil {
-		log.Error("failed to get zrange result", "error", err)
-		return nil, 0, false, err
-	}
-
-	if len(values) == 0 {
-		return nil, 0, false, nil
-	}
-
-	// check if it's empty placeholder.
-	if len(values) == 1 && values[0] == "empty_page" {
-		return nil, 0, true, nil
-	}
-
-	var pagedTxs []*types.TxHistoryInfo
-	for _, v := range values {
-		var tx types.TxHistoryInfo
-		if unmarshalErr := json.Unmarshal([]byte(v), &tx); unmarshalErr != nil {
-			log.Error("failed to unmarshal transaction data", "error", unmarshalErr)
-			return nil, 0, false, unmarshalErr
-		}
-		pagedTxs = append(pagedTxs, &tx)
-	}
-	return pagedTxs, uint64(total), true, nil
-}
-
-func (h *HistoryLogic) cacheTxsInfo(ctx context.Context, cacheKey string, txs []*types.TxHistoryInfo) error {
-	_, err := h.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-		if len(txs) == 0 {
-			if err := pipe.ZAdd(ctx, cacheKey, &redis.Z{Score: 0, Member: "empty_page"}).Err(); err != nil {
-				log.Error("failed to add empty page indicator to sorted set", "error", err)
-				return err
-			}
-		} else {
-			// The transactions are sorted, thus we set the score as their indices.
-			for i, tx := range txs {
-				txBytes, err := json.Marshal(tx)
-				if err != nil {
-					log.Error("failed to marshal transaction to json", "error", err)
-					return err
-				}
-				if err := pipe.ZAdd(ctx, cacheKey, &redis.Z{Score: float64(i), Member: txBytes}).Err(); err != nil {
-					log.Error("failed to add transaction to sorted set", "error", err)
-					return err
-				}
-			}
-		}
-		if err := pipe.Expire(ctx, cacheKey, cacheKeyExpiredTime).Err(); err != nil {
-			log.Error("failed to set expiry time", "error", err)
-			return err
-		}
-		return nil
-	})
-	if err != nil {
-		log.Error("failed to execute transaction", "error", err)
-		return err
-	}
-	return nil
-}
-
-func (h *HistoryLogic) processAndCacheTxHistoryInfo(ctx context.Context, cacheKey string, messages []*orm.CrossMessage, page, pageSize uint64) ([]*types.TxHistoryInfo, uint64, error) {
-	var txHistories []*types.TxHistoryInfo
-	for _, message := range messages {
-		txHistories = append(txHistories, getTxHistoryInfo(message))
-	}
-
-	err := h.cacheTxsInfo(ctx, cacheKey, txHistories)
-	if err != nil {
-		log.Error("failed to cache txs info", "key", cacheKey, "err", err)
-		return nil, 0, err
-	}
-
-	pagedTxs, total, isHit, err := h.getCachedTxsInfo(ctx, cacheKey, page, pageSize)
-	if err != nil {
-		log.Error("failed to get cached tx info", "cached key", cacheKey, "page", page, "page size", pageSize, "error", err)
-		return nil, 0, err
-	}
-
-	if !isHit {
-		log.Error("cache miss after write, expect hit", "cached key", cacheKey, "page", page, "page size", pageSize, "error", err)
-		return nil, 0, err
-	}
-	return pagedTxs, total, nil
-}
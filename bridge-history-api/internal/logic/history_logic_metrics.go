@@ -10,6 +10,8 @@ import (
 type cacheMetrics struct {
 	cacheHits   *prometheus.CounterVec
 	cacheMisses *prometheus.CounterVec
+
+	cacheInvalidations *prometheus.CounterVec
 }
 
 var (
@@ -34,6 +36,13 @@ func initCacheMetrics() *cacheMetrics {
 				},
 				[]string{"api"},
 			),
+			cacheInvalidations: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bridge_history_api_cache_invalidations_total",
+					Help: "The total number of cache invalidations by the fetchers",
+				},
+				[]string{"scope"},
+			),
 		}
 	})
 	return cm
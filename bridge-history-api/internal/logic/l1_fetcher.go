@@ -198,20 +198,22 @@ func (f *L1FetcherLogic) l1FetcherLogs(ctx context.Context, from, to uint64) ([]
 		Topics:    make([][]common.Hash, 1),
 	}
 
-	query.Topics[0] = make([]common.Hash, 13)
+	query.Topics[0] = make([]common.Hash, 15)
 	query.Topics[0][0] = backendabi.L1DepositETHSig
 	query.Topics[0][1] = backendabi.L1DepositERC20Sig
 	query.Topics[0][2] = backendabi.L1DepositERC721Sig
-	query.Topics[0][3] = backendabi.L1DepositERC1155Sig
-	query.Topics[0][4] = backendabi.L1SentMessageEventSig
-	query.Topics[0][5] = backendabi.L1RelayedMessageEventSig
-	query.Topics[0][6] = backendabi.L1FailedRelayedMessageEventSig
-	query.Topics[0][7] = backendabi.L1CommitBatchEventSig
-	query.Topics[0][8] = backendabi.L1RevertBatchEventSig
-	query.Topics[0][9] = backendabi.L1FinalizeBatchEventSig
-	query.Topics[0][10] = backendabi.L1QueueTransactionEventSig
-	query.Topics[0][11] = backendabi.L1DequeueTransactionEventSig
-	query.Topics[0][12] = backendabi.L1DropTransactionEventSig
+	query.Topics[0][3] = backendabi.L1BatchDepositERC721Sig
+	query.Topics[0][4] = backendabi.L1DepositERC1155Sig
+	query.Topics[0][5] = backendabi.L1BatchDepositERC1155Sig
+	query.Topics[0][6] = backendabi.L1SentMessageEventSig
+	query.Topics[0][7] = backendabi.L1RelayedMessageEventSig
+	query.Topics[0][8] = backendabi.L1FailedRelayedMessageEventSig
+	query.Topics[0][9] = backendabi.L1CommitBatchEventSig
+	query.Topics[0][10] = backendabi.L1RevertBatchEventSig
+	query.Topics[0][11] = backendabi.L1FinalizeBatchEventSig
+	query.Topics[0][12] = backendabi.L1QueueTransactionEventSig
+	query.Topics[0][13] = backendabi.L1DequeueTransactionEventSig
+	query.Topics[0][14] = backendabi.L1DropTransactionEventSig
 
 	eventLogs, err := f.client.FilterLogs(ctx, query)
 	if err != nil {
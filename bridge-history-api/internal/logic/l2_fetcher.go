@@ -209,14 +209,16 @@ func (f *L2FetcherLogic) l2FetcherLogs(ctx context.Context, from, to uint64) ([]
 		Addresses: f.addressList,
 		Topics:    make([][]common.Hash, 1),
 	}
-	query.Topics[0] = make([]common.Hash, 7)
+	query.Topics[0] = make([]common.Hash, 9)
 	query.Topics[0][0] = backendabi.L2WithdrawETHSig
 	query.Topics[0][1] = backendabi.L2WithdrawERC20Sig
 	query.Topics[0][2] = backendabi.L2WithdrawERC721Sig
-	query.Topics[0][3] = backendabi.L2WithdrawERC1155Sig
-	query.Topics[0][4] = backendabi.L2SentMessageEventSig
-	query.Topics[0][5] = backendabi.L2RelayedMessageEventSig
-	query.Topics[0][6] = backendabi.L2FailedRelayedMessageEventSig
+	query.Topics[0][3] = backendabi.L2BatchWithdrawERC721Sig
+	query.Topics[0][4] = backendabi.L2WithdrawERC1155Sig
+	query.Topics[0][5] = backendabi.L2BatchWithdrawERC1155Sig
+	query.Topics[0][6] = backendabi.L2SentMessageEventSig
+	query.Topics[0][7] = backendabi.L2RelayedMessageEventSig
+	query.Topics[0][8] = backendabi.L2FailedRelayedMessageEventSig
 
 	eventLogs, err := f.client.FilterLogs(ctx, query)
 	if err != nil {
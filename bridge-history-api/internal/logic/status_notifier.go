@@ -0,0 +1,216 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/orm"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+const (
+	statusPollInterval = 2 * time.Second
+	// statusPollLag leaves time to the fetcher transactions to commit, the updated_at of a message is set before its
+	// transaction commits, thus the newest messages are only polled once they are statusPollLag old.
+	statusPollLag          = 5 * time.Second
+	statusPollBatchSize    = 1000
+	subscriptionBufferSize = 64
+)
+
+// ErrTooManySubscribers is returned by Subscribe once the max number of concurrent subscriptions is reached.
+var ErrTooManySubscribers = errors.New("too many subscribers")
+
+// updatedMessagesGetter gets the cross messages polled by StatusNotifier, it is implemented by orm.CrossMessage.
+type updatedMessagesGetter interface {
+	GetMessagesUpdatedBetween(ctx context.Context, updatedAt time.Time, id uint64, before time.Time, limit int) ([]*orm.CrossMessage, error)
+}
+
+// Subscription receives the status transitions of the txs of its addresses.
+type Subscription struct {
+	// Events is closed once the subscription is cancelled, or if the subscriber is too slow to keep up.
+	Events <-chan *types.TxStatusEvent
+
+	events    chan *types.TxStatusEvent
+	addresses []string
+	// cancelled is guarded by the mutex of the notifier.
+	cancelled bool
+}
+
+// StatusNotifier polls the updated cross messages and pushes their status transitions to the subscribers of their
+// sender or receiver.
+type StatusNotifier struct {
+	messageGetter  updatedMessagesGetter
+	now            func() time.Time
+	pollBatchSize  int
+	maxSubscribers int
+
+	mu             sync.Mutex
+	subscriptions  map[string]map[*Subscription]struct{}
+	numSubscribers int
+
+	lastUpdatedAt time.Time
+	lastID        uint64
+}
+
+// NewStatusNotifier returns a new instance of StatusNotifier, serving at most maxSubscribers concurrent subscriptions.
+func NewStatusNotifier(db *gorm.DB, maxSubscribers int) *StatusNotifier {
+	return newStatusNotifier(orm.NewCrossMessage(db), maxSubscribers)
+}
+
+func newStatusNotifier(messageGetter updatedMessagesGetter, maxSubscribers int) *StatusNotifier {
+	return &StatusNotifier{
+		messageGetter:  messageGetter,
+		now:            time.Now,
+		pollBatchSize:  statusPollBatchSize,
+		maxSubscribers: maxSubscribers,
+		subscriptions:  make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Start polls the updated cross messages until the context is done.
+func (n *StatusNotifier) Start(ctx context.Context) {
+	n.lastUpdatedAt = n.now().Add(-statusPollLag)
+	go func() {
+		ticker := time.NewTicker(statusPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := n.poll(ctx); err != nil {
+					log.Error("failed to poll updated messages", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Subscribe subscribes to the status transitions of the txs of the checksummed addresses, the subscription must be
+// cancelled by Unsubscribe. It returns ErrTooManySubscribers once maxSubscribers subscriptions are served.
+func (n *StatusNotifier) Subscribe(addresses []string) (*Subscription, error) {
+	events := make(chan *types.TxStatusEvent, subscriptionBufferSize)
+	sub := &Subscription{Events: events, events: events, addresses: addresses}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.numSubscribers >= n.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+	n.numSubscribers++
+	for _, address := range addresses {
+		if n.subscriptions[address] == nil {
+			n.subscriptions[address] = make(map[*Subscription]struct{})
+		}
+		n.subscriptions[address][sub] = struct{}{}
+	}
+	return sub, nil
+}
+
+// Unsubscribe cancels the subscription.
+func (n *StatusNotifier) Unsubscribe(sub *Subscription) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.unsubscribe(sub)
+}
+
+func (n *StatusNotifier) unsubscribe(sub *Subscription) {
+	if sub.cancelled {
+		return
+	}
+	sub.cancelled = true
+	n.numSubscribers--
+	for _, address := range sub.addresses {
+		delete(n.subscriptions[address], sub)
+		if len(n.subscriptions[address]) == 0 {
+			delete(n.subscriptions, address)
+		}
+	}
+	close(sub.events)
+}
+
+func (n *StatusNotifier) poll(ctx context.Context) error {
+	before := n.now().Add(-statusPollLag)
+
+	n.mu.Lock()
+	idle := len(n.subscriptions) == 0
+	n.mu.Unlock()
+	if idle {
+		// nobody to notify, skip the messages updated so far.
+		if before.After(n.lastUpdatedAt) {
+			n.lastUpdatedAt, n.lastID = before, 0
+		}
+		return nil
+	}
+
+	for {
+		messages, err := n.messageGetter.GetMessagesUpdatedBetween(ctx, n.lastUpdatedAt, n.lastID, before, n.pollBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			n.notify(message)
+		}
+		if len(messages) > 0 {
+			last := messages[len(messages)-1]
+			n.lastUpdatedAt, n.lastID = last.UpdatedAt, last.ID
+		}
+		if len(messages) < n.pollBatchSize {
+			return nil
+		}
+	}
+}
+
+func (n *StatusNotifier) notify(message *orm.CrossMessage) {
+	status, ok := txStatusEvent(message)
+	if !ok {
+		return
+	}
+	event := &types.TxStatusEvent{Status: status, Tx: getTxHistoryInfo(message)}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, address := range []string{message.Sender, message.Receiver} {
+		for sub := range n.subscriptions[address] {
+			select {
+			case sub.events <- event:
+			default:
+				// drop the slow subscriber rather than its events, it can reconnect and query the txs it missed.
+				log.Warn("subscriber too slow, dropping subscription", "address", address)
+				n.unsubscribe(sub)
+			}
+		}
+		if message.Receiver == message.Sender {
+			break
+		}
+	}
+}
+
+// txStatusEvent returns the status pushed for the current state of the message, false if its state is not pushed.
+func txStatusEvent(message *orm.CrossMessage) (string, bool) {
+	txStatus := orm.TxStatusType(message.TxStatus)
+	switch orm.MessageType(message.MessageType) {
+	case orm.MessageTypeL1SentMessage:
+		switch txStatus {
+		case orm.TxStatusTypeSent:
+			return types.TxStatusEventDeposited, true
+		case orm.TxStatusTypeRelayed:
+			return types.TxStatusEventRelayed, true
+		}
+	case orm.MessageTypeL2SentMessage:
+		switch {
+		case txStatus == orm.TxStatusTypeRelayed:
+			return types.TxStatusEventClaimed, true
+		case orm.RollupStatusType(message.RollupStatus) != orm.RollupStatusTypeFinalized:
+			return "", false
+		case txStatus == orm.TxStatusTypeSent, txStatus == orm.TxStatusTypeFailedRelayed, txStatus == orm.TxStatusTypeRelayTxReverted:
+			return types.TxStatusEventClaimable, true
+		}
+	}
+	return "", false
+}
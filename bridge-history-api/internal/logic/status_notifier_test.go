@@ -0,0 +1,224 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/bridge-history-api/internal/orm"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+const (
+	testSender   = "0x1111111111111111111111111111111111111111"
+	testReceiver = "0x2222222222222222222222222222222222222222"
+	testOther    = "0x3333333333333333333333333333333333333333"
+)
+
+// mockMessageGetter mirrors the (updated_at, id) cursor query of orm.CrossMessage on in memory messages.
+type mockMessageGetter struct {
+	messages []*orm.CrossMessage
+	calls    int
+}
+
+func (m *mockMessageGetter) GetMessagesUpdatedBetween(_ context.Context, updatedAt time.Time, id uint64, before time.Time, limit int) ([]*orm.CrossMessage, error) {
+	m.calls++
+	sorted := append([]*orm.CrossMessage(nil), m.messages...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].UpdatedAt.Equal(sorted[j].UpdatedAt) {
+			return sorted[i].UpdatedAt.Before(sorted[j].UpdatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	var result []*orm.CrossMessage
+	for _, message := range sorted {
+		afterCursor := message.UpdatedAt.After(updatedAt) || (message.UpdatedAt.Equal(updatedAt) && message.ID > id)
+		if afterCursor && message.UpdatedAt.Before(before) && len(result) < limit {
+			result = append(result, message)
+		}
+	}
+	return result, nil
+}
+
+func newDeposit(id uint64, sender string, updatedAt time.Time) *orm.CrossMessage {
+	return &orm.CrossMessage{
+		ID:          id,
+		MessageHash: fmt.Sprintf("%s-%d", sender, id),
+		MessageType: int(orm.MessageTypeL1SentMessage),
+		TxStatus:    int(orm.TxStatusTypeSent),
+		Sender:      sender,
+		Receiver:    testReceiver,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+func newTestNotifier(messages []*orm.CrossMessage, now time.Time) (*StatusNotifier, *mockMessageGetter, *time.Time) {
+	getter := &mockMessageGetter{messages: messages}
+	n := newStatusNotifier(getter, 10)
+	clock := now
+	n.now = func() time.Time { return clock }
+	n.lastUpdatedAt = clock.Add(-time.Hour)
+	return n, getter, &clock
+}
+
+func receiveEvents(sub *Subscription) []*types.TxStatusEvent {
+	var events []*types.TxStatusEvent
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestStatusNotifierCursor(t *testing.T) {
+	now := time.Now()
+	// messages sharing an update time are ordered by id across the poll batches.
+	updatedAt := now.Add(-time.Minute)
+	var messages []*orm.CrossMessage
+	for id := uint64(1); id <= 7; id++ {
+		messages = append(messages, newDeposit(id, testSender, updatedAt))
+	}
+	messages = append(messages, newDeposit(8, testSender, updatedAt.Add(time.Second)))
+	n, getter, _ := newTestNotifier(messages, now)
+	n.pollBatchSize = 3
+
+	sub, err := n.Subscribe([]string{testSender})
+	require.NoError(t, err)
+	defer n.Unsubscribe(sub)
+
+	require.NoError(t, n.poll(context.Background()))
+	events := receiveEvents(sub)
+	require.Len(t, events, 8)
+	for i, event := range events {
+		assert.Equal(t, types.TxStatusEventDeposited, event.Status)
+		assert.Equal(t, messages[i].MessageHash, event.Tx.MessageHash)
+	}
+	assert.Equal(t, 3, getter.calls)
+	assert.Equal(t, updatedAt.Add(time.Second), n.lastUpdatedAt)
+	assert.Equal(t, uint64(8), n.lastID)
+
+	// nothing is pushed twice, a message behind the cursor, e.g. committed more than statusPollLag late, is not pushed.
+	getter.messages = append(getter.messages, newDeposit(9, testSender, updatedAt))
+	require.NoError(t, n.poll(context.Background()))
+	assert.Empty(t, receiveEvents(sub))
+
+	// a later update of an already pushed message is pushed again.
+	relayed := newDeposit(2, testSender, updatedAt.Add(2*time.Second))
+	relayed.TxStatus = int(orm.TxStatusTypeRelayed)
+	getter.messages = append(getter.messages, relayed)
+	require.NoError(t, n.poll(context.Background()))
+	events = receiveEvents(sub)
+	require.Len(t, events, 1)
+	assert.Equal(t, types.TxStatusEventRelayed, events[0].Status)
+	assert.Equal(t, uint64(2), n.lastID)
+}
+
+func TestStatusNotifierLag(t *testing.T) {
+	now := time.Now()
+	messages := []*orm.CrossMessage{
+		newDeposit(1, testSender, now.Add(-statusPollLag-time.Second)),
+		newDeposit(2, testSender, now.Add(-time.Second)),
+	}
+	n, _, clock := newTestNotifier(messages, now)
+
+	sub, err := n.Subscribe([]string{testSender})
+	require.NoError(t, err)
+	defer n.Unsubscribe(sub)
+
+	// the message updated less than statusPollLag ago is left to a later poll.
+	require.NoError(t, n.poll(context.Background()))
+	events := receiveEvents(sub)
+	require.Len(t, events, 1)
+	assert.Equal(t, messages[0].MessageHash, events[0].Tx.MessageHash)
+
+	*clock = now.Add(statusPollLag - 2*time.Second)
+	require.NoError(t, n.poll(context.Background()))
+	assert.Empty(t, receiveEvents(sub))
+
+	*clock = now.Add(statusPollLag)
+	require.NoError(t, n.poll(context.Background()))
+	events = receiveEvents(sub)
+	require.Len(t, events, 1)
+	assert.Equal(t, messages[1].MessageHash, events[0].Tx.MessageHash)
+}
+
+func TestStatusNotifierIdle(t *testing.T) {
+	now := time.Now()
+	messages := []*orm.CrossMessage{newDeposit(1, testSender, now.Add(-time.Minute))}
+	n, getter, _ := newTestNotifier(messages, now)
+
+	// without subscribers the messages are skipped rather than pushed to the next subscriber.
+	require.NoError(t, n.poll(context.Background()))
+	assert.Equal(t, 0, getter.calls)
+	assert.Equal(t, now.Add(-statusPollLag), n.lastUpdatedAt)
+
+	sub, err := n.Subscribe([]string{testSender})
+	require.NoError(t, err)
+	defer n.Unsubscribe(sub)
+	require.NoError(t, n.poll(context.Background()))
+	assert.Empty(t, receiveEvents(sub))
+}
+
+func TestStatusNotifierSlowSubscriber(t *testing.T) {
+	now := time.Now()
+	var messages []*orm.CrossMessage
+	// one event more than the buffer of a subscriber which does not read any.
+	for id := uint64(1); id <= subscriptionBufferSize+1; id++ {
+		messages = append(messages, newDeposit(id, testSender, now.Add(-time.Minute)))
+	}
+	messages = append(messages, newDeposit(subscriptionBufferSize+2, testOther, now.Add(-time.Minute)))
+	n, _, _ := newTestNotifier(messages, now)
+
+	slow, err := n.Subscribe([]string{testSender})
+	require.NoError(t, err)
+	other, err := n.Subscribe([]string{testOther})
+	require.NoError(t, err)
+
+	require.NoError(t, n.poll(context.Background()))
+	events := receiveEvents(slow)
+	assert.Len(t, events, subscriptionBufferSize)
+	_, ok := <-slow.Events
+	assert.False(t, ok, "the slow subscription must be closed")
+	assert.True(t, slow.cancelled)
+	assert.NotContains(t, n.subscriptions, testSender)
+	assert.Equal(t, 1, n.numSubscribers)
+
+	// the other subscription is kept, cancelling the dropped one again is a no-op.
+	assert.Len(t, receiveEvents(other), 1)
+	n.Unsubscribe(slow)
+	assert.Equal(t, 1, n.numSubscribers)
+	assert.Contains(t, n.subscriptions, testOther)
+	n.Unsubscribe(other)
+	assert.Equal(t, 0, n.numSubscribers)
+	assert.Empty(t, n.subscriptions)
+}
+
+func TestStatusNotifierMaxSubscribers(t *testing.T) {
+	n := newStatusNotifier(&mockMessageGetter{}, 2)
+
+	sub1, err := n.Subscribe([]string{testSender})
+	require.NoError(t, err)
+	sub2, err := n.Subscribe([]string{testSender, testReceiver})
+	require.NoError(t, err)
+	_, err = n.Subscribe([]string{testOther})
+	assert.ErrorIs(t, err, ErrTooManySubscribers)
+	assert.NotContains(t, n.subscriptions, testOther)
+
+	n.Unsubscribe(sub1)
+	sub3, err := n.Subscribe([]string{testOther})
+	require.NoError(t, err)
+	n.Unsubscribe(sub2)
+	n.Unsubscribe(sub3)
+	assert.Equal(t, 0, n.numSubscribers)
+}
@@ -0,0 +1,84 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	backendabi "scroll-tech/bridge-history-api/abi"
+	"scroll-tech/bridge-history-api/internal/orm"
+	"scroll-tech/bridge-history-api/internal/types"
+	"scroll-tech/bridge-history-api/internal/utils"
+)
+
+var (
+	// ErrWithdrawalNotFound is returned if the message hash is not a L2 withdrawal.
+	ErrWithdrawalNotFound = errors.New("withdrawal not found")
+	// ErrWithdrawalNotFinalized is returned if the batch of the withdrawal is not finalized yet.
+	ErrWithdrawalNotFinalized = errors.New("withdrawal not finalized yet")
+)
+
+// GetL2WithdrawalProof gets the proof and the relayMessageWithProof calldata to claim the L2 withdrawal on L1.
+func (h *HistoryLogic) GetL2WithdrawalProof(ctx context.Context, messageHash string) (*types.WithdrawalProof, error) {
+	message, err := h.crossMessageOrm.GetL2WithdrawalByMessageHash(ctx, messageHash)
+	if err != nil {
+		log.Error("failed to get L2 withdrawal by message hash", "message hash", messageHash, "error", err)
+		return nil, err
+	}
+	if message == nil {
+		return nil, ErrWithdrawalNotFound
+	}
+	// the withdraw trie of the service only gives the proofs of the finalized withdrawals.
+	if orm.RollupStatusType(message.RollupStatus) != orm.RollupStatusTypeFinalized {
+		return nil, ErrWithdrawalNotFinalized
+	}
+
+	calldata, err := encodeRelayMessageWithProof(message)
+	if err != nil {
+		log.Error("failed to encode relayMessageWithProof", "message hash", messageHash, "error", err)
+		return nil, err
+	}
+
+	root := utils.ComputeMessageRoot(message.MessageNonce, common.HexToHash(message.MessageHash), message.MerkleProof)
+	return &types.WithdrawalProof{
+		MessageHash: message.MessageHash,
+		From:        message.MessageFrom,
+		To:          message.MessageTo,
+		Value:       message.MessageValue,
+		Nonce:       strconv.FormatUint(message.MessageNonce, 10),
+		Message:     message.MessageData,
+		Proof: types.L2MessageProof{
+			BatchIndex:  strconv.FormatUint(message.BatchIndex, 10),
+			MerkleProof: "0x" + common.Bytes2Hex(message.MerkleProof),
+		},
+		WithdrawRoot: root.Hex(),
+		Calldata:     hexutil.Encode(calldata),
+		Claimed:      orm.TxStatusType(message.TxStatus) == orm.TxStatusTypeRelayed,
+	}, nil
+}
+
+func encodeRelayMessageWithProof(message *orm.CrossMessage) ([]byte, error) {
+	value, ok := new(big.Int).SetString(message.MessageValue, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid message value: %s", message.MessageValue)
+	}
+	data, err := hexutil.Decode(message.MessageData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message data: %w", err)
+	}
+	proof := struct {
+		BatchIndex  *big.Int
+		MerkleProof []byte
+	}{
+		BatchIndex:  new(big.Int).SetUint64(message.BatchIndex),
+		MerkleProof: message.MerkleProof,
+	}
+	return backendabi.IL1ScrollMessengerABI.Pack("relayMessageWithProof", common.HexToAddress(message.MessageFrom),
+		common.HexToAddress(message.MessageTo), value, new(big.Int).SetUint64(message.MessageNonce), data, proof)
+}
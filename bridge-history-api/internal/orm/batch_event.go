@@ -71,6 +71,41 @@ func (c *BatchEvent) GetBatchEventSyncedHeightInDB(ctx context.Context) (uint64,
 	return batch.L1BlockNumber, nil
 }
 
+// GetBatchByIndex returns the batch of the index which is not reverted, nil if there is no such batch.
+func (c *BatchEvent) GetBatchByIndex(ctx context.Context, batchIndex uint64) (*BatchEvent, error) {
+	var batch BatchEvent
+	db := c.db.WithContext(ctx)
+	db = db.Model(&BatchEvent{})
+	db = db.Where("batch_index = ?", batchIndex)
+	db = db.Where("batch_status != ?", BatchStatusTypeReverted)
+	db = db.Order("id desc")
+	if err := db.First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get batch by index, index: %v, error: %w", batchIndex, err)
+	}
+	return &batch, nil
+}
+
+// GetBatchByL2BlockNumber returns the batch containing the L2 block which is not reverted, nil if there is no such batch.
+func (c *BatchEvent) GetBatchByL2BlockNumber(ctx context.Context, blockNumber uint64) (*BatchEvent, error) {
+	var batch BatchEvent
+	db := c.db.WithContext(ctx)
+	db = db.Model(&BatchEvent{})
+	db = db.Where("end_block_number >= ?", blockNumber)
+	db = db.Where("start_block_number <= ?", blockNumber)
+	db = db.Where("batch_status != ?", BatchStatusTypeReverted)
+	db = db.Order("end_block_number asc")
+	if err := db.First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get batch by L2 block number, block number: %v, error: %w", blockNumber, err)
+	}
+	return &batch, nil
+}
+
 // GetFinalizedBatchesLEBlockHeight returns the finalized batches with end block <= given block height in db.
 func (c *BatchEvent) GetFinalizedBatchesLEBlockHeight(ctx context.Context, blockHeight uint64) ([]*BatchEvent, error) {
 	var batches []*BatchEvent
@@ -176,6 +176,22 @@ func (c *CrossMessage) GetL2LatestFinalizedWithdrawal(ctx context.Context) (*Cro
 	return &message, nil
 }
 
+// GetL2WithdrawalByMessageHash returns the L2 withdrawal of the message hash from the database.
+func (c *CrossMessage) GetL2WithdrawalByMessageHash(ctx context.Context, messageHash string) (*CrossMessage, error) {
+	var message CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("message_type = ?", MessageTypeL2SentMessage)
+	db = db.Where("message_hash = ?", messageHash)
+	if err := db.First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get L2 withdrawal by message hash, message hash: %v, error: %w", messageHash, err)
+	}
+	return &message, nil
+}
+
 // GetL2WithdrawalsByBlockRange returns the L2 withdrawals by block range from the database.
 func (c *CrossMessage) GetL2WithdrawalsByBlockRange(ctx context.Context, startBlock, endBlock uint64) ([]*CrossMessage, error) {
 	var messages []*CrossMessage
@@ -207,51 +223,100 @@ func (c *CrossMessage) GetMessagesByTxHashes(ctx context.Context, txHashes []str
 	return messages, nil
 }
 
-// GetL2UnclaimedWithdrawalsByAddress retrieves all L2 unclaimed withdrawal messages for a given sender address.
-func (c *CrossMessage) GetL2UnclaimedWithdrawalsByAddress(ctx context.Context, sender string) ([]*CrossMessage, error) {
+// GetMessagesByMessageHashes retrieves all cross messages from the database that match the provided message hashes.
+func (c *CrossMessage) GetMessagesByMessageHashes(ctx context.Context, messageHashes []string) ([]*CrossMessage, error) {
+	var messages []*CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("message_hash in (?)", messageHashes)
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get messages by message hashes, message hashes: %v, error: %w", messageHashes, err)
+	}
+	return messages, nil
+}
+
+// GetMessageByTokenAddress returns a cross message bridging the L1 or L2 token, nil if the token was never bridged.
+func (c *CrossMessage) GetMessageByTokenAddress(ctx context.Context, tokenAddress string) (*CrossMessage, error) {
+	var message CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("(l1_token_address = ? OR l2_token_address = ?)", tokenAddress, tokenAddress)
+	if err := db.Take(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get message by token address, token address: %v, error: %w", tokenAddress, err)
+	}
+	return &message, nil
+}
+
+// GetL2UnclaimedWithdrawalsByAddress retrieves a page of L2 unclaimed withdrawal messages for a given sender address.
+func (c *CrossMessage) GetL2UnclaimedWithdrawalsByAddress(ctx context.Context, sender string, filter *MessageFilter, cursor *MessageCursor, limit int) ([]*CrossMessage, error) {
 	var messages []*CrossMessage
 	db := c.db.WithContext(ctx)
 	db = db.Model(&CrossMessage{})
 	db = db.Where("message_type = ?", MessageTypeL2SentMessage)
 	db = db.Where("tx_status = ?", TxStatusTypeSent)
 	db = db.Where("sender = ?", sender)
-	db = db.Order("block_timestamp desc")
-	db = db.Limit(500)
+	db = filter.apply(db)
+	db = cursor.apply(db)
+	db = db.Order("block_timestamp desc, id desc")
+	db = db.Limit(limit)
 	if err := db.Find(&messages).Error; err != nil {
 		return nil, fmt.Errorf("failed to get L2 claimable withdrawal messages by sender address, sender: %v, error: %w", sender, err)
 	}
 	return messages, nil
 }
 
-// GetL2WithdrawalsByAddress retrieves all L2 claimable withdrawal messages for a given sender address.
-func (c *CrossMessage) GetL2WithdrawalsByAddress(ctx context.Context, sender string) ([]*CrossMessage, error) {
+// GetL2WithdrawalsByAddress retrieves a page of L2 withdrawal messages for a given sender address.
+func (c *CrossMessage) GetL2WithdrawalsByAddress(ctx context.Context, sender string, filter *MessageFilter, cursor *MessageCursor, limit int) ([]*CrossMessage, error) {
 	var messages []*CrossMessage
 	db := c.db.WithContext(ctx)
 	db = db.Model(&CrossMessage{})
 	db = db.Where("message_type = ?", MessageTypeL2SentMessage)
 	db = db.Where("sender = ?", sender)
-	db = db.Order("block_timestamp desc")
-	db = db.Limit(500)
+	db = filter.apply(db)
+	db = cursor.apply(db)
+	db = db.Order("block_timestamp desc, id desc")
+	db = db.Limit(limit)
 	if err := db.Find(&messages).Error; err != nil {
 		return nil, fmt.Errorf("failed to get L2 withdrawal messages by sender address, sender: %v, error: %w", sender, err)
 	}
 	return messages, nil
 }
 
-// GetTxsByAddress retrieves all txs for a given sender address.
-func (c *CrossMessage) GetTxsByAddress(ctx context.Context, sender string) ([]*CrossMessage, error) {
+// GetTxsByAddress retrieves a page of txs for a given sender address.
+func (c *CrossMessage) GetTxsByAddress(ctx context.Context, sender string, filter *MessageFilter, cursor *MessageCursor, limit int) ([]*CrossMessage, error) {
 	var messages []*CrossMessage
 	db := c.db.WithContext(ctx)
 	db = db.Model(&CrossMessage{})
 	db = db.Where("sender = ?", sender)
-	db = db.Order("block_timestamp desc")
-	db = db.Limit(500)
+	db = filter.apply(db)
+	db = cursor.apply(db)
+	db = db.Order("block_timestamp desc, id desc")
+	db = db.Limit(limit)
 	if err := db.Find(&messages).Error; err != nil {
 		return nil, fmt.Errorf("failed to get all txs by sender address, sender: %v, error: %w", sender, err)
 	}
 	return messages, nil
 }
 
+// GetMessagesUpdatedBetween retrieves the cross messages updated after the (updatedAt, id) position and before the given
+// time, sorted by update time and id.
+func (c *CrossMessage) GetMessagesUpdatedBetween(ctx context.Context, updatedAt time.Time, id uint64, before time.Time, limit int) ([]*CrossMessage, error) {
+	var messages []*CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("(updated_at, id) > (?, ?)", updatedAt, id)
+	db = db.Where("updated_at < ?", before)
+	db = db.Order("updated_at asc, id asc")
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get updated messages, updated at: %v, id: %v, before: %v, error: %w", updatedAt, id, before, err)
+	}
+	return messages, nil
+}
+
 // UpdateL1MessageQueueEventsInfo updates the information about L1 message queue events in the database.
 func (c *CrossMessage) UpdateL1MessageQueueEventsInfo(ctx context.Context, l1MessageQueueEvents []*MessageQueueEvent) error {
 	// update tx statuses.
@@ -364,7 +429,7 @@ func (c *CrossMessage) InsertOrUpdateL1Messages(ctx context.Context, messages []
 	// 'tx_status' column is not explicitly assigned during the update to prevent a later status from being overwritten back to "sent".
 	db = db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "message_hash"}},
-		DoUpdates: clause.AssignmentColumns([]string{"sender", "receiver", "token_type", "l1_block_number", "l1_tx_hash", "l1_token_address", "l2_token_address", "token_ids", "token_amounts", "message_type", "block_timestamp", "message_nonce"}),
+		DoUpdates: clause.AssignmentColumns([]string{"sender", "receiver", "token_type", "l1_block_number", "l1_tx_hash", "l1_token_address", "l2_token_address", "token_ids", "token_amounts", "message_type", "block_timestamp", "message_nonce", "updated_at"}),
 	})
 	if err := db.Create(messages).Error; err != nil {
 		return fmt.Errorf("failed to insert message, error: %w", err)
@@ -383,7 +448,7 @@ func (c *CrossMessage) InsertOrUpdateL2Messages(ctx context.Context, messages []
 	// 'tx_status' column is not explicitly assigned during the update to prevent a later status from being overwritten back to "sent".
 	db = db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "message_hash"}},
-		DoUpdates: clause.AssignmentColumns([]string{"sender", "receiver", "token_type", "l2_block_number", "l2_tx_hash", "l1_token_address", "l2_token_address", "token_ids", "token_amounts", "message_type", "block_timestamp", "message_from", "message_to", "message_value", "message_data", "message_nonce"}),
+		DoUpdates: clause.AssignmentColumns([]string{"sender", "receiver", "token_type", "l2_block_number", "l2_tx_hash", "l1_token_address", "l2_token_address", "token_ids", "token_amounts", "message_type", "block_timestamp", "message_from", "message_to", "message_value", "message_data", "message_nonce", "updated_at"}),
 	})
 	if err := db.Create(messages).Error; err != nil {
 		return fmt.Errorf("failed to insert message, error: %w", err)
@@ -484,7 +549,7 @@ func (c *CrossMessage) InsertOrUpdateL2RelayedMessagesOfL1Deposits(ctx context.C
 	db = db.Model(&CrossMessage{})
 	db = db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "message_hash"}},
-		DoUpdates: clause.AssignmentColumns([]string{"message_type", "l2_block_number", "l2_tx_hash", "tx_status"}),
+		DoUpdates: clause.AssignmentColumns([]string{"message_type", "l2_block_number", "l2_tx_hash", "tx_status", "updated_at"}),
 		Where: clause.Where{
 			Exprs: []clause.Expression{
 				clause.And(
@@ -536,7 +601,7 @@ func (c *CrossMessage) InsertOrUpdateL1RelayedMessagesOfL2Withdrawals(ctx contex
 	db = db.Model(&CrossMessage{})
 	db = db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "message_hash"}},
-		DoUpdates: clause.AssignmentColumns([]string{"message_type", "l1_block_number", "l1_tx_hash", "tx_status"}),
+		DoUpdates: clause.AssignmentColumns([]string{"message_type", "l1_block_number", "l1_tx_hash", "tx_status", "updated_at"}),
 		Where: clause.Where{
 			Exprs: []clause.Expression{
 				clause.And(
@@ -0,0 +1,68 @@
+package orm
+
+import (
+	"gorm.io/gorm"
+)
+
+// MessageFilter narrows the cross messages returned by the address queries, a nil filter or an empty field matches any
+// message.
+type MessageFilter struct {
+	MessageType   MessageType    `json:"message_type,omitempty"` // direction, deposits or withdrawals.
+	TokenType     TokenType      `json:"token_type,omitempty"`
+	TokenAddress  string         `json:"token_address,omitempty"`  // matches either the L1 or the L2 token address.
+	TokenID       string         `json:"token_id,omitempty"`       // decimal, matches any of the token ids of erc721 and erc1155 messages.
+	MinAmount     string         `json:"min_amount,omitempty"`     // decimal, only single amount messages match.
+	MaxAmount     string         `json:"max_amount,omitempty"`     // decimal, only single amount messages match.
+	FromTimestamp *uint64        `json:"from_timestamp,omitempty"` // inclusive.
+	ToTimestamp   *uint64        `json:"to_timestamp,omitempty"`   // inclusive.
+	TxStatuses    []TxStatusType `json:"tx_statuses,omitempty"`
+}
+
+func (f *MessageFilter) apply(db *gorm.DB) *gorm.DB {
+	if f == nil {
+		return db
+	}
+	if f.MessageType != MessageTypeUnknown {
+		db = db.Where("message_type = ?", f.MessageType)
+	}
+	if f.TokenType != TokenTypeUnknown {
+		db = db.Where("token_type = ?", f.TokenType)
+	}
+	if f.TokenAddress != "" {
+		db = db.Where("(l1_token_address = ? OR l2_token_address = ?)", f.TokenAddress, f.TokenAddress)
+	}
+	if f.TokenID != "" {
+		// token_ids is a comma separated list, see utils.ConvertBigIntArrayToString.
+		db = db.Where("? = ANY(string_to_array(replace(token_ids, ' ', ''), ','))", f.TokenID)
+	}
+	if f.MinAmount != "" {
+		db = db.Where("token_amount >= CAST(? AS NUMERIC)", f.MinAmount)
+	}
+	if f.MaxAmount != "" {
+		db = db.Where("token_amount <= CAST(? AS NUMERIC)", f.MaxAmount)
+	}
+	if f.FromTimestamp != nil {
+		db = db.Where("block_timestamp >= ?", *f.FromTimestamp)
+	}
+	if f.ToTimestamp != nil {
+		db = db.Where("block_timestamp <= ?", *f.ToTimestamp)
+	}
+	if len(f.TxStatuses) > 0 {
+		db = db.Where("tx_status IN (?)", f.TxStatuses)
+	}
+	return db
+}
+
+// MessageCursor is the position of the last cross message of a page, the messages are sorted by block timestamp and
+// id in descending order, thus the next page starts right after it.
+type MessageCursor struct {
+	BlockTimestamp uint64 `json:"t"`
+	ID             uint64 `json:"i"`
+}
+
+func (c *MessageCursor) apply(db *gorm.DB) *gorm.DB {
+	if c == nil {
+		return db
+	}
+	return db.Where("(block_timestamp, id) < (?, ?)", c.BlockTimestamp, c.ID)
+}
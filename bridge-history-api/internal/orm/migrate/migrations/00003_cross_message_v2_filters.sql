@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- token_amounts holds a comma separated list for erc721 and erc1155, only single amounts can be compared.
+ALTER TABLE cross_message_v2 ADD COLUMN IF NOT EXISTS token_amount NUMERIC
+    GENERATED ALWAYS AS (CASE WHEN token_amounts ~ '^[0-9]+$' THEN token_amounts::NUMERIC END) STORED;
+
+-- the address queries are paginated by (block_timestamp, id) cursors.
+DROP INDEX IF EXISTS idx_cm_message_type_tx_status_sender_block_timestamp;
+DROP INDEX IF EXISTS idx_cm_message_type_sender_block_timestamp;
+DROP INDEX IF EXISTS idx_cm_sender_block_timestamp;
+CREATE INDEX IF NOT EXISTS idx_cm_message_type_tx_status_sender_block_timestamp_id ON cross_message_v2 (message_type, tx_status, sender, block_timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_message_type_sender_block_timestamp_id ON cross_message_v2 (message_type, sender, block_timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_sender_block_timestamp_id ON cross_message_v2 (sender, block_timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_sender_tx_status_block_timestamp_id ON cross_message_v2 (sender, tx_status, block_timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_sender_l1_token_address_block_timestamp_id ON cross_message_v2 (sender, l1_token_address, block_timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_sender_l2_token_address_block_timestamp_id ON cross_message_v2 (sender, l2_token_address, block_timestamp DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cm_sender_l2_token_address_block_timestamp_id;
+DROP INDEX IF EXISTS idx_cm_sender_l1_token_address_block_timestamp_id;
+DROP INDEX IF EXISTS idx_cm_sender_tx_status_block_timestamp_id;
+DROP INDEX IF EXISTS idx_cm_sender_block_timestamp_id;
+DROP INDEX IF EXISTS idx_cm_message_type_sender_block_timestamp_id;
+DROP INDEX IF EXISTS idx_cm_message_type_tx_status_sender_block_timestamp_id;
+CREATE INDEX IF NOT EXISTS idx_cm_message_type_tx_status_sender_block_timestamp ON cross_message_v2 (message_type, tx_status, sender, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_message_type_sender_block_timestamp ON cross_message_v2 (message_type, sender, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_cm_sender_block_timestamp ON cross_message_v2 (sender, block_timestamp DESC);
+ALTER TABLE cross_message_v2 DROP COLUMN IF EXISTS token_amount;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the status notifier follows the updated messages by (updated_at, id) position.
+CREATE INDEX IF NOT EXISTS idx_cm_updated_at_id ON cross_message_v2 (updated_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cm_updated_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the token mappings are looked up by L1 or L2 token address.
+CREATE INDEX IF NOT EXISTS idx_cm_l1_token_address ON cross_message_v2 (l1_token_address);
+CREATE INDEX IF NOT EXISTS idx_cm_l2_token_address ON cross_message_v2 (l2_token_address);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cm_l2_token_address;
+DROP INDEX IF EXISTS idx_cm_l1_token_address;
+-- +goose StatementEnd
@@ -30,6 +30,11 @@ func Route(router *gin.Engine, conf *config.Config, reg prometheus.Registerer) {
 	r.GET("/txs", api.HistoryCtrler.GetTxsByAddress)
 	r.GET("/l2/withdrawals", api.HistoryCtrler.GetL2WithdrawalsByAddress)
 	r.GET("/l2/unclaimed/withdrawals", api.HistoryCtrler.GetL2UnclaimedWithdrawalsByAddress)
+	r.GET("/l2/withdrawal/proof", api.HistoryCtrler.GetL2WithdrawalProof)
 
 	r.POST("/txsbyhashes", api.HistoryCtrler.PostQueryTxsByHashes)
+
+	r.POST("/graphql", api.GraphQLCtrler.Query)
+
+	r.GET("/subscribe", api.SubscriptionCtrler.Subscribe)
 }
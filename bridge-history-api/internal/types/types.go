@@ -23,13 +23,44 @@ const (
 	ErrGetTxsError = 40004
 	// ErrGetTxsByHashError represents an error when trying to get transactions by hash list.
 	ErrGetTxsByHashError = 40005
+	// ErrGetL2WithdrawalProofError represents an error when trying to get the claim proof of a L2 withdrawal.
+	ErrGetL2WithdrawalProofError = 40006
+	// ErrTooManySubscribedAddressesNo represents an error when a subscription has more addresses than allowed.
+	ErrTooManySubscribedAddressesNo = 40007
+	// ErrTooManySubscribersNo represents an error when the max number of concurrent subscriptions is reached.
+	ErrTooManySubscribersNo = 40008
 )
 
-// QueryByAddressRequest the request parameter of address api
+// Directions of the txs of address api
+const (
+	DirectionDeposit    = "deposit"
+	DirectionWithdrawal = "withdrawal"
+)
+
+// QueryByAddressRequest the request parameter of address api, the first page is returned without cursor
 type QueryByAddressRequest struct {
-	Address  string `form:"address" binding:"required"`
-	Page     uint64 `form:"page" binding:"required,min=1"`
-	PageSize uint64 `form:"page_size" binding:"required,min=1,max=100"`
+	Address       string  `form:"address" binding:"required"`
+	Cursor        string  `form:"cursor"`
+	PageSize      uint64  `form:"page_size" binding:"required,min=1,max=100"`
+	Token         string  `form:"token"`
+	TokenType     int     `form:"token_type" binding:"omitempty,min=1,max=4"`
+	TokenID       string  `form:"token_id" binding:"omitempty,number"`
+	MinAmount     string  `form:"min_amount" binding:"omitempty,number"`
+	MaxAmount     string  `form:"max_amount" binding:"omitempty,number"`
+	FromTimestamp *uint64 `form:"from_timestamp"`
+	ToTimestamp   *uint64 `form:"to_timestamp"`
+	TxStatuses    []int   `form:"tx_status" binding:"dive,min=0,max=6"`
+	Direction     string  `form:"direction" binding:"omitempty,oneof=deposit withdrawal"`
+}
+
+// SubscribeRequest the request parameter of subscribe api
+type SubscribeRequest struct {
+	Addresses []string `form:"address" binding:"required,min=1,dive,required"`
+}
+
+// QueryWithdrawalProofRequest the request parameter of withdrawal proof api
+type QueryWithdrawalProofRequest struct {
+	MessageHash string `form:"message_hash" binding:"required"`
 }
 
 // QueryByHashRequest the request parameter of hash api
@@ -43,6 +74,12 @@ type ResultData struct {
 	Total   uint64           `json:"total"`
 }
 
+// PagedResultData contains a page of txs and the cursor of the next page, empty if it is the last page
+type PagedResultData struct {
+	Results    []*TxHistoryInfo `json:"results"`
+	NextCursor string           `json:"next_cursor"`
+}
+
 // Response the response schema
 type Response struct {
 	ErrCode int         `json:"errcode"`
@@ -73,6 +110,39 @@ type L2MessageProof struct {
 	MerkleProof string `json:"merkle_proof"`
 }
 
+// WithdrawalProof is the schema of the claim proof of a L2 withdrawal, Calldata is the encoded
+// relayMessageWithProof call to L1ScrollMessenger, WithdrawRoot is the root given by the proof which
+// must match the withdraw root of the finalized batch
+type WithdrawalProof struct {
+	MessageHash  string         `json:"message_hash"`
+	From         string         `json:"from"`
+	To           string         `json:"to"`
+	Value        string         `json:"value"`
+	Nonce        string         `json:"nonce"`
+	Message      string         `json:"message"`
+	Proof        L2MessageProof `json:"proof"`
+	WithdrawRoot string         `json:"withdraw_root"`
+	Calldata     string         `json:"calldata"`
+	Claimed      bool           `json:"claimed"`
+}
+
+// BatchInfo is the schema of batch info
+type BatchInfo struct {
+	BatchIndex       uint64              `json:"batch_index"`
+	BatchHash        string              `json:"batch_hash"`
+	BatchStatus      orm.BatchStatusType `json:"batch_status"` // 1: committed, 3: finalized
+	L1BlockNumber    uint64              `json:"l1_block_number"`
+	StartBlockNumber uint64              `json:"start_block_number"`
+	EndBlockNumber   uint64              `json:"end_block_number"`
+}
+
+// TokenMapping is the schema of the L1 and L2 addresses of a bridged token
+type TokenMapping struct {
+	TokenType      orm.TokenType `json:"token_type"`
+	L1TokenAddress string        `json:"l1_token_address"`
+	L2TokenAddress string        `json:"l2_token_address"`
+}
+
 // TxHistoryInfo the schema of tx history infos
 type TxHistoryInfo struct {
 	Hash               string              `json:"hash"`
@@ -92,6 +162,20 @@ type TxHistoryInfo struct {
 	BlockTimestamp     uint64              `json:"block_timestamp"`
 }
 
+// Statuses pushed by the subscribe api
+const (
+	TxStatusEventDeposited = "deposited" // L1 deposit sent.
+	TxStatusEventRelayed   = "relayed"   // L1 deposit relayed on L2.
+	TxStatusEventClaimable = "claimable" // L2 withdrawal finalized on L1, thus can be claimed.
+	TxStatusEventClaimed   = "claimed"   // L2 withdrawal relayed on L1.
+)
+
+// TxStatusEvent the schema of a status transition pushed by the subscribe api
+type TxStatusEvent struct {
+	Status string         `json:"status"`
+	Tx     *TxHistoryInfo `json:"tx"`
+}
+
 // RenderJSON renders response with json
 func RenderJSON(ctx *gin.Context, errCode int, err error, data interface{}) {
 	var errMsg string
@@ -116,6 +200,20 @@ func RenderFailure(ctx *gin.Context, errCode int, err error) {
 	RenderJSON(ctx, errCode, err, nil)
 }
 
+// RenderFailureWithStatus renders failure response with json and the given http status
+func RenderFailureWithStatus(ctx *gin.Context, status int, errCode int, err error) {
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	renderData := Response{
+		ErrCode: errCode,
+		ErrMsg:  errMsg,
+		Data:    nil,
+	}
+	ctx.JSON(status, renderData)
+}
+
 // RenderFatal renders fatal response with json
 func RenderFatal(ctx *gin.Context, err error) {
 	var errMsg string
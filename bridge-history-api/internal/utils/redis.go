@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/bridge-history-api/internal/config"
+)
+
+// NewRedisClient returns a redis client, nil if redis is not configured.
+func NewRedisClient(cfg *config.RedisConfig) *redis.Client {
+	if cfg == nil {
+		log.Info("redis is not configured, the cache is disabled")
+		return nil
+	}
+	opts := &redis.Options{
+		Addr:         cfg.Address,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		MinIdleConns: cfg.MinIdleConns,
+		ReadTimeout:  time.Duration(cfg.ReadTimeoutMs * int(time.Millisecond)),
+	}
+	// Production Redis service has enabled transit_encryption.
+	if !cfg.Local {
+		opts.TLSConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: true, //nolint:gosec
+		}
+	}
+	log.Info("init redis client", "addr", opts.Addr, "user name", opts.Username, "is local", cfg.Local,
+		"min idle connections", opts.MinIdleConns, "read timeout", opts.ReadTimeout)
+	return redis.NewClient(opts)
+}
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -183,3 +185,24 @@ func GetSkippedQueueIndices(startIndex uint64, skippedBitmap *big.Int) []uint64
 	}
 	return indices
 }
+
+// EncodeCursor encodes the position of the last item of a page into an opaque pagination cursor.
+func EncodeCursor(position interface{}) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a pagination cursor returned by EncodeCursor into the position of the last item of a page.
+func DecodeCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, position); err != nil {
+		return fmt.Errorf("invalid cursor: %w", err)
+	}
+	return nil
+}
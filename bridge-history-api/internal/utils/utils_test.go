@@ -89,3 +89,20 @@ func TestGetSkippedQueueIndices(t *testing.T) {
 		assert.Equal(t, test.expected, got)
 	}
 }
+
+func TestCursor(t *testing.T) {
+	type position struct {
+		BlockTimestamp uint64 `json:"t"`
+		ID             uint64 `json:"i"`
+	}
+
+	cursor, err := EncodeCursor(&position{BlockTimestamp: 1700000000, ID: 42})
+	assert.NoError(t, err)
+
+	var decoded position
+	assert.NoError(t, DecodeCursor(cursor, &decoded))
+	assert.Equal(t, position{BlockTimestamp: 1700000000, ID: 42}, decoded)
+
+	assert.Error(t, DecodeCursor("not a cursor!", &decoded))
+	assert.Error(t, DecodeCursor("bm90IGpzb24", &decoded))
+}
@@ -122,6 +122,22 @@ func (w *WithdrawTrie) MessageRoot() common.Hash {
 	return w.branches[w.height]
 }
 
+// ComputeMessageRoot returns the root hash of withdraw trie given by the merkle proof of the message, to compare with
+// the withdraw root of the batch which finalized the message.
+func ComputeMessageRoot(messageNonce uint64, msgHash common.Hash, proofBytes []byte) common.Hash {
+	root := msgHash
+	index := messageNonce
+	for _, sibling := range decodeBytesToMerkleProof(proofBytes) {
+		if index%2 == 0 {
+			root = Keccak2(root, sibling)
+		} else {
+			root = Keccak2(sibling, root)
+		}
+		index >>= 1
+	}
+	return root
+}
+
 // decodeBytesToMerkleProof transfer byte array to bytes32 array. The caller should make sure the length is matched.
 func decodeBytesToMerkleProof(proofBytes []byte) []common.Hash {
 	proof := make([]common.Hash, len(proofBytes)/32)
@@ -208,3 +208,19 @@ func computeMerkleRoot(hashes []common.Hash) common.Hash {
 	}
 	return hashes[0]
 }
+
+func TestComputeMessageRoot(t *testing.T) {
+	trie := NewWithdrawTrie()
+	hashes := []common.Hash{
+		common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+		common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+		common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000003"),
+	}
+	proofs := trie.AppendMessages(hashes)
+	for i, hash := range hashes {
+		root := ComputeMessageRoot(uint64(i), hash, proofs[i])
+		if root != trie.MessageRoot() {
+			t.Fatalf("Invalid root of message %d, want %s, got %s", i, trie.MessageRoot().Hex(), root.Hex())
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package awsv4 signs the requests of the AWS APIs and of the S3 compatible storages with the AWS signature version 4.
+package awsv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	algorithm = "AWS4-HMAC-SHA256"
+	// UnsignedPayload is the payload hash of the presigned requests, their payload is not known when signing.
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Credentials are the AWS credentials of the signed requests, SessionToken is only set for temporary credentials.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// SignRequest signs the request in its Authorization header, signing its host and headers.
+func SignRequest(req *http.Request, body []byte, creds Credentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		SHA256Hex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	signature := signature(creds.SecretAccessKey, date, region, service, stringToSign(amzDate, scope, canonicalRequest))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", algorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// PresignURL returns the URL of the request signed in its query, valid for expiry from now. Only the host is signed,
+// the request is sent without credentials.
+func PresignURL(method string, u *url.URL, creds Credentials, region, service string, now time.Time, expiry time.Duration) string {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", algorithm)
+	query.Set("X-Amz-Credential", creds.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiry/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if creds.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalPath(u),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		UnsignedPayload,
+	}, "\n")
+	query.Set("X-Amz-Signature", signature(creds.SecretAccessKey, date, region, service, stringToSign(amzDate, scope, canonicalRequest)))
+
+	presigned := *u
+	presigned.RawQuery = canonicalQuery(query)
+	return presigned.String()
+}
+
+// SHA256Hex returns the hex encoded sha256 hash of data, e.g. the payload hash of the signed requests.
+func SHA256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalPath(u *url.URL) string {
+	if path := u.EscapedPath(); path != "" {
+		return path
+	}
+	return "/"
+}
+
+func stringToSign(amzDate, scope, canonicalRequest string) string {
+	return strings.Join([]string{algorithm, amzDate, scope, SHA256Hex([]byte(canonicalRequest))}, "\n")
+}
+
+func signature(secretAccessKey, date, region, service, stringToSign string) string {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, escape(key)+"="+escape(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// escape escapes every byte except the unreserved characters, as AWS signs them.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,126 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type testService struct{}
+
+func (s *testService) ChainId() *hexutil.Big { //nolint:revive,stylecheck
+	return (*hexutil.Big)(hexutil.MustDecodeBig("0x1"))
+}
+
+func (s *testService) GetTransactionCount(common.Address, string) hexutil.Uint64 {
+	return 10
+}
+
+func (s *testService) SendRawTransaction(hexutil.Bytes) (common.Hash, error) {
+	return common.Hash{}, errors.New("insufficient funds for gas * price + value")
+}
+
+func newTestProxy(t *testing.T) *Proxy {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", new(testService)))
+	node := httptest.NewServer(server)
+	t.Cleanup(node.Close)
+
+	proxy, err := NewProxy(node.URL)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = proxy.Close() })
+	return proxy
+}
+
+func TestProxy(t *testing.T) {
+	proxy := newTestProxy(t)
+	client, err := rpc.Dial(proxy.Endpoint())
+	assert.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+	addr := common.HexToAddress("0x01")
+
+	// forwarded.
+	var nonce hexutil.Uint64
+	assert.NoError(t, client.CallContext(ctx, &nonce, "eth_getTransactionCount", addr, "pending"))
+	assert.Equal(t, hexutil.Uint64(10), nonce)
+	err = client.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes{0x01})
+	assert.ErrorContains(t, err, "insufficient funds")
+	var rpcErr rpc.Error
+	assert.True(t, errors.As(err, &rpcErr))
+
+	// stale nonce and replacement underpriced.
+	proxy.Inject(StaleNonce(3), ReplacementUnderpriced(1))
+	assert.NoError(t, client.CallContext(ctx, &nonce, "eth_getTransactionCount", addr, "pending"))
+	assert.Equal(t, hexutil.Uint64(7), nonce)
+	err = client.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes{0x01})
+	assert.ErrorContains(t, err, "replacement transaction underpriced")
+	err = client.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes{0x01})
+	assert.ErrorContains(t, err, "insufficient funds")
+
+	// dropped and delayed.
+	proxy.Clear()
+	drop := DropCalls("eth_chainId", 1)
+	proxy.Inject(drop, DelayCalls("eth_chainId", 100*time.Millisecond))
+	var chainID hexutil.Big
+	assert.Error(t, client.CallContext(ctx, &chainID, "eth_chainId"))
+	start := time.Now()
+	assert.NoError(t, client.CallContext(ctx, &chainID, "eth_chainId"))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, 1, drop.Hits())
+	assert.Equal(t, 2, proxy.Calls("eth_chainId"))
+
+	// batch.
+	proxy.Clear()
+	proxy.Inject(StaleNonce(20))
+	batch := []rpc.BatchElem{
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "eth_getTransactionCount", Args: []interface{}{addr, "pending"}, Result: &nonce},
+	}
+	assert.NoError(t, client.BatchCallContext(ctx, batch))
+	assert.NoError(t, batch[0].Error)
+	assert.NoError(t, batch[1].Error)
+	assert.Equal(t, uint64(1), chainID.ToInt().Uint64())
+	assert.Equal(t, hexutil.Uint64(0), nonce)
+}
+
+func TestScenario(t *testing.T) {
+	proxy := newTestProxy(t)
+	client, err := ethclient.Dial(proxy.Endpoint())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	var restarted bool
+	scenario := Restart(0, 200*time.Millisecond, func() error {
+		restarted = true
+		return nil
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- proxy.Run(context.Background(), scenario)
+	}()
+
+	// the node is down while restarting.
+	assert.Eventually(t, func() bool {
+		_, err := client.ChainID(context.Background())
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, <-done)
+	assert.True(t, restarted)
+
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), chainID.Uint64())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, proxy.Run(ctx, NodeOutage(time.Second, time.Second)), context.Canceled)
+}
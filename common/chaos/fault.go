@@ -0,0 +1,131 @@
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RPCError is the json-rpc error a fault answers a call with.
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements error.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+// Fault is a fault injected into the calls of a json-rpc method. The matching faults apply in their injection order:
+// the delays add up, the first drop, error or result answers the call, and the rewrites of the forwarded result chain.
+type Fault struct {
+	// Method is the json-rpc method the fault applies to, empty for every method.
+	Method string
+	// Probability is the probability the fault applies to a matching call, 0 applies it to every call.
+	Probability float64
+	// Count is the number of calls the fault applies to, 0 for no limit.
+	Count int
+
+	// Delay delays the call before it is answered.
+	Delay time.Duration
+	// Drop closes the connection without an answer, as a node going away mid-call.
+	Drop bool
+	// Error answers the call with a json-rpc error instead of forwarding it.
+	Error *RPCError
+	// Result answers the call with the result instead of forwarding it.
+	Result json.RawMessage
+	// Rewrite rewrites the result of the forwarded call.
+	Rewrite func(result json.RawMessage) (json.RawMessage, error)
+
+	mu   sync.Mutex
+	hits int
+}
+
+// Hits returns the number of calls the fault applied to.
+func (f *Fault) Hits() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits
+}
+
+// apply checks whether the fault applies to the call of the method and records the hit.
+func (f *Fault) apply(method string) bool {
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Count > 0 && f.hits >= f.Count {
+		return false
+	}
+	if f.Probability > 0 && rand.Float64() >= f.Probability { //nolint:gosec
+		return false
+	}
+	f.hits++
+	return true
+}
+
+// answers returns whether the fault answers the call instead of forwarding it.
+func (f *Fault) answers() bool {
+	return f.Drop || f.Error != nil || f.Result != nil
+}
+
+// DropCalls drops the next count calls of the method, every call if count is 0.
+func DropCalls(method string, count int) *Fault {
+	return &Fault{Method: method, Count: count, Drop: true}
+}
+
+// DelayCalls delays every call of the method.
+func DelayCalls(method string, delay time.Duration) *Fault {
+	return &Fault{Method: method, Delay: delay}
+}
+
+// FailCalls answers the next count calls of the method with the json-rpc error, every call if count is 0.
+func FailCalls(method string, count int, code int, message string) *Fault {
+	return &Fault{Method: method, Count: count, Error: &RPCError{Code: code, Message: message}}
+}
+
+// ReplacementUnderpriced rejects the next count sent transactions as the txpool does when the fees of a replacement
+// are not bumped enough.
+func ReplacementUnderpriced(count int) *Fault {
+	return FailCalls("eth_sendRawTransaction", count, -32000, "replacement transaction underpriced")
+}
+
+// NonceTooLow rejects the next count sent transactions as the txpool does for a nonce already used.
+func NonceTooLow(count int) *Fault {
+	return FailCalls("eth_sendRawTransaction", count, -32000, "nonce too low")
+}
+
+// AlreadyKnown rejects the next count sent transactions as the txpool does for a transaction already in the pool.
+func AlreadyKnown(count int) *Fault {
+	return FailCalls("eth_sendRawTransaction", count, -32000, "already known")
+}
+
+// StaleNonce returns the nonces lagging lag behind, as a node not yet aware of the latest transactions of an address.
+func StaleNonce(lag uint64) *Fault {
+	return &Fault{
+		Method: "eth_getTransactionCount",
+		Rewrite: func(result json.RawMessage) (json.RawMessage, error) {
+			var hex string
+			if err := json.Unmarshal(result, &hex); err != nil {
+				return nil, err
+			}
+			nonce, err := strconv.ParseUint(strings.TrimPrefix(hex, "0x"), 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid nonce %s: %w", hex, err)
+			}
+			if nonce < lag {
+				nonce = 0
+			} else {
+				nonce -= lag
+			}
+			return json.Marshal(fmt.Sprintf("0x%x", nonce))
+		},
+	}
+}
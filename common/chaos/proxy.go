@@ -0,0 +1,228 @@
+// Package chaos injects faults between the services and their nodes in the tests, to exercise the resubmission and
+// recovery logic against dropped, delayed and rejected calls without patching the clients.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// message is a json-rpc request or response.
+type message struct {
+	Version string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *RPCError         `json:"error,omitempty"`
+}
+
+// errDropped is returned when a fault drops the call.
+var errDropped = errors.New("call dropped")
+
+// Proxy is a json-rpc proxy serving over http in front of a node, the calls are forwarded unless a fault applies.
+type Proxy struct {
+	client   *rpc.Client
+	listener net.Listener
+	server   *http.Server
+
+	mu     sync.Mutex
+	faults []*Fault
+	calls  map[string]int
+}
+
+// NewProxy starts a proxy on a local port in front of the node of the endpoint, either http or websocket.
+func NewProxy(target string) (*Proxy, error) {
+	client, err := rpc.Dial(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", target, err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	p := &Proxy{
+		client:   client,
+		listener: listener,
+		calls:    make(map[string]int),
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = p.server.Serve(listener)
+	}()
+	return p, nil
+}
+
+// Endpoint returns the http endpoint of the proxy.
+func (p *Proxy) Endpoint() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Inject adds the faults, applying after the faults already injected.
+func (p *Proxy) Inject(faults ...*Fault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = append(p.faults, faults...)
+}
+
+// Clear removes every fault, the calls are forwarded again.
+func (p *Proxy) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = nil
+}
+
+// Calls returns the number of calls of the method the proxy received, faulted or not.
+func (p *Proxy) Calls(method string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[method]
+}
+
+// Close stops the proxy and closes its client of the node.
+func (p *Proxy) Close() error {
+	err := p.server.Close()
+	p.client.Close()
+	return err
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		response interface{}
+		batch    = len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	)
+	if batch {
+		var reqs []*message
+		if err = json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := make([]*message, 0, len(reqs))
+		for _, req := range reqs {
+			resp, err := p.handle(r.Context(), req)
+			if err != nil {
+				p.fail(w, err)
+				return
+			}
+			resps = append(resps, resp)
+		}
+		response = resps
+	} else {
+		var req message
+		if err = json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := p.handle(r.Context(), &req)
+		if err != nil {
+			p.fail(w, err)
+			return
+		}
+		response = resp
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// fail answers a call which could not be answered, a dropped call closes the connection.
+func (p *Proxy) fail(w http.ResponseWriter, err error) {
+	if errors.Is(err, errDropped) {
+		panic(http.ErrAbortHandler)
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
+// handle applies the faults matching the call, it forwards the call unless a fault answers it.
+func (p *Proxy) handle(ctx context.Context, req *message) (*message, error) {
+	p.mu.Lock()
+	p.calls[req.Method]++
+	var faults []*Fault
+	for _, f := range p.faults {
+		if f.apply(req.Method) {
+			faults = append(faults, f)
+		}
+	}
+	p.mu.Unlock()
+
+	resp := &message{Version: "2.0", ID: req.ID}
+	var delay time.Duration
+	for _, f := range faults {
+		delay += f.Delay
+	}
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, errDropped
+		case <-time.After(delay):
+		}
+	}
+
+	for _, f := range faults {
+		if !f.answers() {
+			continue
+		}
+		switch {
+		case f.Drop:
+			return nil, errDropped
+		case f.Error != nil:
+			resp.Error = f.Error
+		default:
+			resp.Result = f.Result
+		}
+		return resp, nil
+	}
+
+	args := make([]interface{}, len(req.Params))
+	for i, param := range req.Params {
+		args[i] = param
+	}
+	var result json.RawMessage
+	if err := p.client.CallContext(ctx, &result, req.Method, args...); err != nil {
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		resp.Error = &RPCError{Code: rpcErr.ErrorCode(), Message: rpcErr.Error()}
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			resp.Error.Data = dataErr.ErrorData()
+		}
+		return resp, nil
+	}
+
+	for _, f := range faults {
+		if f.Rewrite == nil {
+			continue
+		}
+		rewritten, err := f.Rewrite(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite the result of %s: %w", req.Method, err)
+		}
+		result = rewritten
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	resp.Result = result
+	return resp, nil
+}
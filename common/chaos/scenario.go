@@ -0,0 +1,91 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Step is a step of a scenario, it runs once the previous step ran and its delay elapsed.
+type Step struct {
+	// After is the delay since the previous step.
+	After time.Duration
+	// Clear removes the faults of the previous steps first.
+	Clear bool
+	// Faults are injected into the proxy.
+	Faults []*Fault
+	// Action is run last, e.g. restarting the L1 container.
+	Action func() error
+}
+
+// Scenario is a sequence of faults and actions run against a proxy while the test runs.
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Run runs the steps of the scenario in order, it returns once the last step ran, ctx is done or an action failed.
+func (p *Proxy) Run(ctx context.Context, s *Scenario) error {
+	for i, step := range s.Steps {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(step.After):
+		}
+		if step.Clear {
+			p.Clear()
+		}
+		p.Inject(step.Faults...)
+		if step.Action != nil {
+			if err := step.Action(); err != nil {
+				return fmt.Errorf("scenario %s step %d failed: %w", s.Name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// NodeOutage drops every call for the duration, then the node is reachable again.
+func NodeOutage(after, duration time.Duration) *Scenario {
+	return &Scenario{
+		Name: "node outage",
+		Steps: []Step{
+			{After: after, Faults: []*Fault{DropCalls("", 0)}},
+			{After: duration, Clear: true},
+		},
+	}
+}
+
+// SlowNode delays every call for the duration.
+func SlowNode(after, duration, delay time.Duration) *Scenario {
+	return &Scenario{
+		Name: "slow node",
+		Steps: []Step{
+			{After: after, Faults: []*Fault{DelayCalls("", delay)}},
+			{After: duration, Clear: true},
+		},
+	}
+}
+
+// LaggingNode returns stale nonces and rejects the transactions sent with them as already used, until the duration
+// elapsed.
+func LaggingNode(after, duration time.Duration, lag uint64) *Scenario {
+	return &Scenario{
+		Name: "lagging node",
+		Steps: []Step{
+			{After: after, Faults: []*Fault{StaleNonce(lag), NonceTooLow(0)}},
+			{After: duration, Clear: true},
+		},
+	}
+}
+
+// Restart runs the restart, e.g. App.RestartL1, then drops the calls for the downtime while the node catches up.
+func Restart(after, downtime time.Duration, restart func() error) *Scenario {
+	return &Scenario{
+		Name: "restart",
+		Steps: []Step{
+			{After: after, Faults: []*Fault{DropCalls("", 0)}, Action: restart},
+			{After: downtime, Clear: true},
+		},
+	}
+}
@@ -2,10 +2,18 @@ package database
 
 // Config db config
 type Config struct {
-	// data source name
+	// data source name, or a reference to the secret store holding it, e.g. vault://secret/data/rollup#dsn.
 	DSN        string `json:"dsn"`
 	DriverName string `json:"driver_name"`
 
 	MaxOpenNum int `json:"maxOpenNum"`
 	MaxIdleNum int `json:"maxIdleNum"`
+
+	// data source name or reference of a read replica serving the heavy reads, they are served by the primary when empty.
+	ReplicaDSN string `json:"replica_dsn,omitempty"`
+	// the replication lag beyond which the reads fall back to the primary, defaultMaxReplicaLagSec when 0.
+	MaxReplicaLagSec uint64 `json:"max_replica_lag_sec,omitempty"`
+	// the period of resolving the dsn references again, the new postgres connections use the rotated credentials.
+	// The references are only resolved on start when 0.
+	SecretRefreshSec uint64 `json:"secret_refresh_sec,omitempty"`
 }
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/utils"
 
+	"scroll-tech/common/secrets"
+	"scroll-tech/common/tracing"
 	cutils "scroll-tech/common/utils"
 )
 
@@ -44,14 +48,11 @@ func (g *gormLogger) Trace(_ context.Context, begin time.Time, fc func() (string
 	g.gethLogger.Debug("gorm", "line", utils.FileWithLineNum(), "cost", elapsed, "sql", sql, "rowsAffected", rowsAffected, "err", err)
 }
 
-// InitDB init the db handler
-func InitDB(config *Config) (*gorm.DB, error) {
-	tmpGormLogger := gormLogger{
-		gethLogger: log.Root(),
-	}
-
-	db, err := gorm.Open(postgres.Open(config.DSN), &gorm.Config{
-		Logger: &tmpGormLogger,
+func newGormConfig() *gorm.Config {
+	return &gorm.Config{
+		Logger: &gormLogger{
+			gethLogger: log.Root(),
+		},
 		NowFunc: func() time.Time {
 			// why set time to UTC.
 			// if now set this, the inserted data time will use local timezone. like 2023-07-18 18:24:00 CST+8
@@ -60,21 +61,83 @@ func InitDB(config *Config) (*gorm.DB, error) {
 			// the timezone by loc=Local. but postgres's dsn don't have loc option to set timezone, so just need set the gorm option like that.
 			return cutils.NowUTC()
 		},
-	})
+	}
+}
+
+func setPoolLimits(sqlDB *sql.DB, config *Config) {
+	sqlDB.SetConnMaxLifetime(time.Minute * 10)
+	sqlDB.SetConnMaxIdleTime(time.Minute * 5)
+
+	sqlDB.SetMaxOpenConns(config.MaxOpenNum)
+	sqlDB.SetMaxIdleConns(config.MaxIdleNum)
+}
+
+// dialectors are the gorm dialectors of the drivers other than postgres, by driver name, e.g. sqlite when built with
+// the sqlite tag.
+var dialectors = map[string]func(dsn string) gorm.Dialector{}
+
+func openDialector(config *Config) (gorm.Dialector, error) {
+	if config.DriverName == "" || config.DriverName == "postgres" {
+		return openPostgres(config.DSN, config.SecretRefreshSec)
+	}
+	open, ok := dialectors[config.DriverName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported db driver: %s", config.DriverName)
+	}
+	dsn, err := secrets.Resolve(context.Background(), config.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the dsn: %w", err)
+	}
+	return open(dsn), nil
+}
+
+// openPostgres returns the postgres dialector of the dsn or dsn reference. A reference refreshed periodically is
+// resolved again before opening the connections once its refresh period elapsed, so that the rotated credentials are
+// used by the new connections while the open ones are kept.
+func openPostgres(dsn string, refreshSec uint64) (gorm.Dialector, error) {
+	value, err := secrets.NewValue(context.Background(), dsn, time.Duration(refreshSec)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the dsn: %w", err)
+	}
+	if refreshSec == 0 || !secrets.IsReference(dsn) {
+		return postgres.Open(value.Get(context.Background())), nil
+	}
+
+	connConfig, err := pgx.ParseConfig(value.Get(context.Background()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the dsn: %w", err)
+	}
+	sqlDB := stdlib.OpenDB(*connConfig, stdlib.OptionBeforeConnect(func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+		latest, parseErr := pgx.ParseConfig(value.Get(ctx))
+		if parseErr != nil {
+			return fmt.Errorf("failed to parse the refreshed dsn: %w", parseErr)
+		}
+		*connConfig = *latest
+		return nil
+	}))
+	return postgres.New(postgres.Config{Conn: sqlDB}), nil
+}
+
+// InitDB init the db handler
+func InitDB(config *Config) (*gorm.DB, error) {
+	dialector, err := openDialector(config)
 	if err != nil {
 		return nil, err
 	}
+	db, err := gorm.Open(dialector, newGormConfig())
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, err
+	}
 
 	sqlDB, pingErr := Ping(db)
 	if pingErr != nil {
 		return nil, pingErr
 	}
 
-	sqlDB.SetConnMaxLifetime(time.Minute * 10)
-	sqlDB.SetConnMaxIdleTime(time.Minute * 5)
-
-	sqlDB.SetMaxOpenConns(config.MaxOpenNum)
-	sqlDB.SetMaxIdleConns(config.MaxIdleNum)
+	setPoolLimits(sqlDB, config)
 
 	return db, nil
 }
@@ -0,0 +1,51 @@
+//go:build sqlite
+
+package database
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func init() {
+	dialectors["sqlite"] = func(dsn string) gorm.Dialector {
+		return sqlite.Open(dsn)
+	}
+}
+
+var sqliteDBCount uint64
+
+// InitSQLiteDB opens a new in-memory sqlite database, for the unit tests only needing the ORMs and not a postgres
+// container, and creates the tables of the models. The postgres migrations do not apply to sqlite, the tables are
+// created from the gorm models instead, so the queries relying on postgres, e.g. on partitions, are not supported.
+// The columns of the models must match the migrations, the rollup ORM tests check it for the tables they create.
+func InitSQLiteDB(models ...interface{}) (*gorm.DB, error) {
+	// every database is shared by the connections of its pool, and dropped with the last connection.
+	dsn := fmt.Sprintf("file:scroll_test_%d?mode=memory&cache=shared&_busy_timeout=5000", atomic.AddUint64(&sqliteDBCount, 1))
+	db, err := InitDB(&Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+		// sqlite serializes the writes anyway.
+		MaxOpenNum: 1,
+		MaxIdleNum: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// never recycle the connection, it would drop the database.
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	if err := db.AutoMigrate(models...); err != nil {
+		_ = CloseDB(db)
+		return nil, fmt.Errorf("failed to create the sqlite tables: %w", err)
+	}
+	return db, nil
+}
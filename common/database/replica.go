@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/tracing"
+)
+
+const (
+	// defaultMaxReplicaLagSec is the max replica lag used when max_replica_lag_sec is not configured.
+	defaultMaxReplicaLagSec = 10
+
+	replicaLagCheckInterval = 5 * time.Second
+
+	// replicaLagQuery returns the replication lag in seconds, a replica which replayed all the wal it received is not
+	// lagging even if the primary has been idle since, and a primary is never lagging.
+	replicaLagQuery = `SELECT COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)`
+)
+
+// replicaPool is the connection pool of the read db handler, the queries are sent to the replica as long as its lag is
+// below the threshold, and to the primary otherwise.
+type replicaPool struct {
+	primary *sql.DB
+	replica *sql.DB
+
+	maxLag time.Duration
+	// healthy is false while the replica lags or is unreachable.
+	healthy atomic.Bool
+}
+
+func (p *replicaPool) pool() *sql.DB {
+	if p.healthy.Load() {
+		return p.replica
+	}
+	return p.primary
+}
+
+// PrepareContext implements gorm.ConnPool.
+func (p *replicaPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.pool().PrepareContext(ctx, query)
+}
+
+// ExecContext implements gorm.ConnPool.
+func (p *replicaPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.pool().ExecContext(ctx, query, args...)
+}
+
+// QueryContext implements gorm.ConnPool.
+func (p *replicaPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.pool().QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext implements gorm.ConnPool.
+func (p *replicaPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.pool().QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx implements gorm.TxBeginner, the whole transaction is sent to the same db.
+func (p *replicaPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.pool().BeginTx(ctx, opts)
+}
+
+// GetDBConn implements gorm.GetDBConnector, the replica is the db of the read db handler.
+func (p *replicaPool) GetDBConn() (*sql.DB, error) {
+	return p.replica, nil
+}
+
+func (p *replicaPool) checkLag(ctx context.Context) {
+	var lagSec float64
+	err := p.replica.QueryRowContext(ctx, replicaLagQuery).Scan(&lagSec)
+	healthy := err == nil && lagSec <= p.maxLag.Seconds()
+	if healthy == p.healthy.Swap(healthy) {
+		return
+	}
+	if healthy {
+		log.Info("replica caught up, reads are routed to the replica", "lag sec", lagSec)
+	} else {
+		log.Warn("replica lagging or unreachable, reads fall back to the primary", "lag sec", lagSec, "max lag", p.maxLag, "err", err)
+	}
+}
+
+func (p *replicaPool) watchLag(ctx context.Context) {
+	ticker := time.NewTicker(replicaLagCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := p.replica.Close(); err != nil {
+				log.Error("failed to close replica db", "err", err)
+			}
+			return
+		case <-ticker.C:
+			p.checkLag(ctx)
+		}
+	}
+}
+
+// InitReadDB init the db handler of the heavy reads, the primary is returned as is if no replica is configured. The
+// reads are routed to the replica while it keeps up with the primary, and fall back to the primary otherwise, thus
+// they may be up to max_replica_lag_sec behind the writes. The handler must not be used to write, and its replica
+// connections are closed once ctx is done.
+func InitReadDB(ctx context.Context, config *Config, primary *gorm.DB) (*gorm.DB, error) {
+	if config.ReplicaDSN == "" {
+		return primary, nil
+	}
+
+	primarySQLDB, err := primary.DB()
+	if err != nil {
+		return nil, err
+	}
+	// the replica is not pinged, an unreachable replica only makes the reads fall back to the primary.
+	replicaCfg := newGormConfig()
+	replicaCfg.DisableAutomaticPing = true
+	replicaDialector, err := openPostgres(config.ReplicaDSN, config.SecretRefreshSec)
+	if err != nil {
+		return nil, err
+	}
+	replica, err := gorm.Open(replicaDialector, replicaCfg)
+	if err != nil {
+		return nil, err
+	}
+	replicaSQLDB, err := replica.DB()
+	if err != nil {
+		return nil, err
+	}
+	setPoolLimits(replicaSQLDB, config)
+
+	maxLagSec := config.MaxReplicaLagSec
+	if maxLagSec == 0 {
+		maxLagSec = defaultMaxReplicaLagSec
+	}
+	pool := &replicaPool{
+		primary: primarySQLDB,
+		replica: replicaSQLDB,
+		maxLag:  time.Duration(maxLagSec) * time.Second,
+	}
+	pool.checkLag(ctx)
+	log.Info("init read db", "replica healthy", pool.healthy.Load(), "max lag", pool.maxLag)
+	go pool.watchLag(ctx)
+
+	readCfg := newGormConfig()
+	readCfg.DisableAutomaticPing = true
+	readDB, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), readCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = readDB.Use(tracing.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	return readDB, nil
+}
@@ -0,0 +1,180 @@
+package docker
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"scroll-tech/common/cmd"
+)
+
+const anvilImage = "ghcr.io/foundry-rs/foundry:latest"
+
+// ImgAnvil the anvil image manager, an alternative l1 backend mining instantly which supports fork mode and
+// snapshots.
+type ImgAnvil struct {
+	image string
+	name  string
+	id    string
+
+	port      int
+	chainID   *big.Int
+	blockTime uint64
+
+	forkURL         string
+	forkBlockNumber uint64
+
+	running bool
+	cmd     *cmd.Cmd
+}
+
+// AnvilOption configures the anvil image.
+type AnvilOption func(*ImgAnvil)
+
+// WithAnvilFork runs anvil in fork mode, on top of the state of the chain of url at the given block, 0 for the latest
+// block.
+func WithAnvilFork(url string, blockNumber uint64) AnvilOption {
+	return func(i *ImgAnvil) {
+		i.forkURL = url
+		i.forkBlockNumber = blockNumber
+	}
+}
+
+// WithAnvilBlockTime mines a block every given seconds instead of a block per transaction.
+func WithAnvilBlockTime(blockTimeSec uint64) AnvilOption {
+	return func(i *ImgAnvil) {
+		i.blockTime = blockTimeSec
+	}
+}
+
+// NewImgAnvil return anvil img instance.
+func NewImgAnvil(port int, opts ...AnvilOption) *ImgAnvil {
+	img := &ImgAnvil{
+		image: anvilImage,
+		name:  fmt.Sprintf("anvil-%d", time.Now().Nanosecond()),
+		port:  port,
+	}
+	for _, opt := range opts {
+		opt(img)
+	}
+	img.cmd = cmd.NewCmd("docker", img.params()...)
+	return img
+}
+
+// Start run image and check if it is running healthily.
+func (i *ImgAnvil) Start() error {
+	id := GetContainerID(i.name)
+	if id != "" {
+		return fmt.Errorf("container already exist, name: %s", i.name)
+	}
+	i.id = runContainer(i.cmd, i.name, "Listening on", time.Second*30)
+	i.running = i.id != ""
+	if !i.running {
+		_ = i.Stop()
+		return fmt.Errorf("failed to start image: %s", i.image)
+	}
+
+	i.chainID = waitChainID(i.Endpoint())
+	if err := i.setGenesisAlloc(); err != nil {
+		_ = i.Stop()
+		return fmt.Errorf("failed to fund the l1 genesis accounts: %w", err)
+	}
+	return nil
+}
+
+// setGenesisAlloc funds the accounts and deploys the contracts of the l1geth genesis.
+func (i *ImgAnvil) setGenesisAlloc() error {
+	genesis, err := parseL1Genesis()
+	if err != nil {
+		return err
+	}
+	for address, account := range genesis.Alloc {
+		address = "0x" + strings.TrimPrefix(address, "0x")
+		if err := callRPC(i.Endpoint(), nil, "anvil_setBalance", address, account.Balance); err != nil {
+			return err
+		}
+		if account.Code != "" {
+			if err := callRPC(i.Endpoint(), nil, "anvil_setCode", address, account.Code); err != nil {
+				return err
+			}
+		}
+		for slot, value := range account.Storage {
+			if err := callRPC(i.Endpoint(), nil, "anvil_setStorageAt", address, slot, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsRunning returns docker container's running status.
+func (i *ImgAnvil) IsRunning() bool {
+	return i.running
+}
+
+// Endpoint return the connection endpoint, anvil serves websocket on the same port.
+func (i *ImgAnvil) Endpoint() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", i.port)
+}
+
+// ChainID return chainID.
+func (i *ImgAnvil) ChainID() *big.Int {
+	return i.chainID
+}
+
+func (i *ImgAnvil) containerName() string {
+	return i.name
+}
+
+// Snapshot snapshots the state of the chain, it returns the id to revert to.
+func (i *ImgAnvil) Snapshot() (string, error) {
+	var id string
+	if err := callRPC(i.Endpoint(), &id, "evm_snapshot"); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Revert reverts the state of the chain to the snapshot, the snapshot and the later ones can not be reverted to
+// again.
+func (i *ImgAnvil) Revert(id string) error {
+	var ok bool
+	if err := callRPC(i.Endpoint(), &ok, "evm_revert", id); err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to revert to snapshot: %s", id)
+	}
+	return nil
+}
+
+// Stop the docker container.
+func (i *ImgAnvil) Stop() error {
+	if !i.running {
+		return nil
+	}
+	i.running = false
+	return removeContainer(i.name, i.id)
+}
+
+func (i *ImgAnvil) params() []string {
+	cmds := []string{"run", "--rm", "--name", i.name, "-p", strconv.Itoa(i.port) + ":8545"}
+
+	args := []string{"anvil", "--host", "0.0.0.0", "--port", "8545"}
+	if i.forkURL != "" {
+		args = append(args, "--fork-url", i.forkURL)
+		if i.forkBlockNumber != 0 {
+			args = append(args, "--fork-block-number", strconv.FormatUint(i.forkBlockNumber, 10))
+		}
+	} else if genesis, err := parseL1Genesis(); err == nil {
+		args = append(args, "--chain-id", strconv.FormatUint(genesis.Config.ChainID, 10))
+	}
+	if i.blockTime != 0 {
+		args = append(args, "--block-time", strconv.FormatUint(i.blockTime, 10))
+	}
+
+	// the entrypoint of the foundry image is `sh -c`.
+	return append(cmds, i.image, strings.Join(args, " "))
+}
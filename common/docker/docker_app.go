@@ -40,6 +40,9 @@ type App struct {
 	L2gethImg GethImgInstance
 	DBImg     ImgInstance
 
+	// the node L1gethImg runs, $L1_BACKEND or geth by default.
+	l1Backend L1Backend
+
 	dbClient     *sql.DB
 	DBConfig     *database.DBConfig
 	DBConfigFile string
@@ -51,9 +54,14 @@ type App struct {
 // NewDockerApp returns new instance of dockerApp struct
 func NewDockerApp() *App {
 	timestamp := time.Now().Nanosecond()
+	l1Backend := L1Backend(os.Getenv("L1_BACKEND"))
+	if l1Backend == "" {
+		l1Backend = L1BackendGeth
+	}
 	app := &App{
 		Timestamp:    timestamp,
-		L1gethImg:    newTestL1Docker(),
+		L1gethImg:    newTestL1Docker(l1Backend),
+		l1Backend:    l1Backend,
 		L2gethImg:    newTestL2Docker(),
 		DBImg:        newTestDBDocker("postgres"),
 		DBConfigFile: fmt.Sprintf("/tmp/%d_db-config.json", timestamp),
@@ -112,6 +120,39 @@ func (b *App) RunL1Geth(t *testing.T) {
 	assert.NoError(t, b.L1gethImg.Start())
 }
 
+// RunL1Backend starts the L1 chain on the given backend, the L1 chain running on another backend is stopped first.
+// The anvil options only apply to the anvil backend.
+func (b *App) RunL1Backend(t *testing.T, backend L1Backend, opts ...AnvilOption) {
+	if b.l1Backend != backend || len(opts) > 0 {
+		if b.L1gethImg.IsRunning() {
+			assert.NoError(t, b.L1gethImg.Stop())
+		}
+		b.l1Backend = backend
+		b.L1gethImg = newTestL1Docker(backend, opts...)
+	}
+	b.RunL1Geth(t)
+}
+
+// L1Snapshot snapshots the state of the L1 chain, the L1 backend must support snapshots, e.g. anvil.
+func (b *App) L1Snapshot(t *testing.T) string {
+	img, ok := b.L1gethImg.(SnapshotImgInstance)
+	if !ok {
+		t.Fatalf("l1 backend %s does not support snapshots", b.l1Backend)
+	}
+	id, err := img.Snapshot()
+	assert.NoError(t, err)
+	return id
+}
+
+// L1Revert reverts the state of the L1 chain to the snapshot.
+func (b *App) L1Revert(t *testing.T, id string) {
+	img, ok := b.L1gethImg.(SnapshotImgInstance)
+	if !ok {
+		t.Fatalf("l1 backend %s does not support snapshots", b.l1Backend)
+	}
+	assert.NoError(t, img.Revert(id))
+}
+
 // L1Client returns a ethclient by dialing running l1geth
 func (b *App) L1Client() (*ethclient.Client, error) {
 	if utils.IsNil(b.L1gethImg) {
@@ -180,9 +221,16 @@ func (b *App) mockDBConfig() error {
 	return os.WriteFile(b.DBConfigFile, data, 0644) //nolint:gosec
 }
 
-func newTestL1Docker() GethImgInstance {
+func newTestL1Docker(backend L1Backend, opts ...AnvilOption) GethImgInstance {
 	id, _ := rand.Int(rand.Reader, big.NewInt(2000))
-	return NewImgGeth("scroll_l1geth", "", "", 0, l1StartPort+int(id.Int64()))
+	switch backend {
+	case L1BackendAnvil:
+		return NewImgAnvil(l1StartPort+int(id.Int64()), opts...)
+	case L1BackendReth:
+		return NewImgReth(0, l1StartPort+int(id.Int64()))
+	default:
+		return NewImgGeth("scroll_l1geth", "", "", 0, l1StartPort+int(id.Int64()))
+	}
 }
 
 func newTestL2Docker() GethImgInstance {
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// namedImgInstance is an img instance running in a named container.
+type namedImgInstance interface {
+	containerName() string
+}
+
+// l1ContainerID returns the id of the running L1 container.
+func (b *App) l1ContainerID() (string, error) {
+	img, ok := b.L1gethImg.(namedImgInstance)
+	if !ok || !b.L1gethImg.IsRunning() {
+		return "", fmt.Errorf("l1 geth is not running")
+	}
+	id := GetContainerID(img.containerName())
+	if id == "" {
+		return "", fmt.Errorf("l1 container not found, name: %s", img.containerName())
+	}
+	return id, nil
+}
+
+// PauseL1 freezes the L1 container, the node stops answering until ResumeL1 while its connections stay open.
+func (b *App) PauseL1() error {
+	id, err := b.l1ContainerID()
+	if err != nil {
+		return err
+	}
+	return cli.ContainerPause(context.Background(), id)
+}
+
+// ResumeL1 resumes the L1 container frozen by PauseL1.
+func (b *App) ResumeL1() error {
+	id, err := b.l1ContainerID()
+	if err != nil {
+		return err
+	}
+	return cli.ContainerUnpause(context.Background(), id)
+}
+
+// RestartL1 kills and restarts the L1 container and waits until the node answers again, the node resumes from the
+// chain it persisted and the connections to the node are closed.
+func (b *App) RestartL1() error {
+	id, err := b.l1ContainerID()
+	if err != nil {
+		return err
+	}
+	timeoutSec := 0
+	if err = cli.ContainerRestart(context.Background(), id, container.StopOptions{Timeout: &timeoutSec}); err != nil {
+		return err
+	}
+	if waitChainID(b.L1gethImg.Endpoint()) == nil {
+		return fmt.Errorf("l1 geth did not restart")
+	}
+	return nil
+}
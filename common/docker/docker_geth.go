@@ -93,6 +93,10 @@ func (i *ImgGeth) ChainID() *big.Int {
 	return i.chainID
 }
 
+func (i *ImgGeth) containerName() string {
+	return i.name
+}
+
 func (i *ImgGeth) isOk() bool {
 	keyword := "WebSocket enabled"
 	okCh := make(chan struct{}, 1)
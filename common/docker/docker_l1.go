@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"context"
+	_ "embed" // embed the l1geth genesis
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/common/cmd"
+	"scroll-tech/common/utils"
+)
+
+// L1Backend is the node the L1 chain of the tests runs on.
+type L1Backend string
+
+const (
+	// L1BackendGeth runs the scroll_l1geth image, a clique chain sealing a block every 3 seconds.
+	L1BackendGeth L1Backend = "geth"
+	// L1BackendAnvil runs anvil, mining a block per transaction, it supports fork mode and snapshots.
+	L1BackendAnvil L1Backend = "anvil"
+	// L1BackendReth runs reth in dev mode, mining a block per transaction.
+	L1BackendReth L1Backend = "reth"
+)
+
+// l1GenesisJSON is the genesis of the l1geth image, the other backends fund the same accounts and contracts so the
+// tests run unchanged on any of them.
+//
+//go:embed l1geth/genesis.json
+var l1GenesisJSON []byte
+
+type l1GenesisAccount struct {
+	Code    string            `json:"code,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+	Balance string            `json:"balance"`
+}
+
+type l1Genesis struct {
+	Config struct {
+		ChainID uint64 `json:"chainId"`
+	} `json:"config"`
+	Alloc map[string]l1GenesisAccount `json:"alloc"`
+}
+
+func parseL1Genesis() (*l1Genesis, error) {
+	var genesis l1Genesis
+	if err := json.Unmarshal(l1GenesisJSON, &genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse l1 genesis: %w", err)
+	}
+	return &genesis, nil
+}
+
+// runContainer runs the container in parallel and waits for the keyword in its logs, it returns the container id,
+// empty if the container did not start in time.
+func runContainer(c *cmd.Cmd, name, keyword string, timeout time.Duration) string {
+	okCh := make(chan struct{}, 1)
+	c.RegistFunc(keyword, func(buf string) {
+		if strings.Contains(buf, keyword) {
+			select {
+			case okCh <- struct{}{}:
+			default:
+				return
+			}
+		}
+	})
+	defer c.UnRegistFunc(keyword)
+	// Start cmd in parallel.
+	c.RunCmd(true)
+
+	var id string
+	select {
+	case <-okCh:
+		utils.TryTimes(20, func() bool {
+			id = GetContainerID(name)
+			return id != ""
+		})
+	case err := <-c.ErrChan:
+		if err != nil {
+			fmt.Printf("failed to start %s, err: %v\n", name, err)
+		}
+	case <-time.After(timeout):
+	}
+	return id
+}
+
+// removeContainer stops and removes the container.
+func removeContainer(name, id string) error {
+	ctx := context.Background()
+	// check if container is running, stop the running container.
+	if runningID := GetContainerID(name); runningID != "" {
+		timeoutSec := 3
+		timeout := container.StopOptions{
+			Timeout: &timeoutSec,
+		}
+		if err := cli.ContainerStop(ctx, runningID, timeout); err != nil {
+			return err
+		}
+		id = runningID
+	}
+	if id == "" {
+		return nil
+	}
+	// remove the stopped container.
+	return cli.ContainerRemove(ctx, id, types.ContainerRemoveOptions{})
+}
+
+// waitChainID tries 10 times to get the chain ID of the node.
+func waitChainID(endpoint string) *big.Int {
+	var chainID *big.Int
+	utils.TryTimes(10, func() bool {
+		client, err := ethclient.Dial(endpoint)
+		if err == nil && client != nil {
+			chainID, err = client.ChainID(context.Background())
+			return err == nil && chainID != nil
+		}
+		return false
+	})
+	return chainID
+}
+
+// callRPC calls the json-rpc method of the node.
+func callRPC(endpoint string, result interface{}, method string, args ...interface{}) error {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return client.CallContext(ctx, result, method, args...)
+}
@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"scroll-tech/common/cmd"
+)
+
+const rethImage = "ghcr.io/paradigmxyz/reth:latest"
+
+// ImgReth the reth image manager, an alternative l1 backend running in dev mode, which mines a block per
+// transaction.
+type ImgReth struct {
+	image string
+	name  string
+	id    string
+
+	genesisFile string
+	httpPort    int
+	wsPort      int
+	chainID     *big.Int
+
+	running bool
+	cmd     *cmd.Cmd
+}
+
+// NewImgReth return reth img instance.
+func NewImgReth(hPort, wPort int) *ImgReth {
+	img := &ImgReth{
+		image:    rethImage,
+		name:     fmt.Sprintf("reth-%d", time.Now().Nanosecond()),
+		httpPort: hPort,
+		wsPort:   wPort,
+	}
+	img.genesisFile = fmt.Sprintf("/tmp/%s-genesis.json", img.name)
+	img.cmd = cmd.NewCmd("docker", img.params()...)
+	return img
+}
+
+// Start run image and check if it is running healthily.
+func (i *ImgReth) Start() error {
+	id := GetContainerID(i.name)
+	if id != "" {
+		return fmt.Errorf("container already exist, name: %s", i.name)
+	}
+	if err := i.writeGenesis(); err != nil {
+		return err
+	}
+	i.id = runContainer(i.cmd, i.name, "RPC WS server started", time.Second*30)
+	i.running = i.id != ""
+	if !i.running {
+		_ = i.Stop()
+		_ = os.Remove(i.genesisFile)
+		return fmt.Errorf("failed to start image: %s", i.image)
+	}
+
+	i.chainID = waitChainID(i.Endpoint())
+	return nil
+}
+
+// writeGenesis writes the l1geth genesis as a post-merge genesis, reth does not support clique.
+func (i *ImgReth) writeGenesis() error {
+	var genesis map[string]interface{}
+	if err := json.Unmarshal(l1GenesisJSON, &genesis); err != nil {
+		return fmt.Errorf("failed to parse l1 genesis: %w", err)
+	}
+	config, ok := genesis["config"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid l1 genesis config")
+	}
+	delete(config, "clique")
+	delete(config, "zktrie")
+	config["terminalTotalDifficulty"] = 0
+	config["terminalTotalDifficultyPassed"] = true
+	config["shanghaiTime"] = 0
+	config["cancunTime"] = 0
+	genesis["difficulty"] = "0x0"
+
+	data, err := json.Marshal(genesis)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(i.genesisFile, data, 0644) //nolint:gosec
+}
+
+// IsRunning returns docker container's running status.
+func (i *ImgReth) IsRunning() bool {
+	return i.running
+}
+
+// Endpoint return the connection endpoint.
+func (i *ImgReth) Endpoint() string {
+	switch true {
+	case i.httpPort != 0:
+		return fmt.Sprintf("http://127.0.0.1:%d", i.httpPort)
+	default:
+		return fmt.Sprintf("ws://127.0.0.1:%d", i.wsPort)
+	}
+}
+
+// ChainID return chainID.
+func (i *ImgReth) ChainID() *big.Int {
+	return i.chainID
+}
+
+func (i *ImgReth) containerName() string {
+	return i.name
+}
+
+// Stop the docker container.
+func (i *ImgReth) Stop() error {
+	if !i.running {
+		return nil
+	}
+	i.running = false
+	_ = os.Remove(i.genesisFile)
+	return removeContainer(i.name, i.id)
+}
+
+func (i *ImgReth) params() []string {
+	cmds := []string{"run", "--rm", "--name", i.name, "-v", i.genesisFile + ":/genesis.json"}
+	if i.httpPort != 0 {
+		cmds = append(cmds, "-p", strconv.Itoa(i.httpPort)+":8545")
+	}
+	if i.wsPort != 0 {
+		cmds = append(cmds, "-p", strconv.Itoa(i.wsPort)+":8546")
+	}
+
+	return append(cmds, i.image,
+		"node", "--dev", "--chain", "/genesis.json", "--datadir", "/data",
+		"--http", "--http.addr", "0.0.0.0", "--http.port", "8545", "--http.api", "all",
+		"--ws", "--ws.addr", "0.0.0.0", "--ws.port", "8546", "--ws.api", "all",
+	)
+}
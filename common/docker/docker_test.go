@@ -6,6 +6,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" //nolint:golint
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
 	"scroll-tech/common/docker"
@@ -52,3 +53,37 @@ func TestL2Geth(t *testing.T) {
 	assert.NoError(t, err)
 	t.Logf("chainId: %s", chainID.String())
 }
+
+func TestL1Anvil(t *testing.T) {
+	base.RunL1Backend(t, docker.L1BackendAnvil)
+
+	client, err := base.L1Client()
+	assert.NoError(t, err)
+
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, base.L1gethImg.ChainID(), chainID)
+
+	// the accounts of the l1geth genesis are funded.
+	balance, err := client.BalanceAt(context.Background(), common.HexToAddress("0x1c5a77d9fa7ef466951b2f01f724bca3a5820b63"), nil)
+	assert.NoError(t, err)
+	assert.Positive(t, balance.Sign())
+
+	id := base.L1Snapshot(t)
+	base.L1Revert(t, id)
+}
+
+func TestL1Reth(t *testing.T) {
+	base.RunL1Backend(t, docker.L1BackendReth)
+
+	client, err := base.L1Client()
+	assert.NoError(t, err)
+
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, base.L1gethImg.ChainID(), chainID)
+
+	balance, err := client.BalanceAt(context.Background(), common.HexToAddress("0x1c5a77d9fa7ef466951b2f01f724bca3a5820b63"), nil)
+	assert.NoError(t, err)
+	assert.Positive(t, balance.Sign())
+}
@@ -36,6 +36,13 @@ type GethImgInstance interface {
 	ChainID() *big.Int
 }
 
+// SnapshotImgInstance based on GethImgInstance and add snapshot and revert of the chain state.
+type SnapshotImgInstance interface {
+	GethImgInstance
+	Snapshot() (string, error)
+	Revert(id string) error
+}
+
 // GetContainerID returns the ID of Container.
 func GetContainerID(name string) string {
 	filter := filters.NewArgs()
@@ -0,0 +1,201 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// checkTimeout bounds the time of a single check, a check not returning in time fails.
+const checkTimeout = 5 * time.Second
+
+const (
+	// StatusOK is the status of a healthy component or service.
+	StatusOK = "ok"
+	// StatusFailing is the status of a failing component or service.
+	StatusFailing = "failing"
+)
+
+// Check reports the health of a component, it returns nil when the component is healthy.
+type Check func(ctx context.Context) error
+
+// ComponentStatus is the result of the check of a component.
+type ComponentStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// HealthStatus is the result of the checks of a probe, the service is failing when any component is failing.
+type HealthStatus struct {
+	Status     string            `json:"status"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// healthChecks holds the checks of the probes, the liveness checks are part of the readiness probe too.
+type healthChecks struct {
+	mu        sync.RWMutex
+	liveness  map[string]Check
+	readiness map[string]Check
+}
+
+func newHealthChecks() *healthChecks {
+	return &healthChecks{
+		liveness:  make(map[string]Check),
+		readiness: make(map[string]Check),
+	}
+}
+
+var defaultHealthChecks = newHealthChecks()
+
+// RegisterLivenessCheck adds a check to the /healthz and /readyz probes, a check registered under the same name is
+// replaced. A liveness check fails only when the process can not recover by itself and should be restarted.
+func RegisterLivenessCheck(name string, check Check) {
+	defaultHealthChecks.register(name, check, true)
+}
+
+// RegisterReadinessCheck adds a check to the /readyz probe, a check registered under the same name is replaced.
+func RegisterReadinessCheck(name string, check Check) {
+	defaultHealthChecks.register(name, check, false)
+}
+
+func (h *healthChecks) register(name string, check Check, liveness bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if liveness {
+		h.liveness[name] = check
+	} else {
+		h.readiness[name] = check
+	}
+}
+
+// run runs the checks concurrently, the components are sorted by name.
+func (h *healthChecks) run(ctx context.Context, readiness bool) *HealthStatus {
+	h.mu.RLock()
+	checks := make(map[string]Check, len(h.liveness)+len(h.readiness))
+	for name, check := range h.liveness {
+		checks[name] = check
+	}
+	if readiness {
+		for name, check := range h.readiness {
+			checks[name] = check
+		}
+	}
+	h.mu.RUnlock()
+
+	status := &HealthStatus{Status: StatusOK, Components: make([]ComponentStatus, 0, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			component := runCheck(ctx, name, check)
+			mu.Lock()
+			status.Components = append(status.Components, component)
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	sort.Slice(status.Components, func(i, j int) bool {
+		return status.Components[i].Name < status.Components[j].Name
+	})
+	for _, component := range status.Components {
+		if component.Status != StatusOK {
+			status.Status = StatusFailing
+		}
+	}
+	return status
+}
+
+func runCheck(ctx context.Context, name string, check Check) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		errCh <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	component := ComponentStatus{Name: name, Status: StatusOK, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		component.Status = StatusFailing
+		component.Error = err.Error()
+	}
+	return component
+}
+
+// DBCheck checks the connectivity of the db.
+func DBCheck(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// BlockNumberReader is a client of a node, e.g. ethclient.Client.
+type BlockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// RPCCheck checks the reachability of the node of the client.
+func RPCCheck(client BlockNumberReader) Check {
+	return func(ctx context.Context) error {
+		if _, err := client.BlockNumber(ctx); err != nil {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+		return nil
+	}
+}
+
+// LagCheck fails when the number of blocks a watcher is behind the chain exceeds maxLag.
+func LagCheck(lag func(ctx context.Context) (uint64, error), maxLag uint64) Check {
+	return func(ctx context.Context) error {
+		blocks, err := lag(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get lag: %w", err)
+		}
+		if blocks > maxLag {
+			return fmt.Errorf("lagging %d blocks behind, max lag: %d", blocks, maxLag)
+		}
+		return nil
+	}
+}
+
+// AgeCheck fails when the time since a component last succeeded exceeds maxAge.
+func AgeCheck(age func(ctx context.Context) (time.Duration, error), maxAge time.Duration) Check {
+	return func(ctx context.Context) error {
+		elapsed, err := age(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get age: %w", err)
+		}
+		if elapsed > maxAge {
+			return fmt.Errorf("last succeeded %s ago, max age: %s", elapsed, maxAge)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	checks := newHealthChecks()
+	checks.register("db", func(context.Context) error { return nil }, true)
+	var rpcErr error
+	checks.register("l2_rpc", func(context.Context) error { return rpcErr }, false)
+	checks.register("l2_watcher_lag", LagCheck(func(context.Context) (uint64, error) { return 10, nil }, 100), false)
+
+	probes := &ProbesController{checks: checks}
+	router := gin.New()
+	router.GET("/healthz", probes.HealthCheck)
+	router.GET("/readyz", probes.Ready)
+
+	probe := func(path string) (int, HealthStatus) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp struct {
+			Data HealthStatus `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Data
+	}
+
+	code, status := probe("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, status.Status)
+	assert.Len(t, status.Components, 3)
+
+	// a failing readiness check fails the readiness probe only.
+	rpcErr = errors.New("connection refused")
+	code, status = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFailing, status.Status)
+	assert.Equal(t, ComponentStatus{Name: "l2_rpc", Status: StatusFailing, Error: "connection refused"}, status.Components[1])
+	code, status = probe("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, status.Components, 1)
+
+	// a check is replaced by name.
+	checks.register("l2_watcher_lag", LagCheck(func(context.Context) (uint64, error) { return 101, nil }, 100), false)
+	rpcErr = nil
+	_, status = probe("/readyz")
+	assert.Equal(t, StatusFailing, status.Status)
+	assert.Equal(t, "lagging 101 blocks behind, max lag: 100", status.Components[2].Error)
+
+	checks.register("l2_watcher_lag", AgeCheck(func(context.Context) (time.Duration, error) { return 2 * time.Minute, nil }, time.Minute), false)
+	_, status = probe("/readyz")
+	assert.Equal(t, "last succeeded 2m0s ago, max age: 1m0s", status.Components[2].Error)
+}
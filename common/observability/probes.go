@@ -1,35 +1,55 @@
 package observability
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
-	"scroll-tech/common/database"
 	"scroll-tech/common/types"
 )
 
 // ProbesController probe check controller
 type ProbesController struct {
-	db *gorm.DB
+	checks *healthChecks
 }
 
-// NewProbesController returns an ProbesController instance
+// NewProbesController returns an ProbesController instance, the db connectivity is a liveness check.
 func NewProbesController(db *gorm.DB) *ProbesController {
+	RegisterLivenessCheck("db", DBCheck(db))
 	return &ProbesController{
-		db: db,
+		checks: defaultHealthChecks,
 	}
 }
 
-// HealthCheck the api controller for health check
+// HealthCheck the api controller for health check, it runs the liveness checks
 func (a *ProbesController) HealthCheck(c *gin.Context) {
-	if _, err := database.Ping(a.db); err != nil {
-		types.RenderFatal(c, err)
-		return
-	}
-	types.RenderSuccess(c, nil)
+	renderHealthStatus(c, a.checks.run(c.Request.Context(), false))
 }
 
-// Ready the api controller for ready check
+// Ready the api controller for ready check, it runs the liveness and readiness checks
 func (a *ProbesController) Ready(c *gin.Context) {
-	types.RenderSuccess(c, nil)
+	renderHealthStatus(c, a.checks.run(c.Request.Context(), true))
+}
+
+// renderHealthStatus renders the component statuses, with http status 503 when any component is failing.
+func renderHealthStatus(c *gin.Context, status *HealthStatus) {
+	if status.Status == StatusOK {
+		types.RenderSuccess(c, status)
+		return
+	}
+
+	var failing []string
+	for _, component := range status.Components {
+		if component.Status != StatusOK {
+			failing = append(failing, component.Name)
+		}
+	}
+	c.Set("errcode", types.InternalServerError)
+	c.JSON(http.StatusServiceUnavailable, types.Response{
+		ErrCode: types.InternalServerError,
+		ErrMsg:  fmt.Sprintf("failing components: %v", failing),
+		Data:    status,
+	})
 }
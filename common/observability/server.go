@@ -34,6 +34,9 @@ func Server(c *cli.Context, db *gorm.DB) {
 	})
 
 	probeController := NewProbesController(db)
+	r.GET("/healthz", probeController.HealthCheck)
+	r.GET("/readyz", probeController.Ready)
+	// kept for the probes configured before the kubernetes style paths.
 	r.GET("/health", probeController.HealthCheck)
 	r.GET("/ready", probeController.Ready)
 
@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+
+	"scroll-tech/common/awsv4"
+)
+
+const awssmService = "secretsmanager"
+
+// readAWSSecretsManager reads the AWS Secrets Manager secret by name or arn, or the key of its json value if key is
+// not empty. The credentials and the region come from the default credential chain of the AWS SDK: the environment
+// variables, the shared config files, the web identity token of IRSA, the ECS task role and the EC2 instance profile.
+func readAWSSecretsManager(ctx context.Context, secretID, key string) (string, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load the aws config: %w", err)
+	}
+	if cfg.Credentials == nil {
+		return "", errors.New("no aws credentials configured")
+	}
+	awsCreds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve the aws credentials: %w", err)
+	}
+	creds := awsv4.Credentials{
+		AccessKeyID:     awsCreds.AccessKeyID,
+		SecretAccessKey: awsCreds.SecretAccessKey,
+		SessionToken:    awsCreds.SessionToken,
+	}
+	region := awsRegion(secretID, cfg.Region)
+	if region == "" {
+		return "", errors.New("AWS_REGION must be set")
+	}
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awssmService, region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsv4.SignRequest(req, body, creds, region, awssmService, time.Now())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets manager responded with status %d", resp.StatusCode)
+	}
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err = json.Unmarshal(respBody, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode the secrets manager response: %w", err)
+	}
+	if key == "" {
+		return secret.SecretString, nil
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal([]byte(secret.SecretString), &fields); err != nil {
+		return "", errors.New("the secret is not a json object")
+	}
+	return stringField(fields, key)
+}
+
+// awsRegion returns the region of the secret arn, the configured region for a secret name.
+func awsRegion(secretID, configured string) string {
+	// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+	if parts := strings.Split(secretID, ":"); len(parts) > 3 && parts[0] == "arn" {
+		return parts[3]
+	}
+	if configured != "" {
+		return configured
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
@@ -0,0 +1,109 @@
+// Package secrets resolves the secrets referenced in the config files, so that the private keys, dsns and jwt secrets
+// need not be stored in plaintext.
+//
+// A reference is a uri of the secret store holding the secret, any other value is a plaintext secret:
+//
+//	vault://<path>#<field>      the field of the Vault secret read at /v1/<path>, e.g. vault://secret/data/rollup#commit_key
+//	awssm://<secret-id>[#<key>] the AWS Secrets Manager secret, or the key of its json value, e.g. awssm://rollup/db#dsn
+//
+// The stores are configured by their usual environment variables: VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE for Vault.
+// AWS Secrets Manager is reached with the default credential chain of the AWS SDK, so the static keys, the shared
+// config profiles, IRSA, the ECS task role and the EC2 instance profile all work, and AWS_REGION selects the region.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+const (
+	vaultScheme = "vault://"
+	awssmScheme = "awssm://"
+
+	// resolveTimeout bounds the request of a secret to its store.
+	resolveTimeout = 10 * time.Second
+)
+
+var httpClient = &http.Client{Timeout: resolveTimeout}
+
+// IsReference returns whether the value is a reference to a secret store.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, vaultScheme) || strings.HasPrefix(value, awssmScheme)
+}
+
+// Resolve returns the secret referenced by the value, the value itself if it is not a reference. The error does not
+// include the secret.
+func Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	ref, field, _ := strings.Cut(value, "#")
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	var (
+		secret string
+		err    error
+	)
+	switch {
+	case strings.HasPrefix(ref, vaultScheme):
+		secret, err = readVault(ctx, strings.TrimPrefix(ref, vaultScheme), field)
+	default:
+		secret, err = readAWSSecretsManager(ctx, strings.TrimPrefix(ref, awssmScheme), field)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s: %w", value, err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("failed to resolve secret %s: empty secret", value)
+	}
+	return secret, nil
+}
+
+// Value is a secret resolved again once its refresh period elapsed, e.g. the password of a dsn rotated by the store.
+type Value struct {
+	ref     string
+	refresh time.Duration
+
+	mu         sync.Mutex
+	secret     string
+	resolvedAt time.Time
+}
+
+// NewValue resolves the referenced secret, refresh is the period of resolving it again, 0 never resolves it again.
+func NewValue(ctx context.Context, ref string, refresh time.Duration) (*Value, error) {
+	secret, err := Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &Value{ref: ref, refresh: refresh, secret: secret, resolvedAt: time.Now()}, nil
+}
+
+// Get returns the secret, resolved again if its refresh period elapsed. The previous secret is returned if it fails
+// to be resolved again, it is resolved again on the next call.
+func (v *Value) Get(ctx context.Context) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.refresh == 0 || time.Since(v.resolvedAt) < v.refresh {
+		return v.secret
+	}
+	secret, err := Resolve(ctx, v.ref)
+	if err != nil {
+		log.Warn("failed to refresh secret, keeping the previous one", "err", err)
+		return v.secret
+	}
+	if secret != v.secret {
+		log.Info("secret rotated", "ref", v.ref)
+	}
+	v.secret = secret
+	v.resolvedAt = time.Now()
+	return v.secret
+}
@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// readVault reads the field of the Vault secret at the api path, e.g. secret/data/rollup for the rollup secret of
+// the kv v2 engine mounted at secret.
+func readVault(ctx context.Context, path, field string) (string, error) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", errors.New("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+	if field == "" {
+		return "", errors.New("missing the field of the vault secret")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded with status %d", resp.StatusCode)
+	}
+
+	// the kv v2 engine nests the fields of the secret in data.data, along with data.metadata.
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err = json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode the vault response: %w", err)
+	}
+	fields := secret.Data
+	if nested, ok := fields["data"]; ok {
+		if _, ok = fields["metadata"]; ok {
+			fields = nil
+			if err = json.Unmarshal(nested, &fields); err != nil {
+				return "", fmt.Errorf("failed to decode the vault secret: %w", err)
+			}
+		}
+	}
+	return stringField(fields, field)
+}
+
+// stringField returns the string value of the field.
+func stringField(fields map[string]json.RawMessage, field string) (string, error) {
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("missing field %s", field)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("field %s is not a string", field)
+	}
+	return value, nil
+}
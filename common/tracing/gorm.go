@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin records a span per query of the db, child of the span of the query context.
+type GormPlugin struct{}
+
+// Name implements gorm.Plugin.
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize implements gorm.Plugin.
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("tracing:before_create", before("db.create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", after),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", before("db.query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", after),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", before("db.update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", before("db.delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", after),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", before("db.row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", after),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", before("db.raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func before(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !Enabled() || db.Statement.Context == nil {
+			return
+		}
+		ctx, span := Start(db.Statement.Context, name, "db.table", db.Statement.Table)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes("db.table", db.Statement.Table, "db.statement", db.Statement.SQL.String(), "db.rows_affected", db.Statement.RowsAffected)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+	}
+	span.End()
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// Transport records a span per request and propagates the trace of the request context in its headers.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !Enabled() {
+		return base.RoundTrip(req)
+	}
+
+	// the body of a json-rpc request is small, it is read to name the span by the method.
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	ctx, span := Start(req.Context(), "rpc "+rpcMethod(body), "http.url", req.URL.Redacted())
+	defer span.End()
+	req = req.Clone(ctx)
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	Inject(ctx, req.Header)
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes("http.status_code", resp.StatusCode)
+	return resp, nil
+}
+
+// rpcMethod returns the json-rpc method of the request body, batch for a batch request.
+func rpcMethod(body []byte) string {
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		return "batch"
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil || msg.Method == "" {
+		return "call"
+	}
+	return msg.Method
+}
+
+// DialRPC dials the node of the endpoint, the calls over http are traced with their context.
+func DialRPC(ctx context.Context, endpoint string) (*rpc.Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return rpc.DialContext(ctx, endpoint)
+	}
+	return rpc.DialHTTPWithClient(endpoint, &http.Client{Transport: &Transport{}})
+}
+
+// Middleware records a span per request, child of the trace of the request headers.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Enabled() {
+			c.Next()
+			return
+		}
+		ctx := Extract(c.Request.Context(), c.Request.Header)
+		ctx, span := Start(ctx, c.Request.Method+" "+c.FullPath(), "http.client_ip", c.ClientIP())
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		span.SetAttributes("http.status_code", c.Writer.Status())
+		if last := c.Errors.Last(); last != nil {
+			span.RecordError(last)
+		}
+	}
+}
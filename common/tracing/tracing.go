@@ -0,0 +1,192 @@
+// Package tracing records the spans of the main flows, from the block ingestion to the finalization, and propagates
+// them with a correlation id through the ORM writes and the RPC calls. The spans are dropped unless Init enables an
+// exporter, which requires a build with the otel tag.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config is the tracing config, the spans are exported to an OTLP collector.
+type Config struct {
+	// Whether to export the spans.
+	Enabled bool `json:"enabled"`
+	// The OTLP collector endpoint, host:port.
+	Endpoint string `json:"endpoint"`
+	// The OTLP protocol, grpc or http, grpc when empty.
+	Protocol string `json:"protocol,omitempty"`
+	// Whether to connect to the collector without TLS.
+	Insecure bool `json:"insecure,omitempty"`
+	// The headers sent to the collector, e.g. its auth token.
+	Headers map[string]string `json:"headers,omitempty"`
+	// The ratio of the traces sampled, all traces when 0.
+	SampleRatio float64 `json:"sample_ratio,omitempty"`
+}
+
+func (c *Config) validate() error {
+	if c.Endpoint == "" {
+		return errors.New("tracing endpoint is required")
+	}
+	if c.Protocol != "" && c.Protocol != "grpc" && c.Protocol != "http" {
+		return fmt.Errorf("invalid tracing protocol: %s, must be grpc or http", c.Protocol)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("invalid tracing sample_ratio: %v, must be in [0, 1]", c.SampleRatio)
+	}
+	return nil
+}
+
+// Span is a span of a trace, the attributes are key value pairs as in the log package.
+type Span interface {
+	SetAttributes(kv ...interface{})
+	RecordError(err error)
+	End()
+}
+
+// backend records and propagates the spans.
+type backend interface {
+	start(ctx context.Context, name string, kv []interface{}) (context.Context, Span)
+	spanFromContext(ctx context.Context) Span
+	withCorrelationID(ctx context.Context, id string) context.Context
+	inject(ctx context.Context, header http.Header)
+	extract(ctx context.Context, header http.Header) context.Context
+}
+
+// newExporter creates the backend exporting to the collector of the config, it is registered by the otel build.
+var newExporter func(cfg *Config, service string) (backend, func(context.Context) error, error)
+
+var (
+	mu      sync.RWMutex
+	current backend = noopBackend{}
+	enabled bool
+)
+
+func getBackend() backend {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+func setBackend(b backend, isEnabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	current, enabled = b, isEnabled
+}
+
+// Init starts exporting the spans of the service if enabled by the config, nil disables tracing. It returns the
+// shutdown flushing the spans left.
+func Init(cfg *Config, service string) (func(context.Context) error, error) {
+	if cfg == nil || !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if newExporter == nil {
+		return nil, errors.New("tracing is not supported by this build, rebuild with the otel tag")
+	}
+	b, shutdown, err := newExporter(cfg, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init tracing: %w", err)
+	}
+	setBackend(b, true)
+	return func(ctx context.Context) error {
+		setBackend(noopBackend{}, false)
+		return shutdown(ctx)
+	}, nil
+}
+
+// Enabled returns whether the spans are exported.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled
+}
+
+// Start starts a span, child of the span of ctx, tagged with the correlation id of ctx.
+func Start(ctx context.Context, name string, kv ...interface{}) (context.Context, Span) {
+	ctx = parentContext(ctx)
+	if id := CorrelationID(ctx); id != "" {
+		kv = append(kv, "correlation_id", id)
+	}
+	return getBackend().start(ctx, name, kv)
+}
+
+// SpanFromContext returns the span of ctx, a no-op span if none.
+func SpanFromContext(ctx context.Context) Span {
+	return getBackend().spanFromContext(parentContext(ctx))
+}
+
+// WithCorrelationID sets the correlation id of the flow, e.g. the hash of the batch, tagging the spans started from ctx
+// and propagated along with the trace.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(parentContext(ctx), correlationIDKey{}, id)
+	return getBackend().withCorrelationID(ctx, id)
+}
+
+// CorrelationID returns the correlation id of ctx, empty if none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := parentContext(ctx).Value(correlationIDKey{}).(string)
+	return id
+}
+
+// Link returns ctx carrying the span and the correlation id of from, e.g. to trace the calls of a long-lived context on
+// behalf of a request.
+func Link(ctx, from context.Context) context.Context {
+	if from == nil {
+		return ctx
+	}
+	header := make(http.Header)
+	Inject(from, header)
+	ctx = Extract(ctx, header)
+	if id := CorrelationID(from); id != "" {
+		ctx = context.WithValue(ctx, correlationIDKey{}, id)
+	}
+	return ctx
+}
+
+// Inject writes the trace of ctx into the headers of an outgoing request.
+func Inject(ctx context.Context, header http.Header) {
+	getBackend().inject(parentContext(ctx), header)
+}
+
+// Extract returns ctx carrying the trace of the headers of an incoming request.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return getBackend().extract(ctx, header)
+}
+
+type correlationIDKey struct{}
+
+// parentContext returns the request context of a gin context, which keeps the trace set by Middleware.
+func parentContext(ctx context.Context) context.Context {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		return c.Request.Context()
+	}
+	return ctx
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...interface{}) {}
+func (noopSpan) RecordError(error)            {}
+func (noopSpan) End()                         {}
+
+type noopBackend struct{}
+
+func (noopBackend) start(ctx context.Context, _ string, _ []interface{}) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+func (noopBackend) spanFromContext(context.Context) Span { return noopSpan{} }
+func (noopBackend) withCorrelationID(ctx context.Context, _ string) context.Context {
+	return ctx
+}
+func (noopBackend) inject(context.Context, http.Header) {}
+func (noopBackend) extract(ctx context.Context, _ http.Header) context.Context {
+	return ctx
+}
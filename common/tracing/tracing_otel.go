@@ -0,0 +1,149 @@
+//go:build otel
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"scroll-tech/common/version"
+)
+
+func init() {
+	newExporter = newOTLPBackend
+}
+
+type otelBackend struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func newOTLPBackend(cfg *Config, service string) (backend, func(context.Context) error, error) {
+	var client otlptrace.Client
+	if cfg.Protocol == "http" {
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithHeaders(cfg.Headers)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		client = otlptracehttp.NewClient(opts...)
+	} else {
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint), otlptracegrpc.WithHeaders(cfg.Headers)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		client = otlptracegrpc.NewClient(opts...)
+	}
+	exporter, err := otlptrace.New(context.Background(), client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 {
+		sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", service),
+			attribute.String("service.version", version.Version),
+		)),
+	)
+	b := &otelBackend{
+		tracer:     provider.Tracer("scroll-tech/" + service),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	return b, provider.Shutdown, nil
+}
+
+func (b *otelBackend) start(ctx context.Context, name string, kv []interface{}) (context.Context, Span) {
+	ctx, span := b.tracer.Start(ctx, name, trace.WithAttributes(attributes(kv)...))
+	return ctx, otelSpan{span}
+}
+
+func (b *otelBackend) spanFromContext(ctx context.Context) Span {
+	return otelSpan{trace.SpanFromContext(ctx)}
+}
+
+func (b *otelBackend) withCorrelationID(ctx context.Context, id string) context.Context {
+	member, err := baggage.NewMember("correlation_id", id)
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("correlation_id", id))
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func (b *otelBackend) inject(ctx context.Context, header http.Header) {
+	b.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func (b *otelBackend) extract(ctx context.Context, header http.Header) context.Context {
+	ctx = b.propagator.Extract(ctx, propagation.HeaderCarrier(header))
+	if id := baggage.FromContext(ctx).Member("correlation_id").Value(); id != "" {
+		ctx = context.WithValue(ctx, correlationIDKey{}, id)
+	}
+	return ctx
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(kv ...interface{}) {
+	s.span.SetAttributes(attributes(kv)...)
+}
+
+func (s otelSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s otelSpan) End() {
+	s.span.End()
+}
+
+// attributes converts the key value pairs, the values not of a basic type are formatted.
+func attributes(kv []interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		switch v := kv[i+1].(type) {
+		case string:
+			attrs = append(attrs, attribute.String(key, v))
+		case bool:
+			attrs = append(attrs, attribute.Bool(key, v))
+		case int:
+			attrs = append(attrs, attribute.Int(key, v))
+		case int64:
+			attrs = append(attrs, attribute.Int64(key, v))
+		case uint64:
+			attrs = append(attrs, attribute.Int64(key, int64(v)))
+		case float64:
+			attrs = append(attrs, attribute.Float64(key, v))
+		default:
+			attrs = append(attrs, attribute.String(key, fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
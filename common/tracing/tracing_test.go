@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(kv ...interface{}) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		s.attrs[kv[i].(string)] = kv[i+1]
+	}
+}
+func (s *testSpan) RecordError(err error) {
+	if err != nil {
+		s.err = err
+	}
+}
+func (s *testSpan) End() { s.ended = true }
+
+type spanKey struct{}
+
+// testBackend records the spans, the trace is propagated as the name of the parent span.
+type testBackend struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (b *testBackend) start(ctx context.Context, name string, kv []interface{}) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: make(map[string]interface{})}
+	span.SetAttributes(kv...)
+	if parent, ok := ctx.Value(spanKey{}).(*testSpan); ok {
+		span.attrs["parent"] = parent.name
+	}
+	b.mu.Lock()
+	b.spans = append(b.spans, span)
+	b.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (b *testBackend) spanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(*testSpan); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+func (b *testBackend) withCorrelationID(ctx context.Context, _ string) context.Context {
+	return ctx
+}
+
+func (b *testBackend) inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(spanKey{}).(*testSpan); ok {
+		header.Set("X-Test-Parent", span.name)
+	}
+}
+
+func (b *testBackend) extract(ctx context.Context, header http.Header) context.Context {
+	if name := header.Get("X-Test-Parent"); name != "" {
+		return context.WithValue(ctx, spanKey{}, &testSpan{name: name})
+	}
+	return ctx
+}
+
+func (b *testBackend) span(name string) *testSpan {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, span := range b.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func useTestBackend(t *testing.T) *testBackend {
+	b := &testBackend{}
+	setBackend(b, true)
+	t.Cleanup(func() { setBackend(noopBackend{}, false) })
+	return b
+}
+
+func TestInit(t *testing.T) {
+	shutdown, err := Init(nil, "test")
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.False(t, Enabled())
+
+	_, err = Init(&Config{Enabled: true}, "test")
+	assert.ErrorContains(t, err, "endpoint is required")
+	_, err = Init(&Config{Enabled: true, Endpoint: "localhost:4317", Protocol: "udp"}, "test")
+	assert.ErrorContains(t, err, "invalid tracing protocol")
+	_, err = Init(&Config{Enabled: true, Endpoint: "localhost:4317", SampleRatio: 2}, "test")
+	assert.ErrorContains(t, err, "invalid tracing sample_ratio")
+	if newExporter == nil {
+		_, err = Init(&Config{Enabled: true, Endpoint: "localhost:4317"}, "test")
+		assert.ErrorContains(t, err, "otel tag")
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	b := useTestBackend(t)
+
+	ctx := WithCorrelationID(context.Background(), "batch:0x01")
+	assert.Equal(t, "batch:0x01", CorrelationID(ctx))
+	assert.Equal(t, "", CorrelationID(context.Background()))
+
+	ctx, span := Start(ctx, "commit", "index", 1)
+	span.End()
+	assert.Equal(t, "batch:0x01", b.span("commit").attrs["correlation_id"])
+	assert.Equal(t, 1, b.span("commit").attrs["index"])
+
+	// the linked context keeps its cancellation but carries the trace.
+	base, cancel := context.WithCancel(context.Background())
+	linked := Link(base, ctx)
+	assert.Equal(t, "batch:0x01", CorrelationID(linked))
+	_, span = Start(linked, "send")
+	span.End()
+	assert.Equal(t, "commit", b.span("send").attrs["parent"])
+	cancel()
+	assert.Error(t, linked.Err())
+	assert.Equal(t, base, Link(base, nil))
+}
+
+func TestMiddlewareAndTransport(t *testing.T) {
+	b := useTestBackend(t)
+	gin.SetMode(gin.TestMode)
+
+	var received http.Header
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer node.Close()
+	client, err := DialRPC(context.Background(), node.URL)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	router := gin.New()
+	router.Use(Middleware())
+	router.POST("/submit_proof", func(c *gin.Context) {
+		var chainID string
+		if err := client.CallContext(c, &chainID, "eth_chainId"); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/submit_proof", strings.NewReader("{}"))
+	req.Header.Set("X-Test-Parent", "prover")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	server := b.span("POST /submit_proof")
+	assert.NotNil(t, server)
+	assert.True(t, server.ended)
+	assert.Equal(t, "prover", server.attrs["parent"])
+	assert.Equal(t, http.StatusOK, server.attrs["http.status_code"])
+
+	call := b.span("rpc eth_chainId")
+	assert.NotNil(t, call)
+	assert.True(t, call.ended)
+	assert.Equal(t, "POST /submit_proof", call.attrs["parent"])
+	assert.Equal(t, "rpc eth_chainId", received.Get("X-Test-Parent"))
+}
@@ -2,6 +2,7 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -231,6 +232,33 @@ func (s RollupStatus) String() string {
 	}
 }
 
+// DAVerificationStatus batch da_verification_status (pending, verified, mismatch)
+type DAVerificationStatus int
+
+const (
+	// DAVerificationUndefined : undefined da verification status
+	DAVerificationUndefined DAVerificationStatus = iota
+	// DAVerificationPending : the data committed to layer1 is not re-derived yet
+	DAVerificationPending
+	// DAVerificationVerified : the data committed to layer1 matches the batch
+	DAVerificationVerified
+	// DAVerificationMismatch : the data committed to layer1 does not match the batch
+	DAVerificationMismatch
+)
+
+func (s DAVerificationStatus) String() string {
+	switch s {
+	case DAVerificationPending:
+		return "DAVerificationPending"
+	case DAVerificationVerified:
+		return "DAVerificationVerified"
+	case DAVerificationMismatch:
+		return "DAVerificationMismatch"
+	default:
+		return fmt.Sprintf("Undefined DAVerificationStatus (%d)", int32(s))
+	}
+}
+
 // SenderType defines the various types of senders sending the transactions.
 type SenderType int
 
@@ -245,6 +273,8 @@ const (
 	SenderTypeL1GasOracle
 	// SenderTypeL2GasOracle indicates a sender from L1 responsible for updating L2 gas prices.
 	SenderTypeL2GasOracle
+	// SenderTypeReplayMessage indicates a sender from L2 responsible for replaying L1 messages skipped by the sequencer.
+	SenderTypeReplayMessage
 )
 
 // String returns a string representation of the SenderType.
@@ -258,6 +288,8 @@ func (t SenderType) String() string {
 		return "SenderTypeL1GasOracle"
 	case SenderTypeL2GasOracle:
 		return "SenderTypeL2GasOracle"
+	case SenderTypeReplayMessage:
+		return "SenderTypeReplayMessage"
 	default:
 		return fmt.Sprintf("Unknown SenderType (%d)", int32(t))
 	}
@@ -277,6 +309,10 @@ const (
 	TxStatusConfirmed
 	// TxStatusConfirmedFailed indicates that the transaction has failed during processing.
 	TxStatusConfirmedFailed
+	// TxStatusExpired indicates that the transaction was not confirmed before its deadline and has been cancelled.
+	TxStatusExpired
+	// TxStatusStuck indicates that the transaction reached the max number of replacements and is no longer escalated until an operator resumes it.
+	TxStatusStuck
 )
 
 func (s TxStatus) String() string {
@@ -289,7 +325,65 @@ func (s TxStatus) String() string {
 		return "TxStatusConfirmed"
 	case TxStatusConfirmedFailed:
 		return "TxStatusConfirmedFailed"
+	case TxStatusExpired:
+		return "TxStatusExpired"
+	case TxStatusStuck:
+		return "TxStatusStuck"
 	default:
 		return fmt.Sprintf("Unknown TxStatus (%d)", int32(s))
 	}
 }
+
+// SkippedL1MessageStatus represents the replay status of an L1 message skipped by the L2 sequencer.
+type SkippedL1MessageStatus int
+
+const (
+	// SkippedL1MessageStatusUndefined : undefined skipped L1 message status
+	SkippedL1MessageStatusUndefined SkippedL1MessageStatus = iota
+	// SkippedL1MessageStatusSkipped : the message was skipped and is not replayed yet
+	SkippedL1MessageStatusSkipped
+	// SkippedL1MessageStatusReplaying : the replay transaction is submitted to layer1
+	SkippedL1MessageStatusReplaying
+	// SkippedL1MessageStatusReplayed : the replay transaction is confirmed in layer1
+	SkippedL1MessageStatusReplayed
+	// SkippedL1MessageStatusReplayFailed : the replay transaction is confirmed but failed
+	SkippedL1MessageStatusReplayFailed
+)
+
+func (s SkippedL1MessageStatus) String() string {
+	switch s {
+	case SkippedL1MessageStatusSkipped:
+		return "SkippedL1MessageStatusSkipped"
+	case SkippedL1MessageStatusReplaying:
+		return "SkippedL1MessageStatusReplaying"
+	case SkippedL1MessageStatusReplayed:
+		return "SkippedL1MessageStatusReplayed"
+	case SkippedL1MessageStatusReplayFailed:
+		return "SkippedL1MessageStatusReplayFailed"
+	default:
+		return fmt.Sprintf("Undefined SkippedL1MessageStatus (%d)", int32(s))
+	}
+}
+
+// ErrStatusConflict is the error matched by a StatusConflictError.
+var ErrStatusConflict = errors.New("status transition conflict")
+
+// StatusConflictError is returned by the compare-and-swap status updates of the chunks and batches when the row
+// changed since it was read, e.g. when another process moved its status or reverted it, nothing is updated then.
+type StatusConflictError struct {
+	// Table is the table of the row, e.g. batch.
+	Table string
+	// Hash is the hash of the row.
+	Hash string
+	// Version is the version the row was expected to have.
+	Version int64
+}
+
+func (e *StatusConflictError) Error() string {
+	return fmt.Sprintf("%v: %v %v is no longer at version %d", ErrStatusConflict, e.Table, e.Hash, e.Version)
+}
+
+// Is reports whether target is ErrStatusConflict, for errors.Is.
+func (e *StatusConflictError) Is(target error) bool {
+	return target == ErrStatusConflict
+}
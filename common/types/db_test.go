@@ -1,6 +1,8 @@
 package types
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -177,6 +179,11 @@ func TestSenderType(t *testing.T) {
 			SenderTypeL2GasOracle,
 			"SenderTypeL2GasOracle",
 		},
+		{
+			"SenderTypeReplayMessage",
+			SenderTypeReplayMessage,
+			"SenderTypeReplayMessage",
+		},
 		{
 			"Invalid Value",
 			SenderType(999),
@@ -222,6 +229,16 @@ func TestTxStatus(t *testing.T) {
 			TxStatusConfirmedFailed,
 			"TxStatusConfirmedFailed",
 		},
+		{
+			"TxStatusExpired",
+			TxStatusExpired,
+			"TxStatusExpired",
+		},
+		{
+			"TxStatusStuck",
+			TxStatusStuck,
+			"TxStatusStuck",
+		},
 		{
 			"Invalid Value",
 			TxStatus(999),
@@ -325,3 +342,13 @@ func TestProverTaskFailureType(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusConflictError(t *testing.T) {
+	var err error = &StatusConflictError{Table: "batch", Hash: "0x01", Version: 2}
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), ErrStatusConflict))
+	assert.Equal(t, "status transition conflict: batch 0x01 is no longer at version 2", err.Error())
+
+	var conflictErr *StatusConflictError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &conflictErr))
+	assert.Equal(t, int64(2), conflictErr.Version)
+}
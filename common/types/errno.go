@@ -10,6 +10,8 @@ const (
 	ErrJWTCommonErr = 50000
 	// ErrJWTTokenExpired jwt token expired
 	ErrJWTTokenExpired = 50001
+	// ErrJWTTokenRevoked jwt token revoked, the prover has to login again
+	ErrJWTTokenRevoked = 50002
 
 	// ErrProverStatsAPIParameterInvalidNo is invalid params
 	ErrProverStatsAPIParameterInvalidNo = 10001
@@ -17,6 +19,8 @@ const (
 	ErrProverStatsAPIProverTaskFailure = 10002
 	// ErrProverStatsAPIProverTotalRewardFailure is getting total rewards error
 	ErrProverStatsAPIProverTotalRewardFailure = 10003
+	// ErrProverStatsAPIGetProverStatsFailure is getting prover statistics error
+	ErrProverStatsAPIGetProverStatsFailure = 10004
 
 	// ErrCoordinatorParameterInvalidNo is invalid params
 	ErrCoordinatorParameterInvalidNo = 20001
@@ -26,4 +30,41 @@ const (
 	ErrCoordinatorHandleZkProofFailure = 20003
 	// ErrCoordinatorEmptyProofData get empty proof data
 	ErrCoordinatorEmptyProofData = 20004
+	// ErrCoordinatorGetTaskEscalationsFailure is getting escalated tasks error
+	ErrCoordinatorGetTaskEscalationsFailure = 20005
+	// ErrCoordinatorInvalidProof is a submitted proof failing verification
+	ErrCoordinatorInvalidProof = 20006
+	// ErrCoordinatorRateLimited is a request rejected by the rate limits or quotas
+	ErrCoordinatorRateLimited = 20007
+	// ErrCoordinatorGetPipelineStatusFailure is getting the proving pipeline status error
+	ErrCoordinatorGetPipelineStatusFailure = 20008
+	// ErrCoordinatorHeartbeatFailure is recording a prover heartbeat error
+	ErrCoordinatorHeartbeatFailure = 20009
+	// ErrCoordinatorGetProverHeartbeatsFailure is getting the prover heartbeats error
+	ErrCoordinatorGetProverHeartbeatsFailure = 20010
+	// ErrCoordinatorGetProverAllowlistFailure is getting the prover allowlist error
+	ErrCoordinatorGetProverAllowlistFailure = 20011
+	// ErrCoordinatorUpdateProverAllowlistFailure is adding or removing a prover of the allowlist error
+	ErrCoordinatorUpdateProverAllowlistFailure = 20012
+	// ErrCoordinatorRevokeTokensFailure is revoking the tokens of a prover error
+	ErrCoordinatorRevokeTokensFailure = 20013
+
+	// ErrAdminAPIUnauthorized is a missing or wrong admin api token
+	ErrAdminAPIUnauthorized = 30001
+	// ErrAdminAPIParameterInvalidNo is invalid params
+	ErrAdminAPIParameterInvalidNo = 30002
+	// ErrAdminAPISenderNotFound is an unknown sender
+	ErrAdminAPISenderNotFound = 30003
+	// ErrAdminAPIGetTransactionsFailure is getting pending transactions error
+	ErrAdminAPIGetTransactionsFailure = 30004
+	// ErrAdminAPISetFeeEstimatorFailure is replacing the fee estimator error
+	ErrAdminAPISetFeeEstimatorFailure = 30005
+	// ErrAdminAPIResumeStuckFailure is resuming a stuck transaction error
+	ErrAdminAPIResumeStuckFailure = 30006
+	// ErrAdminAPIGetSkippedL1MessagesFailure is getting skipped l1 messages error
+	ErrAdminAPIGetSkippedL1MessagesFailure = 30007
+	// ErrAdminAPISetLogLevelsFailure is changing the log levels error
+	ErrAdminAPISetLogLevelsFailure = 30008
+	// ErrAdminAPIApproveL1MessageSkipsFailure is approving a held l1 message skip proposal error
+	ErrAdminAPIApproveL1MessageSkipsFailure = 30009
 )
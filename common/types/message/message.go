@@ -76,6 +76,8 @@ type Identity struct {
 	ProverVersion string `json:"prover_version"`
 	// Challenge unique challenge generated by manager
 	Challenge string `json:"challenge"`
+	// CircuitVersion the circuit version the prover proves with, optional so the identities without it hash alike
+	CircuitVersion string `json:"circuit_version,omitempty" rlp:"optional"`
 }
 
 // GenerateToken generates token
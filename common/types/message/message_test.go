@@ -56,6 +56,12 @@ func TestIdentityHash(t *testing.T) {
 
 	expectedHash := "83f5e0ad023e9c1de639ab07b9b4cb972ec9dbbd2524794c533a420a5b137721"
 	assert.Equal(t, expectedHash, hex.EncodeToString(hash))
+
+	// the circuit version is signed when advertised.
+	identity.CircuitVersion = "v0.12.0"
+	hash, err = identity.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, expectedHash, hex.EncodeToString(hash))
 }
 
 func TestProofMessageSignVerifyPublicKey(t *testing.T) {
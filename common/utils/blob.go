@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"math/big"
+)
+
+var (
+	minBlobGasPrice            = big.NewInt(1)
+	blobGasPriceUpdateFraction = big.NewInt(3338477)
+)
+
+// CalcBlobFee calculates the blob base fee of a block from its excess blob gas, as specified by EIP-4844.
+func CalcBlobFee(excessBlobGas uint64) *big.Int {
+	return fakeExponential(minBlobGasPrice, new(big.Int).SetUint64(excessBlobGas), blobGasPriceUpdateFraction)
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using Taylor expansion.
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	var (
+		output = new(big.Int)
+		accum  = new(big.Int).Mul(factor, denominator)
+	)
+	for i := 1; accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(int64(i)))
+	}
+	return output.Div(output, denominator)
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcBlobFee(t *testing.T) {
+	tests := []struct {
+		excessBlobGas uint64
+		blobFee       int64
+	}{
+		{0, 1},
+		{2314057, 1},
+		{2314058, 2},
+		{10 * 1024 * 1024, 23},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.blobFee, CalcBlobFee(tt.excessBlobGas).Int64(), "excess blob gas: %d", tt.excessBlobGas)
+	}
+}
@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
@@ -11,19 +13,46 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// LogConfig is the log levels of a service, the level of the verbosity flag applies when empty.
+type LogConfig struct {
+	// The log level: crit, error, warn, info, debug or trace.
+	Level string `json:"level,omitempty"`
+	// The log levels overriding Level per module, the module of a record is the package directory of the caller, e.g.
+	// sender, relayer or watcher.
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// levelHandler filters the records by the level of their module, the levels may be changed at runtime.
+type levelHandler struct {
+	handler log.Handler
+	// whether to record the module of the records, for the structured formats.
+	withModule bool
+
+	mu      sync.RWMutex
+	level   log.Lvl
+	modules map[string]log.Lvl
+	// the module of the call sites.
+	sites map[uintptr]string
+}
+
+var rootLogHandler *levelHandler
+
 // LogSetup is for setup logger
 func LogSetup(ctx *cli.Context) error {
 	var ostream log.Handler
+	jsonFormat := ctx.Bool(LogJSONFormat.Name)
 	if logFile := ctx.String(LogFileFlag.Name); len(logFile) > 0 {
 		fp, err := os.OpenFile(filepath.Clean(logFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 		if err != nil {
 			log.Crit("Failed to open log file", "err", err)
 		}
-		if ctx.Bool(LogJSONFormat.Name) {
+		if jsonFormat {
 			ostream = log.StreamHandler(io.Writer(fp), log.JSONFormat())
 		} else {
 			ostream = log.StreamHandler(io.Writer(fp), log.TerminalFormat(true))
 		}
+	} else if jsonFormat {
+		ostream = log.StreamHandler(os.Stderr, log.JSONFormat())
 	} else {
 		output := io.Writer(os.Stderr)
 		usecolor := (isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())) && os.Getenv("TERM") != "dumb"
@@ -34,9 +63,136 @@ func LogSetup(ctx *cli.Context) error {
 	}
 	// show the call file and line number
 	log.PrintOrigins(ctx.Bool(LogDebugFlag.Name))
-	glogger := log.NewGlogHandler(ostream)
 	// Set log level
-	glogger.Verbosity(log.Lvl(ctx.Int(VerbosityFlag.Name)))
-	log.Root().SetHandler(glogger)
+	rootLogHandler = &levelHandler{
+		handler:    ostream,
+		withModule: jsonFormat,
+		level:      log.Lvl(ctx.Int(VerbosityFlag.Name)),
+		modules:    make(map[string]log.Lvl),
+		sites:      make(map[uintptr]string),
+	}
+	log.Root().SetHandler(rootLogHandler)
+	return nil
+}
+
+// ApplyLogConfig sets the levels of the config, a module level set to an empty string falls back to the global level.
+// The levels are left unchanged if any of them is invalid.
+func ApplyLogConfig(cfg *LogConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if rootLogHandler == nil {
+		return fmt.Errorf("logger is not set up")
+	}
+	return rootLogHandler.apply(cfg)
+}
+
+// CurrentLogConfig returns the current log levels, nil if the logger is not set up.
+func CurrentLogConfig() *LogConfig {
+	if rootLogHandler == nil {
+		return nil
+	}
+	return rootLogHandler.config()
+}
+
+func (h *levelHandler) apply(cfg *LogConfig) error {
+	var (
+		level   log.Lvl
+		err     error
+		modules = make(map[string]*log.Lvl, len(cfg.Modules))
+	)
+	if cfg.Level != "" {
+		if level, err = log.LvlFromString(cfg.Level); err != nil {
+			return fmt.Errorf("invalid log level: %w", err)
+		}
+	}
+	for module, moduleLevel := range cfg.Modules {
+		if moduleLevel == "" {
+			modules[module] = nil
+			continue
+		}
+		lvl, err := log.LvlFromString(moduleLevel)
+		if err != nil {
+			return fmt.Errorf("invalid log level of module %s: %w", module, err)
+		}
+		modules[module] = &lvl
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if cfg.Level != "" {
+		h.level = level
+	}
+	for module, lvl := range modules {
+		if lvl == nil {
+			delete(h.modules, module)
+		} else {
+			h.modules[module] = *lvl
+		}
+	}
 	return nil
 }
+
+func (h *levelHandler) config() *LogConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	cfg := &LogConfig{Level: levelName(h.level), Modules: make(map[string]string, len(h.modules))}
+	for module, lvl := range h.modules {
+		cfg.Modules[module] = levelName(lvl)
+	}
+	return cfg
+}
+
+// Log implements log.Handler.
+func (h *levelHandler) Log(r *log.Record) error {
+	module := h.module(r)
+
+	h.mu.RLock()
+	level, ok := h.modules[module]
+	if !ok {
+		level = h.level
+	}
+	h.mu.RUnlock()
+	if r.Lvl > level {
+		return nil
+	}
+
+	if h.withModule && module != "" {
+		r.Ctx = append(r.Ctx, "module", module)
+	}
+	return h.handler.Log(r)
+}
+
+// module returns the package directory of the caller of the record.
+func (h *levelHandler) module(r *log.Record) string {
+	frame := r.Call.Frame()
+	h.mu.RLock()
+	module, ok := h.sites[frame.PC]
+	h.mu.RUnlock()
+	if ok {
+		return module
+	}
+	if frame.File != "" {
+		module = filepath.Base(filepath.Dir(frame.File))
+	}
+	h.mu.Lock()
+	h.sites[frame.PC] = module
+	h.mu.Unlock()
+	return module
+}
+
+// levelName returns the name of the level accepted by LogConfig.
+func levelName(lvl log.Lvl) string {
+	names := map[log.Lvl]string{
+		log.LvlCrit:  "crit",
+		log.LvlError: "error",
+		log.LvlWarn:  "warn",
+		log.LvlInfo:  "info",
+		log.LvlDebug: "debug",
+		log.LvlTrace: "trace",
+	}
+	if name, ok := names[lvl]; ok {
+		return name
+	}
+	return fmt.Sprint(int(lvl))
+}
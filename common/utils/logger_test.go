@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelHandler(t *testing.T) {
+	var records []*log.Record
+	h := &levelHandler{
+		handler: log.FuncHandler(func(r *log.Record) error {
+			records = append(records, r)
+			return nil
+		}),
+		withModule: true,
+		level:      log.LvlInfo,
+		modules:    make(map[string]log.Lvl),
+		sites:      make(map[uintptr]string),
+	}
+	logger := log.New()
+	logger.SetHandler(h)
+
+	logger.Debug("filtered")
+	logger.Info("logged")
+	assert.Len(t, records, 1)
+	assert.Equal(t, []interface{}{"module", "utils"}, records[0].Ctx)
+
+	// the level of the module overrides the global level.
+	assert.NoError(t, h.apply(&LogConfig{Level: "warn", Modules: map[string]string{"utils": "debug"}}))
+	logger.Debug("logged")
+	assert.Len(t, records, 2)
+	assert.Equal(t, &LogConfig{Level: "warn", Modules: map[string]string{"utils": "debug"}}, h.config())
+
+	// an invalid config leaves the levels unchanged.
+	assert.Error(t, h.apply(&LogConfig{Level: "error", Modules: map[string]string{"sender": "verbose"}}))
+	assert.Equal(t, &LogConfig{Level: "warn", Modules: map[string]string{"utils": "debug"}}, h.config())
+
+	// an empty module level falls back to the global level.
+	assert.NoError(t, h.apply(&LogConfig{Modules: map[string]string{"utils": ""}}))
+	logger.Info("filtered")
+	assert.Len(t, records, 2)
+	assert.Equal(t, &LogConfig{Level: "warn", Modules: map[string]string{}}, h.config())
+}
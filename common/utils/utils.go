@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/modern-go/reflect2"
@@ -49,6 +50,64 @@ func Loop(ctx context.Context, period time.Duration, f func()) {
 	}
 }
 
+// LoopGroup runs loops which can be drained on shutdown: a loop stops starting iterations once its context is done,
+// and Wait blocks until the running iterations return.
+type LoopGroup struct {
+	wg sync.WaitGroup
+}
+
+// LoopWithContext runs the f func with context periodically in a goroutine tracked by the group.
+func (g *LoopGroup) LoopWithContext(ctx context.Context, period time.Duration, f func(ctx context.Context)) {
+	g.LoopWithTrigger(ctx, period, nil, f)
+}
+
+// LoopWithTrigger runs the f func with context in a goroutine tracked by the group each time trigger receives, and
+// periodically as a fallback for missed notifications. Notifications received while f runs start one more iteration.
+func (g *LoopGroup) LoopWithTrigger(ctx context.Context, period time.Duration, trigger <-chan struct{}, f func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		tick := time.NewTicker(period)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				f(ctx)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			case <-trigger:
+				tick.Reset(period)
+			}
+		}
+	}()
+}
+
+// Loop runs the f func periodically in a goroutine tracked by the group.
+func (g *LoopGroup) Loop(ctx context.Context, period time.Duration, f func()) {
+	g.LoopWithContext(ctx, period, func(context.Context) { f() })
+}
+
+// Wait waits until every loop of the group returned, it returns false if they are still running after timeout.
+func (g *LoopGroup) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
 // IsNil Check if the interface is empty.
 func IsNil(i interface{}) bool {
 	return i == nil || reflect2.IsNil(i)
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var (
+		loops      LoopGroup
+		iterations atomic.Int64
+		running    atomic.Bool
+	)
+	loops.Loop(ctx, time.Hour, func() {
+		running.Store(true)
+		iterations.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		running.Store(false)
+	})
+
+	// The first iteration runs immediately, the loop then waits for the ticker.
+	assert.Eventually(t, running.Load, time.Second, 10*time.Millisecond)
+	cancel()
+
+	// The running iteration is drained, no iteration starts once the context is done.
+	assert.True(t, loops.Wait(time.Second))
+	assert.False(t, running.Load())
+	assert.Equal(t, int64(1), iterations.Load())
+
+	// Wait gives up on loops still running after the timeout.
+	blockedCtx, blockedCancel := context.WithCancel(context.Background())
+	var blocked LoopGroup
+	release := make(chan struct{})
+	blocked.Loop(blockedCtx, time.Hour, func() { <-release })
+	assert.False(t, blocked.Wait(50*time.Millisecond))
+	blockedCancel()
+	close(release)
+	assert.True(t, blocked.Wait(time.Second))
+}
+
+func TestLoopGroupTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var (
+		loops      LoopGroup
+		iterations atomic.Int64
+	)
+	trigger := make(chan struct{}, 1)
+	loops.LoopWithTrigger(ctx, time.Hour, trigger, func(context.Context) {
+		iterations.Add(1)
+	})
+
+	// The first iteration runs immediately, each notification runs one more without waiting for the ticker.
+	assert.Eventually(t, func() bool { return iterations.Load() == 1 }, time.Second, 10*time.Millisecond)
+	trigger <- struct{}{}
+	assert.Eventually(t, func() bool { return iterations.Load() == 2 }, time.Second, 10*time.Millisecond)
+	trigger <- struct{}{}
+	assert.Eventually(t, func() bool { return iterations.Load() == 3 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.True(t, loops.Wait(time.Second))
+}
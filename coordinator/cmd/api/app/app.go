@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,15 +14,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 
 	"scroll-tech/common/database"
 	"scroll-tech/common/observability"
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/utils"
 	"scroll-tech/common/version"
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/controller/rpc"
 	"scroll-tech/coordinator/internal/route"
 )
 
@@ -50,6 +54,18 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
 	}
 
+	shutdownTracing, err := tracing.Init(cfg.Tracing, app.Name)
+	if err != nil {
+		log.Crit("failed to init tracing", "err", err)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Warn("failed to flush the tracing spans", "err", err)
+		}
+	}()
+
 	db, err := database.InitDB(cfg.DB)
 	if err != nil {
 		log.Crit("failed to init db connection", "err", err)
@@ -63,7 +79,21 @@ func action(ctx *cli.Context) error {
 	registry := prometheus.DefaultRegisterer
 	observability.Server(ctx, db)
 
-	apiSrv := apiServer(ctx, cfg, db, registry)
+	// the admin statistics are read from the replica if configured.
+	readDB, err := database.InitReadDB(ctx.Context, cfg.DB, db)
+	if err != nil {
+		log.Crit("failed to init read db connection", "err", err)
+	}
+	if readDB != db {
+		observability.RegisterReadinessCheck("read_db", observability.DBCheck(readDB))
+	}
+
+	apiSrv := apiServer(ctx, cfg, db, readDB, registry)
+
+	var grpcSrv *grpc.Server
+	if cfg.GRPC != nil {
+		grpcSrv = grpcServer(cfg.GRPC, registry)
+	}
 
 	log.Info(
 		"Start coordinator api successfully.",
@@ -78,6 +108,10 @@ func action(ctx *cli.Context) error {
 	<-interrupt
 	log.Info("start shutdown coordinator server ...")
 
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop()
+	}
+
 	closeCtx, cancelExit := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancelExit()
 	if err = apiSrv.Shutdown(closeCtx); err != nil {
@@ -90,9 +124,9 @@ func action(ctx *cli.Context) error {
 	return nil
 }
 
-func apiServer(ctx *cli.Context, cfg *config.Config, db *gorm.DB, reg prometheus.Registerer) *http.Server {
+func apiServer(ctx *cli.Context, cfg *config.Config, db, readDB *gorm.DB, reg prometheus.Registerer) *http.Server {
 	router := gin.New()
-	api.InitController(cfg, db, reg)
+	api.InitController(cfg, db, readDB, reg)
 	route.Route(router, cfg, reg)
 	port := ctx.String(httpPortFlag.Name)
 	srv := &http.Server{
@@ -109,6 +143,25 @@ func apiServer(ctx *cli.Context, cfg *config.Config, db *gorm.DB, reg prometheus
 	return srv
 }
 
+// grpcServer serves the grpc api of the provers, the controllers must have been initialized by apiServer.
+func grpcServer(cfg *config.GRPC, reg prometheus.Registerer) *grpc.Server {
+	srv, err := rpc.NewServer(cfg, rpc.NewService(api.GetTask, api.SubmitProof, api.Heartbeat, api.RateLimiter, reg))
+	if err != nil {
+		log.Crit("failed to create coordinator grpc server", "error", err)
+	}
+	listener, err := net.Listen("tcp", cfg.ListenAddress)
+	if err != nil {
+		log.Crit("failed to listen on coordinator grpc address", "address", cfg.ListenAddress, "error", err)
+	}
+
+	go func() {
+		if runServerErr := srv.Serve(listener); runServerErr != nil {
+			log.Crit("run coordinator grpc server failure", "error", runServerErr)
+		}
+	}()
+	return srv
+}
+
 // Run coordinator.
 func Run() {
 	// RunApp the coordinator.
@@ -1,11 +1,16 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"scroll-tech/common/database"
+	"scroll-tech/common/secrets"
+	"scroll-tech/common/tracing"
 )
 
 // ProverManager loads sequencer configuration items.
@@ -25,6 +30,95 @@ type ProverManager struct {
 	MaxVerifierWorkers int `json:"max_verifier_workers"`
 	// MinProverVersion is the minimum version of the prover that is required.
 	MinProverVersion string `json:"min_prover_version"`
+	// TaskPriority scores the tasks offered to the provers accepting any proof type, nil assigns the oldest task first.
+	TaskPriority *TaskPriority `json:"task_priority,omitempty"`
+	// ProverReputation delays the tasks offered to the provers failing or slow to prove them, nil offers the tasks to
+	// every prover alike.
+	ProverReputation *ProverReputation `json:"prover_reputation,omitempty"`
+	// ProverHeartbeat withholds the tasks from the provers whose latest heartbeat reports too little GPU memory or too
+	// many errors, nil assigns the tasks regardless of the heartbeats.
+	ProverHeartbeat *ProverHeartbeat `json:"prover_heartbeat,omitempty"`
+	// TaskEscalation flags the tasks failing too many times for attention, nil disables it.
+	TaskEscalation *TaskEscalation `json:"task_escalation,omitempty"`
+	// InvalidProofBan temporarily blocks the provers submitting invalid proofs repeatedly, nil disables it.
+	InvalidProofBan *InvalidProofBan `json:"invalid_proof_ban,omitempty"`
+	// CircuitForks routes the tasks to the provers by the circuit version they advertise at login, replacing the vk
+	// check of the provers. Empty assigns the tasks to any prover with the expected vk.
+	CircuitForks []CircuitFork `json:"circuit_forks,omitempty"`
+}
+
+// CircuitFork loads a circuit upgrade: the tasks of the blocks from ForkBlock up to the next fork are only assigned to
+// the provers advertising CircuitVersion. The first fork block is usually 0. The chunks and batches must not span a
+// fork block.
+type CircuitFork struct {
+	ForkBlock      uint64 `json:"fork_block"`
+	CircuitVersion string `json:"circuit_version"`
+}
+
+// TaskPriority loads the scoring of the tasks waiting for a prover. The score of a task is its proof type weight times
+// its age in seconds, plus the time past its deadline times the overdue weight. The task with the highest score is
+// assigned first.
+type TaskPriority struct {
+	// ProofTypeWeights weigh the tasks by proof type: "chunk" and "batch". A missing proof type weighs 1.
+	ProofTypeWeights map[string]float64 `json:"proof_type_weights,omitempty"`
+	// DeadlineSec is the time since its creation a task should be proven by, e.g. to finalize the batches in time,
+	// 0 for no deadline.
+	DeadlineSec int `json:"deadline_sec,omitempty"`
+	// OverdueWeight weighs the seconds past the deadline of a task.
+	OverdueWeight float64 `json:"overdue_weight,omitempty"`
+}
+
+// ProverReputation loads the weighing of the provers by their statistics. The weight of a prover is its success rate
+// on a proof type, scaled down by its average proving time for batches. A prover of weight w is only offered the tasks
+// waiting for at least (1 - w) * MaxDelaySec, leaving the fresh tasks to the reliable provers.
+type ProverReputation struct {
+	// MinTasks is the number of tasks of a proof type a prover should finish before being weighed, fewer weigh 1.
+	MinTasks int `json:"min_tasks"`
+	// MaxDelaySec is the time a task waits before being offered to a prover of weight 0.
+	MaxDelaySec int `json:"max_delay_sec"`
+	// BatchProvingTimeSec is the expected batch proving time, a prover proving batches slower is weighed down by
+	// the ratio of this time and its average proving time. 0 does not weigh the proving time.
+	BatchProvingTimeSec int `json:"batch_proving_time_sec,omitempty"`
+}
+
+// ProverHeartbeat loads the use of the prover heartbeats in the task assignment. Only the heartbeats received within
+// StaleSec are taken into account, a prover without a recent heartbeat is assigned the tasks as before.
+type ProverHeartbeat struct {
+	// StaleSec is the age after which a heartbeat is ignored.
+	StaleSec int `json:"stale_sec"`
+	// MaxRecentErrors is the number of recent errors above which a prover is not assigned tasks, 0 does not check them.
+	MaxRecentErrors uint64 `json:"max_recent_errors,omitempty"`
+	// MinGPUMemoryMB is the free GPU memory a prover should report to be assigned a task, by proof type: "chunk"
+	// and "batch". A missing proof type or a prover reporting no GPU memory is not checked.
+	MinGPUMemoryMB map[string]uint64 `json:"min_gpu_memory_mb,omitempty"`
+}
+
+// TaskEscalation loads the escalation of the tasks failed by the provers. A failed task is recycled and reassigned
+// until it runs out of session attempts; once MaxFailures of its prover tasks timed out or failed it is recorded as
+// needing attention and the alert webhook is notified, once per task.
+type TaskEscalation struct {
+	// MaxFailures is the number of failed prover tasks after which a task is escalated.
+	MaxFailures int `json:"max_failures"`
+	// Webhook is notified of the escalated tasks, nil only records them.
+	Webhook *AlertWebhook `json:"webhook,omitempty"`
+}
+
+// InvalidProofBan loads the temporary block of the provers whose proofs fail verification. A prover submitting
+// MaxInvalidProofs invalid proofs for the tasks assigned within WindowSec is blocked for BanDurationSec.
+type InvalidProofBan struct {
+	MaxInvalidProofs int `json:"max_invalid_proofs"`
+	WindowSec        int `json:"window_sec"`
+	BanDurationSec   int `json:"ban_duration_sec"`
+}
+
+// AlertWebhook loads the delivery of the alerts to an HTTP endpoint.
+type AlertWebhook struct {
+	// URL the alerts are posted to.
+	URL string `json:"url"`
+	// Secret of the HMAC-SHA256 signature of the request body sent in the X-Scroll-Signature header, empty disables signing.
+	Secret string `json:"secret,omitempty"`
+	// TimeoutSec is the timeout of a delivery, defaults to 5.
+	TimeoutSec int `json:"timeout_sec,omitempty"`
 }
 
 // L2 loads l2geth configuration items.
@@ -35,9 +129,37 @@ type L2 struct {
 
 // Auth provides the auth coordinator
 type Auth struct {
+	// Secret signs the jwt tokens, configured as is or as a reference to the secret store holding it, e.g.
+	// vault://secret/data/coordinator#jwt_secret. It is resolved once on start.
 	Secret                     string `json:"secret"`
 	ChallengeExpireDurationSec int    `json:"challenge_expire_duration_sec"`
-	LoginExpireDurationSec     int    `json:"login_expire_duration_sec"`
+	// LoginExpireDurationSec is the lifetime of the login tokens, to be kept short when they are refreshed.
+	LoginExpireDurationSec int `json:"login_expire_duration_sec"`
+	// ChallengeRotationSec rotates the key signing the challenges, derived from the secret, every ChallengeRotationSec.
+	// The challenges signed with the previous key are still accepted. 0 signs the challenges with the secret.
+	ChallengeRotationSec int `json:"challenge_rotation_sec,omitempty"`
+	// MaxRefreshDurationSec is the time since its login a prover may refresh its token before it expires, without a
+	// new challenge. 0 disables the refresh, the provers login again once their token expired.
+	MaxRefreshDurationSec int `json:"max_refresh_duration_sec,omitempty"`
+	// EnforceAllowlist only lets the provers whose public key is in the allowlist, managed on the admin api, login.
+	EnforceAllowlist bool `json:"enforce_allowlist,omitempty"`
+}
+
+func (c *Auth) validate() error {
+	if c.ChallengeRotationSec < 0 || c.MaxRefreshDurationSec < 0 {
+		return fmt.Errorf("challenge_rotation_sec %d and max_refresh_duration_sec %d must not be negative", c.ChallengeRotationSec, c.MaxRefreshDurationSec)
+	}
+	// a challenge must stay verifiable until it expires, i.e. signed by the current or the previous key.
+	if c.ChallengeRotationSec > 0 && c.ChallengeRotationSec < c.ChallengeExpireDurationSec {
+		return fmt.Errorf("challenge_rotation_sec %d must not be smaller than challenge_expire_duration_sec %d", c.ChallengeRotationSec, c.ChallengeExpireDurationSec)
+	}
+	return nil
+}
+
+// Admin loads the admin api configuration items.
+type Admin struct {
+	// AuthToken is the bearer token of the admin api requests.
+	AuthToken string `json:"auth_token"`
 }
 
 // Config load configuration items.
@@ -46,6 +168,130 @@ type Config struct {
 	DB            *database.Config `json:"db"`
 	L2            *L2              `json:"l2"`
 	Auth          *Auth            `json:"auth"`
+	// Admin enables the admin api, nil disables it.
+	Admin *Admin `json:"admin,omitempty"`
+	// GRPC enables the grpc api of the provers, nil disables it.
+	GRPC *GRPC `json:"grpc,omitempty"`
+	// RateLimit limits the requests of the provers, nil does not limit them.
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+	// Tracing exports the spans of the prover api to an OTLP collector, nil disables tracing.
+	Tracing *tracing.Config `json:"tracing,omitempty"`
+	// PayloadStorage offloads the large task payloads to an object storage, nil inlines them in the task responses.
+	PayloadStorage *PayloadStorage `json:"payload_storage,omitempty"`
+}
+
+// Payload storage types.
+const (
+	PayloadStorageS3   = "s3"
+	PayloadStorageFile = "file"
+)
+
+// PayloadStorage loads the offload of the task payloads. A payload of at least MinSizeBytes is uploaded to the storage
+// and the prover is sent a presigned URL and the sha256 hash of the payload instead of the payload itself.
+type PayloadStorage struct {
+	// Type is the storage of the payloads: "s3" or "file".
+	Type string `json:"type"`
+	// MinSizeBytes is the size of the smallest payload offloaded, smaller payloads are inlined.
+	MinSizeBytes int `json:"min_size_bytes"`
+	// URLExpirySec is the validity of the presigned URLs, defaults to 3600.
+	URLExpirySec int `json:"url_expiry_sec,omitempty"`
+	// RetentionSec is the age after which the cron deletes the payloads, 0 keeps them, e.g. to leave their cleanup to
+	// the lifecycle rules of the bucket.
+	RetentionSec int `json:"retention_sec,omitempty"`
+	// S3 loads the S3 compatible storage, e.g. AWS S3, GCS through its XML API with HMAC keys or MinIO.
+	S3 *S3Storage `json:"s3,omitempty"`
+	// File loads the storage of the payloads as static files served by the coordinator api.
+	File *FileStorage `json:"file,omitempty"`
+}
+
+// S3Storage loads an S3 compatible bucket, the objects are addressed in the path style.
+type S3Storage struct {
+	// Endpoint is the url of the storage, e.g. https://s3.us-east-1.amazonaws.com or https://storage.googleapis.com.
+	Endpoint string `json:"endpoint"`
+	Region   string `json:"region"`
+	Bucket   string `json:"bucket"`
+	// Prefix is prepended to the object keys of the payloads.
+	Prefix      string `json:"prefix,omitempty"`
+	AccessKeyID string `json:"access_key_id"`
+	// SecretAccessKey is configured as is or as a reference to the secret store holding it. It is resolved once on start.
+	SecretAccessKey string `json:"secret_access_key"`
+}
+
+// FileStorage loads the storage of the payloads in a directory shared by the coordinator api and cron instances.
+type FileStorage struct {
+	// Dir is the directory the payloads are written to.
+	Dir string `json:"dir"`
+	// BaseURL is the url of the coordinator api reached by the provers, e.g. https://coordinator.example.com.
+	BaseURL string `json:"base_url"`
+	// Secret signs the payload URLs, configured as is or as a reference to the secret store holding it.
+	Secret string `json:"secret"`
+}
+
+func (c *PayloadStorage) validate() error {
+	if c.MinSizeBytes < 0 || c.URLExpirySec < 0 || c.RetentionSec < 0 {
+		return fmt.Errorf("min_size_bytes %d, url_expiry_sec %d and retention_sec %d must not be negative", c.MinSizeBytes, c.URLExpirySec, c.RetentionSec)
+	}
+	switch c.Type {
+	case PayloadStorageS3:
+		if c.S3 == nil || c.S3.Endpoint == "" || c.S3.Region == "" || c.S3.Bucket == "" || c.S3.AccessKeyID == "" || c.S3.SecretAccessKey == "" {
+			return errors.New("s3 storage requires the endpoint, region, bucket, access_key_id and secret_access_key")
+		}
+	case PayloadStorageFile:
+		if c.File == nil || c.File.Dir == "" || c.File.BaseURL == "" || c.File.Secret == "" {
+			return errors.New("file storage requires the dir, base_url and secret")
+		}
+	default:
+		return fmt.Errorf("unknown payload storage type: %q", c.Type)
+	}
+	return nil
+}
+
+func (c *PayloadStorage) resolveSecrets(ctx context.Context) error {
+	var err error
+	if c.S3 != nil {
+		if c.S3.SecretAccessKey, err = secrets.Resolve(ctx, c.S3.SecretAccessKey); err != nil {
+			return fmt.Errorf("failed to resolve the s3 secret access key: %w", err)
+		}
+	}
+	if c.File != nil {
+		if c.File.Secret, err = secrets.Resolve(ctx, c.File.Secret); err != nil {
+			return fmt.Errorf("failed to resolve the file storage secret: %w", err)
+		}
+	}
+	return nil
+}
+
+// RateLimit loads the rate limits and quotas of the prover api, the rejected requests are answered with
+// http status 429 or grpc code ResourceExhausted.
+type RateLimit struct {
+	// Login limits the challenge and login requests per client IP, nil does not limit them.
+	Login *Rate `json:"login,omitempty"`
+	// GetTask limits the get_task requests per prover public key, nil does not limit them.
+	GetTask *Rate `json:"get_task,omitempty"`
+	// SubmitProof limits the submit_proof requests per prover public key, nil does not limit them.
+	SubmitProof *Rate `json:"submit_proof,omitempty"`
+	// MaxConcurrentTasks is the number of tasks a prover may be assigned at once, defaults to 1.
+	MaxConcurrentTasks int `json:"max_concurrent_tasks,omitempty"`
+}
+
+// Rate loads a token bucket rate limit.
+type Rate struct {
+	// RequestsPerSec is the sustained number of requests allowed per second.
+	RequestsPerSec float64 `json:"requests_per_sec"`
+	// Burst is the number of requests allowed at once.
+	Burst int `json:"burst"`
+}
+
+// GRPC loads the grpc api configuration items. The provers authenticate with a client certificate signed by the
+// client CA whose common name is the prover name.
+type GRPC struct {
+	// ListenAddress is the address the grpc server listens on, e.g. ":8391".
+	ListenAddress string `json:"listen_address"`
+	// CertFile and KeyFile are the PEM encoded certificate and key of the server.
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// ClientCAFile is the PEM encoded CA certificate the client certificates are verified with.
+	ClientCAFile string `json:"client_ca_file"`
 }
 
 // VerifierConfig load zk verifier config.
@@ -68,5 +314,21 @@ func NewConfig(file string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.Auth != nil {
+		if err = cfg.Auth.validate(); err != nil {
+			return nil, fmt.Errorf("invalid auth configuration: %w", err)
+		}
+		if cfg.Auth.Secret, err = secrets.Resolve(context.Background(), cfg.Auth.Secret); err != nil {
+			return nil, fmt.Errorf("failed to resolve the auth secret: %w", err)
+		}
+	}
+	if cfg.PayloadStorage != nil {
+		if err = cfg.PayloadStorage.validate(); err != nil {
+			return nil, fmt.Errorf("invalid payload storage configuration: %w", err)
+		}
+		if err = cfg.PayloadStorage.resolveSecrets(context.Background()); err != nil {
+			return nil, err
+		}
+	}
 	return cfg, nil
 }
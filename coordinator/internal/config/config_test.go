@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,7 +24,33 @@ func TestConfig(t *testing.T) {
 				"agg_vk_path": ""
 			},
 			"max_verifier_workers": 4,
-			"min_prover_version": "v1.0.0"
+			"min_prover_version": "v1.0.0",
+			"task_priority": {
+				"proof_type_weights": {"chunk": 1, "batch": 2},
+				"deadline_sec": 3600,
+				"overdue_weight": 10
+			},
+			"prover_reputation": {
+				"min_tasks": 10,
+				"max_delay_sec": 600,
+				"batch_proving_time_sec": 1800
+			},
+			"task_escalation": {
+				"max_failures": 3,
+				"webhook": {
+					"url": "http://localhost:8080/alerts",
+					"secret": "webhook secret"
+				}
+			},
+			"invalid_proof_ban": {
+				"max_invalid_proofs": 3,
+				"window_sec": 3600,
+				"ban_duration_sec": 86400
+			},
+			"circuit_forks": [
+				{"fork_block": 0, "circuit_version": "v0.11.0"},
+				{"fork_block": 1000, "circuit_version": "v0.12.0"}
+			]
 		},
 		"db": {
 			"driver_name": "postgres",
@@ -37,8 +64,45 @@ func TestConfig(t *testing.T) {
  		"auth": {
 			"secret": "prover secret key",
 			"challenge_expire_duration_sec": 3600,
-			"login_expire_duration_sec": 3600
-  		}
+			"login_expire_duration_sec": 900,
+			"challenge_rotation_sec": 7200,
+			"max_refresh_duration_sec": 86400,
+			"enforce_allowlist": true
+  		},
+		"admin": {
+			"auth_token": "admin token"
+		},
+		"grpc": {
+			"listen_address": ":8391",
+			"cert_file": "server.pem",
+			"key_file": "server.key",
+			"client_ca_file": "ca.pem"
+		},
+		"rate_limit": {
+			"login": {
+				"requests_per_sec": 1,
+				"burst": 5
+			},
+			"get_task": {
+				"requests_per_sec": 0.5,
+				"burst": 2
+			},
+			"max_concurrent_tasks": 2
+		},
+		"payload_storage": {
+			"type": "s3",
+			"min_size_bytes": 1048576,
+			"url_expiry_sec": 1800,
+			"retention_sec": 604800,
+			"s3": {
+				"endpoint": "https://storage.googleapis.com",
+				"region": "auto",
+				"bucket": "coordinator-payloads",
+				"prefix": "mainnet",
+				"access_key_id": "access key id",
+				"secret_access_key": "secret access key"
+			}
+		}
 	}`
 
 	t.Run("Success Case", func(t *testing.T) {
@@ -70,6 +134,36 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, cfg, cfg2)
 	})
 
+	t.Run("Invalid Payload Storage", func(t *testing.T) {
+		tmpFile, err := os.CreateTemp("", "payload_storage_config.json")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, tmpFile.Close())
+			assert.NoError(t, os.Remove(tmpFile.Name()))
+		}()
+
+		_, err = tmpFile.WriteString(strings.Replace(configTemplate, `"type": "s3"`, `"type": "file"`, 1))
+		assert.NoError(t, err)
+
+		_, err = NewConfig(tmpFile.Name())
+		assert.ErrorContains(t, err, "file storage requires")
+	})
+
+	t.Run("Invalid Challenge Rotation", func(t *testing.T) {
+		tmpFile, err := os.CreateTemp("", "auth_config.json")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, tmpFile.Close())
+			assert.NoError(t, os.Remove(tmpFile.Name()))
+		}()
+
+		_, err = tmpFile.WriteString(strings.Replace(configTemplate, `"challenge_rotation_sec": 7200`, `"challenge_rotation_sec": 60`, 1))
+		assert.NoError(t, err)
+
+		_, err = NewConfig(tmpFile.Name())
+		assert.ErrorContains(t, err, "challenge_rotation_sec")
+	})
+
 	t.Run("File Not Found", func(t *testing.T) {
 		_, err := NewConfig("non_existent_file.json")
 		assert.ErrorIs(t, err, os.ErrNotExist)
@@ -2,13 +2,16 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
 	"scroll-tech/common/types/message"
 
+	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/logic/auth"
 	"scroll-tech/coordinator/internal/types"
 )
@@ -19,9 +22,9 @@ type AuthController struct {
 }
 
 // NewAuthController returns an LoginController instance
-func NewAuthController(db *gorm.DB) *AuthController {
+func NewAuthController(cfg *config.Config, db *gorm.DB) *AuthController {
 	return &AuthController{
-		loginLogic: auth.NewLoginLogic(db),
+		loginLogic: auth.NewLoginLogic(cfg.Auth, db),
 	}
 }
 
@@ -39,6 +42,14 @@ func (a *AuthController) Login(c *gin.Context) (interface{}, error) {
 		return "", fmt.Errorf("check challenge failure for the not equal challenge string")
 	}
 
+	publicKey, err := recoverPublicKey(login)
+	if err != nil {
+		return "", fmt.Errorf("recover public key failure:%w", err)
+	}
+	if err := a.loginLogic.CheckAllowlist(c, publicKey); err != nil {
+		return "", fmt.Errorf("check prover allowlist failure:%w", err)
+	}
+
 	// check the challenge is used, if used, return failure
 	if err := a.loginLogic.InsertChallengeString(c, login.Message.Challenge); err != nil {
 		return "", fmt.Errorf("login insert challenge string failure:%w", err)
@@ -53,28 +64,57 @@ func (a *AuthController) PayloadFunc(data interface{}) jwt.MapClaims {
 		return jwt.MapClaims{}
 	}
 
-	// recover the public key
-	authMsg := message.AuthMsg{
-		Identity: &message.Identity{
-			Challenge:     v.Message.Challenge,
-			ProverName:    v.Message.ProverName,
-			ProverVersion: v.Message.ProverVersion,
-		},
-		Signature: v.Signature,
-	}
-
-	publicKey, err := authMsg.PublicKey()
+	publicKey, err := recoverPublicKey(v)
 	if err != nil {
 		return jwt.MapClaims{}
 	}
 
 	return jwt.MapClaims{
-		types.PublicKey:     publicKey,
-		types.ProverName:    v.Message.ProverName,
-		types.ProverVersion: v.Message.ProverVersion,
+		types.PublicKey:      publicKey,
+		types.ProverName:     v.Message.ProverName,
+		types.ProverVersion:  v.Message.ProverVersion,
+		types.CircuitVersion: v.Message.CircuitVersion,
+		types.LoginAt:        time.Now().Unix(),
 	}
 }
 
+// Authorizator rejects the tokens issued before the latest revocation of the tokens of the prover.
+func (a *AuthController) Authorizator(_ interface{}, c *gin.Context) bool {
+	claims := jwt.ExtractClaims(c)
+	publicKey, ok := claims[types.PublicKey].(string)
+	if !ok {
+		return false
+	}
+	// the tokens issued before the login time was recorded are bounded by their issue time.
+	loginAt, ok := claims[types.LoginAt].(float64)
+	if !ok {
+		if loginAt, ok = claims["orig_iat"].(float64); !ok {
+			return false
+		}
+	}
+
+	revoked, err := a.loginLogic.IsTokenRevoked(c, publicKey, time.Unix(int64(loginAt), 0))
+	if err != nil {
+		log.Error("failed to check the token revocation", "public key", publicKey, "error", err)
+		return false
+	}
+	return !revoked
+}
+
+// recoverPublicKey recovers the public key of the prover from the signature of its login message.
+func recoverPublicKey(login types.LoginParameter) (string, error) {
+	authMsg := message.AuthMsg{
+		Identity: &message.Identity{
+			Challenge:      login.Message.Challenge,
+			ProverName:     login.Message.ProverName,
+			ProverVersion:  login.Message.ProverVersion,
+			CircuitVersion: login.Message.CircuitVersion,
+		},
+		Signature: login.Signature,
+	}
+	return authMsg.PublicKey()
+}
+
 // IdentityHandler replies to client for /login
 func (a *AuthController) IdentityHandler(c *gin.Context) interface{} {
 	claims := jwt.ExtractClaims(c)
@@ -89,5 +129,9 @@ func (a *AuthController) IdentityHandler(c *gin.Context) interface{} {
 	if proverVersion, ok := claims[types.ProverVersion]; ok {
 		c.Set(types.ProverVersion, proverVersion)
 	}
+
+	if circuitVersion, ok := claims[types.CircuitVersion]; ok {
+		c.Set(types.CircuitVersion, circuitVersion)
+	}
 	return nil
 }
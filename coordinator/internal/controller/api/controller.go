@@ -7,6 +7,8 @@ import (
 	"gorm.io/gorm"
 
 	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/payload"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
 	"scroll-tech/coordinator/internal/logic/verifier"
 )
 
@@ -17,20 +19,45 @@ var (
 	SubmitProof *SubmitProofController
 	// Auth the auth controller
 	Auth *AuthController
+	// ProverStats the prover statistics admin controller
+	ProverStats *ProverStatsController
+	// TaskEscalation the escalated tasks admin controller
+	TaskEscalation *TaskEscalationController
+	// PipelineStatus the proving pipeline status admin controller
+	PipelineStatus *PipelineStatusController
+	// Heartbeat the prover heartbeat controller, also listing the heartbeats on the admin api
+	Heartbeat *HeartbeatController
+	// ProverAllowlist the prover allowlist and token revocation admin controller
+	ProverAllowlist *ProverAllowlistController
+	// RateLimiter the rate limits and quotas of the prover api, nil when not configured
+	RateLimiter *ratelimit.Limiter
+	// TaskPayload offloads the large task payloads to the payload storage, nil when not configured
+	TaskPayload *payload.Offloader
 
 	initControllerOnce sync.Once
 )
 
-// InitController inits Controller with database
-func InitController(cfg *config.Config, db *gorm.DB, reg prometheus.Registerer) {
+// InitController inits Controller with database, the admin listings are served by readDB
+func InitController(cfg *config.Config, db, readDB *gorm.DB, reg prometheus.Registerer) {
 	initControllerOnce.Do(func() {
 		vf, err := verifier.NewVerifier(cfg.ProverManager.Verifier)
 		if err != nil {
 			panic("proof receiver new verifier failure")
 		}
 
-		Auth = NewAuthController(db)
-		GetTask = NewGetTaskController(cfg, db, vf, reg)
+		TaskPayload, err = payload.NewOffloader(cfg.PayloadStorage, reg)
+		if err != nil {
+			panic("task payload new offloader failure")
+		}
+
+		Auth = NewAuthController(cfg, db)
+		GetTask = NewGetTaskController(cfg, db, vf, TaskPayload, reg)
 		SubmitProof = NewSubmitProofController(cfg, db, vf, reg)
+		ProverStats = NewProverStatsController(readDB)
+		TaskEscalation = NewTaskEscalationController(readDB)
+		PipelineStatus = NewPipelineStatusController(readDB)
+		Heartbeat = NewHeartbeatController(db, readDB)
+		ProverAllowlist = NewProverAllowlistController(db)
+		RateLimiter = ratelimit.NewLimiter(cfg.RateLimit, db, reg)
 	})
 }
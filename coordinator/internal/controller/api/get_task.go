@@ -2,16 +2,19 @@ package api
 
 import (
 	"fmt"
-	"math/rand"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 
 	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/payload"
 	"scroll-tech/coordinator/internal/logic/provertask"
 	"scroll-tech/coordinator/internal/logic/verifier"
 	coordinatorType "scroll-tech/coordinator/internal/types"
@@ -20,15 +23,32 @@ import (
 // GetTaskController the get prover task api controller
 type GetTaskController struct {
 	proverTasks map[message.ProofType]provertask.ProverTask
+	// taskScorer picks the proof type of the tasks assigned to the provers accepting any proof type.
+	taskScorer provertask.TaskScorer
+	// proverReputation delays the tasks offered to the unreliable provers, nil if disabled.
+	proverReputation *provertask.ProverReputation
+	// proverHeartbeatCheck withholds the tasks from the provers reporting too few resources, nil if disabled.
+	proverHeartbeatCheck *provertask.ProverHeartbeatCheck
+	// taskPayload offloads the large task payloads, nil if disabled.
+	taskPayload *payload.Offloader
 }
 
 // NewGetTaskController create a get prover task controller
-func NewGetTaskController(cfg *config.Config, db *gorm.DB, vf *verifier.Verifier, reg prometheus.Registerer) *GetTaskController {
+func NewGetTaskController(cfg *config.Config, db *gorm.DB, vf *verifier.Verifier, taskPayload *payload.Offloader, reg prometheus.Registerer) *GetTaskController {
 	chunkProverTask := provertask.NewChunkProverTask(cfg, db, vf.ChunkVK, reg)
 	batchProverTask := provertask.NewBatchProverTask(cfg, db, vf.BatchVK, reg)
 
 	ptc := &GetTaskController{
 		proverTasks: make(map[message.ProofType]provertask.ProverTask),
+		taskScorer:  provertask.NewTaskScorer(cfg.ProverManager.TaskPriority),
+		taskPayload: taskPayload,
+	}
+
+	if cfg.ProverManager.ProverReputation != nil {
+		ptc.proverReputation = provertask.NewProverReputation(cfg.ProverManager.ProverReputation, db, reg)
+	}
+	if cfg.ProverManager.ProverHeartbeat != nil {
+		ptc.proverHeartbeatCheck = provertask.NewProverHeartbeatCheck(cfg.ProverManager.ProverHeartbeat, db, reg)
 	}
 
 	ptc.proverTasks[message.ProofTypeChunk] = chunkProverTask
@@ -46,43 +66,95 @@ func (ptc *GetTaskController) GetTasks(ctx *gin.Context) {
 		return
 	}
 
-	proofType := ptc.proofType(&getTaskParameter)
+	result, errCode, err := ptc.GetTask(ctx, &getTaskParameter)
+	if err != nil {
+		types.RenderFailure(ctx, errCode, err)
+		return
+	}
+	types.RenderSuccess(ctx, result)
+}
+
+// GetTask assigns a task to the prover of ctx, it returns the error code of the failure along with the error, the
+// code is ErrCoordinatorEmptyProofData when no task is waiting.
+func (ptc *GetTaskController) GetTask(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, int, error) {
+	proofType, err := ptc.proofType(ctx, getTaskParameter)
+	if err != nil {
+		log.Error("failed to prioritize prover tasks", "height", getTaskParameter.ProverHeight, "err", err)
+		return nil, types.ErrCoordinatorGetTaskFailure, provertask.ErrCoordinatorInternalFailure
+	}
+	if proofType == message.ProofTypeUndefined {
+		return nil, types.ErrCoordinatorEmptyProofData, fmt.Errorf("get empty prover task")
+	}
+
 	proverTask, isExist := ptc.proverTasks[proofType]
 	if !isExist {
-		nerr := fmt.Errorf("parameter wrong proof type:%v", proofType)
-		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
-		return
+		return nil, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter wrong proof type:%v", proofType)
 	}
 
-	result, err := proverTask.Assign(ctx, &getTaskParameter)
+	admitted, err := ptc.admitProver(ctx, proofType, proverTask, getTaskParameter)
 	if err != nil {
-		nerr := fmt.Errorf("return prover task err:%w", err)
-		types.RenderFailure(ctx, types.ErrCoordinatorGetTaskFailure, nerr)
-		return
+		log.Error("failed to check prover admission", "height", getTaskParameter.ProverHeight, "err", err)
+		return nil, types.ErrCoordinatorGetTaskFailure, provertask.ErrCoordinatorInternalFailure
+	}
+	if !admitted {
+		return nil, types.ErrCoordinatorEmptyProofData, fmt.Errorf("get empty prover task")
 	}
 
+	result, err := proverTask.Assign(ctx, getTaskParameter)
+	if err != nil {
+		return nil, types.ErrCoordinatorGetTaskFailure, fmt.Errorf("return prover task err:%w", err)
+	}
 	if result == nil {
-		nerr := fmt.Errorf("get empty prover task")
-		types.RenderFailure(ctx, types.ErrCoordinatorEmptyProofData, nerr)
-		return
+		return nil, types.ErrCoordinatorEmptyProofData, fmt.Errorf("get empty prover task")
 	}
-
-	types.RenderSuccess(ctx, result)
+	ptc.taskPayload.Offload(ctx, result)
+	tracing.SpanFromContext(ctx).SetAttributes("task_id", result.TaskID, "task_type", message.ProofType(result.TaskType).String())
+	return result, types.Success, nil
 }
 
-func (ptc *GetTaskController) proofType(para *coordinatorType.GetTaskParameter) message.ProofType {
+// proofType returns the proof type requested by the prover. A prover accepting any proof type is assigned the proof
+// type of the waiting task with the highest score, ProofTypeUndefined if no task is waiting.
+func (ptc *GetTaskController) proofType(ctx *gin.Context, para *coordinatorType.GetTaskParameter) (message.ProofType, error) {
 	proofType := message.ProofType(para.TaskType)
+	if proofType != message.ProofTypeUndefined {
+		return proofType, nil
+	}
 
-	proofTypes := []message.ProofType{
-		message.ProofTypeChunk,
-		message.ProofTypeBatch,
+	var candidates []*provertask.TaskCandidate
+	for _, proofType := range []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch} {
+		candidate, err := ptc.proverTasks[proofType].Peek(ctx, para)
+		if err != nil {
+			return message.ProofTypeUndefined, fmt.Errorf("failed to peek %v task: %w", proofType, err)
+		}
+		candidates = append(candidates, candidate)
 	}
 
-	if proofType == message.ProofTypeUndefined {
-		rand.Shuffle(len(proofTypes), func(i, j int) {
-			proofTypes[i], proofTypes[j] = proofTypes[j], proofTypes[i]
-		})
-		proofType = proofTypes[0]
+	candidate := provertask.PrioritizeCandidates(ptc.taskScorer, candidates, time.Now())
+	if candidate == nil {
+		return message.ProofTypeUndefined, nil
+	}
+	return candidate.ProofType, nil
+}
+
+// admitProver returns whether the prover is offered the next task of proverTask given its latest heartbeat and its
+// reputation, always true if both checks are disabled or no task is waiting.
+func (ptc *GetTaskController) admitProver(ctx *gin.Context, proofType message.ProofType, proverTask provertask.ProverTask, para *coordinatorType.GetTaskParameter) (bool, error) {
+	if ptc.proverHeartbeatCheck != nil {
+		admitted, err := ptc.proverHeartbeatCheck.Admit(ctx, ctx.GetString(coordinatorType.PublicKey), proofType, time.Now())
+		if err != nil || !admitted {
+			return false, err
+		}
+	}
+	if ptc.proverReputation == nil {
+		return true, nil
+	}
+
+	candidate, err := proverTask.Peek(ctx, para)
+	if err != nil {
+		return false, fmt.Errorf("failed to peek task: %w", err)
+	}
+	if candidate == nil {
+		return true, nil
 	}
-	return proofType
+	return ptc.proverReputation.Admit(ctx, ctx.GetString(coordinatorType.PublicKey), candidate, time.Now())
 }
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+const defaultProverHeartbeatsLimit = 100
+
+// HeartbeatController the prover heartbeat api controller, the heartbeats are listed on the admin api from readDB
+type HeartbeatController struct {
+	proverHeartbeatOrm     *orm.ProverHeartbeat
+	readProverHeartbeatOrm *orm.ProverHeartbeat
+}
+
+// NewHeartbeatController create a prover heartbeat controller
+func NewHeartbeatController(db, readDB *gorm.DB) *HeartbeatController {
+	return &HeartbeatController{
+		proverHeartbeatOrm:     orm.NewProverHeartbeat(db),
+		readProverHeartbeatOrm: orm.NewProverHeartbeat(readDB),
+	}
+}
+
+// Heartbeat records the heartbeat of the prover
+func (hc *HeartbeatController) Heartbeat(ctx *gin.Context) {
+	var param coordinatorType.HeartbeatParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if errCode, err := hc.Record(ctx, &param); err != nil {
+		types.RenderFailure(ctx, errCode, err)
+		return
+	}
+	types.RenderSuccess(ctx, &coordinatorType.HeartbeatSchema{Time: time.Now().Unix()})
+}
+
+// Record records the heartbeat of the prover of ctx, it returns the error code of the failure along with the error.
+func (hc *HeartbeatController) Record(ctx *gin.Context, param *coordinatorType.HeartbeatParameter) (int, error) {
+	if param.TaskProgress < 0 || param.TaskProgress > 100 {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("invalid task progress: %v", param.TaskProgress)
+	}
+	heartbeat := &orm.ProverHeartbeat{
+		ProverPublicKey: ctx.GetString(coordinatorType.PublicKey),
+		ProverName:      ctx.GetString(coordinatorType.ProverName),
+		ProverVersion:   ctx.GetString(coordinatorType.ProverVersion),
+		GPUMemoryMB:     param.GPUMemoryMB,
+		CircuitVersions: strings.Join(param.CircuitVersions, ","),
+		TaskProgress:    int16(param.TaskProgress),
+		RecentErrors:    param.RecentErrors,
+	}
+	if err := hc.proverHeartbeatOrm.UpsertProverHeartbeat(ctx, heartbeat); err != nil {
+		return types.ErrCoordinatorHeartbeatFailure, fmt.Errorf("failed to record heartbeat, err:%w", err)
+	}
+	return types.Success, nil
+}
+
+// ProverHeartbeatsParameter is the parameter of the prover heartbeats api.
+type ProverHeartbeatsParameter struct {
+	PublicKey string `form:"public_key" json:"public_key"`
+	Offset    int    `form:"offset" json:"offset" binding:"omitempty,min=0"`
+	Limit     int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// ProverHeartbeatSchema is the latest heartbeat of a prover.
+type ProverHeartbeatSchema struct {
+	ProverPublicKey string    `json:"prover_public_key"`
+	ProverName      string    `json:"prover_name"`
+	ProverVersion   string    `json:"prover_version"`
+	GPUMemoryMB     uint64    `json:"gpu_memory_mb"`
+	CircuitVersions []string  `json:"circuit_versions"`
+	TaskProgress    int16     `json:"task_progress"`
+	RecentErrors    uint64    `json:"recent_errors"`
+	LastSeenAt      time.Time `json:"last_seen_at"`
+}
+
+// ListProverHeartbeats lists the latest heartbeats of the provers, of a single prover if the public key is given
+func (hc *HeartbeatController) ListProverHeartbeats(ctx *gin.Context) {
+	var param ProverHeartbeatsParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultProverHeartbeatsLimit
+	}
+
+	heartbeats, err := hc.readProverHeartbeatOrm.ListProverHeartbeats(ctx, param.PublicKey, param.Offset, param.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorGetProverHeartbeatsFailure, fmt.Errorf("failed to get prover heartbeats, err:%w", err))
+		return
+	}
+
+	result := make([]ProverHeartbeatSchema, 0, len(heartbeats))
+	for _, h := range heartbeats {
+		circuitVersions := []string{}
+		if h.CircuitVersions != "" {
+			circuitVersions = strings.Split(h.CircuitVersions, ",")
+		}
+		result = append(result, ProverHeartbeatSchema{
+			ProverPublicKey: h.ProverPublicKey,
+			ProverName:      h.ProverName,
+			ProverVersion:   h.ProverVersion,
+			GPUMemoryMB:     h.GPUMemoryMB,
+			CircuitVersions: circuitVersions,
+			TaskProgress:    h.TaskProgress,
+			RecentErrors:    h.RecentErrors,
+			LastSeenAt:      h.UpdatedAt,
+		})
+	}
+	types.RenderSuccess(ctx, result)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/coordinator/internal/orm"
+)
+
+const (
+	defaultPipelineFailuresLimit     = 100
+	defaultPipelineFailuresWindowSec = 3600
+)
+
+// PipelineStatusController the proving pipeline status admin api controller
+type PipelineStatusController struct {
+	chunkOrm      *orm.Chunk
+	batchOrm      *orm.Batch
+	proverTaskOrm *orm.ProverTask
+}
+
+// NewPipelineStatusController create a proving pipeline status controller
+func NewPipelineStatusController(db *gorm.DB) *PipelineStatusController {
+	return &PipelineStatusController{
+		chunkOrm:      orm.NewChunk(db),
+		batchOrm:      orm.NewBatch(db),
+		proverTaskOrm: orm.NewProverTask(db),
+	}
+}
+
+// PipelineStatusParameter is the parameter of the proving pipeline status api.
+type PipelineStatusParameter struct {
+	FailuresWindowSec int `form:"failures_window_sec" json:"failures_window_sec" binding:"omitempty,min=1"`
+	FailuresLimit     int `form:"failures_limit" json:"failures_limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// TaskStatusCountsSchema is the number of tasks of a task type by proving status.
+type TaskStatusCountsSchema struct {
+	TaskType   string `json:"task_type"`
+	Unassigned int64  `json:"unassigned"`
+	Assigned   int64  `json:"assigned"`
+	Proved     int64  `json:"proved"`
+	Failed     int64  `json:"failed"`
+}
+
+// UnprovenChunkSchema is the oldest chunk waiting for its proof.
+type UnprovenChunkSchema struct {
+	Index  uint64 `json:"index"`
+	Hash   string `json:"hash"`
+	AgeSec uint64 `json:"age_sec"`
+}
+
+// ActiveTaskSchema is a task being proved by a prover.
+type ActiveTaskSchema struct {
+	TaskType   string    `json:"task_type"`
+	TaskID     string    `json:"task_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// ProverActiveTasksSchema is the tasks being proved by a prover.
+type ProverActiveTasksSchema struct {
+	ProverPublicKey string             `json:"prover_public_key"`
+	ProverName      string             `json:"prover_name"`
+	ProverVersion   string             `json:"prover_version"`
+	Tasks           []ActiveTaskSchema `json:"tasks"`
+}
+
+// ProverTaskFailureSchema is a prover task which failed.
+type ProverTaskFailureSchema struct {
+	TaskType        string    `json:"task_type"`
+	TaskID          string    `json:"task_id"`
+	ProverPublicKey string    `json:"prover_public_key"`
+	ProverName      string    `json:"prover_name"`
+	FailureType     string    `json:"failure_type"`
+	FailedAt        time.Time `json:"failed_at"`
+}
+
+// PipelineStatusSchema is the state of the proving pipeline.
+type PipelineStatusSchema struct {
+	Tasks []TaskStatusCountsSchema `json:"tasks"`
+	// OldestUnprovenChunk is nil when every chunk is proved.
+	OldestUnprovenChunk *UnprovenChunkSchema      `json:"oldest_unproven_chunk"`
+	Provers             []ProverActiveTasksSchema `json:"provers"`
+	RecentFailures      []ProverTaskFailureSchema `json:"recent_failures"`
+}
+
+// GetPipelineStatus returns the task counts of every task type, the oldest unproven chunk, the tasks being proved by
+// each prover and the prover tasks which failed recently
+func (psc *PipelineStatusController) GetPipelineStatus(ctx *gin.Context) {
+	var param PipelineStatusParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.FailuresWindowSec == 0 {
+		param.FailuresWindowSec = defaultPipelineFailuresWindowSec
+	}
+	if param.FailuresLimit == 0 {
+		param.FailuresLimit = defaultPipelineFailuresLimit
+	}
+
+	status, err := psc.pipelineStatus(ctx, param)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorGetPipelineStatusFailure, fmt.Errorf("failed to get proving pipeline status, err:%w", err))
+		return
+	}
+	types.RenderSuccess(ctx, status)
+}
+
+func (psc *PipelineStatusController) pipelineStatus(ctx context.Context, param PipelineStatusParameter) (*PipelineStatusSchema, error) {
+	chunkCounts, err := psc.chunkOrm.CountChunksByProvingStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batchCounts, err := psc.batchOrm.CountBatchesByProvingStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oldestChunk, err := psc.chunkOrm.GetOldestUnprovenChunk(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignedTasks, err := psc.proverTaskOrm.GetAssignedProverTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := utils.NowUTC()
+	failedTasks, err := psc.proverTaskOrm.GetRecentFailedProverTasks(ctx, now.Add(-time.Duration(param.FailuresWindowSec)*time.Second), param.FailuresLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &PipelineStatusSchema{
+		Tasks: []TaskStatusCountsSchema{
+			taskStatusCounts(message.ProofTypeChunk, chunkCounts),
+			taskStatusCounts(message.ProofTypeBatch, batchCounts),
+		},
+		Provers:        groupActiveTasksByProver(assignedTasks),
+		RecentFailures: make([]ProverTaskFailureSchema, 0, len(failedTasks)),
+	}
+	if oldestChunk != nil {
+		status.OldestUnprovenChunk = &UnprovenChunkSchema{Index: oldestChunk.Index, Hash: oldestChunk.Hash}
+		if age := now.Sub(oldestChunk.CreatedAt); age > 0 {
+			status.OldestUnprovenChunk.AgeSec = uint64(age.Seconds())
+		}
+	}
+	for _, task := range failedTasks {
+		status.RecentFailures = append(status.RecentFailures, ProverTaskFailureSchema{
+			TaskType:        message.ProofType(task.TaskType).String(),
+			TaskID:          task.TaskID,
+			ProverPublicKey: task.ProverPublicKey,
+			ProverName:      task.ProverName,
+			FailureType:     types.ProverTaskFailureType(task.FailureType).String(),
+			FailedAt:        task.UpdatedAt,
+		})
+	}
+	return status, nil
+}
+
+func taskStatusCounts(taskType message.ProofType, counts map[types.ProvingStatus]int64) TaskStatusCountsSchema {
+	return TaskStatusCountsSchema{
+		TaskType:   taskType.String(),
+		Unassigned: counts[types.ProvingTaskUnassigned],
+		Assigned:   counts[types.ProvingTaskAssigned],
+		Proved:     counts[types.ProvingTaskVerified],
+		Failed:     counts[types.ProvingTaskFailed],
+	}
+}
+
+// groupActiveTasksByProver groups the assigned prover tasks, ordered by prover, by prover.
+func groupActiveTasksByProver(assignedTasks []orm.ProverTask) []ProverActiveTasksSchema {
+	provers := make([]ProverActiveTasksSchema, 0)
+	for _, task := range assignedTasks {
+		if len(provers) == 0 || provers[len(provers)-1].ProverPublicKey != task.ProverPublicKey {
+			provers = append(provers, ProverActiveTasksSchema{
+				ProverPublicKey: task.ProverPublicKey,
+				ProverName:      task.ProverName,
+				ProverVersion:   task.ProverVersion,
+			})
+		}
+		prover := &provers[len(provers)-1]
+		prover.Tasks = append(prover.Tasks, ActiveTaskSchema{
+			TaskType:   message.ProofType(task.TaskType).String(),
+			TaskID:     task.TaskID,
+			AssignedAt: task.AssignedAt,
+		})
+	}
+	return provers
+}
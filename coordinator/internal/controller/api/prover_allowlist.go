@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/coordinator/internal/orm"
+)
+
+const defaultProverAllowlistLimit = 100
+
+var errNotInProverAllowlist = errors.New("not in the prover allowlist")
+
+// ProverAllowlistController the prover allowlist and token revocation admin api controller
+type ProverAllowlistController struct {
+	db                 *gorm.DB
+	proverAllowlistOrm *orm.ProverAllowlist
+	tokenRevocationOrm *orm.ProverTokenRevocation
+}
+
+// NewProverAllowlistController create a prover allowlist controller
+func NewProverAllowlistController(db *gorm.DB) *ProverAllowlistController {
+	return &ProverAllowlistController{
+		db:                 db,
+		proverAllowlistOrm: orm.NewProverAllowlist(db),
+		tokenRevocationOrm: orm.NewProverTokenRevocation(db),
+	}
+}
+
+// ProverAllowlistParameter is the parameter of the prover allowlist listing api.
+type ProverAllowlistParameter struct {
+	Offset int `form:"offset" json:"offset" binding:"omitempty,min=0"`
+	Limit  int `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// ProverPublicKeyParameter is the parameter of the apis updating the allowlist or the tokens of a prover.
+type ProverPublicKeyParameter struct {
+	PublicKey  string `form:"public_key" json:"public_key" binding:"required"`
+	ProverName string `form:"prover_name" json:"prover_name"`
+}
+
+// ProverAllowlistSchema is a prover allowed to login.
+type ProverAllowlistSchema struct {
+	PublicKey  string    `json:"public_key"`
+	ProverName string    `json:"prover_name"`
+	AllowedAt  time.Time `json:"allowed_at"`
+}
+
+// ListProverAllowlist lists the provers of the allowlist
+func (pac *ProverAllowlistController) ListProverAllowlist(ctx *gin.Context) {
+	var param ProverAllowlistParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultProverAllowlistLimit
+	}
+
+	provers, err := pac.proverAllowlistOrm.ListProverPublicKeys(ctx, param.Offset, param.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorGetProverAllowlistFailure, fmt.Errorf("failed to get prover allowlist, err:%w", err))
+		return
+	}
+
+	result := make([]ProverAllowlistSchema, 0, len(provers))
+	for _, p := range provers {
+		result = append(result, ProverAllowlistSchema{
+			PublicKey:  p.PublicKey,
+			ProverName: p.ProverName,
+			AllowedAt:  p.CreatedAt,
+		})
+	}
+	types.RenderSuccess(ctx, result)
+}
+
+// AddProverAllowlist adds a prover to the allowlist
+func (pac *ProverAllowlistController) AddProverAllowlist(ctx *gin.Context) {
+	var param ProverPublicKeyParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	if err := pac.proverAllowlistOrm.InsertProverPublicKey(ctx, param.ProverName, param.PublicKey); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorUpdateProverAllowlistFailure, fmt.Errorf("failed to add prover to allowlist, err:%w", err))
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// RemoveProverAllowlist removes a prover from the allowlist and revokes its tokens, so that it is logged out at once
+func (pac *ProverAllowlistController) RemoveProverAllowlist(ctx *gin.Context) {
+	var param ProverPublicKeyParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	err := pac.db.Transaction(func(tx *gorm.DB) error {
+		removed, err := orm.NewProverAllowlist(tx).DeleteProverPublicKey(ctx, param.PublicKey)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return errNotInProverAllowlist
+		}
+		return pac.tokenRevocationOrm.RevokeTokens(ctx, param.PublicKey, utils.NowUTC(), tx)
+	})
+	if errors.Is(err, errNotInProverAllowlist) {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("public key %s: %w", param.PublicKey, err))
+		return
+	}
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorUpdateProverAllowlistFailure, fmt.Errorf("failed to remove prover from allowlist, err:%w", err))
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// RevokeTokens revokes the tokens issued to a prover so far, it has to login again
+func (pac *ProverAllowlistController) RevokeTokens(ctx *gin.Context) {
+	var param ProverPublicKeyParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	if err := pac.tokenRevocationOrm.RevokeTokens(ctx, param.PublicKey, utils.NowUTC()); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorRevokeTokensFailure, fmt.Errorf("failed to revoke prover tokens, err:%w", err))
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
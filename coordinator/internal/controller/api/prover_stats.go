@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/orm"
+)
+
+const defaultProverStatsLimit = 100
+
+// ProverStatsController the prover statistics admin api controller
+type ProverStatsController struct {
+	proverStatsOrm *orm.ProverStats
+}
+
+// NewProverStatsController create a prover statistics controller
+func NewProverStatsController(db *gorm.DB) *ProverStatsController {
+	return &ProverStatsController{
+		proverStatsOrm: orm.NewProverStats(db),
+	}
+}
+
+// ProverStatsParameter is the parameter of the prover statistics api.
+type ProverStatsParameter struct {
+	PublicKey string `form:"public_key" json:"public_key"`
+	Offset    int    `form:"offset" json:"offset" binding:"omitempty,min=0"`
+	Limit     int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// ProverStatsSchema is the statistics of a prover for a proof type.
+type ProverStatsSchema struct {
+	ProverPublicKey       string  `json:"prover_public_key"`
+	ProverName            string  `json:"prover_name"`
+	TaskType              string  `json:"task_type"`
+	TasksSucceeded        uint64  `json:"tasks_succeeded"`
+	TasksFailed           uint64  `json:"tasks_failed"`
+	TasksTimeout          uint64  `json:"tasks_timeout"`
+	InvalidProofs         uint64  `json:"invalid_proofs"`
+	SuccessRate           float64 `json:"success_rate"`
+	AverageProvingTimeSec float64 `json:"average_proving_time_sec"`
+}
+
+// ListProverStats lists the statistics of the provers, of a single prover if the public key is given
+func (psc *ProverStatsController) ListProverStats(ctx *gin.Context) {
+	var param ProverStatsParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrProverStatsAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultProverStatsLimit
+	}
+
+	stats, err := psc.proverStatsOrm.ListProverStats(ctx, param.PublicKey, param.Offset, param.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrProverStatsAPIGetProverStatsFailure, fmt.Errorf("failed to get prover stats, err:%w", err))
+		return
+	}
+
+	result := make([]ProverStatsSchema, 0, len(stats))
+	for _, s := range stats {
+		var successRate float64
+		if finished := s.FinishedTasks(); finished > 0 {
+			successRate = float64(s.TasksSucceeded) / float64(finished)
+		}
+		result = append(result, ProverStatsSchema{
+			ProverPublicKey:       s.ProverPublicKey,
+			ProverName:            s.ProverName,
+			TaskType:              message.ProofType(s.TaskType).String(),
+			TasksSucceeded:        s.TasksSucceeded,
+			TasksFailed:           s.TasksFailed,
+			TasksTimeout:          s.TasksTimeout,
+			InvalidProofs:         s.InvalidProofs,
+			SuccessRate:           successRate,
+			AverageProvingTimeSec: s.AverageProvingTimeSec(),
+		})
+	}
+	types.RenderSuccess(ctx, result)
+}
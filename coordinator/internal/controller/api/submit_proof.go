@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +39,16 @@ func (spc *SubmitProofController) SubmitProof(ctx *gin.Context) {
 		return
 	}
 
+	errCode, err := spc.Submit(ctx, &spp)
+	if err != nil {
+		types.RenderFailure(ctx, errCode, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// Submit handles a proof submitted by the prover of ctx, it returns the error code of the failure along with the error.
+func (spc *SubmitProofController) Submit(ctx *gin.Context, spp *coordinatorType.SubmitProofParameter) (int, error) {
 	proofMsg := message.ProofMsg{
 		ProofDetail: &message.ProofDetail{
 			ID:     spp.TaskID,
@@ -52,25 +63,25 @@ func (spc *SubmitProofController) SubmitProof(ctx *gin.Context) {
 			var tmpChunkProof message.ChunkProof
 			if err := json.Unmarshal([]byte(spp.Proof), &tmpChunkProof); err != nil {
 				nerr := fmt.Errorf("unmarshal parameter chunk proof invalid, err:%w", err)
-				types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
-				return
+				return types.ErrCoordinatorParameterInvalidNo, nerr
 			}
 			proofMsg.ChunkProof = &tmpChunkProof
 		case message.ProofTypeBatch:
 			var tmpBatchProof message.BatchProof
 			if err := json.Unmarshal([]byte(spp.Proof), &tmpBatchProof); err != nil {
 				nerr := fmt.Errorf("unmarshal parameter batch proof invalid, err:%w", err)
-				types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
-				return
+				return types.ErrCoordinatorParameterInvalidNo, nerr
 			}
 			proofMsg.BatchProof = &tmpBatchProof
 		}
 	}
 
-	if err := spc.submitProofReceiverLogic.HandleZkProof(ctx, &proofMsg, spp); err != nil {
+	if err := spc.submitProofReceiverLogic.HandleZkProof(ctx, &proofMsg, *spp); err != nil {
 		nerr := fmt.Errorf("handle zk proof failure, err:%w", err)
-		types.RenderFailure(ctx, types.ErrCoordinatorHandleZkProofFailure, nerr)
-		return
+		if errors.Is(err, submitproof.ErrValidatorSuccessInvalidProof) || errors.Is(err, submitproof.ErrValidatorFailureVerifiedFailed) {
+			return types.ErrCoordinatorInvalidProof, nerr
+		}
+		return types.ErrCoordinatorHandleZkProofFailure, nerr
 	}
-	types.RenderSuccess(ctx, nil)
+	return types.Success, nil
 }
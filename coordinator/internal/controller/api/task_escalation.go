@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/orm"
+)
+
+const defaultTaskEscalationsLimit = 100
+
+// TaskEscalationController the escalated tasks admin api controller
+type TaskEscalationController struct {
+	taskEscalationOrm *orm.TaskEscalation
+}
+
+// NewTaskEscalationController create an escalated tasks controller
+func NewTaskEscalationController(db *gorm.DB) *TaskEscalationController {
+	return &TaskEscalationController{
+		taskEscalationOrm: orm.NewTaskEscalation(db),
+	}
+}
+
+// TaskEscalationsParameter is the parameter of the escalated tasks api.
+type TaskEscalationsParameter struct {
+	Offset int `form:"offset" json:"offset" binding:"omitempty,min=0"`
+	Limit  int `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// TaskEscalationSchema is a task which failed too many times.
+type TaskEscalationSchema struct {
+	TaskType    string    `json:"task_type"`
+	TaskID      string    `json:"task_id"`
+	Failures    int16     `json:"failures"`
+	EscalatedAt time.Time `json:"escalated_at"`
+}
+
+// ListTaskEscalations lists the escalated tasks, the latest escalated first
+func (tec *TaskEscalationController) ListTaskEscalations(ctx *gin.Context) {
+	var param TaskEscalationsParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultTaskEscalationsLimit
+	}
+
+	escalations, err := tec.taskEscalationOrm.GetTaskEscalations(ctx, param.Offset, param.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorGetTaskEscalationsFailure, fmt.Errorf("failed to get escalated tasks, err:%w", err))
+		return
+	}
+
+	result := make([]TaskEscalationSchema, 0, len(escalations))
+	for _, e := range escalations {
+		result = append(result, TaskEscalationSchema{
+			TaskType:    message.ProofType(e.TaskType).String(),
+			TaskID:      e.TaskID,
+			Failures:    e.Failures,
+			EscalatedAt: e.CreatedAt,
+		})
+	}
+	types.RenderSuccess(ctx, result)
+}
@@ -0,0 +1,46 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/utils"
+)
+
+func (c *Collector) cleanupTaskPayloads() {
+	defer func() {
+		if err := recover(); err != nil {
+			nerr := fmt.Errorf("clean task payloads panic error: %v", err)
+			log.Warn(nerr.Error())
+		}
+	}()
+
+	if c.taskPayload == nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Minute * 10)
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := c.taskPayload.Cleanup(c.ctx, utils.NowUTC())
+			if err != nil {
+				log.Error("delete expired task payloads failure", "deleted", deleted, "error", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Info("deleted expired task payloads", "deleted", deleted)
+			}
+		case <-c.ctx.Done():
+			if c.ctx.Err() != nil {
+				log.Error("manager context canceled with error", "error", c.ctx.Err())
+			}
+			return
+		case <-c.stopTimeoutChan:
+			log.Info("the coordinator run loop exit")
+			return
+		}
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"scroll-tech/common/types/message"
 
 	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/escalation"
+	"scroll-tech/coordinator/internal/logic/payload"
 	"scroll-tech/coordinator/internal/orm"
 )
 
@@ -25,10 +27,15 @@ type Collector struct {
 
 	stopTimeoutChan chan struct{}
 
-	proverTaskOrm *orm.ProverTask
-	chunkOrm      *orm.Chunk
-	batchOrm      *orm.Batch
-	challenge     *orm.Challenge
+	proverTaskOrm  *orm.ProverTask
+	proverStatsOrm *orm.ProverStats
+	chunkOrm       *orm.Chunk
+	batchOrm       *orm.Batch
+	challenge      *orm.Challenge
+	// escalator is nil when task escalation is disabled.
+	escalator *escalation.Escalator
+	// taskPayload is nil when the payload storage is not configured.
+	taskPayload *payload.Offloader
 
 	timeoutBatchCheckerRunTotal     prometheus.Counter
 	batchProverTaskTimeoutTotal     prometheus.Counter
@@ -39,15 +46,23 @@ type Collector struct {
 
 // NewCollector create a collector to cron collect the data to send to prover
 func NewCollector(ctx context.Context, db *gorm.DB, cfg *config.Config, reg prometheus.Registerer) *Collector {
+	taskPayload, err := payload.NewOffloader(cfg.PayloadStorage, reg)
+	if err != nil {
+		log.Crit("failed to create the task payload offloader", "err", err)
+	}
+
 	c := &Collector{
 		cfg:             cfg,
 		db:              db,
 		ctx:             ctx,
 		stopTimeoutChan: make(chan struct{}),
 		proverTaskOrm:   orm.NewProverTask(db),
+		proverStatsOrm:  orm.NewProverStats(db),
 		chunkOrm:        orm.NewChunk(db),
 		batchOrm:        orm.NewBatch(db),
 		challenge:       orm.NewChallenge(db),
+		escalator:       escalation.NewEscalator(cfg.ProverManager.TaskEscalation, db, reg),
+		taskPayload:     taskPayload,
 
 		timeoutBatchCheckerRunTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_batch_timeout_checker_run_total",
@@ -75,6 +90,7 @@ func NewCollector(ctx context.Context, db *gorm.DB, cfg *config.Config, reg prom
 	go c.timeoutChunkProofTask()
 	go c.checkBatchAllChunkReady()
 	go c.cleanupChallenge()
+	go c.cleanupTaskPayloads()
 
 	log.Info("Start coordinator cron successfully.")
 
@@ -161,17 +177,29 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 			log.Warn("Task timeout more than once", "taskType", message.ProofType(assignedProverTask.TaskType).String(), "hash", assignedProverTask.TaskID)
 		}
 
-		timeout.Inc()
-
-		log.Warn("proof task have reach the timeout", "task id", assignedProverTask.TaskID,
-			"prover public key", assignedProverTask.ProverPublicKey, "prover name", assignedProverTask.ProverName, "task type", assignedProverTask.TaskType)
-
+		handled := false
 		err := c.db.Transaction(func(tx *gorm.DB) error {
+			// another coordinator instance may be handling the same timeout.
+			locked, err := c.proverTaskOrm.LockAssignedProverTask(c.ctx, assignedProverTask.UUID, tx)
+			if err != nil {
+				log.Error("lock prover task failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
+				return err
+			}
+			if !locked {
+				return nil
+			}
+			handled = true
+
 			if err := c.proverTaskOrm.UpdateProverTaskProvingStatusAndFailureType(c.ctx, assignedProverTask.UUID, types.ProverProofInvalid, types.ProverTaskFailureTypeTimeout, tx); err != nil {
 				log.Error("update prover task proving status failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
 				return err
 			}
 
+			if err := c.proverStatsOrm.IncreaseProverStats(c.ctx, assignedProverTask.ProverPublicKey, assignedProverTask.ProverName, message.ProofType(assignedProverTask.TaskType), types.ProverTaskFailureTypeTimeout, 0, tx); err != nil {
+				log.Error("increase prover stats failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
+				return err
+			}
+
 			switch message.ProofType(assignedProverTask.TaskType) {
 			case message.ProofTypeChunk:
 				if err := c.chunkOrm.DecreaseActiveAttemptsByHash(c.ctx, assignedProverTask.TaskID, tx); err != nil {
@@ -199,7 +227,18 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 		})
 		if err != nil {
 			log.Error("check task proof is timeout failure", "error", err)
+			continue
+		}
+		if !handled {
+			continue
 		}
+
+		timeout.Inc()
+
+		log.Warn("proof task have reach the timeout", "task id", assignedProverTask.TaskID,
+			"prover public key", assignedProverTask.ProverPublicKey, "prover name", assignedProverTask.ProverName, "task type", assignedProverTask.TaskType)
+
+		c.escalator.CheckTask(c.ctx, message.ProofType(assignedProverTask.TaskType), assignedProverTask.TaskID)
 	}
 }
 
@@ -0,0 +1,21 @@
+package rpc
+
+import "encoding/json"
+
+// jsonCodec encodes the grpc messages in json, the messages are the same as the http api.
+type jsonCodec struct{}
+
+// Marshal implements encoding.Codec.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements encoding.Codec.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name implements encoding.Codec, the clients call with the "json" content subtype.
+func (jsonCodec) Name() string {
+	return "json"
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// NewServer creates the grpc server of the provers serving srv, the provers must present a client certificate
+// signed by the configured client CA.
+func NewServer(cfg *config.GRPC, srv CoordinatorServer) (*grpc.Server, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load grpc server certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(filepath.Clean(cfg.ClientCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read grpc client ca: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in grpc client ca %s", cfg.ClientCAFile)
+	}
+
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})
+	server := grpc.NewServer(grpc.Creds(creds), grpc.ForceServerCodec(jsonCodec{}))
+	server.RegisterService(&serviceDesc, srv)
+	return server, nil
+}
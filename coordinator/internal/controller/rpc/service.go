@@ -0,0 +1,244 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// serviceName is the full name of the grpc service of the provers.
+const serviceName = "coordinator.v1.Coordinator"
+
+// CoordinatorServer is the grpc service of the provers, it shares the controllers of the http api.
+type CoordinatorServer interface {
+	GetTask(ctx context.Context, para *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error)
+	SubmitProof(ctx context.Context, para *coordinatorType.SubmitProofParameter) (*struct{}, error)
+	// Heartbeat records each HeartbeatParameter sent by the prover and answers it with a HeartbeatSchema until the
+	// prover closes the stream, the open streams are the provers connected.
+	Heartbeat(stream grpc.ServerStream) error
+}
+
+// Service implements CoordinatorServer.
+type Service struct {
+	getTask     *api.GetTaskController
+	submitProof *api.SubmitProofController
+	heartbeat   *api.HeartbeatController
+	rateLimiter *ratelimit.Limiter
+
+	connectedProvers prometheus.Gauge
+	heartbeatTotal   prometheus.Counter
+}
+
+// NewService creates a new Service instance.
+func NewService(getTask *api.GetTaskController, submitProof *api.SubmitProofController, heartbeat *api.HeartbeatController, rateLimiter *ratelimit.Limiter, reg prometheus.Registerer) *Service {
+	return &Service{
+		getTask:     getTask,
+		submitProof: submitProof,
+		heartbeat:   heartbeat,
+		rateLimiter: rateLimiter,
+		connectedProvers: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "coordinator_grpc_connected_provers",
+			Help: "The number of provers with an open grpc heartbeat stream.",
+		}),
+		heartbeatTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_grpc_heartbeat_total",
+			Help: "Total number of heartbeats received from the provers.",
+		}),
+	}
+}
+
+// GetTask implements CoordinatorServer.
+func (s *Service) GetTask(ctx context.Context, para *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
+	proverCtx, err := proverContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := proverCtx.GetString(coordinatorType.PublicKey)
+	if !s.rateLimiter.Allow(ratelimit.EndpointGetTask, publicKey) {
+		return nil, toStatus(types.ErrCoordinatorRateLimited, errors.New("too many get_task requests"))
+	}
+	allowed, err := s.rateLimiter.AllowTask(ctx, publicKey)
+	if err != nil {
+		return nil, toStatus(types.ErrCoordinatorGetTaskFailure, err)
+	}
+	if !allowed {
+		return nil, toStatus(types.ErrCoordinatorRateLimited, fmt.Errorf("prover with publicKey %s is already assigned the maximum number of tasks", publicKey))
+	}
+	result, errCode, err := s.getTask.GetTask(proverCtx, para)
+	if err != nil {
+		return nil, toStatus(errCode, err)
+	}
+	return result, nil
+}
+
+// SubmitProof implements CoordinatorServer.
+func (s *Service) SubmitProof(ctx context.Context, para *coordinatorType.SubmitProofParameter) (*struct{}, error) {
+	proverCtx, err := proverContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !s.rateLimiter.Allow(ratelimit.EndpointSubmitProof, proverCtx.GetString(coordinatorType.PublicKey)) {
+		return nil, toStatus(types.ErrCoordinatorRateLimited, errors.New("too many submit_proof requests"))
+	}
+	if err = binding.Validator.ValidateStruct(para); err != nil {
+		return nil, toStatus(types.ErrCoordinatorParameterInvalidNo, err)
+	}
+	if errCode, err := s.submitProof.Submit(proverCtx, para); err != nil {
+		return nil, toStatus(errCode, err)
+	}
+	return &struct{}{}, nil
+}
+
+// Heartbeat implements CoordinatorServer.
+func (s *Service) Heartbeat(stream grpc.ServerStream) error {
+	proverCtx, err := proverContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	proverName := proverCtx.GetString(coordinatorType.ProverName)
+
+	s.connectedProvers.Inc()
+	defer s.connectedProvers.Dec()
+	log.Info("prover connected to the grpc api", "prover name", proverName, "public key", proverCtx.GetString(coordinatorType.PublicKey))
+
+	for {
+		var heartbeat coordinatorType.HeartbeatParameter
+		if err := stream.RecvMsg(&heartbeat); err != nil {
+			if errors.Is(err, io.EOF) {
+				log.Info("prover disconnected from the grpc api", "prover name", proverName)
+				return nil
+			}
+			log.Warn("prover heartbeat stream broken", "prover name", proverName, "err", err)
+			return err
+		}
+		s.heartbeatTotal.Inc()
+		if _, err := s.heartbeat.Record(proverCtx, &heartbeat); err != nil {
+			log.Warn("failed to record prover heartbeat", "prover name", proverName, "err", err)
+		}
+
+		if err := stream.SendMsg(&coordinatorType.HeartbeatSchema{Time: time.Now().Unix()}); err != nil {
+			log.Warn("failed to answer prover heartbeat", "prover name", proverName, "err", err)
+			return err
+		}
+	}
+}
+
+// proverContext returns the context the controllers expect for the prover of a grpc call. The prover is identified by
+// the public_key, prover_name and prover_version metadata, the prover name must be the common name of its verified
+// client certificate. The optional circuit_version metadata advertises its circuit version.
+func proverContext(ctx context.Context) (*gin.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no peer information")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no verified client certificate")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := make(map[string]string)
+	for _, key := range []string{coordinatorType.PublicKey, coordinatorType.ProverName, coordinatorType.ProverVersion} {
+		v := md.Get(key)
+		if len(v) == 0 || v[0] == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "missing %s metadata", key)
+		}
+		values[key] = v[0]
+	}
+
+	if commonName := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName; commonName != values[coordinatorType.ProverName] {
+		return nil, status.Errorf(codes.PermissionDenied, "prover name %s does not match the client certificate %s", values[coordinatorType.ProverName], commonName)
+	}
+
+	proverCtx := &gin.Context{Request: (&http.Request{}).WithContext(ctx)}
+	for key, value := range values {
+		proverCtx.Set(key, value)
+	}
+	// the circuit version is only required by the coordinators routing the tasks by circuit version.
+	if v := md.Get(coordinatorType.CircuitVersion); len(v) > 0 {
+		proverCtx.Set(coordinatorType.CircuitVersion, v[0])
+	}
+	return proverCtx, nil
+}
+
+// toStatus converts the error code of the http api to a grpc status, the code prefixes the message.
+func toStatus(errCode int, err error) error {
+	code := codes.Internal
+	switch errCode {
+	case types.ErrCoordinatorParameterInvalidNo, types.ErrCoordinatorInvalidProof:
+		code = codes.InvalidArgument
+	case types.ErrCoordinatorEmptyProofData:
+		code = codes.NotFound
+	case types.ErrCoordinatorRateLimited:
+		code = codes.ResourceExhausted
+	}
+	return status.Errorf(code, "%d: %v", errCode, err)
+}
+
+func getTaskHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(coordinatorType.GetTaskParameter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).GetTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/GetTask"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).GetTask(ctx, req.(*coordinatorType.GetTaskParameter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func submitProofHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(coordinatorType.SubmitProofParameter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).SubmitProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/SubmitProof"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).SubmitProof(ctx, req.(*coordinatorType.SubmitProofParameter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func heartbeatHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CoordinatorServer).Heartbeat(stream)
+}
+
+// serviceDesc describes CoordinatorServer, the messages are json encoded so there is no protobuf definition.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*CoordinatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetTask", Handler: getTaskHandler},
+		{MethodName: "SubmitProof", Handler: submitProofHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Heartbeat", Handler: heartbeatHandler, ServerStreams: true, ClientStreams: true},
+	},
+}
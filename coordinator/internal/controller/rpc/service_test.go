@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"scroll-tech/common/types"
+
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func TestProverContext(t *testing.T) {
+	md := metadata.Pairs(coordinatorType.PublicKey, "0x1234", coordinatorType.ProverName, "prover-0", coordinatorType.ProverVersion, "v4.0.0")
+	withPeer := func(commonName string) context.Context {
+		state := tls.ConnectionState{}
+		if commonName != "" {
+			state.VerifiedChains = [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}}
+		}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+
+	proverCtx, err := proverContext(metadata.NewIncomingContext(withPeer("prover-0"), md))
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1234", proverCtx.GetString(coordinatorType.PublicKey))
+	assert.Equal(t, "prover-0", proverCtx.GetString(coordinatorType.ProverName))
+	assert.Equal(t, "v4.0.0", proverCtx.GetString(coordinatorType.ProverVersion))
+	assert.Empty(t, proverCtx.GetString(coordinatorType.CircuitVersion))
+
+	md.Set(coordinatorType.CircuitVersion, "v0.12.0")
+	proverCtx, err = proverContext(metadata.NewIncomingContext(withPeer("prover-0"), md))
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.12.0", proverCtx.GetString(coordinatorType.CircuitVersion))
+
+	_, err = proverContext(metadata.NewIncomingContext(withPeer(""), md))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the prover name must be the common name of the client certificate.
+	_, err = proverContext(metadata.NewIncomingContext(withPeer("prover-1"), md))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = proverContext(metadata.NewIncomingContext(withPeer("prover-0"), metadata.Pairs(coordinatorType.ProverName, "prover-0")))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestToStatus(t *testing.T) {
+	err := toStatus(types.ErrCoordinatorEmptyProofData, errors.New("get empty prover task"))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "20004: get empty prover task", status.Convert(err).Message())
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(toStatus(types.ErrCoordinatorInvalidProof, errors.New("invalid"))))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(toStatus(types.ErrCoordinatorRateLimited, errors.New("too many requests"))))
+	assert.Equal(t, codes.Internal, status.Code(toStatus(types.ErrCoordinatorGetTaskFailure, errors.New("failure"))))
+}
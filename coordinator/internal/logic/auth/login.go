@@ -1,21 +1,33 @@
 package auth
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/orm"
 )
 
 // LoginLogic the auth logic
 type LoginLogic struct {
-	challengeOrm *orm.Challenge
+	cfg *config.Auth
+
+	challengeOrm       *orm.Challenge
+	proverAllowlistOrm *orm.ProverAllowlist
+	tokenRevocationOrm *orm.ProverTokenRevocation
 }
 
 // NewLoginLogic new a LoginLogic
-func NewLoginLogic(db *gorm.DB) *LoginLogic {
+func NewLoginLogic(cfg *config.Auth, db *gorm.DB) *LoginLogic {
 	return &LoginLogic{
-		challengeOrm: orm.NewChallenge(db),
+		cfg:                cfg,
+		challengeOrm:       orm.NewChallenge(db),
+		proverAllowlistOrm: orm.NewProverAllowlist(db),
+		tokenRevocationOrm: orm.NewProverTokenRevocation(db),
 	}
 }
 
@@ -23,3 +35,27 @@ func NewLoginLogic(db *gorm.DB) *LoginLogic {
 func (l *LoginLogic) InsertChallengeString(ctx *gin.Context, challenge string) error {
 	return l.challengeOrm.InsertChallenge(ctx, challenge)
 }
+
+// CheckAllowlist checks the public key is in the allowlist when the coordinator enforces it
+func (l *LoginLogic) CheckAllowlist(ctx context.Context, publicKey string) error {
+	if l.cfg == nil || !l.cfg.EnforceAllowlist {
+		return nil
+	}
+	allowed, err := l.proverAllowlistOrm.IsPublicKeyAllowed(ctx, publicKey)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("public key %s is not in the prover allowlist", publicKey)
+	}
+	return nil
+}
+
+// IsTokenRevoked checks whether the tokens of the public key issued by the login at loginAt were revoked
+func (l *LoginLogic) IsTokenRevoked(ctx context.Context, publicKey string, loginAt time.Time) (bool, error) {
+	revokedAt, err := l.tokenRevocationOrm.GetRevokedAt(ctx, publicKey)
+	if err != nil {
+		return false, err
+	}
+	return revokedAt != nil && loginAt.Unix() <= revokedAt.Unix(), nil
+}
@@ -0,0 +1,172 @@
+package escalation
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+const (
+	// signatureHeader carries the hex encoded HMAC-SHA256 of the request body.
+	signatureHeader = "X-Scroll-Signature"
+
+	defaultWebhookTimeoutSec = 5
+)
+
+type escalatorMetrics struct {
+	taskEscalatedTotal *prometheus.CounterVec
+	alertFailedTotal   prometheus.Counter
+}
+
+var (
+	initEscalatorMetricsOnce sync.Once
+	metrics                  *escalatorMetrics
+)
+
+func initEscalatorMetrics(reg prometheus.Registerer) *escalatorMetrics {
+	initEscalatorMetricsOnce.Do(func() {
+		metrics = &escalatorMetrics{
+			taskEscalatedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "coordinator_task_escalated_total",
+				Help: "Total number of tasks escalated after too many failures.",
+			}, []string{"task_type"}),
+			alertFailedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "coordinator_task_escalation_alert_failed_total",
+				Help: "Total number of task escalation alerts which failed to be delivered.",
+			}),
+		}
+	})
+	return metrics
+}
+
+// Alert is the body of the alert posted to the webhook when a task is escalated.
+type Alert struct {
+	TaskType  string `json:"task_type"`
+	TaskID    string `json:"task_id"`
+	Failures  int64  `json:"failures"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Escalator records the tasks which failed too many times as needing attention and alerts the webhook.
+type Escalator struct {
+	cfg *config.TaskEscalation
+
+	proverTaskOrm     *orm.ProverTask
+	taskEscalationOrm *orm.TaskEscalation
+
+	// client is nil when no webhook is configured.
+	client *resty.Client
+
+	metrics *escalatorMetrics
+}
+
+// NewEscalator creates the escalator configured by cfg, a nil cfg returns a nil escalator which escalates nothing.
+func NewEscalator(cfg *config.TaskEscalation, db *gorm.DB, reg prometheus.Registerer) *Escalator {
+	if cfg == nil {
+		return nil
+	}
+
+	e := &Escalator{
+		cfg:               cfg,
+		proverTaskOrm:     orm.NewProverTask(db),
+		taskEscalationOrm: orm.NewTaskEscalation(db),
+		metrics:           initEscalatorMetrics(reg),
+	}
+	if cfg.Webhook != nil && cfg.Webhook.URL != "" {
+		timeoutSec := cfg.Webhook.TimeoutSec
+		if timeoutSec == 0 {
+			timeoutSec = defaultWebhookTimeoutSec
+		}
+		e.client = resty.New().SetTimeout(time.Duration(timeoutSec) * time.Second)
+	}
+	return e
+}
+
+// CheckTask escalates a task which just failed once MaxFailures of its prover tasks failed. A task is only escalated
+// once, it is still reassigned until it runs out of session attempts.
+func (e *Escalator) CheckTask(ctx context.Context, taskType message.ProofType, taskID string) {
+	if e == nil {
+		return
+	}
+
+	failures, err := e.proverTaskOrm.CountFailedProverTasks(ctx, taskType, taskID)
+	if err != nil {
+		log.Error("failed to count failed prover tasks", "task type", taskType.String(), "task id", taskID, "err", err)
+		return
+	}
+	if failures < int64(e.cfg.MaxFailures) {
+		return
+	}
+
+	escalated, err := e.taskEscalationOrm.InsertTaskEscalation(ctx, taskType, taskID, int16(failures))
+	if err != nil {
+		log.Error("failed to escalate task", "task type", taskType.String(), "task id", taskID, "err", err)
+		return
+	}
+	if !escalated {
+		return
+	}
+
+	e.metrics.taskEscalatedTotal.WithLabelValues(taskType.String()).Inc()
+	log.Error("task failed too many times, it needs attention", "task type", taskType.String(), "task id", taskID, "failures", failures)
+
+	if e.client != nil {
+		go e.alert(&Alert{
+			TaskType:  taskType.String(),
+			TaskID:    taskID,
+			Failures:  failures,
+			Timestamp: time.Now().Unix(),
+		})
+	}
+}
+
+// alert posts an alert to the webhook.
+func (e *Escalator) alert(alert *Alert) {
+	if err := e.deliver(alert); err != nil {
+		e.metrics.alertFailedTotal.Inc()
+		log.Warn("failed to deliver task escalation alert", "task type", alert.TaskType, "task id", alert.TaskID, "err", err)
+	}
+}
+
+func (e *Escalator) deliver(alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert, err: %w", err)
+	}
+
+	req := e.client.R().SetHeader("Content-Type", "application/json").SetBody(body)
+	if e.cfg.Webhook.Secret != "" {
+		req.SetHeader(signatureHeader, signBody([]byte(e.cfg.Webhook.Secret), body))
+	}
+	resp, err := req.Post(e.cfg.Webhook.URL)
+	if err != nil {
+		return fmt.Errorf("failed to post alert, err: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("failed to post alert, status: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// signBody returns the hex encoded HMAC-SHA256 of body keyed by secret.
+func signBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
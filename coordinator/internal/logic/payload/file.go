@@ -0,0 +1,138 @@
+package payload
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// FileRoute is the route of the payloads served by the coordinator v1 api.
+const FileRoute = "/task_payload"
+
+// fileStore stores the payloads as files served by the coordinator api, their URLs are signed with an HMAC-SHA256 of
+// the key and the expiry.
+type fileStore struct {
+	cfg *config.FileStorage
+	now func() time.Time
+}
+
+func newFileStore(cfg *config.FileStorage) *fileStore {
+	return &fileStore{cfg: cfg, now: time.Now}
+}
+
+// path returns the file of the key, an error if the key escapes the directory.
+func (s *fileStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", fmt.Errorf("invalid payload key: %q", key)
+	}
+	return filepath.Join(s.cfg.Dir, filepath.FromSlash(clean)), nil
+}
+
+// Put implements Store, the payload is written to a temporary file first so that it is never served partially.
+func (s *fileStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// URL implements Store.
+func (s *fileStore) URL(key string, expiry time.Duration) (string, error) {
+	expires := strconv.FormatInt(s.now().Add(expiry).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.signature(key, expires)}}
+	return strings.TrimSuffix(s.cfg.BaseURL, "/") + "/coordinator/v1" + FileRoute + "/" + key + "?" + query.Encode(), nil
+}
+
+// List implements Store.
+func (s *fileStore) List(_ context.Context) ([]Object, error) {
+	var objects []Object
+	err := filepath.WalkDir(s.cfg.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.cfg.Dir, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: filepath.ToSlash(rel), LastModified: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+// Delete implements Store.
+func (s *fileStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// serve serves the payload of the URL path, the requests without a valid signature or past their expiry are rejected.
+func (s *fileStore) serve(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	expires := ctx.Query("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(s.signature(key, expires)), []byte(ctx.Query("signature"))) {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	if s.now().Unix() > expiresAt {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	path, err := s.path(key)
+	if err != nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if _, err = os.Stat(path); err != nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.File(path)
+}
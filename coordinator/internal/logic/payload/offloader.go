@@ -0,0 +1,172 @@
+package payload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+const defaultURLExpirySec = 3600
+
+// Object is a payload stored in a Store.
+type Object struct {
+	Key          string
+	LastModified time.Time
+}
+
+// Store stores the task payloads and hands out the URLs the provers download them from.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// URL returns the URL the object is downloaded from without credentials until expiry.
+	URL(key string, expiry time.Duration) (string, error)
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type offloaderMetrics struct {
+	offloadedTotal      *prometheus.CounterVec
+	offloadedBytesTotal prometheus.Counter
+	offloadFailedTotal  prometheus.Counter
+	deletedTotal        prometheus.Counter
+}
+
+var (
+	initOffloaderMetricsOnce sync.Once
+	metrics                  *offloaderMetrics
+)
+
+func initOffloaderMetrics(reg prometheus.Registerer) *offloaderMetrics {
+	initOffloaderMetricsOnce.Do(func() {
+		metrics = &offloaderMetrics{
+			offloadedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "coordinator_task_payload_offloaded_total",
+				Help: "Total number of task payloads offloaded to the payload storage.",
+			}, []string{"task_type"}),
+			offloadedBytesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "coordinator_task_payload_offloaded_bytes_total",
+				Help: "Total size of the task payloads offloaded to the payload storage.",
+			}),
+			offloadFailedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "coordinator_task_payload_offload_failed_total",
+				Help: "Total number of task payloads inlined as their offload failed.",
+			}),
+			deletedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "coordinator_task_payload_deleted_total",
+				Help: "Total number of task payloads deleted from the payload storage past their retention.",
+			}),
+		}
+	})
+	return metrics
+}
+
+// Offloader moves the large task payloads out of the task responses into a Store.
+type Offloader struct {
+	cfg       *config.PayloadStorage
+	store     Store
+	urlExpiry time.Duration
+	metrics   *offloaderMetrics
+}
+
+// NewOffloader creates a new Offloader instance, nil if cfg is nil.
+func NewOffloader(cfg *config.PayloadStorage, reg prometheus.Registerer) (*Offloader, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	var store Store
+	switch cfg.Type {
+	case config.PayloadStorageS3:
+		store = newS3Store(cfg.S3)
+	case config.PayloadStorageFile:
+		store = newFileStore(cfg.File)
+	default:
+		return nil, fmt.Errorf("unknown payload storage type: %q", cfg.Type)
+	}
+
+	urlExpiry := time.Duration(cfg.URLExpirySec) * time.Second
+	if cfg.URLExpirySec == 0 {
+		urlExpiry = defaultURLExpirySec * time.Second
+	}
+	return &Offloader{cfg: cfg, store: store, urlExpiry: urlExpiry, metrics: initOffloaderMetrics(reg)}, nil
+}
+
+// Offload uploads the payload of the task when it is large enough, and replaces it with the URL and the sha256 hash
+// of the payload. The payload is kept inline when the upload fails, the task is still assigned.
+func (o *Offloader) Offload(ctx context.Context, task *coordinatorType.GetTaskSchema) {
+	if o == nil || len(task.TaskData) < o.cfg.MinSizeBytes {
+		return
+	}
+
+	hash := sha256.Sum256([]byte(task.TaskData))
+	taskDataHash := hex.EncodeToString(hash[:])
+	taskType := message.ProofType(task.TaskType).String()
+	// the payload of a task is addressed by its content, the provers of a task download the same object.
+	key := fmt.Sprintf("%s/%s/%s.json", taskType, task.TaskID, taskDataHash)
+	if err := o.store.Put(ctx, key, []byte(task.TaskData)); err != nil {
+		o.metrics.offloadFailedTotal.Inc()
+		log.Warn("failed to offload task payload, the payload is inlined", "task type", taskType, "task id", task.TaskID, "size", len(task.TaskData), "err", err)
+		return
+	}
+	url, err := o.store.URL(key, o.urlExpiry)
+	if err != nil {
+		o.metrics.offloadFailedTotal.Inc()
+		log.Warn("failed to get the url of the task payload, the payload is inlined", "task type", taskType, "task id", task.TaskID, "err", err)
+		return
+	}
+
+	o.metrics.offloadedTotal.WithLabelValues(taskType).Inc()
+	o.metrics.offloadedBytesTotal.Add(float64(len(task.TaskData)))
+	task.TaskData = ""
+	task.TaskDataURL = url
+	task.TaskDataHash = taskDataHash
+}
+
+// ServeFile serves the payloads of the file storage to the provers holding their signed URLs.
+func (o *Offloader) ServeFile(ctx *gin.Context) {
+	store, ok := o.store.(*fileStore)
+	if !ok {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	store.serve(ctx)
+}
+
+// Cleanup deletes the payloads stored before now minus the retention, it returns the number of deleted payloads.
+// Nothing is deleted without retention.
+func (o *Offloader) Cleanup(ctx context.Context, now time.Time) (int, error) {
+	if o == nil || o.cfg.RetentionSec == 0 {
+		return 0, nil
+	}
+
+	objects, err := o.store.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the task payloads: %w", err)
+	}
+	before := now.Add(-time.Duration(o.cfg.RetentionSec) * time.Second)
+	var deleted int
+	for _, object := range objects {
+		if !object.LastModified.Before(before) {
+			continue
+		}
+		if err = o.store.Delete(ctx, object.Key); err != nil {
+			return deleted, fmt.Errorf("failed to delete the task payload %s: %w", object.Key, err)
+		}
+		deleted++
+		o.metrics.deletedTotal.Inc()
+	}
+	return deleted, nil
+}
@@ -0,0 +1,128 @@
+package payload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func newTestOffloader(t *testing.T, minSizeBytes, retentionSec int) *Offloader {
+	o, err := NewOffloader(&config.PayloadStorage{
+		Type:         config.PayloadStorageFile,
+		MinSizeBytes: minSizeBytes,
+		RetentionSec: retentionSec,
+		File:         &config.FileStorage{Dir: t.TempDir(), BaseURL: "http://coordinator", Secret: "secret"},
+	}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	return o
+}
+
+func serveFile(o *Offloader, rawURL string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/coordinator/v1"+FileRoute+"/*key", o.ServeFile)
+	u, _ := url.Parse(rawURL)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+	return w
+}
+
+func TestNilOffloader(t *testing.T) {
+	o, err := NewOffloader(nil, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.Nil(t, o)
+
+	task := &coordinatorType.GetTaskSchema{TaskData: "payload"}
+	o.Offload(context.Background(), task)
+	assert.Equal(t, "payload", task.TaskData)
+
+	deleted, err := o.Cleanup(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestOffload(t *testing.T) {
+	o := newTestOffloader(t, 8, 0)
+
+	// the small payloads are inlined.
+	small := &coordinatorType.GetTaskSchema{TaskID: "task-0", TaskType: int(message.ProofTypeChunk), TaskData: "{}"}
+	o.Offload(context.Background(), small)
+	assert.Equal(t, "{}", small.TaskData)
+	assert.Empty(t, small.TaskDataURL)
+
+	data := `{"block_hashes":["0x01"]}`
+	large := &coordinatorType.GetTaskSchema{TaskID: "task-1", TaskType: int(message.ProofTypeChunk), TaskData: data}
+	o.Offload(context.Background(), large)
+	hash := sha256.Sum256([]byte(data))
+	assert.Empty(t, large.TaskData)
+	assert.Equal(t, hex.EncodeToString(hash[:]), large.TaskDataHash)
+
+	w := serveFile(o, large.TaskDataURL)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, data, w.Body.String())
+}
+
+func TestServeFile(t *testing.T) {
+	o := newTestOffloader(t, 0, 0)
+	store := o.store.(*fileStore)
+	require.NoError(t, store.Put(context.Background(), "chunk/task/hash.json", []byte("payload")))
+
+	rawURL, err := store.URL("chunk/task/hash.json", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serveFile(o, rawURL).Code)
+
+	// the signature covers the key.
+	u, _ := url.Parse(rawURL)
+	query := u.Query()
+	u.Path = "/coordinator/v1" + FileRoute + "/chunk/other/hash.json"
+	assert.Equal(t, http.StatusForbidden, serveFile(o, u.String()).Code)
+
+	// and the expiry.
+	query.Set("expires", "1")
+	u.Path = "/coordinator/v1" + FileRoute + "/chunk/task/hash.json"
+	u.RawQuery = query.Encode()
+	assert.Equal(t, http.StatusForbidden, serveFile(o, u.String()).Code)
+
+	store.now = func() time.Time { return time.Now().Add(time.Hour) }
+	assert.Equal(t, http.StatusForbidden, serveFile(o, rawURL).Code)
+
+	_, err = store.path("../escape.json")
+	assert.Error(t, err)
+}
+
+func TestCleanup(t *testing.T) {
+	o := newTestOffloader(t, 0, 60)
+	store := o.store.(*fileStore)
+	require.NoError(t, store.Put(context.Background(), "chunk/old/hash.json", []byte("old")))
+	require.NoError(t, store.Put(context.Background(), "chunk/new/hash.json", []byte("new")))
+
+	now := time.Now()
+	old := now.Add(-2 * time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(store.cfg.Dir, "chunk", "old", "hash.json"), old, old))
+
+	deleted, err := o.Cleanup(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	objects, err := store.List(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "chunk/new/hash.json", objects[0].Key)
+}
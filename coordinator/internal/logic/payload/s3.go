@@ -0,0 +1,159 @@
+package payload
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"scroll-tech/common/awsv4"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+const (
+	s3Service        = "s3"
+	s3RequestTimeout = 30 * time.Second
+)
+
+// s3Store stores the payloads in an S3 compatible bucket, the requests are signed with AWS signature version 4.
+type s3Store struct {
+	cfg    *config.S3Storage
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3Store(cfg *config.S3Storage) *s3Store {
+	return &s3Store{cfg: cfg, client: &http.Client{Timeout: s3RequestTimeout}, now: time.Now}
+}
+
+// objectURL returns the path style URL of the object key, or of the bucket if key is empty.
+func (s *s3Store) objectURL(key string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSuffix(s.cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	u.Path += "/" + s.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	return u, nil
+}
+
+func (s *s3Store) objectKey(key string) string {
+	if s.cfg.Prefix == "" {
+		return key
+	}
+	return strings.TrimSuffix(s.cfg.Prefix, "/") + "/" + key
+}
+
+// Put implements Store.
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	u, err := s.objectURL(s.objectKey(key))
+	if err != nil {
+		return err
+	}
+	_, err = s.do(ctx, http.MethodPut, u, data)
+	return err
+}
+
+// URL implements Store.
+func (s *s3Store) URL(key string, expiry time.Duration) (string, error) {
+	u, err := s.objectURL(s.objectKey(key))
+	if err != nil {
+		return "", err
+	}
+	return awsv4.PresignURL(http.MethodGet, u, s.creds(), s.cfg.Region, s3Service, s.now(), expiry), nil
+}
+
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// List implements Store, the keys are returned without the prefix.
+func (s *s3Store) List(ctx context.Context) ([]Object, error) {
+	var objects []Object
+	var continuationToken string
+	for {
+		u, err := s.objectURL("")
+		if err != nil {
+			return nil, err
+		}
+		query := url.Values{"list-type": {"2"}}
+		if s.cfg.Prefix != "" {
+			query.Set("prefix", strings.TrimSuffix(s.cfg.Prefix, "/")+"/")
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		u.RawQuery = query.Encode()
+
+		body, err := s.do(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		if err = xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode the s3 object listing: %w", err)
+		}
+		for _, content := range result.Contents {
+			key := content.Key
+			if s.cfg.Prefix != "" {
+				key = strings.TrimPrefix(key, strings.TrimSuffix(s.cfg.Prefix, "/")+"/")
+			}
+			objects = append(objects, Object{Key: key, LastModified: content.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Delete implements Store.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	u, err := s.objectURL(s.objectKey(key))
+	if err != nil {
+		return err
+	}
+	_, err = s.do(ctx, http.MethodDelete, u, nil)
+	return err
+}
+
+// do sends the request signed in the Authorization header and returns the response body.
+func (s *s3Store) do(ctx context.Context, method string, u *url.URL, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awsv4.SHA256Hex(body))
+	awsv4.SignRequest(req, body, s.creds(), s.cfg.Region, s3Service, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s failed: %w", method, u.Path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the s3 %s %s response: %w", method, u.Path, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("s3 %s %s failed, status: %d, body: %s", method, u.Path, resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+func (s *s3Store) creds() awsv4.Credentials {
+	return awsv4.Credentials{AccessKeyID: s.cfg.AccessKeyID, SecretAccessKey: s.cfg.SecretAccessKey}
+}
@@ -41,6 +41,7 @@ func NewBatchProverTask(cfg *config.Config, db *gorm.DB, vk string, reg promethe
 			batchOrm:           orm.NewBatch(db),
 			proverTaskOrm:      orm.NewProverTask(db),
 			proverBlockListOrm: orm.NewProverBlockList(db),
+			circuitForks:       newCircuitForks(cfg.ProverManager.CircuitForks, db),
 		},
 		batchAttemptsExceedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_batch_attempts_exceed_total",
@@ -61,26 +62,57 @@ func (bp *BatchProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinato
 		return nil, fmt.Errorf("check prover task parameter failed, error:%w", err)
 	}
 
+	indexRange, found, err := bp.circuitForks.taskRange(ctx, message.ProofTypeBatch, taskCtx.CircuitVersion)
+	if err != nil {
+		log.Error("failed to get the batch tasks of the circuit version", "circuit version", taskCtx.CircuitVersion, "err", err)
+		return nil, ErrCoordinatorInternalFailure
+	}
+	if !found {
+		log.Debug("get empty batch of the circuit version", "circuit version", taskCtx.CircuitVersion)
+		return nil, nil
+	}
+
 	maxActiveAttempts := bp.cfg.ProverManager.ProversPerSession
 	maxTotalAttempts := bp.cfg.ProverManager.SessionAttempts
 	var batchTask *orm.Batch
 	for i := 0; i < 5; i++ {
-		var getTaskError error
 		var tmpBatchTask *orm.Batch
-		tmpBatchTask, getTaskError = bp.batchOrm.GetAssignedBatch(ctx, maxActiveAttempts, maxTotalAttempts)
-		if getTaskError != nil {
-			log.Error("failed to get assigned batch proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
-			return nil, ErrCoordinatorInternalFailure
-		}
-
-		// Why here need get again? In order to support a task can assign to multiple prover, need also assign `ProvingTaskAssigned`
-		// batch to prover. But use `proving_status in (1, 2)` will not use the postgres index. So need split the sql.
-		if tmpBatchTask == nil {
-			tmpBatchTask, getTaskError = bp.batchOrm.GetUnassignedBatch(ctx, maxActiveAttempts, maxTotalAttempts)
+		var rowsAffected int64
+		// the selected task stays locked until its attempts are updated, the concurrent assignments of the other
+		// coordinator instances pick the next task instead of racing for this one.
+		txErr := bp.db.Transaction(func(tx *gorm.DB) error {
+			var getTaskError error
+			tmpBatchTask, getTaskError = bp.batchOrm.GetAssignedBatch(ctx, maxActiveAttempts, maxTotalAttempts, indexRange, tx)
 			if getTaskError != nil {
-				log.Error("failed to get unassigned batch proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
-				return nil, ErrCoordinatorInternalFailure
+				log.Error("failed to get assigned batch proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
+				return getTaskError
+			}
+
+			// Why here need get again? In order to support a task can assign to multiple prover, need also assign `ProvingTaskAssigned`
+			// batch to prover. But use `proving_status in (1, 2)` will not use the postgres index. So need split the sql.
+			if tmpBatchTask == nil {
+				tmpBatchTask, getTaskError = bp.batchOrm.GetUnassignedBatch(ctx, maxActiveAttempts, maxTotalAttempts, indexRange, tx)
+				if getTaskError != nil {
+					log.Error("failed to get unassigned batch proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
+					return getTaskError
+				}
+			}
+
+			if tmpBatchTask == nil {
+				return nil
 			}
+
+			var updateAttemptsErr error
+			rowsAffected, updateAttemptsErr = bp.batchOrm.UpdateBatchAttempts(ctx, tmpBatchTask.Index, tmpBatchTask.ActiveAttempts, tmpBatchTask.TotalAttempts, tx)
+			if updateAttemptsErr != nil {
+				log.Error("failed to update batch attempts", "height", getTaskParameter.ProverHeight, "err", updateAttemptsErr)
+				return updateAttemptsErr
+			}
+
+			return nil
+		})
+		if txErr != nil {
+			return nil, ErrCoordinatorInternalFailure
 		}
 
 		if tmpBatchTask == nil {
@@ -88,12 +120,6 @@ func (bp *BatchProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinato
 			return nil, nil
 		}
 
-		rowsAffected, updateAttemptsErr := bp.batchOrm.UpdateBatchAttempts(ctx, tmpBatchTask.Index, tmpBatchTask.ActiveAttempts, tmpBatchTask.TotalAttempts)
-		if updateAttemptsErr != nil {
-			log.Error("failed to update batch attempts", "height", getTaskParameter.ProverHeight, "err", updateAttemptsErr)
-			return nil, ErrCoordinatorInternalFailure
-		}
-
 		if rowsAffected == 0 {
 			time.Sleep(100 * time.Millisecond)
 			continue
@@ -141,6 +167,29 @@ func (bp *BatchProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinato
 	return taskMsg, nil
 }
 
+// Peek returns the batch task Assign would assign next, nil if no batch task is waiting.
+func (bp *BatchProverTask) Peek(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*TaskCandidate, error) {
+	indexRange, found, err := bp.circuitForks.taskRange(ctx, message.ProofTypeBatch, ctx.GetString(coordinatorType.CircuitVersion))
+	if err != nil || !found {
+		return nil, err
+	}
+	maxActiveAttempts := bp.cfg.ProverManager.ProversPerSession
+	maxTotalAttempts := bp.cfg.ProverManager.SessionAttempts
+	batchTask, err := bp.batchOrm.GetAssignedBatch(ctx, maxActiveAttempts, maxTotalAttempts, indexRange)
+	if err != nil {
+		return nil, err
+	}
+	if batchTask == nil {
+		if batchTask, err = bp.batchOrm.GetUnassignedBatch(ctx, maxActiveAttempts, maxTotalAttempts, indexRange); err != nil {
+			return nil, err
+		}
+	}
+	if batchTask == nil {
+		return nil, nil
+	}
+	return &TaskCandidate{ProofType: message.ProofTypeBatch, CreatedAt: batchTask.CreatedAt}, nil
+}
+
 func (bp *BatchProverTask) formatProverTask(ctx context.Context, task *orm.ProverTask) (*coordinatorType.GetTaskSchema, error) {
 	// get chunk from db
 	chunks, err := bp.chunkOrm.GetChunksByBatchHash(ctx, task.TaskID)
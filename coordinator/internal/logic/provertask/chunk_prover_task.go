@@ -43,6 +43,7 @@ func NewChunkProverTask(cfg *config.Config, db *gorm.DB, vk string, reg promethe
 			blockOrm:           orm.NewL2Block(db),
 			proverTaskOrm:      orm.NewProverTask(db),
 			proverBlockListOrm: orm.NewProverBlockList(db),
+			circuitForks:       newCircuitForks(cfg.ProverManager.CircuitForks, db),
 		},
 		chunkAttemptsExceedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_chunk_attempts_exceed_total",
@@ -63,26 +64,57 @@ func (cp *ChunkProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinato
 		return nil, fmt.Errorf("check prover task parameter failed, error:%w", err)
 	}
 
+	indexRange, found, err := cp.circuitForks.taskRange(ctx, message.ProofTypeChunk, taskCtx.CircuitVersion)
+	if err != nil {
+		log.Error("failed to get the chunk tasks of the circuit version", "circuit version", taskCtx.CircuitVersion, "err", err)
+		return nil, ErrCoordinatorInternalFailure
+	}
+	if !found {
+		log.Debug("get empty chunk of the circuit version", "circuit version", taskCtx.CircuitVersion)
+		return nil, nil
+	}
+
 	maxActiveAttempts := cp.cfg.ProverManager.ProversPerSession
 	maxTotalAttempts := cp.cfg.ProverManager.SessionAttempts
 	var chunkTask *orm.Chunk
 	for i := 0; i < 5; i++ {
-		var getTaskError error
 		var tmpChunkTask *orm.Chunk
-		tmpChunkTask, getTaskError = cp.chunkOrm.GetAssignedChunk(ctx, getTaskParameter.ProverHeight, maxActiveAttempts, maxTotalAttempts)
-		if getTaskError != nil {
-			log.Error("failed to get assigned chunk proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
-			return nil, ErrCoordinatorInternalFailure
-		}
-
-		// Why here need get again? In order to support a task can assign to multiple prover, need also assign `ProvingTaskAssigned`
-		// chunk to prover. But use `proving_status in (1, 2)` will not use the postgres index. So need split the sql.
-		if tmpChunkTask == nil {
-			tmpChunkTask, getTaskError = cp.chunkOrm.GetUnassignedChunk(ctx, getTaskParameter.ProverHeight, maxActiveAttempts, maxTotalAttempts)
+		var rowsAffected int64
+		// the selected task stays locked until its attempts are updated, the concurrent assignments of the other
+		// coordinator instances pick the next task instead of racing for this one.
+		txErr := cp.db.Transaction(func(tx *gorm.DB) error {
+			var getTaskError error
+			tmpChunkTask, getTaskError = cp.chunkOrm.GetAssignedChunk(ctx, getTaskParameter.ProverHeight, maxActiveAttempts, maxTotalAttempts, indexRange, tx)
 			if getTaskError != nil {
-				log.Error("failed to get unassigned chunk proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
-				return nil, ErrCoordinatorInternalFailure
+				log.Error("failed to get assigned chunk proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
+				return getTaskError
+			}
+
+			// Why here need get again? In order to support a task can assign to multiple prover, need also assign `ProvingTaskAssigned`
+			// chunk to prover. But use `proving_status in (1, 2)` will not use the postgres index. So need split the sql.
+			if tmpChunkTask == nil {
+				tmpChunkTask, getTaskError = cp.chunkOrm.GetUnassignedChunk(ctx, getTaskParameter.ProverHeight, maxActiveAttempts, maxTotalAttempts, indexRange, tx)
+				if getTaskError != nil {
+					log.Error("failed to get unassigned chunk proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
+					return getTaskError
+				}
+			}
+
+			if tmpChunkTask == nil {
+				return nil
+			}
+
+			var updateAttemptsErr error
+			rowsAffected, updateAttemptsErr = cp.chunkOrm.UpdateChunkAttempts(ctx, tmpChunkTask.Index, tmpChunkTask.ActiveAttempts, tmpChunkTask.TotalAttempts, tx)
+			if updateAttemptsErr != nil {
+				log.Error("failed to update chunk attempts", "height", getTaskParameter.ProverHeight, "err", updateAttemptsErr)
+				return updateAttemptsErr
 			}
+
+			return nil
+		})
+		if txErr != nil {
+			return nil, ErrCoordinatorInternalFailure
 		}
 
 		if tmpChunkTask == nil {
@@ -90,12 +122,6 @@ func (cp *ChunkProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinato
 			return nil, nil
 		}
 
-		rowsAffected, updateAttemptsErr := cp.chunkOrm.UpdateChunkAttempts(ctx, tmpChunkTask.Index, tmpChunkTask.ActiveAttempts, tmpChunkTask.TotalAttempts)
-		if updateAttemptsErr != nil {
-			log.Error("failed to update chunk attempts", "height", getTaskParameter.ProverHeight, "err", updateAttemptsErr)
-			return nil, ErrCoordinatorInternalFailure
-		}
-
 		if rowsAffected == 0 {
 			time.Sleep(100 * time.Millisecond)
 			continue
@@ -142,6 +168,29 @@ func (cp *ChunkProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinato
 	return taskMsg, nil
 }
 
+// Peek returns the chunk task Assign would assign next, nil if no chunk task is waiting.
+func (cp *ChunkProverTask) Peek(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*TaskCandidate, error) {
+	indexRange, found, err := cp.circuitForks.taskRange(ctx, message.ProofTypeChunk, ctx.GetString(coordinatorType.CircuitVersion))
+	if err != nil || !found {
+		return nil, err
+	}
+	maxActiveAttempts := cp.cfg.ProverManager.ProversPerSession
+	maxTotalAttempts := cp.cfg.ProverManager.SessionAttempts
+	chunkTask, err := cp.chunkOrm.GetAssignedChunk(ctx, getTaskParameter.ProverHeight, maxActiveAttempts, maxTotalAttempts, indexRange)
+	if err != nil {
+		return nil, err
+	}
+	if chunkTask == nil {
+		if chunkTask, err = cp.chunkOrm.GetUnassignedChunk(ctx, getTaskParameter.ProverHeight, maxActiveAttempts, maxTotalAttempts, indexRange); err != nil {
+			return nil, err
+		}
+	}
+	if chunkTask == nil {
+		return nil, nil
+	}
+	return &TaskCandidate{ProofType: message.ProofTypeChunk, CreatedAt: chunkTask.CreatedAt}, nil
+}
+
 func (cp *ChunkProverTask) formatProverTask(ctx context.Context, task *orm.ProverTask) (*coordinatorType.GetTaskSchema, error) {
 	// Get block hashes.
 	blockHashes, dbErr := cp.blockOrm.GetL2BlockHashesByChunkHash(ctx, task.TaskID)
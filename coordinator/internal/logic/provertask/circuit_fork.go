@@ -0,0 +1,131 @@
+package provertask
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+// circuitForks routes the tasks to the provers by circuit version. The tasks of a fork are the indexes from its first
+// task to the first task of the next fork, the first tasks are looked up once they exist and cached since they never
+// change.
+type circuitForks struct {
+	forks []config.CircuitFork
+
+	chunkOrm *orm.Chunk
+	batchOrm *orm.Batch
+
+	mu         sync.Mutex
+	firstChunk map[int]uint64
+	firstBatch map[int]uint64
+}
+
+// newCircuitForks returns nil when no fork is configured, a nil circuitForks assigns any task to any prover.
+func newCircuitForks(forks []config.CircuitFork, db *gorm.DB) *circuitForks {
+	if len(forks) == 0 {
+		return nil
+	}
+
+	sorted := make([]config.CircuitFork, len(forks))
+	copy(sorted, forks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ForkBlock < sorted[j].ForkBlock })
+
+	return &circuitForks{
+		forks:      sorted,
+		chunkOrm:   orm.NewChunk(db),
+		batchOrm:   orm.NewBatch(db),
+		firstChunk: make(map[int]uint64),
+		firstBatch: make(map[int]uint64),
+	}
+}
+
+// checkCircuitVersion returns an error if no fork is proven by the circuit version.
+func (f *circuitForks) checkCircuitVersion(circuitVersion string) error {
+	if f == nil || f.forkOf(circuitVersion) >= 0 {
+		return nil
+	}
+	versions := make([]string, len(f.forks))
+	for i, fork := range f.forks {
+		versions[i] = fork.CircuitVersion
+	}
+	return fmt.Errorf("unsupported circuit version: %s, supported circuit versions: %v", circuitVersion, versions)
+}
+
+// taskRange returns the indexes of the tasks of the proof type proven by the circuit version, false if there is no
+// such task yet.
+func (f *circuitForks) taskRange(ctx context.Context, proofType message.ProofType, circuitVersion string) (*orm.IndexRange, bool, error) {
+	if f == nil {
+		return nil, true, nil
+	}
+	i := f.forkOf(circuitVersion)
+	if i < 0 {
+		return nil, false, f.checkCircuitVersion(circuitVersion)
+	}
+
+	from, found, err := f.firstTask(ctx, proofType, i)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	indexRange := &orm.IndexRange{From: from}
+	if i+1 < len(f.forks) {
+		to, found, err := f.firstTask(ctx, proofType, i+1)
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			indexRange.To = &to
+		}
+	}
+	return indexRange, true, nil
+}
+
+func (f *circuitForks) forkOf(circuitVersion string) int {
+	for i, fork := range f.forks {
+		if fork.CircuitVersion == circuitVersion {
+			return i
+		}
+	}
+	return -1
+}
+
+// firstTask returns the index of the first task of the proof type of the i-th fork, false if it does not exist yet.
+func (f *circuitForks) firstTask(ctx context.Context, proofType message.ProofType, i int) (uint64, bool, error) {
+	if f.forks[i].ForkBlock == 0 {
+		return 0, true, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	chunkIndex, found := f.firstChunk[i]
+	if !found {
+		chunk, err := f.chunkOrm.GetFirstChunkSinceBlock(ctx, f.forks[i].ForkBlock)
+		if err != nil || chunk == nil {
+			return 0, false, err
+		}
+		chunkIndex = chunk.Index
+		f.firstChunk[i] = chunkIndex
+	}
+	if proofType == message.ProofTypeChunk {
+		return chunkIndex, true, nil
+	}
+
+	batchIndex, found := f.firstBatch[i]
+	if !found {
+		batch, err := f.batchOrm.GetFirstBatchSinceChunk(ctx, chunkIndex)
+		if err != nil || batch == nil {
+			return 0, false, err
+		}
+		batchIndex = batch.Index
+		f.firstBatch[i] = batchIndex
+	}
+	return batchIndex, true, nil
+}
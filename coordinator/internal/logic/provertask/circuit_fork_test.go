@@ -0,0 +1,49 @@
+package provertask
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+func TestCircuitForks(t *testing.T) {
+	// no fork routes any task to any prover.
+	var forks *circuitForks
+	assert.NoError(t, forks.checkCircuitVersion(""))
+	indexRange, found, err := forks.taskRange(context.Background(), message.ProofTypeChunk, "")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Nil(t, indexRange)
+
+	forks = newCircuitForks([]config.CircuitFork{
+		{ForkBlock: 1000, CircuitVersion: "v0.12.0"},
+		{ForkBlock: 0, CircuitVersion: "v0.11.0"},
+	}, nil)
+	assert.NoError(t, forks.checkCircuitVersion("v0.11.0"))
+	assert.Error(t, forks.checkCircuitVersion("v0.10.0"))
+	assert.Error(t, forks.checkCircuitVersion(""))
+
+	// the first tasks of the fork are already known.
+	forks.firstChunk[1] = 40
+	forks.firstBatch[1] = 4
+
+	indexRange, found, err = forks.taskRange(context.Background(), message.ProofTypeChunk, "v0.11.0")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(0), indexRange.From)
+	assert.Equal(t, uint64(40), *indexRange.To)
+
+	indexRange, found, err = forks.taskRange(context.Background(), message.ProofTypeBatch, "v0.12.0")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(4), indexRange.From)
+	assert.Nil(t, indexRange.To)
+
+	_, _, err = forks.taskRange(context.Background(), message.ProofTypeChunk, "v0.10.0")
+	assert.Error(t, err)
+}
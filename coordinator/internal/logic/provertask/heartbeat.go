@@ -0,0 +1,67 @@
+package provertask
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+// ProverHeartbeatCheck decides whether a prover is assigned a task from its latest heartbeat, see config.ProverHeartbeat.
+type ProverHeartbeatCheck struct {
+	cfg *config.ProverHeartbeat
+
+	proverHeartbeatOrm *orm.ProverHeartbeat
+
+	withheldTotal *prometheus.CounterVec
+}
+
+// NewProverHeartbeatCheck creates a new ProverHeartbeatCheck instance.
+func NewProverHeartbeatCheck(cfg *config.ProverHeartbeat, db *gorm.DB, reg prometheus.Registerer) *ProverHeartbeatCheck {
+	return &ProverHeartbeatCheck{
+		cfg:                cfg,
+		proverHeartbeatOrm: orm.NewProverHeartbeat(db),
+		withheldTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "coordinator_get_task_withheld_by_heartbeat_total",
+			Help: "Total number of get task requests left empty because of the latest prover heartbeat.",
+		}, []string{"proof_type", "reason"}),
+	}
+}
+
+// Admit returns whether the prover of publicKey is assigned a task of proofType given its latest heartbeat.
+func (c *ProverHeartbeatCheck) Admit(ctx context.Context, publicKey string, proofType message.ProofType, now time.Time) (bool, error) {
+	heartbeat, err := c.proverHeartbeatOrm.GetProverHeartbeat(ctx, publicKey)
+	if err != nil {
+		return false, err
+	}
+	reason := heartbeatWithholdReason(heartbeat, proofType, c.cfg, now)
+	if reason == "" {
+		return true, nil
+	}
+	c.withheldTotal.WithLabelValues(proofType.String(), reason).Inc()
+	log.Debug("task withheld from prover by its heartbeat", "public key", publicKey, "proof type", proofType.String(), "reason", reason)
+	return false, nil
+}
+
+// heartbeatWithholdReason returns why the tasks of proofType are withheld from the prover of the heartbeat, empty if
+// they are not. A missing or stale heartbeat withholds nothing.
+func heartbeatWithholdReason(heartbeat *orm.ProverHeartbeat, proofType message.ProofType, cfg *config.ProverHeartbeat, now time.Time) string {
+	if heartbeat == nil || now.Sub(heartbeat.UpdatedAt) > time.Duration(cfg.StaleSec)*time.Second {
+		return ""
+	}
+	if cfg.MaxRecentErrors > 0 && heartbeat.RecentErrors > cfg.MaxRecentErrors {
+		return "recent_errors"
+	}
+	if minMemory := cfg.MinGPUMemoryMB[proofTypeNames[proofType]]; heartbeat.GPUMemoryMB > 0 && heartbeat.GPUMemoryMB < minMemory {
+		return "gpu_memory"
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package provertask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+func TestHeartbeatWithholdReason(t *testing.T) {
+	cfg := &config.ProverHeartbeat{StaleSec: 60, MaxRecentErrors: 3, MinGPUMemoryMB: map[string]uint64{"batch": 20000}}
+	now := time.Now()
+
+	// a prover without a recent heartbeat is assigned the tasks.
+	assert.Empty(t, heartbeatWithholdReason(nil, message.ProofTypeChunk, cfg, now))
+	assert.Empty(t, heartbeatWithholdReason(&orm.ProverHeartbeat{RecentErrors: 10, UpdatedAt: now.Add(-2 * time.Minute)}, message.ProofTypeChunk, cfg, now))
+
+	assert.Equal(t, "recent_errors", heartbeatWithholdReason(&orm.ProverHeartbeat{RecentErrors: 4, UpdatedAt: now}, message.ProofTypeChunk, cfg, now))
+	assert.Empty(t, heartbeatWithholdReason(&orm.ProverHeartbeat{RecentErrors: 3, UpdatedAt: now}, message.ProofTypeChunk, cfg, now))
+
+	// the GPU memory is only checked for the configured proof types, and when reported.
+	heartbeat := &orm.ProverHeartbeat{GPUMemoryMB: 16000, UpdatedAt: now}
+	assert.Equal(t, "gpu_memory", heartbeatWithholdReason(heartbeat, message.ProofTypeBatch, cfg, now))
+	assert.Empty(t, heartbeatWithholdReason(heartbeat, message.ProofTypeChunk, cfg, now))
+	assert.Empty(t, heartbeatWithholdReason(&orm.ProverHeartbeat{UpdatedAt: now}, message.ProofTypeBatch, cfg, now))
+}
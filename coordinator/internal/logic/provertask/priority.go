@@ -0,0 +1,95 @@
+package provertask
+
+import (
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// proofTypeNames are the names of the proof types in the task priority configuration.
+var proofTypeNames = map[message.ProofType]string{
+	message.ProofTypeChunk: "chunk",
+	message.ProofTypeBatch: "batch",
+}
+
+// TaskCandidate is the task of a proof type which would be assigned next.
+type TaskCandidate struct {
+	ProofType message.ProofType
+	CreatedAt time.Time
+}
+
+// TaskScorer scores the candidate tasks, the candidate with the highest score is assigned first.
+type TaskScorer interface {
+	Score(candidate *TaskCandidate, now time.Time) float64
+}
+
+// weightedTaskScorer scores a task by its age weighted by its proof type, plus the time past its deadline weighted by
+// the overdue weight.
+type weightedTaskScorer struct {
+	proofTypeWeights map[message.ProofType]float64
+	deadline         time.Duration
+	overdueWeight    float64
+}
+
+// NewTaskScorer creates the scorer configured by cfg, a nil cfg scores the tasks by age so that the oldest task is
+// assigned first whatever its proof type.
+func NewTaskScorer(cfg *config.TaskPriority) TaskScorer {
+	s := &weightedTaskScorer{proofTypeWeights: make(map[message.ProofType]float64)}
+	for proofType := range proofTypeNames {
+		s.proofTypeWeights[proofType] = 1
+	}
+	if cfg == nil {
+		return s
+	}
+
+	for name, weight := range cfg.ProofTypeWeights {
+		found := false
+		for proofType, proofTypeName := range proofTypeNames {
+			if name == proofTypeName {
+				s.proofTypeWeights[proofType] = weight
+				found = true
+			}
+		}
+		if !found {
+			log.Warn("unknown proof type in task priority weights, ignored", "proof type", name)
+		}
+	}
+	s.deadline = time.Duration(cfg.DeadlineSec) * time.Second
+	s.overdueWeight = cfg.OverdueWeight
+	return s
+}
+
+// Score implements TaskScorer.
+func (s *weightedTaskScorer) Score(candidate *TaskCandidate, now time.Time) float64 {
+	age := now.Sub(candidate.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+
+	score := s.proofTypeWeights[candidate.ProofType] * age.Seconds()
+	if s.deadline > 0 && age > s.deadline {
+		score += s.overdueWeight * (age - s.deadline).Seconds()
+	}
+	return score
+}
+
+// PrioritizeCandidates returns the candidate with the highest score, nil if there is none. Ties go to the first one.
+func PrioritizeCandidates(scorer TaskScorer, candidates []*TaskCandidate, now time.Time) *TaskCandidate {
+	var (
+		best      *TaskCandidate
+		bestScore float64
+	)
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		if score := scorer.Score(candidate, now); best == nil || score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,38 @@
+package provertask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+func TestTaskScorer(t *testing.T) {
+	now := time.Now()
+	oldBatch := &TaskCandidate{ProofType: message.ProofTypeBatch, CreatedAt: now.Add(-time.Hour)}
+	newChunk := &TaskCandidate{ProofType: message.ProofTypeChunk, CreatedAt: now.Add(-time.Minute)}
+	oldChunk := &TaskCandidate{ProofType: message.ProofTypeChunk, CreatedAt: now.Add(-2 * time.Hour)}
+
+	// the oldest task goes first by default, whatever its proof type.
+	scorer := NewTaskScorer(nil)
+	assert.Equal(t, oldBatch, PrioritizeCandidates(scorer, []*TaskCandidate{newChunk, oldBatch, nil}, now))
+	assert.Equal(t, oldChunk, PrioritizeCandidates(scorer, []*TaskCandidate{oldChunk, oldBatch}, now))
+	assert.Nil(t, PrioritizeCandidates(scorer, []*TaskCandidate{nil, nil}, now))
+
+	// weighing the batches makes an old batch overtake an older chunk.
+	scorer = NewTaskScorer(&config.TaskPriority{ProofTypeWeights: map[string]float64{"batch": 3, "unknown": 10}})
+	assert.Equal(t, oldBatch, PrioritizeCandidates(scorer, []*TaskCandidate{oldChunk, oldBatch}, now))
+
+	// a task past its deadline overtakes the others.
+	scorer = NewTaskScorer(&config.TaskPriority{ProofTypeWeights: map[string]float64{"chunk": 10}, DeadlineSec: 1800, OverdueWeight: 20})
+	assert.InDelta(t, 3600+20*1800, scorer.Score(oldBatch, now), 1e-6)
+	assert.InDelta(t, 600, scorer.Score(newChunk, now), 1e-6)
+	assert.Equal(t, oldBatch, PrioritizeCandidates(scorer, []*TaskCandidate{newChunk, oldBatch}, now))
+
+	// a task created in the future, e.g. with clock skew, scores zero.
+	assert.Equal(t, float64(0), scorer.Score(&TaskCandidate{ProofType: message.ProofTypeBatch, CreatedAt: now.Add(time.Minute)}, now))
+}
@@ -16,6 +16,8 @@ import (
 // ProverTask the interface of a collector who send data to prover
 type ProverTask interface {
 	Assign(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error)
+	// Peek returns the task Assign would assign next, nil if no task is waiting.
+	Peek(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*TaskCandidate, error)
 }
 
 // BaseProverTask a base prover task which contain series functions
@@ -29,12 +31,15 @@ type BaseProverTask struct {
 	blockOrm           *orm.L2Block
 	proverTaskOrm      *orm.ProverTask
 	proverBlockListOrm *orm.ProverBlockList
+
+	circuitForks *circuitForks
 }
 
 type proverTaskContext struct {
-	PublicKey     string
-	ProverName    string
-	ProverVersion string
+	PublicKey      string
+	ProverName     string
+	ProverVersion  string
+	CircuitVersion string
 }
 
 // checkParameter check the prover task parameter illegal
@@ -63,8 +68,14 @@ func (b *BaseProverTask) checkParameter(ctx *gin.Context, getTaskParameter *coor
 		return nil, fmt.Errorf("incompatible prover version. please upgrade your prover, minimum allowed version: %s, actual version: %s", b.cfg.ProverManager.MinProverVersion, proverVersion.(string))
 	}
 
-	// if the prover has a different vk
-	if getTaskParameter.VK != b.vk {
+	// the circuit version of the prover replaces the vk check when the tasks are routed by circuit version, the vk
+	// of the verifier only matches one of the circuits.
+	if b.circuitForks != nil {
+		ptc.CircuitVersion = ctx.GetString(coordinatorType.CircuitVersion)
+		if err := b.circuitForks.checkCircuitVersion(ptc.CircuitVersion); err != nil {
+			return nil, err
+		}
+	} else if getTaskParameter.VK != b.vk {
 		// if the prover reports a different prover version
 		if !version.CheckScrollProverVersion(proverVersion.(string)) {
 			return nil, fmt.Errorf("incompatible prover version. please upgrade your prover, expect version: %s, actual version: %s", version.Version, proverVersion.(string))
@@ -81,6 +92,11 @@ func (b *BaseProverTask) checkParameter(ctx *gin.Context, getTaskParameter *coor
 		return nil, fmt.Errorf("public key %s is blocked from fetching tasks. ProverName: %s, ProverVersion: %s", publicKey, proverName, proverVersion)
 	}
 
+	// with rate limits configured, the rate limiter enforces the number of tasks assigned to a prover at once.
+	if b.cfg.RateLimit != nil {
+		return &ptc, nil
+	}
+
 	isAssigned, err := b.proverTaskOrm.IsProverAssigned(ctx, publicKey.(string))
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if prover %s is assigned a task, err: %w", publicKey.(string), err)
@@ -0,0 +1,67 @@
+package provertask
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+// ProverReputation decides whether a prover is offered a task from its statistics, see config.ProverReputation.
+type ProverReputation struct {
+	cfg *config.ProverReputation
+
+	proverStatsOrm *orm.ProverStats
+
+	deprioritizedTotal *prometheus.CounterVec
+}
+
+// NewProverReputation creates a new ProverReputation instance.
+func NewProverReputation(cfg *config.ProverReputation, db *gorm.DB, reg prometheus.Registerer) *ProverReputation {
+	return &ProverReputation{
+		cfg:            cfg,
+		proverStatsOrm: orm.NewProverStats(db),
+		deprioritizedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "coordinator_get_task_deprioritized_total",
+			Help: "Total number of get task requests left empty because of the prover reputation.",
+		}, []string{"proof_type"}),
+	}
+}
+
+// Admit returns whether the prover of publicKey is offered the candidate task now.
+func (r *ProverReputation) Admit(ctx context.Context, publicKey string, candidate *TaskCandidate, now time.Time) (bool, error) {
+	stats, err := r.proverStatsOrm.GetProverStats(ctx, publicKey, candidate.ProofType)
+	if err != nil {
+		return false, err
+	}
+
+	delay := time.Duration((1 - proverWeight(stats, candidate.ProofType, r.cfg)) * float64(r.cfg.MaxDelaySec) * float64(time.Second))
+	if now.Sub(candidate.CreatedAt) >= delay {
+		return true, nil
+	}
+	r.deprioritizedTotal.WithLabelValues(candidate.ProofType.String()).Inc()
+	return false, nil
+}
+
+// proverWeight returns the weight of a prover between 0 and 1, its success rate on the proof type scaled down by its
+// average proving time for batches. A prover with too few finished tasks weighs 1.
+func proverWeight(stats *orm.ProverStats, proofType message.ProofType, cfg *config.ProverReputation) float64 {
+	if stats == nil || stats.FinishedTasks() == 0 || stats.FinishedTasks() < uint64(cfg.MinTasks) {
+		return 1
+	}
+
+	weight := float64(stats.TasksSucceeded) / float64(stats.FinishedTasks())
+	if proofType == message.ProofTypeBatch && cfg.BatchProvingTimeSec > 0 {
+		if avg := stats.AverageProvingTimeSec(); avg > float64(cfg.BatchProvingTimeSec) {
+			weight *= float64(cfg.BatchProvingTimeSec) / avg
+		}
+	}
+	return weight
+}
@@ -0,0 +1,33 @@
+package provertask
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+func TestProverWeight(t *testing.T) {
+	cfg := &config.ProverReputation{MinTasks: 10, MaxDelaySec: 600, BatchProvingTimeSec: 1800}
+
+	// a new prover, or one with too few finished tasks, weighs 1.
+	assert.Equal(t, float64(1), proverWeight(nil, message.ProofTypeChunk, cfg))
+	assert.Equal(t, float64(1), proverWeight(&orm.ProverStats{TasksTimeout: 9}, message.ProofTypeChunk, cfg))
+
+	// the weight is the success rate.
+	stats := &orm.ProverStats{TasksSucceeded: 6, TasksFailed: 1, TasksTimeout: 2, InvalidProofs: 1, TotalProvingTimeSec: 6 * 3600}
+	assert.InDelta(t, 0.6, proverWeight(stats, message.ProofTypeChunk, cfg), 1e-9)
+
+	// a prover slower than the expected batch proving time is weighed down.
+	assert.InDelta(t, 0.3, proverWeight(stats, message.ProofTypeBatch, cfg), 1e-9)
+	stats.TotalProvingTimeSec = 6 * 1200
+	assert.InDelta(t, 0.6, proverWeight(stats, message.ProofTypeBatch, cfg), 1e-9)
+
+	cfg.BatchProvingTimeSec = 0
+	stats.TotalProvingTimeSec = 6 * 3600
+	assert.InDelta(t, 0.6, proverWeight(stats, message.ProofTypeBatch, cfg), 1e-9)
+}
@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+// Endpoint is an endpoint of the prover api with its own rate limit.
+type Endpoint string
+
+const (
+	// EndpointLogin is the challenge and login endpoints, limited per client IP.
+	EndpointLogin Endpoint = "login"
+	// EndpointGetTask is the get_task endpoint, limited per prover public key.
+	EndpointGetTask Endpoint = "get_task"
+	// EndpointSubmitProof is the submit_proof endpoint, limited per prover public key.
+	EndpointSubmitProof Endpoint = "submit_proof"
+)
+
+const (
+	reasonRate  = "rate"
+	reasonQuota = "quota"
+
+	// idleTimeout is the time after which the bucket of an idle client is dropped.
+	idleTimeout = 10 * time.Minute
+)
+
+type limiterMetrics struct {
+	rejectedTotal *prometheus.CounterVec
+}
+
+var (
+	initLimiterMetricsOnce sync.Once
+	metrics                *limiterMetrics
+)
+
+func initLimiterMetrics(reg prometheus.Registerer) *limiterMetrics {
+	initLimiterMetricsOnce.Do(func() {
+		metrics = &limiterMetrics{
+			rejectedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "coordinator_rate_limit_rejected_total",
+				Help: "Total number of prover requests rejected by the rate limits or quotas.",
+			}, []string{"endpoint", "reason"}),
+		}
+	})
+	return metrics
+}
+
+// Limiter enforces the rate limits and quotas of the prover api.
+type Limiter struct {
+	maxConcurrentTasks int64
+	buckets            map[Endpoint]*buckets
+
+	proverTaskOrm *orm.ProverTask
+
+	metrics *limiterMetrics
+}
+
+// NewLimiter creates the limiter configured by cfg, a nil cfg returns a nil limiter which allows every request.
+func NewLimiter(cfg *config.RateLimit, db *gorm.DB, reg prometheus.Registerer) *Limiter {
+	if cfg == nil {
+		return nil
+	}
+
+	l := &Limiter{
+		maxConcurrentTasks: 1,
+		buckets:            make(map[Endpoint]*buckets),
+		proverTaskOrm:      orm.NewProverTask(db),
+		metrics:            initLimiterMetrics(reg),
+	}
+	if cfg.MaxConcurrentTasks > 0 {
+		l.maxConcurrentTasks = int64(cfg.MaxConcurrentTasks)
+	}
+	for endpoint, r := range map[Endpoint]*config.Rate{
+		EndpointLogin:       cfg.Login,
+		EndpointGetTask:     cfg.GetTask,
+		EndpointSubmitProof: cfg.SubmitProof,
+	} {
+		if r != nil {
+			l.buckets[endpoint] = newBuckets(rate.Limit(r.RequestsPerSec), r.Burst)
+		}
+	}
+	return l
+}
+
+// Allow reports whether a request of the client identified by key to the endpoint is within the rate limit.
+func (l *Limiter) Allow(endpoint Endpoint, key string) bool {
+	if l == nil {
+		return true
+	}
+	b, ok := l.buckets[endpoint]
+	if !ok || b.allow(key, time.Now()) {
+		return true
+	}
+	l.metrics.rejectedTotal.WithLabelValues(string(endpoint), reasonRate).Inc()
+	return false
+}
+
+// AllowTask reports whether the prover with the given public key may be assigned one more task.
+func (l *Limiter) AllowTask(ctx context.Context, publicKey string) (bool, error) {
+	if l == nil {
+		return true, nil
+	}
+	assigned, err := l.proverTaskOrm.CountAssignedProverTasks(ctx, publicKey)
+	if err != nil {
+		return false, err
+	}
+	if assigned < l.maxConcurrentTasks {
+		return true, nil
+	}
+	l.metrics.rejectedTotal.WithLabelValues(string(EndpointGetTask), reasonQuota).Inc()
+	return false, nil
+}
+
+// buckets holds a token bucket per client, the buckets of the clients idle for idleTimeout are dropped.
+type buckets struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newBuckets(limit rate.Limit, burst int) *buckets {
+	return &buckets{
+		limit:     limit,
+		burst:     burst,
+		clients:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (b *buckets) allow(key string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Sub(b.lastSweep) > idleTimeout {
+		for k, c := range b.clients {
+			if now.Sub(c.lastSeen) > idleTimeout {
+				delete(b.clients, k)
+			}
+		}
+		b.lastSweep = now
+	}
+
+	c, ok := b.clients[key]
+	if !ok {
+		c = &bucket{limiter: rate.NewLimiter(b.limit, b.burst)}
+		b.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuckets(t *testing.T) {
+	b := newBuckets(1, 2)
+	now := time.Now()
+
+	// the burst is allowed at once, then a request per second.
+	assert.True(t, b.allow("prover-0", now))
+	assert.True(t, b.allow("prover-0", now))
+	assert.False(t, b.allow("prover-0", now))
+	assert.True(t, b.allow("prover-0", now.Add(time.Second)))
+
+	// each client has its own bucket.
+	assert.True(t, b.allow("prover-1", now))
+
+	// the idle clients are dropped.
+	assert.True(t, b.allow("prover-1", now.Add(idleTimeout+2*time.Second)))
+	assert.Len(t, b.clients, 1)
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	assert.True(t, l.Allow(EndpointLogin, "127.0.0.1"))
+	allowed, err := l.AllowTask(context.Background(), "0x1234")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
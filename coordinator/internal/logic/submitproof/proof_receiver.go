@@ -14,10 +14,13 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
 
 	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/escalation"
 	"scroll-tech/coordinator/internal/logic/verifier"
 	"scroll-tech/coordinator/internal/orm"
 	coordinatorType "scroll-tech/coordinator/internal/types"
@@ -44,14 +47,18 @@ var (
 
 // ProofReceiverLogic the proof receiver logic
 type ProofReceiverLogic struct {
-	chunkOrm      *orm.Chunk
-	batchOrm      *orm.Batch
-	proverTaskOrm *orm.ProverTask
+	chunkOrm       *orm.Chunk
+	batchOrm       *orm.Batch
+	proverTaskOrm  *orm.ProverTask
+	proverStatsOrm *orm.ProverStats
+	blockListOrm   *orm.ProverBlockList
 
 	db  *gorm.DB
 	cfg *config.ProverManager
 
 	verifier *verifier.Verifier
+	// escalator is nil when task escalation is disabled.
+	escalator *escalation.Escalator
 
 	proofReceivedTotal                    prometheus.Counter
 	proofSubmitFailure                    prometheus.Counter
@@ -63,17 +70,21 @@ type ProofReceiverLogic struct {
 	validateFailureProverTaskStatusNotOk  prometheus.Counter
 	validateFailureProverTaskTimeout      prometheus.Counter
 	validateFailureProverTaskHaveVerifier prometheus.Counter
+	proverBannedTotal                     prometheus.Counter
 }
 
 // NewSubmitProofReceiverLogic create a proof receiver logic
 func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *verifier.Verifier, reg prometheus.Registerer) *ProofReceiverLogic {
 	return &ProofReceiverLogic{
-		chunkOrm:      orm.NewChunk(db),
-		batchOrm:      orm.NewBatch(db),
-		proverTaskOrm: orm.NewProverTask(db),
+		chunkOrm:       orm.NewChunk(db),
+		batchOrm:       orm.NewBatch(db),
+		proverTaskOrm:  orm.NewProverTask(db),
+		proverStatsOrm: orm.NewProverStats(db),
+		blockListOrm:   orm.NewProverBlockList(db),
 
-		cfg: cfg,
-		db:  db,
+		cfg:       cfg,
+		db:        db,
+		escalator: escalation.NewEscalator(cfg.TaskEscalation, db, reg),
 
 		verifier: vf,
 
@@ -118,6 +129,10 @@ func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *ver
 			Name: "coordinator_validate_failure_submit_have_been_verifier",
 			Help: "Total number of submit proof validate failure proof have been verifier.",
 		}),
+		proverBannedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_prover_banned_total",
+			Help: "Total number of provers temporarily blocked for submitting invalid proofs.",
+		}),
 	}
 }
 
@@ -126,6 +141,11 @@ func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *ver
 // db/unmarshal errors will not because they are errors on the business logic side.
 func (m *ProofReceiverLogic) HandleZkProof(ctx *gin.Context, proofMsg *message.ProofMsg, proofParameter coordinatorType.SubmitProofParameter) error {
 	m.proofReceivedTotal.Inc()
+	tracing.SpanFromContext(ctx).SetAttributes("task_id", proofMsg.ID, "task_type", proofMsg.Type.String())
+	if ctx.Request != nil {
+		// the proof updates share the correlation id of the rollup flow of the task.
+		ctx.Request = ctx.Request.WithContext(tracing.WithCorrelationID(ctx, taskCorrelationID(proofMsg.Type, proofMsg.ID)))
+	}
 	pk := ctx.GetString(coordinatorType.PublicKey)
 	if len(pk) == 0 {
 		return fmt.Errorf("get public key from context failed")
@@ -176,6 +196,7 @@ func (m *ProofReceiverLogic) HandleZkProof(ctx *gin.Context, proofMsg *message.P
 		m.verifierFailureTotal.WithLabelValues(pv).Inc()
 
 		m.proofRecover(ctx, proverTask, types.ProverTaskFailureTypeVerifiedFailed, proofMsg)
+		m.banRepeatOffender(ctx, proverTask)
 
 		log.Info("proof verified by coordinator failed", "proof id", proofMsg.ID, "prover name", proverTask.ProverName,
 			"prover pk", pk, "prove type", proofMsg.Type, "proof time", proofTimeSec, "error", verifyErr)
@@ -287,12 +308,43 @@ func (m *ProofReceiverLogic) validator(ctx context.Context, proverTask *orm.Prov
 	return nil
 }
 
+// banRepeatOffender temporarily blocks a prover which submitted too many invalid proofs, see config.InvalidProofBan.
+func (m *ProofReceiverLogic) banRepeatOffender(ctx context.Context, proverTask *orm.ProverTask) {
+	if m.cfg.InvalidProofBan == nil {
+		return
+	}
+
+	since := utils.NowUTC().Add(-time.Duration(m.cfg.InvalidProofBan.WindowSec) * time.Second)
+	invalidProofs, err := m.proverTaskOrm.CountInvalidProofsOfProver(ctx, proverTask.ProverPublicKey, since)
+	if err != nil {
+		log.Error("failed to count invalid proofs of prover", "pubKey", proverTask.ProverPublicKey, "error", err)
+		return
+	}
+	if invalidProofs < int64(m.cfg.InvalidProofBan.MaxInvalidProofs) {
+		return
+	}
+
+	expiresAt := utils.NowUTC().Add(time.Duration(m.cfg.InvalidProofBan.BanDurationSec) * time.Second)
+	if err := m.blockListOrm.InsertTemporaryProverPublicKey(ctx, proverTask.ProverName, proverTask.ProverPublicKey, expiresAt); err != nil {
+		log.Error("failed to block prover", "proverName", proverTask.ProverName, "pubKey", proverTask.ProverPublicKey, "error", err)
+		return
+	}
+	m.proverBannedTotal.Inc()
+	log.Warn("prover blocked for submitting invalid proofs", "proverName", proverTask.ProverName, "pubKey", proverTask.ProverPublicKey,
+		"invalidProofs", invalidProofs, "expiresAt", expiresAt)
+}
+
 func (m *ProofReceiverLogic) proofRecover(ctx context.Context, proverTask *orm.ProverTask, failureType types.ProverTaskFailureType, proofMsg *message.ProofMsg) {
 	log.Info("proof recover update proof status", "hash", proverTask.TaskID, "proverPublicKey", proverTask.ProverPublicKey,
 		"taskType", message.ProofType(proverTask.TaskType).String(), "status", types.ProvingTaskUnassigned.String())
 
 	if err := m.updateProofStatus(ctx, proverTask, proofMsg, types.ProverProofInvalid, failureType, 0); err != nil {
 		log.Error("failed to updated proof status ProvingTaskUnassigned", "hash", proverTask.TaskID, "pubKey", proverTask.ProverPublicKey, "error", err)
+		return
+	}
+
+	if failureType != types.ProverTaskFailureTypeServerError {
+		m.escalator.CheckTask(ctx, message.ProofType(proverTask.TaskType), proverTask.TaskID)
 	}
 }
 
@@ -316,6 +368,11 @@ func (m *ProofReceiverLogic) updateProofStatus(ctx context.Context, proverTask *
 			return updateErr
 		}
 
+		if updateErr := m.proverStatsOrm.IncreaseProverStats(ctx, proverTask.ProverPublicKey, proverTask.ProverName, message.ProofType(proverTask.TaskType), failureType, proofTimeSec, tx); updateErr != nil {
+			log.Error("failed to increase prover stats", "uuid", proverTask.UUID, "error", updateErr)
+			return updateErr
+		}
+
 		switch proofMsg.Type {
 		case message.ProofTypeChunk:
 			if err := m.chunkOrm.DecreaseActiveAttemptsByHash(ctx, proverTask.TaskID, tx); err != nil {
@@ -401,3 +458,15 @@ func (m *ProofReceiverLogic) updateProverTaskProof(ctx context.Context, proverTa
 	}
 	return m.proverTaskOrm.UpdateProverTaskProof(ctx, proverTask.UUID, proofBytes)
 }
+
+// taskCorrelationID returns the correlation id of the rollup flow of the task, e.g. batch:<batch hash>.
+func taskCorrelationID(proofType message.ProofType, taskID string) string {
+	switch proofType {
+	case message.ProofTypeChunk:
+		return "chunk:" + taskID
+	case message.ProofTypeBatch:
+		return "batch:" + taskID
+	default:
+		return taskID
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/common/types"
+)
+
+// AdminAuthMiddleware rejects the requests which don't carry the admin api token as a bearer token.
+func AdminAuthMiddleware(authToken string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, found := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !found || authToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) != 1 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, types.Response{
+				ErrCode: types.ErrAdminAPIUnauthorized,
+				ErrMsg:  "unauthorized",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -1,32 +1,42 @@
 package middleware
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
+	gojwt "github.com/golang-jwt/jwt/v4"
 	"github.com/scroll-tech/go-ethereum/log"
 
 	"scroll-tech/coordinator/internal/config"
 )
 
+// challengeEpochClaim is the rotation epoch of the key signing a challenge.
+const challengeEpochClaim = "epoch"
+
+var errChallengeKeyRotated = errors.New("challenge signed by a rotated key")
+
 // ChallengeMiddleware jwt challenge middleware
 func ChallengeMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
-	jwtMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
+	mw := &jwt.GinJWTMiddleware{
 		Authenticator: func(c *gin.Context) (interface{}, error) {
 			return nil, nil
 		},
 		PayloadFunc: func(data interface{}) jwt.MapClaims {
-			b := make([]byte, 32)
-			_, err := rand.Read(b)
+			random, err := randomString()
 			if err != nil {
 				return jwt.MapClaims{}
 			}
 			return jwt.MapClaims{
-				"random": base64.URLEncoding.EncodeToString(b),
+				"random": random,
 			}
 		},
 		Unauthorized:  unauthorized,
@@ -36,8 +46,12 @@ func ChallengeMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 		TokenHeadName: "Bearer",
 		TimeFunc:      time.Now,
 		LoginResponse: loginResponse,
-	})
+	}
+	if conf.Auth.ChallengeRotationSec > 0 {
+		mw.KeyFunc = challengeKeyFunc(conf.Auth)
+	}
 
+	jwtMiddleware, err := jwt.New(mw)
 	if err != nil {
 		log.Crit("new jwt middleware panic", "error", err)
 	}
@@ -48,3 +62,75 @@ func ChallengeMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 
 	return jwtMiddleware
 }
+
+// ChallengeHandler issues the challenges, signed by the key of the current rotation epoch when the challenge key rotates.
+func ChallengeHandler(conf *config.Config, mw *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	if conf.Auth.ChallengeRotationSec <= 0 {
+		return mw.LoginHandler
+	}
+
+	return func(c *gin.Context) {
+		random, err := randomString()
+		if err != nil {
+			unauthorized(c, http.StatusInternalServerError, jwt.ErrFailedTokenCreation.Error())
+			return
+		}
+
+		now := mw.TimeFunc()
+		epoch := now.Unix() / int64(conf.Auth.ChallengeRotationSec)
+		expire := now.Add(mw.Timeout)
+		token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+			"random":            random,
+			challengeEpochClaim: epoch,
+			"exp":               expire.Unix(),
+			"orig_iat":          now.Unix(),
+		})
+		tokenString, err := token.SignedString(challengeKey(conf.Auth.Secret, epoch))
+		if err != nil {
+			unauthorized(c, http.StatusInternalServerError, jwt.ErrFailedTokenCreation.Error())
+			return
+		}
+		loginResponse(c, http.StatusOK, tokenString, expire)
+	}
+}
+
+// challengeKeyFunc returns the key of the epoch a challenge was signed in, only the current and the previous epochs are
+// accepted.
+func challengeKeyFunc(conf *config.Auth) gojwt.Keyfunc {
+	return func(token *gojwt.Token) (interface{}, error) {
+		if token.Method != gojwt.SigningMethodHS256 {
+			return nil, jwt.ErrInvalidSigningAlgorithm
+		}
+		claims, ok := token.Claims.(gojwt.MapClaims)
+		if !ok {
+			return nil, errChallengeKeyRotated
+		}
+		epoch, ok := claims[challengeEpochClaim].(float64)
+		if !ok {
+			return nil, errChallengeKeyRotated
+		}
+		current := time.Now().Unix() / int64(conf.ChallengeRotationSec)
+		if int64(epoch) != current && int64(epoch) != current-1 {
+			return nil, errChallengeKeyRotated
+		}
+		return challengeKey(conf.Secret, int64(epoch)), nil
+	}
+}
+
+// challengeKey derives the key signing the challenges of an epoch from the secret.
+func challengeKey(secret string, epoch int64) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("challenge"))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(epoch))
+	mac.Write(buf[:])
+	return mac.Sum(nil)
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
@@ -19,6 +19,9 @@ func unauthorized(c *gin.Context, _ int, message string) {
 	err := errors.New(lower)
 	if jwt.ErrExpiredToken.Error() == lower {
 		errCode = types.ErrJWTTokenExpired
+	} else if strings.ToLower(jwt.ErrForbidden.Error()) == lower {
+		// only the revoked tokens are rejected by the authorizator.
+		errCode = types.ErrJWTTokenRevoked
 	} else {
 		errCode = types.ErrJWTCommonErr
 	}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
 	"github.com/scroll-tech/go-ethereum/log"
 
 	"scroll-tech/coordinator/internal/config"
@@ -16,15 +18,18 @@ func LoginMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 	jwtMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
 		PayloadFunc:     api.Auth.PayloadFunc,
 		IdentityHandler: api.Auth.IdentityHandler,
+		Authorizator:    api.Auth.Authorizator,
 		IdentityKey:     types.PublicKey,
 		Key:             []byte(conf.Auth.Secret),
 		Timeout:         time.Second * time.Duration(conf.Auth.LoginExpireDurationSec),
+		MaxRefresh:      time.Second * time.Duration(conf.Auth.MaxRefreshDurationSec),
 		Authenticator:   api.Auth.Login,
 		Unauthorized:    unauthorized,
 		TokenLookup:     "header: Authorization, query: token, cookie: jwt",
 		TokenHeadName:   "Bearer",
 		TimeFunc:        time.Now,
 		LoginResponse:   loginResponse,
+		RefreshResponse: loginResponse,
 	})
 
 	if err != nil {
@@ -37,3 +42,18 @@ func LoginMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 
 	return jwtMiddleware
 }
+
+// RefreshHandler issues a new token to a prover holding a valid one, until MaxRefreshDurationSec since its login.
+// It is served behind the login middleware.
+func RefreshHandler(conf *config.Config, mw *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	maxRefresh := time.Second * time.Duration(conf.Auth.MaxRefreshDurationSec)
+	return func(c *gin.Context) {
+		claims := jwt.ExtractClaims(c)
+		loginAt, ok := claims[types.LoginAt].(float64)
+		if !ok || time.Unix(int64(loginAt), 0).Add(maxRefresh).Before(mw.TimeFunc()) {
+			unauthorized(c, http.StatusUnauthorized, jwt.ErrExpiredToken.Error())
+			return
+		}
+		mw.RefreshHandler(c)
+	}
+}
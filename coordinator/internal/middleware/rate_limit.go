@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// RateLimitMiddleware rejects the requests to the endpoint beyond its rate limit. The login requests are limited per
+// client IP, the other requests per prover public key.
+func RateLimitMiddleware(endpoint ratelimit.Endpoint) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.ClientIP()
+		if endpoint != ratelimit.EndpointLogin {
+			key = ctx.GetString(coordinatorType.PublicKey)
+		}
+		if !api.RateLimiter.Allow(endpoint, key) {
+			rejectRequest(ctx, fmt.Errorf("too many %s requests", endpoint))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// ProverQuotaMiddleware rejects the get_task requests of the provers already assigned the maximum number of tasks.
+func ProverQuotaMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		publicKey := ctx.GetString(coordinatorType.PublicKey)
+		allowed, err := api.RateLimiter.AllowTask(ctx, publicKey)
+		if err != nil {
+			log.Error("failed to check the prover task quota", "public key", publicKey, "err", err)
+			types.RenderFailure(ctx, types.ErrCoordinatorGetTaskFailure, err)
+			ctx.Abort()
+			return
+		}
+		if !allowed {
+			rejectRequest(ctx, fmt.Errorf("prover with publicKey %s is already assigned the maximum number of tasks", publicKey))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func rejectRequest(ctx *gin.Context, err error) {
+	ctx.AbortWithStatusJSON(http.StatusTooManyRequests, types.Response{
+		ErrCode: types.ErrCoordinatorRateLimited,
+		ErrMsg:  err.Error(),
+	})
+}
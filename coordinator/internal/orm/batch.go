@@ -9,6 +9,7 @@ import (
 
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/encoding"
@@ -72,12 +73,21 @@ func (*Batch) TableName() string {
 
 // GetUnassignedBatch retrieves unassigned batch based on the specified limit.
 // The returned batch are sorted in ascending order by their index.
-func (o *Batch) GetUnassignedBatch(ctx context.Context, maxActiveAttempts, maxTotalAttempts uint8) (*Batch, error) {
-	db := o.db.WithContext(ctx)
+// Only the indexes within indexRange are selected, a nil indexRange selects any.
+// Within a transaction the returned row is locked until the transaction ends, the rows locked by the concurrent
+// assignments of any coordinator instance are skipped.
+func (o *Batch) GetUnassignedBatch(ctx context.Context, maxActiveAttempts, maxTotalAttempts uint8, indexRange *IndexRange, dbTX ...*gorm.DB) (*Batch, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0].Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	}
+	db = db.WithContext(ctx)
 	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
 	db = db.Where("total_attempts < ?", maxTotalAttempts)
 	db = db.Where("active_attempts < ?", maxActiveAttempts)
 	db = db.Where("chunk_proofs_status = ?", int(types.ChunkProofsStatusReady))
+	db = indexRange.apply(db)
+	db = db.Order("index ASC")
 
 	var batch Batch
 	err := db.First(&batch).Error
@@ -93,12 +103,21 @@ func (o *Batch) GetUnassignedBatch(ctx context.Context, maxActiveAttempts, maxTo
 
 // GetAssignedBatch retrieves assigned batch based on the specified limit.
 // The returned batch are sorted in ascending order by their index.
-func (o *Batch) GetAssignedBatch(ctx context.Context, maxActiveAttempts, maxTotalAttempts uint8) (*Batch, error) {
-	db := o.db.WithContext(ctx)
+// Only the indexes within indexRange are selected, a nil indexRange selects any.
+// Within a transaction the returned row is locked until the transaction ends, the rows locked by the concurrent
+// assignments of any coordinator instance are skipped.
+func (o *Batch) GetAssignedBatch(ctx context.Context, maxActiveAttempts, maxTotalAttempts uint8, indexRange *IndexRange, dbTX ...*gorm.DB) (*Batch, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0].Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	}
+	db = db.WithContext(ctx)
 	db = db.Where("proving_status = ?", int(types.ProvingTaskAssigned))
 	db = db.Where("total_attempts < ?", maxTotalAttempts)
 	db = db.Where("active_attempts < ?", maxActiveAttempts)
 	db = db.Where("chunk_proofs_status = ?", int(types.ChunkProofsStatusReady))
+	db = indexRange.apply(db)
+	db = db.Order("index ASC")
 
 	var batch Batch
 	err := db.First(&batch).Error
@@ -112,6 +131,27 @@ func (o *Batch) GetAssignedBatch(ctx context.Context, maxActiveAttempts, maxTota
 	return &batch, nil
 }
 
+// GetFirstBatchSinceChunk retrieves the index of the first batch starting at or after the given chunk index, nil if
+// there is no such batch yet.
+func (o *Batch) GetFirstBatchSinceChunk(ctx context.Context, chunkIndex uint64) (*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select("index")
+	db = db.Where("start_chunk_index >= ?", chunkIndex)
+	db = db.Order("index ASC")
+
+	var batch Batch
+	err := db.First(&batch).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Batch.GetFirstBatchSinceChunk error: %w, chunk index: %v", err, chunkIndex)
+	}
+	return &batch, nil
+}
+
 // GetUnassignedAndChunksUnreadyBatches get the batches which is unassigned and chunks is not ready
 func (o *Batch) GetUnassignedAndChunksUnreadyBatches(ctx context.Context, offset, limit int) ([]*Batch, error) {
 	if offset < 0 || limit < 0 {
@@ -172,6 +212,17 @@ func (o *Batch) GetLatestBatch(ctx context.Context) (*Batch, error) {
 	return &latestBatch, nil
 }
 
+// CountBatchesByProvingStatus counts the batches by proving status.
+func (o *Batch) CountBatchesByProvingStatus(ctx context.Context) (map[types.ProvingStatus]int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	counts, err := countByProvingStatus(db)
+	if err != nil {
+		return nil, fmt.Errorf("Batch.CountBatchesByProvingStatus error: %w", err)
+	}
+	return counts, nil
+}
+
 // GetAttemptsByHash get batch attempts by hash. Used by unit test
 func (o *Batch) GetAttemptsByHash(ctx context.Context, hash string) (int16, int16, error) {
 	db := o.db.WithContext(ctx)
@@ -289,8 +340,12 @@ func (o *Batch) UpdateProofAndProvingStatusByHash(ctx context.Context, hash stri
 }
 
 // UpdateBatchAttempts atomically increments the attempts count for the earliest available batch that meets the conditions.
-func (o *Batch) UpdateBatchAttempts(ctx context.Context, index uint64, curActiveAttempts, curTotalAttempts int16) (int64, error) {
-	db := o.db.WithContext(ctx)
+func (o *Batch) UpdateBatchAttempts(ctx context.Context, index uint64, curActiveAttempts, curTotalAttempts int16, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("index = ?", index)
 	db = db.Where("active_attempts = ?", curActiveAttempts)
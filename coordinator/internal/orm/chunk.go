@@ -9,6 +9,7 @@ import (
 
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/encoding"
@@ -70,13 +71,22 @@ func (*Chunk) TableName() string {
 
 // GetUnassignedChunk retrieves unassigned chunk based on the specified limit.
 // The returned chunks are sorted in ascending order by their index.
-func (o *Chunk) GetUnassignedChunk(ctx context.Context, height int, maxActiveAttempts, maxTotalAttempts uint8) (*Chunk, error) {
-	db := o.db.WithContext(ctx)
+// Only the indexes within indexRange are selected, a nil indexRange selects any.
+// Within a transaction the returned row is locked until the transaction ends, the rows locked by the concurrent
+// assignments of any coordinator instance are skipped.
+func (o *Chunk) GetUnassignedChunk(ctx context.Context, height int, maxActiveAttempts, maxTotalAttempts uint8, indexRange *IndexRange, dbTX ...*gorm.DB) (*Chunk, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0].Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Chunk{})
 	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
 	db = db.Where("total_attempts < ?", maxTotalAttempts)
 	db = db.Where("active_attempts < ?", maxActiveAttempts)
 	db = db.Where("end_block_number <= ?", height)
+	db = indexRange.apply(db)
+	db = db.Order("index ASC")
 
 	var chunk Chunk
 	err := db.First(&chunk).Error
@@ -92,13 +102,22 @@ func (o *Chunk) GetUnassignedChunk(ctx context.Context, height int, maxActiveAtt
 
 // GetAssignedChunk retrieves assigned chunk based on the specified limit.
 // The returned chunks are sorted in ascending order by their index.
-func (o *Chunk) GetAssignedChunk(ctx context.Context, height int, maxActiveAttempts, maxTotalAttempts uint8) (*Chunk, error) {
-	db := o.db.WithContext(ctx)
+// Only the indexes within indexRange are selected, a nil indexRange selects any.
+// Within a transaction the returned row is locked until the transaction ends, the rows locked by the concurrent
+// assignments of any coordinator instance are skipped.
+func (o *Chunk) GetAssignedChunk(ctx context.Context, height int, maxActiveAttempts, maxTotalAttempts uint8, indexRange *IndexRange, dbTX ...*gorm.DB) (*Chunk, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0].Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Chunk{})
 	db = db.Where("proving_status = ?", int(types.ProvingTaskAssigned))
 	db = db.Where("total_attempts < ?", maxTotalAttempts)
 	db = db.Where("active_attempts < ?", maxActiveAttempts)
 	db = db.Where("end_block_number <= ?", height)
+	db = indexRange.apply(db)
+	db = db.Order("index ASC")
 
 	var chunk Chunk
 	err := db.First(&chunk).Error
@@ -112,6 +131,27 @@ func (o *Chunk) GetAssignedChunk(ctx context.Context, height int, maxActiveAttem
 	return &chunk, nil
 }
 
+// GetFirstChunkSinceBlock retrieves the index of the first chunk starting at or after the given block number, nil if
+// there is no such chunk yet.
+func (o *Chunk) GetFirstChunkSinceBlock(ctx context.Context, blockNumber uint64) (*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Select("index")
+	db = db.Where("start_block_number >= ?", blockNumber)
+	db = db.Order("index ASC")
+
+	var chunk Chunk
+	err := db.First(&chunk).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Chunk.GetFirstChunkSinceBlock error: %w, block number: %v", err, blockNumber)
+	}
+	return &chunk, nil
+}
+
 // GetChunksByBatchHash retrieves the chunks associated with a specific batch hash.
 // The returned chunks are sorted in ascending order by their associated chunk index.
 func (o *Chunk) GetChunksByBatchHash(ctx context.Context, batchHash string) ([]*Chunk, error) {
@@ -166,6 +206,36 @@ func (o *Chunk) GetLatestChunk(ctx context.Context) (*Chunk, error) {
 	return &latestChunk, nil
 }
 
+// GetOldestUnprovenChunk retrieves the chunk of the lowest index whose proof is neither generated nor failed, nil if
+// every chunk is proved.
+func (o *Chunk) GetOldestUnprovenChunk(ctx context.Context) (*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("proving_status IN ?", []int{int(types.ProvingTaskUnassigned), int(types.ProvingTaskAssigned)})
+	db = db.Order("index ASC")
+
+	var chunk Chunk
+	err := db.First(&chunk).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Chunk.GetOldestUnprovenChunk error: %w", err)
+	}
+	return &chunk, nil
+}
+
+// CountChunksByProvingStatus counts the chunks by proving status.
+func (o *Chunk) CountChunksByProvingStatus(ctx context.Context) (map[types.ProvingStatus]int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	counts, err := countByProvingStatus(db)
+	if err != nil {
+		return nil, fmt.Errorf("Chunk.CountChunksByProvingStatus error: %w", err)
+	}
+	return counts, nil
+}
+
 // GetProvingStatusByHash retrieves the proving status of a chunk given its hash.
 func (o *Chunk) GetProvingStatusByHash(ctx context.Context, hash string) (types.ProvingStatus, error) {
 	db := o.db.WithContext(ctx)
@@ -367,8 +437,12 @@ func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, e
 }
 
 // UpdateChunkAttempts atomically increments the attempts count for the earliest available chunk that meets the conditions.
-func (o *Chunk) UpdateChunkAttempts(ctx context.Context, index uint64, curActiveAttempts, curTotalAttempts int16) (int64, error) {
-	db := o.db.WithContext(ctx)
+func (o *Chunk) UpdateChunkAttempts(ctx context.Context, index uint64, curActiveAttempts, curTotalAttempts int16, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Chunk{})
 	db = db.Where("index = ?", index)
 	db = db.Where("active_attempts = ?", curActiveAttempts)
@@ -0,0 +1,21 @@
+package orm
+
+import "gorm.io/gorm"
+
+// IndexRange restricts the tasks selected to the indexes in [From, To), a nil To is unbounded. A nil IndexRange
+// selects any task.
+type IndexRange struct {
+	From uint64
+	To   *uint64
+}
+
+func (r *IndexRange) apply(db *gorm.DB) *gorm.DB {
+	if r == nil {
+		return db
+	}
+	db = db.Where("index >= ?", r.From)
+	if r.To != nil {
+		db = db.Where("index < ?", *r.To)
+	}
+	return db
+}
@@ -2,8 +2,10 @@ package orm
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -21,8 +23,10 @@ import (
 var (
 	base *docker.App
 
-	db            *gorm.DB
-	proverTaskOrm *ProverTask
+	db             *gorm.DB
+	proverTaskOrm  *ProverTask
+	proverStatsOrm *ProverStats
+	escalationOrm  *TaskEscalation
 )
 
 func TestMain(m *testing.M) {
@@ -50,6 +54,8 @@ func setupEnv(t *testing.T) {
 	assert.NoError(t, migrate.ResetDB(sqlDB))
 
 	proverTaskOrm = NewProverTask(db)
+	proverStatsOrm = NewProverStats(db)
+	escalationOrm = NewTaskEscalation(db)
 }
 
 func tearDownEnv(t *testing.T) {
@@ -124,3 +130,285 @@ func TestProverTaskOrmUint256(t *testing.T) {
 	assert.Equal(t, resultRewardUint256, rewardUint256)
 	assert.Equal(t, resultRewardUint256.String(), "115792089237316195423570985008687907853269984665640564039457584007913129639935")
 }
+
+func TestProverStatsOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	stats, err := proverStatsOrm.GetProverStats(context.Background(), "0", message.ProofTypeChunk)
+	assert.NoError(t, err)
+	assert.Nil(t, stats)
+
+	err = proverStatsOrm.IncreaseProverStats(context.Background(), "0", "prover-0", message.ProofTypeChunk, types.ProverTaskFailureTypeUndefined, 100)
+	assert.NoError(t, err)
+	err = proverStatsOrm.IncreaseProverStats(context.Background(), "0", "prover-0", message.ProofTypeChunk, types.ProverTaskFailureTypeUndefined, 200)
+	assert.NoError(t, err)
+	err = proverStatsOrm.IncreaseProverStats(context.Background(), "0", "prover-0", message.ProofTypeChunk, types.ProverTaskFailureTypeTimeout, 0)
+	assert.NoError(t, err)
+	err = proverStatsOrm.IncreaseProverStats(context.Background(), "0", "prover-0", message.ProofTypeChunk, types.ProverTaskFailureTypeVerifiedFailed, 0)
+	assert.NoError(t, err)
+	err = proverStatsOrm.IncreaseProverStats(context.Background(), "0", "prover-0", message.ProofTypeChunk, types.ProverTaskFailureTypeServerError, 0)
+	assert.NoError(t, err)
+	err = proverStatsOrm.IncreaseProverStats(context.Background(), "0", "prover-0", message.ProofTypeBatch, types.ProverTaskFailureTypeSubmitStatusNotOk, 0)
+	assert.NoError(t, err)
+
+	stats, err = proverStatsOrm.GetProverStats(context.Background(), "0", message.ProofTypeChunk)
+	assert.NoError(t, err)
+	assert.NotNil(t, stats)
+	assert.Equal(t, uint64(2), stats.TasksSucceeded)
+	assert.Equal(t, uint64(1), stats.TasksTimeout)
+	assert.Equal(t, uint64(1), stats.InvalidProofs)
+	assert.Equal(t, uint64(4), stats.FinishedTasks())
+	assert.Equal(t, float64(150), stats.AverageProvingTimeSec())
+
+	statsList, err := proverStatsOrm.ListProverStats(context.Background(), "0", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, statsList, 2)
+	assert.Equal(t, int16(message.ProofTypeBatch), statsList[1].TaskType)
+	assert.Equal(t, uint64(1), statsList[1].TasksFailed)
+
+	statsList, err = proverStatsOrm.ListProverStats(context.Background(), "1", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, statsList, 0)
+}
+
+func TestProverHeartbeatOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	heartbeatOrm := NewProverHeartbeat(db)
+	heartbeat, err := heartbeatOrm.GetProverHeartbeat(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.Nil(t, heartbeat)
+
+	assert.NoError(t, heartbeatOrm.UpsertProverHeartbeat(context.Background(), &ProverHeartbeat{ProverPublicKey: "0", ProverName: "prover-0", GPUMemoryMB: 1000, TaskProgress: 10}))
+	// the latest heartbeat replaces the previous one.
+	assert.NoError(t, heartbeatOrm.UpsertProverHeartbeat(context.Background(), &ProverHeartbeat{ProverPublicKey: "0", ProverName: "prover-0", GPUMemoryMB: 800, CircuitVersions: "v0.12,v0.13", TaskProgress: 50, RecentErrors: 2}))
+	assert.NoError(t, heartbeatOrm.UpsertProverHeartbeat(context.Background(), &ProverHeartbeat{ProverPublicKey: "1", ProverName: "prover-1"}))
+
+	heartbeat, err = heartbeatOrm.GetProverHeartbeat(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.NotNil(t, heartbeat)
+	assert.Equal(t, uint64(800), heartbeat.GPUMemoryMB)
+	assert.Equal(t, "v0.12,v0.13", heartbeat.CircuitVersions)
+	assert.Equal(t, int16(50), heartbeat.TaskProgress)
+	assert.Equal(t, uint64(2), heartbeat.RecentErrors)
+
+	heartbeats, err := heartbeatOrm.ListProverHeartbeats(context.Background(), "", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, heartbeats, 2)
+	assert.Equal(t, "prover-1", heartbeats[1].ProverName)
+
+	heartbeats, err = heartbeatOrm.ListProverHeartbeats(context.Background(), "1", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, heartbeats, 1)
+}
+
+func TestTaskEscalationOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	for i, failureType := range []types.ProverTaskFailureType{types.ProverTaskFailureTypeTimeout, types.ProverTaskFailureTypeVerifiedFailed, types.ProverTaskFailureTypeServerError} {
+		proverTask := ProverTask{
+			TaskType:        int16(message.ProofTypeBatch),
+			TaskID:          "test-hash",
+			ProverName:      "prover-0",
+			ProverPublicKey: fmt.Sprintf("%d", i),
+			ProvingStatus:   int16(types.ProverProofInvalid),
+			FailureType:     int16(failureType),
+			Reward:          decimal.NewFromInt(0),
+			AssignedAt:      utils.NowUTC(),
+		}
+		assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+	}
+
+	// the server errors are not counted.
+	failures, err := proverTaskOrm.CountFailedProverTasks(context.Background(), message.ProofTypeBatch, "test-hash")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), failures)
+
+	escalated, err := escalationOrm.InsertTaskEscalation(context.Background(), message.ProofTypeBatch, "test-hash", int16(failures))
+	assert.NoError(t, err)
+	assert.True(t, escalated)
+	escalated, err = escalationOrm.InsertTaskEscalation(context.Background(), message.ProofTypeBatch, "test-hash", 3)
+	assert.NoError(t, err)
+	assert.False(t, escalated)
+
+	escalations, err := escalationOrm.GetTaskEscalations(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, escalations, 1)
+	assert.Equal(t, "test-hash", escalations[0].TaskID)
+	assert.Equal(t, int16(2), escalations[0].Failures)
+}
+
+func TestProverBlockListOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	blockListOrm := NewProverBlockList(db)
+	assert.NoError(t, blockListOrm.InsertTemporaryProverPublicKey(context.Background(), "prover-0", "0", utils.NowUTC().Add(time.Hour)))
+	assert.NoError(t, blockListOrm.InsertTemporaryProverPublicKey(context.Background(), "prover-1", "1", utils.NowUTC().Add(-time.Hour)))
+
+	blocked, err := blockListOrm.IsPublicKeyBlocked(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+
+	// the block of prover-1 expired.
+	blocked, err = blockListOrm.IsPublicKeyBlocked(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	assert.NoError(t, blockListOrm.InsertProverPublicKey(context.Background(), "prover-1", "1"))
+	blocked, err = blockListOrm.IsPublicKeyBlocked(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+}
+
+func TestProverAllowlistOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	allowlistOrm := NewProverAllowlist(db)
+	assert.NoError(t, allowlistOrm.InsertProverPublicKey(context.Background(), "prover-0", "0"))
+	// a listed key is renamed.
+	assert.NoError(t, allowlistOrm.InsertProverPublicKey(context.Background(), "prover-1", "0"))
+
+	allowed, err := allowlistOrm.IsPublicKeyAllowed(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = allowlistOrm.IsPublicKeyAllowed(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	provers, err := allowlistOrm.ListProverPublicKeys(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, provers, 1)
+	assert.Equal(t, "prover-1", provers[0].ProverName)
+
+	deleted, err := allowlistOrm.DeleteProverPublicKey(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = allowlistOrm.DeleteProverPublicKey(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	allowed, err = allowlistOrm.IsPublicKeyAllowed(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	// a removed key can be listed again.
+	assert.NoError(t, allowlistOrm.InsertProverPublicKey(context.Background(), "prover-0", "0"))
+	allowed, err = allowlistOrm.IsPublicKeyAllowed(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestProverTokenRevocationOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	revocationOrm := NewProverTokenRevocation(db)
+	revokedAt, err := revocationOrm.GetRevokedAt(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.Nil(t, revokedAt)
+
+	first := utils.NowUTC().Add(-time.Hour).Truncate(time.Second)
+	latest := utils.NowUTC().Truncate(time.Second)
+	assert.NoError(t, revocationOrm.RevokeTokens(context.Background(), "0", first))
+	assert.NoError(t, revocationOrm.RevokeTokens(context.Background(), "0", latest))
+
+	revokedAt, err = revocationOrm.GetRevokedAt(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.NotNil(t, revokedAt)
+	assert.Equal(t, latest.Unix(), revokedAt.Unix())
+}
+
+func TestLockAssignedProverTask(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "test-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		Reward:          decimal.NewFromInt(0),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+	proverTasks, err := proverTaskOrm.GetProverTasksByHashes(context.Background(), message.ProofTypeChunk, []string{"test-hash"})
+	assert.NoError(t, err)
+	assert.Len(t, proverTasks, 1)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		locked, err := proverTaskOrm.LockAssignedProverTask(context.Background(), proverTasks[0].UUID, tx)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		// another instance skips the prover task locked.
+		return db.Transaction(func(otherTx *gorm.DB) error {
+			locked, err := proverTaskOrm.LockAssignedProverTask(context.Background(), proverTasks[0].UUID, otherTx)
+			assert.NoError(t, err)
+			assert.False(t, locked)
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, proverTaskOrm.UpdateProverTaskProvingStatusAndFailureType(context.Background(), proverTasks[0].UUID, types.ProverProofInvalid, types.ProverTaskFailureTypeTimeout))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		locked, err := proverTaskOrm.LockAssignedProverTask(context.Background(), proverTasks[0].UUID, tx)
+		assert.NoError(t, err)
+		assert.False(t, locked)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestPipelineStatusOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	for i, status := range []types.ProverProveStatus{types.ProverAssigned, types.ProverAssigned, types.ProverProofInvalid, types.ProverProofValid} {
+		proverTask := ProverTask{
+			TaskType:        int16(message.ProofTypeChunk),
+			TaskID:          fmt.Sprintf("test-hash-%d", i),
+			ProverName:      "prover-0",
+			ProverPublicKey: fmt.Sprintf("%d", i%2),
+			ProvingStatus:   int16(status),
+			Reward:          decimal.NewFromInt(0),
+			AssignedAt:      utils.NowUTC(),
+		}
+		assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+	}
+
+	assignedTasks, err := proverTaskOrm.GetAssignedProverTasks(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, assignedTasks, 2)
+	assert.Equal(t, "0", assignedTasks[0].ProverPublicKey)
+	assert.Equal(t, "1", assignedTasks[1].ProverPublicKey)
+
+	failedTasks, err := proverTaskOrm.GetRecentFailedProverTasks(context.Background(), utils.NowUTC().Add(-time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, failedTasks, 1)
+	assert.Equal(t, "test-hash-2", failedTasks[0].TaskID)
+	failedTasks, err = proverTaskOrm.GetRecentFailedProverTasks(context.Background(), utils.NowUTC().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, failedTasks)
+
+	chunkOrm := NewChunk(db)
+	counts, err := chunkOrm.CountChunksByProvingStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+	oldestChunk, err := chunkOrm.GetOldestUnprovenChunk(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, oldestChunk)
+}
@@ -0,0 +1,102 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProverAllowlist represents a prover public key allowed to login.
+type ProverAllowlist struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID         uint   `json:"id" gorm:"column:id;primaryKey"`
+	PublicKey  string `json:"public_key" gorm:"column:public_key"`
+	ProverName string `json:"prover_name" gorm:"column:prover_name;default:''"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverAllowlist creates a new ProverAllowlist instance.
+func NewProverAllowlist(db *gorm.DB) *ProverAllowlist {
+	return &ProverAllowlist{db: db}
+}
+
+// TableName returns the name of the "prover_allowlist" table.
+func (*ProverAllowlist) TableName() string {
+	return "prover_allowlist"
+}
+
+// InsertProverPublicKey adds a prover public key to the allowlist, the prover name of a listed key is updated.
+func (p *ProverAllowlist) InsertProverPublicKey(ctx context.Context, proverName, publicKey string) error {
+	prover := ProverAllowlist{
+		ProverName: proverName,
+		PublicKey:  publicKey,
+	}
+
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverAllowlist{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "public_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"prover_name": proverName,
+			"updated_at":  time.Now(),
+		}),
+	})
+	if err := db.Create(&prover).Error; err != nil {
+		return fmt.Errorf("ProverAllowlist.InsertProverPublicKey error: %w, prover name: %v, public key: %v", err, proverName, publicKey)
+	}
+	return nil
+}
+
+// DeleteProverPublicKey removes a prover public key from the allowlist, it returns whether the key was listed.
+func (p *ProverAllowlist) DeleteProverPublicKey(ctx context.Context, publicKey string) (bool, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Where("public_key = ?", publicKey)
+	result := db.Delete(&ProverAllowlist{})
+	if result.Error != nil {
+		return false, fmt.Errorf("ProverAllowlist.DeleteProverPublicKey error: %w, public key: %v", result.Error, publicKey)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsPublicKeyAllowed checks if the given public key is in the allowlist.
+func (p *ProverAllowlist) IsPublicKeyAllowed(ctx context.Context, publicKey string) (bool, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverAllowlist{})
+	db = db.Where("public_key = ?", publicKey)
+	if err := db.First(&ProverAllowlist{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("ProverAllowlist.IsPublicKeyAllowed error: %w, public key: %v", err, publicKey)
+	}
+	return true, nil
+}
+
+// ListProverPublicKeys retrieves the allowlist sorted by prover name and public key.
+func (p *ProverAllowlist) ListProverPublicKeys(ctx context.Context, offset, limit int) ([]*ProverAllowlist, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, errors.New("offset must not be smaller than 0 and limit must be greater than zero")
+	}
+
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverAllowlist{})
+	db = db.Order("prover_name ASC, public_key ASC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var provers []*ProverAllowlist
+	if err := db.Find(&provers).Error; err != nil {
+		return nil, fmt.Errorf("ProverAllowlist.ListProverPublicKeys error: %w", err)
+	}
+	return provers, nil
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"scroll-tech/common/utils"
 )
 
 // ProverBlockList represents the prover's block entry in the database.
@@ -15,6 +17,8 @@ type ProverBlockList struct {
 	ID         uint   `json:"id" gorm:"column:id;primaryKey"`
 	ProverName string `json:"prover_name" gorm:"column:prover_name"`
 	PublicKey  string `json:"public_key" gorm:"column:public_key"`
+	// ExpiresAt is the end of a temporary block, nil blocks the prover until removed.
+	ExpiresAt *time.Time `json:"expires_at" gorm:"column:expires_at;default:NULL"`
 
 	// metadata
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
@@ -48,6 +52,22 @@ func (p *ProverBlockList) InsertProverPublicKey(ctx context.Context, proverName,
 	return nil
 }
 
+// InsertTemporaryProverPublicKey blocks a Prover public key until expiresAt.
+func (p *ProverBlockList) InsertTemporaryProverPublicKey(ctx context.Context, proverName, publicKey string, expiresAt time.Time) error {
+	prover := ProverBlockList{
+		ProverName: proverName,
+		PublicKey:  publicKey,
+		ExpiresAt:  &expiresAt,
+	}
+
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverBlockList{})
+	if err := db.Create(&prover).Error; err != nil {
+		return fmt.Errorf("ProverBlockList.InsertTemporaryProverPublicKey error: %w, prover name: %v, public key: %v, expires at: %v", err, proverName, publicKey, expiresAt)
+	}
+	return nil
+}
+
 // DeleteProverPublicKey marks a Prover public key as deleted in the block list.
 // for unit test only.
 func (p *ProverBlockList) DeleteProverPublicKey(ctx context.Context, publicKey string) error {
@@ -59,11 +79,12 @@ func (p *ProverBlockList) DeleteProverPublicKey(ctx context.Context, publicKey s
 	return nil
 }
 
-// IsPublicKeyBlocked checks if the given public key is blocked.
+// IsPublicKeyBlocked checks if the given public key is blocked, the expired temporary blocks are ignored.
 func (p *ProverBlockList) IsPublicKeyBlocked(ctx context.Context, publicKey string) (bool, error) {
 	db := p.db.WithContext(ctx)
 	db = db.Model(&ProverBlockList{})
 	db = db.Where("public_key = ?", publicKey)
+	db = db.Where("expires_at IS NULL OR expires_at > ?", utils.NowUTC())
 	if err := db.First(&ProverBlockList{}).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return false, nil // Public key not found, hence it's not blocked.
@@ -0,0 +1,107 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProverHeartbeat represents the latest heartbeat of a prover.
+type ProverHeartbeat struct {
+	db *gorm.DB `gorm:"-"`
+
+	ProverPublicKey string `json:"prover_public_key" gorm:"column:prover_public_key"`
+	ProverName      string `json:"prover_name" gorm:"column:prover_name"`
+	ProverVersion   string `json:"prover_version" gorm:"column:prover_version"`
+
+	GPUMemoryMB uint64 `json:"gpu_memory_mb" gorm:"column:gpu_memory_mb;default:0"`
+	// CircuitVersions are the comma separated circuit versions supported by the prover.
+	CircuitVersions string `json:"circuit_versions" gorm:"column:circuit_versions;default:''"`
+	TaskProgress    int16  `json:"task_progress" gorm:"column:task_progress;default:0"`
+	RecentErrors    uint64 `json:"recent_errors" gorm:"column:recent_errors;default:0"`
+
+	// metadata, UpdatedAt is the time the heartbeat was received
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverHeartbeat creates a new ProverHeartbeat instance.
+func NewProverHeartbeat(db *gorm.DB) *ProverHeartbeat {
+	return &ProverHeartbeat{db: db}
+}
+
+// TableName returns the name of the "prover_heartbeat" table.
+func (*ProverHeartbeat) TableName() string {
+	return "prover_heartbeat"
+}
+
+// GetProverHeartbeat retrieves the latest heartbeat of a prover, nil if the prover sent none.
+func (o *ProverHeartbeat) GetProverHeartbeat(ctx context.Context, publicKey string) (*ProverHeartbeat, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverHeartbeat{})
+	db = db.Where("prover_public_key = ?", publicKey)
+
+	var heartbeat ProverHeartbeat
+	if err := db.First(&heartbeat).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ProverHeartbeat.GetProverHeartbeat error: %w, public key: %v", err, publicKey)
+	}
+	return &heartbeat, nil
+}
+
+// ListProverHeartbeats retrieves the latest heartbeats of the provers, of a single prover if publicKey is not empty.
+// The returned heartbeats are sorted by prover name and public key.
+func (o *ProverHeartbeat) ListProverHeartbeats(ctx context.Context, publicKey string, offset, limit int) ([]*ProverHeartbeat, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, errors.New("offset must not be smaller than 0 and limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverHeartbeat{})
+	if publicKey != "" {
+		db = db.Where("prover_public_key = ?", publicKey)
+	}
+	db = db.Order("prover_name ASC, prover_public_key ASC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var heartbeats []*ProverHeartbeat
+	if err := db.Find(&heartbeats).Error; err != nil {
+		return nil, fmt.Errorf("ProverHeartbeat.ListProverHeartbeats error: %w, public key: %v", err, publicKey)
+	}
+	return heartbeats, nil
+}
+
+// UpsertProverHeartbeat records the heartbeat of a prover, replacing its previous one.
+func (o *ProverHeartbeat) UpsertProverHeartbeat(ctx context.Context, heartbeat *ProverHeartbeat, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverHeartbeat{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "prover_public_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"prover_name":      heartbeat.ProverName,
+			"prover_version":   heartbeat.ProverVersion,
+			"gpu_memory_mb":    heartbeat.GPUMemoryMB,
+			"circuit_versions": heartbeat.CircuitVersions,
+			"task_progress":    heartbeat.TaskProgress,
+			"recent_errors":    heartbeat.RecentErrors,
+			"updated_at":       time.Now(),
+		}),
+	})
+
+	if err := db.Create(heartbeat).Error; err != nil {
+		return fmt.Errorf("ProverHeartbeat.UpsertProverHeartbeat error: %w, public key: %v", err, heartbeat.ProverPublicKey)
+	}
+	return nil
+}
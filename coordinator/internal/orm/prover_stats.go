@@ -0,0 +1,141 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+)
+
+// ProverStats represents the outcome of the tasks of a proof type finished by a prover.
+type ProverStats struct {
+	db *gorm.DB `gorm:"-"`
+
+	ProverPublicKey string `json:"prover_public_key" gorm:"column:prover_public_key"`
+	ProverName      string `json:"prover_name" gorm:"column:prover_name"`
+	TaskType        int16  `json:"task_type" gorm:"column:task_type"`
+
+	TasksSucceeded      uint64 `json:"tasks_succeeded" gorm:"column:tasks_succeeded;default:0"`
+	TasksFailed         uint64 `json:"tasks_failed" gorm:"column:tasks_failed;default:0"`
+	TasksTimeout        uint64 `json:"tasks_timeout" gorm:"column:tasks_timeout;default:0"`
+	InvalidProofs       uint64 `json:"invalid_proofs" gorm:"column:invalid_proofs;default:0"`
+	TotalProvingTimeSec uint64 `json:"total_proving_time_sec" gorm:"column:total_proving_time_sec;default:0"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverStats creates a new ProverStats instance.
+func NewProverStats(db *gorm.DB) *ProverStats {
+	return &ProverStats{db: db}
+}
+
+// TableName returns the name of the "prover_stats" table.
+func (*ProverStats) TableName() string {
+	return "prover_stats"
+}
+
+// FinishedTasks returns the number of tasks finished by the prover, whatever their outcome.
+func (o *ProverStats) FinishedTasks() uint64 {
+	return o.TasksSucceeded + o.TasksFailed + o.TasksTimeout + o.InvalidProofs
+}
+
+// AverageProvingTimeSec returns the average proving time of the succeeded tasks, 0 if none succeeded.
+func (o *ProverStats) AverageProvingTimeSec() float64 {
+	if o.TasksSucceeded == 0 {
+		return 0
+	}
+	return float64(o.TotalProvingTimeSec) / float64(o.TasksSucceeded)
+}
+
+// GetProverStats retrieves the statistics of a prover for a proof type, nil if the prover finished no such task.
+func (o *ProverStats) GetProverStats(ctx context.Context, publicKey string, taskType message.ProofType) (*ProverStats, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverStats{})
+	db = db.Where("prover_public_key = ?", publicKey)
+	db = db.Where("task_type = ?", int16(taskType))
+
+	var stats ProverStats
+	if err := db.First(&stats).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ProverStats.GetProverStats error: %w, public key: %v, task type: %v", err, publicKey, taskType.String())
+	}
+	return &stats, nil
+}
+
+// ListProverStats retrieves the statistics of the provers, of a single prover if publicKey is not empty.
+// The returned statistics are sorted by prover name, public key and task type.
+func (o *ProverStats) ListProverStats(ctx context.Context, publicKey string, offset, limit int) ([]*ProverStats, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, errors.New("offset must not be smaller than 0 and limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverStats{})
+	if publicKey != "" {
+		db = db.Where("prover_public_key = ?", publicKey)
+	}
+	db = db.Order("prover_name ASC, prover_public_key ASC, task_type ASC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var stats []*ProverStats
+	if err := db.Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("ProverStats.ListProverStats error: %w, public key: %v", err, publicKey)
+	}
+	return stats, nil
+}
+
+// IncreaseProverStats records a task finished by a prover, succeeded when failureType is
+// ProverTaskFailureTypeUndefined. Server errors are not caused by the prover and are not recorded.
+func (o *ProverStats) IncreaseProverStats(ctx context.Context, publicKey, proverName string, taskType message.ProofType, failureType types.ProverTaskFailureType, provingTimeSec uint64, dbTX ...*gorm.DB) error {
+	stats := ProverStats{
+		ProverPublicKey: publicKey,
+		ProverName:      proverName,
+		TaskType:        int16(taskType),
+	}
+	var column string
+	switch failureType {
+	case types.ProverTaskFailureTypeUndefined:
+		column, stats.TasksSucceeded, stats.TotalProvingTimeSec = "tasks_succeeded", 1, provingTimeSec
+	case types.ProverTaskFailureTypeSubmitStatusNotOk:
+		column, stats.TasksFailed = "tasks_failed", 1
+	case types.ProverTaskFailureTypeTimeout:
+		column, stats.TasksTimeout = "tasks_timeout", 1
+	case types.ProverTaskFailureTypeVerifiedFailed:
+		column, stats.InvalidProofs = "invalid_proofs", 1
+	default:
+		return nil
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverStats{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "prover_public_key"}, {Name: "task_type"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"prover_name":            proverName,
+			column:                   gorm.Expr("prover_stats." + column + " + 1"),
+			"total_proving_time_sec": gorm.Expr("prover_stats.total_proving_time_sec + ?", stats.TotalProvingTimeSec),
+			"updated_at":             time.Now(),
+		}),
+	})
+
+	if err := db.Create(&stats).Error; err != nil {
+		return fmt.Errorf("ProverStats.IncreaseProverStats error: %w, public key: %v, task type: %v, failure type: %v", err, publicKey, taskType.String(), failureType.String())
+	}
+	return nil
+}
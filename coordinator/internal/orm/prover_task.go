@@ -68,6 +68,20 @@ func (o *ProverTask) IsProverAssigned(ctx context.Context, publicKey string) (bo
 	return true, nil
 }
 
+// CountAssignedProverTasks counts the prover tasks a prover with the given public key is assigned.
+func (o *ProverTask) CountAssignedProverTasks(ctx context.Context, publicKey string) (int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("prover_public_key", publicKey)
+	db = db.Where("proving_status", int(types.ProverAssigned))
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("ProverTask.CountAssignedProverTasks error: %w, public key: %v", err, publicKey)
+	}
+	return count, nil
+}
+
 // GetProverTasks get prover tasks
 func (o *ProverTask) GetProverTasks(ctx context.Context, fields map[string]interface{}, orderByList []string, offset, limit int) ([]ProverTask, error) {
 	db := o.db.WithContext(ctx)
@@ -198,6 +212,53 @@ func (o *ProverTask) GetTimeoutAssignedProverTasks(ctx context.Context, limit in
 	return proverTasks, nil
 }
 
+// GetAssignedProverTasks retrieves the prover tasks being proved, ordered by prover and assignment time.
+func (o *ProverTask) GetAssignedProverTasks(ctx context.Context) ([]ProverTask, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("proving_status", int(types.ProverAssigned))
+	db = db.Order("prover_public_key ASC, assigned_at ASC")
+
+	var proverTasks []ProverTask
+	if err := db.Find(&proverTasks).Error; err != nil {
+		return nil, fmt.Errorf("ProverTask.GetAssignedProverTasks error: %w", err)
+	}
+	return proverTasks, nil
+}
+
+// GetRecentFailedProverTasks retrieves the prover tasks which failed since the given time, the latest failed first.
+func (o *ProverTask) GetRecentFailedProverTasks(ctx context.Context, since time.Time, limit int) ([]ProverTask, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("proving_status", int(types.ProverProofInvalid))
+	db = db.Where("updated_at >= ?", since)
+	db = db.Order("updated_at DESC")
+	db = db.Limit(limit)
+
+	var proverTasks []ProverTask
+	if err := db.Find(&proverTasks).Error; err != nil {
+		return nil, fmt.Errorf("ProverTask.GetRecentFailedProverTasks error: %w, since: %v", err, since)
+	}
+	return proverTasks, nil
+}
+
+// LockAssignedProverTask locks a prover task still assigned until the transaction ends. It returns false if the prover
+// task is no longer assigned or is locked by another coordinator instance handling it.
+func (o *ProverTask) LockAssignedProverTask(ctx context.Context, taskUUID uuid.UUID, dbTX *gorm.DB) (bool, error) {
+	db := dbTX.WithContext(ctx)
+	db = db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	db = db.Model(&ProverTask{})
+	db = db.Select("uuid")
+	db = db.Where("uuid = ?", taskUUID)
+	db = db.Where("proving_status", int(types.ProverAssigned))
+
+	var proverTasks []ProverTask
+	if err := db.Find(&proverTasks).Error; err != nil {
+		return false, fmt.Errorf("ProverTask.LockAssignedProverTask error: %w, uuid: %v", err, taskUUID)
+	}
+	return len(proverTasks) > 0, nil
+}
+
 // TaskTimeoutMoreThanOnce get the timeout twice task. a temp design
 func (o *ProverTask) TaskTimeoutMoreThanOnce(ctx context.Context, taskType message.ProofType, taskID string) bool {
 	db := o.db.WithContext(ctx)
@@ -218,6 +279,39 @@ func (o *ProverTask) TaskTimeoutMoreThanOnce(ctx context.Context, taskType messa
 	return false
 }
 
+// CountFailedProverTasks counts the prover tasks of a task which failed because of the prover: the timed out tasks,
+// the invalid proofs and the failures reported by the provers.
+func (o *ProverTask) CountFailedProverTasks(ctx context.Context, taskType message.ProofType, taskID string) (int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("task_type", int(taskType))
+	db = db.Where("task_id", taskID)
+	db = db.Where("proving_status", int(types.ProverProofInvalid))
+	db = db.Where("failure_type != ?", int(types.ProverTaskFailureTypeServerError))
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("ProverTask.CountFailedProverTasks error: %w, task type: %v, task id: %v", err, taskType.String(), taskID)
+	}
+	return count, nil
+}
+
+// CountInvalidProofsOfProver counts the prover tasks assigned to a prover since the given time whose proofs failed
+// verification.
+func (o *ProverTask) CountInvalidProofsOfProver(ctx context.Context, publicKey string, since time.Time) (int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("prover_public_key", publicKey)
+	db = db.Where("failure_type", int(types.ProverTaskFailureTypeVerifiedFailed))
+	db = db.Where("assigned_at >= ?", since)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("ProverTask.CountInvalidProofsOfProver error: %w, public key: %v", err, publicKey)
+	}
+	return count, nil
+}
+
 // InsertProverTask insert a prover Task record
 func (o *ProverTask) InsertProverTask(ctx context.Context, proverTask *ProverTask, dbTX ...*gorm.DB) error {
 	db := o.db.WithContext(ctx)
@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProverTokenRevocation represents the latest revocation of the login tokens of a prover.
+type ProverTokenRevocation struct {
+	db *gorm.DB `gorm:"-"`
+
+	PublicKey string `json:"public_key" gorm:"column:public_key"`
+	// RevokedAt rejects the tokens issued by a login up to this time.
+	RevokedAt time.Time `json:"revoked_at" gorm:"column:revoked_at"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverTokenRevocation creates a new ProverTokenRevocation instance.
+func NewProverTokenRevocation(db *gorm.DB) *ProverTokenRevocation {
+	return &ProverTokenRevocation{db: db}
+}
+
+// TableName returns the name of the "prover_token_revocation" table.
+func (*ProverTokenRevocation) TableName() string {
+	return "prover_token_revocation"
+}
+
+// RevokeTokens revokes the tokens of a prover issued by a login up to revokedAt, replacing its previous revocation.
+func (p *ProverTokenRevocation) RevokeTokens(ctx context.Context, publicKey string, revokedAt time.Time, dbTX ...*gorm.DB) error {
+	db := p.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverTokenRevocation{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "public_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"revoked_at": revokedAt,
+			"updated_at": time.Now(),
+		}),
+	})
+
+	revocation := ProverTokenRevocation{
+		PublicKey: publicKey,
+		RevokedAt: revokedAt,
+	}
+	if err := db.Create(&revocation).Error; err != nil {
+		return fmt.Errorf("ProverTokenRevocation.RevokeTokens error: %w, public key: %v, revoked at: %v", err, publicKey, revokedAt)
+	}
+	return nil
+}
+
+// GetRevokedAt retrieves the time up to which the tokens of a prover are revoked, nil if they were never revoked.
+func (p *ProverTokenRevocation) GetRevokedAt(ctx context.Context, publicKey string) (*time.Time, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverTokenRevocation{})
+	db = db.Where("public_key = ?", publicKey)
+
+	var revocation ProverTokenRevocation
+	if err := db.First(&revocation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ProverTokenRevocation.GetRevokedAt error: %w, public key: %v", err, publicKey)
+	}
+	return &revocation.RevokedAt, nil
+}
@@ -0,0 +1,26 @@
+package orm
+
+import (
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+)
+
+// countByProvingStatus counts the rows of the model of db by proving status.
+func countByProvingStatus(db *gorm.DB) (map[types.ProvingStatus]int64, error) {
+	var rows []struct {
+		ProvingStatus int16
+		Count         int64
+	}
+	db = db.Select("proving_status, COUNT(*) AS count")
+	db = db.Group("proving_status")
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[types.ProvingStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[types.ProvingStatus(row.ProvingStatus)] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,74 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/types/message"
+)
+
+// TaskEscalation represents a proving task which failed too many times and needs attention.
+type TaskEscalation struct {
+	db *gorm.DB `gorm:"-"`
+
+	TaskType int16  `json:"task_type" gorm:"column:task_type"`
+	TaskID   string `json:"task_id" gorm:"column:task_id"`
+	Failures int16  `json:"failures" gorm:"column:failures"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewTaskEscalation creates a new TaskEscalation instance.
+func NewTaskEscalation(db *gorm.DB) *TaskEscalation {
+	return &TaskEscalation{db: db}
+}
+
+// TableName returns the name of the "task_escalation" table.
+func (*TaskEscalation) TableName() string {
+	return "task_escalation"
+}
+
+// GetTaskEscalations retrieves the escalated tasks, the latest escalated first.
+func (o *TaskEscalation) GetTaskEscalations(ctx context.Context, offset, limit int) ([]*TaskEscalation, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, errors.New("offset must not be smaller than 0 and limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&TaskEscalation{})
+	db = db.Order("created_at DESC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var escalations []*TaskEscalation
+	if err := db.Find(&escalations).Error; err != nil {
+		return nil, fmt.Errorf("TaskEscalation.GetTaskEscalations error: %w", err)
+	}
+	return escalations, nil
+}
+
+// InsertTaskEscalation marks a task as needing attention, it returns false if the task was already escalated.
+func (o *TaskEscalation) InsertTaskEscalation(ctx context.Context, taskType message.ProofType, taskID string, failures int16) (bool, error) {
+	escalation := TaskEscalation{
+		TaskType: int16(taskType),
+		TaskID:   taskID,
+		Failures: failures,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&TaskEscalation{})
+	db = db.Clauses(clause.OnConflict{DoNothing: true})
+	result := db.Create(&escalation)
+	if result.Error != nil {
+		return false, fmt.Errorf("TaskEscalation.InsertTaskEscalation error: %w, task type: %v, task id: %v", result.Error, taskType.String(), taskID)
+	}
+	return result.RowsAffected > 0, nil
+}
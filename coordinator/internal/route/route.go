@@ -5,36 +5,70 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"scroll-tech/common/observability"
+	"scroll-tech/common/tracing"
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/logic/payload"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
 	"scroll-tech/coordinator/internal/middleware"
 )
 
 // Route register route for coordinator
 func Route(router *gin.Engine, cfg *config.Config, reg prometheus.Registerer) {
 	router.Use(gin.Recovery())
+	router.Use(tracing.Middleware())
 
 	observability.Use(router, "coordinator", reg)
 
 	r := router.Group("coordinator")
 
 	v1(r, cfg)
+
+	if cfg.Admin != nil {
+		admin(r, cfg.Admin)
+	}
+}
+
+func admin(router *gin.RouterGroup, conf *config.Admin) {
+	r := router.Group("/admin/v1")
+	r.Use(middleware.AdminAuthMiddleware(conf.AuthToken))
+	{
+		r.GET("/prover_stats", api.ProverStats.ListProverStats)
+		r.GET("/task_escalations", api.TaskEscalation.ListTaskEscalations)
+		r.GET("/pipeline_status", api.PipelineStatus.GetPipelineStatus)
+		r.GET("/prover_heartbeats", api.Heartbeat.ListProverHeartbeats)
+		r.GET("/prover_allowlist", api.ProverAllowlist.ListProverAllowlist)
+		r.POST("/prover_allowlist", api.ProverAllowlist.AddProverAllowlist)
+		r.DELETE("/prover_allowlist", api.ProverAllowlist.RemoveProverAllowlist)
+		r.POST("/revoke_tokens", api.ProverAllowlist.RevokeTokens)
+	}
 }
 
 func v1(router *gin.RouterGroup, conf *config.Config) {
 	r := router.Group("/v1")
 
+	loginRateLimit := middleware.RateLimitMiddleware(ratelimit.EndpointLogin)
+
 	challengeMiddleware := middleware.ChallengeMiddleware(conf)
-	r.GET("/challenge", challengeMiddleware.LoginHandler)
+	r.GET("/challenge", loginRateLimit, middleware.ChallengeHandler(conf, challengeMiddleware))
 
 	loginMiddleware := middleware.LoginMiddleware(conf)
-	r.POST("/login", challengeMiddleware.MiddlewareFunc(), loginMiddleware.LoginHandler)
+	r.POST("/login", loginRateLimit, challengeMiddleware.MiddlewareFunc(), loginMiddleware.LoginHandler)
+
+	// the payload urls are signed, the provers download them without their jwt token.
+	if conf.PayloadStorage != nil && conf.PayloadStorage.Type == config.PayloadStorageFile {
+		r.GET(payload.FileRoute+"/*key", api.TaskPayload.ServeFile)
+	}
 
 	// need jwt token api
 	r.Use(loginMiddleware.MiddlewareFunc())
 	{
-		r.POST("/get_task", api.GetTask.GetTasks)
-		r.POST("/submit_proof", api.SubmitProof.SubmitProof)
+		r.POST("/get_task", middleware.RateLimitMiddleware(ratelimit.EndpointGetTask), middleware.ProverQuotaMiddleware(), api.GetTask.GetTasks)
+		r.POST("/submit_proof", middleware.RateLimitMiddleware(ratelimit.EndpointSubmitProof), api.SubmitProof.SubmitProof)
+		r.POST("/heartbeat", api.Heartbeat.Heartbeat)
+		if conf.Auth.MaxRefreshDurationSec > 0 {
+			r.POST("/refresh_token", middleware.RefreshHandler(conf, loginMiddleware))
+		}
 	}
 }
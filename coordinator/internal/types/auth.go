@@ -9,6 +9,10 @@ const (
 	ProverName = "prover_name"
 	// ProverVersion the prover version for context
 	ProverVersion = "prover_version"
+	// CircuitVersion the circuit version advertised by the prover for context
+	CircuitVersion = "circuit_version"
+	// LoginAt the unix time of the login which issued the token, kept by the token refreshes
+	LoginAt = "login_at"
 )
 
 // Message the login message struct
@@ -16,6 +20,8 @@ type Message struct {
 	Challenge     string `form:"challenge" json:"challenge" binding:"required"`
 	ProverVersion string `form:"prover_version" json:"prover_version" binding:"required"`
 	ProverName    string `form:"prover_name" json:"prover_name" binding:"required"`
+	// CircuitVersion is required by the coordinators routing the tasks by circuit version.
+	CircuitVersion string `form:"circuit_version" json:"circuit_version"`
 }
 
 // LoginParameter for /login api
@@ -13,4 +13,8 @@ type GetTaskSchema struct {
 	TaskID   string `json:"task_id"`
 	TaskType int    `json:"task_type"`
 	TaskData string `json:"task_data"`
+	// TaskDataURL is set instead of TaskData when the payload is offloaded, the payload is downloaded from it.
+	TaskDataURL string `json:"task_data_url,omitempty"`
+	// TaskDataHash is the hex encoded sha256 hash of the offloaded payload.
+	TaskDataHash string `json:"task_data_hash,omitempty"`
 }
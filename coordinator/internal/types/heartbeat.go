@@ -0,0 +1,21 @@
+package types
+
+// HeartbeatParameter the heartbeat sent periodically by a prover, over the http api or the grpc heartbeat stream
+type HeartbeatParameter struct {
+	// ProverHeight is the latest block height known to the prover.
+	ProverHeight int `json:"prover_height"`
+	// GPUMemoryMB is the free GPU memory of the prover, 0 if unknown.
+	GPUMemoryMB uint64 `json:"gpu_memory_mb,omitempty"`
+	// CircuitVersions are the circuit versions the prover supports.
+	CircuitVersions []string `json:"circuit_versions,omitempty"`
+	// TaskProgress is the estimated progress percentage of the current task, 0 if idle.
+	TaskProgress int `json:"task_progress,omitempty"`
+	// RecentErrors is the number of errors of the prover within its reporting window.
+	RecentErrors uint64 `json:"recent_errors,omitempty"`
+}
+
+// HeartbeatSchema the answer of the coordinator to a heartbeat
+type HeartbeatSchema struct {
+	// Time is the unix time of the coordinator.
+	Time int64 `json:"time"`
+}
@@ -101,7 +101,7 @@ func setupCoordinator(t *testing.T, proversPerSession uint8, coordinatorURL stri
 	proofCollector := cron.NewCollector(context.Background(), db, conf, nil)
 
 	router := gin.New()
-	api.InitController(conf, db, nil)
+	api.InitController(conf, db, db, nil)
 	route.Route(router, conf, nil)
 	srv := &http.Server{
 		Addr:    coordinatorURL,
@@ -398,7 +398,7 @@ func testInvalidProof(t *testing.T) {
 		provers[i] = newMockProver(t, "prover_test"+strconv.Itoa(i), coordinatorURL, proofType, version.Version)
 		proverTask := provers[i].getProverTask(t, proofType)
 		assert.NotNil(t, proverTask)
-		provers[i].submitProof(t, proverTask, verifiedFailed, types.ErrCoordinatorHandleZkProofFailure)
+		provers[i].submitProof(t, proverTask, verifiedFailed, types.ErrCoordinatorInvalidProof)
 	}
 
 	// verify proof status
@@ -64,6 +64,39 @@ func init() {
 					Value: 0,
 				}},
 		},
+		{
+			Name:   "data-migrations",
+			Usage:  "List the data migrations and their checkpoints.",
+			Action: listDataMigrations,
+			Flags:  []cli.Flag{&utils.ConfigFileFlag},
+		},
+		{
+			Name:   "data-migrate",
+			Usage:  "Run the data migration <version> from its checkpoint, in batches.",
+			Action: runDataMigration,
+			Flags: []cli.Flag{
+				&utils.ConfigFileFlag,
+				&cli.Int64Flag{
+					Name:     "version",
+					Usage:    "Version of the data migration to run.",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:  "batch-size",
+					Usage: "Maximum number of rows per batch.",
+					Value: 1000,
+				},
+				&cli.DurationFlag{
+					Name:  "batch-interval",
+					Usage: "Pause between the batches, to limit the load of the database.",
+					Value: 0,
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Run every batch in a transaction rolled back, nothing is changed.",
+				},
+			},
+		},
 	}
 
 	// Register `db_cli-test` app for integration-test.
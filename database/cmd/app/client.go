@@ -1,7 +1,18 @@
 package app
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 
@@ -109,3 +120,71 @@ func rollbackDB(ctx *cli.Context) error {
 	version := ctx.Int64("version")
 	return migrate.Rollback(db.DB, &version)
 }
+
+// listDataMigrations prints the data migrations and their checkpoints
+func listDataMigrations(ctx *cli.Context) error {
+	cfg, err := getConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := initDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := migrate.DataMigrationStatuses(ctx.Context, db.DB)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statuses)
+}
+
+// runDataMigration runs a data migration from its checkpoint, it can be interrupted and resumed
+func runDataMigration(ctx *cli.Context) error {
+	cfg, err := getConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := initDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	version := ctx.Int64("version")
+	m := migrate.GetDataMigration(version)
+	if m == nil {
+		return fmt.Errorf("unknown data migration version: %v", version)
+	}
+	serveMetrics(ctx)
+
+	// the batch in progress is rolled back on interrupt, the migration resumes from the last batch committed.
+	runCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return migrate.RunDataMigration(runCtx, db.DB, m, &migrate.DataMigrationOptions{
+		BatchSize:     ctx.Int("batch-size"),
+		BatchInterval: ctx.Duration("batch-interval"),
+		DryRun:        ctx.Bool("dry-run"),
+	}, prometheus.DefaultRegisterer)
+}
+
+// serveMetrics serves the progress metrics of the data migrations in the background if the metrics are enabled
+func serveMetrics(ctx *cli.Context) {
+	if !ctx.Bool(utils.MetricsEnabled.Name) {
+		return
+	}
+	address := fmt.Sprintf(":%s", ctx.String(utils.MetricsPort.Name))
+	server := &http.Server{
+		Addr:              address,
+		Handler:           promhttp.Handler(),
+		ReadHeaderTimeout: time.Minute,
+	}
+	log.Info("Starting metrics server", "address", address)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("run metrics http server failure", "error", err)
+		}
+	}()
+}
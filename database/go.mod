@@ -6,6 +6,7 @@ require (
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/lib/pq v1.10.9
 	github.com/pressly/goose/v3 v3.16.0
+	github.com/prometheus/client_golang v1.14.0
 	github.com/scroll-tech/go-ethereum v1.10.14-0.20240311135752-ccec84ce63c8
 	github.com/stretchr/testify v1.8.4
 	github.com/urfave/cli/v2 v2.25.7
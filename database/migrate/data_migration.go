@@ -0,0 +1,241 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// DataMigration is a versioned migration of the data, e.g. recomputing a column or backfilling batch indexes, run in
+// batches next to the schema migrations. The checkpoint of a batch is recorded in the transaction of the batch, so an
+// interrupted migration resumes after its last committed batch.
+type DataMigration struct {
+	// Version identifies the migration and its checkpoint, it must never be reused.
+	Version int64
+	Name    string
+
+	// Batch migrates the rows following the cursor, an empty cursor being the start, up to limit rows within tx. It
+	// returns the cursor of the last row, the number of rows processed and whether no rows are left. The batches must
+	// be idempotent, a migration may be run again from scratch, e.g. once its checkpoint is reset.
+	Batch func(ctx context.Context, tx *sql.Tx, cursor string, limit int) (next string, rows int64, done bool, err error)
+	// Total optionally estimates the number of rows to process, for the progress of the migration.
+	Total func(ctx context.Context, db *sql.DB) (int64, error)
+}
+
+// DataMigrationOptions are the options of a data migration run.
+type DataMigrationOptions struct {
+	// The maximum number of rows per batch.
+	BatchSize int
+	// The pause between the batches, to limit the load of long backfills.
+	BatchInterval time.Duration
+	// Whether to run every batch in a transaction rolled back, no data nor checkpoint is changed.
+	DryRun bool
+}
+
+// DataMigrationStatus is the checkpoint of a data migration.
+type DataMigrationStatus struct {
+	Version       int64      `json:"version"`
+	Name          string     `json:"name"`
+	LastCursor    string     `json:"last_cursor"`
+	RowsProcessed int64      `json:"rows_processed"`
+	CompletedAt   *time.Time `json:"completed_at"`
+}
+
+type dataMigrationMetrics struct {
+	rowsProcessedTotal *prometheus.CounterVec
+	batchesTotal       *prometheus.CounterVec
+	progress           *prometheus.GaugeVec
+}
+
+var (
+	initDataMigrationMetricsOnce sync.Once
+	dataMigrationMetric          *dataMigrationMetrics
+)
+
+func initDataMigrationMetrics(reg prometheus.Registerer) *dataMigrationMetrics {
+	initDataMigrationMetricsOnce.Do(func() {
+		dataMigrationMetric = &dataMigrationMetrics{
+			rowsProcessedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "database_data_migration_rows_processed_total",
+				Help: "The total number of rows processed by the data migrations, by migration and dry run",
+			}, []string{"migration", "dry_run"}),
+			batchesTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "database_data_migration_batches_total",
+				Help: "The total number of batches run by the data migrations, by migration and dry run",
+			}, []string{"migration", "dry_run"}),
+			progress: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "database_data_migration_progress_ratio",
+				Help: "The ratio of the estimated rows processed by the data migrations, by migration",
+			}, []string{"migration"}),
+		}
+	})
+	return dataMigrationMetric
+}
+
+// ErrConcurrentDataMigration is returned if the checkpoint of a data migration moved during the run, i.e. the migration
+// is run by another process as well.
+var ErrConcurrentDataMigration = errors.New("data migration checkpoint moved, the migration is run concurrently")
+
+// DataMigrations returns the registered data migrations sorted by version.
+func DataMigrations() []*DataMigration {
+	migrations := append([]*DataMigration(nil), dataMigrations...)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations
+}
+
+// GetDataMigration returns the registered data migration of the version, nil if unknown.
+func GetDataMigration(version int64) *DataMigration {
+	for _, m := range dataMigrations {
+		if m.Version == version {
+			return m
+		}
+	}
+	return nil
+}
+
+// DataMigrationStatuses returns the checkpoints of the registered data migrations sorted by version, the migrations
+// never run have an empty checkpoint.
+func DataMigrationStatuses(ctx context.Context, db *sql.DB) ([]*DataMigrationStatus, error) {
+	var statuses []*DataMigrationStatus
+	for _, m := range DataMigrations() {
+		status, err := getDataMigrationStatus(ctx, db, m)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func getDataMigrationStatus(ctx context.Context, db *sql.DB, m *DataMigration) (*DataMigrationStatus, error) {
+	status := &DataMigrationStatus{Version: m.Version, Name: m.Name}
+	var completedAt sql.NullTime
+	err := db.QueryRowContext(ctx, "SELECT last_cursor, rows_processed, completed_at FROM data_migration WHERE version = $1", m.Version).
+		Scan(&status.LastCursor, &status.RowsProcessed, &completedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get data migration checkpoint, version: %v, err: %w", m.Version, err)
+	}
+	if completedAt.Valid {
+		status.CompletedAt = &completedAt.Time
+	}
+	return status, nil
+}
+
+// RunDataMigration runs the data migration from its checkpoint until no rows are left or ctx is done, a completed
+// migration is not run again.
+func RunDataMigration(ctx context.Context, db *sql.DB, m *DataMigration, opts *DataMigrationOptions, reg prometheus.Registerer) error {
+	if opts.BatchSize <= 0 {
+		return fmt.Errorf("invalid data migration batch size: %v", opts.BatchSize)
+	}
+	metrics := initDataMigrationMetrics(reg)
+	dryRun := fmt.Sprint(opts.DryRun)
+
+	status, err := getDataMigrationStatus(ctx, db, m)
+	if err != nil {
+		return err
+	}
+	if status.CompletedAt != nil {
+		log.Info("data migration already completed", "version", m.Version, "name", m.Name, "completed at", status.CompletedAt)
+		return nil
+	}
+	if !opts.DryRun {
+		_, err = db.ExecContext(ctx, "INSERT INTO data_migration (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING", m.Version, m.Name)
+		if err != nil {
+			return fmt.Errorf("failed to init data migration checkpoint, version: %v, err: %w", m.Version, err)
+		}
+	}
+
+	var total int64
+	if m.Total != nil {
+		if total, err = m.Total(ctx, db); err != nil {
+			return fmt.Errorf("failed to estimate data migration rows, version: %v, err: %w", m.Version, err)
+		}
+	}
+	log.Info("start data migration", "version", m.Version, "name", m.Name, "cursor", status.LastCursor, "rows processed", status.RowsProcessed, "total", total, "dry run", opts.DryRun)
+
+	cursor, rowsProcessed := status.LastCursor, status.RowsProcessed
+	for {
+		var (
+			rows int64
+			done bool
+		)
+		cursor, rows, done, err = runDataMigrationBatch(ctx, db, m, opts, cursor, rowsProcessed)
+		if err != nil {
+			return err
+		}
+		rowsProcessed += rows
+
+		metrics.batchesTotal.WithLabelValues(m.Name, dryRun).Inc()
+		metrics.rowsProcessedTotal.WithLabelValues(m.Name, dryRun).Add(float64(rows))
+		if total > 0 {
+			metrics.progress.WithLabelValues(m.Name).Set(float64(rowsProcessed) / float64(total))
+		}
+		log.Info("data migration batch done", "version", m.Version, "name", m.Name, "cursor", cursor, "rows processed", rowsProcessed, "total", total, "dry run", opts.DryRun)
+
+		if done {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.BatchInterval):
+		}
+	}
+
+	log.Info("data migration completed", "version", m.Version, "name", m.Name, "rows processed", rowsProcessed, "dry run", opts.DryRun)
+	return nil
+}
+
+// runDataMigrationBatch runs a batch and records its checkpoint in the same transaction, unless in dry run.
+func runDataMigrationBatch(ctx context.Context, db *sql.DB, m *DataMigration, opts *DataMigrationOptions, cursor string, rowsProcessed int64) (string, int64, bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to begin data migration batch, version: %v, err: %w", m.Version, err)
+	}
+	defer func() {
+		// a no-op once committed.
+		_ = tx.Rollback()
+	}()
+
+	if !opts.DryRun {
+		var lastCursor string
+		err = tx.QueryRowContext(ctx, "SELECT last_cursor FROM data_migration WHERE version = $1 FOR UPDATE", m.Version).Scan(&lastCursor)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("failed to lock data migration checkpoint, version: %v, err: %w", m.Version, err)
+		}
+		if lastCursor != cursor {
+			return "", 0, false, ErrConcurrentDataMigration
+		}
+	}
+
+	next, rows, done, err := m.Batch(ctx, tx, cursor, opts.BatchSize)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to run data migration batch, version: %v, cursor: %v, err: %w", m.Version, cursor, err)
+	}
+	if opts.DryRun {
+		return next, rows, done, nil
+	}
+
+	var completedAt *time.Time
+	if done {
+		now := time.Now().UTC()
+		completedAt = &now
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE data_migration SET last_cursor = $1, rows_processed = $2, completed_at = $3, updated_at = CURRENT_TIMESTAMP WHERE version = $4",
+		next, rowsProcessed+rows, completedAt, m.Version)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to record data migration checkpoint, version: %v, err: %w", m.Version, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return "", 0, false, fmt.Errorf("failed to commit data migration batch, version: %v, err: %w", m.Version, err)
+	}
+	return next, rows, done, nil
+}
@@ -0,0 +1,96 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// dataMigrations are the registered data migrations, the versions are never reused.
+var dataMigrations = []*DataMigration{
+	{
+		// the value column was added with a zero default, see 00024_pending_transaction_value.sql.
+		Version: 1,
+		Name:    "pending_transaction_value",
+		Batch:   backfillPendingTransactionValue("pending_transaction"),
+		Total:   countRows("pending_transaction"),
+	},
+	{
+		Version: 2,
+		Name:    "pending_transaction_archive_value",
+		Batch:   backfillPendingTransactionValue("pending_transaction_archive"),
+		Total:   countRows("pending_transaction_archive"),
+	},
+}
+
+func countRows(table string) func(ctx context.Context, db *sql.DB) (int64, error) {
+	return func(ctx context.Context, db *sql.DB) (int64, error) {
+		var total int64
+		err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&total)
+		return total, err
+	}
+}
+
+// backfillPendingTransactionValue recomputes the value of the transactions of the table from their rlp encoding, the
+// cursor is the id of the last transaction.
+func backfillPendingTransactionValue(table string) func(ctx context.Context, tx *sql.Tx, cursor string, limit int) (string, int64, bool, error) {
+	return func(ctx context.Context, tx *sql.Tx, cursor string, limit int) (string, int64, bool, error) {
+		var lastID uint64
+		if cursor != "" {
+			var err error
+			if lastID, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+				return "", 0, false, fmt.Errorf("invalid cursor: %v, err: %w", cursor, err)
+			}
+		}
+
+		type transaction struct {
+			id        uint64
+			createdAt time.Time
+			value     string
+		}
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT id, created_at, rlp_encoding FROM %s WHERE id > $1 ORDER BY id LIMIT $2", table), lastID, limit)
+		if err != nil {
+			return "", 0, false, err
+		}
+		var txs []transaction
+		for rows.Next() {
+			var (
+				t           transaction
+				rlpEncoding []byte
+			)
+			if err = rows.Scan(&t.id, &t.createdAt, &rlpEncoding); err != nil {
+				_ = rows.Close()
+				return "", 0, false, err
+			}
+			var decoded gethTypes.Transaction
+			if err = rlp.DecodeBytes(rlpEncoding, &decoded); err != nil {
+				_ = rows.Close()
+				return "", 0, false, fmt.Errorf("failed to decode transaction, id: %v, err: %w", t.id, err)
+			}
+			t.value = decoded.Value().String()
+			txs = append(txs, t)
+		}
+		if err = rows.Close(); err != nil {
+			return "", 0, false, err
+		}
+		if err = rows.Err(); err != nil {
+			return "", 0, false, err
+		}
+		if len(txs) == 0 {
+			return cursor, 0, true, nil
+		}
+
+		for _, t := range txs {
+			_, err = tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET value = $1 WHERE id = $2 AND created_at = $3 AND value = 0", table), t.value, t.id, t.createdAt)
+			if err != nil {
+				return "", 0, false, fmt.Errorf("failed to update transaction value, id: %v, err: %w", t.id, err)
+			}
+		}
+		return strconv.FormatUint(txs[len(txs)-1].id, 10), int64(len(txs)), len(txs) < limit, nil
+	}
+}
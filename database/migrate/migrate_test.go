@@ -1,6 +1,9 @@
 package migrate
 
 import (
+	"context"
+	"database/sql"
+	"strconv"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
@@ -40,6 +43,7 @@ func TestMigrate(t *testing.T) {
 	t.Run("testStatus", testStatus)
 	t.Run("testResetDB", testResetDB)
 	t.Run("testMigrate", testMigrate)
+	t.Run("testDataMigration", testDataMigration)
 	t.Run("testRollback", testRollback)
 
 	t.Cleanup(func() {
@@ -63,20 +67,20 @@ func testResetDB(t *testing.T) {
 	cur, err := Current(pgDB.DB)
 	assert.NoError(t, err)
 	// total number of tables.
-	assert.Equal(t, int64(16), cur)
+	assert.Equal(t, int64(47), cur)
 }
 
 func testMigrate(t *testing.T) {
 	assert.NoError(t, Migrate(pgDB.DB))
 	cur, err := Current(pgDB.DB)
 	assert.NoError(t, err)
-	assert.Equal(t, int64(16), cur)
+	assert.Equal(t, int64(47), cur)
 }
 
 func testRollback(t *testing.T) {
 	version, err := Current(pgDB.DB)
 	assert.NoError(t, err)
-	assert.Equal(t, int64(16), version)
+	assert.Equal(t, int64(47), version)
 
 	assert.NoError(t, Rollback(pgDB.DB, nil))
 
@@ -91,3 +95,57 @@ func testRollback(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(0), cur)
 }
+
+func testDataMigration(t *testing.T) {
+	ctx := context.Background()
+	var batches int
+	m := &DataMigration{
+		Version: 1000,
+		Name:    "test_data_migration",
+		// 5 batches of 2 rows.
+		Batch: func(ctx context.Context, tx *sql.Tx, cursor string, limit int) (string, int64, bool, error) {
+			batches++
+			next := 0
+			if cursor != "" {
+				next, _ = strconv.Atoi(cursor)
+			}
+			next += limit
+			return strconv.Itoa(next), int64(limit), next >= 10, nil
+		},
+	}
+	opts := &DataMigrationOptions{BatchSize: 2}
+
+	// a dry run records no checkpoint.
+	opts.DryRun = true
+	assert.NoError(t, RunDataMigration(ctx, pgDB.DB, m, opts, nil))
+	assert.Equal(t, 5, batches)
+	status, err := getDataMigrationStatus(ctx, pgDB.DB, m)
+	assert.NoError(t, err)
+	assert.Empty(t, status.LastCursor)
+	assert.Nil(t, status.CompletedAt)
+
+	batches = 0
+	opts.DryRun = false
+	assert.NoError(t, RunDataMigration(ctx, pgDB.DB, m, opts, nil))
+	assert.Equal(t, 5, batches)
+	status, err = getDataMigrationStatus(ctx, pgDB.DB, m)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", status.LastCursor)
+	assert.Equal(t, int64(10), status.RowsProcessed)
+	assert.NotNil(t, status.CompletedAt)
+
+	// a completed migration is not run again.
+	batches = 0
+	assert.NoError(t, RunDataMigration(ctx, pgDB.DB, m, opts, nil))
+	assert.Equal(t, 0, batches)
+
+	// the registered migrations run on the empty tables.
+	for _, m := range DataMigrations() {
+		assert.NoError(t, RunDataMigration(ctx, pgDB.DB, m, opts, nil))
+	}
+	statuses, err := DataMigrationStatuses(ctx, pgDB.DB)
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.CompletedAt)
+	}
+}
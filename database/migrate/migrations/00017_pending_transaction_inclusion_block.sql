@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN inclusion_block_number BIGINT  NOT NULL DEFAULT 0,
+    ADD COLUMN inclusion_block_hash   VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX idx_pending_transaction_on_sender_type_status_inclusion_block_number ON pending_transaction (sender_type, status, inclusion_block_number);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_pending_transaction_on_sender_type_status_inclusion_block_number;
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS inclusion_block_number,
+    DROP COLUMN IF EXISTS inclusion_block_hash;
+
+-- +goose StatementEnd
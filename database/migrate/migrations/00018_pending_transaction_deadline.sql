@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN deadline TIMESTAMP(0) DEFAULT NULL;
+
+COMMENT ON COLUMN pending_transaction.status IS 'unknown, pending, replaced, confirmed, confirmed failed, expired';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+COMMENT ON COLUMN pending_transaction.status IS 'unknown, pending, replaced, confirmed, confirmed failed';
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS deadline;
+
+-- +goose StatementEnd
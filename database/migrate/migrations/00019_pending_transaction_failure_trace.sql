@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN failure_trace TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN pending_transaction.failure_trace IS 'json summary of the failing call frame of a reverted transaction';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS failure_trace;
+
+-- +goose StatementEnd
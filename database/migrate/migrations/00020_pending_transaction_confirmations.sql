@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN confirmations BIGINT DEFAULT NULL;
+
+COMMENT ON COLUMN pending_transaction.confirmations IS 'confirmation depth override, an rpc.BlockNumber: a block count, -2 latest, -3 finalized or -4 safe; NULL uses the sender default';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS confirmations;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN replacements INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN pending_transaction.status IS 'unknown, pending, replaced, confirmed, confirmed failed, expired, stuck';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+COMMENT ON COLUMN pending_transaction.status IS 'unknown, pending, replaced, confirmed, confirmed failed, expired';
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS replacements;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN target_inclusion_blocks BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN pending_transaction.target_inclusion_blocks IS 'number of blocks the transaction should be included within, 0 uses the escalation multiple';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS target_inclusion_blocks;
+
+-- +goose StatementEnd
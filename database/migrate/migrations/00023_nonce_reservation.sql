@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE nonce_reservation
+(
+    id             SERIAL       PRIMARY KEY,
+    context_id     VARCHAR      NOT NULL,
+    sender_address VARCHAR      NOT NULL,
+    nonce          BIGINT       NOT NULL,
+    hash           VARCHAR      NOT NULL DEFAULT '',
+
+    created_at     TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at     TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_nonce_reservation_on_sender_address_nonce ON nonce_reservation(sender_address, nonce);
+CREATE INDEX idx_nonce_reservation_on_sender_address_context_id ON nonce_reservation(sender_address, context_id);
+
+COMMENT ON COLUMN nonce_reservation.hash IS 'hash of the transaction sent with the reserved nonce, empty while unused';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS nonce_reservation;
+-- +goose StatementEnd
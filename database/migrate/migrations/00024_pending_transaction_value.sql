@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN value DECIMAL(78, 0) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN pending_transaction.value IS 'value in wei transferred by the transaction';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS value;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE batch
+    ADD COLUMN codec_version SMALLINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN batch.codec_version IS 'codec version of the batch data, 0 commits the transactions in calldata, 1 in a 4844 blob';
+
+ALTER TABLE l1_block
+    ADD COLUMN blob_base_fee BIGINT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE l1_block
+    DROP COLUMN IF EXISTS blob_base_fee;
+
+ALTER TABLE batch
+    DROP COLUMN IF EXISTS codec_version;
+
+-- +goose StatementEnd
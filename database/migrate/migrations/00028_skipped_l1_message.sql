@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE skipped_l1_message
+(
+    queue_index       BIGINT       NOT NULL,
+    skip_index        BIGINT       NOT NULL,
+    tx_hash           VARCHAR      NOT NULL,
+    sender            VARCHAR      NOT NULL,
+    target            VARCHAR      NOT NULL,
+    value             VARCHAR      NOT NULL,
+    gas_limit         BIGINT       NOT NULL,
+    calldata          TEXT         NOT NULL,
+    skip_reason       VARCHAR      NOT NULL DEFAULT '',
+    skip_block_number BIGINT       NOT NULL DEFAULT 0,
+    skip_block_hash   VARCHAR      NOT NULL DEFAULT '',
+
+    status            SMALLINT     NOT NULL DEFAULT 1,
+    replay_gas_limit  BIGINT       NOT NULL DEFAULT 0,
+    replay_tx_hash    VARCHAR      NOT NULL DEFAULT '',
+
+    created_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_skipped_l1_message_on_queue_index ON skipped_l1_message(queue_index) WHERE deleted_at IS NULL;
+CREATE INDEX idx_skipped_l1_message_on_skip_index ON skipped_l1_message(skip_index) WHERE deleted_at IS NULL;
+CREATE INDEX idx_skipped_l1_message_on_status ON skipped_l1_message(status) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN skipped_l1_message.skip_index IS 'index of the message in the skipped transactions of l2geth';
+COMMENT ON COLUMN skipped_l1_message.status IS 'undefined, skipped, replaying, replayed, replay_failed';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS skipped_l1_message;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE checkpoint
+(
+    service           VARCHAR      NOT NULL,
+    l1_block_number   BIGINT       NOT NULL DEFAULT 0,
+    l2_block_number   BIGINT       NOT NULL DEFAULT 0,
+    chunk_index       BIGINT       NOT NULL DEFAULT 0,
+    chunk_hash        VARCHAR      NOT NULL DEFAULT '',
+    batch_index       BIGINT       NOT NULL DEFAULT 0,
+    batch_hash        VARCHAR      NOT NULL DEFAULT '',
+    clean_shutdown    BOOLEAN      NOT NULL DEFAULT FALSE,
+
+    created_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_checkpoint_on_service ON checkpoint(service) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN checkpoint.clean_shutdown IS 'whether the checkpoint was recorded by a graceful shutdown, false while the service runs';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS checkpoint;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE l1_processed_block
+(
+    number            BIGINT       NOT NULL,
+    hash              VARCHAR      NOT NULL,
+
+    created_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_l1_processed_block_on_number ON l1_processed_block(number) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE l1_processed_block IS 'hashes of the last blocks of the L1 ranges whose contract events were processed, used to detect reorgs';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l1_processed_block;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE l2_watcher_checkpoint
+(
+    block_number      BIGINT       NOT NULL,
+    block_hash        VARCHAR      NOT NULL,
+
+    created_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_l2_watcher_checkpoint_on_block_number ON l2_watcher_checkpoint(block_number) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE l2_watcher_checkpoint IS 'the last L2 block stored by the L2 watcher, written in the same transaction as the blocks';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l2_watcher_checkpoint;
+-- +goose StatementEnd
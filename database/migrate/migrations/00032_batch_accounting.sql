@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE batch_accounting
+(
+    batch_index       BIGINT       NOT NULL,
+    batch_hash        VARCHAR      NOT NULL,
+
+    commit_tx_hash    VARCHAR      NOT NULL,
+    commit_fee        VARCHAR      NOT NULL,
+    commit_blob_fee   VARCHAR      NOT NULL,
+    finalize_tx_hash  VARCHAR      NOT NULL DEFAULT '',
+    finalize_fee      VARCHAR      NOT NULL DEFAULT '0',
+    l2_fee            VARCHAR      NOT NULL,
+    margin            VARCHAR      NOT NULL,
+
+    created_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_batch_accounting_on_batch_hash ON batch_accounting(batch_hash) WHERE deleted_at IS NULL;
+CREATE INDEX idx_batch_accounting_on_batch_index ON batch_accounting(batch_index) WHERE deleted_at IS NULL;
+CREATE INDEX idx_batch_accounting_on_finalize_tx_hash ON batch_accounting(finalize_tx_hash) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE batch_accounting IS 'the l1 fees paid to commit and finalize each batch and the l2 fees collected in its blocks, in wei';
+COMMENT ON COLUMN batch_accounting.commit_fee IS 'share of the execution fee of the commit transaction, split evenly between the batches it commits';
+COMMENT ON COLUMN batch_accounting.finalize_fee IS 'share of the fee of the finalize transaction, split evenly between the batches it finalizes';
+COMMENT ON COLUMN batch_accounting.margin IS 'l2_fee minus the commit, blob and finalize fees, negative for an unprofitable batch';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS batch_accounting;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prover_stats
+(
+    prover_public_key      VARCHAR      NOT NULL,
+    prover_name            VARCHAR      NOT NULL,
+    task_type              SMALLINT     NOT NULL,
+
+    tasks_succeeded        BIGINT       NOT NULL DEFAULT 0,
+    tasks_failed           BIGINT       NOT NULL DEFAULT 0,
+    tasks_timeout          BIGINT       NOT NULL DEFAULT 0,
+    invalid_proofs         BIGINT       NOT NULL DEFAULT 0,
+    total_proving_time_sec BIGINT       NOT NULL DEFAULT 0,
+
+    created_at             TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at             TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at             TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_prover_stats_on_public_key_task_type ON prover_stats(prover_public_key, task_type);
+
+COMMENT ON TABLE prover_stats IS 'the outcome of the tasks finished by each prover, by proof type';
+COMMENT ON COLUMN prover_stats.tasks_failed IS 'tasks the prover reported as failed';
+COMMENT ON COLUMN prover_stats.total_proving_time_sec IS 'total proving time of the succeeded tasks';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prover_stats;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE task_escalation
+(
+    task_type              SMALLINT     NOT NULL,
+    task_id                VARCHAR      NOT NULL,
+    failures               SMALLINT     NOT NULL,
+
+    created_at             TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at             TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at             TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_task_escalation_on_task_type_task_id ON task_escalation(task_type, task_id);
+
+COMMENT ON TABLE task_escalation IS 'the proving tasks which failed too many times and need attention';
+COMMENT ON COLUMN task_escalation.failures IS 'failed prover tasks of the task when it was escalated';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS task_escalation;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_block_list ADD COLUMN expires_at TIMESTAMP(0) DEFAULT NULL;
+
+COMMENT ON COLUMN prover_block_list.expires_at IS 'end of a temporary block, NULL blocks the prover until removed';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE prover_block_list DROP COLUMN IF EXISTS expires_at;
+-- +goose StatementEnd
@@ -0,0 +1,146 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- create_monthly_partition creates the partition <parent_table>_pYYYYMM holding the rows created in the month of
+-- month_start, the partition is left as is if it exists already.
+CREATE OR REPLACE FUNCTION create_monthly_partition(parent_table TEXT, month_start DATE) RETURNS VOID AS $$
+DECLARE
+    partition_start DATE := date_trunc('month', month_start)::DATE;
+BEGIN
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        parent_table || '_p' || to_char(partition_start, 'YYYYMM'), parent_table,
+        partition_start, (partition_start + INTERVAL '1 month')::DATE);
+END;
+$$ LANGUAGE plpgsql;
+
+-- pending_transaction
+
+ALTER TABLE pending_transaction RENAME TO pending_transaction_unpartitioned;
+ALTER TABLE pending_transaction_unpartitioned RENAME CONSTRAINT pending_transaction_pkey TO pending_transaction_unpartitioned_pkey;
+
+CREATE TABLE pending_transaction
+(
+    LIKE pending_transaction_unpartitioned INCLUDING DEFAULTS INCLUDING COMMENTS,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- the rows out of the monthly partitions, it stays empty as long as the archiver creates the partitions ahead.
+CREATE TABLE pending_transaction_default PARTITION OF pending_transaction DEFAULT;
+
+SELECT create_monthly_partition('pending_transaction', month_start::DATE)
+FROM generate_series(
+    date_trunc('month', COALESCE((SELECT MIN(created_at) FROM pending_transaction_unpartitioned), CURRENT_TIMESTAMP)),
+    date_trunc('month', CURRENT_TIMESTAMP) + INTERVAL '3 month',
+    INTERVAL '1 month'
+) AS month_start;
+
+INSERT INTO pending_transaction SELECT * FROM pending_transaction_unpartitioned;
+
+ALTER SEQUENCE pending_transaction_id_seq OWNED BY pending_transaction.id;
+DROP TABLE pending_transaction_unpartitioned;
+
+-- unique indexes of a partitioned table must include created_at, the hashes are unique by construction.
+CREATE INDEX idx_pending_transaction_on_hash ON pending_transaction(hash);
+CREATE INDEX idx_pending_transaction_on_sender_type_status_nonce_gas_fee_cap ON pending_transaction (sender_type, status, nonce, gas_fee_cap);
+CREATE INDEX idx_pending_transaction_on_sender_address_nonce ON pending_transaction(sender_address, nonce);
+CREATE INDEX idx_pending_transaction_on_sender_type_status_inclusion_block_number ON pending_transaction (sender_type, status, inclusion_block_number);
+
+-- the columns added to pending_transaction must be added to pending_transaction_archive as well.
+CREATE TABLE pending_transaction_archive
+(
+    LIKE pending_transaction INCLUDING COMMENTS,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_pending_transaction_archive_on_hash ON pending_transaction_archive(hash);
+CREATE INDEX idx_pending_transaction_archive_on_created_at ON pending_transaction_archive(created_at);
+
+-- l1_message
+
+ALTER TABLE l1_message RENAME TO l1_message_unpartitioned;
+
+CREATE TABLE l1_message
+(
+    LIKE l1_message_unpartitioned INCLUDING DEFAULTS INCLUDING COMMENTS
+) PARTITION BY RANGE (created_at);
+
+CREATE TABLE l1_message_default PARTITION OF l1_message DEFAULT;
+
+SELECT create_monthly_partition('l1_message', month_start::DATE)
+FROM generate_series(
+    date_trunc('month', COALESCE((SELECT MIN(created_at) FROM l1_message_unpartitioned), CURRENT_TIMESTAMP)),
+    date_trunc('month', CURRENT_TIMESTAMP) + INTERVAL '3 month',
+    INTERVAL '1 month'
+) AS month_start;
+
+INSERT INTO l1_message SELECT * FROM l1_message_unpartitioned;
+
+DROP TABLE l1_message_unpartitioned;
+
+-- the queue indexes are no longer unique across partitions, the ORM skips the stored messages instead.
+CREATE INDEX l1_message_hash_index ON l1_message (msg_hash) WHERE deleted_at IS NULL;
+CREATE INDEX l1_message_nonce_index ON l1_message (queue_index) WHERE deleted_at IS NULL;
+CREATE INDEX l1_message_height_index ON l1_message (height) WHERE deleted_at IS NULL;
+
+-- the columns added to l1_message must be added to l1_message_archive as well.
+CREATE TABLE l1_message_archive
+(
+    LIKE l1_message INCLUDING DEFAULTS INCLUDING COMMENTS
+);
+
+CREATE INDEX l1_message_archive_nonce_index ON l1_message_archive (queue_index) WHERE deleted_at IS NULL;
+CREATE INDEX l1_message_archive_height_index ON l1_message_archive (height) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- l1_message
+
+ALTER TABLE l1_message RENAME TO l1_message_partitioned;
+
+CREATE TABLE l1_message
+(
+    LIKE l1_message_partitioned INCLUDING DEFAULTS INCLUDING COMMENTS
+);
+
+INSERT INTO l1_message SELECT * FROM l1_message_archive UNION ALL SELECT * FROM l1_message_partitioned;
+
+DROP TABLE l1_message_partitioned;
+DROP TABLE l1_message_archive;
+
+create index l1_message_hash_index
+on l1_message (msg_hash) where deleted_at IS NULL;
+
+create unique index l1_message_nonce_uindex
+on l1_message (queue_index) where deleted_at IS NULL;
+
+create index l1_message_height_index
+on l1_message (height) where deleted_at IS NULL;
+
+-- pending_transaction
+
+ALTER TABLE pending_transaction RENAME TO pending_transaction_partitioned;
+ALTER TABLE pending_transaction_partitioned RENAME CONSTRAINT pending_transaction_pkey TO pending_transaction_partitioned_pkey;
+
+CREATE TABLE pending_transaction
+(
+    LIKE pending_transaction_partitioned INCLUDING DEFAULTS INCLUDING COMMENTS,
+    PRIMARY KEY (id)
+);
+
+INSERT INTO pending_transaction SELECT * FROM pending_transaction_archive UNION ALL SELECT * FROM pending_transaction_partitioned;
+
+ALTER SEQUENCE pending_transaction_id_seq OWNED BY pending_transaction.id;
+DROP TABLE pending_transaction_partitioned;
+DROP TABLE pending_transaction_archive;
+
+CREATE UNIQUE INDEX unique_idx_pending_transaction_on_hash ON pending_transaction(hash);
+CREATE INDEX idx_pending_transaction_on_sender_type_status_nonce_gas_fee_cap ON pending_transaction (sender_type, status, nonce, gas_fee_cap);
+CREATE INDEX idx_pending_transaction_on_sender_address_nonce ON pending_transaction(sender_address, nonce);
+CREATE INDEX idx_pending_transaction_on_sender_type_status_inclusion_block_number ON pending_transaction (sender_type, status, inclusion_block_number);
+
+DROP FUNCTION IF EXISTS create_monthly_partition(TEXT, DATE);
+
+-- +goose StatementEnd
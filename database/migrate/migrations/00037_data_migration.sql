@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE data_migration
+(
+    version        BIGINT       PRIMARY KEY,
+    name           VARCHAR      NOT NULL,
+    last_cursor    VARCHAR      NOT NULL DEFAULT '',
+    rows_processed BIGINT       NOT NULL DEFAULT 0,
+    completed_at   TIMESTAMP(0) DEFAULT NULL,
+
+    created_at     TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN data_migration.last_cursor IS 'opaque position of the last migrated batch, the next batch resumes after it';
+COMMENT ON COLUMN data_migration.completed_at IS 'NULL while the migration has batches left';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS data_migration;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE batch ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN chunk.version IS 'bumped by the proving status transitions and batch assignments of the rollup relayer, for its compare-and-swap updates';
+COMMENT ON COLUMN batch.version IS 'bumped by the status transitions of the rollup relayer, for its compare-and-swap updates';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE batch DROP COLUMN IF EXISTS version;
+ALTER TABLE chunk DROP COLUMN IF EXISTS version;
+
+-- +goose StatementEnd
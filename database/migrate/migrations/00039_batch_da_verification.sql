@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE batch ADD COLUMN da_verification_status SMALLINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN batch.da_verification_status IS 'undefined, pending, verified, mismatch: whether the data committed to L1 was re-derived and matches the batch';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE batch DROP COLUMN IF EXISTS da_verification_status;
+
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE l1_message_audit
+(
+    id                     BIGSERIAL    PRIMARY KEY,
+    l1_block_number        BIGINT       NOT NULL,
+    local_next_index       BIGINT       NOT NULL,
+    onchain_next_index     BIGINT       NOT NULL,
+    diverged               BOOLEAN      NOT NULL,
+    divergent_queue_index  BIGINT       NOT NULL DEFAULT 0,
+    reason                 VARCHAR      NOT NULL DEFAULT '',
+
+    created_at             TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at             TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at             TIMESTAMP(0) DEFAULT NULL
+);
+
+COMMENT ON TABLE l1_message_audit IS 'the results of the l1 message queue audits which changed the audit state, message relaying is halted while the latest one diverged';
+COMMENT ON COLUMN l1_message_audit.divergent_queue_index IS 'the first queue index whose message differs from the message queue contract, only set when diverged';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l1_message_audit;
+-- +goose StatementEnd
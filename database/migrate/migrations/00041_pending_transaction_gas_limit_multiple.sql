@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN gas_limit_multiple_num BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN gas_limit_multiple_den BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN pending_transaction.gas_limit_multiple_num IS 'numerator of the multiple applied to the estimated gas limit, 0 if the gas limit is not estimated';
+COMMENT ON COLUMN pending_transaction.gas_limit_multiple_den IS 'denominator of the multiple applied to the estimated gas limit, 0 if the gas limit is not estimated';
+
+-- the archived rows are moved with SELECT *, the columns must match pending_transaction.
+ALTER TABLE pending_transaction_archive
+    ADD COLUMN gas_limit_multiple_num BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN gas_limit_multiple_den BIGINT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction_archive
+    DROP COLUMN IF EXISTS gas_limit_multiple_den,
+    DROP COLUMN IF EXISTS gas_limit_multiple_num;
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS gas_limit_multiple_den,
+    DROP COLUMN IF EXISTS gas_limit_multiple_num;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prover_heartbeat
+(
+    prover_public_key VARCHAR      NOT NULL,
+    prover_name       VARCHAR      NOT NULL,
+    prover_version    VARCHAR      NOT NULL DEFAULT '',
+
+    gpu_memory_mb     BIGINT       NOT NULL DEFAULT 0,
+    circuit_versions  VARCHAR      NOT NULL DEFAULT '',
+    task_progress     SMALLINT     NOT NULL DEFAULT 0,
+    recent_errors     BIGINT       NOT NULL DEFAULT 0,
+
+    created_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_prover_heartbeat_on_public_key ON prover_heartbeat(prover_public_key);
+
+COMMENT ON TABLE prover_heartbeat IS 'the latest heartbeat of each prover, updated_at is the time it was received';
+COMMENT ON COLUMN prover_heartbeat.gpu_memory_mb IS 'free GPU memory reported by the prover, 0 if unknown';
+COMMENT ON COLUMN prover_heartbeat.circuit_versions IS 'comma separated circuit versions supported by the prover';
+COMMENT ON COLUMN prover_heartbeat.task_progress IS 'estimated progress percentage of the current task, 0 if idle';
+COMMENT ON COLUMN prover_heartbeat.recent_errors IS 'errors of the prover within its reporting window';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prover_heartbeat;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction
+    ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}'::JSONB;
+
+COMMENT ON COLUMN pending_transaction.metadata IS 'key/value labels attached by the sender caller, e.g. the batch index or the message nonce';
+
+CREATE INDEX idx_pending_transaction_on_metadata ON pending_transaction USING GIN (metadata jsonb_path_ops);
+
+-- the archived rows are moved with SELECT *, the columns must match pending_transaction.
+ALTER TABLE pending_transaction_archive
+    ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}'::JSONB;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pending_transaction_archive
+    DROP COLUMN IF EXISTS metadata;
+
+DROP INDEX IF EXISTS idx_pending_transaction_on_metadata;
+
+ALTER TABLE pending_transaction
+    DROP COLUMN IF EXISTS metadata;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE batch_override
+(
+    id                       BIGSERIAL    PRIMARY KEY,
+    batch_index              BIGINT       NOT NULL,
+    batch_hash               VARCHAR      NOT NULL,
+    action                   VARCHAR      NOT NULL,
+    reason                   VARCHAR      NOT NULL,
+    operator                 VARCHAR      NOT NULL,
+    previous_rollup_status   SMALLINT     NOT NULL,
+    previous_proving_status  SMALLINT     NOT NULL,
+    tx_hash                  VARCHAR      NOT NULL DEFAULT '',
+
+    created_at               TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at               TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at               TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE INDEX idx_batch_override_on_batch_index ON batch_override(batch_index) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE batch_override IS 'the manual overrides of the batch statuses applied by the operators with the rollup-relayer cli';
+COMMENT ON COLUMN batch_override.action IS 'recommit, invalidate_proof, skip_commit or skip_finalize';
+COMMENT ON COLUMN batch_override.tx_hash IS 'the l1 transaction which committed or finalized the skipped batch outside of the relayer, only set when skipped';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS batch_override;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l2_watcher_checkpoint
+    ADD COLUMN withdraw_trie_next_index BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN withdraw_trie_frontier   BYTEA  DEFAULT NULL;
+
+COMMENT ON COLUMN l2_watcher_checkpoint.withdraw_trie_next_index IS 'the number of messages appended to the withdraw trie up to the checkpoint block';
+COMMENT ON COLUMN l2_watcher_checkpoint.withdraw_trie_frontier IS 'the branches of the withdraw trie at the checkpoint block, 32 bytes per level, NULL when the withdraw roots are not computed';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE l2_watcher_checkpoint
+    DROP COLUMN IF EXISTS withdraw_trie_frontier,
+    DROP COLUMN IF EXISTS withdraw_trie_next_index;
+
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prover_allowlist
+(
+    id          BIGSERIAL PRIMARY KEY,
+    public_key  VARCHAR      NOT NULL,
+    prover_name VARCHAR      NOT NULL DEFAULT '',
+
+    created_at  TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_prover_allowlist_on_public_key ON prover_allowlist(public_key) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE prover_allowlist IS 'the prover public keys allowed to login when the coordinator enforces the allowlist';
+
+CREATE TABLE prover_token_revocation
+(
+    public_key  VARCHAR      NOT NULL,
+    revoked_at  TIMESTAMP(0) NOT NULL,
+
+    created_at  TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_prover_token_revocation_on_public_key ON prover_token_revocation(public_key);
+
+COMMENT ON TABLE prover_token_revocation IS 'the latest revocation of the login tokens of each prover';
+COMMENT ON COLUMN prover_token_revocation.revoked_at IS 'the tokens of the prover issued by a login up to this time are rejected';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prover_token_revocation;
+DROP TABLE IF EXISTS prover_allowlist;
+-- +goose StatementEnd
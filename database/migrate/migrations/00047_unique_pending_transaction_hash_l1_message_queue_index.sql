@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- the unique indexes of the partitioned tables must include created_at, the uniqueness lost by 00036 is kept in
+-- non-partitioned tables instead, which the ORMs fill in the transaction inserting the rows.
+
+CREATE TABLE pending_transaction_hash
+(
+    hash        VARCHAR      NOT NULL PRIMARY KEY,
+
+    created_at  TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE pending_transaction_hash IS 'the hashes of the stored and archived pending transactions';
+
+INSERT INTO pending_transaction_hash (hash)
+SELECT hash FROM pending_transaction UNION SELECT hash FROM pending_transaction_archive;
+
+CREATE TABLE l1_message_queue_index
+(
+    queue_index BIGINT       NOT NULL PRIMARY KEY,
+
+    created_at  TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE l1_message_queue_index IS 'the queue indexes of the stored and archived l1 messages which are not deleted';
+
+INSERT INTO l1_message_queue_index (queue_index)
+SELECT queue_index FROM l1_message WHERE deleted_at IS NULL
+UNION SELECT queue_index FROM l1_message_archive WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l1_message_queue_index;
+DROP TABLE IF EXISTS pending_transaction_hash;
+-- +goose StatementEnd
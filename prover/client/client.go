@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
@@ -20,15 +22,24 @@ import (
 // CoordinatorClient is a client used for interacting with the Coordinator service.
 type CoordinatorClient struct {
 	client *resty.Client
+	// payloadClient downloads the offloaded task payloads, their URLs are signed and sent without the auth token.
+	payloadClient *resty.Client
 
-	proverName string
-	priv       *ecdsa.PrivateKey
+	proverName     string
+	circuitVersion string
+	priv           *ecdsa.PrivateKey
+
+	// tokenIssuedAt and tokenExpiresAt bound the login token, refreshed past half its lifetime when the coordinator
+	// serves the refresh api.
+	tokenIssuedAt      time.Time
+	tokenExpiresAt     time.Time
+	refreshUnsupported bool
 
 	mu sync.Mutex
 }
 
 // NewCoordinatorClient constructs a new CoordinatorClient.
-func NewCoordinatorClient(cfg *config.CoordinatorConfig, proverName string, priv *ecdsa.PrivateKey) (*CoordinatorClient, error) {
+func NewCoordinatorClient(cfg *config.CoordinatorConfig, proverName, circuitVersion string, priv *ecdsa.PrivateKey) (*CoordinatorClient, error) {
 	client := resty.New().
 		SetTimeout(time.Duration(cfg.ConnectionTimeoutSec) * time.Second).
 		SetRetryCount(cfg.RetryCount).
@@ -43,6 +54,19 @@ func NewCoordinatorClient(cfg *config.CoordinatorConfig, proverName string, priv
 			return response.IsError()
 		})
 
+	payloadClient := resty.New().
+		SetTimeout(time.Duration(cfg.ConnectionTimeoutSec) * time.Second).
+		SetRetryCount(cfg.RetryCount).
+		SetRetryWaitTime(time.Duration(cfg.RetryWaitTimeSec) * time.Second).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			if err != nil {
+				log.Warn("Encountered an error while downloading the task payload. Retrying...", "error", err)
+				return true
+			}
+			// the signed URL expired or the payload was deleted, retrying does not help.
+			return response.StatusCode() >= 500
+		})
+
 	log.Info("successfully initialized prover client",
 		"base url", cfg.BaseURL,
 		"connection timeout (second)", cfg.ConnectionTimeoutSec,
@@ -50,9 +74,11 @@ func NewCoordinatorClient(cfg *config.CoordinatorConfig, proverName string, priv
 		"retry wait time (second)", cfg.RetryWaitTimeSec)
 
 	return &CoordinatorClient{
-		client:     client,
-		proverName: proverName,
-		priv:       priv,
+		client:         client,
+		payloadClient:  payloadClient,
+		proverName:     proverName,
+		circuitVersion: circuitVersion,
+		priv:           priv,
 	}, nil
 }
 
@@ -80,9 +106,10 @@ func (c *CoordinatorClient) Login(ctx context.Context) error {
 	// Prepare and sign the login request
 	authMsg := &message.AuthMsg{
 		Identity: &message.Identity{
-			ProverVersion: version.Version,
-			ProverName:    c.proverName,
-			Challenge:     challengeResult.Data.Token,
+			ProverVersion:  version.Version,
+			ProverName:     c.proverName,
+			Challenge:      challengeResult.Data.Token,
+			CircuitVersion: c.circuitVersion,
 		},
 	}
 
@@ -94,13 +121,15 @@ func (c *CoordinatorClient) Login(ctx context.Context) error {
 	// Login to coordinator
 	loginReq := &LoginRequest{
 		Message: struct {
-			Challenge     string `json:"challenge"`
-			ProverName    string `json:"prover_name"`
-			ProverVersion string `json:"prover_version"`
+			Challenge      string `json:"challenge"`
+			ProverName     string `json:"prover_name"`
+			ProverVersion  string `json:"prover_version"`
+			CircuitVersion string `json:"circuit_version,omitempty"`
 		}{
-			Challenge:     authMsg.Identity.Challenge,
-			ProverName:    authMsg.Identity.ProverName,
-			ProverVersion: authMsg.Identity.ProverVersion,
+			Challenge:      authMsg.Identity.Challenge,
+			ProverName:     authMsg.Identity.ProverName,
+			ProverVersion:  authMsg.Identity.ProverVersion,
+			CircuitVersion: authMsg.Identity.CircuitVersion,
 		},
 		Signature: authMsg.Signature,
 	}
@@ -128,15 +157,64 @@ func (c *CoordinatorClient) Login(ctx context.Context) error {
 	}
 
 	// store JWT token for future requests
-	c.client.SetAuthToken(loginResult.Data.Token)
+	c.setAuthToken(loginResult.Data.Token, loginResult.Data.Time)
 
 	return nil
 }
 
+// refreshTokenIfExpiring refreshes the login token once past half its lifetime. A token which can no longer be
+// refreshed is kept until it expires, the prover then logins again.
+func (c *CoordinatorClient) refreshTokenIfExpiring(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refreshUnsupported || c.tokenExpiresAt.IsZero() {
+		return
+	}
+	if time.Now().Before(c.tokenIssuedAt.Add(c.tokenExpiresAt.Sub(c.tokenIssuedAt) / 2)) {
+		return
+	}
+
+	var refreshResult LoginResponse
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetResult(&refreshResult).
+		Post("/coordinator/v1/refresh_token")
+	if err != nil {
+		log.Warn("failed to refresh the login token", "error", err)
+		return
+	}
+	if resp.StatusCode() == 404 {
+		log.Info("the coordinator does not refresh the login tokens, logging in again once expired")
+		c.refreshUnsupported = true
+		return
+	}
+	if resp.StatusCode() != 200 || refreshResult.ErrCode != types.Success || refreshResult.Data == nil {
+		log.Info("the login token was not refreshed, logging in again once expired", "status code", resp.StatusCode(), "error code", refreshResult.ErrCode, "error message", refreshResult.ErrMsg)
+		c.tokenExpiresAt = time.Time{}
+		return
+	}
+	c.setAuthToken(refreshResult.Data.Token, refreshResult.Data.Time)
+}
+
+// setAuthToken stores the login token for future requests, expiresAt is its expiry time as sent by the coordinator.
+func (c *CoordinatorClient) setAuthToken(token, expiresAt string) {
+	c.client.SetAuthToken(token)
+
+	c.tokenIssuedAt = time.Now()
+	c.tokenExpiresAt = time.Time{}
+	if expiry, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+		c.tokenExpiresAt = expiry
+	}
+}
+
 // GetTask sends a request to the coordinator to get prover task.
 func (c *CoordinatorClient) GetTask(ctx context.Context, req *GetTaskRequest) (*GetTaskResponse, error) {
 	var result GetTaskResponse
 
+	c.refreshTokenIfExpiring(ctx)
+
 	resp, err := c.client.R().
 		SetHeader("Content-Type", "application/json").
 		SetBody(req).
@@ -151,8 +229,8 @@ func (c *CoordinatorClient) GetTask(ctx context.Context, req *GetTaskRequest) (*
 		return nil, fmt.Errorf("failed to get task, status code: %v", resp.StatusCode())
 	}
 
-	if result.ErrCode == types.ErrJWTTokenExpired {
-		log.Info("JWT expired, attempting to re-login")
+	if result.ErrCode == types.ErrJWTTokenExpired || result.ErrCode == types.ErrJWTTokenRevoked {
+		log.Info("JWT expired or revoked, attempting to re-login", "error code", result.ErrCode)
 		if err := c.Login(ctx); err != nil {
 			return nil, fmt.Errorf("JWT expired, re-login failed: %w", err)
 		}
@@ -166,10 +244,29 @@ func (c *CoordinatorClient) GetTask(ctx context.Context, req *GetTaskRequest) (*
 	return &result, nil
 }
 
+// GetTaskData downloads the task payload offloaded by the coordinator and checks it against its sha256 hash.
+func (c *CoordinatorClient) GetTaskData(ctx context.Context, url, hash string) (string, error) {
+	resp, err := c.payloadClient.R().SetContext(ctx).Get(url)
+	if err != nil {
+		return "", fmt.Errorf("request for the task payload failed: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("failed to download the task payload, status code: %v", resp.StatusCode())
+	}
+
+	sum := sha256.Sum256(resp.Body())
+	if got := hex.EncodeToString(sum[:]); got != hash {
+		return "", fmt.Errorf("task payload hash mismatch, expected: %s, got: %s", hash, got)
+	}
+	return string(resp.Body()), nil
+}
+
 // SubmitProof sends a request to the coordinator to submit proof.
 func (c *CoordinatorClient) SubmitProof(ctx context.Context, req *SubmitProofRequest) error {
 	var result SubmitProofResponse
 
+	c.refreshTokenIfExpiring(ctx)
+
 	resp, err := c.client.R().
 		SetHeader("Content-Type", "application/json").
 		SetBody(req).
@@ -186,8 +283,8 @@ func (c *CoordinatorClient) SubmitProof(ctx context.Context, req *SubmitProofReq
 		return fmt.Errorf("failed to submit proof, status code not 200: %w", ErrCoordinatorConnect)
 	}
 
-	if result.ErrCode == types.ErrJWTTokenExpired {
-		log.Info("JWT expired, attempting to re-login")
+	if result.ErrCode == types.ErrJWTTokenExpired || result.ErrCode == types.ErrJWTTokenRevoked {
+		log.Info("JWT expired or revoked, attempting to re-login", "error code", result.ErrCode)
 		if err := c.Login(ctx); err != nil {
 			log.Error("JWT expired, re-login failed", "error", err)
 			return fmt.Errorf("JWT expired, re-login failed: %w", ErrCoordinatorConnect)
@@ -202,3 +299,39 @@ func (c *CoordinatorClient) SubmitProof(ctx context.Context, req *SubmitProofReq
 
 	return nil
 }
+
+// Heartbeat sends the heartbeat of the prover to the coordinator.
+func (c *CoordinatorClient) Heartbeat(ctx context.Context, req *HeartbeatRequest) error {
+	var result HeartbeatResponse
+
+	c.refreshTokenIfExpiring(ctx)
+
+	resp, err := c.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(req).
+		SetResult(&result).
+		Post("/coordinator/v1/heartbeat")
+
+	if err != nil {
+		return fmt.Errorf("heartbeat request failed: %w", ErrCoordinatorConnect)
+	}
+
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("failed to send heartbeat, status code not 200: %w", ErrCoordinatorConnect)
+	}
+
+	if result.ErrCode == types.ErrJWTTokenExpired || result.ErrCode == types.ErrJWTTokenRevoked {
+		log.Info("JWT expired or revoked, attempting to re-login", "error code", result.ErrCode)
+		if err := c.Login(ctx); err != nil {
+			return fmt.Errorf("JWT expired, re-login failed: %w", ErrCoordinatorConnect)
+		}
+		log.Info("re-login success")
+		return c.Heartbeat(ctx, req)
+	}
+
+	if result.ErrCode != types.Success {
+		return fmt.Errorf("error code: %v, error message: %v", result.ErrCode, result.ErrMsg)
+	}
+
+	return nil
+}
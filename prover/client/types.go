@@ -22,9 +22,10 @@ type ChallengeResponse struct {
 // LoginRequest defines the request structure for login API
 type LoginRequest struct {
 	Message struct {
-		Challenge     string `json:"challenge"`
-		ProverName    string `json:"prover_name"`
-		ProverVersion string `json:"prover_version"`
+		Challenge      string `json:"challenge"`
+		ProverName     string `json:"prover_name"`
+		ProverVersion  string `json:"prover_version"`
+		CircuitVersion string `json:"circuit_version,omitempty"`
 	} `json:"message"`
 	Signature string `json:"signature"`
 }
@@ -55,6 +56,9 @@ type GetTaskResponse struct {
 		TaskID   string `json:"task_id"`
 		TaskType int    `json:"task_type"`
 		TaskData string `json:"task_data"`
+		// TaskDataURL is set instead of TaskData when the coordinator offloaded the payload.
+		TaskDataURL  string `json:"task_data_url,omitempty"`
+		TaskDataHash string `json:"task_data_hash,omitempty"`
 	} `json:"data"`
 }
 
@@ -74,3 +78,21 @@ type SubmitProofResponse struct {
 	ErrCode int    `json:"errcode"`
 	ErrMsg  string `json:"errmsg"`
 }
+
+// HeartbeatRequest defines the request structure for the Heartbeat API.
+type HeartbeatRequest struct {
+	ProverHeight    int      `json:"prover_height,omitempty"`
+	GPUMemoryMB     uint64   `json:"gpu_memory_mb,omitempty"`
+	CircuitVersions []string `json:"circuit_versions,omitempty"`
+	TaskProgress    int      `json:"task_progress,omitempty"`
+	RecentErrors    uint64   `json:"recent_errors,omitempty"`
+}
+
+// HeartbeatResponse defines the response structure for the Heartbeat API.
+type HeartbeatResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Data    *struct {
+		Time int64 `json:"time"`
+	} `json:"data"`
+}
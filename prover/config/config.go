@@ -20,6 +20,8 @@ type Config struct {
 	DBPath           string             `json:"db_path"`
 	Coordinator      *CoordinatorConfig `json:"coordinator"`
 	L2Geth           *L2GethConfig      `json:"l2geth,omitempty"` // only for chunk_prover
+	// reports the resources and the progress of the prover to the coordinator when set
+	Heartbeat *HeartbeatConfig `json:"heartbeat,omitempty"`
 }
 
 // ProverCoreConfig load zk prover config.
@@ -28,6 +30,53 @@ type ProverCoreConfig struct {
 	AssetsPath string            `json:"assets_path"`
 	ProofType  message.ProofType `json:"proof_type,omitempty"` // 1: chunk prover (default type), 2: batch prover
 	DumpDir    string            `json:"dump_dir,omitempty"`
+	// advertised at login to the coordinators routing the tasks by circuit version
+	CircuitVersion string `json:"circuit_version,omitempty"`
+	// proves several tasks at once when set, one at a time otherwise
+	Concurrency *ConcurrencyConfig `json:"concurrency,omitempty"`
+	// proves with a remote proving service when set, with the local libzkp otherwise
+	Remote *RemoteBackendConfig `json:"remote,omitempty"`
+}
+
+// RemoteBackendConfig loads the connection to a remote proving service. The service is reached over TLS when
+// CAFile is set, and the prover authenticates with a client certificate when CertFile and KeyFile are set.
+type RemoteBackendConfig struct {
+	// Endpoint is the grpc address of the proving service, e.g. "prover-farm:8392".
+	Endpoint string `json:"endpoint"`
+	// CAFile is the PEM encoded CA certificate the service certificate is verified with.
+	CAFile string `json:"ca_file,omitempty"`
+	// CertFile and KeyFile are the PEM encoded client certificate and key of the prover.
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	// TimeoutSec is the timeout of a proving call, 0 waits as long as the proving takes.
+	TimeoutSec int `json:"timeout_sec,omitempty"`
+}
+
+// ConcurrencyConfig loads the scheduling of the tasks proven at once. A new task is only fetched while the running
+// tasks are below the cap of the circuit type and the free memory is enough for another task. The coordinator must
+// allow the prover as many concurrent tasks.
+type ConcurrencyConfig struct {
+	// MaxChunkTasks and MaxBatchTasks cap the tasks proven at once per circuit type, default 1.
+	MaxChunkTasks int `json:"max_chunk_tasks,omitempty"`
+	MaxBatchTasks int `json:"max_batch_tasks,omitempty"`
+	// TaskMemoryMB is the peak memory of a task, 0 does not check the free memory.
+	TaskMemoryMB uint64 `json:"task_memory_mb,omitempty"`
+	// TaskGPUMemoryMB is the peak GPU memory of a task, 0 does not check the free GPU memory.
+	TaskGPUMemoryMB uint64 `json:"task_gpu_memory_mb,omitempty"`
+	// TaskStartIntervalSec is the time a started task is given to allocate its memory before the free memory is
+	// measured for the next one, default 30.
+	TaskStartIntervalSec int `json:"task_start_interval_sec,omitempty"`
+}
+
+// HeartbeatConfig loads the heartbeat sent periodically to the coordinator, carrying the free GPU memory, the circuit
+// versions supported, the estimated progress of the current task and the number of recent errors.
+type HeartbeatConfig struct {
+	// IntervalSec is the time between two heartbeats, default 30.
+	IntervalSec int `json:"interval_sec,omitempty"`
+	// ErrorWindowSec is the time over which the proving and submission errors are counted, default 3600.
+	ErrorWindowSec int `json:"error_window_sec,omitempty"`
+	// CircuitVersions are the circuit versions supported, default the circuit version of the core if set.
+	CircuitVersions []string `json:"circuit_versions,omitempty"`
 }
 
 // CoordinatorConfig represents the configuration for the Coordinator client.
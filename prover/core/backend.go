@@ -0,0 +1,20 @@
+package core
+
+import (
+	"github.com/scroll-tech/go-ethereum/core/types"
+
+	"scroll-tech/common/types/message"
+)
+
+// Backend proves the tasks of the prover, locally with libzkp or with a remote proving service.
+type Backend interface {
+	// VerifyingKey returns the vk of the circuit, empty if it is not known yet.
+	VerifyingKey() string
+	ProveChunk(taskID string, traces []*types.BlockTrace) (*message.ChunkProof, error)
+	ProveBatch(taskID string, chunkInfos []*message.ChunkInfo, chunkProofs []*message.ChunkProof) (*message.BatchProof, error)
+}
+
+// VerifyingKey implements Backend.
+func (p *ProverCore) VerifyingKey() string {
+	return p.VK
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/prover/config"
+)
+
+// remoteServiceName is the full name of the grpc service of the remote proving services.
+const remoteServiceName = "prover.v1.ProvingService"
+
+// VKRequest asks the remote proving service for the vk of the circuit of a proof type.
+type VKRequest struct {
+	ProofType message.ProofType `json:"proof_type"`
+}
+
+// VKResponse is the vk of a circuit.
+type VKResponse struct {
+	VK string `json:"vk"`
+}
+
+// ProveChunkRequest forwards the block traces of a chunk to the remote proving service.
+type ProveChunkRequest struct {
+	TaskID string              `json:"task_id"`
+	Traces []*types.BlockTrace `json:"traces"`
+}
+
+// ProveChunkResponse is the proof of a chunk.
+type ProveChunkResponse struct {
+	Proof *message.ChunkProof `json:"proof"`
+}
+
+// ProveBatchRequest forwards the chunk infos and proofs of a batch to the remote proving service.
+type ProveBatchRequest struct {
+	TaskID      string                `json:"task_id"`
+	ChunkInfos  []*message.ChunkInfo  `json:"chunk_infos"`
+	ChunkProofs []*message.ChunkProof `json:"chunk_proofs"`
+}
+
+// ProveBatchResponse is the proof of a batch.
+type ProveBatchResponse struct {
+	Proof *message.BatchProof `json:"proof"`
+}
+
+// RemoteBackend proves the tasks with a remote proving service over grpc, the messages are json encoded.
+type RemoteBackend struct {
+	proofType message.ProofType
+	timeout   time.Duration
+	conn      *grpc.ClientConn
+
+	mu sync.Mutex
+	vk string
+}
+
+// NewRemoteBackend connects to the remote proving service of cfg.
+func NewRemoteBackend(cfg *config.RemoteBackendConfig, proofType message.ProofType) (*RemoteBackend, error) {
+	creds, err := remoteCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(cfg.Endpoint, grpc.WithTransportCredentials(creds), grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the remote proving service %s: %w", cfg.Endpoint, err)
+	}
+
+	b := &RemoteBackend{
+		proofType: proofType,
+		timeout:   time.Duration(cfg.TimeoutSec) * time.Second,
+		conn:      conn,
+	}
+	// we may not be able to get the vk at the first time, it is asked again when needed.
+	if b.VerifyingKey() == "" {
+		log.Warn("failed to get the vk from the remote proving service", "endpoint", cfg.Endpoint)
+	}
+	return b, nil
+}
+
+func remoteCredentials(cfg *config.RemoteBackendConfig) (credentials.TransportCredentials, error) {
+	if cfg.CAFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	caPEM, err := os.ReadFile(filepath.Clean(cfg.CAFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote proving service ca: %w", err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in remote proving service ca %s", cfg.CAFile)
+	}
+	tlsConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load remote proving service client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// VerifyingKey implements Backend.
+func (b *RemoteBackend) VerifyingKey() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.vk != "" {
+		return b.vk
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var resp VKResponse
+	if err := b.conn.Invoke(ctx, "/"+remoteServiceName+"/GetVK", &VKRequest{ProofType: b.proofType}, &resp); err != nil {
+		log.Error("failed to get the vk from the remote proving service", "proof type", b.proofType, "err", err)
+		return ""
+	}
+	b.vk = resp.VK
+	return b.vk
+}
+
+// ProveChunk implements Backend.
+func (b *RemoteBackend) ProveChunk(taskID string, traces []*types.BlockTrace) (*message.ChunkProof, error) {
+	if b.proofType != message.ProofTypeChunk {
+		return nil, fmt.Errorf("prover is not a chunk-prover (type: %v), but is trying to prove a chunk", b.proofType)
+	}
+	var resp ProveChunkResponse
+	if err := b.invoke("ProveChunk", &ProveChunkRequest{TaskID: taskID, Traces: traces}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to generate chunk proof remotely: %w", err)
+	}
+	if resp.Proof == nil {
+		return nil, fmt.Errorf("empty chunk proof from the remote proving service, task-id: %s", taskID)
+	}
+	return resp.Proof, nil
+}
+
+// ProveBatch implements Backend.
+func (b *RemoteBackend) ProveBatch(taskID string, chunkInfos []*message.ChunkInfo, chunkProofs []*message.ChunkProof) (*message.BatchProof, error) {
+	if b.proofType != message.ProofTypeBatch {
+		return nil, fmt.Errorf("prover is not a batch-prover (type: %v), but is trying to prove a batch", b.proofType)
+	}
+	var resp ProveBatchResponse
+	if err := b.invoke("ProveBatch", &ProveBatchRequest{TaskID: taskID, ChunkInfos: chunkInfos, ChunkProofs: chunkProofs}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to generate batch proof remotely: %w", err)
+	}
+	if resp.Proof == nil {
+		return nil, fmt.Errorf("empty batch proof from the remote proving service, task-id: %s", taskID)
+	}
+	return resp.Proof, nil
+}
+
+func (b *RemoteBackend) invoke(method string, req, resp interface{}) error {
+	ctx := context.Background()
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	log.Info("Start to create proof remotely ...", "method", method)
+	if err := b.conn.Invoke(ctx, "/"+remoteServiceName+"/"+method, req, resp); err != nil {
+		return err
+	}
+	log.Info("Finish creating proof remotely!", "method", method)
+	return nil
+}
+
+// Close closes the connection to the remote proving service.
+func (b *RemoteBackend) Close() error {
+	return b.conn.Close()
+}
+
+// jsonCodec encodes the grpc messages of the remote proving service in json.
+type jsonCodec struct{}
+
+// Marshal implements encoding.Codec.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements encoding.Codec.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name implements encoding.Codec.
+func (jsonCodec) Name() string {
+	return "json"
+}
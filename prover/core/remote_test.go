@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/prover/config"
+)
+
+type provingService struct{}
+
+func (provingService) getVK(_ context.Context, req *VKRequest) (*VKResponse, error) {
+	return &VKResponse{VK: req.ProofType.String() + "-vk"}, nil
+}
+
+func (provingService) proveChunk(_ context.Context, req *ProveChunkRequest) (*ProveChunkResponse, error) {
+	return &ProveChunkResponse{Proof: &message.ChunkProof{Proof: []byte(req.TaskID)}}, nil
+}
+
+func unaryHandler[Req, Resp any](handle func(provingService, context.Context, *Req) (*Resp, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		return handle(srv.(provingService), ctx, in)
+	}
+}
+
+func TestRemoteBackend(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer(grpc.ForceServerCodec(jsonCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: remoteServiceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "GetVK", Handler: unaryHandler(provingService.getVK)},
+			{MethodName: "ProveChunk", Handler: unaryHandler(provingService.proveChunk)},
+		},
+	}, provingService{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	backend, err := NewRemoteBackend(&config.RemoteBackendConfig{Endpoint: lis.Addr().String(), TimeoutSec: 10}, message.ProofTypeChunk)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, backend.Close())
+	}()
+
+	assert.Equal(t, "proof type chunk-vk", backend.VerifyingKey())
+
+	proof, err := backend.ProveChunk("task-0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("task-0"), proof.Proof)
+
+	_, err = backend.ProveBatch("task-1", nil, nil)
+	assert.Error(t, err)
+}
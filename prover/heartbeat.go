@@ -0,0 +1,158 @@
+package prover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/prover/client"
+	"scroll-tech/prover/config"
+	putils "scroll-tech/prover/utils"
+)
+
+const (
+	defaultHeartbeatInterval = 30 * time.Second
+	defaultErrorWindow       = time.Hour
+	// provingTimeAlpha is the smoothing factor of the average proving time the progress is estimated from.
+	provingTimeAlpha = 0.3
+)
+
+// heartbeatReporter tracks the running tasks and the errors of the prover for its heartbeats. The libzkp proving
+// calls report no progress, the progress of a task is estimated from its elapsed time and the average proving time
+// of the previous tasks. A nil heartbeatReporter tracks nothing.
+type heartbeatReporter struct {
+	interval        time.Duration
+	errorWindow     time.Duration
+	circuitVersions []string
+
+	availableGPUMemoryMB func() (uint64, error)
+
+	mu             sync.Mutex
+	running        map[string]time.Time
+	avgProvingTime time.Duration
+	errors         []time.Time
+}
+
+func newHeartbeatReporter(ctx context.Context, cfg *config.HeartbeatConfig, circuitVersion string) *heartbeatReporter {
+	interval := defaultHeartbeatInterval
+	if cfg.IntervalSec > 0 {
+		interval = time.Duration(cfg.IntervalSec) * time.Second
+	}
+	errorWindow := defaultErrorWindow
+	if cfg.ErrorWindowSec > 0 {
+		errorWindow = time.Duration(cfg.ErrorWindowSec) * time.Second
+	}
+	circuitVersions := cfg.CircuitVersions
+	if len(circuitVersions) == 0 && circuitVersion != "" {
+		circuitVersions = []string{circuitVersion}
+	}
+
+	return &heartbeatReporter{
+		interval:        interval,
+		errorWindow:     errorWindow,
+		circuitVersions: circuitVersions,
+		availableGPUMemoryMB: func() (uint64, error) {
+			return putils.AvailableGPUMemoryMB(ctx)
+		},
+		running: make(map[string]time.Time),
+	}
+}
+
+// taskStarted records the start of the proving of a task.
+func (h *heartbeatReporter) taskStarted(taskID string, now time.Time) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.running[taskID] = now
+}
+
+// taskFinished records the end of the proving of a task, its proving time when it succeeded and an error otherwise.
+func (h *heartbeatReporter) taskFinished(taskID string, now time.Time, err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	start, ok := h.running[taskID]
+	delete(h.running, taskID)
+	if err != nil {
+		h.errors = append(h.errors, now)
+		return
+	}
+	if !ok {
+		return
+	}
+	if provingTime := now.Sub(start); h.avgProvingTime == 0 {
+		h.avgProvingTime = provingTime
+	} else {
+		h.avgProvingTime = time.Duration(provingTimeAlpha*float64(provingTime) + (1-provingTimeAlpha)*float64(h.avgProvingTime))
+	}
+}
+
+// recordError records an error other than a proving failure, e.g. a failed proof submission.
+func (h *heartbeatReporter) recordError(now time.Time) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errors = append(h.errors, now)
+}
+
+// request returns the heartbeat at now: the estimated progress of the task started first, at most 99 until it is
+// done and 0 without previous proving times, and the errors within the error window.
+func (h *heartbeatReporter) request(now time.Time) *client.HeartbeatRequest {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	req := &client.HeartbeatRequest{CircuitVersions: h.circuitVersions}
+	var earliest time.Time
+	for _, start := range h.running {
+		if earliest.IsZero() || start.Before(earliest) {
+			earliest = start
+		}
+	}
+	if !earliest.IsZero() && h.avgProvingTime > 0 {
+		req.TaskProgress = int(100 * now.Sub(earliest) / h.avgProvingTime)
+		if req.TaskProgress > 99 {
+			req.TaskProgress = 99
+		}
+	}
+
+	recent := h.errors[:0]
+	for _, t := range h.errors {
+		if now.Sub(t) <= h.errorWindow {
+			recent = append(recent, t)
+		}
+	}
+	h.errors = recent
+	req.RecentErrors = uint64(len(recent))
+	return req
+}
+
+// HeartbeatLoop sends the heartbeats to the coordinator until the prover stops.
+func (r *Prover) HeartbeatLoop() {
+	ticker := time.NewTicker(r.heartbeat.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		req := r.heartbeat.request(time.Now())
+		gpuMemory, err := r.heartbeat.availableGPUMemoryMB()
+		if err != nil {
+			log.Debug("failed to read the free GPU memory of the heartbeat", "error", err)
+		}
+		req.GPUMemoryMB = gpuMemory
+		if err = r.coordinatorClient.Heartbeat(r.ctx, req); err != nil {
+			log.Warn("failed to send heartbeat to coordinator", "error", err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package prover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/prover/config"
+)
+
+func TestHeartbeatReporter(t *testing.T) {
+	h := newHeartbeatReporter(context.Background(), &config.HeartbeatConfig{ErrorWindowSec: 60}, "v0.12.0")
+	assert.Equal(t, defaultHeartbeatInterval, h.interval)
+	assert.Equal(t, []string{"v0.12.0"}, h.circuitVersions)
+	now := time.Now()
+
+	// no progress is estimated without a previous proving time.
+	h.taskStarted("1", now)
+	assert.Equal(t, 0, h.request(now.Add(time.Minute)).TaskProgress)
+	h.taskFinished("1", now.Add(100*time.Second), nil)
+	assert.Equal(t, 100*time.Second, h.avgProvingTime)
+
+	// the progress of the running task is its elapsed time over the average proving time, at most 99.
+	h.taskStarted("2", now.Add(200*time.Second))
+	assert.Equal(t, 50, h.request(now.Add(250*time.Second)).TaskProgress)
+	assert.Equal(t, 99, h.request(now.Add(400*time.Second)).TaskProgress)
+
+	// failed tasks and submissions are counted within the error window.
+	h.taskFinished("2", now.Add(400*time.Second), errors.New("proving error"))
+	assert.Equal(t, 100*time.Second, h.avgProvingTime)
+	h.recordError(now.Add(430 * time.Second))
+	req := h.request(now.Add(450 * time.Second))
+	assert.Equal(t, 0, req.TaskProgress)
+	assert.Equal(t, uint64(2), req.RecentErrors)
+	assert.Equal(t, uint64(1), h.request(now.Add(470*time.Second)).RecentErrors)
+	assert.Equal(t, uint64(0), h.request(now.Add(500*time.Second)).RecentErrors)
+
+	// a nil reporter tracks nothing.
+	var nilReporter *heartbeatReporter
+	nilReporter.taskStarted("3", now)
+	nilReporter.taskFinished("3", now, nil)
+	nilReporter.recordError(now)
+}
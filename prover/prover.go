@@ -29,6 +29,8 @@ import (
 var (
 	// retry connecting to coordinator
 	retryWait = time.Second * 10
+	// check again whether the scheduler allows one more task
+	schedulerPollInterval = time.Second * 5
 )
 
 // Prover contains websocket conn to coordinator, and task stack.
@@ -38,7 +40,9 @@ type Prover struct {
 	coordinatorClient *client.CoordinatorClient
 	stack             *store.Stack
 	l2GethClient      *ethclient.Client // only applicable for a chunk_prover
-	proverCore        *core.ProverCore
+	proverCore        core.Backend
+	scheduler         *scheduler         // only set when proving several tasks at once
+	heartbeat         *heartbeatReporter // only set when heartbeats are sent to the coordinator
 
 	isClosed int64
 	stopChan chan struct{}
@@ -74,19 +78,35 @@ func NewProver(ctx context.Context, cfg *config.Config) (*Prover, error) {
 		l2GethClient.SetHeader("Accept-Encoding", "gzip")
 	}
 
-	// Create prover_core instance
-	log.Info("init prover_core")
-	newProverCore, err := core.NewProverCore(cfg.Core)
+	var newProverCore core.Backend
+	if cfg.Core.Remote != nil {
+		log.Info("connect to the remote proving service", "endpoint", cfg.Core.Remote.Endpoint)
+		newProverCore, err = core.NewRemoteBackend(cfg.Core.Remote, cfg.Core.ProofType)
+	} else {
+		// Create prover_core instance
+		log.Info("init prover_core")
+		newProverCore, err = core.NewProverCore(cfg.Core)
+	}
 	if err != nil {
 		return nil, err
 	}
 	log.Info("init prover_core successfully!")
 
-	coordinatorClient, err := client.NewCoordinatorClient(cfg.Coordinator, cfg.ProverName, priv)
+	coordinatorClient, err := client.NewCoordinatorClient(cfg.Coordinator, cfg.ProverName, cfg.Core.CircuitVersion, priv)
 	if err != nil {
 		return nil, err
 	}
 
+	var taskScheduler *scheduler
+	if cfg.Core.Concurrency != nil {
+		taskScheduler = newScheduler(ctx, cfg.Core.Concurrency, cfg.Core.ProofType)
+	}
+
+	var heartbeat *heartbeatReporter
+	if cfg.Heartbeat != nil {
+		heartbeat = newHeartbeatReporter(ctx, cfg.Heartbeat, cfg.Core.CircuitVersion)
+	}
+
 	return &Prover{
 		ctx:               ctx,
 		cfg:               cfg,
@@ -94,6 +114,8 @@ func NewProver(ctx context.Context, cfg *config.Config) (*Prover, error) {
 		l2GethClient:      l2GethClient,
 		stack:             stackDb,
 		proverCore:        newProverCore,
+		scheduler:         taskScheduler,
+		heartbeat:         heartbeat,
 		stopChan:          make(chan struct{}),
 		priv:              priv,
 	}, nil
@@ -117,6 +139,15 @@ func (r *Prover) Start() {
 	}
 	log.Info("login to coordinator successfully!")
 
+	r.resubmitCachedProofs()
+
+	if r.heartbeat != nil {
+		go r.HeartbeatLoop()
+	}
+	if r.scheduler != nil {
+		go r.ConcurrentProveLoop()
+		return
+	}
 	go r.ProveLoop()
 }
 
@@ -153,7 +184,107 @@ func (r *Prover) proveAndSubmit() error {
 		}
 	}
 
-	var proofMsg *message.ProofDetail
+	return r.proveTask(task)
+}
+
+// ConcurrentProveLoop keeps fetching new tasks while the scheduler allows one more, and proves each in its own
+// goroutine. The tasks left in the stack by a previous run are resumed first.
+func (r *Prover) ConcurrentProveLoop() {
+	tasks, err := r.stack.List()
+	if err != nil {
+		log.Error("failed to list the tasks of the stack", "error", err)
+	}
+	for _, task := range tasks {
+		r.scheduler.acquire(time.Now())
+		go r.proveScheduledTask(task)
+	}
+
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		default:
+		}
+
+		if !r.scheduler.tryAcquire(time.Now()) {
+			time.Sleep(schedulerPollInterval)
+			continue
+		}
+
+		task, err := r.fetchTaskFromCoordinator()
+		if err != nil {
+			r.scheduler.release()
+			log.Error("failed to fetch task from coordinator", "prover type", r.cfg.Core.ProofType, "error", err)
+			time.Sleep(retryWait)
+			continue
+		}
+		if err = r.stack.Push(task); err != nil {
+			r.scheduler.release()
+			log.Error("failed to push task into stack", "task-id", task.Task.ID, "error", err)
+			continue
+		}
+		go r.proveScheduledTask(task)
+	}
+}
+
+func (r *Prover) proveScheduledTask(task *store.ProvingTask) {
+	defer r.scheduler.release()
+	for {
+		err := r.proveTask(task)
+		if err == nil {
+			return
+		}
+		log.Error("proveTask", "prover type", r.cfg.Core.ProofType, "task-id", task.Task.ID, "error", err)
+
+		// the proof is kept when the coordinator could not be reached, submit it again.
+		proofMsg, err := r.stack.GetProof(task.Task.ID)
+		if err != nil || proofMsg == nil {
+			return
+		}
+		select {
+		case <-r.stopChan:
+			return
+		case <-time.After(retryWait):
+		}
+	}
+}
+
+// resubmitCachedProofs submits the proofs cached before a restart or a lost connection. The proofs of the tasks no
+// longer open in the coordinator are rejected and dropped.
+func (r *Prover) resubmitCachedProofs() {
+	tasks, err := r.stack.List()
+	if err != nil {
+		log.Error("failed to list the tasks of the stack", "error", err)
+		return
+	}
+	for _, task := range tasks {
+		proofMsg, err := r.stack.GetProof(task.Task.ID)
+		if err != nil {
+			log.Error("failed to get the cached proof", "task-id", task.Task.ID, "error", err)
+			continue
+		}
+		if proofMsg == nil {
+			continue
+		}
+		log.Info("resubmit cached proof", "task-type", task.Task.Type, "task-id", task.Task.ID)
+		if err = r.submitProof(proofMsg, task.Task.UUID); err != nil {
+			log.Error("failed to resubmit cached proof", "task-type", task.Task.Type, "task-id", task.Task.ID, "error", err)
+		}
+	}
+}
+
+// proveTask proves a task of the stack and submits the proof, or the failure once the task was tried too many times.
+// A proof is cached until it is submitted, a task already proven is submitted again instead of proven again.
+func (r *Prover) proveTask(task *store.ProvingTask) error {
+	proofMsg, err := r.stack.GetProof(task.Task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get cached proof: %v", err)
+	}
+	if proofMsg != nil {
+		log.Info("submit cached proof", "task-type", task.Task.Type, "task-id", task.Task.ID)
+		return r.submitProof(proofMsg, task.Task.UUID)
+	}
+
 	if task.Times <= 2 {
 		// If tried times <= 2, try to proof the task.
 		if err = r.stack.UpdateTimes(task, task.Times+1); err != nil {
@@ -161,11 +292,16 @@ func (r *Prover) proveAndSubmit() error {
 		}
 
 		log.Info("start to prove task", "task-type", task.Task.Type, "task-id", task.Task.ID)
+		r.heartbeat.taskStarted(task.Task.ID, time.Now())
 		proofMsg, err = r.prove(task)
+		r.heartbeat.taskFinished(task.Task.ID, time.Now(), err)
 		if err != nil { // handling error from prove
 			log.Error("failed to prove task", "task_type", task.Task.Type, "task-id", task.Task.ID, "err", err)
 			return r.submitErr(task, message.ProofFailureNoPanic, err)
 		}
+		if err = r.stack.PutProof(task.Task.ID, proofMsg); err != nil {
+			log.Error("failed to cache proof", "task-type", task.Task.Type, "task-id", task.Task.ID, "err", err)
+		}
 		return r.submitProof(proofMsg, task.Task.UUID)
 	}
 
@@ -181,7 +317,7 @@ func (r *Prover) fetchTaskFromCoordinator() (*store.ProvingTask, error) {
 		TaskType: r.Type(),
 		// we may not be able to get the vk at the first time, so we should pass vk to the coordinator every time we getTask
 		// instead of passing vk when we login
-		VK: r.proverCore.VK,
+		VK: r.proverCore.VerifyingKey(),
 	}
 
 	if req.TaskType == message.ProofTypeChunk {
@@ -202,6 +338,11 @@ func (r *Prover) fetchTaskFromCoordinator() (*store.ProvingTask, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task, req: %v, err: %v", req, err)
 	}
+	if resp.Data.TaskDataURL != "" {
+		if resp.Data.TaskData, err = r.coordinatorClient.GetTaskData(r.ctx, resp.Data.TaskDataURL, resp.Data.TaskDataHash); err != nil {
+			return nil, fmt.Errorf("failed to download task data, task id: %v, err: %v", resp.Data.TaskID, err)
+		}
+	}
 
 	// create a new TaskMsg
 	taskMsg := message.TaskMsg{
@@ -284,11 +425,37 @@ func (r *Prover) proveChunk(task *store.ProvingTask) (*message.ChunkProof, error
 	if task.Task.ChunkTaskDetail == nil {
 		return nil, fmt.Errorf("ChunkTaskDetail is empty")
 	}
+	traces, err := r.getChunkWitness(task)
+	if err != nil {
+		return nil, err
+	}
+	return r.proverCore.ProveChunk(task.Task.ID, traces)
+}
+
+// getChunkWitness returns the block traces of a chunk task, cached so a restart does not fetch them again.
+func (r *Prover) getChunkWitness(task *store.ProvingTask) ([]*types.BlockTrace, error) {
+	witness, err := r.stack.GetWitness(task.Task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached witness: %v", err)
+	}
+	if witness != nil {
+		var traces []*types.BlockTrace
+		if err = json.Unmarshal(witness, &traces); err == nil {
+			return traces, nil
+		}
+		log.Warn("invalid cached witness, fetch the traces again", "task-id", task.Task.ID, "err", err)
+	}
+
 	traces, err := r.getSortedTracesByHashes(task.Task.ChunkTaskDetail.BlockHashes)
 	if err != nil {
 		return nil, fmt.Errorf("get traces from eth node failed, block hashes: %v, err: %v", task.Task.ChunkTaskDetail.BlockHashes, err)
 	}
-	return r.proverCore.ProveChunk(task.Task.ID, traces)
+	if witness, err = json.Marshal(traces); err != nil {
+		log.Error("failed to encode witness", "task-id", task.Task.ID, "err", err)
+	} else if err = r.stack.PutWitness(task.Task.ID, witness); err != nil {
+		log.Error("failed to cache witness", "task-id", task.Task.ID, "err", err)
+	}
+	return traces, nil
 }
 
 func (r *Prover) proveBatch(task *store.ProvingTask) (*message.BatchProof, error) {
@@ -329,6 +496,7 @@ func (r *Prover) submitProof(msg *message.ProofDetail, uuid string) error {
 
 	// send the submit request
 	if err := r.coordinatorClient.SubmitProof(r.ctx, req); err != nil {
+		r.heartbeat.recordError(time.Now())
 		if !errors.Is(errors.Unwrap(err), client.ErrCoordinatorConnect) {
 			if deleteErr := r.stack.Delete(msg.ID); deleteErr != nil {
 				log.Error("prover stack pop failed", "task_type", msg.Type, "task_id", msg.ID, "err", deleteErr)
@@ -359,6 +527,7 @@ func (r *Prover) submitErr(task *store.ProvingTask, proofFailureType message.Pro
 
 	// send the submit request
 	if submitErr := r.coordinatorClient.SubmitProof(r.ctx, req); submitErr != nil {
+		r.heartbeat.recordError(time.Now())
 		if !errors.Is(errors.Unwrap(err), client.ErrCoordinatorConnect) {
 			if deleteErr := r.stack.Delete(task.Task.ID); deleteErr != nil {
 				log.Error("prover stack pop failed", "task_type", task.Task.Type, "task_id", task.Task.ID, "err", deleteErr)
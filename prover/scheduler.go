@@ -0,0 +1,120 @@
+package prover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/prover/config"
+	putils "scroll-tech/prover/utils"
+)
+
+const defaultTaskStartInterval = 30 * time.Second
+
+// scheduler decides when the prover can start one more task: below the cap of its circuit type, once the last task
+// started had time to allocate its memory, and while the free memory is enough for another task.
+type scheduler struct {
+	maxTasks          int
+	taskMemoryMB      uint64
+	taskGPUMemoryMB   uint64
+	taskStartInterval time.Duration
+
+	availableMemoryMB    func() (uint64, error)
+	availableGPUMemoryMB func() (uint64, error)
+
+	mu        sync.Mutex
+	running   int
+	lastStart time.Time
+}
+
+func newScheduler(ctx context.Context, cfg *config.ConcurrencyConfig, proofType message.ProofType) *scheduler {
+	maxTasks := cfg.MaxChunkTasks
+	if proofType == message.ProofTypeBatch {
+		maxTasks = cfg.MaxBatchTasks
+	}
+	if maxTasks <= 0 {
+		maxTasks = 1
+	}
+	taskStartInterval := defaultTaskStartInterval
+	if cfg.TaskStartIntervalSec > 0 {
+		taskStartInterval = time.Duration(cfg.TaskStartIntervalSec) * time.Second
+	}
+
+	return &scheduler{
+		maxTasks:          maxTasks,
+		taskMemoryMB:      cfg.TaskMemoryMB,
+		taskGPUMemoryMB:   cfg.TaskGPUMemoryMB,
+		taskStartInterval: taskStartInterval,
+		availableMemoryMB: putils.AvailableMemoryMB,
+		availableGPUMemoryMB: func() (uint64, error) {
+			return putils.AvailableGPUMemoryMB(ctx)
+		},
+	}
+}
+
+// tryAcquire reserves a slot for a new task, the slot must be released once the task is done.
+func (s *scheduler) tryAcquire(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running >= s.maxTasks {
+		return false
+	}
+	if s.running > 0 {
+		if now.Sub(s.lastStart) < s.taskStartInterval {
+			return false
+		}
+		if !s.hasHeadroom() {
+			return false
+		}
+	}
+
+	s.running++
+	s.lastStart = now
+	return true
+}
+
+// acquire reserves a slot for a task which must be proven regardless of the headroom, e.g. a task left in the stack.
+func (s *scheduler) acquire(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running++
+	s.lastStart = now
+}
+
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+}
+
+// hasHeadroom reports whether the free memory is enough for another task, the first task is always allowed.
+func (s *scheduler) hasHeadroom() bool {
+	if s.taskMemoryMB > 0 {
+		available, err := s.availableMemoryMB()
+		if err != nil {
+			log.Warn("failed to measure the available memory", "err", err)
+			return false
+		}
+		if available < s.taskMemoryMB {
+			log.Debug("not enough memory for another task", "available (MB)", available, "task memory (MB)", s.taskMemoryMB)
+			return false
+		}
+	}
+	if s.taskGPUMemoryMB > 0 {
+		available, err := s.availableGPUMemoryMB()
+		if err != nil {
+			log.Warn("failed to measure the available gpu memory", "err", err)
+			return false
+		}
+		if available < s.taskGPUMemoryMB {
+			log.Debug("not enough gpu memory for another task", "available (MB)", available, "task gpu memory (MB)", s.taskGPUMemoryMB)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package prover
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/prover/config"
+)
+
+func TestScheduler(t *testing.T) {
+	cfg := &config.ConcurrencyConfig{MaxChunkTasks: 2, TaskMemoryMB: 1000, TaskStartIntervalSec: 10}
+	s := newScheduler(context.Background(), cfg, message.ProofTypeChunk)
+	available := uint64(500)
+	s.availableMemoryMB = func() (uint64, error) { return available, nil }
+	now := time.Now()
+
+	// the first task is always allowed.
+	assert.True(t, s.tryAcquire(now))
+
+	// the next one once the first had time to allocate its memory, and while there is enough memory left.
+	assert.False(t, s.tryAcquire(now.Add(5*time.Second)))
+	assert.False(t, s.tryAcquire(now.Add(10*time.Second)))
+	available = 1500
+	assert.True(t, s.tryAcquire(now.Add(10*time.Second)))
+
+	// capped per circuit type.
+	assert.False(t, s.tryAcquire(now.Add(time.Minute)))
+	s.release()
+	assert.True(t, s.tryAcquire(now.Add(time.Minute)))
+
+	// a batch prover proves one task at a time by default.
+	s = newScheduler(context.Background(), cfg, message.ProofTypeBatch)
+	assert.Equal(t, 1, s.maxTasks)
+}
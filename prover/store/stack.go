@@ -29,7 +29,13 @@ type ProvingTask struct {
 	Times int `json:"times"`
 }
 
-var bucket = []byte("stack")
+var (
+	bucket = []byte("stack")
+	// proofsBucket caches the proofs of the tasks until they are submitted.
+	proofsBucket = []byte("proofs")
+	// witnessesBucket caches the witnesses of the tasks until they are proven.
+	witnessesBucket = []byte("witnesses")
+)
 
 // NewStack new a Stack object.
 func NewStack(path string) (*Stack, error) {
@@ -38,8 +44,12 @@ func NewStack(path string) (*Stack, error) {
 		return nil, err
 	}
 	err = kvdb.Update(func(tx *bbolt.Tx) error {
-		_, err = tx.CreateBucketIfNotExists(bucket)
-		return err
+		for _, name := range [][]byte{bucket, proofsBucket, witnessesBucket} {
+			if _, err = tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		log.Crit("init stack failed", "error", err)
@@ -82,14 +92,85 @@ func (s *Stack) Peek() (*ProvingTask, error) {
 	return traces, nil
 }
 
-// Delete pops the proving-task on the top of Stack.
+// List returns all the proving-tasks of the Stack, from the bottom to the top.
+func (s *Stack) List() ([]*ProvingTask, error) {
+	var tasks []*ProvingTask
+	err := s.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(_, value []byte) error {
+			task := &ProvingTask{}
+			if err := json.Unmarshal(value, task); err != nil {
+				return err
+			}
+			tasks = append(tasks, task)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Delete pops the proving-task on the top of Stack, with its cached proof and witness.
 func (s *Stack) Delete(taskID string) error {
 	return s.Update(func(tx *bbolt.Tx) error {
-		bu := tx.Bucket(bucket)
-		return bu.Delete([]byte(taskID))
+		for _, name := range [][]byte{bucket, proofsBucket, witnessesBucket} {
+			if err := tx.Bucket(name).Delete([]byte(taskID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutProof caches the proof of a task, so it is submitted again instead of proven again after a restart.
+func (s *Stack) PutProof(taskID string, proof *message.ProofDetail) error {
+	byt, err := json.Marshal(proof)
+	if err != nil {
+		return err
+	}
+	return s.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(proofsBucket).Put([]byte(taskID), byt)
 	})
 }
 
+// GetProof returns the cached proof of a task, nil if there is none.
+func (s *Stack) GetProof(taskID string) (*message.ProofDetail, error) {
+	value, err := s.get(proofsBucket, taskID)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	proof := &message.ProofDetail{}
+	if err = json.Unmarshal(value, proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// PutWitness caches the witness of a task, e.g. the block traces of a chunk.
+func (s *Stack) PutWitness(taskID string, witness []byte) error {
+	return s.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(witnessesBucket).Put([]byte(taskID), witness)
+	})
+}
+
+// GetWitness returns the cached witness of a task, nil if there is none.
+func (s *Stack) GetWitness(taskID string) ([]byte, error) {
+	return s.get(witnessesBucket, taskID)
+}
+
+func (s *Stack) get(name []byte, taskID string) ([]byte, error) {
+	var value []byte
+	err := s.View(func(tx *bbolt.Tx) error {
+		// the value is only valid during the transaction.
+		if v := tx.Bucket(name).Get([]byte(taskID)); v != nil {
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return value, err
+}
+
 // UpdateTimes updates the prover prove times of the proving task.
 func (s *Stack) UpdateTimes(task *ProvingTask, updateTimes int) error {
 	task.Times = updateTimes
@@ -99,9 +180,6 @@ func (s *Stack) UpdateTimes(task *ProvingTask, updateTimes int) error {
 	}
 	key := []byte(task.Task.ID)
 	return s.Update(func(tx *bbolt.Tx) error {
-		bu := tx.Bucket(bucket)
-		c := bu.Cursor()
-		key, _ = c.Last()
-		return bu.Put(key, byt)
+		return tx.Bucket(bucket).Put(key, byt)
 	})
 }
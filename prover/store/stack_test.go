@@ -68,4 +68,53 @@ func TestStack(t *testing.T) {
 	peek2, err := s.Peek()
 	assert.NoError(t, err)
 	assert.Equal(t, 3, peek2.Times)
+
+	// the tasks proven concurrently are updated by task id.
+	err = s.Push(&ProvingTask{Task: &message.TaskMsg{UUID: taskUUID.String(), ID: strconv.Itoa(2)}})
+	assert.NoError(t, err)
+	err = s.UpdateTimes(peek2, 4)
+	assert.NoError(t, err)
+
+	tasks, err := s.List()
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, strconv.Itoa(1), tasks[0].Task.ID)
+	assert.Equal(t, 4, tasks[0].Times)
+	assert.Equal(t, strconv.Itoa(2), tasks[1].Task.ID)
+	assert.Equal(t, 0, tasks[1].Times)
+}
+
+func TestStackCache(t *testing.T) {
+	path, err := os.MkdirTemp("/tmp/", "stack_db_test-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	s, err := NewStack(filepath.Join(path, "test-stack"))
+	assert.NoError(t, err)
+	defer s.Close()
+
+	assert.NoError(t, s.Push(&ProvingTask{Task: &message.TaskMsg{ID: "1"}}))
+
+	proof, err := s.GetProof("1")
+	assert.NoError(t, err)
+	assert.Nil(t, proof)
+	assert.NoError(t, s.PutProof("1", &message.ProofDetail{ID: "1", Type: message.ProofTypeChunk, Status: message.StatusOk}))
+	proof, err = s.GetProof("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", proof.ID)
+	assert.Equal(t, message.ProofTypeChunk, proof.Type)
+
+	assert.NoError(t, s.PutWitness("1", []byte("traces")))
+	witness, err := s.GetWitness("1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("traces"), witness)
+
+	// the cache of a task is dropped with it.
+	assert.NoError(t, s.Delete("1"))
+	proof, err = s.GetProof("1")
+	assert.NoError(t, err)
+	assert.Nil(t, proof)
+	witness, err = s.GetWitness("1")
+	assert.NoError(t, err)
+	assert.Nil(t, witness)
 }
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// AvailableMemoryMB returns the memory available to start new processes, read from /proc/meminfo.
+func AvailableMemoryMB() (uint64, error) {
+	meminfo, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	return parseMemAvailable(bytes.NewReader(meminfo))
+}
+
+// parseMemAvailable parses the MemAvailable line of /proc/meminfo, in kB.
+func parseMemAvailable(r io.Reader) (uint64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemAvailable: %v, err: %w", fields[1], err)
+		}
+		return kb / 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemAvailable not found")
+}
+
+// AvailableGPUMemoryMB returns the free memory of the GPU with the least free memory, queried with nvidia-smi.
+func AvailableGPUMemoryMB(ctx context.Context) (uint64, error) {
+	out, err := exec.CommandContext(ctx, "nvidia-smi", "--query-gpu=memory.free", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return 0, fmt.Errorf("nvidia-smi failed: %w", err)
+	}
+	return parseGPUMemoryFree(out)
+}
+
+// parseGPUMemoryFree parses the free memory of each GPU, one per line in MiB, and returns the least.
+func parseGPUMemoryFree(out []byte) (uint64, error) {
+	var least uint64
+	found := false
+	for _, line := range bytes.Split(bytes.TrimSpace(out), []byte("\n")) {
+		mb, err := strconv.ParseUint(strings.TrimSpace(string(line)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid free GPU memory: %s, err: %w", line, err)
+		}
+		if !found || mb < least {
+			least = mb
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no GPU found")
+	}
+	return least, nil
+}
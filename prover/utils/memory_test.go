@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMemAvailable(t *testing.T) {
+	meminfo := "MemTotal:       65830244 kB\nMemFree:         1270388 kB\nMemAvailable:   40960000 kB\n"
+	mb, err := parseMemAvailable(strings.NewReader(meminfo))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(40000), mb)
+
+	_, err = parseMemAvailable(strings.NewReader("MemTotal:       65830244 kB\n"))
+	assert.Error(t, err)
+}
+
+func TestParseGPUMemoryFree(t *testing.T) {
+	mb, err := parseGPUMemoryFree([]byte("20480\n8192\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8192), mb)
+
+	_, err = parseGPUMemoryFree([]byte(""))
+	assert.Error(t, err)
+}
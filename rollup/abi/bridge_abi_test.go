@@ -13,6 +13,10 @@ func TestEventSignature(t *testing.T) {
 
 	assert.Equal(L1CommitBatchEventSignature, common.HexToHash("2c32d4ae151744d0bf0b9464a3e897a1d17ed2f1af71f7c9a75f12ce0d28238f"))
 	assert.Equal(L1FinalizeBatchEventSignature, common.HexToHash("26ba82f907317eedc97d0cbef23de76a43dd6edb563bdb6e9407645b950a7a2d"))
+	assert.Equal(L1PausedEventSignature, common.HexToHash("62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258"))
+	assert.Equal(L1UnpausedEventSignature, common.HexToHash("5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa"))
+	assert.Equal(L1PausedEventSignature, L1ScrollMessengerABI.Events["Paused"].ID)
+	assert.Equal(L1UnpausedEventSignature, L1ScrollMessengerABI.Events["Unpaused"].ID)
 
 	assert.Equal(L2SentMessageEventSignature, common.HexToHash("104371f3b442861a2a7b82a070afbbaab748bb13757bf47769e170e37809ec1e"))
 	assert.Equal(L2RelayedMessageEventSignature, common.HexToHash("4641df4a962071e12719d8c8c8e5ac7fc4d97b927346a3d7a335b1f7517e133c"))
@@ -99,6 +103,18 @@ func TestPackSetL1BaseFee(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestPackSetL1BaseFeeAndBlobBaseFee(t *testing.T) {
+	assert := assert.New(t)
+
+	l1GasOracleABI, err := L1GasPriceOracleMetaData.GetAbi()
+	assert.NoError(err)
+
+	baseFee := big.NewInt(2333)
+	blobBaseFee := big.NewInt(1)
+	_, err = l1GasOracleABI.Pack("setL1BaseFeeAndBlobBaseFee", baseFee, blobBaseFee)
+	assert.NoError(err)
+}
+
 func TestPackSetL2BaseFee(t *testing.T) {
 	assert := assert.New(t)
 
@@ -109,3 +125,13 @@ func TestPackSetL2BaseFee(t *testing.T) {
 	_, err = l2GasOracleABI.Pack("setL2BaseFee", baseFee)
 	assert.NoError(err)
 }
+
+func TestPackReplayMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	l1MessengerABI, err := L1ScrollMessengerMetaData.GetAbi()
+	assert.NoError(err)
+
+	_, err = l1MessengerABI.Pack("replayMessage", common.Address{}, common.Address{}, big.NewInt(0), big.NewInt(0), []byte{}, uint32(0), common.Address{})
+	assert.NoError(err)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +19,8 @@ import (
 	"scroll-tech/common/version"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/checkpoint"
+	"scroll-tech/rollup/internal/controller/quorum"
 	"scroll-tech/rollup/internal/controller/watcher"
 )
 
@@ -46,6 +49,18 @@ func action(ctx *cli.Context) error {
 	if err != nil {
 		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
 	}
+	if err = utils.ApplyLogConfig(cfg.LogConfig); err != nil {
+		log.Crit("failed to apply log config", "config file", cfgFile, "error", err)
+	}
+
+	// The tunable parameters are reloaded from the config file on SIGHUP.
+	reloader := config.NewReloader(cfgFile, cfg)
+	reloader.Subscribe(ctx.Context, func(cfg *config.Config) {
+		if err := utils.ApplyLogConfig(cfg.LogConfig); err != nil {
+			log.Error("failed to apply reloaded log config", "error", err)
+		}
+	})
+	go reloader.Run(ctx.Context)
 
 	subCtx, cancel := context.WithCancel(ctx.Context)
 	// Init db connection
@@ -60,31 +75,88 @@ func action(ctx *cli.Context) error {
 		}
 	}()
 
-	registry := prometheus.DefaultRegisterer
+	registry := prometheus.WrapRegistererWithPrefix(cfg.MetricsPrefix(), prometheus.DefaultRegisterer)
 	observability.Server(ctx, db)
 	l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
 	if err != nil {
 		log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
 	}
+	observability.RegisterReadinessCheck("l1_rpc", observability.RPCCheck(l1client))
 
-	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations,
+	// Resume from the last L1 block processed before a graceful shutdown, the L1 messages stored so far only
+	// tell the block of the latest message.
+	checkpointer := checkpoint.NewCheckpointer(db, "event_watcher", false /* tracksProposals */)
+	resumed, err := checkpointer.Resume(subCtx)
+	if err != nil {
+		log.Crit("failed to resume from checkpoint", "error", err)
+	}
+	startHeight := cfg.L1Config.StartHeight
+	if resumed != nil && resumed.L1BlockNumber > startHeight {
+		startHeight = resumed.L1BlockNumber
+	}
+
+	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, startHeight, cfg.L1Config.Confirmations,
 		cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
+	// The contract events feed the finalization, they are read through a quorum of l1 endpoints when configured.
+	if cfg.L1Config.Quorum != nil {
+		l1Quorum, err := quorum.NewClient(cfg.L1Config.Quorum, registry)
+		if err != nil {
+			log.Crit("failed to connect l1 quorum endpoints", "config file", cfgFile, "error", err)
+		}
+		defer l1Quorum.Close()
+		l1watcher.UseQuorum(l1Quorum)
+	}
+	if cfg.L1Config.EventConfirmations != nil {
+		l1watcher.UseEventConfirmations(cfg.L1Config.MessageConfirmations(), cfg.L1Config.RollupEventConfirmations())
+	}
 
-	go utils.Loop(subCtx, 10*time.Second, func() {
+	if cfg.HealthCheckConfig != nil && cfg.HealthCheckConfig.MaxL1WatcherLag > 0 {
+		observability.RegisterReadinessCheck("l1_watcher_lag", observability.LagCheck(l1watcher.EventLag, cfg.HealthCheckConfig.MaxL1WatcherLag))
+	}
+
+	// On shutdown stopCtx stops starting loop iterations, subCtx is only canceled once the running iterations are drained.
+	stopCtx, stop := context.WithCancel(subCtx)
+	defer stop()
+	var loops utils.LoopGroup
+
+	// With a subscription, fetching is cheap until new events are notified, contract events are fetched right after.
+	fetchPeriod := 10 * time.Second
+	if cfg.L1Config.WebsocketEndpoint != "" {
+		l1watcher.SubscribeContractEvents(subCtx, cfg.L1Config.WebsocketEndpoint)
+		fetchPeriod = time.Second
+	}
+
+	loops.Loop(stopCtx, fetchPeriod, func() {
 		if loopErr := l1watcher.FetchContractEvent(); loopErr != nil {
 			log.Error("Failed to fetch bridge contract", "err", loopErr)
 		}
 	})
 
+	if cfg.L1Config.MessageAudit != nil {
+		auditor := watcher.NewL1MessageAuditor(subCtx, cfg.L1Config.MessageAudit, l1client, cfg.L1Config.L1MessageQueueAddress, db, registry)
+
+		loops.Loop(stopCtx, time.Minute, auditor.TryAuditL1Messages)
+	}
+
 	log.Info("Start event-watcher successfully")
 
-	// Catch CTRL-C to ensure a graceful shutdown.
+	// Catch CTRL-C and SIGTERM to ensure a graceful shutdown.
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Wait until the interrupt signal is received from an OS signal.
 	<-interrupt
 
+	log.Info("Stopping event-watcher, draining running loops", "timeout", cfg.ShutdownTimeout())
+	stop()
+	if !loops.Wait(cfg.ShutdownTimeout()) {
+		log.Warn("running loops did not return before the shutdown timeout, no checkpoint recorded")
+		return nil
+	}
+
+	if err = checkpointer.Record(subCtx, l1watcher.ProcessedMsgHeight()); err != nil {
+		log.Error("failed to record checkpoint", "error", err)
+	}
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,7 +20,9 @@ import (
 	"scroll-tech/common/version"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/checkpoint"
 	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/sender"
 	"scroll-tech/rollup/internal/controller/watcher"
 	butils "scroll-tech/rollup/internal/utils"
 )
@@ -46,10 +49,22 @@ func init() {
 func action(ctx *cli.Context) error {
 	// Load config file.
 	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
-	cfg, err := config.NewConfig(cfgFile)
+	cfg, err := config.NewGasOracleConfig(cfgFile)
 	if err != nil {
 		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
 	}
+	if err = utils.ApplyLogConfig(cfg.LogConfig); err != nil {
+		log.Crit("failed to apply log config", "config file", cfgFile, "error", err)
+	}
+
+	// The tunable parameters are reloaded from the config file on SIGHUP.
+	reloader := config.NewGasOracleReloader(cfgFile, cfg)
+	reloader.Subscribe(ctx.Context, func(cfg *config.Config) {
+		if err := utils.ApplyLogConfig(cfg.LogConfig); err != nil {
+			log.Error("failed to apply reloaded log config", "error", err)
+		}
+	})
+	go reloader.Run(ctx.Context)
 	subCtx, cancel := context.WithCancel(ctx.Context)
 	// Init db connection
 	db, err := database.InitDB(cfg.DBConfig)
@@ -63,7 +78,8 @@ func action(ctx *cli.Context) error {
 		}
 	}()
 
-	registry := prometheus.DefaultRegisterer
+	// The metrics are namespaced, gas_oracle by default, apart from the ones of the rollup relayer.
+	registry := prometheus.WrapRegistererWithPrefix(cfg.MetricsPrefix(), prometheus.DefaultRegisterer)
 	observability.Server(ctx, db)
 
 	l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
@@ -76,8 +92,21 @@ func action(ctx *cli.Context) error {
 	if err != nil {
 		log.Crit("failed to connect l2 geth", "config file", cfgFile, "error", err)
 	}
+	observability.RegisterReadinessCheck("l1_rpc", observability.RPCCheck(l1client))
+	observability.RegisterReadinessCheck("l2_rpc", observability.RPCCheck(l2client))
+
+	// Resume from the last L1 block header processed before a graceful shutdown.
+	checkpointer := checkpoint.NewCheckpointer(db, "gas_oracle", false /* tracksProposals */)
+	resumed, err := checkpointer.Resume(subCtx)
+	if err != nil {
+		log.Crit("failed to resume from checkpoint", "error", err)
+	}
+	startHeight := cfg.L1Config.StartHeight
+	if resumed != nil && resumed.L1BlockNumber > startHeight {
+		startHeight = resumed.L1BlockNumber
+	}
 
-	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations, cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
+	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, startHeight, cfg.L1Config.Confirmations, cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
 
 	l1relayer, err := relayer.NewLayer1Relayer(ctx.Context, db, cfg.L1Config.RelayerConfig, relayer.ServiceTypeL1GasOracle, registry)
 	if err != nil {
@@ -87,8 +116,33 @@ func action(ctx *cli.Context) error {
 	if err != nil {
 		log.Crit("failed to create new l2 relayer", "config file", cfgFile, "error", err)
 	}
+	sender.RegisterHealthChecks(append(l1relayer.Senders(), l2relayer.Senders()...))
+	reloader.Subscribe(ctx.Context, func(cfg *config.Config) {
+		updateSenders := func(senders []*sender.Sender, senderCfg *config.SenderConfig) {
+			for _, s := range senders {
+				if err := s.UpdateConfig(senderCfg); err != nil {
+					log.Error("failed to update sender config", "service", s.Service(), "name", s.Name(), "error", err)
+				}
+			}
+		}
+		updateSenders(l1relayer.Senders(), cfg.L1Config.RelayerConfig.SenderConfig)
+		updateSenders(l2relayer.Senders(), cfg.L2Config.RelayerConfig.SenderConfig)
+	})
+	if cfg.HealthCheckConfig != nil && cfg.HealthCheckConfig.MaxL1WatcherLag > 0 {
+		observability.RegisterReadinessCheck("l1_watcher_lag", observability.LagCheck(l1watcher.HeaderLag, cfg.HealthCheckConfig.MaxL1WatcherLag))
+	}
+	if cfg.HealthCheckConfig != nil && cfg.HealthCheckConfig.MaxGasOracleRunAgeSec > 0 {
+		maxRunAge := time.Duration(cfg.HealthCheckConfig.MaxGasOracleRunAgeSec) * time.Second
+		observability.RegisterReadinessCheck("l1_gas_oracle_run_age", observability.AgeCheck(l1relayer.GasOracleRunAge, maxRunAge))
+		observability.RegisterReadinessCheck("l2_gas_oracle_run_age", observability.AgeCheck(l2relayer.GasOracleRunAge, maxRunAge))
+	}
+	// On shutdown stopCtx stops starting loop iterations, subCtx is only canceled once the running iterations are drained.
+	stopCtx, stop := context.WithCancel(subCtx)
+	defer stop()
+	var loops utils.LoopGroup
+
 	// Start l1 watcher process
-	go utils.LoopWithContext(subCtx, 10*time.Second, func(ctx context.Context) {
+	loops.LoopWithContext(stopCtx, 10*time.Second, func(ctx context.Context) {
 		// Fetch the latest block number to decrease the delay when fetching gas prices
 		// Use latest block number - 1 to prevent frequent reorg
 		number, loopErr := butils.GetLatestConfirmedBlockNumber(ctx, l1client, rpc.LatestBlockNumber)
@@ -104,19 +158,31 @@ func action(ctx *cli.Context) error {
 	})
 
 	// Start l1relayer process
-	go utils.Loop(subCtx, 10*time.Second, l1relayer.ProcessGasPriceOracle)
-	go utils.Loop(subCtx, 2*time.Second, l2relayer.ProcessGasPriceOracle)
+	loops.Loop(stopCtx, 10*time.Second, l1relayer.ProcessGasPriceOracle)
+	loops.Loop(stopCtx, 2*time.Second, l2relayer.ProcessGasPriceOracle)
 
 	// Finish start all message relayer functions
 	log.Info("Start gas-oracle successfully")
 
-	// Catch CTRL-C to ensure a graceful shutdown.
+	// Catch CTRL-C and SIGTERM to ensure a graceful shutdown.
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Wait until the interrupt signal is received from an OS signal.
 	<-interrupt
 
+	log.Info("Stopping gas-oracle, draining running loops", "timeout", cfg.ShutdownTimeout())
+	stop()
+	if !loops.Wait(cfg.ShutdownTimeout()) {
+		log.Warn("running loops did not return before the shutdown timeout, no checkpoint recorded")
+		return nil
+	}
+
+	l1relayer.Flush()
+	l2relayer.Flush()
+	if err = checkpointer.Record(subCtx, l1watcher.ProcessedBlockHeight()); err != nil {
+		log.Error("failed to record checkpoint", "error", err)
+	}
 	return nil
 }
 
@@ -2,29 +2,161 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
 
 	"scroll-tech/common/database"
 	"scroll-tech/common/observability"
+	"scroll-tech/common/tracing"
+	"scroll-tech/common/types"
 	"scroll-tech/common/utils"
 	"scroll-tech/common/version"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/accounting"
+	"scroll-tech/rollup/internal/controller/api"
+	"scroll-tech/rollup/internal/controller/archiver"
+	"scroll-tech/rollup/internal/controller/checkpoint"
+	"scroll-tech/rollup/internal/controller/leader"
+	"scroll-tech/rollup/internal/controller/quorum"
 	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/sender"
 	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/events"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/route"
 	butils "scroll-tech/rollup/internal/utils"
 )
 
 var app *cli.App
 
+// replaySenderTypeFlag selects the sender whose recorded transactions are replayed.
+var replaySenderTypeFlag = cli.StringFlag{
+	Name:  "sender-type",
+	Usage: "The sender type to replay: commit, finalize, l1-gas-oracle or l2-gas-oracle",
+	Value: "commit",
+}
+
+// skippedL1MessageStatusFlag filters the listed skipped L1 messages by status.
+var skippedL1MessageStatusFlag = cli.StringFlag{
+	Name:  "status",
+	Usage: "The status of the listed skipped l1 messages: all, skipped, replaying, replayed or replay-failed",
+	Value: "all",
+}
+
+// skippedL1MessageLimitFlag limits the number of listed skipped L1 messages.
+var skippedL1MessageLimitFlag = cli.IntFlag{
+	Name:  "limit",
+	Usage: "The maximum number of listed skipped l1 messages",
+	Value: 100,
+}
+
+// queueIndexFlag selects the skipped L1 message to replay.
+var queueIndexFlag = cli.Uint64Flag{
+	Name:     "queue-index",
+	Usage:    "The queue index of the skipped l1 message to replay",
+	Required: true,
+}
+
+// gasLimitFlag overrides the gas limit of the replayed L1 message.
+var gasLimitFlag = cli.Uint64Flag{
+	Name:     "gas-limit",
+	Usage:    "The new gas limit of the replayed l1 message",
+	Required: true,
+}
+
+// backfillFromFlag and backfillToFlag select the block range to backfill, both inclusive.
+var backfillFromFlag = cli.Uint64Flag{
+	Name:     "from",
+	Usage:    "The first block to backfill",
+	Required: true,
+}
+
+var backfillToFlag = cli.Uint64Flag{
+	Name:     "to",
+	Usage:    "The last block to backfill",
+	Required: true,
+}
+
+// backfillChainFlag selects the watcher whose ingestion is replayed.
+var backfillChainFlag = cli.StringFlag{
+	Name:  "chain",
+	Usage: "The chain to backfill: l1 for the contract events, l2 for the blocks",
+	Value: "l2",
+}
+
+// backfillWorkersFlag sets the number of block ranges backfilled in parallel.
+var backfillWorkersFlag = cli.IntFlag{
+	Name:  "workers",
+	Usage: "The number of block ranges backfilled in parallel",
+	Value: 4,
+}
+
+// backfillRateFlag limits the number of block ranges started per second.
+var backfillRateFlag = cli.Float64Flag{
+	Name:  "rate",
+	Usage: "The maximum number of block ranges started per second, 0 for no limit",
+	Value: 10,
+}
+
+// batchAccountingLimitFlag sets the number of listed batch accountings.
+var batchAccountingLimitFlag = cli.IntFlag{
+	Name:  "limit",
+	Usage: "The maximum number of listed batch accountings, the latest batches first",
+	Value: 100,
+}
+
+// batchIndexFlag selects the inspected or overridden batch.
+var batchIndexFlag = cli.Uint64Flag{
+	Name:     "index",
+	Usage:    "The index of the batch",
+	Required: true,
+}
+
+// batchOverrideReasonFlag and batchOverrideOperatorFlag are recorded in the audit of a batch override.
+var batchOverrideReasonFlag = cli.StringFlag{
+	Name:     "reason",
+	Usage:    "The reason of the override, recorded in the batch_override table",
+	Required: true,
+}
+
+var batchOverrideOperatorFlag = cli.StringFlag{
+	Name:    "operator",
+	Usage:   "The operator applying the override, recorded in the batch_override table",
+	EnvVars: []string{"USER"},
+}
+
+// batchSkipStageFlag selects the stage of the skipped batch.
+var batchSkipStageFlag = cli.StringFlag{
+	Name:  "stage",
+	Usage: "The stage completed outside of the relayer: commit or finalize",
+	Value: "commit",
+}
+
+// batchSkipTxHashFlag is the layer1 transaction which completed the stage of the skipped batch.
+var batchSkipTxHashFlag = cli.StringFlag{
+	Name:     "tx-hash",
+	Usage:    "The layer1 transaction which committed or finalized the batch outside of the relayer",
+	Required: true,
+}
+
 func init() {
 	// Set up rollup-relayer app info.
 	app = cli.NewApp()
@@ -34,7 +166,68 @@ func init() {
 	app.Version = version.Version
 	app.Flags = append(app.Flags, utils.CommonFlags...)
 	app.Flags = append(app.Flags, utils.RollupRelayerFlags...)
-	app.Commands = []*cli.Command{}
+	app.Commands = []*cli.Command{
+		{
+			Name:   "replay",
+			Usage:  "Broadcast the transactions recorded by a dry-run sender",
+			Action: replayAction,
+			Flags:  []cli.Flag{&replaySenderTypeFlag},
+		},
+		{
+			Name:   "skipped-messages",
+			Usage:  "List the l1 messages skipped by l2geth",
+			Action: skippedMessagesAction,
+			Flags:  []cli.Flag{&skippedL1MessageStatusFlag, &skippedL1MessageLimitFlag},
+		},
+		{
+			Name:   "replay-skipped-message",
+			Usage:  "Replay a skipped l1 message with a new gas limit through the L1ScrollMessenger",
+			Action: replaySkippedMessageAction,
+			Flags:  []cli.Flag{&queueIndexFlag, &gasLimitFlag},
+		},
+		{
+			Name:   "backfill",
+			Usage:  "Replay the watcher ingestion for a historical block range, the stored rows are kept",
+			Action: backfillAction,
+			Flags:  []cli.Flag{&backfillFromFlag, &backfillToFlag, &backfillChainFlag, &backfillWorkersFlag, &backfillRateFlag},
+		},
+		{
+			Name:   "batch-accounting",
+			Usage:  "List the l1 fees paid and the l2 fees collected for the latest accounted batches",
+			Action: batchAccountingAction,
+			Flags:  []cli.Flag{&batchAccountingLimitFlag},
+		},
+		{
+			Name:  "batch",
+			Usage: "Inspect a batch or override its statuses, every override is audited with its reason",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "inspect",
+					Usage:  "Print the statuses, chunks, l1 transactions and overrides of a batch",
+					Action: batchInspectAction,
+					Flags:  []cli.Flag{&batchIndexFlag},
+				},
+				{
+					Name:   "recommit",
+					Usage:  "Commit a committing or commit failed batch again, along with the later uncommitted batches",
+					Action: batchRecommitAction,
+					Flags:  []cli.Flag{&batchIndexFlag, &batchOverrideReasonFlag, &batchOverrideOperatorFlag},
+				},
+				{
+					Name:   "invalidate-proof",
+					Usage:  "Drop the proof of a batch to prove it again",
+					Action: batchInvalidateProofAction,
+					Flags:  []cli.Flag{&batchIndexFlag, &batchOverrideReasonFlag, &batchOverrideOperatorFlag},
+				},
+				{
+					Name:   "skip",
+					Usage:  "Mark a batch committed or finalized by a layer1 transaction sent outside of the relayer",
+					Action: batchSkipAction,
+					Flags:  []cli.Flag{&batchIndexFlag, &batchSkipStageFlag, &batchSkipTxHashFlag, &batchOverrideReasonFlag, &batchOverrideOperatorFlag},
+				},
+			},
+		},
+	}
 	app.Before = func(ctx *cli.Context) error {
 		return utils.LogSetup(ctx)
 	}
@@ -49,6 +242,33 @@ func action(ctx *cli.Context) error {
 	if err != nil {
 		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
 	}
+	if err = utils.ApplyLogConfig(cfg.LogConfig); err != nil {
+		log.Crit("failed to apply log config", "config file", cfgFile, "error", err)
+	}
+
+	// The tunable parameters are reloaded from the config file on SIGHUP.
+	reloader := config.NewReloader(cfgFile, cfg)
+	reloader.Subscribe(ctx.Context, func(cfg *config.Config) {
+		if err := utils.ApplyLogConfig(cfg.LogConfig); err != nil {
+			log.Error("failed to apply reloaded log config", "error", err)
+		}
+	})
+	go reloader.Run(ctx.Context)
+	if cfg.DryRun {
+		log.Warn("rollup-relayer runs in dry-run mode, L1 transactions are recorded but not broadcast, see the replay command")
+	}
+
+	shutdownTracing, err := tracing.Init(cfg.TracingConfig, app.Name)
+	if err != nil {
+		log.Crit("failed to init tracing", "err", err)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Warn("failed to flush the tracing spans", "err", err)
+		}
+	}()
 
 	subCtx, cancel := context.WithCancel(ctx.Context)
 	// Init db connection
@@ -63,19 +283,27 @@ func action(ctx *cli.Context) error {
 		}
 	}()
 
-	registry := prometheus.DefaultRegisterer
+	// The admin api listings are read from the replica if configured.
+	readDB, err := database.InitReadDB(subCtx, cfg.DBConfig, db)
+	if err != nil {
+		log.Crit("failed to init read db connection", "err", err)
+	}
+
+	registry := prometheus.WrapRegistererWithPrefix(cfg.MetricsPrefix(), prometheus.DefaultRegisterer)
 	observability.Server(ctx, db)
 
+	// The lifecycle events of the chunks and batches are published on the bus for the subsystems subscribed to it.
+	eventBus := events.NewBus(registry)
+
 	// Init l2geth connection
-	l2client, err := ethclient.Dial(cfg.L2Config.Endpoint)
+	l2rpcClient, err := tracing.DialRPC(subCtx, cfg.L2Config.Endpoint)
 	if err != nil {
 		log.Crit("failed to connect l2 geth", "config file", cfgFile, "error", err)
 	}
-
-	initGenesis := ctx.Bool(utils.ImportGenesisFlag.Name)
-	l2relayer, err := relayer.NewLayer2Relayer(ctx.Context, l2client, db, cfg.L2Config.RelayerConfig, initGenesis, relayer.ServiceTypeL2RollupRelayer, registry)
-	if err != nil {
-		log.Crit("failed to create l2 relayer", "config file", cfgFile, "error", err)
+	l2client := ethclient.NewClient(l2rpcClient)
+	observability.RegisterReadinessCheck("l2_rpc", observability.RPCCheck(l2client))
+	if readDB != db {
+		observability.RegisterReadinessCheck("read_db", observability.DBCheck(readDB))
 	}
 
 	genesisPath := ctx.String(utils.Genesis.Name)
@@ -84,49 +312,547 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to read genesis", "genesis file", genesisPath, "error", err)
 	}
 
-	chunkProposer := watcher.NewChunkProposer(subCtx, cfg.L2Config.ChunkProposerConfig, genesis.Config, db, registry)
-	if err != nil {
-		log.Crit("failed to create chunkProposer", "config file", cfgFile, "error", err)
+	initGenesis := ctx.Bool(utils.ImportGenesisFlag.Name)
+
+	// The committed batch hashes are verified by a quorum of l1 endpoints before finalizing.
+	var l1Quorum *quorum.Client
+	if cfg.L1Config.Quorum != nil {
+		l1Quorum, err = quorum.NewClient(cfg.L1Config.Quorum, registry)
+		if err != nil {
+			log.Crit("failed to connect l1 quorum endpoints", "config file", cfgFile, "error", err)
+		}
+		defer l1Quorum.Close()
 	}
 
-	batchProposer := watcher.NewBatchProposer(subCtx, cfg.L2Config.BatchProposerConfig, genesis.Config, db, registry)
-	if err != nil {
-		log.Crit("failed to create batchProposer", "config file", cfgFile, "error", err)
+	// The fees of the commit and finalize transactions are read from the l1 receipts.
+	var l1client *ethclient.Client
+	if cfg.L2Config.BatchAccountingConfig != nil {
+		l1client, err = ethclient.Dial(cfg.L1Config.Endpoint)
+		if err != nil {
+			log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
+		}
+		defer l1client.Close()
+		observability.RegisterReadinessCheck("l1_rpc", observability.RPCCheck(l1client))
 	}
 
-	l2watcher := watcher.NewL2WatcherClient(subCtx, l2client, cfg.L2Config.Confirmations, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot, db, registry)
+	// On shutdown stopCtx stops starting loop iterations, subCtx is only canceled once the running iterations are
+	// drained so that a proposer does not leave a half-written chunk or batch behind.
+	stopCtx, stop := context.WithCancel(subCtx)
+	defer stop()
+	var (
+		loops          utils.LoopGroup
+		leadingRelayer atomic.Pointer[relayer.Layer2Relayer]
+		checkpointer   = checkpoint.NewCheckpointer(db, "rollup_relayer", true /* tracksProposals */)
+	)
 
-	// Watcher loop to fetch missing blocks
-	go utils.LoopWithContext(subCtx, 2*time.Second, func(ctx context.Context) {
-		number, loopErr := butils.GetLatestConfirmedBlockNumber(ctx, l2client, cfg.L2Config.Confirmations)
-		if loopErr != nil {
-			log.Error("failed to get block number", "err", loopErr)
-			return
+	// start runs every rollup relayer function until runCtx is done, the senders resume from the persisted
+	// pending transactions and nonce reservations, so a newly elected leader takes over without nonce conflicts.
+	start := func(runCtx context.Context) {
+		// a new leadership term starts with the reloaded tunable parameters.
+		cfg := reloader.Current()
+		if _, err := checkpointer.Resume(runCtx); err != nil {
+			log.Crit("failed to resume from checkpoint", "error", err)
 		}
-		l2watcher.TryFetchRunningMissingBlocks(number)
-	})
 
-	go utils.Loop(subCtx, 2*time.Second, chunkProposer.TryProposeChunk)
+		l2relayer, err := relayer.NewLayer2Relayer(runCtx, l2client, db, cfg.L2Config.RelayerConfig, initGenesis, relayer.ServiceTypeL2RollupRelayer, registry)
+		if err != nil {
+			log.Crit("failed to create l2 relayer", "config file", cfgFile, "error", err)
+		}
+		if l1Quorum != nil {
+			l2relayer.UseL1Quorum(l1Quorum)
+		}
+		var l1PauseMonitor *relayer.L1PauseMonitor
+		if cfg.L2Config.RelayerConfig.L1PauseMonitor != nil {
+			l1PauseMonitor = relayer.NewL1PauseMonitor(runCtx, cfg.L2Config.RelayerConfig.L1PauseMonitor, l1client,
+				cfg.L2Config.RelayerConfig.RollupContractAddress, cfg.L1Config.L1ScrollMessengerAddress, registry)
+			l2relayer.UseL1PauseMonitor(l1PauseMonitor)
+		}
+		l2relayer.UseEventBus(eventBus)
+		leadingRelayer.Store(l2relayer)
+		sender.RegisterHealthChecks(l2relayer.Senders())
+
+		// The loops end with the leadership or on shutdown.
+		loopCtx, loopCancel := context.WithCancel(runCtx)
+		go func() {
+			defer loopCancel()
+			select {
+			case <-runCtx.Done():
+			case <-stopCtx.Done():
+			}
+		}()
+
+		chunkProposer := watcher.NewChunkProposer(runCtx, cfg.L2Config.ChunkProposerConfig, genesis.Config, db, registry)
+		if err != nil {
+			log.Crit("failed to create chunkProposer", "config file", cfgFile, "error", err)
+		}
+
+		batchProposer := watcher.NewBatchProposer(runCtx, cfg.L2Config.BatchProposerConfig, genesis.Config, db, registry)
+		if err != nil {
+			log.Crit("failed to create batchProposer", "config file", cfgFile, "error", err)
+		}
+
+		// The proposals skipping too many l1 messages are approved on the admin api.
+		skipGuard := watcher.NewL1MessageSkipGuard()
+		chunkProposer.SetL1MessageSkipGuard(skipGuard)
+		batchProposer.SetL1MessageSkipGuard(skipGuard)
+		chunkProposer.SetEventBus(eventBus)
+
+		l2watcher := watcher.NewL2WatcherClient(runCtx, l2client, cfg.L2Config.Confirmations, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot, db, registry)
+		if cfg.L2Config.ComputeWithdrawRoot {
+			l2watcher.EnableWithdrawTrie()
+			batchProposer.SetWithdrawRootVerifier(l2watcher)
+		}
+		if err = l2watcher.VerifyCheckpoint(); err != nil {
+			log.Crit("failed to verify l2 watcher checkpoint", "error", err)
+		}
+		if cfg.HealthCheckConfig != nil && cfg.HealthCheckConfig.MaxL2WatcherLag > 0 {
+			observability.RegisterReadinessCheck("l2_watcher_lag", observability.LagCheck(l2watcher.Lag, cfg.HealthCheckConfig.MaxL2WatcherLag))
+		}
+
+		if cfg.L2Config.FetchSkippedL1Messages {
+			skippedL1MessageWatcher := watcher.NewSkippedL1MessageWatcher(runCtx, l2rpcClient, db, registry)
+
+			loops.Loop(loopCtx, 10*time.Second, skippedL1MessageWatcher.TryFetchSkippedL1Messages)
+		}
+
+		// Watcher loop to fetch missing blocks
+		loops.LoopWithContext(loopCtx, 2*time.Second, func(ctx context.Context) {
+			number, loopErr := butils.GetLatestConfirmedBlockNumber(ctx, l2client, cfg.L2Config.Confirmations)
+			if loopErr != nil {
+				log.Error("failed to get block number", "err", loopErr)
+				return
+			}
+			l2watcher.TryFetchRunningMissingBlocks(number)
+		})
+
+		// Chunks are proposed as soon as the watcher stores new blocks, the ticker is a fallback.
+		loops.LoopWithTrigger(loopCtx, 2*time.Second, l2watcher.BlocksStored(), func(context.Context) {
+			chunkProposer.TryProposeChunk()
+		})
+
+		loops.Loop(loopCtx, 10*time.Second, batchProposer.TryProposeBatch)
+
+		loops.Loop(loopCtx, 2*time.Second, l2relayer.ProcessPendingBatches)
+
+		loops.Loop(loopCtx, 15*time.Second, l2relayer.ProcessCommittedBatches)
 
-	go utils.Loop(subCtx, 10*time.Second, batchProposer.TryProposeBatch)
+		if cfg.L2Config.BatchAccountingConfig != nil {
+			accountant := accounting.NewAccountant(runCtx, cfg.L2Config.BatchAccountingConfig, l1client, l2client, db, registry)
 
-	go utils.Loop(subCtx, 2*time.Second, l2relayer.ProcessPendingBatches)
+			loops.Loop(loopCtx, 30*time.Second, accountant.TryAccountBatches)
+		}
+
+		if cfg.L2Config.FinalizationMonitorConfig != nil {
+			finalizationMonitor := relayer.NewFinalizationMonitor(runCtx, cfg.L2Config.FinalizationMonitorConfig, l1client, db, registry)
+
+			loops.Loop(loopCtx, 30*time.Second, finalizationMonitor.TryMonitorFinalization)
+		}
+
+		if cfg.L2Config.RelayerConfig.DAVerifier != nil {
+			daVerifier := relayer.NewDAVerifier(runCtx, cfg.L2Config.RelayerConfig.DAVerifier, l1client, db, registry)
+
+			loops.Loop(loopCtx, 15*time.Second, daVerifier.TryVerifyCommittedBatches)
+		}
+
+		if l1PauseMonitor != nil {
+			loops.Loop(loopCtx, 12*time.Second, l1PauseMonitor.TryMonitorPause)
+		}
 
-	go utils.Loop(subCtx, 15*time.Second, l2relayer.ProcessCommittedBatches)
+		// The partitions are created ahead even if the rows are not archived.
+		tableArchiver := archiver.NewArchiver(runCtx, cfg.ArchiverConfig, db, registry)
+
+		loops.Loop(loopCtx, time.Hour, tableArchiver.TryArchive)
+
+		reloader.Subscribe(loopCtx, func(cfg *config.Config) {
+			chunkProposer.UpdateConfig(cfg.L2Config.ChunkProposerConfig)
+			batchProposer.UpdateConfig(cfg.L2Config.BatchProposerConfig)
+			for _, s := range l2relayer.Senders() {
+				if err := s.UpdateConfig(cfg.L2Config.RelayerConfig.SenderConfig); err != nil {
+					log.Error("failed to update sender config", "service", s.Service(), "name", s.Name(), "error", err)
+				}
+			}
+		})
+
+		if cfg.AdminAPIConfig != nil {
+			startAdminServer(cfg.AdminAPIConfig, l2relayer.Senders(), skipGuard, readDB)
+		}
+	}
+
+	if cfg.LeaderElectionConfig != nil {
+		elector := leader.NewElector(db, cfg.LeaderElectionConfig, "rollup_relayer", registry)
+		go func() {
+			if electErr := elector.Run(subCtx, start); electErr != nil {
+				log.Crit("rollup-relayer leader election failed", "error", electErr)
+			}
+		}()
+	} else {
+		start(subCtx)
+	}
 
 	// Finish start all rollup relayer functions.
 	log.Info("Start rollup-relayer successfully")
 
-	// Catch CTRL-C to ensure a graceful shutdown.
+	// Catch CTRL-C and SIGTERM to ensure a graceful shutdown.
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Wait until the interrupt signal is received from an OS signal.
 	<-interrupt
 
+	log.Info("Stopping rollup-relayer, draining running loops", "timeout", cfg.ShutdownTimeout())
+	stop()
+	if !loops.Wait(cfg.ShutdownTimeout()) {
+		log.Warn("running loops did not return before the shutdown timeout, no checkpoint recorded")
+		return nil
+	}
+
+	// Only the leader runs the relayer functions and records the checkpoint.
+	if l2relayer := leadingRelayer.Load(); l2relayer != nil {
+		l2relayer.Flush()
+		if err = checkpointer.Record(subCtx, 0); err != nil {
+			log.Error("failed to record checkpoint", "error", err)
+		}
+	}
+	return nil
+}
+
+// startAdminServer starts the sender admin api server in the background, the skipped l1 messages are listed from readDB.
+func startAdminServer(cfg *config.AdminAPIConfig, senders []*sender.Sender, skipGuard *watcher.L1MessageSkipGuard, readDB *gorm.DB) {
+	router := gin.New()
+	route.AdminRoute(router, cfg, api.NewAdminController(senders, skipGuard, readDB))
+	server := &http.Server{
+		Addr:              cfg.ListenAddress,
+		Handler:           router,
+		ReadHeaderTimeout: time.Minute,
+	}
+	log.Info("Starting admin api server", "address", cfg.ListenAddress)
+
+	go func() {
+		if runServerErr := server.ListenAndServe(); runServerErr != nil && !errors.Is(runServerErr, http.ErrServerClosed) {
+			log.Crit("run admin api http server failure", "error", runServerErr)
+		}
+	}()
+}
+
+func replayAction(ctx *cli.Context) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	// The L2 relayer submits batches and L2 gas prices to L1, the L1 relayer submits L1 gas prices to L2.
+	var (
+		senderType types.SenderType
+		endpoint   string
+	)
+	switch ctx.String(replaySenderTypeFlag.Name) {
+	case "commit":
+		senderType, endpoint = types.SenderTypeCommitBatch, cfg.L2Config.RelayerConfig.SenderConfig.Endpoint
+	case "finalize":
+		senderType, endpoint = types.SenderTypeFinalizeBatch, cfg.L2Config.RelayerConfig.SenderConfig.Endpoint
+	case "l1-gas-oracle":
+		senderType, endpoint = types.SenderTypeL1GasOracle, cfg.L1Config.RelayerConfig.SenderConfig.Endpoint
+	case "l2-gas-oracle":
+		senderType, endpoint = types.SenderTypeL2GasOracle, cfg.L2Config.RelayerConfig.SenderConfig.Endpoint
+	default:
+		return fmt.Errorf("unknown sender type: %v", ctx.String(replaySenderTypeFlag.Name))
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	client, err := ethclient.Dial(endpoint)
+	if err != nil {
+		log.Crit("failed to connect sender endpoint", "config file", cfgFile, "error", err)
+	}
+
+	replayed, err := sender.ReplayRecordedTransactions(ctx.Context, client, db, senderType)
+	if err != nil {
+		return fmt.Errorf("failed to replay recorded transactions, replayed: %d, err: %w", replayed, err)
+	}
+	log.Info("replayed recorded transactions", "sender type", senderType, "replayed", replayed)
 	return nil
 }
 
+func skippedMessagesAction(ctx *cli.Context) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	var status types.SkippedL1MessageStatus
+	switch ctx.String(skippedL1MessageStatusFlag.Name) {
+	case "all":
+		status = types.SkippedL1MessageStatusUndefined
+	case "skipped":
+		status = types.SkippedL1MessageStatusSkipped
+	case "replaying":
+		status = types.SkippedL1MessageStatusReplaying
+	case "replayed":
+		status = types.SkippedL1MessageStatusReplayed
+	case "replay-failed":
+		status = types.SkippedL1MessageStatusReplayFailed
+	default:
+		return fmt.Errorf("unknown skipped l1 message status: %v", ctx.String(skippedL1MessageStatusFlag.Name))
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	messages, err := orm.NewSkippedL1Message(db).GetSkippedL1Messages(ctx.Context, status, 0, ctx.Int(skippedL1MessageLimitFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to get skipped l1 messages, err: %w", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(messages)
+}
+
+func batchAccountingAction(ctx *cli.Context) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	accountings, err := orm.NewBatchAccounting(db).GetBatchAccountings(ctx.Context, ctx.Int(batchAccountingLimitFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to get batch accountings, err: %w", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(accountings)
+}
+
+func replaySkippedMessageAction(ctx *cli.Context) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	gasLimit := ctx.Uint64(gasLimitFlag.Name)
+	if gasLimit == 0 || gasLimit > math.MaxUint32 {
+		return fmt.Errorf("invalid gas limit: %v", gasLimit)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	// The replayMessage transaction is sent to layer 1 by the sender of the L2 relayer config.
+	relayerCfg := cfg.L2Config.RelayerConfig
+	l1client, err := ethclient.Dial(relayerCfg.SenderConfig.Endpoint)
+	if err != nil {
+		log.Crit("failed to connect sender endpoint", "config file", cfgFile, "error", err)
+	}
+
+	replayer, err := relayer.NewSkippedL1MessageReplayer(ctx.Context, l1client, db, relayerCfg, cfg.L1Config.L1ScrollMessengerAddress, cfg.L1Config.L1MessageQueueAddress, prometheus.DefaultRegisterer)
+	if err != nil {
+		return fmt.Errorf("failed to create skipped l1 message replayer, err: %w", err)
+	}
+	defer replayer.Stop()
+
+	queueIndex := ctx.Uint64(queueIndexFlag.Name)
+	txHash, err := replayer.Replay(ctx.Context, queueIndex, uint32(gasLimit))
+	if err != nil {
+		return fmt.Errorf("failed to replay skipped l1 message, queue index: %d, err: %w", queueIndex, err)
+	}
+	log.Info("replayed skipped l1 message", "queue index", queueIndex, "gas limit", gasLimit, "tx hash", txHash.Hex())
+	return nil
+}
+
+func backfillAction(ctx *cli.Context) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	backfillCfg := watcher.BackfillConfig{
+		Workers:         ctx.Int(backfillWorkersFlag.Name),
+		RangesPerSecond: ctx.Float64(backfillRateFlag.Name),
+	}
+	from, to := ctx.Uint64(backfillFromFlag.Name), ctx.Uint64(backfillToFlag.Name)
+
+	// The metrics of the backfill are not exported, it may run next to the relayer.
+	registry := prometheus.NewRegistry()
+	switch ctx.String(backfillChainFlag.Name) {
+	case "l1":
+		l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+		if err != nil {
+			log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
+		}
+		l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations,
+			cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
+		if cfg.L1Config.Quorum != nil {
+			l1Quorum, err := quorum.NewClient(cfg.L1Config.Quorum, registry)
+			if err != nil {
+				log.Crit("failed to connect l1 quorum endpoints", "config file", cfgFile, "error", err)
+			}
+			defer l1Quorum.Close()
+			l1watcher.UseQuorum(l1Quorum)
+		}
+		if err = l1watcher.Backfill(ctx.Context, backfillCfg, from, to); err != nil {
+			return err
+		}
+	case "l2":
+		l2client, err := ethclient.Dial(cfg.L2Config.Endpoint)
+		if err != nil {
+			log.Crit("failed to connect l2 geth", "config file", cfgFile, "error", err)
+		}
+		l2watcher := watcher.NewL2WatcherClient(ctx.Context, l2client, cfg.L2Config.Confirmations, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot, db, registry)
+		if err = l2watcher.VerifyCheckpoint(); err != nil {
+			return fmt.Errorf("failed to verify l2 watcher checkpoint, err: %w", err)
+		}
+		if err = l2watcher.Backfill(ctx.Context, backfillCfg, from, to); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown backfill chain: %v", ctx.String(backfillChainFlag.Name))
+	}
+	log.Info("backfilled blocks", "chain", ctx.String(backfillChainFlag.Name), "from", from, "to", to)
+	return nil
+}
+
+func batchInspectAction(ctx *cli.Context) error {
+	return withBatchOperator(ctx, false, func(operator *relayer.BatchOperator) error {
+		inspection, err := operator.Inspect(ctx.Context, ctx.Uint64(batchIndexFlag.Name))
+		if err != nil {
+			return fmt.Errorf("failed to inspect batch, err: %w", err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inspection)
+	})
+}
+
+func batchRecommitAction(ctx *cli.Context) error {
+	return withBatchOperator(ctx, false, func(operator *relayer.BatchOperator) error {
+		index := ctx.Uint64(batchIndexFlag.Name)
+		reset, err := operator.Recommit(ctx.Context, index, ctx.String(batchOverrideOperatorFlag.Name), ctx.String(batchOverrideReasonFlag.Name))
+		if err != nil {
+			return fmt.Errorf("failed to recommit batch, index: %d, err: %w", index, err)
+		}
+		log.Info("reset batches to be committed again", "index", index, "reset", reset)
+		return nil
+	})
+}
+
+func batchInvalidateProofAction(ctx *cli.Context) error {
+	return withBatchOperator(ctx, false, func(operator *relayer.BatchOperator) error {
+		index := ctx.Uint64(batchIndexFlag.Name)
+		if err := operator.InvalidateProof(ctx.Context, index, ctx.String(batchOverrideOperatorFlag.Name), ctx.String(batchOverrideReasonFlag.Name)); err != nil {
+			return fmt.Errorf("failed to invalidate batch proof, index: %d, err: %w", index, err)
+		}
+		log.Info("invalidated batch proof", "index", index)
+		return nil
+	})
+}
+
+func batchSkipAction(ctx *cli.Context) error {
+	stage := relayer.SkipStage(ctx.String(batchSkipStageFlag.Name))
+	txHash := ctx.String(batchSkipTxHashFlag.Name)
+	if !isHexHash(txHash) {
+		return fmt.Errorf("invalid tx hash: %v", txHash)
+	}
+	return withBatchOperator(ctx, true, func(operator *relayer.BatchOperator) error {
+		index := ctx.Uint64(batchIndexFlag.Name)
+		if err := operator.Skip(ctx.Context, index, stage, common.HexToHash(txHash), ctx.String(batchOverrideOperatorFlag.Name), ctx.String(batchOverrideReasonFlag.Name)); err != nil {
+			return fmt.Errorf("failed to skip batch, index: %d, stage: %s, err: %w", index, stage, err)
+		}
+		log.Info("skipped batch", "index", index, "stage", stage, "tx hash", txHash)
+		return nil
+	})
+}
+
+// withBatchOperator runs fn with a BatchOperator of the config file, connected to layer1 if withL1Client is set.
+func withBatchOperator(ctx *cli.Context, withL1Client bool, fn func(operator *relayer.BatchOperator) error) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	// The batches are committed and finalized to layer 1 by the sender of the L2 relayer config.
+	relayerCfg := cfg.L2Config.RelayerConfig
+	if !withL1Client {
+		return fn(relayer.NewBatchOperator(db, nil, relayerCfg.RollupContractAddress))
+	}
+	l1client, err := ethclient.Dial(relayerCfg.SenderConfig.Endpoint)
+	if err != nil {
+		log.Crit("failed to connect sender endpoint", "config file", cfgFile, "error", err)
+	}
+	defer l1client.Close()
+	return fn(relayer.NewBatchOperator(db, l1client, relayerCfg.RollupContractAddress))
+}
+
+func isHexHash(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == common.HashLength
+}
+
 // Run rollup relayer cmd instance.
 func Run() {
 	if err := app.Run(os.Args); err != nil {
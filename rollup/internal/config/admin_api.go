@@ -0,0 +1,9 @@
+package config
+
+// AdminAPIConfig loads the admin http api configuration items.
+type AdminAPIConfig struct {
+	// The address the admin api listens on, e.g. 127.0.0.1:8590.
+	ListenAddress string `json:"listen_address"`
+	// The bearer token every admin api request must carry.
+	AuthToken string `json:"auth_token"`
+}
@@ -0,0 +1,11 @@
+package config
+
+// ArchiverConfig loads the archiver configuration items. The rows of pending_transaction and l1_message which no
+// longer change are moved to their archive table once older than the retention window, then the emptied monthly
+// partitions are dropped.
+type ArchiverConfig struct {
+	// The number of days the final transactions and the finalized messages are kept before being archived.
+	RetentionDays uint64 `json:"retention_days"`
+	// The maximum number of rows moved per statement.
+	BatchSize int `json:"batch_size"`
+}
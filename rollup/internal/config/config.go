@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"scroll-tech/common/database"
+	"scroll-tech/common/tracing"
+	"scroll-tech/common/utils"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core"
 )
 
@@ -17,15 +21,183 @@ type Config struct {
 	L1Config *L1Config        `json:"l1_config"`
 	L2Config *L2Config        `json:"l2_config"`
 	DBConfig *database.Config `json:"db_config"`
+	// The admin api config, the admin api is disabled when nil.
+	AdminAPIConfig *AdminAPIConfig `json:"admin_api_config,omitempty"`
+	// The leader election config, every instance runs as leader when nil.
+	LeaderElectionConfig *LeaderElectionConfig `json:"leader_election_config,omitempty"`
+	// The archiver config, the rows are never archived when nil, the monthly partitions are created ahead regardless.
+	ArchiverConfig *ArchiverConfig `json:"archiver_config,omitempty"`
+	// The tracing config, the spans of the rollup flows are exported to an OTLP collector, tracing is disabled when nil.
+	TracingConfig *tracing.Config `json:"tracing_config,omitempty"`
+	// The readiness probe thresholds, the watcher lag is not part of the readiness probe when nil.
+	HealthCheckConfig *HealthCheckConfig `json:"health_check_config,omitempty"`
+	// The log levels, globally and per module, the verbosity flag applies when nil.
+	LogConfig *utils.LogConfig `json:"log_config,omitempty"`
+	// Whether to run in shadow mode: chunks and batches are proposed and proofs and fees are handled as usual,
+	// but every sender records its transactions in pending_transaction instead of broadcasting them.
+	DryRun bool `json:"dry_run,omitempty"`
+	// The prefix of the names of the metrics of the service, the metrics are not prefixed when empty. The gas_oracle
+	// binary defaults to defaultGasOracleMetricsNamespace.
+	MetricsNamespace string `json:"metrics_namespace,omitempty"`
+	// The time to wait on shutdown for the running loop iterations to return before giving up on the checkpoint,
+	// defaultShutdownTimeoutSec when 0.
+	ShutdownTimeoutSec uint64 `json:"shutdown_timeout_sec,omitempty"`
+}
+
+// defaultShutdownTimeoutSec is the shutdown timeout used when shutdown_timeout_sec is not configured.
+const defaultShutdownTimeoutSec = 30
+
+// ShutdownTimeout returns the time to wait on shutdown for the running loop iterations to return.
+func (c *Config) ShutdownTimeout() time.Duration {
+	if c.ShutdownTimeoutSec == 0 {
+		return defaultShutdownTimeoutSec * time.Second
+	}
+	return time.Duration(c.ShutdownTimeoutSec) * time.Second
+}
+
+// MetricsPrefix returns the prefix of the metric names of the service, empty when no metrics namespace is configured.
+func (c *Config) MetricsPrefix() string {
+	if c.MetricsNamespace == "" {
+		return ""
+	}
+	return c.MetricsNamespace + "_"
 }
 
 func (c *Config) validate() error {
 	if maxChunkPerBatch := c.L2Config.BatchProposerConfig.MaxChunkNumPerBatch; maxChunkPerBatch <= 0 {
 		return fmt.Errorf("Invalid max_chunk_num_per_batch configuration: %v", maxChunkPerBatch)
 	}
+	if dynamicSizing := c.L2Config.ChunkProposerConfig.DynamicSizing; dynamicSizing != nil {
+		if err := dynamicSizing.validate(); err != nil {
+			return fmt.Errorf("invalid chunk proposer dynamic_sizing configuration: %w", err)
+		}
+	}
+	if dynamicSizing := c.L2Config.BatchProposerConfig.DynamicSizing; dynamicSizing != nil {
+		if err := dynamicSizing.validate(); err != nil {
+			return fmt.Errorf("invalid batch proposer dynamic_sizing configuration: %w", err)
+		}
+	}
+	if accounting := c.L2Config.BatchAccountingConfig; accounting != nil {
+		if accounting.MaxBatchesPerRun <= 0 {
+			return fmt.Errorf("invalid batch accounting max_batches_per_run configuration: %v", accounting.MaxBatchesPerRun)
+		}
+		if c.L1Config == nil || c.L1Config.Endpoint == "" {
+			return errors.New("batch accounting requires the l1 endpoint")
+		}
+	}
+	if monitor := c.L2Config.FinalizationMonitorConfig; monitor != nil {
+		if err := monitor.validate(); err != nil {
+			return fmt.Errorf("invalid finalization monitor configuration: %w", err)
+		}
+		if c.L1Config == nil || c.L1Config.Endpoint == "" {
+			return errors.New("finalization monitor requires the l1 endpoint")
+		}
+	}
+	if c.L2Config.RelayerConfig != nil && c.L2Config.RelayerConfig.DAVerifier != nil {
+		verifier := c.L2Config.RelayerConfig.DAVerifier
+		if verifier.MaxBatchesPerRun <= 0 {
+			return fmt.Errorf("invalid da verifier max_batches_per_run configuration: %v", verifier.MaxBatchesPerRun)
+		}
+		if c.L1Config == nil || c.L1Config.Endpoint == "" {
+			return errors.New("da verifier requires the l1 endpoint")
+		}
+	}
+	if c.L2Config.RelayerConfig != nil && c.L2Config.RelayerConfig.L1PauseMonitor != nil {
+		if c.L2Config.RelayerConfig.L1PauseMonitor.MaxBlockRange == 0 {
+			return errors.New("l1 pause monitor max_block_range must be positive")
+		}
+		if c.L2Config.RelayerConfig.RollupContractAddress == (common.Address{}) {
+			return errors.New("l1 pause monitor requires the rollup_contract_address")
+		}
+		if c.L1Config == nil || c.L1Config.Endpoint == "" {
+			return errors.New("l1 pause monitor requires the l1 endpoint")
+		}
+	}
+	if err := c.validateRelayers(); err != nil {
+		return err
+	}
+	if c.L1Config != nil && c.L1Config.MessageAudit != nil {
+		if c.L1Config.MessageAudit.WindowSize == 0 {
+			return errors.New("l1 message audit requires a positive window_size")
+		}
+		if c.L1Config.L1MessageQueueAddress == (common.Address{}) {
+			return errors.New("l1 message audit requires the l1_message_queue_address")
+		}
+	}
+	if c.L1Config != nil && c.L1Config.Quorum != nil {
+		if err := c.L1Config.Quorum.validate(); err != nil {
+			return fmt.Errorf("invalid l1 quorum configuration: %w", err)
+		}
+	}
+	if c.L1Config != nil && c.L1Config.EventConfirmations != nil {
+		if err := c.L1Config.EventConfirmations.validate(); err != nil {
+			return fmt.Errorf("invalid l1 event_confirmations configuration: %w", err)
+		}
+	}
+	if c.AdminAPIConfig != nil && c.AdminAPIConfig.AuthToken == "" {
+		return errors.New("admin api requires a non-empty auth_token")
+	}
+	if c.LeaderElectionConfig != nil && c.LeaderElectionConfig.LockID == 0 {
+		return errors.New("leader election requires a non-zero lock_id")
+	}
+	if c.ArchiverConfig != nil && (c.ArchiverConfig.RetentionDays == 0 || c.ArchiverConfig.BatchSize <= 0) {
+		return fmt.Errorf("invalid archiver configuration: retention_days %v, batch_size %v, both must be positive", c.ArchiverConfig.RetentionDays, c.ArchiverConfig.BatchSize)
+	}
+	if c.DryRun && c.LeaderElectionConfig != nil {
+		return errors.New("dry run is not compatible with leader election, a shadow instance must not compete with live instances")
+	}
+	return nil
+}
+
+// validateRelayers checks the relayer and sender configs shared by the rollup relayer and the gas oracle.
+func (c *Config) validateRelayers() error {
+	if c.L1Config != nil && c.L1Config.RelayerConfig != nil && c.L1Config.RelayerConfig.GasOracleConfig != nil {
+		if blobBaseFee := c.L1Config.RelayerConfig.GasOracleConfig.BlobBaseFee; blobBaseFee != nil && (blobBaseFee.EWMAAlpha <= 0 || blobBaseFee.EWMAAlpha > 1) {
+			return fmt.Errorf("invalid blob_base_fee ewma_alpha configuration: %v, must be in (0, 1]", blobBaseFee.EWMAAlpha)
+		}
+		if aggregation := c.L1Config.RelayerConfig.GasOracleConfig.BaseFeeAggregation; aggregation != nil {
+			if err := aggregation.validate(); err != nil {
+				return fmt.Errorf("invalid base_fee_aggregation configuration: %w", err)
+			}
+		}
+	}
+	if c.L1Config != nil && c.L1Config.RelayerConfig != nil && c.L1Config.RelayerConfig.SenderConfig != nil {
+		if err := c.L1Config.RelayerConfig.SenderConfig.validateGasLimitMultiples(); err != nil {
+			return fmt.Errorf("invalid l1 sender gas_limit_multiples configuration: %w", err)
+		}
+		if err := c.L1Config.RelayerConfig.SenderConfig.validateBlobBudget(); err != nil {
+			return fmt.Errorf("invalid l1 sender blob_budget configuration: %w", err)
+		}
+	}
+	if c.L2Config.RelayerConfig != nil && c.L2Config.RelayerConfig.SenderConfig != nil {
+		if err := c.L2Config.RelayerConfig.SenderConfig.validateGasLimitMultiples(); err != nil {
+			return fmt.Errorf("invalid l2 sender gas_limit_multiples configuration: %w", err)
+		}
+		if err := c.L2Config.RelayerConfig.SenderConfig.validateBlobBudget(); err != nil {
+			return fmt.Errorf("invalid l2 sender blob_budget configuration: %w", err)
+		}
+	}
+	if c.L2Config.RelayerConfig != nil && c.L2Config.RelayerConfig.CommitPacing != nil {
+		if err := c.L2Config.RelayerConfig.CommitPacing.validate(); err != nil {
+			return fmt.Errorf("invalid commit_pacing configuration: %w", err)
+		}
+	}
 	return nil
 }
 
+// applyDryRun switches every sender to dry-run mode.
+func (c *Config) applyDryRun() {
+	if !c.DryRun {
+		return
+	}
+	if c.L1Config != nil && c.L1Config.RelayerConfig != nil && c.L1Config.RelayerConfig.SenderConfig != nil {
+		c.L1Config.RelayerConfig.SenderConfig.DryRun = true
+	}
+	if c.L2Config != nil && c.L2Config.RelayerConfig != nil && c.L2Config.RelayerConfig.SenderConfig != nil {
+		c.L2Config.RelayerConfig.SenderConfig.DryRun = true
+	}
+}
+
 // NewConfig returns a new instance of Config.
 func NewConfig(file string) (*Config, error) {
 	buf, err := os.ReadFile(filepath.Clean(file))
@@ -42,6 +214,7 @@ func NewConfig(file string) (*Config, error) {
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
+	cfg.applyDryRun()
 	return cfg, nil
 }
 
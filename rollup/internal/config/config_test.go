@@ -1,13 +1,18 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
 )
 
 func TestConfig(t *testing.T) {
@@ -35,6 +40,472 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, cfg.DBConfig, cfg2.DBConfig)
 	})
 
+	t.Run("Dry Run Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.False(t, cfg.L1Config.RelayerConfig.SenderConfig.DryRun)
+		assert.False(t, cfg.L2Config.RelayerConfig.SenderConfig.DryRun)
+
+		cfg.DryRun = true
+		data, err := json.Marshal(cfg)
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_dry_run_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+		cfg2, err := NewConfig(tmpJSON)
+		assert.NoError(t, err)
+		assert.True(t, cfg2.L1Config.RelayerConfig.SenderConfig.DryRun)
+		assert.True(t, cfg2.L2Config.RelayerConfig.SenderConfig.DryRun)
+
+		cfg.LeaderElectionConfig = &LeaderElectionConfig{LockID: 1}
+		data, err = json.Marshal(cfg)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+		_, err = NewConfig(tmpJSON)
+		assert.Error(t, err)
+	})
+
+	t.Run("Dynamic Sizing Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_dynamic_sizing_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		cfg.L2Config.BatchProposerConfig.DynamicSizing = &DynamicSizingConfig{LowFee: 1, HighFee: 10, MinFillRatio: 0.5, MinTimeoutRatio: 0.25}
+		data, err := json.Marshal(cfg)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+		cfg2, err := NewConfig(tmpJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, cfg.L2Config.BatchProposerConfig.DynamicSizing, cfg2.L2Config.BatchProposerConfig.DynamicSizing)
+		assert.Nil(t, cfg2.L2Config.ChunkProposerConfig.DynamicSizing)
+
+		cfg.L2Config.ChunkProposerConfig.DynamicSizing = &DynamicSizingConfig{LowFee: 10, HighFee: 10, MinFillRatio: 0.5, MinTimeoutRatio: 0.25}
+		data, err = json.Marshal(cfg)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+		_, err = NewConfig(tmpJSON)
+		assert.Error(t, err)
+	})
+
+	t.Run("Batch Accounting Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_batch_accounting_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		cfg.L2Config.BatchAccountingConfig = &BatchAccountingConfig{MaxBatchesPerRun: 20}
+		data, err := json.Marshal(cfg)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+		cfg2, err := NewConfig(tmpJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, 20, cfg2.L2Config.BatchAccountingConfig.MaxBatchesPerRun)
+
+		cfg.L2Config.BatchAccountingConfig.MaxBatchesPerRun = 0
+		data, err = json.Marshal(cfg)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+		_, err = NewConfig(tmpJSON)
+		assert.Error(t, err)
+	})
+
+	t.Run("Blob Base Fee Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_blob_base_fee_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		for _, tc := range []struct {
+			alpha float64
+			valid bool
+		}{{0.2, true}, {1, true}, {0, false}, {1.5, false}} {
+			cfg.L1Config.RelayerConfig.GasOracleConfig.BlobBaseFee = &BlobBaseFeeConfig{BlobBaseFeeDiff: 100000, EWMAAlpha: tc.alpha}
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, cfg.L1Config.RelayerConfig.GasOracleConfig.BlobBaseFee, cfg2.L1Config.RelayerConfig.GasOracleConfig.BlobBaseFee)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("Base Fee Aggregation Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_base_fee_aggregation_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		local := &BaseFeeSourceConfig{Name: "local", Type: BaseFeeSourceLocal, Weight: 1}
+		feeAPI := &BaseFeeSourceConfig{Name: "fee_api", Type: BaseFeeSourceHTTP, Endpoint: "http://fee-api", ValuePath: "data.base_fee", Weight: 1, TimeoutSec: 3}
+		for _, tc := range []struct {
+			sources    []*BaseFeeSourceConfig
+			minSources int
+			valid      bool
+		}{
+			{[]*BaseFeeSourceConfig{local, feeAPI}, 2, true},
+			{[]*BaseFeeSourceConfig{local, feeAPI}, 3, false},
+			{[]*BaseFeeSourceConfig{local, local}, 1, false},
+			{[]*BaseFeeSourceConfig{local, {Name: "rpc", Type: BaseFeeSourceRPC, Weight: 1, TimeoutSec: 3}}, 1, false},
+			{[]*BaseFeeSourceConfig{local, {Name: "fee_api", Type: BaseFeeSourceHTTP, Endpoint: "http://fee-api", Weight: 1, TimeoutSec: 3}}, 1, false},
+			{[]*BaseFeeSourceConfig{{Name: "local", Type: "unknown", Weight: 1}}, 1, false},
+			{[]*BaseFeeSourceConfig{{Name: "local", Type: BaseFeeSourceLocal}}, 1, false},
+		} {
+			cfg.L1Config.RelayerConfig.GasOracleConfig.BaseFeeAggregation = &BaseFeeAggregationConfig{Sources: tc.sources, MaxDeviation: 100000, MinSources: tc.minSources}
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, cfg.L1Config.RelayerConfig.GasOracleConfig.BaseFeeAggregation, cfg2.L1Config.RelayerConfig.GasOracleConfig.BaseFeeAggregation)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("L1 Quorum Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_l1_quorum_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		endpoints := []string{"http://l1-a:8545", "http://l1-b:8545", "http://l1-c:8545"}
+		for _, tc := range []struct {
+			threshold int
+			valid     bool
+		}{{2, true}, {3, true}, {1, false}, {4, false}, {0, false}} {
+			cfg.L1Config.Quorum = &QuorumConfig{Endpoints: endpoints, Threshold: tc.threshold}
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, cfg.L1Config.Quorum, cfg2.L1Config.Quorum)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("L1 Event Confirmations Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.Equal(t, cfg.L1Config.Confirmations, cfg.L1Config.MessageConfirmations())
+		assert.Equal(t, cfg.L1Config.Confirmations, cfg.L1Config.RollupEventConfirmations())
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_l1_event_confirmations_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		safe, finalized, pending, blocks := rpc.SafeBlockNumber, rpc.FinalizedBlockNumber, rpc.PendingBlockNumber, rpc.BlockNumber(12)
+		for _, tc := range []struct {
+			messages *rpc.BlockNumber
+			rollup   *rpc.BlockNumber
+			valid    bool
+		}{{&safe, &finalized, true}, {nil, &finalized, true}, {&blocks, nil, true}, {&pending, nil, false}} {
+			cfg.L1Config.EventConfirmations = &L1EventConfirmationsConfig{L1Messages: tc.messages, RollupEvents: tc.rollup}
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if !tc.valid {
+				assert.Error(t, err)
+				continue
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cfg.L1Config.MessageConfirmations(), cfg2.L1Config.MessageConfirmations())
+			assert.Equal(t, cfg.L1Config.RollupEventConfirmations(), cfg2.L1Config.RollupEventConfirmations())
+			if tc.rollup == nil {
+				assert.Equal(t, cfg.L1Config.Confirmations, cfg2.L1Config.RollupEventConfirmations())
+			}
+		}
+	})
+
+	t.Run("L1 Pause Monitor Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_l1_pause_monitor_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		for _, tc := range []struct {
+			maxBlockRange uint64
+			rollupAddress common.Address
+			valid         bool
+		}{
+			{1000, common.HexToAddress("0x01"), true},
+			{0, common.HexToAddress("0x01"), false},
+			{1000, common.Address{}, false},
+		} {
+			cfg.L2Config.RelayerConfig.L1PauseMonitor = &L1PauseMonitorConfig{MaxBlockRange: tc.maxBlockRange}
+			cfg.L2Config.RelayerConfig.RollupContractAddress = tc.rollupAddress
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, cfg.L2Config.RelayerConfig.L1PauseMonitor, cfg2.L2Config.RelayerConfig.L1PauseMonitor)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("Gas Limit Multiple Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		senderConfig := cfg.L2Config.RelayerConfig.SenderConfig
+		num, den := senderConfig.GasLimitMultiple(types.SenderTypeCommitBatch)
+		assert.Equal(t, [2]uint64{12, 10}, [2]uint64{num, den})
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_gas_limit_multiple_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		for _, tc := range []struct {
+			multiples map[string]*GasLimitMultipleConfig
+			valid     bool
+		}{
+			{map[string]*GasLimitMultipleConfig{"commit_batch": {Num: 15, Den: 10}, "l2_gas_oracle": {Num: 1, Den: 1}}, true},
+			{map[string]*GasLimitMultipleConfig{"commit_sender": {Num: 15, Den: 10}}, false},
+			{map[string]*GasLimitMultipleConfig{"commit_batch": {Num: 9, Den: 10}}, false},
+			{map[string]*GasLimitMultipleConfig{"commit_batch": {Num: 1, Den: 0}}, false},
+		} {
+			senderConfig.GasLimitMultiples = tc.multiples
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if !tc.valid {
+				assert.Error(t, err)
+				continue
+			}
+			assert.NoError(t, err)
+			num, den = cfg2.L2Config.RelayerConfig.SenderConfig.GasLimitMultiple(types.SenderTypeCommitBatch)
+			assert.Equal(t, [2]uint64{15, 10}, [2]uint64{num, den})
+			num, den = cfg2.L2Config.RelayerConfig.SenderConfig.GasLimitMultiple(types.SenderTypeFinalizeBatch)
+			assert.Equal(t, [2]uint64{12, 10}, [2]uint64{num, den})
+		}
+	})
+
+	t.Run("Blob Budget Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.Nil(t, cfg.L2Config.RelayerConfig.SenderConfig.BlobBudget)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_blob_budget_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		for _, tc := range []struct {
+			budget *BlobBudgetConfig
+			valid  bool
+		}{
+			{&BlobBudgetConfig{MaxPendingBlobs: 16, MaxBlobsPerBlock: 6}, true},
+			{&BlobBudgetConfig{MaxPendingBlobs: 16}, true},
+			{&BlobBudgetConfig{MaxBlobsPerBlock: 6}, false},
+			{&BlobBudgetConfig{MaxPendingBlobs: 4, MaxBlobsPerBlock: 6}, false},
+		} {
+			cfg.L2Config.RelayerConfig.SenderConfig.BlobBudget = tc.budget
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.budget, cfg2.L2Config.RelayerConfig.SenderConfig.BlobBudget)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("Commit Pacing Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.Nil(t, cfg.L2Config.RelayerConfig.CommitPacing)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_commit_pacing_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+
+		for _, tc := range []struct {
+			pacing *CommitPacingConfig
+			valid  bool
+		}{
+			{&CommitPacingConfig{WindowSec: 120, MaxCommitsPerWindow: 2, CatchUpDelaySec: 1800, CatchUpPendingBatches: 50}, true},
+			{&CommitPacingConfig{WindowSec: 3600, MaxCommitGasPerWindow: 10_000_000}, true},
+			{&CommitPacingConfig{MaxCommitsPerWindow: 2}, false},
+			{&CommitPacingConfig{WindowSec: 120}, false},
+		} {
+			cfg.L2Config.RelayerConfig.CommitPacing = tc.pacing
+			data, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+
+			cfg2, err := NewConfig(tmpJSON)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.pacing, cfg2.L2Config.RelayerConfig.CommitPacing)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("Shutdown Timeout Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, cfg.ShutdownTimeout())
+
+		cfg.ShutdownTimeoutSec = 5
+		assert.Equal(t, 5*time.Second, cfg.ShutdownTimeout())
+	})
+
+	t.Run("Reload Case", func(t *testing.T) {
+		cfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+
+		tmpJSON := fmt.Sprintf("/tmp/%d_rollup_reload_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+		write := func(update func(cfg *Config)) {
+			next, err := NewConfig("../../conf/config.json")
+			assert.NoError(t, err)
+			update(next)
+			data, err := json.Marshal(next)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+		}
+
+		reloader := NewReloader(tmpJSON, cfg)
+		var reloaded []*Config
+		reloader.Subscribe(context.Background(), func(cfg *Config) { reloaded = append(reloaded, cfg) })
+
+		// a tunable parameter is applied.
+		write(func(cfg *Config) { cfg.L2Config.RelayerConfig.SenderConfig.EscalateMultipleNum = 12 })
+		changes, err := reloader.Reload()
+		assert.NoError(t, err)
+		assert.Equal(t, []ParamChange{{Param: "l2_config.relayer_config.sender_config.escalate_multiple_num", Old: "11", New: "12"}}, changes)
+		assert.Len(t, reloaded, 1)
+		assert.Equal(t, uint64(12), reloader.Current().L2Config.RelayerConfig.SenderConfig.EscalateMultipleNum)
+
+		// an invalid or restart only change is rejected.
+		write(func(cfg *Config) { cfg.L2Config.RelayerConfig.SenderConfig.EscalateMultipleNum = 9 })
+		_, err = reloader.Reload()
+		assert.ErrorContains(t, err, "escalate_multiple_num")
+		write(func(cfg *Config) {
+			cfg.L2Config.RelayerConfig.SenderConfig.EscalateMultipleNum = 13
+			cfg.L2Config.Endpoint = "http://localhost:8545"
+		})
+		_, err = reloader.Reload()
+		assert.ErrorContains(t, err, "l2_config.endpoint")
+		assert.Len(t, reloaded, 1)
+		assert.Equal(t, uint64(12), reloader.Current().L2Config.RelayerConfig.SenderConfig.EscalateMultipleNum)
+	})
+
+	t.Run("Gas Oracle Case", func(t *testing.T) {
+		cfg, err := NewGasOracleConfig("../../conf/gas_oracle_config.json")
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg.L1Config.RelayerConfig.GasOracleSenderPrivateKey)
+		assert.NotNil(t, cfg.L2Config.RelayerConfig.GasOracleSenderPrivateKey)
+		assert.Nil(t, cfg.L2Config.RelayerConfig.CommitSenderPrivateKey)
+		assert.Equal(t, "gas_oracle_", cfg.MetricsPrefix())
+		assert.Equal(t, uint64(300), cfg.HealthCheckConfig.MaxGasOracleRunAgeSec)
+
+		// The rollup relayer config is a valid gas oracle config, without metrics namespace it defaults to gas_oracle.
+		cfg, err = NewGasOracleConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.Equal(t, "gas_oracle_", cfg.MetricsPrefix())
+		rollupCfg, err := NewConfig("../../conf/config.json")
+		assert.NoError(t, err)
+		assert.Equal(t, "", rollupCfg.MetricsPrefix())
+
+		cfg.L2Config.RelayerConfig.GasOracleSenderPrivateKey = nil
+		data, err := json.Marshal(cfg)
+		assert.NoError(t, err)
+		tmpJSON := fmt.Sprintf("/tmp/%d_gas_oracle_config.json", time.Now().Nanosecond())
+		defer func() {
+			if _, err = os.Stat(tmpJSON); err == nil {
+				assert.NoError(t, os.Remove(tmpJSON))
+			}
+		}()
+		assert.NoError(t, os.WriteFile(tmpJSON, data, 0644))
+		_, err = NewGasOracleConfig(tmpJSON)
+		assert.ErrorContains(t, err, "gas oracle requires the l2 relayer sender_config and gas_oracle_sender_private_key")
+	})
+
 	t.Run("File Not Found", func(t *testing.T) {
 		_, err := NewConfig("non_existent_file.json")
 		assert.ErrorIs(t, err, os.ErrNotExist)
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// defaultGasOracleMetricsNamespace is the metrics namespace of the gas_oracle binary when metrics_namespace is not
+// configured, so that its sender and relayer metrics are not mixed up with the ones of the rollup relayer.
+const defaultGasOracleMetricsNamespace = "gas_oracle"
+
+// NewGasOracleConfig returns the config of the gas_oracle binary. The gas oracle runs with its own config file, sender
+// and key, independently of the batch submission: only the l1 and l2 endpoints, their relayer configs with the gas
+// oracle sender private keys and the db config are required, the proposers and the other senders are not configured.
+func NewGasOracleConfig(file string) (*Config, error) {
+	buf, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	err = json.Unmarshal(buf, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateGasOracle(); err != nil {
+		return nil, err
+	}
+	if cfg.MetricsNamespace == "" {
+		cfg.MetricsNamespace = defaultGasOracleMetricsNamespace
+	}
+	cfg.applyDryRun()
+	return cfg, nil
+}
+
+func (c *Config) validateGasOracle() error {
+	if c.L1Config == nil || c.L1Config.Endpoint == "" {
+		return errors.New("gas oracle requires the l1 endpoint")
+	}
+	if c.L2Config == nil || c.L2Config.Endpoint == "" {
+		return errors.New("gas oracle requires the l2 endpoint")
+	}
+	if c.L1Config.RelayerConfig == nil || c.L1Config.RelayerConfig.SenderConfig == nil || c.L1Config.RelayerConfig.GasOracleSenderPrivateKey == nil {
+		return errors.New("gas oracle requires the l1 relayer sender_config and gas_oracle_sender_private_key")
+	}
+	if c.L2Config.RelayerConfig == nil || c.L2Config.RelayerConfig.SenderConfig == nil || c.L2Config.RelayerConfig.GasOracleSenderPrivateKey == nil {
+		return errors.New("gas oracle requires the l2 relayer sender_config and gas_oracle_sender_private_key")
+	}
+	return c.validateRelayers()
+}
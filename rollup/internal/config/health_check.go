@@ -0,0 +1,12 @@
+package config
+
+// HealthCheckConfig loads the thresholds of the readiness probe.
+type HealthCheckConfig struct {
+	// The max number of confirmed L1 blocks not processed yet by the L1 watcher, the lag is not checked when 0.
+	MaxL1WatcherLag uint64 `json:"max_l1_watcher_lag,omitempty"`
+	// The max number of confirmed L2 blocks not stored yet by the L2 watcher, the lag is not checked when 0.
+	MaxL2WatcherLag uint64 `json:"max_l2_watcher_lag,omitempty"`
+	// The max number of seconds since the last successful run of each gas price oracle of the gas oracle, updating or
+	// deliberately keeping the on-chain value, the runs are not checked when 0.
+	MaxGasOracleRunAgeSec uint64 `json:"max_gas_oracle_run_age_sec,omitempty"`
+}
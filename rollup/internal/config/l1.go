@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
@@ -9,14 +11,98 @@ import (
 type L1Config struct {
 	// Confirmations block height confirmations number.
 	Confirmations rpc.BlockNumber `json:"confirmations"`
+	// The confirmations of each class of contract events, the classes not set wait for Confirmations.
+	EventConfirmations *L1EventConfirmationsConfig `json:"event_confirmations,omitempty"`
 	// l1 eth node url.
 	Endpoint string `json:"endpoint"`
+	// l1 eth node websocket url, contract events are followed through eth_subscribe when set and polled otherwise.
+	WebsocketEndpoint string `json:"websocket_endpoint,omitempty"`
 	// The start height to sync event from layer 1
 	StartHeight uint64 `json:"start_height"`
 	// The L1MessageQueue contract address deployed on layer 1 chain.
 	L1MessageQueueAddress common.Address `json:"l1_message_queue_address"`
 	// The ScrollChain contract address deployed on layer 1 chain.
 	ScrollChainContractAddress common.Address `json:"scroll_chain_address"`
+	// The L1ScrollMessenger contract address deployed on layer 1 chain, used to replay skipped L1 messages.
+	L1ScrollMessengerAddress common.Address `json:"l1_scroll_messenger_address,omitempty"`
 	// The relayer config
 	RelayerConfig *RelayerConfig `json:"relayer_config"`
+	// The l1 endpoints required to agree on the data feeding finalization, nil to trust the endpoint.
+	Quorum *QuorumConfig `json:"quorum,omitempty"`
+	// The periodic audit of the stored L1 messages against the L1MessageQueue contract, nil disables it.
+	MessageAudit *L1MessageAuditConfig `json:"message_audit,omitempty"`
+}
+
+// L1EventConfirmationsConfig loads the confirmations the L1 watcher waits for before acting on each class of contract
+// events, e.g. the L1 messages can be relayed at the "safe" block while the rollup events wait for the "finalized" one.
+type L1EventConfirmationsConfig struct {
+	// The confirmations of the QueueTransaction events of the L1MessageQueue contract.
+	L1Messages *rpc.BlockNumber `json:"l1_messages,omitempty"`
+	// The confirmations of the CommitBatch and FinalizeBatch events of the ScrollChain contract.
+	RollupEvents *rpc.BlockNumber `json:"rollup_events,omitempty"`
+}
+
+// MessageConfirmations returns the confirmations of the L1 message events.
+func (c *L1Config) MessageConfirmations() rpc.BlockNumber {
+	if c.EventConfirmations != nil && c.EventConfirmations.L1Messages != nil {
+		return *c.EventConfirmations.L1Messages
+	}
+	return c.Confirmations
+}
+
+// RollupEventConfirmations returns the confirmations of the rollup events.
+func (c *L1Config) RollupEventConfirmations() rpc.BlockNumber {
+	if c.EventConfirmations != nil && c.EventConfirmations.RollupEvents != nil {
+		return *c.EventConfirmations.RollupEvents
+	}
+	return c.Confirmations
+}
+
+func validateConfirmations(confirmations rpc.BlockNumber) error {
+	switch confirmations {
+	case rpc.SafeBlockNumber, rpc.FinalizedBlockNumber, rpc.LatestBlockNumber:
+		return nil
+	}
+	if confirmations.Int64() < 0 {
+		return fmt.Errorf("unsupported confirmations: %v", confirmations)
+	}
+	return nil
+}
+
+func (c *L1EventConfirmationsConfig) validate() error {
+	if c.L1Messages != nil {
+		if err := validateConfirmations(*c.L1Messages); err != nil {
+			return fmt.Errorf("l1_messages: %w", err)
+		}
+	}
+	if c.RollupEvents != nil {
+		if err := validateConfirmations(*c.RollupEvents); err != nil {
+			return fmt.Errorf("rollup_events: %w", err)
+		}
+	}
+	return nil
+}
+
+// L1MessageAuditConfig loads the configuration of the L1 message queue audit. The audit compares the next queue index
+// and the hashes of the latest messages with the contract at the latest block processed by the L1 watcher, the
+// message relaying is halted while they diverge.
+type L1MessageAuditConfig struct {
+	// The number of latest messages whose hashes are compared with the contract.
+	WindowSize uint64 `json:"window_size"`
+}
+
+// QuorumConfig loads the configuration of the quorum reads of the l1 data feeding finalization.
+type QuorumConfig struct {
+	// The l1 eth node urls queried for each read.
+	Endpoints []string `json:"endpoints"`
+	// The number of endpoints which must return the same result.
+	Threshold int `json:"threshold"`
+}
+
+func (c *QuorumConfig) validate() error {
+	// A majority threshold prevents two conflicting results from both reaching the quorum.
+	if c.Threshold <= 0 || c.Threshold > len(c.Endpoints) || 2*c.Threshold <= len(c.Endpoints) {
+		return fmt.Errorf("threshold %d must be a majority of the %d endpoints", c.Threshold, len(c.Endpoints))
+	}
+	return nil
 }
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/scroll-tech/go-ethereum/rpc"
 
 	"github.com/scroll-tech/go-ethereum/common"
@@ -16,12 +19,66 @@ type L2Config struct {
 	L2MessageQueueAddress common.Address `json:"l2_message_queue_address"`
 	// The WithdrawTrieRootSlot in L2MessageQueue contract.
 	WithdrawTrieRootSlot common.Hash `json:"withdraw_trie_root_slot,omitempty"`
+	// Whether to compute the withdraw roots of the blocks from the AppendMessage events on the withdraw trie persisted
+	// with the l2 watcher checkpoint, instead of reading the root of every block. The computed root is checked against
+	// the root read at the last block of every batch.
+	ComputeWithdrawRoot bool `json:"compute_withdraw_root,omitempty"`
+	// Whether to persist the L1 messages skipped by l2geth, requires the scroll namespace of l2geth.
+	FetchSkippedL1Messages bool `json:"fetch_skipped_l1_messages,omitempty"`
 	// The relayer config
 	RelayerConfig *RelayerConfig `json:"relayer_config"`
 	// The chunk_proposer config
 	ChunkProposerConfig *ChunkProposerConfig `json:"chunk_proposer_config"`
 	// The batch_proposer config
 	BatchProposerConfig *BatchProposerConfig `json:"batch_proposer_config"`
+	// The batch_accounting config, the fees of the batches are not recorded when it is not set.
+	BatchAccountingConfig *BatchAccountingConfig `json:"batch_accounting_config,omitempty"`
+	// The finalization_monitor config, the finalization deadlines of the committed batches are not monitored when it
+	// is not set.
+	FinalizationMonitorConfig *FinalizationMonitorConfig `json:"finalization_monitor_config,omitempty"`
+}
+
+// FinalizationMonitorConfig loads finalization_monitor configuration items. The deadline of a committed batch is its
+// commit time plus DeadlineSec, e.g. the delay after which the rollup contract lets anyone finalize the batches, or
+// an operational target. An alert is fired once per batch and threshold crossed, with the reason of the delay.
+type FinalizationMonitorConfig struct {
+	// The time since its commit a batch should be finalized by.
+	DeadlineSec uint64 `json:"deadline_sec"`
+	// The fractions of the deadline elapsed at which the alerts of a batch are fired, ascending in (0, 1], e.g.
+	// [0.5, 0.8, 1]. The last threshold fires a critical alert, the others a warning.
+	AlertThresholds []float64 `json:"alert_thresholds"`
+	// The L1 base fee in wei above which the delay of a submitted finalize transaction is attributed to L1 congestion,
+	// only a base fee above the fee cap of the transaction is attributed to congestion when 0.
+	CongestedBaseFee uint64 `json:"congested_base_fee,omitempty"`
+	// The webhook the alerts are posted to as json, nil disables it. Its retry count and timeout apply to every hook.
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// The Slack incoming webhook url the alerts are posted to, empty disables it.
+	SlackWebhookURL string `json:"slack_webhook_url,omitempty"`
+	// The routing key of the PagerDuty Events API v2 integration the alerts are triggered on, empty disables it. The
+	// incidents are resolved once their batches are finalized.
+	PagerDutyRoutingKey string `json:"pagerduty_routing_key,omitempty"`
+}
+
+func (c *FinalizationMonitorConfig) validate() error {
+	if c.DeadlineSec == 0 {
+		return errors.New("deadline_sec must be positive")
+	}
+	if len(c.AlertThresholds) == 0 {
+		return errors.New("alert_thresholds must not be empty")
+	}
+	for i, threshold := range c.AlertThresholds {
+		if threshold <= 0 || threshold > 1 || (i > 0 && threshold <= c.AlertThresholds[i-1]) {
+			return fmt.Errorf("alert_thresholds must be ascending in (0, 1]: %v", c.AlertThresholds)
+		}
+	}
+	return nil
+}
+
+// BatchAccountingConfig loads batch_accounting configuration items. The L1 fees paid to commit and finalize each batch
+// are read from the receipts of the l1 endpoint, the L2 fees collected in its blocks from the receipts of l2geth.
+type BatchAccountingConfig struct {
+	// The maximum number of batches accounted per run.
+	MaxBatchesPerRun int `json:"max_batches_per_run"`
 }
 
 // ChunkProposerConfig loads chunk_proposer configuration items.
@@ -33,6 +90,12 @@ type ChunkProposerConfig struct {
 	ChunkTimeoutSec                 uint64  `json:"chunk_timeout_sec"`
 	MaxRowConsumptionPerChunk       uint64  `json:"max_row_consumption_per_chunk"`
 	GasCostIncreaseMultiplier       float64 `json:"gas_cost_increase_multiplier"`
+	// The maximum number of L1 messages skipped by the sequencer in a chunk, 0 disables the limit. A chunk is ended
+	// before the block exceeding it and a block skipping more messages on its own is held until approved on the admin
+	// api.
+	MaxL1MessageSkipsPerChunk uint64 `json:"max_l1_message_skips_per_chunk,omitempty"`
+	// The adaptation of the chunk size and timeout to the L1 fees, nil proposes chunks with the configured limits only.
+	DynamicSizing *DynamicSizingConfig `json:"dynamic_sizing,omitempty"`
 }
 
 // BatchProposerConfig loads batch_proposer configuration items.
@@ -42,4 +105,37 @@ type BatchProposerConfig struct {
 	MaxL1CommitCalldataSizePerBatch uint64  `json:"max_l1_commit_calldata_size_per_batch"`
 	BatchTimeoutSec                 uint64  `json:"batch_timeout_sec"`
 	GasCostIncreaseMultiplier       float64 `json:"gas_cost_increase_multiplier"`
+	// The maximum number of L1 messages skipped by the sequencer in a batch, 0 disables the limit. A batch is ended
+	// before the chunk exceeding it and a chunk skipping more messages on its own is held until approved on the admin
+	// api.
+	MaxL1MessageSkipsPerBatch uint64 `json:"max_l1_message_skips_per_batch,omitempty"`
+	// The adaptation of the batch size and timeout to the L1 fees, nil proposes batches with the configured limits only.
+	DynamicSizing *DynamicSizingConfig `json:"dynamic_sizing,omitempty"`
+}
+
+// DynamicSizingConfig loads the configuration items adapting the size and timeout of proposals to the L1 fees of the
+// latest L1 block. When fees are expensive proposals are packed up to the configured limits, when fees are cheap they
+// are proposed once they reach a fraction of the limits or of the timeout, which reduces the finality latency.
+type DynamicSizingConfig struct {
+	// The L1 fee in wei at or below which fees are cheap.
+	LowFee uint64 `json:"low_fee"`
+	// The L1 fee in wei at or above which fees are expensive, the targets are interpolated linearly in between.
+	HighFee uint64 `json:"high_fee"`
+	// The fraction of the size limits a proposal is packed to when fees are cheap, in (0, 1].
+	MinFillRatio float64 `json:"min_fill_ratio"`
+	// The fraction of the timeout after which a proposal is made when fees are cheap, in (0, 1].
+	MinTimeoutRatio float64 `json:"min_timeout_ratio"`
+}
+
+func (c *DynamicSizingConfig) validate() error {
+	if c.HighFee <= c.LowFee {
+		return fmt.Errorf("high_fee must be greater than low_fee, low_fee: %v, high_fee: %v", c.LowFee, c.HighFee)
+	}
+	if c.MinFillRatio <= 0 || c.MinFillRatio > 1 {
+		return fmt.Errorf("min_fill_ratio must be in (0, 1], got: %v", c.MinFillRatio)
+	}
+	if c.MinTimeoutRatio <= 0 || c.MinTimeoutRatio > 1 {
+		return fmt.Errorf("min_timeout_ratio must be in (0, 1], got: %v", c.MinTimeoutRatio)
+	}
+	return nil
 }
@@ -0,0 +1,11 @@
+package config
+
+// LeaderElectionConfig loads the leader election configuration items.
+type LeaderElectionConfig struct {
+	// The key of the Postgres advisory lock held by the leader, instances sharing a database and a key elect one leader.
+	LockID int64 `json:"lock_id"`
+	// The interval at which a standby instance tries to acquire the lock.
+	RetryIntervalSec uint64 `json:"retry_interval_sec"`
+	// The interval at which the leader checks that its lock session is alive.
+	HeartbeatIntervalSec uint64 `json:"heartbeat_interval_sec"`
+}
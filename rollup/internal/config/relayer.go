@@ -1,13 +1,19 @@
 package config
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/common/secrets"
+	"scroll-tech/common/types"
 )
 
 // SenderConfig The config for transaction sender
@@ -20,14 +26,182 @@ type SenderConfig struct {
 	EscalateBlocks uint64 `json:"escalate_blocks"`
 	// The gap number between a block be confirmed and the latest block.
 	Confirmations rpc.BlockNumber `json:"confirmations"`
+	// The number of blocks below the confirmed block within which confirmed transactions are still re-verified against reorgs, 0 disables it.
+	ReorgCheckBlocks uint64 `json:"reorg_check_blocks"`
+	// The number of replacements after which a transaction is marked stuck and no longer escalated until an operator resumes it, 0 means no limit.
+	MaxReplacements uint64 `json:"max_replacements"`
 	// The numerator of gas price escalate multiple.
 	EscalateMultipleNum uint64 `json:"escalate_multiple_num"`
 	// The denominator of gas price escalate multiple.
 	EscalateMultipleDen uint64 `json:"escalate_multiple_den"`
+	// The bounds within which the escalate multiple adapts to the success rate of replacements, nil keeps it static.
+	AdaptiveEscalation *AdaptiveEscalationConfig `json:"adaptive_escalation,omitempty"`
+	// The multiples applied to the estimated gas limit per sender type, keyed by commit_batch, finalize_batch,
+	// l1_gas_oracle, l2_gas_oracle or replay_message, the sender types not configured pad the estimate by 20%.
+	GasLimitMultiples map[string]*GasLimitMultipleConfig `json:"gas_limit_multiples,omitempty"`
 	// The maximum gas price can be used to send transaction.
 	MaxGasPrice uint64 `json:"max_gas_price"`
-	// The transaction type to use: LegacyTx, AccessListTx, DynamicFeeTx
+	// The maximum blob gas price can be used when resubmitting blob transactions.
+	MaxBlobGasPrice uint64 `json:"max_blob_gas_price"`
+	// The minimum gas price of legacy transactions and gas fee cap of DynamicFeeTx transactions, for networks suggesting unusable prices, 0 disables the floor.
+	MinGasPrice uint64 `json:"min_gas_price"`
+	// The minimum gas tip cap of DynamicFeeTx transactions, 0 disables the floor.
+	MinGasTipCap uint64 `json:"min_gas_tip_cap"`
+	// The transaction type to use: LegacyTx, AccessListTx, DynamicFeeTx.
+	// DynamicFeeTx is downgraded to LegacyTx on start if the chain does not support EIP-1559.
 	TxType string `json:"tx_type"`
+	// The number of receipts fetched per JSON-RPC batch request when checking pending transactions, 0 fetches them one by one.
+	ReceiptBatchSize int `json:"receipt_batch_size"`
+	// Whether to fetch the receipts of transactions with a known inclusion block with one eth_getBlockReceipts per block, requires ReceiptBatchSize.
+	UseBlockReceipts bool `json:"use_block_receipts"`
+	// The maximum number of in-flight transactions, further transactions are queued until earlier ones confirm, 0 means no limit.
+	MaxPendingTxs uint64 `json:"max_pending_txs"`
+	// The limits on the blobs carried by the in-flight blob transactions, further blob transactions are queued, nil means no limit.
+	BlobBudget *BlobBudgetConfig `json:"blob_budget,omitempty"`
+	// The account balance in wei below which the sender stops issuing new transactions, nil disables the guard.
+	MinBalance *big.Int `json:"min_balance,omitempty"`
+	// The base fee in wei above which deferrable transactions (gas oracle updates) are held, 0 disables deferral.
+	DeferBaseFeeCeiling uint64 `json:"defer_base_fee_ceiling"`
+	// The maximum number of seconds a deferrable transaction is held before it is sent regardless of the base fee, 0 means no limit.
+	MaxDeferSeconds uint64 `json:"max_defer_seconds"`
+	// Whether to build, sign and record transactions in pending_transaction without broadcasting them, see the replay command.
+	DryRun bool `json:"dry_run"`
+	// Whether to trace reverted transactions with debug_traceTransaction and persist the failing call frame, requires the debug namespace.
+	TraceFailedTransactions bool `json:"trace_failed_transactions"`
+	// Whether to simulate the transaction with eth_call at the pending block before sending it.
+	SimulateBeforeSend bool `json:"simulate_before_send"`
+	// The strategy used to estimate the gas tip cap of DynamicFeeTx transactions, nil uses the node suggestion.
+	FeeEstimator *FeeEstimatorConfig `json:"fee_estimator,omitempty"`
+	// The access list generation config, nil generates an access list for every DynamicFeeTx without caching.
+	AccessList *AccessListConfig `json:"access_list,omitempty"`
+	// The Multicall3 contract used to aggregate the calls of AggregateCall, nil disables aggregation.
+	Multicall *MulticallConfig `json:"multicall,omitempty"`
+	// The webhook notified of transaction lifecycle events, nil disables it.
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+}
+
+// GasLimitMultipleConfig The multiple applied to the estimated gas limit of the transactions of a sender type.
+type GasLimitMultipleConfig struct {
+	// The numerator of the gas limit multiple, must not be smaller than Den.
+	Num uint64 `json:"num"`
+	// The denominator of the gas limit multiple.
+	Den uint64 `json:"den"`
+}
+
+// The gas limit multiple of the sender types not configured in GasLimitMultiples.
+const (
+	defaultGasLimitMultipleNum = 12
+	defaultGasLimitMultipleDen = 10
+)
+
+// gasLimitMultipleSenderTypes maps the keys of GasLimitMultiples to the sender types.
+var gasLimitMultipleSenderTypes = map[string]types.SenderType{
+	"commit_batch":   types.SenderTypeCommitBatch,
+	"finalize_batch": types.SenderTypeFinalizeBatch,
+	"l1_gas_oracle":  types.SenderTypeL1GasOracle,
+	"l2_gas_oracle":  types.SenderTypeL2GasOracle,
+	"replay_message": types.SenderTypeReplayMessage,
+}
+
+// GasLimitMultiple returns the multiple applied to the estimated gas limit of the transactions of senderType.
+func (c *SenderConfig) GasLimitMultiple(senderType types.SenderType) (num, den uint64) {
+	for key, t := range gasLimitMultipleSenderTypes {
+		if t != senderType {
+			continue
+		}
+		if multiple, ok := c.GasLimitMultiples[key]; ok && multiple != nil {
+			return multiple.Num, multiple.Den
+		}
+	}
+	return defaultGasLimitMultipleNum, defaultGasLimitMultipleDen
+}
+
+func (c *SenderConfig) validateGasLimitMultiples() error {
+	for key, multiple := range c.GasLimitMultiples {
+		if _, ok := gasLimitMultipleSenderTypes[key]; !ok {
+			return fmt.Errorf("unknown sender type %q", key)
+		}
+		if multiple == nil || multiple.Den == 0 || multiple.Num < multiple.Den {
+			return fmt.Errorf("invalid multiple of %s, num must not be smaller than a positive den", key)
+		}
+	}
+	return nil
+}
+
+// BlobBudgetConfig The config for pacing the blob transactions of a sender, replacements are never held as they reuse the blob slots of the replaced transactions.
+type BlobBudgetConfig struct {
+	// The maximum number of blobs carried by the in-flight transactions, at most the account blob limit of the blob pool,
+	// so that the replacements of the in-flight transactions are not rejected.
+	MaxPendingBlobs uint64 `json:"max_pending_blobs"`
+	// The maximum number of blobs of the new transactions sent within one L1 block, at most the per-block blob limit, 0 means no limit.
+	MaxBlobsPerBlock uint64 `json:"max_blobs_per_block"`
+}
+
+func (c *SenderConfig) validateBlobBudget() error {
+	if c.BlobBudget == nil {
+		return nil
+	}
+	if c.BlobBudget.MaxPendingBlobs == 0 {
+		return errors.New("max_pending_blobs must be positive")
+	}
+	if c.BlobBudget.MaxBlobsPerBlock > c.BlobBudget.MaxPendingBlobs {
+		return fmt.Errorf("max_blobs_per_block %d exceeds max_pending_blobs %d", c.BlobBudget.MaxBlobsPerBlock, c.BlobBudget.MaxPendingBlobs)
+	}
+	return nil
+}
+
+// WebhookConfig The config for the delivery of transaction lifecycle events to an HTTP endpoint.
+type WebhookConfig struct {
+	// The URL the events are posted to.
+	URL string `json:"url"`
+	// The secret of the HMAC-SHA256 signature of the request body sent in the X-Scroll-Signature header, empty disables signing.
+	Secret string `json:"secret,omitempty"`
+	// The number of retries of a failed delivery, defaults to 3.
+	RetryCount int `json:"retry_count,omitempty"`
+	// The timeout in seconds of a delivery attempt, defaults to 5.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// AdaptiveEscalationConfig The config for adapting the numerator of the escalate multiple, the denominator stays EscalateMultipleDen.
+type AdaptiveEscalationConfig struct {
+	// The minimum numerator of the escalate multiple, must be larger than EscalateMultipleDen.
+	MinMultipleNum uint64 `json:"min_multiple_num"`
+	// The maximum numerator of the escalate multiple.
+	MaxMultipleNum uint64 `json:"max_multiple_num"`
+	// The number of replacement outcomes at the current multiple after which it is adjusted, defaults to 20.
+	Window uint64 `json:"window,omitempty"`
+	// The success rate under which the multiple is raised by one, defaults to 0.5.
+	LowSuccessRate float64 `json:"low_success_rate,omitempty"`
+	// The success rate above which the multiple is lowered by one, defaults to 0.9.
+	HighSuccessRate float64 `json:"high_success_rate,omitempty"`
+}
+
+// AccessListConfig The config for the access lists generated with eth_createAccessList.
+type AccessListConfig struct {
+	// The number of seconds an access list is reused for the same contract method, 0 disables the cache.
+	CacheTTLSeconds uint64 `json:"cache_ttl_seconds"`
+	// The contracts for which no access list is generated, where access lists don't reduce the gas cost.
+	DisabledContracts []common.Address `json:"disabled_contracts,omitempty"`
+}
+
+// MulticallConfig The config for the aggregation of calls into Multicall3 transactions.
+type MulticallConfig struct {
+	// The address of the Multicall3 contract.
+	Address common.Address `json:"address"`
+	// The maximum number of calls per aggregated transaction, a full batch is sent without waiting for the next block, 0 means no limit.
+	MaxCalls int `json:"max_calls"`
+}
+
+// FeeEstimatorConfig The config for the gas tip cap estimation strategy of transaction sender.
+type FeeEstimatorConfig struct {
+	// The estimator type: node (eth_maxPriorityFeePerGas) or fee_history (eth_feeHistory percentiles).
+	Type string `json:"type"`
+	// The reward percentile of eth_feeHistory, defaults to 50.
+	RewardPercentile float64 `json:"reward_percentile,omitempty"`
+	// The number of blocks of eth_feeHistory to average over, defaults to 20.
+	LookbackBlocks uint64 `json:"lookback_blocks,omitempty"`
+	// The smoothing factor of the EWMA in (0, 1], higher values follow the fee market faster, defaults to 0.3.
+	EWMAAlpha float64 `json:"ewma_alpha,omitempty"`
 }
 
 // ChainMonitor this config is used to get batch status from chain_monitor API.
@@ -38,11 +212,56 @@ type ChainMonitor struct {
 	BaseURL  string `json:"base_url"`
 }
 
+// DAVerifierConfig loads the configuration of the verifier re-deriving the committed batch data from L1. The batches
+// of the rollup relayer are only finalized once their commit data is verified when configured.
+type DAVerifierConfig struct {
+	// The maximum number of committed batches verified per run.
+	MaxBatchesPerRun int `json:"max_batches_per_run"`
+}
+
+// L1PauseMonitorConfig loads the configuration of the monitor of the Paused and Unpaused events of the rollup contract
+// and of the L1 messenger. The commit and finalize transactions are suspended while the rollup contract is paused.
+type L1PauseMonitorConfig struct {
+	// The maximum number of L1 blocks scanned for the pause events per query.
+	MaxBlockRange uint64 `json:"max_block_range"`
+}
+
+// CommitPacingConfig loads the pacing of the batch commits, spreading them over time instead of committing the batches
+// as soon as they are proposed. A commit is held while the commits sent within the last WindowSec reach
+// MaxCommitsPerWindow or their commit gas reaches MaxCommitGasPerWindow, e.g. a window of 120 seconds paces the
+// commits per 10 L1 blocks. The pacing is bypassed to catch up with a backlog, while the oldest pending batch waits
+// longer than CatchUpDelaySec or at least CatchUpPendingBatches batches are pending.
+type CommitPacingConfig struct {
+	WindowSec uint64 `json:"window_sec"`
+	// The maximum number of commit transactions sent within a window, 0 means no limit.
+	MaxCommitsPerWindow uint64 `json:"max_commits_per_window,omitempty"`
+	// The maximum commit gas of the transactions sent within a window, as estimated for their fallback gas limit,
+	// 0 means no limit. A commit exceeding it alone is sent once the window is empty.
+	MaxCommitGasPerWindow uint64 `json:"max_commit_gas_per_window,omitempty"`
+	// 0 disables the catch-up by delay.
+	CatchUpDelaySec uint64 `json:"catch_up_delay_sec,omitempty"`
+	// 0 disables the catch-up by backlog.
+	CatchUpPendingBatches uint64 `json:"catch_up_pending_batches,omitempty"`
+}
+
+func (c *CommitPacingConfig) validate() error {
+	if c.WindowSec == 0 {
+		return errors.New("window_sec must be positive")
+	}
+	if c.MaxCommitsPerWindow == 0 && c.MaxCommitGasPerWindow == 0 {
+		return errors.New("max_commits_per_window or max_commit_gas_per_window is required")
+	}
+	return nil
+}
+
 // RelayerConfig loads relayer configuration items.
 // What we need to pay attention to is that
 type RelayerConfig struct {
 	// RollupContractAddress store the rollup contract address.
 	RollupContractAddress common.Address `json:"rollup_contract_address,omitempty"`
+	// CommittedBatchesSlot store the storage slot of the `committedBatches` mapping in the rollup contract, used to estimate
+	// a batch commit while its parent commit is in flight. Zero disables the state override.
+	CommittedBatchesSlot common.Hash `json:"committed_batches_slot,omitempty"`
 	// GasPriceOracleContractAddress store the scroll messenger contract address.
 	GasPriceOracleContractAddress common.Address `json:"gas_price_oracle_contract_address"`
 	// sender config
@@ -51,12 +270,26 @@ type RelayerConfig struct {
 	GasOracleConfig *GasOracleConfig `json:"gas_oracle_config"`
 	// ChainMonitor config of monitoring service
 	ChainMonitor *ChainMonitor `json:"chain_monitor"`
+	// DAVerifier re-derives the committed batch data from L1 and holds the finalization of mismatching batches,
+	// finalization does not wait for the verification when nil.
+	DAVerifier *DAVerifierConfig `json:"da_verifier,omitempty"`
+	// L1PauseMonitor suspends the commit and finalize transactions while the rollup contract is paused, nil submits
+	// them regardless of the pause state.
+	L1PauseMonitor *L1PauseMonitorConfig `json:"l1_pause_monitor,omitempty"`
 	// L1CommitGasLimitMultiplier multiplier for fallback gas limit in commitBatch txs
 	L1CommitGasLimitMultiplier float64 `json:"l1_commit_gas_limit_multiplier,omitempty"`
-	// The private key of the relayer
+	// ReproposeStaleBatches enables proposing the batches of a reverted commit again when their parent batch hash is stale,
+	// e.g. after the parent batch was proposed again. The stale batches are kept RollupCommitFailed when disabled.
+	ReproposeStaleBatches bool `json:"repropose_stale_batches,omitempty"`
+	// CommitPacing spreads the batch commits over time, nil commits the batches as soon as they are proposed.
+	CommitPacing *CommitPacingConfig `json:"commit_pacing,omitempty"`
+	// The private key of the relayer, configured in hex or as a reference to the secret store holding it, e.g.
+	// awssm://rollup/commit-sender-key.
 	GasOracleSenderPrivateKey *ecdsa.PrivateKey `json:"-"`
 	CommitSenderPrivateKey    *ecdsa.PrivateKey `json:"-"`
 	FinalizeSenderPrivateKey  *ecdsa.PrivateKey `json:"-"`
+	// The private key replaying skipped L1 messages, it pays the fee of the replayed messages.
+	ReplayMessageSenderPrivateKey *ecdsa.PrivateKey `json:"-"`
 
 	// Indicates if bypass features specific to testing environments are enabled.
 	EnableTestEnvBypassFeatures bool `json:"enable_test_env_bypass_features"`
@@ -70,6 +303,111 @@ type GasOracleConfig struct {
 	MinGasPrice uint64 `json:"min_gas_price"`
 	// GasPriceDiff store the percentage of gas price difference.
 	GasPriceDiff uint64 `json:"gas_price_diff"`
+	// MaxGasPrice store the maximum gas price to set, no maximum when 0.
+	MaxGasPrice uint64 `json:"max_gas_price,omitempty"`
+	// MaxGasPriceStep store the maximum percentage of gas price change per update, no limit when 0.
+	MaxGasPriceStep uint64 `json:"max_gas_price_step,omitempty"`
+	// MaxStalenessSec forces an update once the last update is older, updates are only triggered by GasPriceDiff when 0.
+	MaxStalenessSec uint64 `json:"max_staleness_sec,omitempty"`
+	// BlobBaseFee enables relaying the L1 blob base fee along with the L1 base fee, only used by the L1 gas oracle.
+	BlobBaseFee *BlobBaseFeeConfig `json:"blob_base_fee,omitempty"`
+	// BaseFeeAggregation aggregates the L1 base fee readings of several sources before relaying, only used by the L1
+	// gas oracle. The base fee of the latest L1 block stored by the L1 watcher is relayed when nil.
+	BaseFeeAggregation *BaseFeeAggregationConfig `json:"base_fee_aggregation,omitempty"`
+}
+
+// Base fee source types.
+const (
+	// BaseFeeSourceLocal is the base fee of the latest L1 block stored by the L1 watcher from the local node.
+	BaseFeeSourceLocal = "local"
+	// BaseFeeSourceRPC is the base fee of the latest block header of an L1 eth node.
+	BaseFeeSourceRPC = "rpc"
+	// BaseFeeSourceHTTP is a base fee read from the JSON response of an external fee API.
+	BaseFeeSourceHTTP = "http"
+)
+
+// BaseFeeAggregationConfig The config for aggregating the L1 base fee of several sources. The weighted median of the
+// readings is taken, the readings deviating from it by more than MaxDeviation are rejected as outliers and the
+// weighted median of the remaining readings is relayed.
+type BaseFeeAggregationConfig struct {
+	Sources []*BaseFeeSourceConfig `json:"sources"`
+	// MaxDeviation store the maximum percentage of deviation of a reading from the median, in the unit of gas_price_diff.
+	MaxDeviation uint64 `json:"max_deviation"`
+	// MinSources is the number of readings which must remain after the outlier rejection, the update is skipped otherwise.
+	MinSources int `json:"min_sources"`
+}
+
+// BaseFeeSourceConfig The config of a source of the L1 base fee.
+type BaseFeeSourceConfig struct {
+	// Name labels the readings of the source in the logs and metrics.
+	Name string `json:"name"`
+	// Type is one of local, rpc and http.
+	Type string `json:"type"`
+	// Endpoint is the url of the eth node of rpc sources, of the fee api of http sources.
+	Endpoint string `json:"endpoint,omitempty"`
+	// ValuePath is the dot separated path of the base fee in the JSON response of http sources, e.g. "data.base_fee".
+	ValuePath string `json:"value_path,omitempty"`
+	// GweiValue is set when the base fee of http sources is given in gwei, it is given in wei otherwise.
+	GweiValue bool `json:"gwei_value,omitempty"`
+	// Weight is the weight of the readings of the source in the median.
+	Weight float64 `json:"weight"`
+	// TimeoutSec bounds the reading of the source, the source is skipped for the update on timeout.
+	TimeoutSec uint64 `json:"timeout_sec"`
+}
+
+func (c *BaseFeeAggregationConfig) validate() error {
+	if len(c.Sources) == 0 {
+		return errors.New("no base fee source")
+	}
+	if c.MinSources <= 0 || c.MinSources > len(c.Sources) {
+		return fmt.Errorf("min_sources %d must be in [1, %d]", c.MinSources, len(c.Sources))
+	}
+	names := make(map[string]struct{}, len(c.Sources))
+	for _, source := range c.Sources {
+		if source.Name == "" {
+			return errors.New("base fee source without name")
+		}
+		if _, exists := names[source.Name]; exists {
+			return fmt.Errorf("duplicated base fee source name: %s", source.Name)
+		}
+		names[source.Name] = struct{}{}
+		switch source.Type {
+		case BaseFeeSourceLocal:
+		case BaseFeeSourceRPC, BaseFeeSourceHTTP:
+			if source.Endpoint == "" {
+				return fmt.Errorf("base fee source %s requires an endpoint", source.Name)
+			}
+			if source.TimeoutSec == 0 {
+				return fmt.Errorf("base fee source %s requires a positive timeout_sec", source.Name)
+			}
+		default:
+			return fmt.Errorf("invalid type of base fee source %s: %q", source.Name, source.Type)
+		}
+		if source.Type == BaseFeeSourceHTTP && source.ValuePath == "" {
+			return fmt.Errorf("base fee source %s requires a value_path", source.Name)
+		}
+		if source.Weight <= 0 {
+			return fmt.Errorf("base fee source %s requires a positive weight", source.Name)
+		}
+	}
+	return nil
+}
+
+// BlobBaseFeeConfig The config for relaying the L1 blob base fee to the L1 gas price oracle.
+type BlobBaseFeeConfig struct {
+	// MinBlobBaseFee store the minimum blob base fee to set.
+	MinBlobBaseFee uint64 `json:"min_blob_base_fee"`
+	// BlobBaseFeeDiff store the percentage of blob base fee difference.
+	BlobBaseFeeDiff uint64 `json:"blob_base_fee_diff"`
+	// MaxBlobBaseFee store the maximum blob base fee to set, no maximum when 0.
+	MaxBlobBaseFee uint64 `json:"max_blob_base_fee,omitempty"`
+	// MaxBlobBaseFeeStep store the maximum percentage of blob base fee change per update, no limit when 0.
+	MaxBlobBaseFeeStep uint64 `json:"max_blob_base_fee_step,omitempty"`
+	// MaxStalenessSec forces an update once the last update is older, updates are only triggered by BlobBaseFeeDiff when 0.
+	MaxStalenessSec uint64 `json:"max_staleness_sec,omitempty"`
+	// EWMAAlpha is the weight of the latest blob base fee in its exponentially weighted moving average, in (0, 1].
+	// The blob base fee moves faster than the base fee, the smoothed value is relayed, 1 relays the latest value.
+	EWMAAlpha float64 `json:"ewma_alpha"`
 }
 
 // relayerConfigAlias RelayerConfig alias name
@@ -80,6 +418,12 @@ func convertAndCheck(key string, uniqueAddressesSet map[string]struct{}) (*ecdsa
 		return nil, nil
 	}
 
+	// the private keys are resolved once on start, a rotated key requires a restart.
+	key, err := secrets.Resolve(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+
 	privKey, err := crypto.ToECDSA(common.FromHex(key))
 	if err != nil {
 		return nil, err
@@ -101,6 +445,8 @@ func (r *RelayerConfig) UnmarshalJSON(input []byte) error {
 		GasOracleSenderPrivateKey string `json:"gas_oracle_sender_private_key"`
 		CommitSenderPrivateKey    string `json:"commit_sender_private_key"`
 		FinalizeSenderPrivateKey  string `json:"finalize_sender_private_key"`
+		// The private key replaying skipped L1 messages, optional
+		ReplayMessageSenderPrivateKey string `json:"replay_message_sender_private_key,omitempty"`
 	}
 	var err error
 	if err = json.Unmarshal(input, &privateKeysConfig); err != nil {
@@ -126,6 +472,11 @@ func (r *RelayerConfig) UnmarshalJSON(input []byte) error {
 		return fmt.Errorf("error converting and checking finalize sender private key: %w", err)
 	}
 
+	r.ReplayMessageSenderPrivateKey, err = convertAndCheck(privateKeysConfig.ReplayMessageSenderPrivateKey, uniqueAddressesSet)
+	if err != nil {
+		return fmt.Errorf("error converting and checking replay message sender private key: %w", err)
+	}
+
 	return nil
 }
 
@@ -137,12 +488,17 @@ func (r *RelayerConfig) MarshalJSON() ([]byte, error) {
 		GasOracleSenderPrivateKey string `json:"gas_oracle_sender_private_key"`
 		CommitSenderPrivateKey    string `json:"commit_sender_private_key"`
 		FinalizeSenderPrivateKey  string `json:"finalize_sender_private_key"`
+		// The private key replaying skipped L1 messages, optional
+		ReplayMessageSenderPrivateKey string `json:"replay_message_sender_private_key,omitempty"`
 	}{}
 
 	privateKeysConfig.relayerConfigAlias = relayerConfigAlias(*r)
 	privateKeysConfig.GasOracleSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.GasOracleSenderPrivateKey))
 	privateKeysConfig.CommitSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.CommitSenderPrivateKey))
 	privateKeysConfig.FinalizeSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.FinalizeSenderPrivateKey))
+	if r.ReplayMessageSenderPrivateKey != nil {
+		privateKeysConfig.ReplayMessageSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.ReplayMessageSenderPrivateKey))
+	}
 
 	return json.Marshal(&privateKeysConfig)
 }
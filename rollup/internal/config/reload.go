@@ -0,0 +1,267 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// tunableParams are the parameters changed by a reload, with the parameters below them. The other parameters require a
+// restart.
+var tunableParams = []string{
+	"l1_config.relayer_config.sender_config.escalate_blocks",
+	"l1_config.relayer_config.sender_config.escalate_multiple_num",
+	"l1_config.relayer_config.sender_config.escalate_multiple_den",
+	"l1_config.relayer_config.sender_config.max_gas_price",
+	"l1_config.relayer_config.sender_config.max_blob_gas_price",
+	"l2_config.relayer_config.sender_config.escalate_blocks",
+	"l2_config.relayer_config.sender_config.escalate_multiple_num",
+	"l2_config.relayer_config.sender_config.escalate_multiple_den",
+	"l2_config.relayer_config.sender_config.max_gas_price",
+	"l2_config.relayer_config.sender_config.max_blob_gas_price",
+	"l2_config.chunk_proposer_config.max_block_num_per_chunk",
+	"l2_config.chunk_proposer_config.max_tx_num_per_chunk",
+	"l2_config.chunk_proposer_config.max_l1_commit_gas_per_chunk",
+	"l2_config.chunk_proposer_config.max_l1_commit_calldata_size_per_chunk",
+	"l2_config.chunk_proposer_config.chunk_timeout_sec",
+	"l2_config.chunk_proposer_config.max_row_consumption_per_chunk",
+	"l2_config.chunk_proposer_config.gas_cost_increase_multiplier",
+	"l2_config.batch_proposer_config.max_chunk_num_per_batch",
+	"l2_config.batch_proposer_config.max_l1_commit_gas_per_batch",
+	"l2_config.batch_proposer_config.max_l1_commit_calldata_size_per_batch",
+	"l2_config.batch_proposer_config.batch_timeout_sec",
+	"l2_config.batch_proposer_config.gas_cost_increase_multiplier",
+	"log_config",
+}
+
+// ParamChange is a tunable parameter changed by a reload, the values are formatted as in the config file.
+type ParamChange struct {
+	Param string
+	Old   string
+	New   string
+}
+
+// Reloader reloads the config file on SIGHUP. The reloaded config is validated as on start, a config changing a
+// parameter which requires a restart is rejected. The accepted config replaces the current one and is passed to the
+// subscribers, which apply its tunable parameters.
+type Reloader struct {
+	file string
+	load func(file string) (*Config, error)
+
+	mu          sync.Mutex
+	current     *Config
+	subscribers []subscriber
+}
+
+type subscriber struct {
+	ctx context.Context
+	fn  func(cfg *Config)
+}
+
+// NewReloader returns a reloader of the file, cfg is the config loaded on start.
+func NewReloader(file string, cfg *Config) *Reloader {
+	return &Reloader{file: file, load: NewConfig, current: cfg}
+}
+
+// NewGasOracleReloader returns a reloader of the file of the gas_oracle binary, cfg is the config loaded on start.
+func NewGasOracleReloader(file string, cfg *Config) *Reloader {
+	return &Reloader{file: file, load: NewGasOracleConfig, current: cfg}
+}
+
+// Current returns the current config.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Subscribe adds a function called with the config accepted by each reload until ctx is done.
+func (r *Reloader) Subscribe(ctx context.Context, fn func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, subscriber{ctx: ctx, fn: fn})
+}
+
+// Reload loads the config file and replaces the current config if it is valid, it returns the changed parameters.
+func (r *Reloader) Reload() ([]ParamChange, error) {
+	cfg, err := r.load(r.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file: %w", err)
+	}
+	if err = cfg.validateTunables(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changes, restartParams, err := diffConfigs(r.current, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(restartParams) > 0 {
+		return nil, fmt.Errorf("changed parameters require a restart: %s", strings.Join(restartParams, ", "))
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	r.current = cfg
+	subscribers := r.subscribers[:0]
+	for _, s := range r.subscribers {
+		if s.ctx.Err() != nil {
+			continue
+		}
+		s.fn(cfg)
+		subscribers = append(subscribers, s)
+	}
+	r.subscribers = subscribers
+	return changes, nil
+}
+
+// Run reloads the config file on each SIGHUP until ctx is done.
+func (r *Reloader) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			changes, err := r.Reload()
+			if err != nil {
+				log.Error("config reload rejected, the current config is kept", "file", r.file, "err", err)
+				continue
+			}
+			if len(changes) == 0 {
+				log.Info("config reloaded, no parameter changed", "file", r.file)
+				continue
+			}
+			for _, change := range changes {
+				log.Info("config parameter changed", "param", change.Param, "old", change.Old, "new", change.New)
+			}
+			log.Info("config reloaded", "file", r.file, "changes", len(changes))
+		}
+	}
+}
+
+// validateTunables checks the tunable parameters which are only checked by the components on start.
+func (c *Config) validateTunables() error {
+	senders := map[string]*SenderConfig{}
+	if c.L1Config != nil && c.L1Config.RelayerConfig != nil {
+		senders["l1"] = c.L1Config.RelayerConfig.SenderConfig
+	}
+	if c.L2Config != nil && c.L2Config.RelayerConfig != nil {
+		senders["l2"] = c.L2Config.RelayerConfig.SenderConfig
+	}
+	for name, sender := range senders {
+		if sender == nil {
+			continue
+		}
+		if sender.EscalateMultipleNum <= sender.EscalateMultipleDen {
+			return fmt.Errorf("invalid %s sender configuration, escalate_multiple_num: %v, escalate_multiple_den: %v", name, sender.EscalateMultipleNum, sender.EscalateMultipleDen)
+		}
+		if sender.MaxGasPrice > 0 && (sender.MinGasPrice > sender.MaxGasPrice || sender.MinGasTipCap > sender.MaxGasPrice) {
+			return fmt.Errorf("invalid %s sender configuration, min_gas_price: %v, min_gas_tip_cap: %v, max_gas_price: %v", name, sender.MinGasPrice, sender.MinGasTipCap, sender.MaxGasPrice)
+		}
+	}
+	return nil
+}
+
+// diffConfigs returns the changed tunable parameters, and the changed parameters which require a restart. The values of
+// the latter are not returned as they may be secrets.
+func diffConfigs(prev, next *Config) ([]ParamChange, []string, error) {
+	oldParams, err := flattenConfig(prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	newParams, err := flattenConfig(next)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := make(map[string]struct{}, len(newParams))
+	for param := range oldParams {
+		params[param] = struct{}{}
+	}
+	for param := range newParams {
+		params[param] = struct{}{}
+	}
+	sorted := make([]string, 0, len(params))
+	for param := range params {
+		sorted = append(sorted, param)
+	}
+	sort.Strings(sorted)
+
+	var (
+		changes       []ParamChange
+		restartParams []string
+	)
+	for _, param := range sorted {
+		oldValue, newValue := oldParams[param], newParams[param]
+		if oldValue == newValue {
+			continue
+		}
+		if isTunable(param) {
+			changes = append(changes, ParamChange{Param: param, Old: oldValue, New: newValue})
+		} else {
+			restartParams = append(restartParams, param)
+		}
+	}
+	return changes, restartParams, nil
+}
+
+func isTunable(param string) bool {
+	for _, tunable := range tunableParams {
+		if param == tunable || strings.HasPrefix(param, tunable+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenConfig returns the json values of the config by their dotted path.
+func flattenConfig(cfg *Config) (map[string]string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var tree interface{}
+	if err = json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	params := make(map[string]string)
+	flatten("", tree, params)
+	return params, nil
+}
+
+func flatten(prefix string, value interface{}, params map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(join(key), child, params)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(join(fmt.Sprint(i)), child, params)
+		}
+	default:
+		data, _ := json.Marshal(v)
+		params[prefix] = string(data)
+	}
+}
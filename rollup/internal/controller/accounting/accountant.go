@@ -0,0 +1,279 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// ReceiptReader reads the receipts of the transactions of a chain.
+type ReceiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethTypes.Receipt, error)
+}
+
+// Accountant records the L1 fees paid to commit and finalize each batch and the L2 fees collected in its blocks.
+// Only the transactions which committed or finalized a batch are accounted, the fees of failed transactions are not.
+type Accountant struct {
+	ctx context.Context
+	cfg *config.BatchAccountingConfig
+
+	l1Client ReceiptReader
+	l2Client ReceiptReader
+
+	batchOrm           *orm.Batch
+	chunkOrm           *orm.Chunk
+	l2BlockOrm         *orm.L2Block
+	batchAccountingOrm *orm.BatchAccounting
+
+	metrics *accountantMetrics
+}
+
+// NewAccountant creates a new Accountant instance.
+func NewAccountant(ctx context.Context, cfg *config.BatchAccountingConfig, l1Client, l2Client ReceiptReader, db *gorm.DB, reg prometheus.Registerer) *Accountant {
+	log.Info("new batch accountant", "maxBatchesPerRun", cfg.MaxBatchesPerRun)
+
+	return &Accountant{
+		ctx:                ctx,
+		cfg:                cfg,
+		l1Client:           l1Client,
+		l2Client:           l2Client,
+		batchOrm:           orm.NewBatch(db),
+		chunkOrm:           orm.NewChunk(db),
+		l2BlockOrm:         orm.NewL2Block(db),
+		batchAccountingOrm: orm.NewBatchAccounting(db),
+		metrics:            initAccountantMetrics(reg),
+	}
+}
+
+// TryAccountBatches records the accounting of the committed batches not accounted yet, then the finalize fee of the
+// accounted batches finalized since.
+func (a *Accountant) TryAccountBatches() {
+	if err := a.accountCommittedBatches(); err != nil {
+		log.Error("failed to account committed batches", "err", err)
+	}
+	if err := a.accountFinalizedBatches(); err != nil {
+		log.Error("failed to account finalized batches", "err", err)
+	}
+}
+
+func (a *Accountant) accountCommittedBatches() error {
+	// the genesis batch carries no L2 fees.
+	startIndex := uint64(1)
+	latest, err := a.batchAccountingOrm.GetLatestBatchAccounting(a.ctx)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		startIndex = latest.BatchIndex + 1
+	}
+
+	// batches are committed in order, the committed batches following the latest accounting are consecutive.
+	batches, err := a.batchOrm.GetBatches(a.ctx, map[string]interface{}{
+		"index >= ?":         startIndex,
+		"rollup_status IN ?": []int{int(types.RollupCommitted), int(types.RollupFinalizing), int(types.RollupFinalized), int(types.RollupFinalizeFailed)},
+	}, nil, a.cfg.MaxBatchesPerRun)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range batches {
+		commitFee, commitBlobFee, err := a.l1FeeShare(batch.CommitTxHash, "commit_tx_hash = ?")
+		if err != nil {
+			return fmt.Errorf("failed to get commit fee of batch, index: %v, err: %w", batch.Index, err)
+		}
+		l2Fee, err := a.batchL2Fee(batch)
+		if err != nil {
+			return fmt.Errorf("failed to get l2 fee of batch, index: %v, err: %w", batch.Index, err)
+		}
+		margin := batchMargin(l2Fee, commitFee, commitBlobFee, new(big.Int))
+
+		accounting := &orm.BatchAccounting{
+			BatchIndex:    batch.Index,
+			BatchHash:     batch.Hash,
+			CommitTxHash:  batch.CommitTxHash,
+			CommitFee:     commitFee.String(),
+			CommitBlobFee: commitBlobFee.String(),
+			L2Fee:         l2Fee.String(),
+			Margin:        margin.String(),
+		}
+		if err := a.batchAccountingOrm.InsertBatchAccounting(a.ctx, accounting); err != nil {
+			return err
+		}
+
+		a.metrics.accountedBatchesTotal.Inc()
+		a.metrics.l1FeeTotal.WithLabelValues("commit").Add(weiToGwei(commitFee))
+		a.metrics.l1FeeTotal.WithLabelValues("commit_blob").Add(weiToGwei(commitBlobFee))
+		a.metrics.l2FeeTotal.Add(weiToGwei(l2Fee))
+		log.Info("accounted committed batch", "index", batch.Index, "commit fee", commitFee, "commit blob fee", commitBlobFee, "l2 fee", l2Fee)
+	}
+	return nil
+}
+
+func (a *Accountant) accountFinalizedBatches() error {
+	accountings, err := a.batchAccountingOrm.GetUnfinalizedBatchAccountings(a.ctx, a.cfg.MaxBatchesPerRun)
+	if err != nil {
+		return err
+	}
+
+	for _, accounting := range accountings {
+		batches, err := a.batchOrm.GetBatches(a.ctx, map[string]interface{}{"hash = ?": accounting.BatchHash}, nil, 1)
+		if err != nil {
+			return err
+		}
+		// batches are finalized in order, the following ones are not finalized either.
+		if len(batches) == 0 || types.RollupStatus(batches[0].RollupStatus) != types.RollupFinalized {
+			return nil
+		}
+		batch := batches[0]
+
+		finalizeFee, finalizeBlobFee, err := a.l1FeeShare(batch.FinalizeTxHash, "finalize_tx_hash = ?")
+		if err != nil {
+			return fmt.Errorf("failed to get finalize fee of batch, index: %v, err: %w", batch.Index, err)
+		}
+		finalizeFee.Add(finalizeFee, finalizeBlobFee)
+
+		// the l2 fee followed by the commit fees.
+		fees, err := parseWei(accounting.L2Fee, accounting.CommitFee, accounting.CommitBlobFee)
+		if err != nil {
+			return fmt.Errorf("invalid accounting of batch, index: %v, err: %w", accounting.BatchIndex, err)
+		}
+		margin := batchMargin(fees[0], fees[1], fees[2], finalizeFee)
+
+		if err := a.batchAccountingOrm.UpdateFinalizeFeeAndMargin(a.ctx, accounting.BatchHash, batch.FinalizeTxHash, finalizeFee.String(), margin.String()); err != nil {
+			return err
+		}
+
+		a.metrics.l1FeeTotal.WithLabelValues("finalize").Add(weiToGwei(finalizeFee))
+		a.metrics.batchMargin.Set(weiToGwei(margin))
+		if margin.Sign() < 0 {
+			a.metrics.unprofitableBatchesTotal.Inc()
+		}
+		log.Info("accounted finalized batch", "index", batch.Index, "finalize fee", finalizeFee, "margin", margin)
+	}
+	return nil
+}
+
+// l1FeeShare returns the share of the execution and blob fees of an L1 transaction paid by each of the batches it
+// commits or finalizes, the batches matching txHashQuery. The shares are rounded down.
+func (a *Accountant) l1FeeShare(txHash string, txHashQuery string) (*big.Int, *big.Int, error) {
+	receipt, err := a.l1Client.TransactionReceipt(a.ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get receipt of l1 transaction %v: %w", txHash, err)
+	}
+	batches, err := a.batchOrm.GetBatches(a.ctx, map[string]interface{}{txHashQuery: txHash}, nil, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	fee, blobFee := l1Fees(receipt)
+	return shareFee(fee, len(batches)), shareFee(blobFee, len(batches)), nil
+}
+
+// batchL2Fee returns the fees collected by the L2 transactions of the blocks of a batch.
+func (a *Accountant) batchL2Fee(batch *orm.Batch) (*big.Int, error) {
+	chunks, err := a.chunkOrm.GetChunksInRange(a.ctx, batch.StartChunkIndex, batch.EndChunkIndex)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := a.l2BlockOrm.GetL2BlocksInRange(a.ctx, chunks[0].StartBlockNumber, chunks[len(chunks)-1].EndBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return blocksL2Fee(a.ctx, a.l2Client, blocks)
+}
+
+// blocksL2Fee returns the fees collected by the L2 transactions of the blocks, the execution fee plus the L1 data fee
+// of each transaction. L1 messages pay no fee on L2.
+func blocksL2Fee(ctx context.Context, l2Client ReceiptReader, blocks []*encoding.Block) (*big.Int, error) {
+	total := new(big.Int)
+	for _, block := range blocks {
+		for _, tx := range block.Transactions {
+			if tx.Type == gethTypes.L1MessageTxType {
+				continue
+			}
+			receipt, err := l2Client.TransactionReceipt(ctx, common.HexToHash(tx.TxHash))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get receipt of l2 transaction %v: %w", tx.TxHash, err)
+			}
+			total.Add(total, l2Fee(tx, receipt))
+		}
+	}
+	return total, nil
+}
+
+// l2Fee returns the fee paid by an L2 transaction, the gas price of the transaction is used when the receipt does
+// not report the effective gas price.
+func l2Fee(tx *gethTypes.TransactionData, receipt *gethTypes.Receipt) *big.Int {
+	gasPrice := receipt.EffectiveGasPrice
+	if gasPrice == nil && tx.GasPrice != nil {
+		gasPrice = tx.GasPrice.ToInt()
+	}
+
+	fee := new(big.Int)
+	if gasPrice != nil {
+		fee.Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	}
+	if receipt.L1Fee != nil {
+		fee.Add(fee, receipt.L1Fee)
+	}
+	return fee
+}
+
+// l1Fees returns the execution fee and the blob fee paid by an L1 transaction.
+func l1Fees(receipt *gethTypes.Receipt) (*big.Int, *big.Int) {
+	fee, blobFee := new(big.Int), new(big.Int)
+	if receipt.EffectiveGasPrice != nil {
+		fee.Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	}
+	if receipt.BlobGasPrice != nil {
+		blobFee.Mul(receipt.BlobGasPrice, new(big.Int).SetUint64(receipt.BlobGasUsed))
+	}
+	return fee, blobFee
+}
+
+// shareFee returns the share of fee paid by each of n batches, rounded down.
+func shareFee(fee *big.Int, n int) *big.Int {
+	if n <= 1 {
+		return fee
+	}
+	return new(big.Int).Div(fee, big.NewInt(int64(n)))
+}
+
+// batchMargin returns the L2 fee minus the L1 fees of a batch.
+func batchMargin(l2Fee *big.Int, l1Fees ...*big.Int) *big.Int {
+	margin := new(big.Int).Set(l2Fee)
+	for _, fee := range l1Fees {
+		margin.Sub(margin, fee)
+	}
+	return margin
+}
+
+// parseWei parses the decimal fees stored in the database.
+func parseWei(fees ...string) ([]*big.Int, error) {
+	parsed := make([]*big.Int, len(fees))
+	for i, fee := range fees {
+		value, ok := new(big.Int).SetString(fee, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid fee: %q", fee)
+		}
+		parsed[i] = value
+	}
+	return parsed, nil
+}
+
+// weiToGwei converts a fee in wei to gwei, the metrics unit.
+func weiToGwei(wei *big.Int) float64 {
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9)).Float64()
+	return gwei
+}
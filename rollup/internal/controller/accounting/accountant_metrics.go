@@ -0,0 +1,49 @@
+package accounting
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type accountantMetrics struct {
+	accountedBatchesTotal    prometheus.Counter
+	unprofitableBatchesTotal prometheus.Counter
+	l1FeeTotal               *prometheus.CounterVec
+	l2FeeTotal               prometheus.Counter
+	batchMargin              prometheus.Gauge
+}
+
+var (
+	initAccountantMetricOnce sync.Once
+	accountantMetric         *accountantMetrics
+)
+
+func initAccountantMetrics(reg prometheus.Registerer) *accountantMetrics {
+	initAccountantMetricOnce.Do(func() {
+		accountantMetric = &accountantMetrics{
+			accountedBatchesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_batch_accounting_batches_total",
+				Help: "The total number of committed batches accounted",
+			}),
+			unprofitableBatchesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_batch_accounting_unprofitable_batches_total",
+				Help: "The total number of finalized batches whose l1 fees exceed their l2 fees",
+			}),
+			l1FeeTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_batch_accounting_l1_fee_gwei_total",
+				Help: "The total l1 fees paid for the accounted batches in gwei, by commit, commit_blob and finalize fee",
+			}, []string{"fee"}),
+			l2FeeTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_batch_accounting_l2_fee_gwei_total",
+				Help: "The total l2 fees collected in the blocks of the accounted batches in gwei",
+			}),
+			batchMargin: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_batch_accounting_margin_gwei",
+				Help: "The l2 fees minus the l1 fees of the latest finalized batch in gwei",
+			}),
+		}
+	})
+	return accountantMetric
+}
@@ -0,0 +1,82 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+)
+
+type mockReceiptReader map[common.Hash]*gethTypes.Receipt
+
+func (r mockReceiptReader) TransactionReceipt(_ context.Context, txHash common.Hash) (*gethTypes.Receipt, error) {
+	receipt, ok := r[txHash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return receipt, nil
+}
+
+func TestL1Fees(t *testing.T) {
+	fee, blobFee := l1Fees(&gethTypes.Receipt{
+		GasUsed:           100000,
+		EffectiveGasPrice: big.NewInt(30e9),
+		BlobGasUsed:       131072,
+		BlobGasPrice:      big.NewInt(2),
+	})
+	assert.Equal(t, big.NewInt(3e15), fee)
+	assert.Equal(t, big.NewInt(262144), blobFee)
+
+	// a transaction without blobs.
+	_, blobFee = l1Fees(&gethTypes.Receipt{GasUsed: 100000, EffectiveGasPrice: big.NewInt(30e9)})
+	assert.Equal(t, 0, blobFee.Sign())
+
+	// the fee of a commit group is split between its batches, rounded down.
+	assert.Equal(t, big.NewInt(33), shareFee(big.NewInt(100), 3))
+	assert.Equal(t, big.NewInt(100), shareFee(big.NewInt(100), 1))
+
+	assert.Equal(t, big.NewInt(-20), batchMargin(big.NewInt(100), big.NewInt(70), big.NewInt(30), big.NewInt(20)))
+}
+
+func TestBlocksL2Fee(t *testing.T) {
+	txHashes := []common.Hash{common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")}
+	blocks := []*encoding.Block{
+		{Transactions: []*gethTypes.TransactionData{
+			{Type: gethTypes.L1MessageTxType, TxHash: txHashes[0].Hex()},
+			{Type: gethTypes.DynamicFeeTxType, TxHash: txHashes[1].Hex()},
+		}},
+		{Transactions: []*gethTypes.TransactionData{
+			{Type: gethTypes.LegacyTxType, TxHash: txHashes[2].Hex(), GasPrice: (*hexutil.Big)(big.NewInt(5))},
+		}},
+	}
+	l2Client := mockReceiptReader{
+		txHashes[1]: {GasUsed: 21000, EffectiveGasPrice: big.NewInt(10), L1Fee: big.NewInt(1000)},
+		// the gas price of the transaction is used without an effective gas price.
+		txHashes[2]: {GasUsed: 50000, L1Fee: big.NewInt(2000)},
+	}
+
+	// the L1 message has no receipt in the mock, it is skipped.
+	fee, err := blocksL2Fee(context.Background(), l2Client, blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(21000*10+1000+50000*5+2000), fee)
+
+	delete(l2Client, txHashes[2])
+	_, err = blocksL2Fee(context.Background(), l2Client, blocks)
+	assert.Error(t, err)
+}
+
+func TestParseWei(t *testing.T) {
+	fees, err := parseWei("100", "-20", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(100), big.NewInt(-20), big.NewInt(0)}, fees)
+
+	_, err = parseWei("0x10")
+	assert.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const defaultPendingTransactionsLimit = 100
+
+// AdminController the sender admin api controller
+type AdminController struct {
+	senders   map[string]*sender.Sender
+	skipGuard *watcher.L1MessageSkipGuard
+
+	skippedL1MessageOrm *orm.SkippedL1Message
+}
+
+// NewAdminController create a sender admin controller, senders are addressed by service and name, the chunks and
+// batches held by skipGuard are approved on the controller.
+func NewAdminController(senders []*sender.Sender, skipGuard *watcher.L1MessageSkipGuard, db *gorm.DB) *AdminController {
+	ac := &AdminController{
+		senders:             make(map[string]*sender.Sender, len(senders)),
+		skipGuard:           skipGuard,
+		skippedL1MessageOrm: orm.NewSkippedL1Message(db),
+	}
+	for _, s := range senders {
+		ac.senders[senderKey(s.Service(), s.Name())] = s
+	}
+	return ac
+}
+
+func senderKey(service, name string) string {
+	return service + "/" + name
+}
+
+// SenderSchema is the status of a sender.
+type SenderSchema struct {
+	Service         string `json:"service"`
+	Name            string `json:"name"`
+	SenderType      string `json:"sender_type"`
+	Paused          bool   `json:"paused"`
+	QueueLength     int    `json:"queue_length"`
+	DeferredLength  int    `json:"deferred_length"`
+	DependentLength int    `json:"dependent_length"`
+	// EscalateMultipleNum and EscalateMultipleDen are the current escalate multiple, adapted to ReplacementStats if enabled.
+	EscalateMultipleNum uint64                   `json:"escalate_multiple_num"`
+	EscalateMultipleDen uint64                   `json:"escalate_multiple_den"`
+	ReplacementStats    []sender.ReplacementStat `json:"replacement_stats"`
+}
+
+// TransactionSchema is a pending or replaced transaction of a sender.
+type TransactionSchema struct {
+	ContextID         string     `json:"context_id"`
+	Hash              string     `json:"hash"`
+	Nonce             uint64     `json:"nonce"`
+	Status            string     `json:"status"`
+	SubmitBlockNumber uint64     `json:"submit_block_number"`
+	Replacements      uint64     `json:"replacements"`
+	Deadline          *time.Time `json:"deadline,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// ContextIDParameter is the parameter of the escalate and cancel apis.
+type ContextIDParameter struct {
+	ContextID string `form:"context_id" json:"context_id" binding:"required"`
+}
+
+// TransactionsParameter is the parameter of the transactions api.
+type TransactionsParameter struct {
+	Limit int `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// CancelSchema is the response of the cancel api.
+type CancelSchema struct {
+	// Dropped is true when the transaction was still queued or deferred and never broadcast.
+	Dropped bool `json:"dropped"`
+}
+
+// ListSenders lists the senders and their status
+func (ac *AdminController) ListSenders(ctx *gin.Context) {
+	senders := make([]SenderSchema, 0, len(ac.senders))
+	for _, s := range ac.senders {
+		multipleNum, multipleDen := s.EscalateMultiple()
+		senders = append(senders, SenderSchema{
+			Service:             s.Service(),
+			Name:                s.Name(),
+			SenderType:          s.SenderType().String(),
+			Paused:              s.IsPaused(),
+			QueueLength:         s.QueueLength(),
+			DeferredLength:      s.DeferredLength(),
+			DependentLength:     s.DependentLength(),
+			EscalateMultipleNum: multipleNum,
+			EscalateMultipleDen: multipleDen,
+			ReplacementStats:    s.ReplacementStats(),
+		})
+	}
+	types.RenderSuccess(ctx, senders)
+}
+
+// ListTransactions lists the pending and replaced transactions of a sender
+func (ac *AdminController) ListTransactions(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+
+	var param TransactionsParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultPendingTransactionsLimit
+	}
+
+	txs, err := s.GetPendingTransactions(param.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIGetTransactionsFailure, fmt.Errorf("failed to get pending transactions, err:%w", err))
+		return
+	}
+
+	result := make([]TransactionSchema, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, TransactionSchema{
+			ContextID:         tx.ContextID,
+			Hash:              tx.Hash,
+			Nonce:             tx.Nonce,
+			Status:            tx.Status.String(),
+			SubmitBlockNumber: tx.SubmitBlockNumber,
+			Replacements:      tx.Replacements,
+			Deadline:          tx.Deadline,
+			CreatedAt:         tx.CreatedAt,
+		})
+	}
+	types.RenderSuccess(ctx, result)
+}
+
+// Escalate forces the escalation of a pending transaction on the next check
+func (ac *AdminController) Escalate(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+
+	var param ContextIDParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	s.ForceEscalation(param.ContextID)
+	types.RenderSuccess(ctx, nil)
+}
+
+// Cancel cancels a queued, deferred or pending transaction
+func (ac *AdminController) Cancel(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+
+	var param ContextIDParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	types.RenderSuccess(ctx, CancelSchema{Dropped: s.CancelTransaction(param.ContextID)})
+}
+
+// ResumeStuckSchema is the response of the resume stuck api.
+type ResumeStuckSchema struct {
+	// Resumed is false when the context has no stuck transaction.
+	Resumed bool `json:"resumed"`
+}
+
+// ResumeStuck resumes the escalation of a stuck transaction
+func (ac *AdminController) ResumeStuck(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+
+	var param ContextIDParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	resumed, err := s.ResumeStuckTransaction(param.ContextID)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIResumeStuckFailure, fmt.Errorf("failed to resume stuck transaction, err:%w", err))
+		return
+	}
+	types.RenderSuccess(ctx, ResumeStuckSchema{Resumed: resumed})
+}
+
+// Pause stops a sender from issuing new transactions
+func (ac *AdminController) Pause(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+	s.Pause()
+	types.RenderSuccess(ctx, nil)
+}
+
+// Resume lets a paused sender issue new transactions again
+func (ac *AdminController) Resume(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+	s.Resume()
+	types.RenderSuccess(ctx, nil)
+}
+
+// SetFeeEstimator replaces the fee estimation strategy of a sender
+func (ac *AdminController) SetFeeEstimator(ctx *gin.Context) {
+	s, ok := ac.sender(ctx)
+	if !ok {
+		return
+	}
+
+	var param config.FeeEstimatorConfig
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	if err := s.SetFeeEstimator(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPISetFeeEstimatorFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// SkippedL1MessagesParameter is the parameter of the skipped l1 messages api.
+type SkippedL1MessagesParameter struct {
+	// Status filters the messages by SkippedL1MessageStatus, 0 lists all of them.
+	Status int `form:"status" json:"status" binding:"omitempty,min=0,max=4"`
+	Offset int `form:"offset" json:"offset" binding:"omitempty,min=0"`
+	Limit  int `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// ListSkippedL1Messages lists the L1 messages skipped by l2geth and the status of their replay
+func (ac *AdminController) ListSkippedL1Messages(ctx *gin.Context) {
+	var param SkippedL1MessagesParameter
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultPendingTransactionsLimit
+	}
+
+	messages, err := ac.skippedL1MessageOrm.GetSkippedL1Messages(ctx, types.SkippedL1MessageStatus(param.Status), param.Offset, param.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIGetSkippedL1MessagesFailure, fmt.Errorf("failed to get skipped l1 messages, err:%w", err))
+		return
+	}
+	types.RenderSuccess(ctx, messages)
+}
+
+// L1MessageSkipsParameter is the parameter of the l1 message skips approval api.
+type L1MessageSkipsParameter struct {
+	Kind string `form:"kind" json:"kind" binding:"required,oneof=chunk batch"`
+	// Start is the first block number of a chunk, or the first chunk index of a batch.
+	Start *uint64 `form:"start" json:"start" binding:"required"`
+}
+
+// ListHeldL1MessageSkips lists the chunk and batch proposals held for skipping more l1 messages than allowed
+func (ac *AdminController) ListHeldL1MessageSkips(ctx *gin.Context) {
+	types.RenderSuccess(ctx, ac.skipGuard.Held())
+}
+
+// ApproveL1MessageSkips lets a held chunk or batch proposal skip more l1 messages than allowed
+func (ac *AdminController) ApproveL1MessageSkips(ctx *gin.Context) {
+	var param L1MessageSkipsParameter
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	if err := ac.skipGuard.Approve(param.Kind, *param.Start); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIApproveL1MessageSkipsFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// GetLogLevels returns the current log levels, globally and per module
+func (ac *AdminController) GetLogLevels(ctx *gin.Context) {
+	types.RenderSuccess(ctx, utils.CurrentLogConfig())
+}
+
+// SetLogLevels changes the log levels at runtime, the modules missing from the parameter keep their level and a
+// module set to an empty level falls back to the global level
+func (ac *AdminController) SetLogLevels(ctx *gin.Context) {
+	var param utils.LogConfig
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPIParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	if err := utils.ApplyLogConfig(&param); err != nil {
+		types.RenderFailure(ctx, types.ErrAdminAPISetLogLevelsFailure, fmt.Errorf("failed to set log levels, err:%w", err))
+		return
+	}
+	log.Info("log levels changed by the admin api", "level", param.Level, "modules", param.Modules)
+	types.RenderSuccess(ctx, utils.CurrentLogConfig())
+}
+
+// sender returns the sender addressed by the service and name path parameters, it renders a failure when not found.
+func (ac *AdminController) sender(ctx *gin.Context) (*sender.Sender, bool) {
+	s, ok := ac.senders[senderKey(ctx.Param("service"), ctx.Param("name"))]
+	if !ok {
+		types.RenderFailure(ctx, types.ErrAdminAPISenderNotFound, fmt.Errorf("sender not found, service: %s, name: %s", ctx.Param("service"), ctx.Param("name")))
+		return nil, false
+	}
+	return s, true
+}
@@ -0,0 +1,140 @@
+package archiver
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	pendingTransactionTable = "pending_transaction"
+	l1MessageTable          = "l1_message"
+
+	// partitionsAhead is the number of months the partitions are created ahead, the rows of a month without partition
+	// land in the default partition, which then prevents that month partition from being created.
+	partitionsAhead = 3
+)
+
+// Archiver maintains the monthly partitions of pending_transaction and l1_message, and moves their rows which no
+// longer change to the archive tables once older than the retention window.
+type Archiver struct {
+	ctx context.Context
+	// cfg is nil when the rows are not archived.
+	cfg *config.ArchiverConfig
+
+	pendingTransactionOrm *orm.PendingTransaction
+	l1MessageOrm          *orm.L1Message
+	batchOrm              *orm.Batch
+	chunkOrm              *orm.Chunk
+	partitionedTables     []*orm.PartitionedTable
+
+	metrics *archiverMetrics
+}
+
+// NewArchiver creates a new Archiver instance, cfg is nil to only maintain the partitions.
+func NewArchiver(ctx context.Context, cfg *config.ArchiverConfig, db *gorm.DB, reg prometheus.Registerer) *Archiver {
+	if cfg != nil {
+		log.Info("new archiver", "retentionDays", cfg.RetentionDays, "batchSize", cfg.BatchSize)
+	}
+
+	return &Archiver{
+		ctx:                   ctx,
+		cfg:                   cfg,
+		pendingTransactionOrm: orm.NewPendingTransaction(db),
+		l1MessageOrm:          orm.NewL1Message(db),
+		batchOrm:              orm.NewBatch(db),
+		chunkOrm:              orm.NewChunk(db),
+		partitionedTables: []*orm.PartitionedTable{
+			orm.NewPartitionedTable(db, pendingTransactionTable),
+			orm.NewPartitionedTable(db, l1MessageTable),
+		},
+		metrics: initArchiverMetrics(reg),
+	}
+}
+
+// TryArchive creates the partitions of the coming months, then archives the rows older than the retention window and
+// drops the partitions they emptied.
+func (a *Archiver) TryArchive() {
+	now := time.Now()
+	for i, table := range []string{pendingTransactionTable, l1MessageTable} {
+		if err := a.partitionedTables[i].CreateMonthlyPartitions(a.ctx, now, now.AddDate(0, partitionsAhead, 0)); err != nil {
+			a.metrics.archiveFailuresTotal.WithLabelValues(table).Inc()
+			log.Error("failed to create monthly partitions", "table", table, "err", err)
+		}
+	}
+
+	if a.cfg == nil {
+		return
+	}
+	before := now.AddDate(0, 0, -int(a.cfg.RetentionDays))
+
+	if err := a.archivePendingTransactions(before); err != nil {
+		a.metrics.archiveFailuresTotal.WithLabelValues(pendingTransactionTable).Inc()
+		log.Error("failed to archive pending transactions", "before", before, "err", err)
+	}
+	if err := a.archiveL1Messages(before); err != nil {
+		a.metrics.archiveFailuresTotal.WithLabelValues(l1MessageTable).Inc()
+		log.Error("failed to archive l1 messages", "before", before, "err", err)
+	}
+
+	for i, table := range []string{pendingTransactionTable, l1MessageTable} {
+		dropped, err := a.partitionedTables[i].DropEmptyPartitionsBefore(a.ctx, before)
+		a.metrics.droppedPartitionsTotal.WithLabelValues(table).Add(float64(len(dropped)))
+		if len(dropped) > 0 {
+			log.Info("dropped archived partitions", "table", table, "partitions", dropped)
+		}
+		if err != nil {
+			a.metrics.archiveFailuresTotal.WithLabelValues(table).Inc()
+			log.Error("failed to drop archived partitions", "table", table, "err", err)
+		}
+	}
+}
+
+func (a *Archiver) archivePendingTransactions(before time.Time) error {
+	return a.archive(pendingTransactionTable, func() (int64, error) {
+		return a.pendingTransactionOrm.ArchiveTransactionsCreatedBefore(a.ctx, before, a.cfg.BatchSize)
+	})
+}
+
+func (a *Archiver) archiveL1Messages(before time.Time) error {
+	// the messages popped by the finalized batches are no longer read.
+	batches, err := a.batchOrm.GetBatches(a.ctx, map[string]interface{}{"rollup_status = ?": types.RollupFinalized}, []string{"index DESC"}, 1)
+	if err != nil {
+		return err
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+	chunks, err := a.chunkOrm.GetChunksInRange(a.ctx, batches[0].EndChunkIndex, batches[0].EndChunkIndex)
+	if err != nil {
+		return err
+	}
+	finalizedQueueIndex := chunks[0].TotalL1MessagesPoppedBefore + chunks[0].TotalL1MessagesPoppedInChunk
+
+	return a.archive(l1MessageTable, func() (int64, error) {
+		return a.l1MessageOrm.ArchiveL1MessagesCreatedBefore(a.ctx, before, finalizedQueueIndex, a.cfg.BatchSize)
+	})
+}
+
+// archive moves the rows batch by batch until a batch is not full.
+func (a *Archiver) archive(table string, archiveBatch func() (int64, error)) error {
+	for a.ctx.Err() == nil {
+		archived, err := archiveBatch()
+		if err != nil {
+			return err
+		}
+		a.metrics.archivedRowsTotal.WithLabelValues(table).Add(float64(archived))
+		if archived < int64(a.cfg.BatchSize) {
+			return nil
+		}
+	}
+	return a.ctx.Err()
+}
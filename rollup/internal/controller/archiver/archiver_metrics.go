@@ -0,0 +1,39 @@
+package archiver
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type archiverMetrics struct {
+	archivedRowsTotal      *prometheus.CounterVec
+	droppedPartitionsTotal *prometheus.CounterVec
+	archiveFailuresTotal   *prometheus.CounterVec
+}
+
+var (
+	initArchiverMetricOnce sync.Once
+	archiverMetric         *archiverMetrics
+)
+
+func initArchiverMetrics(reg prometheus.Registerer) *archiverMetrics {
+	initArchiverMetricOnce.Do(func() {
+		archiverMetric = &archiverMetrics{
+			archivedRowsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_archiver_archived_rows_total",
+				Help: "The total number of rows moved to the archive tables, by table",
+			}, []string{"table"}),
+			droppedPartitionsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_archiver_dropped_partitions_total",
+				Help: "The total number of emptied monthly partitions dropped, by table",
+			}, []string{"table"}),
+			archiveFailuresTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_archiver_failures_total",
+				Help: "The total number of failed partition maintenance and archival runs, by table",
+			}, []string{"table"}),
+		}
+	})
+	return archiverMetric
+}
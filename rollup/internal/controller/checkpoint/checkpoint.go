@@ -0,0 +1,179 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// Checkpointer records the progress of a service on graceful shutdown and verifies it on the next startup.
+// A service proposing chunks and batches also records the latest chunk and batch, and repairs the chunk and batch
+// hashes referenced by the blocks and chunks of the latest proposals on startup.
+type Checkpointer struct {
+	db      *gorm.DB
+	service string
+
+	// tracksProposals is set for the service proposing chunks and batches.
+	tracksProposals bool
+
+	checkpointOrm *orm.Checkpoint
+	l2BlockOrm    *orm.L2Block
+	chunkOrm      *orm.Chunk
+	batchOrm      *orm.Batch
+}
+
+// NewCheckpointer creates a new Checkpointer instance.
+func NewCheckpointer(db *gorm.DB, service string, tracksProposals bool) *Checkpointer {
+	return &Checkpointer{
+		db:              db,
+		service:         service,
+		tracksProposals: tracksProposals,
+		checkpointOrm:   orm.NewCheckpoint(db),
+		l2BlockOrm:      orm.NewL2Block(db),
+		chunkOrm:        orm.NewChunk(db),
+		batchOrm:        orm.NewBatch(db),
+	}
+}
+
+// Resume verifies the checkpoint recorded by the previous run and marks the service as running. It returns the
+// checkpoint to resume from, nil on the first run or when the checkpoint is not trusted, either because the previous
+// run did not shut down gracefully or because the recorded proposals changed since.
+func (c *Checkpointer) Resume(ctx context.Context) (*orm.Checkpoint, error) {
+	if c.tracksProposals {
+		if err := c.repairLatestProposals(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	checkpoint, err := c.checkpointOrm.GetCheckpoint(ctx, c.service)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		log.Info("no checkpoint recorded, starting from the database state", "service", c.service)
+		return nil, c.checkpointOrm.SaveCheckpoint(ctx, &orm.Checkpoint{Service: c.service})
+	}
+
+	if err = c.checkpointOrm.UpdateCleanShutdown(ctx, c.service, false); err != nil {
+		return nil, err
+	}
+
+	if !checkpoint.CleanShutdown {
+		log.Warn("previous run did not shut down gracefully, ignoring its checkpoint", "service", c.service, "recorded at", checkpoint.UpdatedAt)
+		return nil, nil
+	}
+
+	if c.tracksProposals {
+		verified, err := c.verifyProposals(ctx, checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		if !verified {
+			return nil, nil
+		}
+	}
+
+	log.Info("resuming from checkpoint", "service", c.service, "l1 block number", checkpoint.L1BlockNumber, "l2 block number", checkpoint.L2BlockNumber,
+		"chunk index", checkpoint.ChunkIndex, "batch index", checkpoint.BatchIndex, "recorded at", checkpoint.UpdatedAt)
+	return checkpoint, nil
+}
+
+// Record records the checkpoint of a graceful shutdown, it must be called once the loops of the service are drained.
+// l1BlockNumber is the last L1 block processed by the service, 0 if the service does not process L1 blocks.
+func (c *Checkpointer) Record(ctx context.Context, l1BlockNumber uint64) error {
+	checkpoint := &orm.Checkpoint{
+		Service:       c.service,
+		L1BlockNumber: l1BlockNumber,
+		CleanShutdown: true,
+	}
+
+	if c.tracksProposals {
+		l2BlockNumber, err := c.l2BlockOrm.GetL2BlocksLatestHeight(ctx)
+		if err != nil {
+			return err
+		}
+		checkpoint.L2BlockNumber = l2BlockNumber
+
+		chunk, err := c.chunkOrm.GetLatestChunk(ctx)
+		if err != nil {
+			return err
+		}
+		if chunk != nil {
+			checkpoint.ChunkIndex = chunk.Index
+			checkpoint.ChunkHash = chunk.Hash
+		}
+
+		batch, err := c.batchOrm.GetLatestBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if batch != nil {
+			checkpoint.BatchIndex = batch.Index
+			checkpoint.BatchHash = batch.Hash
+		}
+	}
+
+	if err := c.checkpointOrm.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return err
+	}
+	log.Info("recorded checkpoint", "service", c.service, "l1 block number", checkpoint.L1BlockNumber, "l2 block number", checkpoint.L2BlockNumber,
+		"chunk index", checkpoint.ChunkIndex, "batch index", checkpoint.BatchIndex)
+	return nil
+}
+
+// verifyProposals reports whether the chunk and batch recorded by the checkpoint are still stored.
+func (c *Checkpointer) verifyProposals(ctx context.Context, checkpoint *orm.Checkpoint) (bool, error) {
+	if checkpoint.ChunkHash != "" {
+		chunks, err := c.chunkOrm.GetChunksInRange(ctx, checkpoint.ChunkIndex, checkpoint.ChunkIndex)
+		if err != nil {
+			return false, err
+		}
+		if len(chunks) == 0 || chunks[0].Hash != checkpoint.ChunkHash {
+			log.Warn("chunk of checkpoint changed, ignoring the checkpoint", "service", c.service, "chunk index", checkpoint.ChunkIndex, "chunk hash", checkpoint.ChunkHash)
+			return false, nil
+		}
+	}
+
+	if checkpoint.BatchHash != "" {
+		batch, err := c.batchOrm.GetBatchByIndex(ctx, checkpoint.BatchIndex)
+		if err != nil {
+			return false, err
+		}
+		if batch == nil || batch.Hash != checkpoint.BatchHash {
+			log.Warn("batch of checkpoint changed, ignoring the checkpoint", "service", c.service, "batch index", checkpoint.BatchIndex, "batch hash", checkpoint.BatchHash)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// repairLatestProposals makes the blocks of the latest chunk and the chunks of the latest batch reference them,
+// which completes a proposal interrupted between writing the proposal and updating what it contains.
+func (c *Checkpointer) repairLatestProposals(ctx context.Context) error {
+	chunk, err := c.chunkOrm.GetLatestChunk(ctx)
+	if err != nil {
+		return err
+	}
+	batch, err := c.batchOrm.GetLatestBatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Transaction(func(dbTX *gorm.DB) error {
+		if chunk != nil {
+			if err := c.l2BlockOrm.UpdateChunkHashInRange(ctx, chunk.StartBlockNumber, chunk.EndBlockNumber, chunk.Hash, dbTX); err != nil {
+				return fmt.Errorf("failed to repair the chunk hash of the blocks of chunk %d: %w", chunk.Index, err)
+			}
+		}
+		if batch != nil {
+			if err := c.chunkOrm.UpdateBatchHashInRange(ctx, batch.StartChunkIndex, batch.EndChunkIndex, batch.Hash, dbTX); err != nil {
+				return fmt.Errorf("failed to repair the batch hash of the chunks of batch %d: %w", batch.Index, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,178 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultRetryIntervalSec     = 5
+	defaultHeartbeatIntervalSec = 5
+)
+
+// ErrLeadershipLost is returned by Run when the session holding the leader lock breaks.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// Elector elects one leader among the instances of a service sharing a database. The leader holds a Postgres
+// session-level advisory lock on a dedicated connection, Postgres releases the lock when that session ends,
+// which lets a standby instance take over when the leader dies.
+type Elector struct {
+	db                *gorm.DB
+	name              string
+	lockID            int64
+	retryInterval     time.Duration
+	heartbeatInterval time.Duration
+
+	isLeader     prometheus.Gauge
+	electedTotal prometheus.Counter
+}
+
+// NewElector creates a new Elector instance.
+func NewElector(db *gorm.DB, cfg *config.LeaderElectionConfig, name string, reg prometheus.Registerer) *Elector {
+	retryIntervalSec := cfg.RetryIntervalSec
+	if retryIntervalSec == 0 {
+		retryIntervalSec = defaultRetryIntervalSec
+	}
+	heartbeatIntervalSec := cfg.HeartbeatIntervalSec
+	if heartbeatIntervalSec == 0 {
+		heartbeatIntervalSec = defaultHeartbeatIntervalSec
+	}
+
+	return &Elector{
+		db:                db,
+		name:              name,
+		lockID:            cfg.LockID,
+		retryInterval:     time.Duration(retryIntervalSec) * time.Second,
+		heartbeatInterval: time.Duration(heartbeatIntervalSec) * time.Second,
+
+		isLeader: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name:        "leader_election_is_leader",
+			Help:        "Whether this instance is the leader, 1 for leader and 0 for standby.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		electedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name:        "leader_election_elected_total",
+			Help:        "The total number of times this instance became the leader.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+	}
+}
+
+// Run stays standby until this instance acquires the leader lock, then calls lead with a context which is canceled
+// once leadership ends, and holds the lock until ctx is done. It returns nil when ctx is done and ErrLeadershipLost
+// when the lock session breaks. State created by lead is not reused, the caller is expected to exit on
+// ErrLeadershipLost and to be restarted as a standby instance.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) error {
+	conn, err := e.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	if conn == nil {
+		return nil
+	}
+	defer e.release(conn)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	e.isLeader.Set(1)
+	defer e.isLeader.Set(0)
+	e.electedTotal.Inc()
+	log.Info("acquired leadership", "name", e.name, "lock id", e.lockID)
+
+	lead(leaderCtx)
+
+	ticker := time.NewTicker(e.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("releasing leadership", "name", e.name, "lock id", e.lockID)
+			return nil
+		case <-ticker.C:
+			if err := e.heartbeat(ctx, conn); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				log.Error("leader lock session broken, stepping down", "name", e.name, "lock id", e.lockID, "err", err)
+				return fmt.Errorf("%w: %v", ErrLeadershipLost, err)
+			}
+		}
+	}
+}
+
+// acquire blocks until the leader lock is acquired and returns the connection holding it, or nil once ctx is done.
+func (e *Elector) acquire(ctx context.Context) (*sql.Conn, error) {
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql db, err: %w", err)
+	}
+
+	log.Info("waiting for leadership", "name", e.name, "lock id", e.lockID)
+	for {
+		conn, acquired, err := e.tryAcquire(ctx, sqlDB)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil
+			}
+			log.Warn("failed to try acquiring leader lock", "name", e.name, "lock id", e.lockID, "err", err)
+		}
+		if acquired {
+			return conn, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+func (e *Elector) tryAcquire(ctx context.Context, sqlDB *sql.DB) (*sql.Conn, bool, error) {
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.lockID).Scan(&acquired); err != nil {
+		e.release(conn)
+		return nil, false, err
+	}
+	if !acquired {
+		// the lock is not held by this session, the connection can go back to the pool.
+		if err := conn.Close(); err != nil {
+			log.Warn("failed to close connection", "name", e.name, "err", err)
+		}
+		return nil, false, nil
+	}
+	return conn, true, nil
+}
+
+func (e *Elector) heartbeat(ctx context.Context, conn *sql.Conn) error {
+	heartbeatCtx, cancel := context.WithTimeout(ctx, e.heartbeatInterval)
+	defer cancel()
+	return conn.PingContext(heartbeatCtx)
+}
+
+// release closes the session of conn instead of returning it to the pool, which releases the lock it may hold.
+func (e *Elector) release(conn *sql.Conn) {
+	if err := conn.Raw(func(interface{}) error { return driver.ErrBadConn }); err != nil && !errors.Is(err, driver.ErrBadConn) {
+		log.Warn("failed to discard leader lock connection", "name", e.name, "err", err)
+	}
+	if err := conn.Close(); err != nil && !errors.Is(err, sql.ErrConnDone) {
+		log.Warn("failed to close leader lock connection", "name", e.name, "err", err)
+	}
+}
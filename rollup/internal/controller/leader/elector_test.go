@@ -0,0 +1,91 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/docker"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+var base *docker.App
+
+func TestMain(m *testing.M) {
+	base = docker.NewDockerApp()
+
+	m.Run()
+
+	base.Free()
+}
+
+func setupDB(t *testing.T) *gorm.DB {
+	db, err := database.InitDB(&database.Config{
+		DSN:        base.DBConfig.DSN,
+		DriverName: base.DBConfig.DriverName,
+		MaxOpenNum: base.DBConfig.MaxOpenNum,
+		MaxIdleNum: base.DBConfig.MaxIdleNum,
+	})
+	assert.NoError(t, err)
+	return db
+}
+
+func TestElector(t *testing.T) {
+	base.RunDBImage(t)
+
+	cfg := &config.LeaderElectionConfig{LockID: 1, RetryIntervalSec: 1, HeartbeatIntervalSec: 1}
+
+	db1 := setupDB(t)
+	defer database.CloseDB(db1)
+	db2 := setupDB(t)
+	defer database.CloseDB(db2)
+
+	elector1 := NewElector(db1, cfg, "elector_1", nil)
+	elector2 := NewElector(db2, cfg, "elector_2", nil)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	leader1 := make(chan context.Context, 1)
+	leader2 := make(chan context.Context, 1)
+	done1 := make(chan error, 1)
+	done2 := make(chan error, 1)
+
+	go func() { done1 <- elector1.Run(ctx1, func(ctx context.Context) { leader1 <- ctx }) }()
+	var leaderCtx1 context.Context
+	select {
+	case leaderCtx1 = <-leader1:
+	case <-time.After(10 * time.Second):
+		t.Fatal("first elector did not become leader")
+	}
+
+	go func() { done2 <- elector2.Run(ctx2, func(ctx context.Context) { leader2 <- ctx }) }()
+	select {
+	case <-leader2:
+		t.Fatal("second elector became leader while the first one holds the lock")
+	case <-time.After(3 * time.Second):
+	}
+
+	// the standby takes over once the leader steps down.
+	cancel1()
+	assert.NoError(t, <-done1)
+	assert.Error(t, leaderCtx1.Err())
+
+	var leaderCtx2 context.Context
+	select {
+	case leaderCtx2 = <-leader2:
+	case <-time.After(10 * time.Second):
+		t.Fatal("second elector did not take over")
+	}
+	assert.NoError(t, leaderCtx2.Err())
+
+	cancel2()
+	assert.NoError(t, <-done2)
+}
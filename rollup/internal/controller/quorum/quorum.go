@@ -0,0 +1,168 @@
+package quorum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// ErrNoQuorum is returned when not enough endpoints return the same result.
+var ErrNoQuorum = errors.New("l1 endpoints did not reach a quorum")
+
+// backend is the part of an L1 client queried through the quorum.
+type backend interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	Close()
+}
+
+// Client reads the L1 data feeding finalization from several endpoints and returns a result only when at least
+// threshold endpoints agree on it, so that a single malicious or buggy RPC provider cannot make the rollup act on
+// forged data. The disagreements are alerted even when the quorum is reached.
+type Client struct {
+	endpoints []string
+	backends  []backend
+	threshold int
+
+	metrics *quorumMetrics
+}
+
+// NewClient connects to the endpoints of the quorum configuration.
+func NewClient(cfg *config.QuorumConfig, reg prometheus.Registerer) (*Client, error) {
+	backends := make([]backend, 0, len(cfg.Endpoints))
+	for _, endpoint := range cfg.Endpoints {
+		client, err := ethclient.Dial(endpoint)
+		if err != nil {
+			for _, b := range backends {
+				b.Close()
+			}
+			return nil, fmt.Errorf("failed to connect l1 endpoint %s: %w", endpoint, err)
+		}
+		backends = append(backends, client)
+	}
+	return newClient(cfg.Endpoints, backends, cfg.Threshold, reg), nil
+}
+
+func newClient(endpoints []string, backends []backend, threshold int, reg prometheus.Registerer) *Client {
+	return &Client{
+		endpoints: endpoints,
+		backends:  backends,
+		threshold: threshold,
+		metrics:   initQuorumMetrics(reg),
+	}
+}
+
+// Close closes the connections to the endpoints.
+func (c *Client) Close() {
+	for _, b := range c.backends {
+		b.Close()
+	}
+}
+
+// FilterLogs returns the logs matching the query agreed on by the quorum. The query must be bounded by block numbers,
+// the endpoints may follow different heads otherwise.
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	result, err := c.agree(ctx, "eth_getLogs", func(ctx context.Context, b backend) (interface{}, []byte, error) {
+		logs, err := b.FilterLogs(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded, err := json.Marshal(logs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return logs, encoded, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]types.Log), nil
+}
+
+// CallContract returns the output of the call agreed on by the quorum.
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	result, err := c.agree(ctx, "eth_call", func(ctx context.Context, b backend) (interface{}, []byte, error) {
+		output, err := b.CallContract(ctx, msg, blockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		return output, output, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// agree runs read on every endpoint in parallel and returns the result whose encoding is returned by at least
+// threshold endpoints.
+func (c *Client) agree(ctx context.Context, method string, read func(ctx context.Context, b backend) (interface{}, []byte, error)) (interface{}, error) {
+	type response struct {
+		result interface{}
+		digest common.Hash
+		err    error
+	}
+	responses := make([]response, len(c.backends))
+
+	var wg sync.WaitGroup
+	for i, b := range c.backends {
+		wg.Add(1)
+		go func(i int, b backend) {
+			defer wg.Done()
+			result, encoded, err := read(ctx, b)
+			responses[i] = response{result: result, digest: crypto.Keccak256Hash(encoded), err: err}
+		}(i, b)
+	}
+	wg.Wait()
+
+	votes := make(map[common.Hash][]string)
+	var (
+		winner   common.Hash
+		answered int
+	)
+	for i, resp := range responses {
+		if resp.err != nil {
+			c.metrics.quorumEndpointFailureTotal.WithLabelValues(method).Inc()
+			log.Warn("l1 quorum endpoint failed", "method", method, "endpoint", c.endpoints[i], "err", resp.err)
+			continue
+		}
+		answered++
+		votes[resp.digest] = append(votes[resp.digest], c.endpoints[i])
+		if len(votes[resp.digest]) > len(votes[winner]) {
+			winner = resp.digest
+		}
+	}
+
+	if len(votes) > 1 {
+		c.metrics.quorumDisagreementTotal.WithLabelValues(method).Inc()
+		groups := make([][]string, 0, len(votes))
+		for _, endpoints := range votes {
+			groups = append(groups, endpoints)
+		}
+		log.Error("l1 endpoints disagree, check the rpc providers", "method", method, "endpoints by result", groups)
+	}
+
+	if len(votes[winner]) < c.threshold {
+		c.metrics.quorumNotReachedTotal.WithLabelValues(method).Inc()
+		return nil, fmt.Errorf("%w, method: %s, agreeing: %d, answered: %d, threshold: %d", ErrNoQuorum, method, len(votes[winner]), answered, c.threshold)
+	}
+	for _, resp := range responses {
+		if resp.err == nil && resp.digest == winner {
+			return resp.result, nil
+		}
+	}
+	return nil, ErrNoQuorum
+}
@@ -0,0 +1,39 @@
+package quorum
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type quorumMetrics struct {
+	quorumDisagreementTotal    *prometheus.CounterVec
+	quorumNotReachedTotal      *prometheus.CounterVec
+	quorumEndpointFailureTotal *prometheus.CounterVec
+}
+
+var (
+	initQuorumMetricOnce sync.Once
+	quorumMetric         *quorumMetrics
+)
+
+func initQuorumMetrics(reg prometheus.Registerer) *quorumMetrics {
+	initQuorumMetricOnce.Do(func() {
+		quorumMetric = &quorumMetrics{
+			quorumDisagreementTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_l1_quorum_disagreement_total",
+				Help: "The total number of l1 quorum reads whose endpoints returned different results",
+			}, []string{"method"}),
+			quorumNotReachedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_l1_quorum_not_reached_total",
+				Help: "The total number of l1 quorum reads rejected as too few endpoints agreed",
+			}, []string{"method"}),
+			quorumEndpointFailureTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_l1_quorum_endpoint_failure_total",
+				Help: "The total number of failed l1 quorum endpoint requests",
+			}, []string{"method"}),
+		}
+	})
+	return quorumMetric
+}
@@ -0,0 +1,71 @@
+package quorum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBackend struct {
+	output []byte
+	logs   []types.Log
+	err    error
+}
+
+func (m *mockBackend) FilterLogs(context.Context, ethereum.FilterQuery) ([]types.Log, error) {
+	return m.logs, m.err
+}
+
+func (m *mockBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return m.output, m.err
+}
+
+func (m *mockBackend) Close() {}
+
+func newMockClient(threshold int, backends ...backend) *Client {
+	endpoints := make([]string, len(backends))
+	for i := range backends {
+		endpoints[i] = string(rune('a' + i))
+	}
+	return newClient(endpoints, backends, threshold, nil)
+}
+
+func TestCallContract(t *testing.T) {
+	good, forged := []byte{1}, []byte{2}
+	failed := &mockBackend{err: errors.New("connection refused")}
+
+	// 2-of-3 endpoints agreeing reach the quorum, the forged result is outvoted.
+	output, err := newMockClient(2, &mockBackend{output: good}, &mockBackend{output: forged}, &mockBackend{output: good}).CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, good, output)
+
+	// A failed endpoint does not prevent the quorum of the others.
+	output, err = newMockClient(2, failed, &mockBackend{output: good}, &mockBackend{output: good}).CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, good, output)
+
+	// Without agreement, no result is returned.
+	_, err = newMockClient(2, failed, &mockBackend{output: forged}, &mockBackend{output: good}).CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	assert.ErrorIs(t, err, ErrNoQuorum)
+	_, err = newMockClient(2, failed, failed, &mockBackend{output: good}).CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	assert.ErrorIs(t, err, ErrNoQuorum)
+}
+
+func TestFilterLogs(t *testing.T) {
+	logs := []types.Log{{Address: common.HexToAddress("0x01"), BlockNumber: 10, TxHash: common.HexToHash("0x0a")}}
+	forged := []types.Log{{Address: common.HexToAddress("0x01"), BlockNumber: 10, TxHash: common.HexToHash("0x0b")}}
+
+	result, err := newMockClient(2, &mockBackend{logs: logs}, &mockBackend{logs: forged}, &mockBackend{logs: logs}).FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, logs, result)
+
+	// An endpoint omitting a log disagrees.
+	_, err = newMockClient(2, &mockBackend{logs: logs}, &mockBackend{}, &mockBackend{logs: forged}).FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, ErrNoQuorum)
+}
@@ -0,0 +1,283 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// baseFeeReader reads the latest L1 base fee of a source, localBaseFee is the base fee of the latest L1 block stored
+// by the L1 watcher.
+type baseFeeReader interface {
+	baseFee(ctx context.Context, localBaseFee uint64) (uint64, error)
+}
+
+// baseFeeSource is a configured source of the L1 base fee.
+type baseFeeSource struct {
+	name   string
+	weight float64
+	// timeout is 0 for the local source, which is not read over the network.
+	timeout time.Duration
+	reader  baseFeeReader
+}
+
+// baseFeeReading is the base fee read from a source.
+type baseFeeReading struct {
+	source  *baseFeeSource
+	baseFee uint64
+}
+
+// baseFeeAggregator reads the L1 base fee from several sources and selects the weighted median of the readings
+// which are not outliers.
+type baseFeeAggregator struct {
+	sources      []*baseFeeSource
+	maxDeviation uint64
+	minSources   int
+}
+
+func newBaseFeeAggregator(cfg *config.BaseFeeAggregationConfig) (*baseFeeAggregator, error) {
+	aggregator := &baseFeeAggregator{maxDeviation: cfg.MaxDeviation, minSources: cfg.MinSources}
+	for _, sourceCfg := range cfg.Sources {
+		source := &baseFeeSource{
+			name:    sourceCfg.Name,
+			weight:  sourceCfg.Weight,
+			timeout: time.Duration(sourceCfg.TimeoutSec) * time.Second,
+		}
+		switch sourceCfg.Type {
+		case config.BaseFeeSourceLocal:
+			source.reader = localBaseFeeReader{}
+			source.timeout = 0
+		case config.BaseFeeSourceRPC:
+			client, err := ethclient.Dial(sourceCfg.Endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to dial base fee source %s, err: %w", sourceCfg.Name, err)
+			}
+			source.reader = &rpcBaseFeeReader{client: client}
+		case config.BaseFeeSourceHTTP:
+			source.reader = newHTTPBaseFeeReader(sourceCfg.Endpoint, sourceCfg.ValuePath, sourceCfg.GweiValue)
+		default:
+			return nil, fmt.Errorf("invalid type of base fee source %s: %q", sourceCfg.Name, sourceCfg.Type)
+		}
+		aggregator.sources = append(aggregator.sources, source)
+	}
+	return aggregator, nil
+}
+
+// aggregate reads every source concurrently, each within its timeout, and returns the aggregated base fee.
+func (a *baseFeeAggregator) aggregate(ctx context.Context, localBaseFee uint64, metrics *l1RelayerMetrics) (uint64, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		readings []baseFeeReading
+	)
+	for _, source := range a.sources {
+		wg.Add(1)
+		go func(source *baseFeeSource) {
+			defer wg.Done()
+			readCtx := ctx
+			if source.timeout > 0 {
+				var cancel context.CancelFunc
+				readCtx, cancel = context.WithTimeout(ctx, source.timeout)
+				defer cancel()
+			}
+			baseFee, err := source.reader.baseFee(readCtx, localBaseFee)
+			if err != nil {
+				metrics.rollupL1RelayerBaseFeeSourceFailureTotal.WithLabelValues(source.name).Inc()
+				log.Warn("Failed to read l1 base fee from source", "source", source.name, "err", err)
+				return
+			}
+			metrics.rollupL1RelayerBaseFeeSourceReading.WithLabelValues(source.name).Set(float64(baseFee))
+			mu.Lock()
+			readings = append(readings, baseFeeReading{source: source, baseFee: baseFee})
+			mu.Unlock()
+		}(source)
+	}
+	wg.Wait()
+
+	baseFee, outliers, err := selectBaseFee(readings, a.maxDeviation, a.minSources)
+	for _, outlier := range outliers {
+		metrics.rollupL1RelayerBaseFeeSourceOutlierTotal.WithLabelValues(outlier.source.name).Inc()
+		log.Warn("Reject outlying l1 base fee reading", "source", outlier.source.name, "baseFee", outlier.baseFee)
+	}
+	if err != nil {
+		return 0, err
+	}
+	metrics.rollupL1RelayerAggregatedBaseFee.Set(float64(baseFee))
+	return baseFee, nil
+}
+
+// selectBaseFee returns the weighted median of the readings deviating from the weighted median of all the readings by
+// at most maxDeviation, along with the rejected readings. At least minSources readings must remain.
+func selectBaseFee(readings []baseFeeReading, maxDeviation uint64, minSources int) (uint64, []baseFeeReading, error) {
+	if len(readings) < minSources {
+		return 0, nil, fmt.Errorf("%d base fee readings, %d required", len(readings), minSources)
+	}
+	median := weightedMedian(readings)
+
+	var accepted, outliers []baseFeeReading
+	for _, reading := range readings {
+		if relativeDeviation(reading.baseFee, median) > maxDeviation {
+			outliers = append(outliers, reading)
+		} else {
+			accepted = append(accepted, reading)
+		}
+	}
+	if len(accepted) < minSources {
+		return 0, outliers, fmt.Errorf("%d base fee readings after rejecting %d outliers, %d required", len(accepted), len(outliers), minSources)
+	}
+	return weightedMedian(accepted), outliers, nil
+}
+
+// weightedMedian returns the lowest base fee of the readings reaching half the total weight, in base fee order.
+func weightedMedian(readings []baseFeeReading) uint64 {
+	sorted := make([]baseFeeReading, len(readings))
+	copy(sorted, readings)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].baseFee < sorted[j].baseFee })
+
+	var total float64
+	for _, reading := range sorted {
+		total += reading.source.weight
+	}
+	var cumulative float64
+	for _, reading := range sorted {
+		cumulative += reading.source.weight
+		if 2*cumulative >= total {
+			return reading.baseFee
+		}
+	}
+	return sorted[len(sorted)-1].baseFee
+}
+
+// relativeDeviation returns the relative deviation of value from reference, in gasPriceDiffPrecision units. Any value
+// but 0 deviates infinitely from a 0 reference.
+func relativeDeviation(value, reference uint64) uint64 {
+	if reference == 0 {
+		if value == 0 {
+			return 0
+		}
+		return ^uint64(0)
+	}
+	delta := new(big.Int).Sub(new(big.Int).SetUint64(value), new(big.Int).SetUint64(reference))
+	delta.Abs(delta)
+	delta.Mul(delta, big.NewInt(gasPriceDiffPrecision))
+	delta.Div(delta, new(big.Int).SetUint64(reference))
+	if !delta.IsUint64() {
+		return ^uint64(0)
+	}
+	return delta.Uint64()
+}
+
+type localBaseFeeReader struct{}
+
+func (localBaseFeeReader) baseFee(_ context.Context, localBaseFee uint64) (uint64, error) {
+	return localBaseFee, nil
+}
+
+type rpcBaseFeeReader struct {
+	client *ethclient.Client
+}
+
+func (r *rpcBaseFeeReader) baseFee(ctx context.Context, _ uint64) (uint64, error) {
+	header, err := r.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest header, err: %w", err)
+	}
+	if header.BaseFee == nil || !header.BaseFee.IsUint64() {
+		return 0, fmt.Errorf("invalid base fee of header %v: %v", header.Number, header.BaseFee)
+	}
+	return header.BaseFee.Uint64(), nil
+}
+
+// httpBaseFeeReader reads the base fee at a path of the JSON response of a fee api, e.g. "data.base_fee" or
+// "result.0.value", the value is a JSON number or a string.
+type httpBaseFeeReader struct {
+	client    *resty.Client
+	endpoint  string
+	valuePath []string
+	gweiValue bool
+}
+
+func newHTTPBaseFeeReader(endpoint, valuePath string, gweiValue bool) *httpBaseFeeReader {
+	return &httpBaseFeeReader{
+		client:    resty.New(),
+		endpoint:  endpoint,
+		valuePath: strings.Split(valuePath, "."),
+		gweiValue: gweiValue,
+	}
+}
+
+func (r *httpBaseFeeReader) baseFee(ctx context.Context, _ uint64) (uint64, error) {
+	resp, err := r.client.R().SetContext(ctx).SetHeader("Accept", "application/json").Get(r.endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("fee api request failed, err: %w", err)
+	}
+	if resp.IsError() {
+		return 0, fmt.Errorf("fee api request failed, status: %d", resp.StatusCode())
+	}
+	return parseBaseFee(resp.Body(), r.valuePath, r.gweiValue)
+}
+
+// parseBaseFee returns the base fee in wei at the path of the JSON document.
+func parseBaseFee(body []byte, valuePath []string, gweiValue bool) (uint64, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return 0, fmt.Errorf("failed to decode fee api response, err: %w", err)
+	}
+
+	for _, key := range valuePath {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			value = node[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return 0, fmt.Errorf("invalid index %q of fee api response", key)
+			}
+			value = node[index]
+		default:
+			value = nil
+		}
+		if value == nil {
+			return 0, fmt.Errorf("no %q in fee api response", strings.Join(valuePath, "."))
+		}
+	}
+
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	default:
+		return 0, fmt.Errorf("invalid base fee in fee api response: %v", value)
+	}
+	baseFee, ok := new(big.Float).SetPrec(256).SetString(text)
+	if !ok || baseFee.Sign() < 0 {
+		return 0, fmt.Errorf("invalid base fee in fee api response: %q", text)
+	}
+	if gweiValue {
+		baseFee.Mul(baseFee, big.NewFloat(1e9))
+	}
+	// rounded to the nearest wei.
+	wei, _ := baseFee.Add(baseFee, big.NewFloat(0.5)).Int(nil)
+	if !wei.IsUint64() {
+		return 0, errors.New("base fee in fee api response overflows")
+	}
+	return wei.Uint64(), nil
+}
@@ -0,0 +1,93 @@
+package relayer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestSelectBaseFee(t *testing.T) {
+	reading := func(baseFee uint64, weight float64) baseFeeReading {
+		return baseFeeReading{source: &baseFeeSource{name: "source", weight: weight}, baseFee: baseFee}
+	}
+
+	// The outlier is rejected, the median of the remaining readings is selected.
+	baseFee, outliers, err := selectBaseFee([]baseFeeReading{reading(100, 1), reading(104, 1), reading(1000, 1), reading(102, 1)}, 100000, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(102), baseFee)
+	assert.Len(t, outliers, 1)
+	assert.Equal(t, uint64(1000), outliers[0].baseFee)
+
+	// The weights move the median.
+	baseFee, outliers, err = selectBaseFee([]baseFeeReading{reading(100, 1), reading(104, 3), reading(102, 1)}, 100000, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(104), baseFee)
+	assert.Empty(t, outliers)
+
+	_, _, err = selectBaseFee([]baseFeeReading{reading(100, 1)}, 100000, 2)
+	assert.Error(t, err)
+	_, outliers, err = selectBaseFee([]baseFeeReading{reading(100, 1), reading(150, 1), reading(300, 1)}, 100000, 2)
+	assert.Error(t, err)
+	assert.Len(t, outliers, 2)
+}
+
+func TestParseBaseFee(t *testing.T) {
+	baseFee, err := parseBaseFee([]byte(`{"data":{"base_fee":"12000000000"}}`), []string{"data", "base_fee"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12000000000), baseFee)
+
+	baseFee, err = parseBaseFee([]byte(`{"result":[{"value":12.345678901}]}`), []string{"result", "0", "value"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12345678901), baseFee)
+
+	_, err = parseBaseFee([]byte(`{"data":{}}`), []string{"data", "base_fee"}, false)
+	assert.Error(t, err)
+	_, err = parseBaseFee([]byte(`{"data":[1]}`), []string{"data", "1"}, false)
+	assert.Error(t, err)
+	_, err = parseBaseFee([]byte(`{"data":"-1"}`), []string{"data"}, false)
+	assert.Error(t, err)
+}
+
+func TestBaseFeeAggregator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fee":
+			_, _ = w.Write([]byte(`{"base_fee_gwei":"10.2"}`))
+		case "/slow":
+			time.Sleep(2 * time.Second)
+			_, _ = w.Write([]byte(`{"base_fee_gwei":"10.1"}`))
+		default:
+			_, _ = w.Write([]byte(`{"base_fee_gwei":"30"}`))
+		}
+	}))
+	defer srv.Close()
+
+	aggregator, err := newBaseFeeAggregator(&config.BaseFeeAggregationConfig{
+		Sources: []*config.BaseFeeSourceConfig{
+			{Name: "local", Type: config.BaseFeeSourceLocal, Weight: 2},
+			{Name: "fee", Type: config.BaseFeeSourceHTTP, Endpoint: srv.URL + "/fee", ValuePath: "base_fee_gwei", GweiValue: true, Weight: 1, TimeoutSec: 1},
+			{Name: "outlier", Type: config.BaseFeeSourceHTTP, Endpoint: srv.URL + "/outlier", ValuePath: "base_fee_gwei", GweiValue: true, Weight: 1, TimeoutSec: 1},
+			{Name: "slow", Type: config.BaseFeeSourceHTTP, Endpoint: srv.URL + "/slow", ValuePath: "base_fee_gwei", GweiValue: true, Weight: 1, TimeoutSec: 1},
+		},
+		MaxDeviation: 100000,
+		MinSources:   2,
+	})
+	assert.NoError(t, err)
+
+	// The slow source times out and the outlier is rejected.
+	metrics := initL1RelayerMetrics(prometheus.NewRegistry())
+	baseFee, err := aggregator.aggregate(context.Background(), 10e9, metrics)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10e9), baseFee)
+
+	aggregator.minSources = 3
+	_, err = aggregator.aggregate(context.Background(), 10e9, metrics)
+	assert.Error(t, err)
+}
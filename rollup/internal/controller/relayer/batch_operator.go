@@ -0,0 +1,297 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// inspectedTransactionsLimit is the maximum number of listed transactions of each label of an inspected batch.
+const inspectedTransactionsLimit = 20
+
+// SkipStage is the stage of a batch completed outside of the relayer.
+type SkipStage string
+
+const (
+	// SkipStageCommit marks the batch committed.
+	SkipStageCommit SkipStage = "commit"
+	// SkipStageFinalize marks the batch finalized.
+	SkipStageFinalize SkipStage = "finalize"
+)
+
+// l1TransactionReader reads the layer1 transactions which skip a batch.
+type l1TransactionReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*gethTypes.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*gethTypes.Receipt, error)
+}
+
+// BatchOperator inspects the batches and applies the manual overrides of the operators during incidents, each
+// override is recorded along with its reason in the batch_override table.
+type BatchOperator struct {
+	db       *gorm.DB
+	l1Client l1TransactionReader
+
+	rollupContractAddress common.Address
+
+	batchOrm              *orm.Batch
+	chunkOrm              *orm.Chunk
+	pendingTransactionOrm *orm.PendingTransaction
+	batchOverrideOrm      *orm.BatchOverride
+}
+
+// NewBatchOperator creates a new BatchOperator instance, l1Client is only used to check the transactions of Skip and
+// may be nil otherwise.
+func NewBatchOperator(db *gorm.DB, l1Client l1TransactionReader, rollupContractAddress common.Address) *BatchOperator {
+	return &BatchOperator{
+		db:                    db,
+		l1Client:              l1Client,
+		rollupContractAddress: rollupContractAddress,
+		batchOrm:              orm.NewBatch(db),
+		chunkOrm:              orm.NewChunk(db),
+		pendingTransactionOrm: orm.NewPendingTransaction(db),
+		batchOverrideOrm:      orm.NewBatchOverride(db),
+	}
+}
+
+// BatchInspection is the state of a batch, its chunks and its layer1 transactions.
+type BatchInspection struct {
+	Index                uint64     `json:"index"`
+	Hash                 string     `json:"hash"`
+	CodecVersion         int16      `json:"codec_version"`
+	RollupStatus         string     `json:"rollup_status"`
+	ProvingStatus        string     `json:"proving_status"`
+	ChunkProofsStatus    string     `json:"chunk_proofs_status"`
+	DAVerificationStatus string     `json:"da_verification_status"`
+	HasProof             bool       `json:"has_proof"`
+	CommitTxHash         string     `json:"commit_tx_hash"`
+	CommittedAt          *time.Time `json:"committed_at"`
+	FinalizeTxHash       string     `json:"finalize_tx_hash"`
+	FinalizedAt          *time.Time `json:"finalized_at"`
+
+	Chunks       []ChunkInspection       `json:"chunks"`
+	Transactions []TransactionInspection `json:"transactions"`
+	Overrides    []*orm.BatchOverride    `json:"overrides"`
+}
+
+// ChunkInspection is the state of a chunk of an inspected batch.
+type ChunkInspection struct {
+	Index            uint64 `json:"index"`
+	Hash             string `json:"hash"`
+	StartBlockNumber uint64 `json:"start_block_number"`
+	EndBlockNumber   uint64 `json:"end_block_number"`
+	ProvingStatus    string `json:"proving_status"`
+}
+
+// TransactionInspection is a layer1 transaction sent for an inspected batch.
+type TransactionInspection struct {
+	Hash               string            `json:"hash"`
+	SenderType         string            `json:"sender_type"`
+	Status             string            `json:"status"`
+	Nonce              uint64            `json:"nonce"`
+	Replacements       uint64            `json:"replacements"`
+	InclusionBlockHash string            `json:"inclusion_block_hash"`
+	Metadata           map[string]string `json:"metadata"`
+	CreatedAt          time.Time         `json:"created_at"`
+}
+
+// Inspect returns the state of the batch of the given index.
+func (o *BatchOperator) Inspect(ctx context.Context, index uint64) (*BatchInspection, error) {
+	batch, err := o.batchOrm.GetBatchByIndex(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection := &BatchInspection{
+		Index:                batch.Index,
+		Hash:                 batch.Hash,
+		CodecVersion:         batch.CodecVersion,
+		RollupStatus:         types.RollupStatus(batch.RollupStatus).String(),
+		ProvingStatus:        types.ProvingStatus(batch.ProvingStatus).String(),
+		ChunkProofsStatus:    types.ChunkProofsStatus(batch.ChunkProofsStatus).String(),
+		DAVerificationStatus: types.DAVerificationStatus(batch.DAVerificationStatus).String(),
+		HasProof:             len(batch.Proof) > 0,
+		CommitTxHash:         batch.CommitTxHash,
+		CommittedAt:          batch.CommittedAt,
+		FinalizeTxHash:       batch.FinalizeTxHash,
+		FinalizedAt:          batch.FinalizedAt,
+	}
+
+	chunks, err := o.chunkOrm.GetChunksInRange(ctx, batch.StartChunkIndex, batch.EndChunkIndex)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range chunks {
+		inspection.Chunks = append(inspection.Chunks, ChunkInspection{
+			Index:            chunk.Index,
+			Hash:             chunk.Hash,
+			StartBlockNumber: chunk.StartBlockNumber,
+			EndBlockNumber:   chunk.EndBlockNumber,
+			ProvingStatus:    types.ProvingStatus(chunk.ProvingStatus).String(),
+		})
+	}
+
+	// The commit and finalize transactions are labeled with the batch hash.
+	txs, err := o.pendingTransactionOrm.GetTransactionsByLabels(ctx, map[string]string{TxLabelBatchHash: batch.Hash}, inspectedTransactionsLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		inspection.Transactions = append(inspection.Transactions, TransactionInspection{
+			Hash:               tx.Hash,
+			SenderType:         tx.SenderType.String(),
+			Status:             tx.Status.String(),
+			Nonce:              tx.Nonce,
+			Replacements:       tx.Replacements,
+			InclusionBlockHash: tx.InclusionBlockHash,
+			Metadata:           tx.Metadata,
+			CreatedAt:          tx.CreatedAt,
+		})
+	}
+
+	if inspection.Overrides, err = o.batchOverrideOrm.GetBatchOverridesByIndex(ctx, index); err != nil {
+		return nil, err
+	}
+	return inspection, nil
+}
+
+// Recommit resets the batch of the given index to pending, along with the later batches whose commit is in flight or
+// failed, so that the relayer commits them again. The batch commit must be in flight or failed. It returns the number
+// of reset batches. The transactions still pending in the sender are not cancelled.
+func (o *BatchOperator) Recommit(ctx context.Context, index uint64, operator, reason string) (int64, error) {
+	batch, err := o.getOverriddenBatch(ctx, index, reason)
+	if err != nil {
+		return 0, err
+	}
+	status := types.RollupStatus(batch.RollupStatus)
+	if status != types.RollupCommitting && status != types.RollupCommitFailed {
+		return 0, fmt.Errorf("batch %d is %s, only a committing or commit failed batch is committed again", index, status)
+	}
+
+	var reset int64
+	err = o.db.Transaction(func(dbTX *gorm.DB) error {
+		var err error
+		if reset, err = o.batchOrm.ResetUncommittedBatchesGEIndex(ctx, index, dbTX); err != nil {
+			return err
+		}
+		return o.batchOverrideOrm.InsertBatchOverride(ctx, newBatchOverride(batch, orm.BatchOverrideActionRecommit, operator, reason, ""), dbTX)
+	})
+	return reset, err
+}
+
+// InvalidateProof drops the proof of the batch of the given index so that the coordinator proves it again. The proof
+// of a batch finalizing or finalized on layer1 is not invalidated.
+func (o *BatchOperator) InvalidateProof(ctx context.Context, index uint64, operator, reason string) error {
+	batch, err := o.getOverriddenBatch(ctx, index, reason)
+	if err != nil {
+		return err
+	}
+	if status := types.RollupStatus(batch.RollupStatus); status == types.RollupFinalizing || status == types.RollupFinalized {
+		return fmt.Errorf("batch %d is %s, its proof was submitted to layer1", index, status)
+	}
+
+	return o.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := o.batchOrm.ResetProof(ctx, batch.Hash, dbTX); err != nil {
+			return err
+		}
+		return o.batchOverrideOrm.InsertBatchOverride(ctx, newBatchOverride(batch, orm.BatchOverrideActionInvalidateProof, operator, reason, ""), dbTX)
+	})
+}
+
+// Skip marks the batch of the given index committed or finalized by the given layer1 transaction, sent outside of the
+// relayer, so that the relayer skips the stage of the batch. The transaction must have succeeded and be sent to the
+// rollup contract.
+func (o *BatchOperator) Skip(ctx context.Context, index uint64, stage SkipStage, txHash common.Hash, operator, reason string) error {
+	batch, err := o.getOverriddenBatch(ctx, index, reason)
+	if err != nil {
+		return err
+	}
+
+	status := types.RollupStatus(batch.RollupStatus)
+	var action string
+	switch stage {
+	case SkipStageCommit:
+		if status != types.RollupPending && status != types.RollupCommitting && status != types.RollupCommitFailed {
+			return fmt.Errorf("batch %d is %s, only an uncommitted batch skips its commit", index, status)
+		}
+		action = orm.BatchOverrideActionSkipCommit
+	case SkipStageFinalize:
+		if status != types.RollupCommitted && status != types.RollupFinalizing && status != types.RollupFinalizeFailed {
+			return fmt.Errorf("batch %d is %s, only a committed and unfinalized batch skips its finalization", index, status)
+		}
+		action = orm.BatchOverrideActionSkipFinalize
+	default:
+		return fmt.Errorf("unknown skip stage: %v", stage)
+	}
+
+	if err = o.checkSkipTransaction(ctx, txHash); err != nil {
+		return err
+	}
+
+	return o.db.Transaction(func(dbTX *gorm.DB) error {
+		switch {
+		case stage == SkipStageCommit:
+			if err := o.batchOrm.UpdateCommitTxHashAndRollupStatus(ctx, batch.Hash, txHash.String(), types.RollupCommitted, dbTX); err != nil {
+				return err
+			}
+		default:
+			if err := o.batchOrm.UpdateFinalizeTxHashAndRollupStatus(ctx, batch.Hash, txHash.String(), types.RollupFinalized, dbTX); err != nil {
+				return err
+			}
+		}
+		return o.batchOverrideOrm.InsertBatchOverride(ctx, newBatchOverride(batch, action, operator, reason, txHash.String()), dbTX)
+	})
+}
+
+// getOverriddenBatch returns the batch of the given index, an error if the override has no reason.
+func (o *BatchOperator) getOverriddenBatch(ctx context.Context, index uint64, reason string) (*orm.Batch, error) {
+	if reason == "" {
+		return nil, errors.New("the reason of the override is required")
+	}
+	return o.batchOrm.GetBatchByIndex(ctx, index)
+}
+
+func (o *BatchOperator) checkSkipTransaction(ctx context.Context, txHash common.Hash) error {
+	if o.l1Client == nil {
+		return errors.New("no layer1 client to check the skip transaction")
+	}
+	tx, isPending, err := o.l1Client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to get the skip transaction %s: %w", txHash, err)
+	}
+	if isPending {
+		return fmt.Errorf("the skip transaction %s is pending", txHash)
+	}
+	if tx.To() == nil || *tx.To() != o.rollupContractAddress {
+		return fmt.Errorf("the skip transaction %s is not sent to the rollup contract %s", txHash, o.rollupContractAddress)
+	}
+	receipt, err := o.l1Client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to get the receipt of the skip transaction %s: %w", txHash, err)
+	}
+	if receipt.Status != gethTypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("the skip transaction %s failed in block %v", txHash, receipt.BlockNumber)
+	}
+	return nil
+}
+
+func newBatchOverride(batch *orm.Batch, action, operator, reason, txHash string) *orm.BatchOverride {
+	return &orm.BatchOverride{
+		BatchIndex:            batch.Index,
+		BatchHash:             batch.Hash,
+		Action:                action,
+		Reason:                reason,
+		Operator:              operator,
+		PreviousRollupStatus:  batch.RollupStatus,
+		PreviousProvingStatus: batch.ProvingStatus,
+		TxHash:                txHash,
+	}
+}
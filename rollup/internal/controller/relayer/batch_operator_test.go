@@ -0,0 +1,113 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv0"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+type mockL1TransactionReader struct {
+	to     common.Address
+	status uint64
+}
+
+func (m *mockL1TransactionReader) TransactionByHash(_ context.Context, _ common.Hash) (*gethTypes.Transaction, bool, error) {
+	return gethTypes.NewTx(&gethTypes.DynamicFeeTx{To: &m.to}), false, nil
+}
+
+func (m *mockL1TransactionReader) TransactionReceipt(_ context.Context, _ common.Hash) (*gethTypes.Receipt, error) {
+	return &gethTypes.Receipt{Status: m.status, BlockNumber: big.NewInt(1)}, nil
+}
+
+func testBatchOperator(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	ctx := context.Background()
+	err := orm.NewL2Block(db).InsertL2Blocks(ctx, []*encoding.Block{block1, block2})
+	assert.NoError(t, err)
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(ctx, chunk1)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(ctx, chunk2)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	dbBatch, err := batchOrm.InsertBatch(ctx, &encoding.Batch{
+		Index:           0,
+		ParentBatchHash: common.Hash{},
+		Chunks:          []*encoding.Chunk{chunk1, chunk2},
+		StartChunkIndex: 0,
+		StartChunkHash:  chunkHash1,
+		EndChunkIndex:   1,
+		EndChunkHash:    chunkHash2,
+	}, codecv0.CodecV0Version)
+	require.NoError(t, err)
+
+	rollupContract := common.HexToAddress("0x1234")
+	l1Client := &mockL1TransactionReader{to: rollupContract, status: gethTypes.ReceiptStatusSuccessful}
+	operator := NewBatchOperator(db, l1Client, rollupContract)
+
+	inspection, err := operator.Inspect(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, dbBatch.Hash, inspection.Hash)
+	assert.Equal(t, types.RollupPending.String(), inspection.RollupStatus)
+	assert.Len(t, inspection.Chunks, 2)
+
+	// the overrides are audited with their reason.
+	_, err = operator.Recommit(ctx, 0, "operator", "")
+	assert.Error(t, err)
+	// a pending batch is committed by the relayer already.
+	_, err = operator.Recommit(ctx, 0, "operator", "stuck commit")
+	assert.Error(t, err)
+
+	assert.NoError(t, batchOrm.UpdateCommitTxHashAndRollupStatus(ctx, dbBatch.Hash, "0x01", types.RollupCommitting))
+	reset, err := operator.Recommit(ctx, 0, "operator", "stuck commit")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), reset)
+
+	// the skip transaction must succeed and be sent to the rollup contract.
+	txHash := common.HexToHash("0x02")
+	l1Client.status = gethTypes.ReceiptStatusFailed
+	assert.Error(t, operator.Skip(ctx, 0, SkipStageCommit, txHash, "operator", "committed manually"))
+	l1Client.status = gethTypes.ReceiptStatusSuccessful
+	l1Client.to = common.HexToAddress("0x5678")
+	assert.Error(t, operator.Skip(ctx, 0, SkipStageCommit, txHash, "operator", "committed manually"))
+	l1Client.to = rollupContract
+	assert.NoError(t, operator.Skip(ctx, 0, SkipStageCommit, txHash, "operator", "committed manually"))
+
+	assert.NoError(t, batchOrm.UpdateProvingStatus(ctx, dbBatch.Hash, types.ProvingTaskVerified))
+	assert.NoError(t, operator.InvalidateProof(ctx, 0, "operator", "invalid proof"))
+
+	assert.NoError(t, operator.Skip(ctx, 0, SkipStageFinalize, txHash, "operator", "finalized manually"))
+	// the proof of a finalized batch is kept.
+	assert.Error(t, operator.InvalidateProof(ctx, 0, "operator", "invalid proof"))
+
+	inspection, err = operator.Inspect(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, types.RollupFinalized.String(), inspection.RollupStatus)
+	assert.Equal(t, types.ProvingTaskUnassigned.String(), inspection.ProvingStatus)
+	assert.Equal(t, txHash.String(), inspection.CommitTxHash)
+	assert.Equal(t, txHash.String(), inspection.FinalizeTxHash)
+	require.Len(t, inspection.Overrides, 4)
+	assert.Equal(t, orm.BatchOverrideActionRecommit, inspection.Overrides[0].Action)
+	assert.Equal(t, int16(types.RollupCommitting), inspection.Overrides[0].PreviousRollupStatus)
+	assert.Equal(t, orm.BatchOverrideActionSkipCommit, inspection.Overrides[1].Action)
+	assert.Equal(t, orm.BatchOverrideActionInvalidateProof, inspection.Overrides[2].Action)
+	assert.Equal(t, int16(types.ProvingTaskVerified), inspection.Overrides[2].PreviousProvingStatus)
+	assert.Equal(t, orm.BatchOverrideActionSkipFinalize, inspection.Overrides[3].Action)
+	assert.Equal(t, txHash.String(), inspection.Overrides[3].TxHash)
+	assert.Equal(t, "finalized manually", inspection.Overrides[3].Reason)
+}
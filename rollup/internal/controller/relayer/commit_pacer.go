@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"time"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// pacedCommit is a commit transaction accounted by the commit pacer.
+type pacedCommit struct {
+	sentAt    time.Time
+	commitGas uint64
+}
+
+// commitPacer spreads the batch commits over a sliding window: a commit is held while the commits sent within the
+// window reach maxCommits or maxCommitGas. The commits are only tracked in memory, the window starts empty on restart.
+type commitPacer struct {
+	window time.Duration
+	// maxCommits and maxCommitGas are 0 when not bounded.
+	maxCommits   uint64
+	maxCommitGas uint64
+	// catchUpDelay and catchUpPendingBatches are 0 when disabled.
+	catchUpDelay          time.Duration
+	catchUpPendingBatches uint64
+
+	sent []pacedCommit
+}
+
+// newCommitPacer returns nil when the commits are not paced.
+func newCommitPacer(cfg *config.CommitPacingConfig) *commitPacer {
+	if cfg == nil {
+		return nil
+	}
+	return &commitPacer{
+		window:                time.Duration(cfg.WindowSec) * time.Second,
+		maxCommits:            cfg.MaxCommitsPerWindow,
+		maxCommitGas:          cfg.MaxCommitGasPerWindow,
+		catchUpDelay:          time.Duration(cfg.CatchUpDelaySec) * time.Second,
+		catchUpPendingBatches: cfg.CatchUpPendingBatches,
+	}
+}
+
+// isCatchingUp reports whether the pacing is bypassed to catch up with the backlog, given the creation time of the
+// oldest pending batch and the number of pending batches.
+func (p *commitPacer) isCatchingUp(oldestPendingAt time.Time, pendingBatches uint64, now time.Time) bool {
+	if p.catchUpDelay > 0 && now.Sub(oldestPendingAt) > p.catchUpDelay {
+		return true
+	}
+	return p.catchUpPendingBatches > 0 && pendingBatches >= p.catchUpPendingBatches
+}
+
+// allow reports whether a commit of commitGas can be sent at now. A commit is always allowed when the window is empty.
+func (p *commitPacer) allow(commitGas uint64, now time.Time) bool {
+	p.prune(now)
+	if len(p.sent) == 0 {
+		return true
+	}
+	if p.maxCommits > 0 && uint64(len(p.sent)) >= p.maxCommits {
+		return false
+	}
+	if p.maxCommitGas > 0 {
+		var windowGas uint64
+		for _, c := range p.sent {
+			windowGas += c.commitGas
+		}
+		if windowGas+commitGas > p.maxCommitGas {
+			return false
+		}
+	}
+	return true
+}
+
+// record accounts a commit sent at now, including the commits sent while catching up.
+func (p *commitPacer) record(commitGas uint64, now time.Time) {
+	p.prune(now)
+	p.sent = append(p.sent, pacedCommit{sentAt: now, commitGas: commitGas})
+}
+
+// prune drops the commits sent before the window.
+func (p *commitPacer) prune(now time.Time) {
+	i := 0
+	for i < len(p.sent) && now.Sub(p.sent[i].sentAt) >= p.window {
+		i++
+	}
+	p.sent = p.sent[i:]
+}
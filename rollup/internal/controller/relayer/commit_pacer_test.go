@@ -0,0 +1,46 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestCommitPacer(t *testing.T) {
+	assert.Nil(t, newCommitPacer(nil))
+
+	now := time.Now()
+	pacer := newCommitPacer(&config.CommitPacingConfig{WindowSec: 120, MaxCommitsPerWindow: 2, MaxCommitGasPerWindow: 1000})
+
+	// A commit exceeding the gas of the window alone is sent while the window is empty.
+	assert.True(t, pacer.allow(1500, now))
+	pacer.record(1500, now)
+	assert.False(t, pacer.allow(100, now.Add(time.Minute)))
+
+	// The window slides past the first commit.
+	assert.True(t, pacer.allow(600, now.Add(2*time.Minute)))
+	pacer.record(600, now.Add(2*time.Minute))
+	assert.False(t, pacer.allow(500, now.Add(3*time.Minute)))
+	assert.True(t, pacer.allow(400, now.Add(3*time.Minute)))
+	pacer.record(400, now.Add(3*time.Minute))
+
+	// At most 2 commits are sent within the window.
+	assert.False(t, pacer.allow(0, now.Add(3*time.Minute+30*time.Second)))
+	assert.True(t, pacer.allow(0, now.Add(5*time.Minute)))
+}
+
+func TestCommitPacerCatchUp(t *testing.T) {
+	now := time.Now()
+	pacer := newCommitPacer(&config.CommitPacingConfig{WindowSec: 120, MaxCommitsPerWindow: 1, CatchUpDelaySec: 600, CatchUpPendingBatches: 10})
+
+	assert.False(t, pacer.isCatchingUp(now.Add(-time.Minute), 9, now))
+	assert.True(t, pacer.isCatchingUp(now.Add(-11*time.Minute), 1, now))
+	assert.True(t, pacer.isCatchingUp(now, 10, now))
+
+	// the catch-up is disabled by default.
+	pacer = newCommitPacer(&config.CommitPacingConfig{WindowSec: 120, MaxCommitsPerWindow: 1})
+	assert.False(t, pacer.isCatchingUp(now.Add(-24*time.Hour), 1000, now))
+}
@@ -1,6 +1,12 @@
 package relayer
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"scroll-tech/rollup/internal/controller/sender"
+)
 
 const (
 	gasPriceDiffPrecision = 1000000
@@ -8,6 +14,18 @@ const (
 	defaultGasPriceDiff = 50000 // 5%
 )
 
+// The labels attached to the transactions of the relayers with sender.WithMetadata, values are decimal indexes and hex hashes.
+const (
+	// TxLabelBatchHash is the hash of the committed or finalized batch.
+	TxLabelBatchHash = "batch_hash"
+	// TxLabelBatchIndex is the index of the committed or finalized batch.
+	TxLabelBatchIndex = "batch_index"
+	// TxLabelMessageNonce is the queue index of the replayed L1 message.
+	TxLabelMessageNonce = "message_nonce"
+	// TxLabelL1BlockNumber is the L1 block of the relayed L1 base fee.
+	TxLabelL1BlockNumber = "l1_block_number"
+)
+
 var (
 	// ErrExecutionRevertedMessageExpired error of Message expired
 	ErrExecutionRevertedMessageExpired = errors.New("execution reverted: Message expired")
@@ -15,6 +33,15 @@ var (
 	ErrExecutionRevertedAlreadySuccessExecuted = errors.New("execution reverted: Message was already successfully executed")
 )
 
+// sentTxHash returns the hash to record for a transaction handed to the sender, or an empty string if the sender holds it
+// with sender.ErrQueued, the hash being recorded when the confirmation of the dispatched transaction is handled.
+func sentTxHash(txHash common.Hash, err error) string {
+	if errors.Is(err, sender.ErrQueued) {
+		return ""
+	}
+	return txHash.String()
+}
+
 // ServiceType defines the various types of services within the relayer.
 type ServiceType int
 
@@ -0,0 +1,218 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// errDAMismatch is wrapped by the verification errors of commit data which differs from the batch data in the db.
+var errDAMismatch = errors.New("committed data mismatch")
+
+// blockContextSize is the size of the encoded block context in the chunks of the commit calldata.
+const blockContextSize = 60
+
+// L1TxReader reads the transactions committing the batches on L1.
+type L1TxReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*gethTypes.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethTypes.Receipt, error)
+}
+
+// DAVerifier re-derives the data of the committed batches from L1, the calldata of the commit transactions, and
+// checks it against the batch data in the db. The batches are marked verified or mismatching, the
+// rollup relayer only finalizes verified batches when the verifier is configured.
+type DAVerifier struct {
+	ctx context.Context
+	cfg *config.DAVerifierConfig
+
+	l1Client    L1TxReader
+	l1RollupABI *abi.ABI
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	metrics *daVerifierMetrics
+}
+
+// committedBatch is the commit data of a batch decoded from the calldata of its commit transaction.
+type committedBatch struct {
+	version                uint8
+	parentBatchHeader      []byte
+	chunks                 [][]byte
+	skippedL1MessageBitmap []byte
+}
+
+// NewDAVerifier creates a new DAVerifier instance.
+func NewDAVerifier(ctx context.Context, cfg *config.DAVerifierConfig, l1Client L1TxReader, db *gorm.DB, reg prometheus.Registerer) *DAVerifier {
+	log.Info("new da verifier", "maxBatchesPerRun", cfg.MaxBatchesPerRun)
+
+	return &DAVerifier{
+		ctx:         ctx,
+		cfg:         cfg,
+		l1Client:    l1Client,
+		l1RollupABI: bridgeAbi.ScrollChainABI,
+		batchOrm:    orm.NewBatch(db),
+		chunkOrm:    orm.NewChunk(db),
+		l2BlockOrm:  orm.NewL2Block(db),
+		metrics:     initDAVerifierMetrics(reg),
+	}
+}
+
+// TryVerifyCommittedBatches verifies the commit data of the earliest committed batches pending verification. A batch
+// failing to be verified, e.g. on an L1 endpoint error, stays pending and is verified again in the next run.
+func (v *DAVerifier) TryVerifyCommittedBatches() {
+	batches, err := v.batchOrm.GetBatches(v.ctx, map[string]interface{}{
+		"rollup_status IN ?":     []int{int(types.RollupCommitted), int(types.RollupFinalizing), int(types.RollupFinalizeFailed)},
+		"da_verification_status": int(types.DAVerificationPending),
+		"index > ?":              0,
+	}, []string{"index ASC"}, v.cfg.MaxBatchesPerRun)
+	if err != nil {
+		log.Error("failed to get batches pending da verification", "err", err)
+		return
+	}
+
+	for _, batch := range batches {
+		status := types.DAVerificationVerified
+		if verifyErr := v.verifyBatch(batch); verifyErr != nil {
+			if !errors.Is(verifyErr, errDAMismatch) {
+				v.metrics.verificationFailuresTotal.Inc()
+				log.Warn("failed to verify committed batch data", "index", batch.Index, "hash", batch.Hash, "err", verifyErr)
+				continue
+			}
+			status = types.DAVerificationMismatch
+			log.Error("committed batch data does not match the db, holding its finalization", "index", batch.Index, "hash", batch.Hash,
+				"commit tx hash", batch.CommitTxHash, "err", verifyErr)
+		}
+
+		if err = v.batchOrm.UpdateDAVerificationStatus(v.ctx, batch.Hash, status); err != nil {
+			log.Error("failed to update da verification status", "index", batch.Index, "hash", batch.Hash, "status", status, "err", err)
+			return
+		}
+		v.metrics.batchesVerifiedTotal.WithLabelValues(status.String()).Inc()
+		v.metrics.latestVerifiedBatchIndex.Set(float64(batch.Index))
+		log.Info("verified committed batch data", "index", batch.Index, "hash", batch.Hash, "status", status)
+	}
+}
+
+// verifyBatch re-derives the commit data of the batch from L1 and compares it with the commit data encoded from the
+// db, the returned error wraps errDAMismatch if they differ.
+func (v *DAVerifier) verifyBatch(batch *orm.Batch) error {
+	commitTxHash := common.HexToHash(batch.CommitTxHash)
+	tx, isPending, err := v.l1Client.TransactionByHash(v.ctx, commitTxHash)
+	if err != nil {
+		return fmt.Errorf("failed to get commit tx, hash: %s, err: %w", commitTxHash.Hex(), err)
+	}
+	if isPending {
+		return fmt.Errorf("commit tx is pending, hash: %s", commitTxHash.Hex())
+	}
+	receipt, err := v.l1Client.TransactionReceipt(v.ctx, commitTxHash)
+	if err != nil {
+		return fmt.Errorf("failed to get commit tx receipt, hash: %s, err: %w", commitTxHash.Hex(), err)
+	}
+	if receipt.Status != gethTypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%w: commit tx reverted, hash: %s", errDAMismatch, commitTxHash.Hex())
+	}
+
+	committed, err := decodeCommitCalldata(v.l1RollupABI, tx.Data(), batch.Index)
+	if err != nil {
+		return err
+	}
+
+	dbChunks, err := v.chunkOrm.GetChunksInRange(v.ctx, batch.StartChunkIndex, batch.EndChunkIndex)
+	if err != nil {
+		return fmt.Errorf("failed to fetch chunks, start index: %d, end index: %d, err: %w", batch.StartChunkIndex, batch.EndChunkIndex, err)
+	}
+	chunks, err := loadChunkBlocks(v.ctx, v.l2BlockOrm, dbChunks)
+	if err != nil {
+		return err
+	}
+	expected, err := encodeCommitPayload(batch, dbChunks, chunks)
+	if err != nil {
+		return err
+	}
+	parentBatch, err := v.batchOrm.GetBatchByIndex(v.ctx, batch.Index-1)
+	if err != nil {
+		return fmt.Errorf("failed to get parent batch of commit tx, index: %d, err: %w", batch.Index-1, err)
+	}
+	return compareCommitPayload(committed, expected, parentBatch.BatchHeader, chunks)
+}
+
+// decodeCommitCalldata decodes the commit data of the batch with the index from the calldata of commitBatch.
+func decodeCommitCalldata(rollupABI *abi.ABI, calldata []byte, batchIndex uint64) (*committedBatch, error) {
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("%w: commit tx calldata too short", errDAMismatch)
+	}
+	method, err := rollupABI.MethodById(calldata[:4])
+	if err != nil || method.Name != "commitBatch" {
+		return nil, fmt.Errorf("%w: commit tx does not call commitBatch", errDAMismatch)
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unpack %s calldata: %v", errDAMismatch, method.Name, err)
+	}
+
+	committed := &committedBatch{}
+	committed.version, _ = args[0].(uint8)
+	committed.parentBatchHeader, _ = args[1].([]byte)
+	if len(committed.parentBatchHeader) < 9 {
+		return nil, fmt.Errorf("%w: invalid parent batch header length: %d", errDAMismatch, len(committed.parentBatchHeader))
+	}
+	// the batch index follows the version byte in every header encoding.
+	if parentIndex := binary.BigEndian.Uint64(committed.parentBatchHeader[1:9]); parentIndex+1 != batchIndex {
+		return nil, fmt.Errorf("%w: commitBatch commits batch %d, expected: %d", errDAMismatch, parentIndex+1, batchIndex)
+	}
+	committed.chunks, _ = args[2].([][]byte)
+	committed.skippedL1MessageBitmap, _ = args[3].([]byte)
+	return committed, nil
+}
+
+// compareCommitPayload compares the commit data decoded from the calldata with the commit data encoded from the db,
+// a differing chunk is reported with its first differing block.
+func compareCommitPayload(committed *committedBatch, expected *commitPayload, parentBatchHeader []byte, chunks []*encoding.Chunk) error {
+	if committed.version != expected.version {
+		return fmt.Errorf("%w: codec version, committed: %d, expected: %d", errDAMismatch, committed.version, expected.version)
+	}
+	if !bytes.Equal(committed.parentBatchHeader, parentBatchHeader) {
+		return fmt.Errorf("%w: parent batch header", errDAMismatch)
+	}
+	if !bytes.Equal(committed.skippedL1MessageBitmap, expected.skippedL1MessageBitmap) {
+		return fmt.Errorf("%w: skipped l1 message bitmap", errDAMismatch)
+	}
+	if len(committed.chunks) != len(expected.chunks) {
+		return fmt.Errorf("%w: number of chunks, committed: %d, expected: %d", errDAMismatch, len(committed.chunks), len(expected.chunks))
+	}
+
+	for i := range committed.chunks {
+		if bytes.Equal(committed.chunks[i], expected.chunks[i]) {
+			continue
+		}
+		offset := 0
+		for offset < len(committed.chunks[i]) && offset < len(expected.chunks[i]) && committed.chunks[i][offset] == expected.chunks[i][offset] {
+			offset++
+		}
+		// the number of blocks is followed by the block contexts, then by the L2 transactions in codecv0.
+		if offset > 0 && (offset-1)/blockContextSize < len(chunks[i].Blocks) {
+			block := chunks[i].Blocks[(offset-1)/blockContextSize]
+			return fmt.Errorf("%w: chunk %d, block context of block %d", errDAMismatch, i, block.Header.Number.Uint64())
+		}
+		return fmt.Errorf("%w: chunk %d, at byte %d", errDAMismatch, i, offset)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package relayer
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type daVerifierMetrics struct {
+	batchesVerifiedTotal      *prometheus.CounterVec
+	verificationFailuresTotal prometheus.Counter
+	latestVerifiedBatchIndex  prometheus.Gauge
+}
+
+var (
+	initDAVerifierMetricOnce sync.Once
+	daVerifierMetric         *daVerifierMetrics
+)
+
+func initDAVerifierMetrics(reg prometheus.Registerer) *daVerifierMetrics {
+	initDAVerifierMetricOnce.Do(func() {
+		daVerifierMetric = &daVerifierMetrics{
+			batchesVerifiedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_da_verifier_batches_verified_total",
+				Help: "The total number of committed batches whose data was re-derived from L1, by verification status",
+			}, []string{"status"}),
+			verificationFailuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_da_verifier_verification_failures_total",
+				Help: "The total number of committed batch verifications which failed and are retried, e.g. on L1 endpoint errors",
+			}),
+			latestVerifiedBatchIndex: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_da_verifier_latest_verified_batch_index",
+				Help: "The index of the latest committed batch whose data was re-derived from L1",
+			}),
+		}
+	})
+	return daVerifierMetric
+}
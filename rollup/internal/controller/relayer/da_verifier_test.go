@@ -0,0 +1,57 @@
+package relayer
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv0"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+)
+
+func TestDAVerifierCompare(t *testing.T) {
+	var chunks []*encoding.Chunk
+	for _, file := range []string{"../../../testdata/blockTrace_02.json", "../../../testdata/blockTrace_03.json"} {
+		trace, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		block := &encoding.Block{}
+		assert.NoError(t, json.Unmarshal(trace, block))
+		chunks = append(chunks, &encoding.Chunk{Blocks: []*encoding.Block{block}})
+	}
+
+	daBatch, err := codecv0.NewDABatch(&encoding.Batch{Index: 1, Chunks: chunks})
+	assert.NoError(t, err)
+	expected := &commitPayload{version: daBatch.Version, skippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap}
+	for _, chunk := range chunks {
+		daChunk, err := codecv0.NewDAChunk(chunk, 0)
+		assert.NoError(t, err)
+		encodedChunk, err := daChunk.Encode()
+		assert.NoError(t, err)
+		expected.chunks = append(expected.chunks, encodedChunk)
+	}
+	// the genesis header, version 0 and batch index 0.
+	parentHeader := make([]byte, 89)
+
+	calldata, err := bridgeAbi.ScrollChainABI.Pack("commitBatch", expected.version, parentHeader, expected.chunks, expected.skippedL1MessageBitmap)
+	assert.NoError(t, err)
+	committed, err := decodeCommitCalldata(bridgeAbi.ScrollChainABI, calldata, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, compareCommitPayload(committed, expected, parentHeader, chunks))
+	_, err = decodeCommitCalldata(bridgeAbi.ScrollChainABI, calldata, 2)
+	assert.True(t, errors.Is(err, errDAMismatch))
+
+	// a differing block context is reported with its block.
+	committed.chunks = [][]byte{expected.chunks[0], common.CopyBytes(expected.chunks[1])}
+	committed.chunks[1][10]++
+	err = compareCommitPayload(committed, expected, parentHeader, chunks)
+	assert.True(t, errors.Is(err, errDAMismatch))
+	assert.Contains(t, err.Error(), "chunk 1, block context of block "+chunks[1].Blocks[0].Header.Number.String())
+	committed.chunks = expected.chunks[:1]
+	assert.True(t, errors.Is(compareCommitPayload(committed, expected, parentHeader, chunks), errDAMismatch))
+}
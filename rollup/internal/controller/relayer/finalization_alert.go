@@ -0,0 +1,139 @@
+package relayer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	alertHookWebhook   = "webhook"
+	alertHookSlack     = "slack"
+	alertHookPagerDuty = "pagerduty"
+
+	// alertSignatureHeader carries the hex encoded HMAC-SHA256 of the request body, as in the sender webhook deliveries.
+	alertSignatureHeader = "X-Scroll-Signature"
+
+	defaultAlertRetryCount = 3
+	defaultAlertTimeout    = 5
+)
+
+// pagerDutyEventsURL is the endpoint of the PagerDuty Events API v2.
+var pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// finalizationAlerter delivers the finalization alerts to the configured hooks. A delivery failure is logged and
+// counted, it does not prevent the delivery to the other hooks.
+type finalizationAlerter struct {
+	client *resty.Client
+
+	webhookURL          string
+	webhookSecret       []byte
+	slackWebhookURL     string
+	pagerDutyRoutingKey string
+}
+
+func newFinalizationAlerter(cfg *config.FinalizationMonitorConfig) *finalizationAlerter {
+	retryCount, timeout := defaultAlertRetryCount, defaultAlertTimeout
+	a := &finalizationAlerter{
+		slackWebhookURL:     cfg.SlackWebhookURL,
+		pagerDutyRoutingKey: cfg.PagerDutyRoutingKey,
+	}
+	if cfg.Webhook != nil {
+		a.webhookURL = cfg.Webhook.URL
+		a.webhookSecret = []byte(cfg.Webhook.Secret)
+		if cfg.Webhook.RetryCount != 0 {
+			retryCount = cfg.Webhook.RetryCount
+		}
+		if cfg.Webhook.Timeout != 0 {
+			timeout = cfg.Webhook.Timeout
+		}
+	}
+
+	a.client = resty.New()
+	a.client.SetRetryCount(retryCount)
+	a.client.SetTimeout(time.Duration(timeout) * time.Second)
+	a.client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		return err != nil || resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= http.StatusInternalServerError
+	})
+	return a
+}
+
+// fire delivers the alert to every configured hook.
+func (a *finalizationAlerter) fire(ctx context.Context, alert *FinalizationAlert, metrics *finalizationMonitorMetrics) {
+	if a.webhookURL != "" {
+		a.deliver(ctx, alertHookWebhook, a.webhookURL, alert, a.webhookSecret, metrics)
+	}
+	if a.slackWebhookURL != "" {
+		a.deliver(ctx, alertHookSlack, a.slackWebhookURL, map[string]string{"text": fmt.Sprintf("[%s] %s", alert.Severity, alert)}, nil, metrics)
+	}
+	if a.pagerDutyRoutingKey != "" {
+		a.deliver(ctx, alertHookPagerDuty, pagerDutyEventsURL, a.pagerDutyEvent("trigger", alert), nil, metrics)
+	}
+}
+
+// resolve resolves the PagerDuty incident of the alerted batch no longer pending finalization, the other hooks are
+// not notified.
+func (a *finalizationAlerter) resolve(ctx context.Context, alert *FinalizationAlert, metrics *finalizationMonitorMetrics) {
+	if a.pagerDutyRoutingKey != "" {
+		a.deliver(ctx, alertHookPagerDuty, pagerDutyEventsURL, a.pagerDutyEvent("resolve", alert), nil, metrics)
+	}
+}
+
+// pagerDutyEvent returns the PagerDuty event of the alert, deduplicated by batch so that the alerts of the later
+// thresholds update the incident of the batch.
+func (a *finalizationAlerter) pagerDutyEvent(action string, alert *FinalizationAlert) map[string]interface{} {
+	event := map[string]interface{}{
+		"routing_key":  a.pagerDutyRoutingKey,
+		"event_action": action,
+		"dedup_key":    "rollup-finalization-" + alert.BatchHash,
+	}
+	if action == "trigger" {
+		event["payload"] = map[string]interface{}{
+			"summary":        alert.String(),
+			"source":         "rollup-relayer",
+			"severity":       alert.Severity,
+			"component":      "finalization",
+			"custom_details": alert,
+		}
+	}
+	return event
+}
+
+// deliver posts the json body to the url, retrying on network errors, 429 and 5xx responses.
+func (a *finalizationAlerter) deliver(ctx context.Context, hook, url string, payload interface{}, secret []byte, metrics *finalizationMonitorMetrics) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("failed to encode finalization alert", "hook", hook, "err", err)
+		return
+	}
+
+	req := a.client.R().SetContext(ctx).SetHeader("Content-Type", "application/json").SetBody(body)
+	if len(secret) > 0 {
+		req.SetHeader(alertSignatureHeader, signAlertBody(secret, body))
+	}
+	resp, err := req.Post(url)
+	if err == nil && resp.IsError() {
+		err = fmt.Errorf("status: %d", resp.StatusCode())
+	}
+	if err != nil {
+		metrics.alertDeliveryFailuresTotal.WithLabelValues(hook).Inc()
+		log.Warn("failed to deliver finalization alert", "hook", hook, "err", err)
+	}
+}
+
+// signAlertBody returns the hex encoded HMAC-SHA256 of body keyed by secret.
+func signAlertBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,238 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	// DelayReasonNoProof is the reason of a batch whose proof is not verified.
+	DelayReasonNoProof = "no_proof"
+	// DelayReasonSenderStuck is the reason of a proven batch whose finalize transaction is not sent or not included
+	// while its fee cap covers the L1 base fee.
+	DelayReasonSenderStuck = "sender_stuck"
+	// DelayReasonL1Congestion is the reason of a proven batch whose finalize transaction is priced out by the L1 base fee.
+	DelayReasonL1Congestion = "l1_congestion"
+
+	// AlertSeverityWarning is the severity of the alerts of the thresholds before the last one.
+	AlertSeverityWarning = "warning"
+	// AlertSeverityCritical is the severity of the alerts of the last threshold.
+	AlertSeverityCritical = "critical"
+)
+
+// HeaderReader reads the headers of a chain.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+}
+
+// FinalizationAlert is an alert of a committed batch approaching or past its finalization deadline.
+type FinalizationAlert struct {
+	BatchIndex uint64 `json:"batch_index"`
+	BatchHash  string `json:"batch_hash"`
+	Severity   string `json:"severity"`
+	// Threshold is the fraction of the deadline elapsed which fired the alert.
+	Threshold float64 `json:"threshold"`
+	Reason    string  `json:"reason"`
+	// RemainingSec is the time left until the deadline, negative once it is missed.
+	RemainingSec int64     `json:"remaining_sec"`
+	CommittedAt  time.Time `json:"committed_at"`
+	Deadline     time.Time `json:"deadline"`
+}
+
+// FinalizationMonitor tracks how close the committed batches not finalized yet are to their finalization deadline,
+// and fires the alert hooks as the batches cross the alert thresholds. The fired alerts are kept in memory, only the
+// thresholds crossed since the monitor started fire again after a restart.
+type FinalizationMonitor struct {
+	ctx context.Context
+	cfg *config.FinalizationMonitorConfig
+
+	l1Client HeaderReader
+
+	batchOrm              *orm.Batch
+	pendingTransactionOrm *orm.PendingTransaction
+
+	alerter *finalizationAlerter
+	// alerted are the latest alerts fired by batch hash, along with the number of thresholds crossed, the batches are
+	// forgotten once finalized.
+	alerted map[string]*alertedBatch
+
+	metrics *finalizationMonitorMetrics
+}
+
+type alertedBatch struct {
+	thresholds int
+	alert      *FinalizationAlert
+}
+
+// NewFinalizationMonitor creates a new FinalizationMonitor instance.
+func NewFinalizationMonitor(ctx context.Context, cfg *config.FinalizationMonitorConfig, l1Client HeaderReader, db *gorm.DB, reg prometheus.Registerer) *FinalizationMonitor {
+	log.Info("new finalization monitor", "deadlineSec", cfg.DeadlineSec, "alertThresholds", cfg.AlertThresholds)
+
+	return &FinalizationMonitor{
+		ctx:                   ctx,
+		cfg:                   cfg,
+		l1Client:              l1Client,
+		batchOrm:              orm.NewBatch(db),
+		pendingTransactionOrm: orm.NewPendingTransaction(db),
+		alerter:               newFinalizationAlerter(cfg),
+		alerted:               make(map[string]*alertedBatch),
+		metrics:               initFinalizationMonitorMetrics(reg),
+	}
+}
+
+// TryMonitorFinalization updates the deadline gauges of the committed batches not finalized yet, fires the alerts of
+// the thresholds they crossed since the last run and resolves the alerts of the batches finalized since.
+func (m *FinalizationMonitor) TryMonitorFinalization() {
+	batches, err := m.batchOrm.GetBatches(m.ctx, map[string]interface{}{
+		"rollup_status IN ?": []int{int(types.RollupCommitted), int(types.RollupFinalizing), int(types.RollupFinalizeFailed)},
+	}, []string{"index ASC"}, 0)
+	if err != nil {
+		log.Error("failed to get unfinalized batches", "err", err)
+		return
+	}
+
+	// the base fee is only needed to tell the reason of a delay, it is unknown if the header fails to be read.
+	var baseFee *big.Int
+	if header, headerErr := m.l1Client.HeaderByNumber(m.ctx, nil); headerErr != nil {
+		log.Warn("failed to get latest l1 header", "err", headerErr)
+	} else {
+		baseFee = header.BaseFee
+	}
+
+	now := time.Now()
+	deadline := time.Duration(m.cfg.DeadlineSec) * time.Second
+	pastThresholds := make([]int, len(m.cfg.AlertThresholds))
+	unfinalized := make(map[string]struct{}, len(batches))
+	// the remaining time of the oldest batch, batches are committed in order.
+	var oldestRemaining *time.Duration
+	for _, batch := range batches {
+		if batch.CommittedAt == nil {
+			continue
+		}
+		unfinalized[batch.Hash] = struct{}{}
+
+		remaining := batch.CommittedAt.Add(deadline).Sub(now)
+		if oldestRemaining == nil {
+			oldestRemaining = &remaining
+		}
+		crossed := crossedThresholds(m.cfg.AlertThresholds, now.Sub(*batch.CommittedAt), deadline)
+		for j := 0; j < crossed; j++ {
+			pastThresholds[j]++
+		}
+
+		alerted := m.alerted[batch.Hash]
+		if crossed == 0 || (alerted != nil && alerted.thresholds >= crossed) {
+			continue
+		}
+		reason, reasonErr := m.delayReason(batch, baseFee)
+		if reasonErr != nil {
+			log.Error("failed to get finalization delay reason", "index", batch.Index, "hash", batch.Hash, "err", reasonErr)
+			continue
+		}
+
+		// thresholds crossed since the last run only fire the highest one.
+		severity := AlertSeverityWarning
+		if crossed == len(m.cfg.AlertThresholds) {
+			severity = AlertSeverityCritical
+		}
+		alert := &FinalizationAlert{
+			BatchIndex:   batch.Index,
+			BatchHash:    batch.Hash,
+			Severity:     severity,
+			Threshold:    m.cfg.AlertThresholds[crossed-1],
+			Reason:       reason,
+			RemainingSec: int64(remaining.Seconds()),
+			CommittedAt:  *batch.CommittedAt,
+			Deadline:     batch.CommittedAt.Add(deadline),
+		}
+		m.alerted[batch.Hash] = &alertedBatch{thresholds: crossed, alert: alert}
+		m.metrics.alertsTotal.WithLabelValues(severity, reason).Inc()
+		log.Warn("batch approaching finalization deadline", "index", batch.Index, "hash", batch.Hash, "severity", severity,
+			"threshold", alert.Threshold, "reason", reason, "remaining", remaining.Round(time.Second))
+		m.alerter.fire(m.ctx, alert, m.metrics)
+	}
+
+	if oldestRemaining == nil {
+		oldestRemaining = &deadline
+	}
+	m.metrics.remainingSeconds.Set(oldestRemaining.Seconds())
+	m.metrics.unfinalizedBatches.Set(float64(len(unfinalized)))
+	for i, threshold := range m.cfg.AlertThresholds {
+		m.metrics.batchesPastThreshold.WithLabelValues(strconv.FormatFloat(threshold, 'f', -1, 64)).Set(float64(pastThresholds[i]))
+	}
+
+	for hash, alerted := range m.alerted {
+		if _, ok := unfinalized[hash]; ok {
+			continue
+		}
+		log.Info("alerted batch no longer pending finalization", "index", alerted.alert.BatchIndex, "hash", hash)
+		m.alerter.resolve(m.ctx, alerted.alert, m.metrics)
+		delete(m.alerted, hash)
+	}
+}
+
+// crossedThresholds returns the number of thresholds crossed after elapsed of the deadline.
+func crossedThresholds(thresholds []float64, elapsed, deadline time.Duration) int {
+	fraction := elapsed.Seconds() / deadline.Seconds()
+	crossed := 0
+	for _, threshold := range thresholds {
+		if fraction < threshold {
+			break
+		}
+		crossed++
+	}
+	return crossed
+}
+
+// delayReason tells why the batch is not finalized yet, a nil base fee is unknown.
+func (m *FinalizationMonitor) delayReason(batch *orm.Batch, baseFee *big.Int) (string, error) {
+	if types.ProvingStatus(batch.ProvingStatus) != types.ProvingTaskVerified {
+		return DelayReasonNoProof, nil
+	}
+
+	txs, err := m.pendingTransactionOrm.GetTransactionsBySenderTypeAndContextID(m.ctx, types.SenderTypeFinalizeBatch, batch.Hash)
+	if err != nil {
+		return "", err
+	}
+	// the latest transaction replaces the previous ones, the finalize transaction is not sent yet when there is none.
+	if len(txs) == 0 || baseFee == nil {
+		return DelayReasonSenderStuck, nil
+	}
+	latest := txs[0]
+	if latest.Status != types.TxStatusPending && latest.Status != types.TxStatusReplaced {
+		return DelayReasonSenderStuck, nil
+	}
+	return finalizeTxDelayReason(baseFee, latest.GasFeeCap, m.cfg.CongestedBaseFee), nil
+}
+
+// finalizeTxDelayReason tells whether a pending finalize transaction is delayed by the L1 base fee.
+func finalizeTxDelayReason(baseFee *big.Int, gasFeeCap, congestedBaseFee uint64) string {
+	if baseFee.Cmp(new(big.Int).SetUint64(gasFeeCap)) > 0 {
+		return DelayReasonL1Congestion
+	}
+	if congestedBaseFee > 0 && baseFee.Cmp(new(big.Int).SetUint64(congestedBaseFee)) > 0 {
+		return DelayReasonL1Congestion
+	}
+	return DelayReasonSenderStuck
+}
+
+// String returns the summary of the alert.
+func (a *FinalizationAlert) String() string {
+	if a.RemainingSec < 0 {
+		return fmt.Sprintf("batch %d missed its finalization deadline by %s, reason: %s", a.BatchIndex, time.Duration(-a.RemainingSec)*time.Second, a.Reason)
+	}
+	return fmt.Sprintf("batch %d is %s from its finalization deadline, reason: %s", a.BatchIndex, time.Duration(a.RemainingSec)*time.Second, a.Reason)
+}
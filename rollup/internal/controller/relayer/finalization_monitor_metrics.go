@@ -0,0 +1,49 @@
+package relayer
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type finalizationMonitorMetrics struct {
+	unfinalizedBatches         prometheus.Gauge
+	remainingSeconds           prometheus.Gauge
+	batchesPastThreshold       *prometheus.GaugeVec
+	alertsTotal                *prometheus.CounterVec
+	alertDeliveryFailuresTotal *prometheus.CounterVec
+}
+
+var (
+	initFinalizationMonitorMetricOnce sync.Once
+	finalizationMonitorMetric         *finalizationMonitorMetrics
+)
+
+func initFinalizationMonitorMetrics(reg prometheus.Registerer) *finalizationMonitorMetrics {
+	initFinalizationMonitorMetricOnce.Do(func() {
+		finalizationMonitorMetric = &finalizationMonitorMetrics{
+			unfinalizedBatches: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_finalization_monitor_unfinalized_batches",
+				Help: "The number of committed batches not finalized yet",
+			}),
+			remainingSeconds: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_finalization_monitor_deadline_remaining_seconds",
+				Help: "The time left until the finalization deadline of the oldest unfinalized batch, negative once missed, the whole deadline when every batch is finalized",
+			}),
+			batchesPastThreshold: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_finalization_monitor_batches_past_threshold",
+				Help: "The number of unfinalized batches past each alert threshold of their finalization deadline",
+			}, []string{"threshold"}),
+			alertsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_finalization_monitor_alerts_total",
+				Help: "The total number of finalization deadline alerts fired, by severity and delay reason",
+			}, []string{"severity", "reason"}),
+			alertDeliveryFailuresTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_finalization_monitor_alert_delivery_failures_total",
+				Help: "The total number of finalization alerts which failed to be delivered, by hook",
+			}, []string{"hook"}),
+		}
+	})
+	return finalizationMonitorMetric
+}
@@ -0,0 +1,101 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestFinalizationDeadlineThresholds(t *testing.T) {
+	thresholds := []float64{0.5, 0.8, 1}
+	deadline := time.Hour
+	assert.Equal(t, 0, crossedThresholds(thresholds, 29*time.Minute, deadline))
+	assert.Equal(t, 1, crossedThresholds(thresholds, 30*time.Minute, deadline))
+	assert.Equal(t, 2, crossedThresholds(thresholds, 50*time.Minute, deadline))
+	assert.Equal(t, 3, crossedThresholds(thresholds, 2*time.Hour, deadline))
+
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+	// the base fee exceeding the fee cap prices out the transaction.
+	assert.Equal(t, DelayReasonL1Congestion, finalizeTxDelayReason(gwei(30), 20e9, 0))
+	assert.Equal(t, DelayReasonSenderStuck, finalizeTxDelayReason(gwei(10), 20e9, 0))
+	// the base fee above the congested base fee is congestion even if the fee cap covers it.
+	assert.Equal(t, DelayReasonL1Congestion, finalizeTxDelayReason(gwei(15), 20e9, 12e9))
+	assert.Equal(t, DelayReasonSenderStuck, finalizeTxDelayReason(gwei(10), 20e9, 12e9))
+}
+
+func TestFinalizationAlerter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string][][]byte{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/webhook" {
+			assert.Equal(t, signAlertBody([]byte("secret"), body), r.Header.Get(alertSignatureHeader))
+		}
+		requests[r.URL.Path] = append(requests[r.URL.Path], body)
+	}))
+	defer srv.Close()
+	pagerDutyEventsURL = srv.URL + "/pagerduty"
+
+	alerter := newFinalizationAlerter(&config.FinalizationMonitorConfig{
+		Webhook:             &config.WebhookConfig{URL: srv.URL + "/webhook", Secret: "secret"},
+		SlackWebhookURL:     srv.URL + "/slack",
+		PagerDutyRoutingKey: "routing-key",
+	})
+	metrics := initFinalizationMonitorMetrics(prometheus.NewRegistry())
+	alert := &FinalizationAlert{
+		BatchIndex:   7,
+		BatchHash:    "0x07",
+		Severity:     AlertSeverityCritical,
+		Threshold:    1,
+		Reason:       DelayReasonNoProof,
+		RemainingSec: -90,
+	}
+	alerter.fire(context.Background(), alert, metrics)
+	alerter.resolve(context.Background(), alert, metrics)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var webhookAlert FinalizationAlert
+	assert.Len(t, requests["/webhook"], 1)
+	assert.NoError(t, json.Unmarshal(requests["/webhook"][0], &webhookAlert))
+	assert.Equal(t, *alert, webhookAlert)
+
+	var slackMessage map[string]string
+	assert.Len(t, requests["/slack"], 1)
+	assert.NoError(t, json.Unmarshal(requests["/slack"][0], &slackMessage))
+	assert.Equal(t, "[critical] batch 7 missed its finalization deadline by 1m30s, reason: no_proof", slackMessage["text"])
+
+	var trigger, resolve struct {
+		RoutingKey  string `json:"routing_key"`
+		EventAction string `json:"event_action"`
+		DedupKey    string `json:"dedup_key"`
+		Payload     *struct {
+			Summary  string `json:"summary"`
+			Severity string `json:"severity"`
+		} `json:"payload"`
+	}
+	assert.Len(t, requests["/pagerduty"], 2)
+	assert.NoError(t, json.Unmarshal(requests["/pagerduty"][0], &trigger))
+	assert.NoError(t, json.Unmarshal(requests["/pagerduty"][1], &resolve))
+	assert.Equal(t, "routing-key", trigger.RoutingKey)
+	assert.Equal(t, "trigger", trigger.EventAction)
+	assert.Equal(t, AlertSeverityCritical, trigger.Payload.Severity)
+	assert.Equal(t, "resolve", resolve.EventAction)
+	assert.Equal(t, trigger.DedupKey, resolve.DedupKey)
+	assert.Nil(t, resolve.Payload)
+}
@@ -0,0 +1,189 @@
+package relayer
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/sender"
+)
+
+// gasOracleUpdateReason tells why a gas price oracle update is submitted or suppressed.
+type gasOracleUpdateReason string
+
+const (
+	gasOracleUpdateInitial   gasOracleUpdateReason = "initial"
+	gasOracleUpdateDeviation gasOracleUpdateReason = "deviation"
+	gasOracleUpdateStale     gasOracleUpdateReason = "stale"
+
+	gasOracleSuppressedNoDeviation gasOracleUpdateReason = "no_deviation"
+
+	// Anomalies are suppressed and alerted, the previous on-chain value is kept.
+	gasOracleAnomalyBelowMin  gasOracleUpdateReason = "below_min_gas_price"
+	gasOracleAnomalyAboveMax  gasOracleUpdateReason = "above_max_gas_price"
+	gasOracleAnomalyStepLimit gasOracleUpdateReason = "step_limit_exceeded"
+)
+
+// isAnomaly reports whether the gas price was rejected as out of the sanity bounds.
+func (r gasOracleUpdateReason) isAnomaly() bool {
+	return r == gasOracleAnomalyBelowMin || r == gasOracleAnomalyAboveMax || r == gasOracleAnomalyStepLimit
+}
+
+// gasOracleUpdatePolicy decides when a gas price oracle is updated: an update is submitted when the new gas price
+// deviates from the last on-chain value by at least gasPriceDiff, or when the last update is older than
+// maxStaleness. Gas prices out of [minGasPrice, maxGasPrice] or changing by more than maxStep from the last on-chain
+// value are rejected, so that a single bad RPC response does not reach the oracle. A genuine move beyond maxStep is
+// not relayed either until the bounds are reconfigured.
+type gasOracleUpdatePolicy struct {
+	minGasPrice  uint64
+	gasPriceDiff uint64
+	// maxGasPrice and maxStep are 0 when not bounded.
+	maxGasPrice uint64
+	maxStep     uint64
+	// maxStaleness is 0 when updates are only triggered by deviation.
+	maxStaleness time.Duration
+
+	// lastGasPrice caches the last on-chain value, 0 while unknown.
+	lastGasPrice  uint64
+	lastUpdatedAt time.Time
+}
+
+func newGasOracleUpdatePolicy(cfg *config.GasOracleConfig) *gasOracleUpdatePolicy {
+	if cfg == nil {
+		return &gasOracleUpdatePolicy{gasPriceDiff: defaultGasPriceDiff}
+	}
+	return &gasOracleUpdatePolicy{
+		minGasPrice:  cfg.MinGasPrice,
+		gasPriceDiff: cfg.GasPriceDiff,
+		maxGasPrice:  cfg.MaxGasPrice,
+		maxStep:      cfg.MaxGasPriceStep,
+		maxStaleness: time.Duration(cfg.MaxStalenessSec) * time.Second,
+	}
+}
+
+func newBlobBaseFeeUpdatePolicy(cfg *config.BlobBaseFeeConfig) *gasOracleUpdatePolicy {
+	return &gasOracleUpdatePolicy{
+		minGasPrice:  cfg.MinBlobBaseFee,
+		gasPriceDiff: cfg.BlobBaseFeeDiff,
+		maxGasPrice:  cfg.MaxBlobBaseFee,
+		maxStep:      cfg.MaxBlobBaseFeeStep,
+		maxStaleness: time.Duration(cfg.MaxStalenessSec) * time.Second,
+	}
+}
+
+// deviation returns the relative deviation of gasPrice from the last on-chain value, in gasPriceDiffPrecision units.
+func (p *gasOracleUpdatePolicy) deviation(gasPrice uint64) uint64 {
+	if p.lastGasPrice == 0 {
+		return 0
+	}
+	return relativeDeviation(gasPrice, p.lastGasPrice)
+}
+
+// shouldUpdate reports whether gasPrice is submitted at now and why.
+func (p *gasOracleUpdatePolicy) shouldUpdate(gasPrice uint64, now time.Time) (bool, gasOracleUpdateReason) {
+	if gasPrice < p.minGasPrice {
+		return false, gasOracleAnomalyBelowMin
+	}
+	if p.maxGasPrice > 0 && gasPrice > p.maxGasPrice {
+		return false, gasOracleAnomalyAboveMax
+	}
+	if p.lastGasPrice == 0 {
+		return true, gasOracleUpdateInitial
+	}
+	if p.maxStep > 0 && p.deviation(gasPrice) > p.maxStep {
+		return false, gasOracleAnomalyStepLimit
+	}
+	// A deviation of at least one wei is required when gasPriceDiff rounds down to 0.
+	if gasPrice != p.lastGasPrice && p.deviation(gasPrice) >= p.gasPriceDiff {
+		return true, gasOracleUpdateDeviation
+	}
+	if p.maxStaleness > 0 && now.Sub(p.lastUpdatedAt) >= p.maxStaleness {
+		return true, gasOracleUpdateStale
+	}
+	return false, gasOracleSuppressedNoDeviation
+}
+
+// updated caches gasPrice as the last on-chain value submitted at now.
+func (p *gasOracleUpdatePolicy) updated(gasPrice uint64, now time.Time) {
+	p.lastGasPrice = gasPrice
+	p.lastUpdatedAt = now
+}
+
+// loadOnChainGasPrice caches the value of the oracle contract read by the view function method, so that a restart
+// does not submit an update unless the gas price deviates or becomes stale. The time of the on-chain update is not
+// known, the staleness timer starts when the value is loaded.
+func (p *gasOracleUpdatePolicy) loadOnChainGasPrice(s *sender.Sender, contract common.Address, contractABI *abi.ABI, method string, now time.Time) error {
+	data, err := contractABI.Pack(method)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s, err: %w", method, err)
+	}
+	output, err := s.CallContract(contract, data)
+	if err != nil {
+		return fmt.Errorf("failed to call %s, err: %w", method, err)
+	}
+	result, err := contractABI.Unpack(method, output)
+	if err != nil || len(result) == 0 {
+		return fmt.Errorf("failed to unpack %s, err: %v", method, err)
+	}
+	gasPrice, ok := result[0].(*big.Int)
+	if !ok || !gasPrice.IsUint64() {
+		return fmt.Errorf("unexpected %s result: %v", method, result[0])
+	}
+	p.updated(gasPrice.Uint64(), now)
+	return nil
+}
+
+// blobBaseFeeEWMA is the exponentially weighted moving average of the blob base fee over L1 blocks, the first block
+// initializes it.
+type blobBaseFeeEWMA struct {
+	alpha float64
+
+	value       float64
+	lastBlock   uint64
+	initialized bool
+}
+
+// add folds the blob base fee of an L1 block into the average, once per block, and returns the rounded average.
+func (e *blobBaseFeeEWMA) add(blockNumber uint64, blobBaseFee uint64) uint64 {
+	if !e.initialized {
+		e.value = float64(blobBaseFee)
+		e.lastBlock = blockNumber
+		e.initialized = true
+	} else if blockNumber > e.lastBlock {
+		e.value = e.alpha*float64(blobBaseFee) + (1-e.alpha)*e.value
+		e.lastBlock = blockNumber
+	}
+	return uint64(math.Round(e.value))
+}
+
+// gasOracleRunClock records the time of the last successful gas price oracle run, submitting an update or keeping
+// the on-chain value, for the readiness probe of the gas oracle. It starts when the relayer is created.
+type gasOracleRunClock struct {
+	// lastRunAt is in unix nanoseconds, it is read by the probes concurrently with the runs.
+	lastRunAt atomic.Int64
+}
+
+func newGasOracleRunClock(now time.Time) *gasOracleRunClock {
+	c := &gasOracleRunClock{}
+	c.ran(now)
+	return c
+}
+
+// ran records a successful run at now.
+func (c *gasOracleRunClock) ran(now time.Time) {
+	c.lastRunAt.Store(now.UnixNano())
+}
+
+// age returns the time since the last successful run.
+func (c *gasOracleRunClock) age(now time.Time) time.Duration {
+	if age := now.Sub(time.Unix(0, c.lastRunAt.Load())); age > 0 {
+		return age
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestGasOracleUpdatePolicy(t *testing.T) {
+	now := time.Now()
+	policy := newGasOracleUpdatePolicy(&config.GasOracleConfig{MinGasPrice: 10, GasPriceDiff: 50000, MaxStalenessSec: 60})
+
+	// The first gas price is submitted while the on-chain value is unknown.
+	update, reason := policy.shouldUpdate(20, now)
+	assert.True(t, update)
+	assert.Equal(t, gasOracleUpdateInitial, reason)
+	policy.updated(1000, now)
+
+	// Less than 5% deviation is suppressed, 5% or more is submitted.
+	update, reason = policy.shouldUpdate(1049, now.Add(time.Second))
+	assert.False(t, update)
+	assert.Equal(t, gasOracleSuppressedNoDeviation, reason)
+	assert.Equal(t, uint64(49000), policy.deviation(1049))
+	update, reason = policy.shouldUpdate(950, now.Add(time.Second))
+	assert.True(t, update)
+	assert.Equal(t, gasOracleUpdateDeviation, reason)
+
+	// Gas prices below the minimum are never submitted.
+	update, reason = policy.shouldUpdate(5, now.Add(time.Hour))
+	assert.False(t, update)
+	assert.Equal(t, gasOracleAnomalyBelowMin, reason)
+	assert.True(t, reason.isAnomaly())
+
+	// A stale value is submitted without deviation.
+	update, reason = policy.shouldUpdate(1000, now.Add(time.Minute))
+	assert.True(t, update)
+	assert.Equal(t, gasOracleUpdateStale, reason)
+
+	// Without max staleness updates are only triggered by deviation.
+	policy = newGasOracleUpdatePolicy(nil)
+	policy.updated(1000, now)
+	update, _ = policy.shouldUpdate(1000, now.Add(24*time.Hour))
+	assert.False(t, update)
+	update, _ = policy.shouldUpdate(1050, now)
+	assert.True(t, update)
+}
+
+func TestGasOracleUpdatePolicyBounds(t *testing.T) {
+	now := time.Now()
+	policy := newGasOracleUpdatePolicy(&config.GasOracleConfig{MinGasPrice: 10, GasPriceDiff: 50000, MaxGasPrice: 10000, MaxGasPriceStep: 500000, MaxStalenessSec: 60})
+
+	// The absolute bounds apply before the on-chain value is known.
+	update, reason := policy.shouldUpdate(5, now)
+	assert.False(t, update)
+	assert.Equal(t, gasOracleAnomalyBelowMin, reason)
+	update, reason = policy.shouldUpdate(20000, now)
+	assert.False(t, update)
+	assert.Equal(t, gasOracleAnomalyAboveMax, reason)
+	assert.True(t, reason.isAnomaly())
+	policy.updated(1000, now)
+
+	// A change of up to 50% is submitted, a larger jump is rejected even when the value is stale.
+	update, reason = policy.shouldUpdate(1500, now)
+	assert.True(t, update)
+	assert.Equal(t, gasOracleUpdateDeviation, reason)
+	update, reason = policy.shouldUpdate(1501, now.Add(time.Hour))
+	assert.False(t, update)
+	assert.Equal(t, gasOracleAnomalyStepLimit, reason)
+	update, reason = policy.shouldUpdate(499, now)
+	assert.False(t, update)
+	assert.Equal(t, gasOracleAnomalyStepLimit, reason)
+
+	// The previous value is kept.
+	assert.Equal(t, uint64(1000), policy.lastGasPrice)
+	assert.False(t, gasOracleSuppressedNoDeviation.isAnomaly())
+}
+
+func TestBlobBaseFeeEWMA(t *testing.T) {
+	ewma := &blobBaseFeeEWMA{alpha: 0.5}
+	assert.Equal(t, uint64(100), ewma.add(10, 100))
+	// A block is folded into the average once.
+	assert.Equal(t, uint64(100), ewma.add(10, 300))
+	assert.Equal(t, uint64(200), ewma.add(11, 300))
+	assert.Equal(t, uint64(150), ewma.add(12, 100))
+	// Older blocks are ignored.
+	assert.Equal(t, uint64(150), ewma.add(11, 1000))
+
+	latest := &blobBaseFeeEWMA{alpha: 1}
+	latest.add(1, 100)
+	assert.Equal(t, uint64(7), latest.add(2, 7))
+
+	policy := newBlobBaseFeeUpdatePolicy(&config.BlobBaseFeeConfig{BlobBaseFeeDiff: 100000, EWMAAlpha: 0.5})
+	policy.updated(150, time.Now())
+	update, reason := policy.shouldUpdate(170, time.Now())
+	assert.True(t, update)
+	assert.Equal(t, gasOracleUpdateDeviation, reason)
+}
+
+func TestGasOracleRunClock(t *testing.T) {
+	now := time.Now()
+	clock := newGasOracleRunClock(now)
+	assert.Equal(t, time.Duration(0), clock.age(now))
+	assert.Equal(t, 90*time.Second, clock.age(now.Add(90*time.Second)))
+
+	clock.ran(now.Add(60 * time.Second))
+	assert.Equal(t, 30*time.Second, clock.age(now.Add(90*time.Second)))
+	// A clock moving backwards is not a negative age.
+	assert.Equal(t, time.Duration(0), clock.age(now))
+}
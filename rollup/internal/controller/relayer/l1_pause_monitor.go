@@ -0,0 +1,170 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+// L1PauseClient reads the pause events and the pause state of the L1 contracts.
+type L1PauseClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethTypes.Log, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// pausableContract is a L1 contract whose pause state is monitored.
+type pausableContract struct {
+	name    string
+	address common.Address
+	abi     *abi.ABI
+	paused  atomic.Bool
+}
+
+// L1PauseMonitor follows the Paused and Unpaused events of the rollup contract and of the L1 messenger, the relayer
+// does not submit the transactions of a paused contract, which would revert. The pause state is read from the contracts
+// on the first run, then updated by the events of the blocks up to the latest one.
+type L1PauseMonitor struct {
+	ctx context.Context
+	cfg *config.L1PauseMonitorConfig
+
+	client    L1PauseClient
+	rollup    *pausableContract
+	contracts []*pausableContract
+	// nextBlock is the next block scanned for pause events, 0 until the pause state is read from the contracts.
+	nextBlock uint64
+
+	metrics *l1PauseMonitorMetrics
+}
+
+// NewL1PauseMonitor creates a new L1PauseMonitor instance, the messenger is not monitored when its address is zero.
+func NewL1PauseMonitor(ctx context.Context, cfg *config.L1PauseMonitorConfig, client L1PauseClient, rollupAddress, messengerAddress common.Address, reg prometheus.Registerer) *L1PauseMonitor {
+	log.Info("new l1 pause monitor", "maxBlockRange", cfg.MaxBlockRange, "rollupAddress", rollupAddress.Hex(), "messengerAddress", messengerAddress.Hex())
+
+	m := &L1PauseMonitor{
+		ctx:     ctx,
+		cfg:     cfg,
+		client:  client,
+		rollup:  &pausableContract{name: "scroll_chain", address: rollupAddress, abi: bridgeAbi.ScrollChainABI},
+		metrics: initL1PauseMonitorMetrics(reg),
+	}
+	m.contracts = []*pausableContract{m.rollup}
+	if messengerAddress != (common.Address{}) {
+		m.contracts = append(m.contracts, &pausableContract{name: "l1_scroll_messenger", address: messengerAddress, abi: bridgeAbi.L1ScrollMessengerABI})
+	}
+	return m
+}
+
+// RollupPaused reports whether the rollup contract is paused. It is false until the first successful run.
+func (m *L1PauseMonitor) RollupPaused() bool {
+	return m.rollup.paused.Load()
+}
+
+// TryMonitorPause reads the pause state of the contracts on the first run, and applies the pause events of the
+// blocks following the previous run on the next ones, at most MaxBlockRange blocks per run.
+func (m *L1PauseMonitor) TryMonitorPause() {
+	if err := m.monitorPause(); err != nil {
+		m.metrics.failuresTotal.Inc()
+		log.Warn("failed to monitor the pause state of the l1 contracts", "next block", m.nextBlock, "err", err)
+	}
+}
+
+func (m *L1PauseMonitor) monitorPause() error {
+	latest, err := m.client.BlockNumber(m.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest l1 block number, err: %w", err)
+	}
+
+	if m.nextBlock == 0 {
+		for _, c := range m.contracts {
+			paused, err := callPaused(m.ctx, m.client, c.abi, c.address, new(big.Int).SetUint64(latest))
+			if err != nil {
+				return fmt.Errorf("failed to read the pause state of %s, err: %w", c.name, err)
+			}
+			m.setPaused(c, paused, latest)
+		}
+		m.nextBlock = latest + 1
+		return nil
+	}
+
+	if m.nextBlock > latest {
+		return nil
+	}
+	to := latest
+	if to-m.nextBlock+1 > m.cfg.MaxBlockRange {
+		to = m.nextBlock + m.cfg.MaxBlockRange - 1
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(m.nextBlock),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: make([]common.Address, 0, len(m.contracts)),
+		Topics:    [][]common.Hash{{bridgeAbi.L1PausedEventSignature, bridgeAbi.L1UnpausedEventSignature}},
+	}
+	for _, c := range m.contracts {
+		query.Addresses = append(query.Addresses, c.address)
+	}
+	logs, err := m.client.FilterLogs(m.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to filter pause events from %d to %d, err: %w", m.nextBlock, to, err)
+	}
+	// the logs are in block order, the last event of a contract sets its state.
+	for _, vLog := range logs {
+		for _, c := range m.contracts {
+			if vLog.Address == c.address && len(vLog.Topics) > 0 {
+				m.setPaused(c, vLog.Topics[0] == bridgeAbi.L1PausedEventSignature, vLog.BlockNumber)
+			}
+		}
+	}
+	m.nextBlock = to + 1
+	return nil
+}
+
+func (m *L1PauseMonitor) setPaused(c *pausableContract, paused bool, blockNumber uint64) {
+	if c.paused.Swap(paused) != paused {
+		if paused {
+			m.metrics.contractTransitionsTotal.WithLabelValues(c.name, "paused").Inc()
+			log.Warn("l1 contract paused, its transactions are suspended", "contract", c.name, "address", c.address.Hex(), "block", blockNumber)
+		} else {
+			m.metrics.contractTransitionsTotal.WithLabelValues(c.name, "unpaused").Inc()
+			log.Info("l1 contract unpaused, its transactions are resumed", "contract", c.name, "address", c.address.Hex(), "block", blockNumber)
+		}
+	}
+	if paused {
+		m.metrics.contractPaused.WithLabelValues(c.name).Set(1)
+	} else {
+		m.metrics.contractPaused.WithLabelValues(c.name).Set(0)
+	}
+}
+
+// callPaused returns the paused() state of a pausable contract at blockNumber, nil is the latest block.
+func callPaused(ctx context.Context, client L1PauseClient, contractABI *abi.ABI, address common.Address, blockNumber *big.Int) (bool, error) {
+	data, err := contractABI.Pack("paused")
+	if err != nil {
+		return false, fmt.Errorf("failed to pack paused, err: %w", err)
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, blockNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to call paused, err: %w", err)
+	}
+	result, err := contractABI.Unpack("paused", output)
+	if err != nil || len(result) == 0 {
+		return false, fmt.Errorf("failed to unpack paused, err: %v", err)
+	}
+	paused, ok := result[0].(bool)
+	if !ok {
+		return false, fmt.Errorf("unexpected paused result: %v", result[0])
+	}
+	return paused, nil
+}
@@ -0,0 +1,39 @@
+package relayer
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type l1PauseMonitorMetrics struct {
+	contractPaused           *prometheus.GaugeVec
+	contractTransitionsTotal *prometheus.CounterVec
+	failuresTotal            prometheus.Counter
+}
+
+var (
+	initL1PauseMonitorMetricOnce sync.Once
+	l1PauseMonitorMetric         *l1PauseMonitorMetrics
+)
+
+func initL1PauseMonitorMetrics(reg prometheus.Registerer) *l1PauseMonitorMetrics {
+	initL1PauseMonitorMetricOnce.Do(func() {
+		l1PauseMonitorMetric = &l1PauseMonitorMetrics{
+			contractPaused: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_l1_contract_paused",
+				Help: "Whether the l1 contract is paused, its transactions are not submitted while it is 1",
+			}, []string{"contract"}),
+			contractTransitionsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_l1_contract_pause_transitions_total",
+				Help: "The total number of pause state changes of the l1 contracts, by contract and new state",
+			}, []string{"contract", "state"}),
+			failuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_pause_monitor_failures_total",
+				Help: "The total number of l1 pause monitor runs which failed, e.g. on l1 endpoint errors",
+			}),
+		}
+	})
+	return l1PauseMonitorMetric
+}
@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+type fakeL1PauseClient struct {
+	latest uint64
+	paused map[common.Address]bool
+	logs   []gethTypes.Log
+	err    error
+
+	queries []ethereum.FilterQuery
+}
+
+func (c *fakeL1PauseClient) BlockNumber(context.Context) (uint64, error) {
+	return c.latest, c.err
+}
+
+func (c *fakeL1PauseClient) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]gethTypes.Log, error) {
+	c.queries = append(c.queries, query)
+	var logs []gethTypes.Log
+	for _, vLog := range c.logs {
+		if vLog.BlockNumber >= query.FromBlock.Uint64() && vLog.BlockNumber <= query.ToBlock.Uint64() {
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, c.err
+}
+
+func (c *fakeL1PauseClient) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return bridgeAbi.ScrollChainABI.Methods["paused"].Outputs.Pack(c.paused[*msg.To])
+}
+
+func TestL1PauseMonitor(t *testing.T) {
+	rollup := common.HexToAddress("0x01")
+	messenger := common.HexToAddress("0x02")
+	pauseLog := func(address common.Address, paused bool, blockNumber uint64) gethTypes.Log {
+		topic := bridgeAbi.L1UnpausedEventSignature
+		if paused {
+			topic = bridgeAbi.L1PausedEventSignature
+		}
+		return gethTypes.Log{Address: address, Topics: []common.Hash{topic}, BlockNumber: blockNumber}
+	}
+
+	client := &fakeL1PauseClient{latest: 100, paused: map[common.Address]bool{rollup: true}}
+	m := NewL1PauseMonitor(context.Background(), &config.L1PauseMonitorConfig{MaxBlockRange: 10}, client, rollup, messenger, prometheus.NewRegistry())
+	assert.False(t, m.RollupPaused())
+
+	// the first run reads the pause state from the contracts.
+	m.TryMonitorPause()
+	assert.True(t, m.RollupPaused())
+	assert.Equal(t, uint64(101), m.nextBlock)
+
+	// the last event of the scanned blocks sets the state, at most MaxBlockRange blocks are scanned per run.
+	client.latest = 150
+	client.logs = []gethTypes.Log{pauseLog(rollup, false, 103), pauseLog(messenger, true, 104), pauseLog(rollup, true, 105), pauseLog(rollup, false, 108), pauseLog(rollup, true, 115)}
+	m.TryMonitorPause()
+	assert.False(t, m.RollupPaused())
+	assert.True(t, m.contracts[1].paused.Load())
+	assert.Equal(t, uint64(111), m.nextBlock)
+	assert.Equal(t, []common.Address{rollup, messenger}, client.queries[0].Addresses)
+	assert.Equal(t, uint64(110), client.queries[0].ToBlock.Uint64())
+
+	m.TryMonitorPause()
+	assert.True(t, m.RollupPaused())
+
+	// a failed run keeps the state and scans the same blocks again.
+	client.err = errors.New("l1 endpoint error")
+	m.TryMonitorPause()
+	assert.True(t, m.RollupPaused())
+	assert.Equal(t, uint64(121), m.nextBlock)
+}
+
+func TestL2RelayerRollupPaused(t *testing.T) {
+	client := &fakeL1PauseClient{latest: 100, paused: map[common.Address]bool{common.HexToAddress("0x01"): true}}
+	m := NewL1PauseMonitor(context.Background(), &config.L1PauseMonitorConfig{MaxBlockRange: 10}, client, common.HexToAddress("0x01"), common.Address{}, prometheus.NewRegistry())
+	assert.Len(t, m.contracts, 1)
+
+	r := &Layer2Relayer{metrics: initL2RelayerMetrics(prometheus.NewRegistry())}
+	assert.False(t, r.rollupPaused())
+	r.UseL1PauseMonitor(m)
+	assert.False(t, r.rollupPaused())
+	m.TryMonitorPause()
+	assert.True(t, r.rollupPaused())
+}
@@ -2,8 +2,11 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
@@ -19,12 +22,12 @@ import (
 	"scroll-tech/rollup/internal/orm"
 )
 
-// Layer1Relayer is responsible for
-//  1. fetch pending L1Message from db
-//  2. relay pending message to layer 2 node
+// Layer1Relayer is responsible for relaying the base fee and the blob base fee of the
+// layer 1 blocks stored by the watcher to the L1GasPriceOracle of layer 2.
 //
-// Actions are triggered by new head from layer 1 geth node.
-// @todo It's better to be triggered by watcher.
+// It does not relay L1 messages: the sequencer includes them in the queue index order
+// enforced by the L1MessageQueue, so relaying them first by fee or by sender, with an
+// aging policy against starvation, is deferred until messages can be included out of order.
 type Layer1Relayer struct {
 	ctx context.Context
 
@@ -33,9 +36,14 @@ type Layer1Relayer struct {
 	gasOracleSender *sender.Sender
 	l1GasOracleABI  *abi.ABI
 
-	lastGasPrice uint64
-	minGasPrice  uint64
-	gasPriceDiff uint64
+	gasOraclePolicy *gasOracleUpdatePolicy
+	// gasOracleRunClock records the last successful run of ProcessGasPriceOracle.
+	gasOracleRunClock *gasOracleRunClock
+	// blobBaseFeePolicy and blobBaseFee are nil when the blob base fee is not relayed.
+	blobBaseFeePolicy *gasOracleUpdatePolicy
+	blobBaseFee       *blobBaseFeeEWMA
+	// baseFeeAggregator is nil when the base fee of the latest stored L1 block is relayed.
+	baseFeeAggregator *baseFeeAggregator
 
 	l1BlockOrm *orm.L1Block
 	metrics    *l1RelayerMetrics
@@ -62,16 +70,6 @@ func NewLayer1Relayer(ctx context.Context, db *gorm.DB, cfg *config.RelayerConfi
 		return nil, fmt.Errorf("invalid service type for l1_relayer: %v", serviceType)
 	}
 
-	var minGasPrice uint64
-	var gasPriceDiff uint64
-	if cfg.GasOracleConfig != nil {
-		minGasPrice = cfg.GasOracleConfig.MinGasPrice
-		gasPriceDiff = cfg.GasOracleConfig.GasPriceDiff
-	} else {
-		minGasPrice = 0
-		gasPriceDiff = defaultGasPriceDiff
-	}
-
 	l1Relayer := &Layer1Relayer{
 		cfg:        cfg,
 		ctx:        ctx,
@@ -80,8 +78,19 @@ func NewLayer1Relayer(ctx context.Context, db *gorm.DB, cfg *config.RelayerConfi
 		gasOracleSender: gasOracleSender,
 		l1GasOracleABI:  bridgeAbi.L1GasPriceOracleABI,
 
-		minGasPrice:  minGasPrice,
-		gasPriceDiff: gasPriceDiff,
+		gasOraclePolicy:   newGasOracleUpdatePolicy(cfg.GasOracleConfig),
+		gasOracleRunClock: newGasOracleRunClock(time.Now()),
+	}
+
+	if cfg.GasOracleConfig != nil && cfg.GasOracleConfig.BlobBaseFee != nil {
+		l1Relayer.blobBaseFeePolicy = newBlobBaseFeeUpdatePolicy(cfg.GasOracleConfig.BlobBaseFee)
+		l1Relayer.blobBaseFee = &blobBaseFeeEWMA{alpha: cfg.GasOracleConfig.BlobBaseFee.EWMAAlpha}
+	}
+	if cfg.GasOracleConfig != nil && cfg.GasOracleConfig.BaseFeeAggregation != nil {
+		l1Relayer.baseFeeAggregator, err = newBaseFeeAggregator(cfg.GasOracleConfig.BaseFeeAggregation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create base fee aggregator, err: %w", err)
+		}
 	}
 
 	l1Relayer.metrics = initL1RelayerMetrics(reg)
@@ -96,7 +105,29 @@ func NewLayer1Relayer(ctx context.Context, db *gorm.DB, cfg *config.RelayerConfi
 	return l1Relayer, nil
 }
 
-// ProcessGasPriceOracle imports gas price to layer2
+// Senders returns the senders created for the service type of the relayer.
+func (r *Layer1Relayer) Senders() []*sender.Sender {
+	if r.gasOracleSender == nil {
+		return nil
+	}
+	return []*sender.Sender{r.gasOracleSender}
+}
+
+// Flush broadcasts the transactions queued by the gas oracle sender, e.g. before a graceful shutdown.
+func (r *Layer1Relayer) Flush() {
+	if err := r.gasOracleSender.Flush(); err != nil {
+		log.Error("failed to flush sender", "name", r.gasOracleSender.Name(), "service", r.gasOracleSender.Service(), "err", err)
+	}
+}
+
+// GasOracleRunAge returns the time since the last successful run of ProcessGasPriceOracle, or since the relayer was
+// created before the first one.
+func (r *Layer1Relayer) GasOracleRunAge(context.Context) (time.Duration, error) {
+	return r.gasOracleRunClock.age(time.Now()), nil
+}
+
+// ProcessGasPriceOracle imports gas price to layer2, along with the smoothed blob base fee when it is relayed.
+// The blob base fee also triggers an update with its own thresholds, both fees are then set in one transaction.
 func (r *Layer1Relayer) ProcessGasPriceOracle() {
 	r.metrics.rollupL1RelayerGasPriceOraclerRunTotal.Inc()
 	latestBlockHeight, err := r.l1BlockOrm.GetLatestL1BlockHeight(r.ctx)
@@ -119,38 +150,102 @@ func (r *Layer1Relayer) ProcessGasPriceOracle() {
 	block := blocks[0]
 
 	if types.GasOracleStatus(block.GasOracleStatus) == types.GasOraclePending {
-		expectedDelta := r.lastGasPrice * r.gasPriceDiff / gasPriceDiffPrecision
-		if r.lastGasPrice > 0 && expectedDelta == 0 {
-			expectedDelta = 1
-		}
-		// last is undefine or (block.BaseFee >= minGasPrice && exceed diff)
-		if r.lastGasPrice == 0 || (block.BaseFee >= r.minGasPrice && (block.BaseFee >= r.lastGasPrice+expectedDelta || block.BaseFee <= r.lastGasPrice-expectedDelta)) {
-			baseFee := big.NewInt(int64(block.BaseFee))
-			data, err := r.l1GasOracleABI.Pack("setL1BaseFee", baseFee)
+		baseFee := block.BaseFee
+		if r.baseFeeAggregator != nil {
+			baseFee, err = r.baseFeeAggregator.aggregate(r.ctx, block.BaseFee, r.metrics)
 			if err != nil {
-				log.Error("Failed to pack setL1BaseFee", "block.Hash", block.Hash, "block.Height", block.Number, "block.BaseFee", block.BaseFee, "err", err)
+				r.metrics.rollupL1RelayerBaseFeeAggregationFailureTotal.Inc()
+				log.Error("Failed to aggregate l1 base fee, skipping the gas price oracle update", "block.Height", block.Number, "err", err)
 				return
 			}
+		}
+
+		if r.gasOraclePolicy.lastGasPrice == 0 {
+			if err = r.gasOraclePolicy.loadOnChainGasPrice(r.gasOracleSender, r.cfg.GasPriceOracleContractAddress, r.l1GasOracleABI, "l1BaseFee", time.Now()); err != nil {
+				log.Warn("Failed to load l1 base fee from the gas price oracle", "err", err)
+			}
+			r.metrics.rollupL1RelayerLastGasPrice.Set(float64(r.gasOraclePolicy.lastGasPrice))
+		}
+		r.metrics.rollupL1RelayerGasPriceDeviation.Set(float64(r.gasOraclePolicy.deviation(baseFee)) / gasPriceDiffPrecision)
+
+		update, reason := r.gasOraclePolicy.shouldUpdate(baseFee, time.Now())
+		anomaly := reason.isAnomaly()
+
+		var blobBaseFee uint64
+		if r.blobBaseFeePolicy != nil {
+			if r.blobBaseFeePolicy.lastGasPrice == 0 {
+				if err = r.blobBaseFeePolicy.loadOnChainGasPrice(r.gasOracleSender, r.cfg.GasPriceOracleContractAddress, r.l1GasOracleABI, "l1BlobBaseFee", time.Now()); err != nil {
+					log.Warn("Failed to load l1 blob base fee from the gas price oracle", "err", err)
+				}
+				r.metrics.rollupL1RelayerLastBlobBaseFee.Set(float64(r.blobBaseFeePolicy.lastGasPrice))
+			}
+			blobBaseFee = r.blobBaseFee.add(block.Number, block.BlobBaseFee)
+			r.metrics.rollupL1RelayerBlobBaseFeeDeviation.Set(float64(r.blobBaseFeePolicy.deviation(blobBaseFee)) / gasPriceDiffPrecision)
 
-			hash, err := r.gasOracleSender.SendTransaction(block.Hash, &r.cfg.GasPriceOracleContractAddress, big.NewInt(0), data, 0)
+			// Both fees are set in one transaction, an anomaly of either one suppresses the update.
+			blobUpdate, blobReason := r.blobBaseFeePolicy.shouldUpdate(blobBaseFee, time.Now())
+			switch {
+			case anomaly:
+			case blobReason.isAnomaly():
+				update, reason, anomaly = false, "blob_"+blobReason, true
+			case !update && blobUpdate:
+				update, reason = true, "blob_"+blobReason
+			}
+		}
+
+		if anomaly {
+			r.metrics.rollupL1RelayerGasPriceOracleAnomalyTotal.WithLabelValues(string(reason)).Inc()
+			log.Error("Reject anomalous l1 base fee, keeping the previous value", "block.Height", block.Number, "block.BaseFee", block.BaseFee, "baseFee", baseFee, "lastGasPrice", r.gasOraclePolicy.lastGasPrice,
+				"blobBaseFee", blobBaseFee, "reason", reason)
+		}
+		if !update {
+			r.metrics.rollupL1RelayerGasPriceOracleSuppressedTotal.WithLabelValues(string(reason)).Inc()
+			log.Debug("Suppress l1 base fee update", "block.Height", block.Number, "block.BaseFee", block.BaseFee, "baseFee", baseFee, "lastGasPrice", r.gasOraclePolicy.lastGasPrice, "reason", reason)
+		} else {
+			var data []byte
+			if r.blobBaseFeePolicy != nil {
+				data, err = r.l1GasOracleABI.Pack("setL1BaseFeeAndBlobBaseFee", new(big.Int).SetUint64(baseFee), new(big.Int).SetUint64(blobBaseFee))
+			} else {
+				data, err = r.l1GasOracleABI.Pack("setL1BaseFee", new(big.Int).SetUint64(baseFee))
+			}
 			if err != nil {
+				log.Error("Failed to pack setL1BaseFee", "block.Hash", block.Hash, "block.Height", block.Number, "block.BaseFee", block.BaseFee, "baseFee", baseFee, "blobBaseFee", blobBaseFee, "err", err)
+				return
+			}
+
+			hash, err := r.gasOracleSender.SendTransaction(block.Hash, &r.cfg.GasPriceOracleContractAddress, big.NewInt(0), data, 0, sender.WithMetadata(map[string]string{
+				TxLabelL1BlockNumber: strconv.FormatUint(block.Number, 10),
+			}))
+			if err != nil && !errors.Is(err, sender.ErrQueued) {
 				log.Error("Failed to send setL1BaseFee tx to layer2 ", "block.Hash", block.Hash, "block.Height", block.Number, "err", err)
 				return
 			}
+			oracleTxHash := sentTxHash(hash, err)
 
-			err = r.l1BlockOrm.UpdateL1GasOracleStatusAndOracleTxHash(r.ctx, block.Hash, types.GasOracleImporting, hash.String())
+			err = r.l1BlockOrm.UpdateL1GasOracleStatusAndOracleTxHash(r.ctx, block.Hash, types.GasOracleImporting, oracleTxHash)
 			if err != nil {
 				log.Error("UpdateGasOracleStatusAndOracleTxHash failed", "block.Hash", block.Hash, "block.Height", block.Number, "err", err)
 				return
 			}
-			r.lastGasPrice = block.BaseFee
-			r.metrics.rollupL1RelayerLastGasPrice.Set(float64(r.lastGasPrice))
-			log.Info("Update l1 base fee", "txHash", hash.String(), "baseFee", baseFee)
+			r.gasOraclePolicy.updated(baseFee, time.Now())
+			r.metrics.rollupL1RelayerLastGasPrice.Set(float64(baseFee))
+			if r.blobBaseFeePolicy != nil {
+				r.blobBaseFeePolicy.updated(blobBaseFee, time.Now())
+				r.metrics.rollupL1RelayerLastBlobBaseFee.Set(float64(blobBaseFee))
+			}
+			r.metrics.rollupL1RelayerGasPriceOracleUpdateTotal.WithLabelValues(string(reason)).Inc()
+			log.Info("Update l1 base fee", "txHash", oracleTxHash, "baseFee", baseFee, "blobBaseFee", blobBaseFee, "reason", reason)
 		}
 	}
+	r.gasOracleRunClock.ran(time.Now())
 }
 
 func (r *Layer1Relayer) handleConfirmation(cfm *sender.Confirmation) {
+	if cfm.SenderStatus != sender.SenderStatusNone {
+		log.Warn("Sender status changed", "sender type", cfm.SenderType, "status", cfm.SenderStatus)
+		return
+	}
+
 	switch cfm.SenderType {
 	case types.SenderTypeL1GasOracle:
 		var status types.GasOracleStatus
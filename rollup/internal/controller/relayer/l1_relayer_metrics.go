@@ -8,10 +8,21 @@ import (
 )
 
 type l1RelayerMetrics struct {
-	rollupL1RelayerGasPriceOraclerRunTotal      prometheus.Counter
-	rollupL1RelayerLastGasPrice                 prometheus.Gauge
-	rollupL1UpdateGasOracleConfirmedTotal       prometheus.Counter
-	rollupL1UpdateGasOracleConfirmedFailedTotal prometheus.Counter
+	rollupL1RelayerGasPriceOraclerRunTotal        prometheus.Counter
+	rollupL1RelayerLastGasPrice                   prometheus.Gauge
+	rollupL1RelayerGasPriceDeviation              prometheus.Gauge
+	rollupL1RelayerLastBlobBaseFee                prometheus.Gauge
+	rollupL1RelayerBlobBaseFeeDeviation           prometheus.Gauge
+	rollupL1RelayerGasPriceOracleUpdateTotal      *prometheus.CounterVec
+	rollupL1RelayerGasPriceOracleSuppressedTotal  *prometheus.CounterVec
+	rollupL1RelayerGasPriceOracleAnomalyTotal     *prometheus.CounterVec
+	rollupL1RelayerBaseFeeSourceReading           *prometheus.GaugeVec
+	rollupL1RelayerBaseFeeSourceFailureTotal      *prometheus.CounterVec
+	rollupL1RelayerBaseFeeSourceOutlierTotal      *prometheus.CounterVec
+	rollupL1RelayerAggregatedBaseFee              prometheus.Gauge
+	rollupL1RelayerBaseFeeAggregationFailureTotal prometheus.Counter
+	rollupL1UpdateGasOracleConfirmedTotal         prometheus.Counter
+	rollupL1UpdateGasOracleConfirmedFailedTotal   prometheus.Counter
 }
 
 var (
@@ -30,6 +41,50 @@ func initL1RelayerMetrics(reg prometheus.Registerer) *l1RelayerMetrics {
 				Name: "rollup_layer1_gas_price_latest_gas_price",
 				Help: "The latest gas price of rollup relayer l1",
 			}),
+			rollupL1RelayerGasPriceDeviation: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer1_gas_price_deviation",
+				Help: "The relative deviation of the l1 base fee from the last on-chain value of the gas price oracle",
+			}),
+			rollupL1RelayerLastBlobBaseFee: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer1_gas_price_latest_blob_base_fee",
+				Help: "The latest smoothed blob base fee relayed by rollup relayer l1",
+			}),
+			rollupL1RelayerBlobBaseFeeDeviation: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer1_blob_base_fee_deviation",
+				Help: "The relative deviation of the smoothed l1 blob base fee from the last on-chain value of the gas price oracle",
+			}),
+			rollupL1RelayerGasPriceOracleUpdateTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer1_gas_price_oracle_update_total",
+				Help: "The total number of layer1 gas price oracle updates by reason",
+			}, []string{"reason"}),
+			rollupL1RelayerGasPriceOracleSuppressedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer1_gas_price_oracle_suppressed_total",
+				Help: "The total number of suppressed layer1 gas price oracle updates by reason",
+			}, []string{"reason"}),
+			rollupL1RelayerGasPriceOracleAnomalyTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer1_gas_price_oracle_anomaly_total",
+				Help: "The total number of layer1 gas price oracle updates rejected as out of the sanity bounds by reason",
+			}, []string{"reason"}),
+			rollupL1RelayerBaseFeeSourceReading: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_layer1_base_fee_source_reading",
+				Help: "The latest l1 base fee read from each base fee source",
+			}, []string{"source"}),
+			rollupL1RelayerBaseFeeSourceFailureTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer1_base_fee_source_failure_total",
+				Help: "The total number of failed or timed out l1 base fee readings by source",
+			}, []string{"source"}),
+			rollupL1RelayerBaseFeeSourceOutlierTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer1_base_fee_source_outlier_total",
+				Help: "The total number of l1 base fee readings rejected as outliers by source",
+			}, []string{"source"}),
+			rollupL1RelayerAggregatedBaseFee: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer1_aggregated_base_fee",
+				Help: "The latest l1 base fee aggregated from the base fee sources",
+			}),
+			rollupL1RelayerBaseFeeAggregationFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer1_base_fee_aggregation_failure_total",
+				Help: "The total number of skipped gas price oracle updates for lack of l1 base fee readings",
+			}),
 			rollupL1UpdateGasOracleConfirmedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 				Name: "rollup_layer1_update_gas_oracle_confirmed_total",
 				Help: "The total number of updating layer1 gas oracle confirmed",
@@ -2,13 +2,16 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -17,6 +20,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/encoding/codecv0"
@@ -24,7 +28,9 @@ import (
 
 	bridgeAbi "scroll-tech/rollup/abi"
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/quorum"
 	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/events"
 	"scroll-tech/rollup/internal/orm"
 )
 
@@ -53,13 +59,26 @@ type Layer2Relayer struct {
 	gasOracleSender *sender.Sender
 	l2GasOracleABI  *abi.ABI
 
-	lastGasPrice uint64
-	minGasPrice  uint64
-	gasPriceDiff uint64
+	gasOraclePolicy *gasOracleUpdatePolicy
+	// gasOracleRunClock records the last successful run of ProcessGasPriceOracle.
+	gasOracleRunClock *gasOracleRunClock
 
 	// Used to get batch status from chain_monitor api.
 	chainMonitorClient *resty.Client
 
+	// Used to verify the committed batch hashes before finalizing, nil when not configured.
+	l1Quorum *quorum.Client
+	// Used to suspend the commit and finalize transactions while the rollup contract is paused, nil when not configured.
+	l1PauseMonitor *L1PauseMonitor
+
+	// Used to publish the lifecycle events of the batches, nil when not configured.
+	events *events.Bus
+	// The last batch whose proof was published, so that a proof is published once while finalizing is retried.
+	lastProofBatchHash string
+
+	// Used to spread the batch commits over time, nil when the commits are not paced.
+	commitPacer *commitPacer
+
 	metrics *l2RelayerMetrics
 }
 
@@ -103,16 +122,6 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		return nil, fmt.Errorf("invalid service type for l2_relayer: %v", serviceType)
 	}
 
-	var minGasPrice uint64
-	var gasPriceDiff uint64
-	if cfg.GasOracleConfig != nil {
-		minGasPrice = cfg.GasOracleConfig.MinGasPrice
-		gasPriceDiff = cfg.GasOracleConfig.GasPriceDiff
-	} else {
-		minGasPrice = 0
-		gasPriceDiff = defaultGasPriceDiff
-	}
-
 	layer2Relayer := &Layer2Relayer{
 		ctx: ctx,
 		db:  db,
@@ -130,8 +139,10 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		gasOracleSender: gasOracleSender,
 		l2GasOracleABI:  bridgeAbi.L2GasPriceOracleABI,
 
-		minGasPrice:  minGasPrice,
-		gasPriceDiff: gasPriceDiff,
+		gasOraclePolicy:   newGasOracleUpdatePolicy(cfg.GasOracleConfig),
+		gasOracleRunClock: newGasOracleRunClock(time.Now()),
+
+		commitPacer: newCommitPacer(cfg.CommitPacing),
 
 		cfg: cfg,
 	}
@@ -210,7 +221,7 @@ func (r *Layer2Relayer) initializeGenesis() error {
 		}
 
 		var dbBatch *orm.Batch
-		dbBatch, err = r.batchOrm.InsertBatch(r.ctx, batch, dbTX)
+		dbBatch, err = r.batchOrm.InsertBatch(r.ctx, batch, codecv0.CodecV0Version, dbTX)
 		if err != nil {
 			return fmt.Errorf("failed to insert batch: %v", err)
 		}
@@ -249,11 +260,14 @@ func (r *Layer2Relayer) commitGenesisBatch(batchHash string, batchHeader []byte,
 	}
 
 	// submit genesis batch to L1 rollup contract
-	txHash, err := r.commitSender.SendTransaction(batchHash, &r.cfg.RollupContractAddress, big.NewInt(0), calldata, 0)
-	if err != nil {
+	txHash, err := r.commitSender.SendTransaction(batchHash, &r.cfg.RollupContractAddress, big.NewInt(0), calldata, 0, sender.WithMetadata(map[string]string{
+		TxLabelBatchHash:  batchHash,
+		TxLabelBatchIndex: "0",
+	}))
+	if err != nil && !errors.Is(err, sender.ErrQueued) {
 		return fmt.Errorf("failed to send import genesis batch tx to L1, error: %v", err)
 	}
-	log.Info("importGenesisBatch transaction sent", "contract", r.cfg.RollupContractAddress, "txHash", txHash.String(), "batchHash", batchHash)
+	log.Info("importGenesisBatch transaction sent", "contract", r.cfg.RollupContractAddress, "txHash", sentTxHash(txHash, err), "batchHash", batchHash)
 
 	// wait for confirmation
 	// we assume that no other transactions are sent before initializeGenesis completes
@@ -268,7 +282,7 @@ func (r *Layer2Relayer) commitGenesisBatch(batchHash string, batchHeader []byte,
 
 		// timeout
 		case <-time.After(5 * time.Minute):
-			return fmt.Errorf("import genesis timeout after 5 minutes, original txHash: %v", txHash.String())
+			return fmt.Errorf("import genesis timeout after 5 minutes, original txHash: %v", sentTxHash(txHash, err))
 
 		// handle confirmation
 		case confirmation := <-r.commitSender.ConfirmChan():
@@ -284,6 +298,32 @@ func (r *Layer2Relayer) commitGenesisBatch(batchHash string, batchHeader []byte,
 	}
 }
 
+// Senders returns the senders created for the service type of the relayer.
+func (r *Layer2Relayer) Senders() []*sender.Sender {
+	var senders []*sender.Sender
+	for _, s := range []*sender.Sender{r.gasOracleSender, r.commitSender, r.finalizeSender} {
+		if s != nil {
+			senders = append(senders, s)
+		}
+	}
+	return senders
+}
+
+// Flush broadcasts the transactions queued by the senders of the relayer, e.g. before a graceful shutdown.
+func (r *Layer2Relayer) Flush() {
+	for _, s := range r.Senders() {
+		if err := s.Flush(); err != nil {
+			log.Error("failed to flush sender", "name", s.Name(), "service", s.Service(), "err", err)
+		}
+	}
+}
+
+// GasOracleRunAge returns the time since the last successful run of ProcessGasPriceOracle, or since the relayer was
+// created before the first one.
+func (r *Layer2Relayer) GasOracleRunAge(context.Context) (time.Duration, error) {
+	return r.gasOracleRunClock.age(time.Now()), nil
+}
+
 // ProcessGasPriceOracle imports gas price to layer1
 func (r *Layer2Relayer) ProcessGasPriceOracle() {
 	r.metrics.rollupL2RelayerGasPriceOraclerRunTotal.Inc()
@@ -300,53 +340,79 @@ func (r *Layer2Relayer) ProcessGasPriceOracle() {
 			return
 		}
 		suggestGasPriceUint64 := uint64(suggestGasPrice.Int64())
-		expectedDelta := r.lastGasPrice * r.gasPriceDiff / gasPriceDiffPrecision
-		if r.lastGasPrice > 0 && expectedDelta == 0 {
-			expectedDelta = 1
+
+		if r.gasOraclePolicy.lastGasPrice == 0 {
+			if err = r.gasOraclePolicy.loadOnChainGasPrice(r.gasOracleSender, r.cfg.GasPriceOracleContractAddress, r.l2GasOracleABI, "l2BaseFee", time.Now()); err != nil {
+				log.Warn("Failed to load l2 base fee from the gas price oracle", "err", err)
+			}
+			r.metrics.rollupL2RelayerLastGasPrice.Set(float64(r.gasOraclePolicy.lastGasPrice))
 		}
+		r.metrics.rollupL2RelayerGasPriceDeviation.Set(float64(r.gasOraclePolicy.deviation(suggestGasPriceUint64)) / gasPriceDiffPrecision)
 
-		// last is undefine or (suggestGasPriceUint64 >= minGasPrice && exceed diff)
-		if r.lastGasPrice == 0 || (suggestGasPriceUint64 >= r.minGasPrice && (suggestGasPriceUint64 >= r.lastGasPrice+expectedDelta || suggestGasPriceUint64 <= r.lastGasPrice-expectedDelta)) {
+		update, reason := r.gasOraclePolicy.shouldUpdate(suggestGasPriceUint64, time.Now())
+		if reason.isAnomaly() {
+			r.metrics.rollupL2RelayerGasPriceOracleAnomalyTotal.WithLabelValues(string(reason)).Inc()
+			log.Error("Reject anomalous l2 gas price, keeping the previous value", "batch.Hash", batch.Hash, "GasPrice", suggestGasPriceUint64, "lastGasPrice", r.gasOraclePolicy.lastGasPrice, "reason", reason)
+		}
+		if !update {
+			r.metrics.rollupL2RelayerGasPriceOracleSuppressedTotal.WithLabelValues(string(reason)).Inc()
+			log.Debug("Suppress l2 gas price update", "batch.Hash", batch.Hash, "GasPrice", suggestGasPriceUint64, "lastGasPrice", r.gasOraclePolicy.lastGasPrice, "reason", reason)
+		} else {
 			data, err := r.l2GasOracleABI.Pack("setL2BaseFee", suggestGasPrice)
 			if err != nil {
 				log.Error("Failed to pack setL2BaseFee", "batch.Hash", batch.Hash, "GasPrice", suggestGasPrice.Uint64(), "err", err)
 				return
 			}
 
-			hash, err := r.gasOracleSender.SendTransaction(batch.Hash, &r.cfg.GasPriceOracleContractAddress, big.NewInt(0), data, 0)
-			if err != nil {
+			hash, err := r.gasOracleSender.SendTransaction(batch.Hash, &r.cfg.GasPriceOracleContractAddress, big.NewInt(0), data, 0, sender.WithMetadata(map[string]string{
+				TxLabelBatchHash:  batch.Hash,
+				TxLabelBatchIndex: strconv.FormatUint(batch.Index, 10),
+			}))
+			if err != nil && !errors.Is(err, sender.ErrQueued) {
 				log.Error("Failed to send setL2BaseFee tx to layer2 ", "batch.Hash", batch.Hash, "err", err)
 				return
 			}
+			oracleTxHash := sentTxHash(hash, err)
 
-			err = r.batchOrm.UpdateL2GasOracleStatusAndOracleTxHash(r.ctx, batch.Hash, types.GasOracleImporting, hash.String())
+			err = r.batchOrm.UpdateL2GasOracleStatusAndOracleTxHash(r.ctx, batch.Hash, types.GasOracleImporting, oracleTxHash)
 			if err != nil {
 				log.Error("UpdateGasOracleStatusAndOracleTxHash failed", "batch.Hash", batch.Hash, "err", err)
 				return
 			}
-			r.lastGasPrice = suggestGasPriceUint64
-			r.metrics.rollupL2RelayerLastGasPrice.Set(float64(r.lastGasPrice))
-			log.Info("Update l2 gas price", "txHash", hash.String(), "GasPrice", suggestGasPrice)
+			r.gasOraclePolicy.updated(suggestGasPriceUint64, time.Now())
+			r.metrics.rollupL2RelayerLastGasPrice.Set(float64(suggestGasPriceUint64))
+			r.metrics.rollupL2RelayerGasPriceOracleUpdateTotal.WithLabelValues(string(reason)).Inc()
+			log.Info("Update l2 gas price", "txHash", oracleTxHash, "GasPrice", suggestGasPrice, "reason", reason)
 		}
 	}
+	r.gasOracleRunClock.ran(time.Now())
+}
+
+// committedBatchStateOverrides returns the state override set of the rollup contract in which `committedBatches[index]` is batchHash.
+func committedBatchStateOverrides(rollupContract common.Address, committedBatchesSlot common.Hash, index uint64, batchHash common.Hash) sender.StateOverrides {
+	// The storage key of a mapping entry is keccak256(key . slot).
+	key := crypto.Keccak256Hash(common.BigToHash(new(big.Int).SetUint64(index)).Bytes(), committedBatchesSlot.Bytes())
+	return sender.StateOverrides{
+		rollupContract: {StateDiff: map[common.Hash]common.Hash{key: batchHash}},
+	}
 }
 
 // ProcessPendingBatches processes the pending batches by sending commitBatch transactions to layer 1.
 func (r *Layer2Relayer) ProcessPendingBatches() {
+	if r.rollupPaused() {
+		return
+	}
+
 	// get pending batches from database in ascending order by their index.
 	batches, err := r.batchOrm.GetFailedAndPendingBatches(r.ctx, 5)
 	if err != nil {
 		log.Error("Failed to fetch pending L2 batches", "err", err)
 		return
 	}
+	catchingUp := len(batches) > 0 && r.isCommitCatchingUp(batches[0])
 	for _, batch := range batches {
 		r.metrics.rollupL2RelayerProcessPendingBatchTotal.Inc()
-		// get current header and parent header.
-		daBatch, err := codecv0.NewDABatchFromBytes(batch.BatchHeader)
-		if err != nil {
-			log.Error("Failed to initialize new DA batch from bytes", "index", batch.Index, "hash", batch.Hash, "err", err)
-			return
-		}
+		// get parent header.
 		parentBatch := &orm.Batch{}
 		if batch.Index > 0 {
 			parentBatch, err = r.batchOrm.GetBatchByIndex(r.ctx, batch.Index-1)
@@ -360,6 +426,25 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 					"index", parentBatch.Index, "tx hash", parentBatch.CommitTxHash)
 				return
 			}
+
+			if parentBatch.Hash != batch.ParentBatchHash {
+				// the commit would revert, the batch has to be proposed again on top of the current parent.
+				log.Error("Batch was proposed on top of a stale parent batch, halting further committing",
+					"index", batch.Index, "parent hash", batch.ParentBatchHash, "current parent hash", parentBatch.Hash)
+				if r.cfg.ReproposeStaleBatches {
+					if err = r.reproposeBatches(batch); err != nil {
+						log.Error("failed to propose batches again", "start index", batch.Index, "err", err)
+					}
+				}
+				return
+			}
+		}
+
+		commitGas := uint64(float64(batch.TotalL1CommitGas) * r.cfg.L1CommitGasLimitMultiplier)
+		if r.commitPacer != nil && !catchingUp && !r.commitPacer.allow(commitGas, time.Now()) {
+			r.metrics.rollupL2RelayerCommitPacedTotal.Inc()
+			log.Debug("Commit held by the commit pacing", "index", batch.Index, "commit gas", commitGas)
+			return
 		}
 
 		// get the metadata of chunks for the batch
@@ -371,47 +456,43 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 			return
 		}
 
-		encodedChunks := make([][]byte, len(dbChunks))
-		for i, c := range dbChunks {
-			var blocks []*encoding.Block
-			blocks, err = r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
-			if err != nil {
-				log.Error("Failed to fetch blocks", "start number", c.StartBlockNumber, "end number", c.EndBlockNumber, "error", err)
-				return
-			}
-			chunk := &encoding.Chunk{
-				Blocks: blocks,
-			}
-			var daChunk *codecv0.DAChunk
-			daChunk, err = codecv0.NewDAChunk(chunk, c.TotalL1MessagesPoppedBefore)
-			if err != nil {
-				log.Error("Failed to initialize new DA chunk", "start number", c.StartBlockNumber, "end number", c.EndBlockNumber, "error", err)
-				return
-			}
-			var daChunkBytes []byte
-			daChunkBytes, err = daChunk.Encode()
-			if err != nil {
-				log.Error("Failed to encode DA chunk", "start number", c.StartBlockNumber, "end number", c.EndBlockNumber, "error", err)
-				return
-			}
-			encodedChunks[i] = daChunkBytes
+		payload, err := r.constructCommitPayload(batch, dbChunks)
+		if err != nil {
+			log.Error("Failed to construct commit payload", "index", batch.Index, "codec version", batch.CodecVersion, "error", err)
+			return
 		}
-
-		calldata, err := r.l1RollupABI.Pack("commitBatch", daBatch.Version, parentBatch.BatchHeader, encodedChunks, daBatch.SkippedL1MessageBitmap)
+		calldata, err := r.l1RollupABI.Pack("commitBatch", payload.version, parentBatch.BatchHeader, payload.chunks, payload.skippedL1MessageBitmap)
 		if err != nil {
 			log.Error("Failed to pack commitBatch", "index", batch.Index, "error", err)
 			return
 		}
 
 		// send transaction
-		fallbackGasLimit := uint64(float64(batch.TotalL1CommitGas) * r.cfg.L1CommitGasLimitMultiplier)
+		fallbackGasLimit := commitGas
 		if types.RollupStatus(batch.RollupStatus) == types.RollupCommitFailed {
 			// use eth_estimateGas if this batch has been committed failed.
 			fallbackGasLimit = 0
 			log.Warn("Batch commit previously failed, using eth_estimateGas for the re-submission", "hash", batch.Hash)
 		}
-		txHash, err := r.commitSender.SendTransaction(batch.Hash, &r.cfg.RollupContractAddress, big.NewInt(0), calldata, fallbackGasLimit)
-		if err != nil {
+		var sendOpts []sender.SendOption
+		if batch.Index > 0 && types.RollupStatus(parentBatch.RollupStatus) == types.RollupCommitting {
+			// The parent batch commit is still in flight, never broadcast this commit before it.
+			sendOpts = append(sendOpts, sender.WithDependsOn(parentBatch.Hash))
+			if r.cfg.CommittedBatchesSlot != (common.Hash{}) {
+				// Estimate this commit assuming the parent commit has landed.
+				sendOpts = append(sendOpts, sender.WithStateOverrides(committedBatchStateOverrides(r.cfg.RollupContractAddress, r.cfg.CommittedBatchesSlot, parentBatch.Index, common.HexToHash(parentBatch.Hash))))
+			}
+		}
+		ctx, span := tracing.Start(r.ctx, "l2_relayer.commit_batch", "batch_index", batch.Index)
+		ctx = tracing.WithCorrelationID(ctx, "batch:"+batch.Hash)
+		sendOpts = append(sendOpts, sender.WithTraceContext(ctx), sender.WithMetadata(map[string]string{
+			TxLabelBatchHash:  batch.Hash,
+			TxLabelBatchIndex: strconv.FormatUint(batch.Index, 10),
+		}))
+		txHash, err := r.commitSender.SendTransaction(batch.Hash, &r.cfg.RollupContractAddress, big.NewInt(0), calldata, fallbackGasLimit, sendOpts...)
+		if err != nil && !errors.Is(err, sender.ErrQueued) {
+			span.RecordError(err)
+			span.End()
 			log.Error(
 				"Failed to send commitBatch tx to layer1",
 				"index", batch.Index,
@@ -430,18 +511,135 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 			return
 		}
 
-		err = r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, batch.Hash, txHash.String(), types.RollupCommitting)
+		commitTxHash := sentTxHash(txHash, err)
+		err = r.batchOrm.CompareAndSwapCommitTxHashAndRollupStatus(ctx, batch, commitTxHash, types.RollupCommitting)
+		span.RecordError(err)
+		span.End()
+		if errors.Is(err, types.ErrStatusConflict) {
+			// e.g. the batch was reverted to be proposed again meanwhile, the commit is left to fail in layer1.
+			log.Error("Batch changed while committing it, halting further committing", "index", batch.Index, "hash", batch.Hash, "tx hash", commitTxHash, "err", err)
+			return
+		}
 		if err != nil {
-			log.Error("UpdateCommitTxHashAndRollupStatus failed", "hash", batch.Hash, "index", batch.Index, "err", err)
+			log.Error("CompareAndSwapCommitTxHashAndRollupStatus failed", "index", batch.Index, "hash", batch.Hash, "err", err)
 			return
 		}
+		if r.commitPacer != nil {
+			r.commitPacer.record(commitGas, time.Now())
+		}
 		r.metrics.rollupL2RelayerProcessPendingBatchSuccessTotal.Inc()
-		log.Info("Sent the commitBatch tx to layer1", "batch index", batch.Index, "batch hash", batch.Hash, "tx hash", txHash.Hex())
+		log.Info("Sent the commitBatch tx to layer1", "batch index", batch.Index, "batch hash", batch.Hash, "tx hash", commitTxHash)
+	}
+}
+
+// isCommitCatchingUp reports whether the commit pacing is bypassed to catch up with the pending batches, oldestBatch
+// being the first pending batch. The commits are paced as usual if the pending batches can not be counted.
+func (r *Layer2Relayer) isCommitCatchingUp(oldestBatch *orm.Batch) bool {
+	if r.commitPacer == nil {
+		return false
+	}
+
+	var pendingBatches uint64
+	if r.commitPacer.catchUpPendingBatches > 0 {
+		var err error
+		if pendingBatches, err = r.batchOrm.GetFailedAndPendingBatchCount(r.ctx); err != nil {
+			log.Error("Failed to count the pending batches for the commit pacing", "err", err)
+		}
+	}
+
+	catchingUp := r.commitPacer.isCatchingUp(oldestBatch.CreatedAt, pendingBatches, time.Now())
+	if catchingUp {
+		r.metrics.rollupL2RelayerCommitCatchingUp.Set(1)
+	} else {
+		r.metrics.rollupL2RelayerCommitCatchingUp.Set(0)
+	}
+	return catchingUp
+}
+
+// commitPayload is the commit data of one batch.
+type commitPayload struct {
+	version                uint8
+	chunks                 [][]byte
+	skippedL1MessageBitmap []byte
+}
+
+// constructCommitPayload returns the commit data of the batch encoded with its codec version.
+func (r *Layer2Relayer) constructCommitPayload(dbBatch *orm.Batch, dbChunks []*orm.Chunk) (*commitPayload, error) {
+	chunks, err := loadChunkBlocks(r.ctx, r.l2BlockOrm, dbChunks)
+	if err != nil {
+		return nil, err
+	}
+	return encodeCommitPayload(dbBatch, dbChunks, chunks)
+}
+
+// loadChunkBlocks returns the chunks with their L2 blocks.
+func loadChunkBlocks(ctx context.Context, l2BlockOrm *orm.L2Block, dbChunks []*orm.Chunk) ([]*encoding.Chunk, error) {
+	chunks := make([]*encoding.Chunk, len(dbChunks))
+	for i, c := range dbChunks {
+		blocks, err := l2BlockOrm.GetL2BlocksInRange(ctx, c.StartBlockNumber, c.EndBlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch blocks, start number: %d, end number: %d, err: %w", c.StartBlockNumber, c.EndBlockNumber, err)
+		}
+		chunks[i] = &encoding.Chunk{Blocks: blocks}
+	}
+	return chunks, nil
+}
+
+// encodeCommitPayload encodes the commit data of the batch from the chunks of loadChunkBlocks.
+func encodeCommitPayload(dbBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) (*commitPayload, error) {
+	encodedChunks := make([][]byte, len(dbChunks))
+	switch uint8(dbBatch.CodecVersion) {
+	case codecv0.CodecV0Version:
+		daBatch, err := codecv0.NewDABatchFromBytes(dbBatch.BatchHeader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize new DA batch from bytes, err: %w", err)
+		}
+		for i, c := range dbChunks {
+			daChunk, err := codecv0.NewDAChunk(chunks[i], c.TotalL1MessagesPoppedBefore)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize new DA chunk, start number: %d, end number: %d, err: %w", c.StartBlockNumber, c.EndBlockNumber, err)
+			}
+			if encodedChunks[i], err = daChunk.Encode(); err != nil {
+				return nil, fmt.Errorf("failed to encode DA chunk, start number: %d, end number: %d, err: %w", c.StartBlockNumber, c.EndBlockNumber, err)
+			}
+		}
+		return &commitPayload{version: daBatch.Version, chunks: encodedChunks, skippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap}, nil
+	default:
+		return nil, fmt.Errorf("unsupported codec version: %d", dbBatch.CodecVersion)
 	}
 }
 
 // ProcessCommittedBatches submit proof to layer 1 rollup contract
+// UseL1Quorum verifies the committed batch hashes through the quorum of L1 endpoints before finalizing.
+func (r *Layer2Relayer) UseL1Quorum(l1Quorum *quorum.Client) {
+	r.l1Quorum = l1Quorum
+}
+
+// UseL1PauseMonitor suspends the commit and finalize transactions while the monitor reports the rollup contract paused.
+func (r *Layer2Relayer) UseL1PauseMonitor(monitor *L1PauseMonitor) {
+	r.l1PauseMonitor = monitor
+}
+
+// UseEventBus publishes the commit, proof and finalization events of the batches on bus.
+func (r *Layer2Relayer) UseEventBus(bus *events.Bus) {
+	r.events = bus
+}
+
+// rollupPaused reports whether the rollup contract is paused on L1, the transactions sent to it would revert.
+func (r *Layer2Relayer) rollupPaused() bool {
+	if r.l1PauseMonitor == nil || !r.l1PauseMonitor.RollupPaused() {
+		return false
+	}
+	r.metrics.rollupL2RelayerL1PausedSkippedTotal.Inc()
+	log.Debug("rollup contract paused on l1, skip submitting commit and finalize transactions")
+	return true
+}
+
 func (r *Layer2Relayer) ProcessCommittedBatches() {
+	if r.rollupPaused() {
+		return
+	}
+
 	// retrieves the earliest batch whose rollup status is 'committed'
 	fields := map[string]interface{}{
 		"rollup_status": types.RollupCommitted,
@@ -476,6 +674,10 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 		}
 
 	case types.ProvingTaskVerified:
+		if batch.Hash != r.lastProofBatchHash {
+			r.lastProofBatchHash = batch.Hash
+			r.events.Publish(events.Event{Type: events.ProofReceived, Index: batch.Index, Hash: batch.Hash})
+		}
 		log.Info("Start to roll up zk proof", "hash", batch.Hash)
 		r.metrics.rollupL2RelayerProcessCommittedBatchesFinalizedTotal.Inc()
 		if err := r.finalizeBatch(batch, true); err != nil {
@@ -506,6 +708,10 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 }
 
 func (r *Layer2Relayer) finalizeBatch(batch *orm.Batch, withProof bool) error {
+	ctx, span := tracing.Start(r.ctx, "l2_relayer.finalize_batch", "batch_index", batch.Index, "with_proof", withProof)
+	defer span.End()
+	ctx = tracing.WithCorrelationID(ctx, "batch:"+batch.Hash)
+
 	// Check batch status before send `finalizeBatch` tx.
 	if r.cfg.ChainMonitor.Enabled {
 		var batchStatus bool
@@ -521,6 +727,12 @@ func (r *Layer2Relayer) finalizeBatch(batch *orm.Batch, withProof bool) error {
 			return err
 		}
 	}
+	if err := r.verifyCommittedBatchHash(batch); err != nil {
+		return err
+	}
+	if err := r.verifyBatchDataAvailability(batch); err != nil {
+		return err
+	}
 
 	var parentBatchStateRoot string
 	if batch.Index > 0 {
@@ -575,9 +787,13 @@ func (r *Layer2Relayer) finalizeBatch(batch *orm.Batch, withProof bool) error {
 	}
 
 	// add suffix `-finalize` to avoid duplication with commit tx in unit tests
-	txHash, err := r.finalizeSender.SendTransaction(batch.Hash, &r.cfg.RollupContractAddress, big.NewInt(0), txCalldata, 0)
-	finalizeTxHash := &txHash
-	if err != nil {
+	txHash, err := r.finalizeSender.SendTransaction(batch.Hash, &r.cfg.RollupContractAddress, big.NewInt(0), txCalldata, 0, sender.WithTraceContext(ctx), sender.WithMetadata(map[string]string{
+		TxLabelBatchHash:  batch.Hash,
+		TxLabelBatchIndex: strconv.FormatUint(batch.Index, 10),
+	}))
+	finalizeTxHash := sentTxHash(txHash, err)
+	if err != nil && !errors.Is(err, sender.ErrQueued) {
+		span.RecordError(err)
 		log.Error(
 			"finalizeBatch in layer1 failed",
 			"with proof", withProof,
@@ -600,8 +816,9 @@ func (r *Layer2Relayer) finalizeBatch(batch *orm.Batch, withProof bool) error {
 	log.Info("finalizeBatch in layer1", "with proof", withProof, "index", batch.Index, "batch hash", batch.Hash, "tx hash", batch.Hash)
 
 	// record and sync with db, @todo handle db error
-	if err := r.batchOrm.UpdateFinalizeTxHashAndRollupStatus(r.ctx, batch.Hash, finalizeTxHash.String(), types.RollupFinalizing); err != nil {
-		log.Error("UpdateFinalizeTxHashAndRollupStatus failed", "index", batch.Index, "batch hash", batch.Hash, "tx hash", finalizeTxHash.String(), "err", err)
+	if err := r.batchOrm.CompareAndSwapFinalizeTxHashAndRollupStatus(ctx, batch.Hash, batch.Version, finalizeTxHash, types.RollupFinalizing); err != nil {
+		span.RecordError(err)
+		log.Error("CompareAndSwapFinalizeTxHashAndRollupStatus failed", "index", batch.Index, "batch hash", batch.Hash, "tx hash", finalizeTxHash, "err", err)
 		return err
 	}
 	r.metrics.rollupL2RelayerProcessCommittedBatchesFinalizedSuccessTotal.Inc()
@@ -654,7 +871,62 @@ func (r *Layer2Relayer) getBatchStatusByIndex(batch *orm.Batch) (bool, error) {
 	return response.Data, nil
 }
 
+// verifyCommittedBatchHash checks that the quorum of L1 endpoints agrees that the batch is committed with its hash, so
+// that a forged commit event reported by a single endpoint does not lead to finalizing the wrong batch.
+func (r *Layer2Relayer) verifyCommittedBatchHash(batch *orm.Batch) error {
+	if r.l1Quorum == nil {
+		return nil
+	}
+
+	data, err := r.l1RollupABI.Pack("committedBatches", new(big.Int).SetUint64(batch.Index))
+	if err != nil {
+		return fmt.Errorf("failed to pack committedBatches, index: %v, err: %w", batch.Index, err)
+	}
+	output, err := r.l1Quorum.CallContract(r.ctx, ethereum.CallMsg{To: &r.cfg.RollupContractAddress, Data: data}, nil)
+	if err != nil {
+		log.Warn("failed to read the committed batch hash through the l1 quorum", "index", batch.Index, "err", err)
+		return err
+	}
+	result, err := r.l1RollupABI.Unpack("committedBatches", output)
+	if err != nil || len(result) == 0 {
+		return fmt.Errorf("failed to unpack committedBatches, index: %v, err: %v", batch.Index, err)
+	}
+	committedHash, ok := result[0].([32]byte)
+	if !ok {
+		return fmt.Errorf("unexpected committedBatches result: %v", result[0])
+	}
+	if common.Hash(committedHash).Hex() != batch.Hash {
+		r.metrics.rollupL2RelayerCommittedBatchHashMismatchTotal.Inc()
+		log.Error("committed batch hash on L1 does not match, stop finalizing and check the reason", "index", batch.Index, "hash", batch.Hash, "committed hash", common.Hash(committedHash).Hex())
+		return fmt.Errorf("committed batch hash mismatch, index: %v, hash: %v, committed hash: %v", batch.Index, batch.Hash, common.Hash(committedHash).Hex())
+	}
+	return nil
+}
+
+// verifyBatchDataAvailability checks that the commit data of the batch is verified by the DAVerifier before it is
+// finalized, the check is skipped when the verifier is not configured.
+func (r *Layer2Relayer) verifyBatchDataAvailability(batch *orm.Batch) error {
+	if r.cfg.DAVerifier == nil || batch.Index == 0 {
+		return nil
+	}
+	switch status := types.DAVerificationStatus(batch.DAVerificationStatus); status {
+	case types.DAVerificationVerified:
+		return nil
+	case types.DAVerificationMismatch:
+		r.metrics.rollupL2RelayerDAMismatchFinalizeHeldTotal.Inc()
+		log.Error("committed batch data does not match the db, stop finalizing and check the reason", "index", batch.Index, "hash", batch.Hash)
+		return fmt.Errorf("%w, index: %v, hash: %v", errDAMismatch, batch.Index, batch.Hash)
+	default:
+		return fmt.Errorf("committed batch data not verified yet, index: %v, hash: %v, status: %v", batch.Index, batch.Hash, status)
+	}
+}
+
 func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
+	if cfm.SenderStatus != sender.SenderStatusNone {
+		log.Warn("Sender status changed", "sender type", cfm.SenderType, "status", cfm.SenderStatus)
+		return
+	}
+
 	switch cfm.SenderType {
 	case types.SenderTypeCommitBatch:
 		var status types.RollupStatus
@@ -664,13 +936,19 @@ func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
 		} else {
 			status = types.RollupCommitFailed
 			r.metrics.rollupL2BatchesCommittedConfirmedFailedTotal.Inc()
-			log.Warn("CommitBatchTxType transaction confirmed but failed in layer1", "confirmation", cfm)
+			log.Error("CommitBatchTxType transaction confirmed but failed in layer1", "context ID", cfm.ContextID, "tx hash", cfm.TxHash.String(), "tx status", cfm.TxStatus, "revert reason", cfm.RevertReason)
 		}
 
 		err := r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, cfm.ContextID, cfm.TxHash.String(), status)
 		if err != nil {
 			log.Warn("UpdateCommitTxHashAndRollupStatus failed", "confirmation", cfm, "err", err)
+			break
 		}
+		if !cfm.IsSuccessful {
+			r.recoverFailedCommit(cfm.ContextID)
+			break
+		}
+		r.publishBatchEvents(cfm.ContextID, cfm.TxHash.String(), events.BatchCommitted)
 	case types.SenderTypeFinalizeBatch:
 		var status types.RollupStatus
 		if cfm.IsSuccessful {
@@ -685,6 +963,10 @@ func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
 		err := r.batchOrm.UpdateFinalizeTxHashAndRollupStatus(r.ctx, cfm.ContextID, cfm.TxHash.String(), status)
 		if err != nil {
 			log.Warn("UpdateFinalizeTxHashAndRollupStatus failed", "confirmation", cfm, "err", err)
+			break
+		}
+		if cfm.IsSuccessful {
+			r.publishBatchEvents(cfm.ContextID, cfm.TxHash.String(), events.BatchFinalized, events.WithdrawRootUpdated)
 		}
 	case types.SenderTypeL2GasOracle:
 		batchHash := cfm.ContextID
@@ -709,6 +991,100 @@ func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
 	log.Info("Transaction confirmed in layer1", "confirmation", cfm)
 }
 
+// publishBatchEvents publishes the events of the batch batchHash confirmed by the L1 transaction txHash, the batch is
+// only read when an event bus is set.
+func (r *Layer2Relayer) publishBatchEvents(batchHash, txHash string, eventTypes ...events.Type) {
+	if r.events == nil {
+		return
+	}
+	batches, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{"hash": batchHash}, nil, 1)
+	if err != nil || len(batches) == 0 {
+		log.Warn("failed to get the batch of the lifecycle events", "hash", batchHash, "err", err)
+		return
+	}
+	for _, eventType := range eventTypes {
+		event := events.Event{Type: eventType, Index: batches[0].Index, Hash: batchHash, TxHash: txHash}
+		if eventType == events.WithdrawRootUpdated {
+			event.WithdrawRoot = batches[0].WithdrawRoot
+		}
+		r.events.Publish(event)
+	}
+}
+
+// recoverFailedCommit handles the failed commit of the batch batchHash. The batch is RollupCommitFailed, which
+// ProcessPendingBatches commits again. Committing it again can only succeed if it was proposed on top of the current
+// parent batch, a batch proposed on top of a stale parent, e.g. one proposed again after a revert, is deleted along
+// with the following batches and proposed again when ReproposeStaleBatches is enabled.
+func (r *Layer2Relayer) recoverFailedCommit(batchHash string) {
+	batches, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{"hash": batchHash}, nil, 1)
+	if err != nil {
+		log.Error("failed to get batch of failed commit", "hash", batchHash, "err", err)
+		return
+	}
+	if len(batches) == 0 || batches[0].Index == 0 {
+		return
+	}
+
+	batch := batches[0]
+	parentBatch, err := r.batchOrm.GetBatchByIndex(r.ctx, batch.Index-1)
+	if err != nil {
+		log.Error("failed to get parent batch of failed commit", "index", batch.Index-1, "err", err)
+		return
+	}
+	if parentBatch.Hash == batch.ParentBatchHash {
+		log.Warn("batch of failed commit rolled back to be committed again", "index", batch.Index, "hash", batch.Hash)
+		return
+	}
+
+	log.Error("batch of failed commit was proposed on top of a stale parent batch",
+		"index", batch.Index, "hash", batch.Hash, "parent hash", batch.ParentBatchHash, "current parent hash", parentBatch.Hash)
+	if !r.cfg.ReproposeStaleBatches {
+		return
+	}
+	if err := r.reproposeBatches(batch); err != nil {
+		log.Error("failed to propose batches again", "start index", batch.Index, "err", err)
+	}
+}
+
+// reproposeBatches deletes firstBatch and the following batches and releases their chunks, so that the batch proposer
+// proposes them again on top of the current parent batch. It waits for the commits still in flight to fail first.
+func (r *Layer2Relayer) reproposeBatches(firstBatch *orm.Batch) error {
+	inFlight, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{
+		"index >= ?":             firstBatch.Index,
+		"rollup_status NOT IN ?": []int{int(types.RollupPending), int(types.RollupCommitFailed)},
+	}, nil, 1)
+	if err != nil {
+		return err
+	}
+	if len(inFlight) > 0 {
+		log.Warn("following batch commit in flight, postponing proposing batches again", "start index", firstBatch.Index, "in flight index", inFlight[0].Index, "rollup status", types.RollupStatus(inFlight[0].RollupStatus))
+		return nil
+	}
+
+	latestBatch, err := r.batchOrm.GetLatestBatch(r.ctx)
+	if err != nil {
+		return err
+	}
+	if latestBatch == nil {
+		return nil
+	}
+
+	err = r.db.Transaction(func(dbTX *gorm.DB) error {
+		if dbErr := r.batchOrm.DeleteBatchesGEIndex(r.ctx, firstBatch.Index, dbTX); dbErr != nil {
+			return dbErr
+		}
+		return r.chunkOrm.UpdateBatchHashInRange(r.ctx, firstBatch.StartChunkIndex, latestBatch.EndChunkIndex, "", dbTX)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.metrics.rollupL2BatchesReproposedTotal.Add(float64(latestBatch.Index - firstBatch.Index + 1))
+	log.Warn("deleted batches to be proposed again", "start index", firstBatch.Index, "end index", latestBatch.Index,
+		"start chunk index", firstBatch.StartChunkIndex, "end chunk index", latestBatch.EndChunkIndex)
+	return nil
+}
+
 func (r *Layer2Relayer) handleL2GasOracleConfirmLoop(ctx context.Context) {
 	for {
 		select {
@@ -12,6 +12,10 @@ type l2RelayerMetrics struct {
 	rollupL2RelayerProcessPendingBatchSuccessTotal              prometheus.Counter
 	rollupL2RelayerGasPriceOraclerRunTotal                      prometheus.Counter
 	rollupL2RelayerLastGasPrice                                 prometheus.Gauge
+	rollupL2RelayerGasPriceDeviation                            prometheus.Gauge
+	rollupL2RelayerGasPriceOracleUpdateTotal                    *prometheus.CounterVec
+	rollupL2RelayerGasPriceOracleSuppressedTotal                *prometheus.CounterVec
+	rollupL2RelayerGasPriceOracleAnomalyTotal                   *prometheus.CounterVec
 	rollupL2RelayerProcessCommittedBatchesTotal                 prometheus.Counter
 	rollupL2RelayerProcessCommittedBatchesFinalizedTotal        prometheus.Counter
 	rollupL2RelayerProcessCommittedBatchesFinalizedSuccessTotal prometheus.Counter
@@ -23,6 +27,12 @@ type l2RelayerMetrics struct {
 	rollupL2UpdateGasOracleConfirmedFailedTotal                 prometheus.Counter
 	rollupL2ChainMonitorLatestFailedCall                        prometheus.Counter
 	rollupL2ChainMonitorLatestFailedBatchStatus                 prometheus.Counter
+	rollupL2BatchesReproposedTotal                              prometheus.Counter
+	rollupL2RelayerCommittedBatchHashMismatchTotal              prometheus.Counter
+	rollupL2RelayerDAMismatchFinalizeHeldTotal                  prometheus.Counter
+	rollupL2RelayerL1PausedSkippedTotal                         prometheus.Counter
+	rollupL2RelayerCommitPacedTotal                             prometheus.Counter
+	rollupL2RelayerCommitCatchingUp                             prometheus.Gauge
 }
 
 var (
@@ -49,6 +59,22 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_gas_price_latest_gas_price",
 				Help: "The latest gas price of rollup relayer l2",
 			}),
+			rollupL2RelayerGasPriceDeviation: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer2_gas_price_deviation",
+				Help: "The relative deviation of the l2 gas price from the last on-chain value of the gas price oracle",
+			}),
+			rollupL2RelayerGasPriceOracleUpdateTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_gas_price_oracle_update_total",
+				Help: "The total number of layer2 gas price oracle updates by reason",
+			}, []string{"reason"}),
+			rollupL2RelayerGasPriceOracleSuppressedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_gas_price_oracle_suppressed_total",
+				Help: "The total number of suppressed layer2 gas price oracle updates by reason",
+			}, []string{"reason"}),
+			rollupL2RelayerGasPriceOracleAnomalyTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_gas_price_oracle_anomaly_total",
+				Help: "The total number of layer2 gas price oracle updates rejected as out of the sanity bounds by reason",
+			}, []string{"reason"}),
 			rollupL2RelayerProcessCommittedBatchesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 				Name: "rollup_layer2_process_committed_batches_total",
 				Help: "The total number of layer2 process committed batches run total",
@@ -93,6 +119,30 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_chain_monitor_latest_failed_batch_status",
 				Help: "The total number of failed batch status get from chain_monitor",
 			}),
+			rollupL2BatchesReproposedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_batches_reproposed_total",
+				Help: "The total number of layer2 batches deleted to be proposed again on top of an updated parent batch",
+			}),
+			rollupL2RelayerCommittedBatchHashMismatchTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_committed_batch_hash_mismatch_total",
+				Help: "The total number of batches not finalized as the l1 quorum reported a different committed hash",
+			}),
+			rollupL2RelayerDAMismatchFinalizeHeldTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_da_mismatch_finalize_held_total",
+				Help: "The total number of finalize attempts held as the committed batch data does not match the db",
+			}),
+			rollupL2RelayerL1PausedSkippedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_l1_paused_skipped_total",
+				Help: "The total number of commit and finalize runs skipped as the rollup contract is paused on l1",
+			}),
+			rollupL2RelayerCommitPacedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_commit_paced_total",
+				Help: "The total number of batch commits held by the commit pacing",
+			}),
+			rollupL2RelayerCommitCatchingUp: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer2_commit_catching_up",
+				Help: "Whether the commit pacing is bypassed to catch up with the pending batches, 1 if so",
+			}),
 		}
 	})
 	return l2RelayerMetric
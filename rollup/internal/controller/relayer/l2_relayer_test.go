@@ -11,6 +11,7 @@ import (
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -18,6 +19,7 @@ import (
 	"scroll-tech/common/database"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv0"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
 
@@ -73,7 +75,7 @@ func testL2RelayerProcessPendingBatches(t *testing.T) {
 	}
 
 	batchOrm := orm.NewBatch(db)
-	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 
 	relayer.ProcessPendingBatches()
@@ -104,7 +106,7 @@ func testL2RelayerProcessCommittedBatches(t *testing.T) {
 	}
 
 	batchOrm := orm.NewBatch(db)
-	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 
 	err = batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitted)
@@ -156,7 +158,7 @@ func testL2RelayerFinalizeTimeoutBatches(t *testing.T) {
 	}
 
 	batchOrm := orm.NewBatch(db)
-	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 
 	err = batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitted)
@@ -171,6 +173,70 @@ func testL2RelayerFinalizeTimeoutBatches(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func testL2RelayerReproposeStaleBatchesOnCommitFailure(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.ReproposeStaleBatches = true
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, &relayerCfg, false, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	err = l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+	assert.NoError(t, err)
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk1)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk2)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	dbBatch1, err := batchOrm.InsertBatch(context.Background(), &encoding.Batch{
+		Index:           0,
+		Chunks:          []*encoding.Chunk{chunk1},
+		StartChunkIndex: 0,
+		StartChunkHash:  chunkHash1,
+		EndChunkIndex:   0,
+		EndChunkHash:    chunkHash1,
+	}, codecv0.CodecV0Version)
+	assert.NoError(t, err)
+	assert.NoError(t, chunkOrm.UpdateBatchHashInRange(context.Background(), 0, 0, dbBatch1.Hash))
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch1.Hash, types.RollupCommitted))
+
+	// the second batch was proposed on top of a parent batch which has been proposed again since.
+	dbBatch2, err := batchOrm.InsertBatch(context.Background(), &encoding.Batch{
+		Index:                      1,
+		TotalL1MessagePoppedBefore: chunk1.NumL1Messages(0),
+		ParentBatchHash:            common.HexToHash("0x1"),
+		Chunks:                     []*encoding.Chunk{chunk2},
+		StartChunkIndex:            1,
+		StartChunkHash:             chunkHash2,
+		EndChunkIndex:              1,
+		EndChunkHash:               chunkHash2,
+	}, codecv0.CodecV0Version)
+	assert.NoError(t, err)
+	assert.NoError(t, chunkOrm.UpdateBatchHashInRange(context.Background(), 1, 1, dbBatch2.Hash))
+	assert.NoError(t, batchOrm.UpdateCommitTxHashAndRollupStatus(context.Background(), dbBatch2.Hash, "0x123456789abcdef", types.RollupCommitting))
+
+	relayer.handleConfirmation(&sender.Confirmation{
+		ContextID:    dbBatch2.Hash,
+		IsSuccessful: false,
+		TxHash:       common.HexToHash("0x123456789abcdef"),
+		SenderType:   types.SenderTypeCommitBatch,
+		TxStatus:     types.TxStatusConfirmedFailed,
+	})
+
+	latestBatch, err := batchOrm.GetLatestBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, dbBatch1.Hash, latestBatch.Hash)
+
+	dbChunks, err := chunkOrm.GetChunksInRange(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, dbChunks, 1)
+	assert.Empty(t, dbChunks[0].BatchHash)
+}
+
 func testL2RelayerCommitConfirm(t *testing.T) {
 	db := setupL2RelayerDB(t)
 	defer database.CloseDB(db)
@@ -198,7 +264,7 @@ func testL2RelayerCommitConfirm(t *testing.T) {
 			EndChunkHash:               chunkHash2,
 		}
 
-		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 		assert.NoError(t, err)
 		batchHashes[i] = dbBatch.Hash
 	}
@@ -257,7 +323,7 @@ func testL2RelayerFinalizeConfirm(t *testing.T) {
 			EndChunkHash:               chunkHash2,
 		}
 
-		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 		assert.NoError(t, err)
 		batchHashes[i] = dbBatch.Hash
 	}
@@ -305,7 +371,7 @@ func testL2RelayerGasOracleConfirm(t *testing.T) {
 	}
 
 	batchOrm := orm.NewBatch(db)
-	dbBatch1, err := batchOrm.InsertBatch(context.Background(), batch1)
+	dbBatch1, err := batchOrm.InsertBatch(context.Background(), batch1, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 
 	batch2 := &encoding.Batch{
@@ -319,7 +385,7 @@ func testL2RelayerGasOracleConfirm(t *testing.T) {
 		EndChunkHash:               chunkHash2,
 	}
 
-	dbBatch2, err := batchOrm.InsertBatch(context.Background(), batch2)
+	dbBatch2, err := batchOrm.InsertBatch(context.Background(), batch2, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 
 	// Create and set up the Layer2 Relayer.
@@ -480,7 +546,7 @@ func testGetBatchStatusByIndex(t *testing.T) {
 	}
 
 	batchOrm := orm.NewBatch(db)
-	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 
 	cfg.L2Config.RelayerConfig.ChainMonitor.Enabled = true
@@ -492,3 +558,14 @@ func testGetBatchStatusByIndex(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, true, status)
 }
+
+func TestCommittedBatchStateOverrides(t *testing.T) {
+	rollupContract := common.HexToAddress("0x1")
+	batchHash := common.HexToHash("0x1234")
+	overrides := committedBatchStateOverrides(rollupContract, common.BigToHash(big.NewInt(157)), 5, batchHash)
+	assert.Len(t, overrides, 1)
+
+	key := crypto.Keccak256Hash(common.LeftPadBytes([]byte{5}, 32), common.LeftPadBytes([]byte{157}, 32))
+	assert.Equal(t, map[common.Hash]common.Hash{key: batchHash}, overrides[rollupContract].StateDiff)
+	assert.Nil(t, overrides[rollupContract].Code)
+}
@@ -0,0 +1,233 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// replayMessageGasOverhead is added to the new gas limit of a replayed message to get the fallback gas limit of the
+// replayMessage transaction on layer 1.
+const replayMessageGasOverhead = 200000
+
+// ErrL1MessageRelayingHalted is returned by Replay while the latest L1 message audit found the stored L1 messages
+// diverging from the L1MessageQueue contract.
+var ErrL1MessageRelayingHalted = errors.New("l1 message relaying halted: stored l1 messages diverge from the message queue contract")
+
+// ErrL1MessengerPaused is returned by Replay while L1ScrollMessenger is paused, the replayMessage transaction would
+// revert.
+var ErrL1MessengerPaused = errors.New("l1 scroll messenger paused")
+
+// SkippedL1MessageReplayer replays skipped L1 messages through L1ScrollMessenger.replayMessage with a new gas limit,
+// the replayed message is appended to the L1 message queue again.
+type SkippedL1MessageReplayer struct {
+	ctx      context.Context
+	l1Client *ethclient.Client
+
+	skippedL1MessageOrm *orm.SkippedL1Message
+	l1MessageAuditOrm   *orm.L1MessageAudit
+
+	replaySender  *sender.Sender
+	refundAddress common.Address
+
+	l1MessengerAddress    common.Address
+	l1MessageQueueAddress common.Address
+	l1MessengerABI        *abi.ABI
+	l1MessageQueueABI     *abi.ABI
+	l2MessengerABI        *abi.ABI
+}
+
+// NewSkippedL1MessageReplayer creates a new SkippedL1MessageReplayer instance, l1Client must be connected to the
+// endpoint of the sender config.
+func NewSkippedL1MessageReplayer(ctx context.Context, l1Client *ethclient.Client, db *gorm.DB, cfg *config.RelayerConfig, l1MessengerAddress, l1MessageQueueAddress common.Address, reg prometheus.Registerer) (*SkippedL1MessageReplayer, error) {
+	if cfg.ReplayMessageSenderPrivateKey == nil {
+		return nil, errors.New("replay message sender private key is not set")
+	}
+	if l1MessengerAddress == (common.Address{}) {
+		return nil, errors.New("l1 scroll messenger address is not set")
+	}
+
+	replaySender, err := sender.NewSender(ctx, cfg.SenderConfig, cfg.ReplayMessageSenderPrivateKey, "l2_relayer", "replay_message_sender", types.SenderTypeReplayMessage, db, reg)
+	if err != nil {
+		addr := crypto.PubkeyToAddress(cfg.ReplayMessageSenderPrivateKey.PublicKey)
+		return nil, fmt.Errorf("new replay message sender failed for address %s, err: %w", addr.Hex(), err)
+	}
+
+	return &SkippedL1MessageReplayer{
+		ctx:      ctx,
+		l1Client: l1Client,
+
+		skippedL1MessageOrm: orm.NewSkippedL1Message(db),
+		l1MessageAuditOrm:   orm.NewL1MessageAudit(db),
+
+		replaySender:  replaySender,
+		refundAddress: crypto.PubkeyToAddress(cfg.ReplayMessageSenderPrivateKey.PublicKey),
+
+		l1MessengerAddress:    l1MessengerAddress,
+		l1MessageQueueAddress: l1MessageQueueAddress,
+		l1MessengerABI:        bridgeAbi.L1ScrollMessengerABI,
+		l1MessageQueueABI:     bridgeAbi.L1MessageQueueABI,
+		l2MessengerABI:        bridgeAbi.L2ScrollMessengerABI,
+	}, nil
+}
+
+// Stop stops the replay message sender.
+func (r *SkippedL1MessageReplayer) Stop() {
+	r.replaySender.Stop()
+}
+
+// Replay sends the replayMessage transaction of the skipped L1 message with queueIndex and newGasLimit, and waits
+// until it is confirmed or ctx is done. The message is marked SkippedL1MessageStatusReplaying while the transaction
+// is pending, then SkippedL1MessageStatusReplayed or SkippedL1MessageStatusReplayFailed. It returns
+// ErrL1MessageRelayingHalted while the stored L1 messages diverge from the L1MessageQueue contract, and
+// ErrL1MessengerPaused while L1ScrollMessenger is paused.
+func (r *SkippedL1MessageReplayer) Replay(ctx context.Context, queueIndex uint64, newGasLimit uint32) (common.Hash, error) {
+	audit, err := r.l1MessageAuditOrm.GetLatestL1MessageAudit(r.ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if audit != nil && audit.Diverged {
+		log.Error("l1 message relaying halted", "audit height", audit.L1BlockNumber, "divergent queue index", audit.DivergentQueueIndex, "reason", audit.Reason)
+		return common.Hash{}, ErrL1MessageRelayingHalted
+	}
+
+	paused, err := callPaused(r.ctx, r.l1Client, r.l1MessengerABI, r.l1MessengerAddress, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to read the pause state of the l1 scroll messenger, err: %w", err)
+	}
+	if paused {
+		return common.Hash{}, ErrL1MessengerPaused
+	}
+
+	message, err := r.skippedL1MessageOrm.GetSkippedL1MessageByQueueIndex(r.ctx, queueIndex)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if message == nil {
+		return common.Hash{}, fmt.Errorf("skipped l1 message not found, queue index: %v", queueIndex)
+	}
+	switch types.SkippedL1MessageStatus(message.Status) {
+	case types.SkippedL1MessageStatusSkipped, types.SkippedL1MessageStatusReplayFailed:
+	default:
+		return common.Hash{}, fmt.Errorf("skipped l1 message cannot be replayed, queue index: %v, status: %v", queueIndex, types.SkippedL1MessageStatus(message.Status))
+	}
+
+	data, err := r.packReplayMessage(message, newGasLimit)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	fee, err := r.estimateCrossDomainMessageFee(newGasLimit)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	contextID := fmt.Sprintf("replay-message-%d", queueIndex)
+	txHash, err := r.replaySender.SendTransaction(contextID, &r.l1MessengerAddress, fee, data, uint64(newGasLimit)+replayMessageGasOverhead, sender.WithMetadata(map[string]string{
+		TxLabelMessageNonce: strconv.FormatUint(queueIndex, 10),
+	}))
+	if err != nil && !errors.Is(err, sender.ErrQueued) {
+		return common.Hash{}, fmt.Errorf("failed to send replay message transaction, queue index: %v, err: %w", queueIndex, err)
+	}
+	replayTxHash := sentTxHash(txHash, err)
+	log.Info("replay message transaction sent", "queue index", queueIndex, "new gas limit", newGasLimit, "fee", fee, "tx hash", replayTxHash)
+
+	if err = r.skippedL1MessageOrm.UpdateReplayTxHashAndStatus(r.ctx, queueIndex, replayTxHash, uint64(newGasLimit), types.SkippedL1MessageStatusReplaying); err != nil {
+		return txHash, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return txHash, fmt.Errorf("stopped waiting for the replay message transaction, queue index: %v, err: %w", queueIndex, ctx.Err())
+		case cfm := <-r.replaySender.ConfirmChan():
+			if cfm.SenderStatus != sender.SenderStatusNone {
+				log.Warn("Sender status changed", "sender type", cfm.SenderType, "status", cfm.SenderStatus, "context ID", cfm.ContextID)
+				continue
+			}
+			if cfm.ContextID != contextID {
+				continue
+			}
+			status := types.SkippedL1MessageStatusReplayed
+			if !cfm.IsSuccessful {
+				status = types.SkippedL1MessageStatusReplayFailed
+				log.Warn("replay message transaction failed", "queue index", queueIndex, "tx hash", cfm.TxHash.Hex(), "tx status", cfm.TxStatus, "revert reason", cfm.RevertReason)
+			}
+			if err = r.skippedL1MessageOrm.UpdateReplayTxHashAndStatus(r.ctx, queueIndex, cfm.TxHash.Hex(), uint64(newGasLimit), status); err != nil {
+				return cfm.TxHash, err
+			}
+			if !cfm.IsSuccessful {
+				return cfm.TxHash, fmt.Errorf("replay message transaction failed, queue index: %v, tx hash: %v", queueIndex, cfm.TxHash.Hex())
+			}
+			return cfm.TxHash, nil
+		}
+	}
+}
+
+// packReplayMessage decodes the relayMessage calldata of the skipped L1 message transaction and packs the matching
+// replayMessage call of L1ScrollMessenger.
+func (r *SkippedL1MessageReplayer) packReplayMessage(message *orm.SkippedL1Message, newGasLimit uint32) ([]byte, error) {
+	calldata := common.FromHex(message.Calldata)
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("invalid calldata of skipped l1 message, queue index: %v", message.QueueIndex)
+	}
+	method, err := r.l2MessengerABI.MethodById(calldata[:4])
+	if err != nil || method.Name != "relayMessage" {
+		return nil, fmt.Errorf("skipped l1 message is not a relayMessage call, queue index: %v", message.QueueIndex)
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack relayMessage calldata, queue index: %v, err: %w", message.QueueIndex, err)
+	}
+
+	// relayMessage(address _from, address _to, uint256 _value, uint256 _nonce, bytes _message)
+	from, _ := args[0].(common.Address)
+	to, _ := args[1].(common.Address)
+	value, _ := args[2].(*big.Int)
+	msg, _ := args[4].([]byte)
+
+	data, err := r.l1MessengerABI.Pack("replayMessage", from, to, value, new(big.Int).SetUint64(message.QueueIndex), msg, newGasLimit, r.refundAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack replayMessage, queue index: %v, err: %w", message.QueueIndex, err)
+	}
+	return data, nil
+}
+
+// estimateCrossDomainMessageFee returns the fee of a message with gasLimit charged by L1MessageQueue.
+func (r *SkippedL1MessageReplayer) estimateCrossDomainMessageFee(gasLimit uint32) (*big.Int, error) {
+	data, err := r.l1MessageQueueABI.Pack("estimateCrossDomainMessageFee", new(big.Int).SetUint64(uint64(gasLimit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack estimateCrossDomainMessageFee, err: %w", err)
+	}
+	output, err := r.l1Client.CallContract(r.ctx, ethereum.CallMsg{To: &r.l1MessageQueueAddress, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call estimateCrossDomainMessageFee, err: %w", err)
+	}
+	result, err := r.l1MessageQueueABI.Unpack("estimateCrossDomainMessageFee", output)
+	if err != nil || len(result) == 0 {
+		return nil, fmt.Errorf("failed to unpack estimateCrossDomainMessageFee, err: %v", err)
+	}
+	fee, ok := result[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected estimateCrossDomainMessageFee result: %v", result[0])
+	}
+	return fee, nil
+}
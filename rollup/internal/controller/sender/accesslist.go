@@ -0,0 +1,82 @@
+package sender
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// accessListCacheKey identifies the calls sharing an access list: the same method of the same contract.
+type accessListCacheKey struct {
+	to       common.Address
+	selector [4]byte
+}
+
+// accessListCacheEntry is the outcome of eth_createAccessList for a contract method. A nil access list records
+// that the access list didn't reduce the gas limit, so the call is sent without one.
+type accessListCacheEntry struct {
+	accessList *types.AccessList
+	// gasDelta is the gas limit with the access list minus the gas limit without it.
+	gasDelta  int64
+	expiresAt time.Time
+}
+
+// accessListCache caches access lists by contract method for a TTL, saving an eth_createAccessList round trip per send.
+// Storage slots depending on the call arguments may differ between calls, a stale slot only costs its warm access.
+type accessListCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[accessListCacheKey]*accessListCacheEntry
+}
+
+func newAccessListCache(ttl time.Duration) *accessListCache {
+	return &accessListCache{
+		ttl:     ttl,
+		entries: make(map[accessListCacheKey]*accessListCacheEntry),
+	}
+}
+
+func newAccessListCacheKey(to *common.Address, data []byte) (accessListCacheKey, bool) {
+	if to == nil || len(data) < 4 {
+		return accessListCacheKey{}, false
+	}
+	key := accessListCacheKey{to: *to}
+	copy(key.selector[:], data[:4])
+	return key, true
+}
+
+func (c *accessListCache) get(key accessListCacheKey) (*accessListCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *accessListCache) set(key accessListCacheKey, accessList *types.AccessList, gasDelta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &accessListCacheEntry{accessList: accessList, gasDelta: gasDelta, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// isAccessListDisabled reports whether access lists are disabled for the target contract by configuration.
+func (s *Sender) isAccessListDisabled(to *common.Address) bool {
+	if s.config().AccessList == nil || to == nil {
+		return false
+	}
+	for _, disabled := range s.config().AccessList.DisabledContracts {
+		if disabled == *to {
+			return true
+		}
+	}
+	return false
+}
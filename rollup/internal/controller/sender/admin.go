@@ -0,0 +1,185 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// ErrSenderPaused is returned by SendTransaction while the sender is paused by an operator.
+var ErrSenderPaused = errors.New("sender paused by operator")
+
+// Name returns the name of the sender.
+func (s *Sender) Name() string {
+	return s.name
+}
+
+// Service returns the service the sender belongs to.
+func (s *Sender) Service() string {
+	return s.service
+}
+
+// SenderType returns the type of the sender.
+func (s *Sender) SenderType() types.SenderType {
+	return s.senderType
+}
+
+// Pause stops the sender from issuing new transactions until Resume is called,
+// in-flight transactions are still tracked and escalated.
+func (s *Sender) Pause() {
+	s.pausedManually.Store(true)
+	log.Warn("sender paused by operator", "service", s.service, "name", s.name)
+}
+
+// Resume lifts a pause set by Pause, it does not lift a low balance pause.
+func (s *Sender) Resume() {
+	s.pausedManually.Store(false)
+	log.Info("sender resumed by operator", "service", s.service, "name", s.name)
+}
+
+// GetPendingTransactions returns the pending and replaced transactions of the sender, ordered by nonce.
+func (s *Sender) GetPendingTransactions(limit int) ([]orm.PendingTransaction, error) {
+	return s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(s.ctx, s.senderType, limit)
+}
+
+// ForceEscalation makes the next pending transaction check resubmit the transaction of contextID with escalated fees,
+// regardless of EscalateBlocks.
+func (s *Sender) ForceEscalation(contextID string) {
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+	s.forcedEscalations[contextID] = struct{}{}
+	log.Info("forced escalation requested", "service", s.service, "name", s.name, "context ID", contextID)
+}
+
+// CancelTransaction cancels the transaction of contextID. A queued, deferred or dependent transaction is dropped, an in-flight one
+// is replaced by a self-transfer on the next pending transaction check and confirmed with TxStatusExpired.
+// It returns whether a queued, deferred or dependent transaction was dropped.
+func (s *Sender) CancelTransaction(contextID string) bool {
+	if s.removeQueuedTransaction(contextID) {
+		log.Info("queued transaction cancelled", "service", s.service, "name", s.name, "context ID", contextID)
+		return true
+	}
+
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+	s.cancellations[contextID] = struct{}{}
+	log.Info("cancellation requested", "service", s.service, "name", s.name, "context ID", contextID)
+	return false
+}
+
+// SetFeeEstimator replaces the fee estimator at runtime.
+func (s *Sender) SetFeeEstimator(cfg *config.FeeEstimatorConfig) error {
+	feeEstimator, err := newFeeEstimator(cfg, s.rpcClient, s.client)
+	if err != nil {
+		return fmt.Errorf("failed to create fee estimator, err: %w", err)
+	}
+
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+	s.feeEstimator = feeEstimator
+	log.Info("fee estimator replaced", "service", s.service, "name", s.name, "config", cfg)
+	return nil
+}
+
+// UpdateConfig replaces the tunable parameters of the sender at runtime: the escalation blocks and multiple and the gas
+// price caps, the other parameters of cfg are ignored as they require a restart.
+func (s *Sender) UpdateConfig(cfg *config.SenderConfig) error {
+	if cfg.EscalateMultipleNum <= cfg.EscalateMultipleDen {
+		return fmt.Errorf("invalid params, EscalateMultipleNum; %v, EscalateMultipleDen: %v", cfg.EscalateMultipleNum, cfg.EscalateMultipleDen)
+	}
+	current := s.config()
+	if cfg.MaxGasPrice > 0 && (current.MinGasPrice > cfg.MaxGasPrice || current.MinGasTipCap > cfg.MaxGasPrice) {
+		return fmt.Errorf("invalid params, MinGasPrice: %v, MinGasTipCap: %v, MaxGasPrice: %v", current.MinGasPrice, current.MinGasTipCap, cfg.MaxGasPrice)
+	}
+
+	s.escalationMu.Lock()
+	defer s.escalationMu.Unlock()
+	if cfg.EscalateMultipleNum != current.EscalateMultipleNum || cfg.EscalateMultipleDen != current.EscalateMultipleDen {
+		if err := s.escalation.setMultiple(cfg.EscalateMultipleNum, cfg.EscalateMultipleDen); err != nil {
+			return err
+		}
+		s.metrics.escalateMultipleNum.WithLabelValues(s.service, s.name).Set(float64(s.escalation.multipleNum))
+	}
+
+	next := *current
+	next.EscalateBlocks = cfg.EscalateBlocks
+	next.EscalateMultipleNum = cfg.EscalateMultipleNum
+	next.EscalateMultipleDen = cfg.EscalateMultipleDen
+	next.MaxGasPrice = cfg.MaxGasPrice
+	next.MaxBlobGasPrice = cfg.MaxBlobGasPrice
+	s.cfg.Store(&next)
+	log.Info("sender config updated", "service", s.service, "name", s.name, "escalate blocks", next.EscalateBlocks,
+		"escalate multiple num", next.EscalateMultipleNum, "escalate multiple den", next.EscalateMultipleDen,
+		"max gas price", next.MaxGasPrice, "max blob gas price", next.MaxBlobGasPrice)
+	return nil
+}
+
+func (s *Sender) getFeeEstimator() FeeEstimator {
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+	return s.feeEstimator
+}
+
+// consumeForcedEscalation reports whether an escalation was forced for contextID and clears the request.
+func (s *Sender) consumeForcedEscalation(contextID string) bool {
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+	if _, ok := s.forcedEscalations[contextID]; !ok {
+		return false
+	}
+	delete(s.forcedEscalations, contextID)
+	return true
+}
+
+// consumeCancellation reports whether a cancellation was requested for contextID and clears the request.
+func (s *Sender) consumeCancellation(contextID string) bool {
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+	if _, ok := s.cancellations[contextID]; !ok {
+		return false
+	}
+	delete(s.cancellations, contextID)
+	return true
+}
+
+// removeQueuedTransaction drops the queued, deferred or dependent transaction of contextID, if any.
+func (s *Sender) removeQueuedTransaction(contextID string) bool {
+	s.queueMu.Lock()
+	for i, queuedTx := range s.queue {
+		if queuedTx.ContextID == contextID {
+			s.queue = append(s.queue[:i:i], s.queue[i+1:]...)
+			s.metrics.queueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.queue)))
+			s.queueMu.Unlock()
+			return true
+		}
+	}
+	s.queueMu.Unlock()
+
+	s.deferMu.Lock()
+	for i, deferredTx := range s.deferred {
+		if deferredTx.ContextID == contextID {
+			s.deferred = append(s.deferred[:i:i], s.deferred[i+1:]...)
+			s.updateDeferredMetrics()
+			s.deferMu.Unlock()
+			return true
+		}
+	}
+	s.deferMu.Unlock()
+
+	s.dependentMu.Lock()
+	defer s.dependentMu.Unlock()
+	for i, dependentTx := range s.dependents {
+		if dependentTx.ContextID == contextID {
+			s.dependents = append(s.dependents[:i:i], s.dependents[i+1:]...)
+			s.metrics.dependentQueueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.dependents)))
+			return true
+		}
+	}
+	return false
+}
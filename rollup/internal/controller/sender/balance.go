@@ -0,0 +1,114 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/observability"
+)
+
+var (
+	// ErrSenderPausedLowBalance is returned by SendTransaction while the sender is paused because of low account balance.
+	ErrSenderPausedLowBalance = errors.New("sender paused: account balance below threshold")
+	// ErrValueExceedsBudget is returned by SendTransaction when the value of the transaction exceeds the account balance
+	// left above MinBalance once the value of the transactions in flight is accounted for.
+	ErrValueExceedsBudget = errors.New("transaction value exceeds the available balance")
+)
+
+// IsPaused reports whether the sender stopped issuing new transactions, because of low balance or an operator pause.
+func (s *Sender) IsPaused() bool {
+	return s.pausedLowBalance.Load() || s.pausedManually.Load()
+}
+
+// HealthCheck returns ErrSenderPausedLowBalance while the sender is paused because of low account balance, an operator
+// pause is not reported.
+func (s *Sender) HealthCheck(_ context.Context) error {
+	if s.pausedLowBalance.Load() {
+		return fmt.Errorf("%w, address: %s, min balance: %v", ErrSenderPausedLowBalance, s.auth.From.String(), s.config().MinBalance)
+	}
+	return nil
+}
+
+// RegisterHealthChecks adds the low balance pause of the senders to the readiness probe.
+func RegisterHealthChecks(senders []*Sender) {
+	for _, s := range senders {
+		observability.RegisterReadinessCheck(fmt.Sprintf("sender_%s_%s", s.service, s.name), s.HealthCheck)
+	}
+}
+
+// availableBalance returns the account balance minus the value transferred by the transactions in flight,
+// which is spent once they are included.
+func (s *Sender) availableBalance() (*big.Int, error) {
+	balance, err := s.client.BalanceAt(s.ctx, s.auth.From, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sender account balance, err: %w", err)
+	}
+	balanceFloat, _ := balance.Float64()
+	s.metrics.accountBalance.WithLabelValues(s.service, s.name).Set(balanceFloat)
+
+	inFlightValue, err := s.pendingTransactionOrm.GetInFlightValueBySenderType(s.ctx, s.senderType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-flight value, err: %w", err)
+	}
+	inFlightValueFloat, _ := inFlightValue.Float64()
+	s.metrics.inFlightValue.WithLabelValues(s.service, s.name).Set(inFlightValueFloat)
+
+	return balance.Sub(balance, inFlightValue), nil
+}
+
+// checkValueBudget returns ErrValueExceedsBudget if sending value would bring the available balance under MinBalance.
+func (s *Sender) checkValueBudget(value *big.Int) error {
+	available, err := s.availableBalance()
+	if err != nil {
+		return err
+	}
+	minBalance := new(big.Int)
+	if s.config().MinBalance != nil {
+		minBalance.Set(s.config().MinBalance)
+	}
+	if new(big.Int).Sub(available, value).Cmp(minBalance) < 0 {
+		s.metrics.valueBudgetExceededTotal.WithLabelValues(s.service, s.name).Inc()
+		log.Warn("transaction value exceeds the available balance", "service", s.service, "name", s.name, "value", value, "available", available, "min balance", minBalance)
+		return fmt.Errorf("%w, value: %v, available: %v, min balance: %v", ErrValueExceedsBudget, value, available, minBalance)
+	}
+	return nil
+}
+
+// checkBalance pauses the sender when the account balance, minus the value of the transactions in flight,
+// drops below MinBalance, and resumes it once it is back above the threshold.
+func (s *Sender) checkBalance() {
+	balance, err := s.availableBalance()
+	if err != nil {
+		log.Warn("failed to get sender available balance", "service", s.service, "name", s.name, "address", s.auth.From.String(), "err", err)
+		return
+	}
+
+	if s.config().MinBalance == nil {
+		return
+	}
+
+	lowBalance := balance.Cmp(s.config().MinBalance) < 0
+	if lowBalance == s.pausedLowBalance.Load() {
+		return
+	}
+	s.pausedLowBalance.Store(lowBalance)
+
+	status := SenderResumed
+	if lowBalance {
+		status = SenderPausedLowBalance
+		s.metrics.pausedLowBalance.WithLabelValues(s.service, s.name).Set(1)
+		log.Error("sender account balance below threshold, pausing new transactions", "service", s.service, "name", s.name, "address", s.auth.From.String(), "balance", balance, "min balance", s.config().MinBalance)
+	} else {
+		s.metrics.pausedLowBalance.WithLabelValues(s.service, s.name).Set(0)
+		log.Info("sender account balance recovered, resuming new transactions", "service", s.service, "name", s.name, "address", s.auth.From.String(), "balance", balance, "min balance", s.config().MinBalance)
+	}
+
+	s.confirmCh <- &Confirmation{
+		SenderType:   s.senderType,
+		SenderStatus: status,
+	}
+}
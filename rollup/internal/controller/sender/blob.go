@@ -0,0 +1,157 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/utils"
+)
+
+// blobTxPriceBump is the minimum fee bump in percent geth's blob pool requires to replace a blob transaction,
+// it applies to the gas tip cap, the gas fee cap and the blob gas fee cap alike.
+const blobTxPriceBump = 100
+
+// WithBlobSidecar attaches a blob sidecar to the transaction, which is then sent as a BlobTx. It requires the DynamicFeeTx type.
+// Since eth_estimateGas can not carry blobs, the fallback gas limit is used as is and the transaction is not simulated.
+func WithBlobSidecar(sidecar *gethTypes.BlobTxSidecar) SendOption {
+	return func(o *sendOptions) {
+		o.blobSidecar = sidecar
+	}
+}
+
+// estimateBlobGas returns the fee data of a new blob transaction carrying sidecar, the blob gas fee cap is twice
+// the current blob base fee, capped by MaxBlobGasPrice.
+func (s *Sender) estimateBlobGas(sidecar *gethTypes.BlobTxSidecar, fallbackGasLimit uint64, baseFee uint64) (*FeeData, error) {
+	if s.txType != DynamicFeeTxType {
+		return nil, fmt.Errorf("blob transactions require the %s tx type, got: %s", DynamicFeeTxType, s.txType)
+	}
+	if fallbackGasLimit == 0 {
+		return nil, errors.New("blob transactions require a fallback gas limit")
+	}
+
+	header, err := s.client.HeaderByNumber(s.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header by number, err: %w", err)
+	}
+	if header.ExcessBlobGas == nil {
+		return nil, errors.New("blob tx not supported: header.ExcessBlobGas is nil")
+	}
+
+	gasTipCap, err := s.getFeeEstimator().SuggestGasTipCap(s.ctx)
+	if err != nil {
+		log.Error("estimateBlobGas SuggestGasTipCap failure", "error", err)
+		return nil, err
+	}
+	gasTipCap = raiseToFloor(gasTipCap, s.config().MinGasTipCap)
+	gasFeeCap := new(big.Int).Add(gasTipCap, new(big.Int).Mul(new(big.Int).SetUint64(baseFee), big.NewInt(2)))
+	gasFeeCap = raiseToFloor(gasFeeCap, s.config().MinGasPrice)
+
+	blobGasFeeCap := new(big.Int).Mul(utils.CalcBlobFee(*header.ExcessBlobGas), big.NewInt(2))
+	if maxBlobGasPrice := new(big.Int).SetUint64(s.config().MaxBlobGasPrice); blobGasFeeCap.Cmp(maxBlobGasPrice) > 0 {
+		log.Warn("blob tx blob gas fee cap capped by max blob gas price", "required", blobGasFeeCap.Uint64(), "max blob gas price", maxBlobGasPrice.Uint64())
+		blobGasFeeCap = maxBlobGasPrice
+	}
+
+	return &FeeData{
+		gasTipCap:     gasTipCap,
+		gasFeeCap:     gasFeeCap,
+		blobGasFeeCap: blobGasFeeCap,
+		gasLimit:      fallbackGasLimit,
+		sidecar:       sidecar,
+	}, nil
+}
+
+// bumpAtLeast returns the larger of escalated and original bumped by percent.
+func bumpAtLeast(escalated, original *big.Int, percent int64) *big.Int {
+	minimum := new(big.Int).Mul(original, big.NewInt(100+percent))
+	minimum = minimum.Div(minimum, big.NewInt(100))
+	if escalated.Cmp(minimum) < 0 {
+		return minimum
+	}
+	return escalated
+}
+
+// escalateBlobFeeData returns the fee data of the blob transaction tx bumped by the escalate multiple, adjusted for the
+// current base fee and bumped by at least blobTxPriceBump so that the replacement is accepted by the blob pool.
+// The gas fee cap is capped by MaxGasPrice and the blob gas fee cap by MaxBlobGasPrice, a capped replacement is
+// likely to be rejected until the original transaction is included or dropped.
+func (s *Sender) escalateBlobFeeData(tx *gethTypes.Transaction, baseFee uint64) *FeeData {
+	multipleNum, multipleDen := s.EscalateMultiple()
+	escalateMultipleNum := new(big.Int).SetUint64(multipleNum)
+	escalateMultipleDen := new(big.Int).SetUint64(multipleDen)
+	maxGasPrice := new(big.Int).SetUint64(s.config().MaxGasPrice)
+	maxBlobGasPrice := new(big.Int).SetUint64(s.config().MaxBlobGasPrice)
+
+	if tx.BlobTxSidecar() == nil {
+		log.Warn("resubmitting blob tx without sidecar", "tx hash", tx.Hash().String(), "nonce", tx.Nonce())
+	}
+
+	originalGasTipCap := tx.GasTipCap()
+	originalGasFeeCap := tx.GasFeeCap()
+	originalBlobGasFeeCap := tx.BlobGasFeeCap()
+
+	gasTipCap := new(big.Int).Mul(originalGasTipCap, escalateMultipleNum)
+	gasTipCap = gasTipCap.Div(gasTipCap, escalateMultipleDen)
+	gasTipCap = bumpAtLeast(gasTipCap, originalGasTipCap, blobTxPriceBump)
+
+	gasFeeCap := new(big.Int).Mul(originalGasFeeCap, escalateMultipleNum)
+	gasFeeCap = gasFeeCap.Div(gasFeeCap, escalateMultipleDen)
+	gasFeeCap = bumpAtLeast(gasFeeCap, originalGasFeeCap, blobTxPriceBump)
+	gasTipCap = raiseToFloor(gasTipCap, s.config().MinGasTipCap)
+	gasFeeCap = raiseToFloor(gasFeeCap, s.config().MinGasPrice)
+
+	// adjust for rising basefee
+	adjBaseFee := new(big.Int).SetUint64(baseFee)
+	adjBaseFee = adjBaseFee.Mul(adjBaseFee, escalateMultipleNum)
+	adjBaseFee = adjBaseFee.Div(adjBaseFee, escalateMultipleDen)
+	currentGasFeeCap := new(big.Int).Add(gasTipCap, adjBaseFee)
+	if gasFeeCap.Cmp(currentGasFeeCap) < 0 {
+		gasFeeCap = currentGasFeeCap
+	}
+
+	blobGasFeeCap := new(big.Int).Mul(originalBlobGasFeeCap, escalateMultipleNum)
+	blobGasFeeCap = blobGasFeeCap.Div(blobGasFeeCap, escalateMultipleDen)
+	blobGasFeeCap = bumpAtLeast(blobGasFeeCap, originalBlobGasFeeCap, blobTxPriceBump)
+
+	// but don't exceed maxGasPrice and maxBlobGasPrice
+	if gasFeeCap.Cmp(maxGasPrice) > 0 {
+		log.Warn("blob tx gas fee cap capped by max gas price, replacement may be rejected", "required", gasFeeCap.Uint64(), "max gas price", maxGasPrice.Uint64())
+		gasFeeCap = maxGasPrice
+	}
+	if blobGasFeeCap.Cmp(maxBlobGasPrice) > 0 {
+		log.Warn("blob tx blob gas fee cap capped by max blob gas price, replacement may be rejected", "required", blobGasFeeCap.Uint64(), "max blob gas price", maxBlobGasPrice.Uint64())
+		blobGasFeeCap = maxBlobGasPrice
+	}
+
+	// gasTipCap <= gasFeeCap
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasTipCap = gasFeeCap
+	}
+
+	txInfo := map[string]interface{}{
+		"tx_hash":                   tx.Hash().String(),
+		"tx_type":                   "BlobTx",
+		"from":                      s.auth.From.String(),
+		"nonce":                     tx.Nonce(),
+		"original_gas_tip_cap":      originalGasTipCap.Uint64(),
+		"adjusted_gas_tip_cap":      gasTipCap.Uint64(),
+		"original_gas_fee_cap":      originalGasFeeCap.Uint64(),
+		"adjusted_gas_fee_cap":      gasFeeCap.Uint64(),
+		"original_blob_gas_fee_cap": originalBlobGasFeeCap.Uint64(),
+		"adjusted_blob_gas_fee_cap": blobGasFeeCap.Uint64(),
+	}
+	log.Info("Transaction gas adjustment details", "service", s.service, "name", s.name, "txInfo", txInfo)
+
+	return &FeeData{
+		gasTipCap:     gasTipCap,
+		gasFeeCap:     gasFeeCap,
+		blobGasFeeCap: blobGasFeeCap,
+		accessList:    tx.AccessList(),
+		gasLimit:      tx.Gas(),
+		sidecar:       tx.BlobTxSidecar(),
+	}
+}
@@ -0,0 +1,101 @@
+package sender
+
+import (
+	"bytes"
+	"fmt"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// blobBudgetTransactionsLimit bounds the in-flight transactions, replaced ones included, loaded to count the blobs in flight.
+const blobBudgetTransactionsLimit = 1000
+
+// blobCount returns the number of blobs carried by the transaction, 0 if it has no blob sidecar.
+func (t *QueuedTransaction) blobCount() uint64 {
+	sidecar := newSendOptions(t.Options).blobSidecar
+	if sidecar == nil {
+		return 0
+	}
+	return uint64(len(sidecar.Blobs))
+}
+
+// pendingBlobCount returns the number of blobs carried by the in-flight transactions. The transactions of a nonce
+// occupy the blob slots of one transaction in the blob pool, the largest one is counted.
+func (s *Sender) pendingBlobCount() (uint64, error) {
+	transactions, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(s.ctx, s.senderType, blobBudgetTransactionsLimit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending transactions, err: %w", err)
+	}
+
+	blobsByNonce := make(map[uint64]uint64)
+	for _, txn := range transactions {
+		if txn.Type != gethTypes.BlobTxType {
+			continue
+		}
+		tx := new(gethTypes.Transaction)
+		if err := tx.DecodeRLP(rlp.NewStream(bytes.NewReader(txn.RLPEncoding), 0)); err != nil {
+			return 0, fmt.Errorf("failed to decode RLP, hash: %s, err: %w", txn.Hash, err)
+		}
+		if blobs := uint64(len(tx.BlobHashes())); blobs > blobsByNonce[tx.Nonce()] {
+			blobsByNonce[tx.Nonce()] = blobs
+		}
+	}
+
+	var pendingBlobs uint64
+	for _, blobs := range blobsByNonce {
+		pendingBlobs += blobs
+	}
+	s.metrics.blobSlotsInUse.WithLabelValues(s.service, s.name).Set(float64(pendingBlobs))
+	return pendingBlobs, nil
+}
+
+// isBlobBudgetExceeded reports whether a new transaction carrying blobs has to wait, either because the blobs in flight
+// would exceed MaxPendingBlobs or because the blobs sent in the current block would exceed MaxBlobsPerBlock.
+// A transaction is never held when no blob is in flight, even if it carries more blobs than the budget. It requires sendMu.
+func (s *Sender) isBlobBudgetExceeded(blobs uint64) (bool, error) {
+	budget := s.config().BlobBudget
+	if budget == nil || blobs == 0 {
+		return false, nil
+	}
+	s.metrics.blobSlotsLimit.WithLabelValues(s.service, s.name).Set(float64(budget.MaxPendingBlobs))
+
+	if budget.MaxBlobsPerBlock > 0 && s.blobsInBlock > 0 {
+		blockNumber, err := s.client.BlockNumber(s.ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get block number, err: %w", err)
+		}
+		if blockNumber == s.blobBlockNumber && s.blobsInBlock+blobs > budget.MaxBlobsPerBlock {
+			return true, nil
+		}
+	}
+
+	pendingBlobs, err := s.pendingBlobCount()
+	if err != nil {
+		log.Error("failed to get pending blob count", "sender meta", s.getSenderMeta(), "err", err)
+		return false, err
+	}
+	return pendingBlobs > 0 && pendingBlobs+blobs > budget.MaxPendingBlobs, nil
+}
+
+// recordSentBlobs accounts the blobs of a new transaction sent at blockNumber for MaxBlobsPerBlock. It requires sendMu.
+func (s *Sender) recordSentBlobs(blockNumber, blobs uint64) {
+	if blockNumber != s.blobBlockNumber {
+		s.blobBlockNumber = blockNumber
+		s.blobsInBlock = 0
+	}
+	s.blobsInBlock += blobs
+}
+
+// checkBlobSlots refreshes the blob slots in use, as they are released by confirmations while no blob transaction is sent.
+func (s *Sender) checkBlobSlots() {
+	budget := s.config().BlobBudget
+	if budget == nil {
+		return
+	}
+	s.metrics.blobSlotsLimit.WithLabelValues(s.service, s.name).Set(float64(budget.MaxPendingBlobs))
+	if _, err := s.pendingBlobCount(); err != nil {
+		log.Error("failed to get pending blob count", "sender meta", s.getSenderMeta(), "err", err)
+	}
+}
@@ -0,0 +1,161 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+)
+
+// dependencyStatus is the state of the transaction a dependent transaction waits for.
+type dependencyStatus int
+
+const (
+	// dependencyWaiting means the dependency is not known by the node yet.
+	dependencyWaiting dependencyStatus = iota
+	// dependencyReady means the dependency is pending in the node or included.
+	dependencyReady
+	// dependencyFailed means the dependency expired without being included.
+	dependencyFailed
+)
+
+// dependentTransaction is a transaction held until the transaction of dependsOn is at least pending.
+type dependentTransaction struct {
+	*QueuedTransaction
+	dependsOn string
+}
+
+// WithDependsOn holds the transaction until the transaction of dependsOn, sent by the same sender, is at least pending
+// in the node. The dependent transaction is then broadcast with a later nonce so that it can never be included before
+// its dependency, e.g. commitBatch(N+1) after commitBatch(N). Dependent transactions are released in order, and are
+// reported as failed through the confirmation channel if their dependency expires.
+func WithDependsOn(contextID string) SendOption {
+	return func(o *sendOptions) {
+		o.dependsOn = contextID
+	}
+}
+
+// DependentLength returns the number of transactions waiting for their dependency.
+func (s *Sender) DependentLength() int {
+	s.dependentMu.Lock()
+	defer s.dependentMu.Unlock()
+	return len(s.dependents)
+}
+
+// isDependencyWaitRequired reports whether a new transaction depending on dependsOn has to be held,
+// either because earlier dependent transactions are still held or because its dependency is not ready.
+func (s *Sender) isDependencyWaitRequired(dependsOn string) (bool, error) {
+	if s.DependentLength() > 0 {
+		return true, nil
+	}
+	status, err := s.getDependencyStatus(dependsOn)
+	if err != nil {
+		return false, err
+	}
+	return status != dependencyReady, nil
+}
+
+// getDependencyStatus returns the status of the transaction of contextID sent by this sender.
+func (s *Sender) getDependencyStatus(contextID string) (dependencyStatus, error) {
+	txs, err := s.pendingTransactionOrm.GetTransactionsBySenderTypeAndContextID(s.ctx, s.senderType, contextID)
+	if err != nil {
+		return dependencyWaiting, fmt.Errorf("failed to get dependency transactions, context ID: %s, err: %w", contextID, err)
+	}
+
+	for _, txn := range txs {
+		switch txn.Status {
+		case types.TxStatusConfirmed:
+			return dependencyReady, nil
+		case types.TxStatusExpired:
+			return dependencyFailed, nil
+		}
+	}
+
+	// the broadcast transactions of the context, the latest replacement first.
+	for _, txn := range txs {
+		if txn.Status != types.TxStatusPending && txn.Status != types.TxStatusReplaced && txn.Status != types.TxStatusStuck {
+			continue
+		}
+		_, _, err := s.client.TransactionByHash(s.ctx, common.HexToHash(txn.Hash))
+		if err == nil {
+			return dependencyReady, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return dependencyWaiting, fmt.Errorf("failed to get dependency transaction, hash: %s, err: %w", txn.Hash, err)
+		}
+	}
+	return dependencyWaiting, nil
+}
+
+func (s *Sender) holdDependentTransaction(queuedTx *QueuedTransaction, dependsOn string) {
+	s.dependentMu.Lock()
+	defer s.dependentMu.Unlock()
+
+	s.dependents = append(s.dependents, &dependentTransaction{QueuedTransaction: queuedTx, dependsOn: dependsOn})
+	s.metrics.dependentQueueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.dependents)))
+	log.Info("transaction held until its dependency is pending", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID,
+		"depends on", dependsOn, "dependent length", len(s.dependents))
+}
+
+// releaseDependentTransactions sends the held transactions, in order, as long as their dependency is ready.
+// A transaction whose dependency expired, or which fails to be sent, is reported as failed through the confirmation channel.
+func (s *Sender) releaseDependentTransactions() {
+	if s.IsPaused() || s.DependentLength() == 0 {
+		return
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	for {
+		s.dependentMu.Lock()
+		if len(s.dependents) == 0 {
+			s.dependentMu.Unlock()
+			return
+		}
+		dependentTx := s.dependents[0]
+		s.dependentMu.Unlock()
+
+		status, err := s.getDependencyStatus(dependentTx.dependsOn)
+		if err != nil {
+			log.Error("failed to get dependency status", "service", s.service, "name", s.name, "context ID", dependentTx.ContextID, "depends on", dependentTx.dependsOn, "err", err)
+			return
+		}
+		if status == dependencyWaiting {
+			return
+		}
+
+		s.dependentMu.Lock()
+		s.dependents = s.dependents[1:]
+		s.metrics.dependentQueueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.dependents)))
+		s.dependentMu.Unlock()
+
+		if status == dependencyFailed {
+			log.Warn("dependency of held transaction expired, dropping it", "service", s.service, "name", s.name, "context ID", dependentTx.ContextID, "depends on", dependentTx.dependsOn)
+			s.confirmCh <- &Confirmation{
+				ContextID:    dependentTx.ContextID,
+				IsSuccessful: false,
+				TxStatus:     types.TxStatusConfirmedFailed,
+				SenderType:   s.senderType,
+				Metadata:     dependentTx.metadata(),
+			}
+			continue
+		}
+
+		log.Info("dependent transaction released", "service", s.service, "name", s.name, "context ID", dependentTx.ContextID, "depends on", dependentTx.dependsOn)
+		if _, err := s.sendDeferredTransaction(dependentTx.QueuedTransaction); err != nil {
+			log.Error("failed to send dependent transaction", "service", s.service, "name", s.name, "context ID", dependentTx.ContextID, "err", err)
+			s.confirmCh <- &Confirmation{
+				ContextID:    dependentTx.ContextID,
+				IsSuccessful: false,
+				TxStatus:     types.TxStatusConfirmedFailed,
+				SenderType:   s.senderType,
+				Metadata:     dependentTx.metadata(),
+			}
+		}
+	}
+}
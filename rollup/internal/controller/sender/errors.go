@@ -0,0 +1,110 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNonceTooLow is returned when the node rejects a transaction whose nonce has already been used.
+	ErrNonceTooLow = errors.New("nonce too low")
+	// ErrUnderpriced is returned when the node rejects a transaction or a replacement whose fees are too low.
+	ErrUnderpriced = errors.New("transaction underpriced")
+	// ErrInsufficientFunds is returned when the sender account cannot pay for the gas and value of a transaction.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrEstimationFailed is matched by every *EstimationError.
+	ErrEstimationFailed = errors.New("gas estimation failed")
+	// ErrQueued is returned with an empty hash when the transaction is held by the sender to be sent later,
+	// the outcome of the transaction is then delivered through the confirmation channel.
+	ErrQueued = errors.New("transaction queued")
+)
+
+// EstimationError is returned when the gas limit of a transaction without fallback gas limit cannot be estimated.
+type EstimationError struct {
+	// RevertReason is the decoded revert reason, empty if the estimation did not revert or it could not be decoded.
+	RevertReason string
+	// RevertData is the raw revert data returned by the node.
+	RevertData []byte
+	// Err is the original error returned by the estimation.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *EstimationError) Error() string {
+	if e.RevertReason != "" {
+		return fmt.Sprintf("gas estimation reverted: %s", e.RevertReason)
+	}
+	return fmt.Sprintf("gas estimation failed: %v", e.Err)
+}
+
+// Unwrap returns the original estimation error.
+func (e *EstimationError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrEstimationFailed.
+func (e *EstimationError) Is(target error) bool {
+	return target == ErrEstimationFailed
+}
+
+// newEstimationError creates the *EstimationError of a failed estimation.
+func newEstimationError(err error) *EstimationError {
+	revertData := revertDataFromError(err)
+	return &EstimationError{
+		RevertReason: decodeRevertReason(revertData),
+		RevertData:   revertData,
+		Err:          classifyNodeError(err),
+	}
+}
+
+// classifyNodeError wraps an error returned by the node with the matching ErrNonceTooLow, ErrUnderpriced or
+// ErrInsufficientFunds, the message of err is kept. Other errors are returned unchanged.
+func classifyNodeError(err error) error {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "nonce too low"):
+		return fmt.Errorf("%w: %w", ErrNonceTooLow, err)
+	case strings.Contains(msg, "underpriced"), strings.Contains(msg, "less than block base fee"):
+		return fmt.Errorf("%w: %w", ErrUnderpriced, err)
+	case strings.Contains(msg, "insufficient funds"):
+		return fmt.Errorf("%w: %w", ErrInsufficientFunds, err)
+	default:
+		return err
+	}
+}
+
+// isTerminalSendError reports whether sending the transaction again cannot succeed, i.e. it reverts or its reserved
+// nonce is unusable. Node and database failures, nonce races and fees too low are transient.
+func isTerminalSendError(err error) bool {
+	if errors.Is(err, ErrNonceNotReserved) || errors.Is(err, ErrReservedNonceUsed) {
+		return true
+	}
+	var simErr *SimulationError
+	if errors.As(err, &simErr) {
+		return isRevertError(simErr.Data, simErr.Err)
+	}
+	var estimationErr *EstimationError
+	if errors.As(err, &estimationErr) {
+		return isRevertError(estimationErr.RevertData, estimationErr.Err)
+	}
+	return false
+}
+
+// isRevertError reports whether the call failed because it reverted, with or without revert data.
+func isRevertError(revertData []byte, err error) bool {
+	return len(revertData) > 0 || (err != nil && strings.Contains(strings.ToLower(err.Error()), "execution reverted"))
+}
+
+// revertReasonFromError returns the decoded revert reason of a failed simulation or estimation, empty otherwise.
+func revertReasonFromError(err error) string {
+	var simErr *SimulationError
+	if errors.As(err, &simErr) {
+		return simErr.Reason
+	}
+	var estimationErr *EstimationError
+	if errors.As(err, &estimationErr) {
+		return estimationErr.RevertReason
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+package sender
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultAdaptiveEscalationWindow          = 20
+	defaultAdaptiveEscalationLowSuccessRate  = 0.5
+	defaultAdaptiveEscalationHighSuccessRate = 0.9
+)
+
+// ReplacementStat is the number of replacements sent at an escalate multiple numerator and how many of them were included
+// before being replaced again. Statistics are kept in memory since the start of the sender.
+type ReplacementStat struct {
+	MultipleNum uint64 `json:"multiple_num"`
+	Attempts    uint64 `json:"attempts"`
+	Successes   uint64 `json:"successes"`
+}
+
+// escalationTracker tracks the outcome of replacements and adapts the escalate multiple numerator within the configured bounds.
+type escalationTracker struct {
+	multipleDen     uint64
+	minMultipleNum  uint64
+	maxMultipleNum  uint64
+	window          uint64
+	lowSuccessRate  float64
+	highSuccessRate float64
+
+	multipleNum     uint64
+	windowAttempts  uint64
+	windowSuccesses uint64
+	stats           map[uint64]*ReplacementStat
+	// replacements maps a context ID to its latest replacement and the numerator it was escalated with.
+	replacements map[string]trackedReplacement
+}
+
+type trackedReplacement struct {
+	hash        common.Hash
+	multipleNum uint64
+}
+
+// newEscalationTracker creates the tracker of the escalate multiple, nil cfg keeps EscalateMultipleNum.
+func newEscalationTracker(cfg *config.AdaptiveEscalationConfig, multipleNum, multipleDen uint64) (*escalationTracker, error) {
+	t := &escalationTracker{
+		multipleDen:    multipleDen,
+		minMultipleNum: multipleNum,
+		maxMultipleNum: multipleNum,
+		multipleNum:    multipleNum,
+		stats:          make(map[uint64]*ReplacementStat),
+		replacements:   make(map[string]trackedReplacement),
+	}
+	if cfg == nil {
+		return t, nil
+	}
+
+	if cfg.MinMultipleNum <= multipleDen || cfg.MaxMultipleNum < cfg.MinMultipleNum {
+		return nil, fmt.Errorf("invalid adaptive escalation bounds, MinMultipleNum: %v, MaxMultipleNum: %v, EscalateMultipleDen: %v", cfg.MinMultipleNum, cfg.MaxMultipleNum, multipleDen)
+	}
+	t.minMultipleNum = cfg.MinMultipleNum
+	t.maxMultipleNum = cfg.MaxMultipleNum
+	t.window = cfg.Window
+	t.lowSuccessRate = cfg.LowSuccessRate
+	t.highSuccessRate = cfg.HighSuccessRate
+	if t.window == 0 {
+		t.window = defaultAdaptiveEscalationWindow
+	}
+	if t.lowSuccessRate == 0 {
+		t.lowSuccessRate = defaultAdaptiveEscalationLowSuccessRate
+	}
+	if t.highSuccessRate == 0 {
+		t.highSuccessRate = defaultAdaptiveEscalationHighSuccessRate
+	}
+	if t.lowSuccessRate > t.highSuccessRate || t.highSuccessRate > 1 {
+		return nil, fmt.Errorf("invalid adaptive escalation success rates, LowSuccessRate: %v, HighSuccessRate: %v", t.lowSuccessRate, t.highSuccessRate)
+	}
+
+	// start from the configured multiple, clamped within the bounds.
+	if t.multipleNum < t.minMultipleNum {
+		t.multipleNum = t.minMultipleNum
+	}
+	if t.multipleNum > t.maxMultipleNum {
+		t.multipleNum = t.maxMultipleNum
+	}
+	return t, nil
+}
+
+// setMultiple replaces the configured escalate multiple, the numerator is clamped within the adaptive bounds.
+func (t *escalationTracker) setMultiple(multipleNum, multipleDen uint64) error {
+	if t.window == 0 {
+		t.minMultipleNum, t.maxMultipleNum = multipleNum, multipleNum
+	} else if t.minMultipleNum <= multipleDen {
+		return fmt.Errorf("invalid adaptive escalation bounds, MinMultipleNum: %v, EscalateMultipleDen: %v", t.minMultipleNum, multipleDen)
+	}
+	t.multipleDen = multipleDen
+	t.multipleNum = multipleNum
+	if t.multipleNum < t.minMultipleNum {
+		t.multipleNum = t.minMultipleNum
+	}
+	if t.multipleNum > t.maxMultipleNum {
+		t.multipleNum = t.maxMultipleNum
+	}
+	return nil
+}
+
+// replacementSent records the replacement of a context escalated with multipleNum.
+func (t *escalationTracker) replacementSent(contextID string, hash common.Hash, multipleNum uint64) {
+	t.replacements[contextID] = trackedReplacement{hash: hash, multipleNum: multipleNum}
+}
+
+// replacementDone records the outcome of the replacement of a context if hash is its latest replacement: successful
+// when it was included, failed when it has to be replaced again. It returns whether the multiple numerator changed.
+func (t *escalationTracker) replacementDone(contextID string, hash common.Hash, successful bool) (uint64, bool, bool) {
+	replacement, ok := t.replacements[contextID]
+	if !ok {
+		return 0, false, false
+	}
+	delete(t.replacements, contextID)
+	if replacement.hash != hash {
+		return 0, false, false
+	}
+
+	stat, ok := t.stats[replacement.multipleNum]
+	if !ok {
+		stat = &ReplacementStat{MultipleNum: replacement.multipleNum}
+		t.stats[replacement.multipleNum] = stat
+	}
+	stat.Attempts++
+	if successful {
+		stat.Successes++
+	}
+
+	// only the outcomes at the current multiple decide of its adjustment.
+	if t.window == 0 || replacement.multipleNum != t.multipleNum {
+		return replacement.multipleNum, true, false
+	}
+	t.windowAttempts++
+	if successful {
+		t.windowSuccesses++
+	}
+	if t.windowAttempts < t.window {
+		return replacement.multipleNum, true, false
+	}
+
+	successRate := float64(t.windowSuccesses) / float64(t.windowAttempts)
+	t.windowAttempts, t.windowSuccesses = 0, 0
+	switch {
+	case successRate < t.lowSuccessRate && t.multipleNum < t.maxMultipleNum:
+		t.multipleNum++
+	case successRate > t.highSuccessRate && t.multipleNum > t.minMultipleNum:
+		t.multipleNum--
+	default:
+		return replacement.multipleNum, true, false
+	}
+	return replacement.multipleNum, true, true
+}
+
+// forget drops the replacement of a context without recording an outcome, e.g. when it is cancelled.
+func (t *escalationTracker) forget(contextID string) {
+	delete(t.replacements, contextID)
+}
+
+// replacementStats returns the replacement statistics ordered by multiple numerator.
+func (t *escalationTracker) replacementStats() []ReplacementStat {
+	stats := make([]ReplacementStat, 0, len(t.stats))
+	for _, stat := range t.stats {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].MultipleNum < stats[j].MultipleNum })
+	return stats
+}
+
+// EscalateMultiple returns the current numerator and denominator of the escalate multiple.
+func (s *Sender) EscalateMultiple() (uint64, uint64) {
+	s.escalationMu.Lock()
+	defer s.escalationMu.Unlock()
+	return s.escalation.multipleNum, s.escalation.multipleDen
+}
+
+// ReplacementStats returns the success rates of replacements by escalate multiple numerator.
+func (s *Sender) ReplacementStats() []ReplacementStat {
+	s.escalationMu.Lock()
+	defer s.escalationMu.Unlock()
+	return s.escalation.replacementStats()
+}
+
+// recordReplacementSent records that the transaction of a context was replaced by hash, escalated with multipleNum.
+func (s *Sender) recordReplacementSent(contextID string, hash common.Hash, multipleNum uint64) {
+	s.escalationMu.Lock()
+	defer s.escalationMu.Unlock()
+	s.escalation.replacementSent(contextID, hash, multipleNum)
+}
+
+// recordReplacementOutcome records whether the latest replacement of a context, if hash, was included or had to be replaced again.
+func (s *Sender) recordReplacementOutcome(contextID string, hash common.Hash, successful bool) {
+	s.escalationMu.Lock()
+	defer s.escalationMu.Unlock()
+
+	multipleNum, recorded, adjusted := s.escalation.replacementDone(contextID, hash, successful)
+	if !recorded {
+		return
+	}
+	outcome := "failed"
+	if successful {
+		outcome = "successful"
+	}
+	s.metrics.replacementOutcomeTotal.WithLabelValues(s.service, s.name, strconv.FormatUint(multipleNum, 10), outcome).Inc()
+	if adjusted {
+		s.metrics.escalateMultipleNum.WithLabelValues(s.service, s.name).Set(float64(s.escalation.multipleNum))
+		log.Info("escalate multiple adjusted", "service", s.service, "name", s.name, "previous num", multipleNum,
+			"num", s.escalation.multipleNum, "den", s.escalation.multipleDen)
+	}
+}
+
+// forgetReplacement drops the replacement of a context without recording an outcome.
+func (s *Sender) forgetReplacement(contextID string) {
+	s.escalationMu.Lock()
+	defer s.escalationMu.Unlock()
+	s.escalation.forget(contextID)
+}
@@ -10,53 +10,77 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 )
 
-func (s *Sender) estimateLegacyGas(to *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64) (*FeeData, error) {
+// raiseToFloor returns the larger of price and floor, a zero floor leaves price unchanged.
+func raiseToFloor(price *big.Int, floor uint64) *big.Int {
+	if floor == 0 || price.Cmp(new(big.Int).SetUint64(floor)) >= 0 {
+		return price
+	}
+	return new(big.Int).SetUint64(floor)
+}
+
+// padGasLimit returns the estimated gas limit multiplied by the gas limit multiple of the sender type to avoid out of
+// gas errors, and the applied multiple.
+func (s *Sender) padGasLimit(gasLimit uint64) (uint64, uint64, uint64) {
+	num, den := s.config().GasLimitMultiple(s.senderType)
+	return gasLimit * num / den, num, den
+}
+
+func (s *Sender) estimateLegacyGas(to *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, overrides StateOverrides) (*FeeData, error) {
 	gasPrice, err := s.client.SuggestGasPrice(s.ctx)
 	if err != nil {
 		log.Error("estimateLegacyGas SuggestGasPrice failure", "error", err)
 		return nil, err
 	}
-	gasLimit, _, err := s.estimateGasLimit(to, data, gasPrice, nil, nil, value, false)
+	gasPrice = raiseToFloor(gasPrice, s.config().MinGasPrice)
+	var multipleNum, multipleDen uint64
+	gasLimit, _, err := s.estimateGasLimit(to, data, gasPrice, nil, nil, value, false, overrides)
 	if err != nil {
 		log.Error("estimateLegacyGas estimateGasLimit failure", "gas price", gasPrice, "from", s.auth.From.String(),
 			"nonce", s.auth.Nonce.Uint64(), "to address", to.String(), "fallback gas limit", fallbackGasLimit, "error", err)
 		if fallbackGasLimit == 0 {
-			return nil, err
+			return nil, newEstimationError(err)
 		}
 		gasLimit = fallbackGasLimit
 	} else {
-		gasLimit = gasLimit * 12 / 10 // 20% extra gas to avoid out of gas error
+		gasLimit, multipleNum, multipleDen = s.padGasLimit(gasLimit)
 	}
 	return &FeeData{
-		gasPrice: gasPrice,
-		gasLimit: gasLimit,
+		gasPrice:            gasPrice,
+		gasLimit:            gasLimit,
+		gasLimitMultipleNum: multipleNum,
+		gasLimitMultipleDen: multipleDen,
 	}, nil
 }
 
-func (s *Sender) estimateDynamicGas(to *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, baseFee uint64) (*FeeData, error) {
-	gasTipCap, err := s.client.SuggestGasTipCap(s.ctx)
+func (s *Sender) estimateDynamicGas(to *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, baseFee uint64, overrides StateOverrides) (*FeeData, error) {
+	gasTipCap, err := s.getFeeEstimator().SuggestGasTipCap(s.ctx)
 	if err != nil {
 		log.Error("estimateDynamicGas SuggestGasTipCap failure", "error", err)
 		return nil, err
 	}
 
+	gasTipCap = raiseToFloor(gasTipCap, s.config().MinGasTipCap)
 	gasFeeCap := new(big.Int).Add(gasTipCap, new(big.Int).Mul(new(big.Int).SetUint64(baseFee), big.NewInt(2)))
-	gasLimit, accessList, err := s.estimateGasLimit(to, data, nil, gasTipCap, gasFeeCap, value, true)
+	gasFeeCap = raiseToFloor(gasFeeCap, s.config().MinGasPrice)
+	var multipleNum, multipleDen uint64
+	gasLimit, accessList, err := s.estimateGasLimit(to, data, nil, gasTipCap, gasFeeCap, value, true, overrides)
 	if err != nil {
 		log.Error("estimateDynamicGas estimateGasLimit failure",
 			"from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "to address", to.String(),
 			"fallback gas limit", fallbackGasLimit, "error", err)
 		if fallbackGasLimit == 0 {
-			return nil, err
+			return nil, newEstimationError(err)
 		}
 		gasLimit = fallbackGasLimit
 	} else {
-		gasLimit = gasLimit * 12 / 10 // 20% extra gas to avoid out of gas error
+		gasLimit, multipleNum, multipleDen = s.padGasLimit(gasLimit)
 	}
 	feeData := &FeeData{
-		gasLimit:  gasLimit,
-		gasTipCap: gasTipCap,
-		gasFeeCap: gasFeeCap,
+		gasLimit:            gasLimit,
+		gasTipCap:           gasTipCap,
+		gasFeeCap:           gasFeeCap,
+		gasLimitMultipleNum: multipleNum,
+		gasLimitMultipleDen: multipleDen,
 	}
 	if accessList != nil {
 		feeData.accessList = *accessList
@@ -64,7 +88,7 @@ func (s *Sender) estimateDynamicGas(to *common.Address, value *big.Int, data []b
 	return feeData, nil
 }
 
-func (s *Sender) estimateGasLimit(to *common.Address, data []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int, useAccessList bool) (uint64, *types.AccessList, error) {
+func (s *Sender) estimateGasLimit(to *common.Address, data []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int, useAccessList bool, overrides StateOverrides) (uint64, *types.AccessList, error) {
 	msg := ethereum.CallMsg{
 		From:      s.auth.From,
 		To:        to,
@@ -74,16 +98,36 @@ func (s *Sender) estimateGasLimit(to *common.Address, data []byte, gasPrice, gas
 		Value:     value,
 		Data:      data,
 	}
+	if overrides != nil {
+		gasLimit, err := s.estimateGasWithOverrides(msg, overrides)
+		if err != nil {
+			log.Error("estimateGasLimit EstimateGas failure with state overrides", "error", err)
+			return 0, nil, err
+		}
+		return gasLimit, nil, nil
+	}
+
 	gasLimitWithoutAccessList, err := s.client.EstimateGas(s.ctx, msg)
 	if err != nil {
 		log.Error("estimateGasLimit EstimateGas failure without access list", "error", err)
 		return 0, nil, err
 	}
 
-	if !useAccessList {
+	if !useAccessList || s.isAccessListDisabled(to) {
 		return gasLimitWithoutAccessList, nil, nil
 	}
 
+	cacheKey, cacheable := newAccessListCacheKey(to, data)
+	cacheable = cacheable && s.accessListCache != nil
+	if cacheable {
+		if entry, ok := s.accessListCache.get(cacheKey); ok {
+			if entry.accessList == nil {
+				return gasLimitWithoutAccessList, nil, nil
+			}
+			return uint64(int64(gasLimitWithoutAccessList) + entry.gasDelta), entry.accessList, nil
+		}
+	}
+
 	// Explicitly set a gas limit to prevent the "insufficient funds for gas * price + value" error.
 	// Because if msg.Gas remains unset, CreateAccessList defaults to using RPCGasCap(), which can be excessively high.
 	msg.Gas = gasLimitWithoutAccessList * 3
@@ -104,8 +148,14 @@ func (s *Sender) estimateGasLimit(to *common.Address, data []byte, gasPrice, gas
 	log.Info("gas", "senderName", s.name, "senderService", s.service, "gasLimitWithAccessList", gasLimitWithAccessList, "gasLimitWithoutAccessList", gasLimitWithoutAccessList, "accessList", accessList)
 
 	if gasLimitWithAccessList < gasLimitWithoutAccessList {
+		if cacheable {
+			s.accessListCache.set(cacheKey, accessList, int64(gasLimitWithAccessList)-int64(gasLimitWithoutAccessList))
+		}
 		return gasLimitWithAccessList, accessList, nil
 	}
+	if cacheable {
+		s.accessListCache.set(cacheKey, nil, 0)
+	}
 	return gasLimitWithoutAccessList, nil, nil
 }
 
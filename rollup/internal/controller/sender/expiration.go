@@ -0,0 +1,33 @@
+package sender
+
+import (
+	"math/big"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// cancelTxGasLimit is the gas limit of the self-transfer used to cancel an expired transaction.
+const cancelTxGasLimit = 21000
+
+// isCancellationTx reports whether tx is a self-transfer replacement sent to cancel an expired transaction,
+// the sender never sends empty self-transfers otherwise.
+func (s *Sender) isCancellationTx(tx *gethTypes.Transaction) bool {
+	return tx.To() != nil && *tx.To() == s.auth.From && len(tx.Data()) == 0 && tx.Value().Sign() == 0
+}
+
+// cancelTransaction replaces tx with an empty self-transfer using the same nonce and escalated fees.
+func (s *Sender) cancelTransaction(tx *gethTypes.Transaction, baseFee uint64) (*gethTypes.Transaction, error) {
+	feeData := s.escalateFeeData(tx, baseFee)
+	feeData.gasLimit = cancelTxGasLimit
+	feeData.accessList = nil
+
+	nonce := tx.Nonce()
+	s.metrics.cancelTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+	cancelTx, err := s.createAndSendTx(feeData, &s.auth.From, big.NewInt(0), nil, &nonce)
+	if err != nil {
+		log.Error("failed to create and send tx (cancel case)", "from", s.auth.From.String(), "nonce", nonce, "err", err)
+		return nil, err
+	}
+	return cancelTx, nil
+}
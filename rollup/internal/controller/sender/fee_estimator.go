@@ -0,0 +1,133 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// NodeFeeEstimatorType asks the node for a tip with eth_maxPriorityFeePerGas, the default.
+	NodeFeeEstimatorType = "node"
+	// FeeHistoryFeeEstimatorType derives the tip from eth_feeHistory reward percentiles smoothed by an EWMA.
+	FeeHistoryFeeEstimatorType = "fee_history"
+
+	defaultFeeHistoryRewardPercentile = 50
+	defaultFeeHistoryLookbackBlocks   = 20
+	defaultFeeHistoryEWMAAlpha        = 0.3
+)
+
+// FeeEstimator suggests the priority fee (gas tip cap) of DynamicFeeTx transactions.
+type FeeEstimator interface {
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// newFeeEstimator creates the fee estimator selected by cfg, nil cfg selects the node estimator.
+func newFeeEstimator(cfg *config.FeeEstimatorConfig, rpcClient *rpc.Client, client *ethclient.Client) (FeeEstimator, error) {
+	if cfg == nil || cfg.Type == "" || cfg.Type == NodeFeeEstimatorType {
+		return client, nil
+	}
+
+	if cfg.Type != FeeHistoryFeeEstimatorType {
+		return nil, fmt.Errorf("unknown fee estimator type: %v", cfg.Type)
+	}
+
+	estimator := &feeHistoryEstimator{
+		rpcClient:        rpcClient,
+		rewardPercentile: cfg.RewardPercentile,
+		lookbackBlocks:   cfg.LookbackBlocks,
+		ewmaAlpha:        cfg.EWMAAlpha,
+	}
+	if estimator.rewardPercentile == 0 {
+		estimator.rewardPercentile = defaultFeeHistoryRewardPercentile
+	}
+	if estimator.lookbackBlocks == 0 {
+		estimator.lookbackBlocks = defaultFeeHistoryLookbackBlocks
+	}
+	if estimator.ewmaAlpha == 0 {
+		estimator.ewmaAlpha = defaultFeeHistoryEWMAAlpha
+	}
+	if estimator.rewardPercentile < 0 || estimator.rewardPercentile > 100 {
+		return nil, fmt.Errorf("invalid fee estimator reward percentile: %v, must be within [0, 100]", estimator.rewardPercentile)
+	}
+	if estimator.ewmaAlpha < 0 || estimator.ewmaAlpha > 1 {
+		return nil, fmt.Errorf("invalid fee estimator ewma alpha: %v, must be within (0, 1]", estimator.ewmaAlpha)
+	}
+	return estimator, nil
+}
+
+// feeHistoryResult is the response of eth_feeHistory.
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// averageReward returns the average reward at the first requested percentile and the number of blocks averaged.
+func (r *feeHistoryResult) averageReward() (*big.Int, int64) {
+	sum := new(big.Int)
+	var count int64
+	for i, rewards := range r.Reward {
+		// skip empty blocks, their reward is zero regardless of the fee market.
+		if len(rewards) == 0 || rewards[0] == nil || (i < len(r.GasUsedRatio) && r.GasUsedRatio[i] == 0) {
+			continue
+		}
+		sum.Add(sum, rewards[0].ToInt())
+		count++
+	}
+	if count == 0 {
+		return sum, 0
+	}
+	return sum.Div(sum, big.NewInt(count)), count
+}
+
+// feeHistoryEstimator averages the tip paid at a reward percentile over the last lookbackBlocks blocks,
+// then smooths the average with an exponentially weighted moving average so that a transient spike
+// only moves the suggestion by ewmaAlpha of its size.
+type feeHistoryEstimator struct {
+	rpcClient        *rpc.Client
+	rewardPercentile float64
+	lookbackBlocks   uint64
+	ewmaAlpha        float64
+
+	mu   sync.Mutex
+	ewma *big.Float
+}
+
+// SuggestGasTipCap implements the FeeEstimator interface.
+func (e *feeHistoryEstimator) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var result feeHistoryResult
+	if err := e.rpcClient.CallContext(ctx, &result, "eth_feeHistory", hexutil.Uint64(e.lookbackBlocks), "latest", []float64{e.rewardPercentile}); err != nil {
+		return nil, fmt.Errorf("failed to get fee history, err: %w", err)
+	}
+
+	average, count := result.averageReward()
+	if count == 0 {
+		return nil, fmt.Errorf("no reward in fee history of the last %d blocks", e.lookbackBlocks)
+	}
+	sample := new(big.Float).SetInt(average)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.ewma == nil {
+		e.ewma = sample
+	} else {
+		// ewma = alpha * sample + (1 - alpha) * ewma
+		weighted := new(big.Float).Mul(sample, big.NewFloat(e.ewmaAlpha))
+		e.ewma = weighted.Add(weighted, new(big.Float).Mul(e.ewma, big.NewFloat(1-e.ewmaAlpha)))
+	}
+
+	gasTipCap, _ := e.ewma.Int(nil)
+	log.Debug("fee history tip estimation", "sample", sample.String(), "ewma", gasTipCap.String(), "blocks", count)
+	return gasTipCap, nil
+}
@@ -0,0 +1,113 @@
+package sender
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+const (
+	// txPriceBump is the minimum fee bump in percent geth's transaction pool requires to replace a transaction.
+	txPriceBump = 10
+
+	// maxTargetInclusionBaseFeeBlocks bounds the number of blocks of base fee increase the fee cap covers.
+	maxTargetInclusionBaseFeeBlocks = 32
+)
+
+// WithTargetInclusion asks for the transaction to be included within the given number of blocks. Instead of the
+// configured escalation multiple, the gas tip cap is derived from the eth_feeHistory reward percentile matching the
+// window and the gas fee cap covers the maximum base fee increase over the window. Both are derived again on each
+// escalation for the blocks left in the window. It only applies to DynamicFeeTx transactions.
+func WithTargetInclusion(blocks uint64) SendOption {
+	return func(o *sendOptions) {
+		o.targetInclusionBlocks = blocks
+	}
+}
+
+// targetInclusionPercentile returns the reward percentile to pay for an inclusion within the given number of blocks,
+// from the 90th percentile for the next block down to the 10th percentile for 9 blocks or more.
+func targetInclusionPercentile(blocks uint64) float64 {
+	if blocks >= 9 {
+		return 10
+	}
+	return float64(100 - 10*blocks)
+}
+
+// maxBaseFeeAfter returns the maximum base fee after the given number of full blocks, the base fee rising by at most 1/8 per block.
+func maxBaseFeeAfter(baseFee uint64, blocks uint64) *big.Int {
+	if blocks > maxTargetInclusionBaseFeeBlocks {
+		blocks = maxTargetInclusionBaseFeeBlocks
+	}
+	maxBaseFee := new(big.Int).SetUint64(baseFee)
+	for i := uint64(0); i < blocks; i++ {
+		maxBaseFee.Add(maxBaseFee, new(big.Int).Div(maxBaseFee, big.NewInt(8)))
+	}
+	return maxBaseFee
+}
+
+// deriveTargetInclusionFees derives the gas tip cap and gas fee cap of a transaction to be included within the given number of blocks.
+func (s *Sender) deriveTargetInclusionFees(blocks uint64, baseFee uint64) (*big.Int, *big.Int, error) {
+	lookbackBlocks := uint64(defaultFeeHistoryLookbackBlocks)
+	if s.config().FeeEstimator != nil && s.config().FeeEstimator.LookbackBlocks > 0 {
+		lookbackBlocks = s.config().FeeEstimator.LookbackBlocks
+	}
+
+	percentile := targetInclusionPercentile(blocks)
+	var result feeHistoryResult
+	if err := s.rpcClient.CallContext(s.ctx, &result, "eth_feeHistory", hexutil.Uint64(lookbackBlocks), "latest", []float64{percentile}); err != nil {
+		return nil, nil, fmt.Errorf("failed to get fee history, err: %w", err)
+	}
+
+	gasTipCap, count := result.averageReward()
+	if count == 0 {
+		return nil, nil, fmt.Errorf("no reward in fee history of the last %d blocks", lookbackBlocks)
+	}
+	gasFeeCap := new(big.Int).Add(gasTipCap, maxBaseFeeAfter(baseFee, blocks))
+	log.Debug("target inclusion fees", "blocks", blocks, "percentile", percentile, "gas tip cap", gasTipCap.String(), "gas fee cap", gasFeeCap.String())
+	return gasTipCap, gasFeeCap, nil
+}
+
+// applyTargetInclusionFees replaces the fees of feeData by the ones derived for the target inclusion window,
+// raised to MinGasPrice and MinGasTipCap and capped by MaxGasPrice.
+// The fees of original, the transaction being replaced if any, are bumped by at least txPriceBump so that the replacement is accepted.
+func (s *Sender) applyTargetInclusionFees(feeData *FeeData, original *gethTypes.Transaction, blocks uint64, baseFee uint64) error {
+	gasTipCap, gasFeeCap, err := s.deriveTargetInclusionFees(blocks, baseFee)
+	if err != nil {
+		return err
+	}
+	gasTipCap = raiseToFloor(gasTipCap, s.config().MinGasTipCap)
+	gasFeeCap = raiseToFloor(gasFeeCap, s.config().MinGasPrice)
+	if original != nil {
+		gasTipCap = bumpAtLeast(gasTipCap, original.GasTipCap(), txPriceBump)
+		gasFeeCap = bumpAtLeast(gasFeeCap, original.GasFeeCap(), txPriceBump)
+	}
+
+	maxGasPrice := new(big.Int).SetUint64(s.config().MaxGasPrice)
+	if gasFeeCap.Cmp(maxGasPrice) > 0 {
+		log.Warn("target inclusion gas fee cap capped by max gas price", "required", gasFeeCap.Uint64(), "max gas price", maxGasPrice.Uint64(), "blocks", blocks)
+		gasFeeCap = maxGasPrice
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasTipCap = gasFeeCap
+	}
+
+	feeData.gasTipCap = gasTipCap
+	feeData.gasFeeCap = gasFeeCap
+	return nil
+}
+
+// targetInclusionBlocksLeft returns the blocks left in the target inclusion window of a transaction first submitted at
+// firstSubmitBlockNumber, at least one once the window elapsed.
+func targetInclusionBlocksLeft(targetInclusionBlocks, firstSubmitBlockNumber, blockNumber uint64) uint64 {
+	elapsed := uint64(0)
+	if blockNumber > firstSubmitBlockNumber {
+		elapsed = blockNumber - firstSubmitBlockNumber
+	}
+	if elapsed >= targetInclusionBlocks {
+		return 1
+	}
+	return targetInclusionBlocks - elapsed
+}
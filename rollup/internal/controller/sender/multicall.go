@@ -0,0 +1,236 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// multicallContextIDPrefix prefixes the context ID of aggregated transactions, followed by the comma separated context IDs of the calls.
+// Keeping the call context IDs in the context ID lets the results be decoded from the transaction alone, across restarts.
+const multicallContextIDPrefix = "multicall:"
+
+const multicall3ABIJSON = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var multicall3ABI *abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABIJSON))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse multicall3 abi, err: %v", err))
+	}
+	multicall3ABI = &parsed
+}
+
+// multicall3Call is the Multicall3.Call3 struct.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result is the Multicall3.Result struct.
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// aggregatedCall is a call waiting to be aggregated into the next Multicall3 transaction.
+type aggregatedCall struct {
+	contextID string
+	call      multicall3Call
+}
+
+// CallResult is the result of a call aggregated into a Multicall3 transaction.
+type CallResult struct {
+	ContextID string
+	Target    common.Address
+	// Success is false if the call reverted, or if the aggregated transaction failed as a whole.
+	Success bool
+	// ReturnData is the return data of the call, or its revert data. It is nil when the transaction could not be traced.
+	ReturnData []byte
+	// RevertReason is the decoded revert reason of a failed call.
+	RevertReason string
+}
+
+// ErrAggregationDisabled is returned by AggregateCall when no Multicall3 contract is configured.
+var ErrAggregationDisabled = errors.New("call aggregation disabled: no multicall address configured")
+
+// AggregateCall queues a call to be sent along with the other calls of the same block in a single Multicall3 aggregate3 transaction.
+// A call with allowFailure set may revert without reverting the others. The confirmation of the aggregated transaction
+// carries the context ID prefixed by multicallContextIDPrefix and the result of every call in CallResults.
+func (s *Sender) AggregateCall(contextID string, target common.Address, data []byte, allowFailure bool) error {
+	if s.config().Multicall == nil || s.config().Multicall.Address == (common.Address{}) {
+		return ErrAggregationDisabled
+	}
+	if strings.Contains(contextID, ",") {
+		return fmt.Errorf("invalid context ID of aggregated call: %s, must not contain a comma", contextID)
+	}
+
+	s.aggregateMu.Lock()
+	defer s.aggregateMu.Unlock()
+	s.aggregated = append(s.aggregated, &aggregatedCall{
+		contextID: contextID,
+		call:      multicall3Call{Target: target, AllowFailure: allowFailure, CallData: data},
+	})
+	return nil
+}
+
+// AggregatedLength returns the number of calls waiting to be aggregated.
+func (s *Sender) AggregatedLength() int {
+	s.aggregateMu.Lock()
+	defer s.aggregateMu.Unlock()
+	return len(s.aggregated)
+}
+
+// flushAggregatedCalls sends the queued calls as one Multicall3 transaction, at most once per block unless MaxCalls is reached.
+// If the transaction fails to be sent, every call is reported as failed through the confirmation channel.
+func (s *Sender) flushAggregatedCalls() {
+	if s.config().Multicall == nil || s.IsPaused() || s.AggregatedLength() == 0 {
+		return
+	}
+
+	blockNumber, _, err := s.getBlockNumberAndBaseFee(s.ctx)
+	if err != nil {
+		log.Error("failed to get block number and base fee", "error", err)
+		return
+	}
+
+	s.aggregateMu.Lock()
+	maxCalls := len(s.aggregated)
+	full := s.config().Multicall.MaxCalls > 0 && maxCalls >= s.config().Multicall.MaxCalls
+	if full {
+		maxCalls = s.config().Multicall.MaxCalls
+	}
+	if blockNumber <= s.lastAggregateBlock && !full {
+		s.aggregateMu.Unlock()
+		return
+	}
+	calls := s.aggregated[:maxCalls:maxCalls]
+	s.aggregated = s.aggregated[maxCalls:]
+	s.lastAggregateBlock = blockNumber
+	s.aggregateMu.Unlock()
+
+	contextIDs := make([]string, len(calls))
+	multicalls := make([]multicall3Call, len(calls))
+	for i, c := range calls {
+		contextIDs[i] = c.contextID
+		multicalls[i] = c.call
+	}
+
+	data, err := multicall3ABI.Pack("aggregate3", multicalls)
+	if err != nil {
+		log.Error("failed to pack aggregate3", "service", s.service, "name", s.name, "calls", len(calls), "err", err)
+		s.failAggregatedCalls(calls)
+		return
+	}
+
+	contextID := multicallContextIDPrefix + strings.Join(contextIDs, ",")
+	hash, err := s.SendTransaction(contextID, &s.config().Multicall.Address, big.NewInt(0), data, 0)
+	if err != nil {
+		log.Error("failed to send aggregated transaction", "service", s.service, "name", s.name, "calls", len(calls), "err", err)
+		s.failAggregatedCalls(calls)
+		return
+	}
+	s.metrics.aggregatedCallTotal.WithLabelValues(s.service, s.name).Add(float64(len(calls)))
+	log.Info("aggregated calls sent", "service", s.service, "name", s.name, "hash", hash.String(), "calls", len(calls), "block number", blockNumber)
+}
+
+func (s *Sender) failAggregatedCalls(calls []*aggregatedCall) {
+	contextIDs := make([]string, len(calls))
+	results := make([]*CallResult, len(calls))
+	for i, c := range calls {
+		contextIDs[i] = c.contextID
+		results[i] = &CallResult{ContextID: c.contextID, Target: c.call.Target}
+	}
+	s.confirmCh <- &Confirmation{
+		ContextID:    multicallContextIDPrefix + strings.Join(contextIDs, ","),
+		IsSuccessful: false,
+		SenderType:   s.senderType,
+		CallResults:  results,
+	}
+}
+
+// IsAggregateContextID reports whether the context ID is the one of an aggregated Multicall3 transaction.
+func IsAggregateContextID(contextID string) bool {
+	return strings.HasPrefix(contextID, multicallContextIDPrefix)
+}
+
+// decodeCallResults decodes the result of every call of a confirmed aggregated transaction. The calls are decoded from the
+// transaction calldata and the results from the output of the callTracer, since aggregate3 doesn't emit them. If the
+// transaction can't be traced, calls not allowed to fail are known to have succeeded along with the transaction.
+func (s *Sender) decodeCallResults(contextID string, tx *gethTypes.Transaction, receipt *gethTypes.Receipt) []*CallResult {
+	contextIDs := strings.Split(strings.TrimPrefix(contextID, multicallContextIDPrefix), ",")
+
+	calls, err := unpackAggregate3Calls(tx.Data())
+	if err != nil || len(calls) != len(contextIDs) {
+		log.Error("failed to decode aggregated calls", "context ID", contextID, "hash", tx.Hash().String(), "calls", len(calls), "err", err)
+		return nil
+	}
+
+	results := make([]*CallResult, len(calls))
+	for i, call := range calls {
+		results[i] = &CallResult{ContextID: contextIDs[i], Target: call.Target}
+	}
+	if receipt.Status != gethTypes.ReceiptStatusSuccessful {
+		return results
+	}
+
+	var root callFrame
+	if err := s.rpcClient.CallContext(s.ctx, &root, "debug_traceTransaction", tx.Hash(), map[string]interface{}{"tracer": "callTracer"}); err != nil {
+		log.Warn("failed to trace aggregated transaction, results of calls allowed to fail are unknown", "hash", tx.Hash().String(), "err", err)
+		for i, call := range calls {
+			results[i].Success = !call.AllowFailure
+		}
+		return results
+	}
+
+	callResults, err := unpackAggregate3Results(root.Output)
+	if err != nil || len(callResults) != len(calls) {
+		log.Error("failed to decode aggregated call results", "hash", tx.Hash().String(), "results", len(callResults), "err", err)
+		return results
+	}
+	for i, callResult := range callResults {
+		results[i].Success = callResult.Success
+		results[i].ReturnData = callResult.ReturnData
+		if !callResult.Success {
+			results[i].RevertReason = decodeRevertReason(callResult.ReturnData)
+		}
+	}
+	return results
+}
+
+func unpackAggregate3Calls(data []byte) ([]multicall3Call, error) {
+	method := multicall3ABI.Methods["aggregate3"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, errors.New("not an aggregate3 call")
+	}
+	out, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3 calls, err: %w", err)
+	}
+	calls, ok := abi.ConvertType(out[0], new([]multicall3Call)).(*[]multicall3Call)
+	if !ok {
+		return nil, errors.New("failed to convert aggregate3 calls")
+	}
+	return *calls, nil
+}
+
+func unpackAggregate3Results(output []byte) ([]multicall3Result, error) {
+	out, err := multicall3ABI.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3 results, err: %w", err)
+	}
+	results, ok := abi.ConvertType(out[0], new([]multicall3Result)).(*[]multicall3Result)
+	if !ok {
+		return nil, errors.New("failed to convert aggregate3 results")
+	}
+	return *results, nil
+}
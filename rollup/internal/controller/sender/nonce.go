@@ -0,0 +1,103 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNonceNotReserved is returned when sending with a nonce which is not reserved for the context of the transaction.
+	ErrNonceNotReserved = errors.New("nonce not reserved for context")
+	// ErrReservedNonceUsed is returned when sending with a reserved nonce which has already been used.
+	ErrReservedNonceUsed = errors.New("reserved nonce already used")
+)
+
+// WithReservedNonce sends the transaction with a nonce reserved for its context by ReserveNonces instead of the next nonce of the sender.
+func WithReservedNonce(nonce uint64) SendOption {
+	return func(o *sendOptions) {
+		o.reservedNonce = &nonce
+	}
+}
+
+// ReserveNonces reserves n consecutive nonces of the sender account for contextID and returns them, so that producers
+// sharing the account pre-assign the nonces of their transactions regardless of the order they are sent in.
+// Reservations are persisted: reserving again for the same context returns the same nonces, e.g. after a restart.
+// Every reserved nonce has to be sent with WithReservedNonce, an unused one blocks the transactions with later nonces.
+func (s *Sender) ReserveNonces(contextID string, n int) ([]uint64, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of nonces to reserve: %d", n)
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	reservations, err := s.nonceReservationOrm.GetNonceReservationsByContextID(s.ctx, s.auth.From, contextID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce reservations, err: %w", err)
+	}
+	if len(reservations) > 0 {
+		if len(reservations) != n {
+			return nil, fmt.Errorf("context %s already reserved %d nonces, requested: %d", contextID, len(reservations), n)
+		}
+		nonces := make([]uint64, len(reservations))
+		for i := range reservations {
+			nonces[i] = reservations[i].Nonce
+		}
+		return nonces, nil
+	}
+
+	firstNonce := s.auth.Nonce.Uint64()
+	nonces := make([]uint64, n)
+	for i := range nonces {
+		nonces[i] = firstNonce + uint64(i)
+	}
+	if err := s.nonceReservationOrm.InsertNonceReservations(s.ctx, contextID, s.auth.From, nonces); err != nil {
+		return nil, fmt.Errorf("failed to insert nonce reservations, err: %w", err)
+	}
+
+	s.nextReservedNonce = firstNonce + uint64(n)
+	s.auth.Nonce = new(big.Int).SetUint64(s.nextReservedNonce)
+	s.metrics.reservedNonceTotal.WithLabelValues(s.service, s.name).Add(float64(n))
+	log.Info("nonces reserved", "service", s.service, "name", s.name, "context ID", contextID, "first nonce", firstNonce, "count", n)
+	return nonces, nil
+}
+
+// checkReservedNonce checks that nonce is reserved for contextID and not used yet.
+func (s *Sender) checkReservedNonce(contextID string, nonce uint64) error {
+	reservation, err := s.nonceReservationOrm.GetNonceReservation(s.ctx, s.auth.From, nonce)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce reservation, err: %w", err)
+	}
+	if reservation == nil || reservation.ContextID != contextID {
+		return fmt.Errorf("%w, context ID: %s, nonce: %d", ErrNonceNotReserved, contextID, nonce)
+	}
+	if reservation.Hash != "" {
+		return fmt.Errorf("%w, context ID: %s, nonce: %d, hash: %s", ErrReservedNonceUsed, contextID, nonce, reservation.Hash)
+	}
+	return nil
+}
+
+// useReservedNonce records tx as the transaction sent with its reserved nonce.
+func (s *Sender) useReservedNonce(tx *gethTypes.Transaction, dbTX *gorm.DB) error {
+	used, err := s.nonceReservationOrm.UpdateNonceReservationAsUsed(s.ctx, s.auth.From, tx.Nonce(), tx.Hash(), dbTX)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return fmt.Errorf("%w, nonce: %d, hash: %s", ErrReservedNonceUsed, tx.Nonce(), tx.Hash().String())
+	}
+	return nil
+}
+
+// maxNonce returns the larger of the pending nonce of the account and the nonce following the reserved ones.
+func (s *Sender) maxNonce(pendingNonce uint64) uint64 {
+	if s.nextReservedNonce > pendingNonce {
+		return s.nextReservedNonce
+	}
+	return pendingNonce
+}
@@ -0,0 +1,81 @@
+package sender
+
+import (
+	"context"
+	"time"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// SendOption configures optional parameters of a transaction sent by SendTransaction.
+type SendOption func(*sendOptions)
+
+type sendOptions struct {
+	deadline       *time.Time
+	stateOverrides StateOverrides
+	confirmations  *int64
+
+	targetInclusionBlocks uint64
+	reservedNonce         *uint64
+	dependsOn             string
+	blobSidecar           *gethTypes.BlobTxSidecar
+	traceCtx              context.Context
+	metadata              map[string]string
+}
+
+func newSendOptions(opts []SendOption) *sendOptions {
+	options := &sendOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithDeadline sets the time by which the transaction must be confirmed. If it is still unconfirmed after the deadline,
+// the sender cancels it with a self-transfer replacement and delivers a TxStatusExpired confirmation.
+func WithDeadline(deadline time.Time) SendOption {
+	return func(o *sendOptions) {
+		utcDeadline := deadline.UTC()
+		o.deadline = &utcDeadline
+	}
+}
+
+// WithStateOverrides sets the state override set applied when estimating the gas limit and simulating the transaction,
+// e.g. to estimate a batch commit assuming its parent batch, still in flight, has been committed.
+// Access lists are not generated for such transactions since eth_createAccessList does not support state overrides.
+func WithStateOverrides(overrides StateOverrides) SendOption {
+	return func(o *sendOptions) {
+		o.stateOverrides = overrides
+	}
+}
+
+// WithTraceContext traces the sending of the transaction as part of the trace of ctx, e.g. of the commit of a batch.
+func WithTraceContext(ctx context.Context) SendOption {
+	return func(o *sendOptions) {
+		o.traceCtx = ctx
+	}
+}
+
+// WithConfirmations overrides the confirmation depth of the sender for this transaction, e.g. rpc.LatestBlockNumber
+// for a gas oracle update or rpc.FinalizedBlockNumber for a batch finalization.
+func WithConfirmations(confirmations rpc.BlockNumber) SendOption {
+	return func(o *sendOptions) {
+		value := confirmations.Int64()
+		o.confirmations = &value
+	}
+}
+
+// WithMetadata attaches key/value labels to the transaction, e.g. the batch index or the message nonce. The labels are
+// stored with the transaction and its replacements, queryable with the labels ORM queries, and are delivered in the
+// Metadata of its confirmation. Several WithMetadata options are merged.
+func WithMetadata(metadata map[string]string) SendOption {
+	return func(o *sendOptions) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]string, len(metadata))
+		}
+		for key, value := range metadata {
+			o.metadata[key] = value
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package sender
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+)
+
+// OverrideAccount specifies the state of an account to be overridden when estimating gas and simulating a transaction.
+// Unlike gethclient.OverrideAccount unset fields are omitted, so that overriding the storage of a contract keeps its code.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      hexutil.Bytes               `json:"code,omitempty"`
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// StateOverrides is the state override set of eth_estimateGas and eth_call.
+type StateOverrides map[common.Address]OverrideAccount
+
+// toCallArg converts msg to the json-rpc call object, including the EIP-1559 fee fields.
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	return arg
+}
+
+// estimateGasWithOverrides calls eth_estimateGas at the pending block with the state override set.
+func (s *Sender) estimateGasWithOverrides(msg ethereum.CallMsg, overrides StateOverrides) (uint64, error) {
+	var gas hexutil.Uint64
+	if err := s.rpcClient.CallContext(s.ctx, &gas, "eth_estimateGas", toCallArg(msg), "pending", overrides); err != nil {
+		return 0, fmt.Errorf("failed to estimate gas with state overrides, err: %w", err)
+	}
+	return uint64(gas), nil
+}
+
+// callWithOverrides calls eth_call at the pending block with the state override set.
+func (s *Sender) callWithOverrides(msg ethereum.CallMsg, overrides StateOverrides) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := s.rpcClient.CallContext(s.ctx, &result, "eth_call", toCallArg(msg), "pending", overrides); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,237 @@
+package sender
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+)
+
+// QueuedTransaction is a transaction request waiting for an in-flight slot before being broadcast.
+type QueuedTransaction struct {
+	ContextID        string
+	Target           *common.Address
+	Value            *big.Int
+	Data             []byte
+	FallbackGasLimit uint64
+	Options          []SendOption
+
+	// attempts is the number of failed dispatches of the transaction, it is sent again from retryAt on.
+	attempts int
+	retryAt  time.Time
+}
+
+const (
+	// queuedTxMinBackoff and queuedTxMaxBackoff bound the delay before dispatching again a queued transaction which
+	// failed to be sent, the delay doubles on every failure.
+	queuedTxMinBackoff = time.Second
+	queuedTxMaxBackoff = 5 * time.Minute
+)
+
+// metadata returns the labels attached to the transaction with WithMetadata.
+func (t *QueuedTransaction) metadata() map[string]string {
+	return newSendOptions(t.Options).metadata
+}
+
+// QueueLength returns the number of transactions waiting in the queue.
+func (s *Sender) QueueLength() int {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	return len(s.queue)
+}
+
+// Flush broadcasts all queued transactions regardless of MaxPendingTxs and the BlobBudget, e.g. before a planned shutdown.
+// It stops at the first failure and leaves the remaining transactions in the queue.
+func (s *Sender) Flush() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	for {
+		queuedTx := s.peekQueuedTransaction()
+		if queuedTx == nil {
+			return nil
+		}
+		if _, err := s.sendQueuedTransaction(queuedTx); err != nil {
+			return fmt.Errorf("failed to flush queued transaction, context ID: %s, err: %w", queuedTx.ContextID, err)
+		}
+	}
+}
+
+// Drain removes all queued transactions without broadcasting them and returns them to the caller.
+func (s *Sender) Drain() []*QueuedTransaction {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	drained := s.queue
+	s.queue = nil
+	s.metrics.queueDepth.WithLabelValues(s.service, s.name).Set(0)
+	return drained
+}
+
+// isQueueingEnabled reports whether new transactions may wait in the queue, i.e. MaxPendingTxs or the BlobBudget is configured.
+func (s *Sender) isQueueingEnabled() bool {
+	return s.config().MaxPendingTxs > 0 || s.config().BlobBudget != nil
+}
+
+// isQueueingRequired reports whether a new transaction has to wait in the queue, either because earlier transactions
+// are still queued, because MaxPendingTxs transactions are in flight or because its blobs exceed the BlobBudget.
+func (s *Sender) isQueueingRequired(queuedTx *QueuedTransaction) (bool, error) {
+	if s.QueueLength() > 0 {
+		return true, nil
+	}
+
+	full, err := s.isMaxPendingTxsReached()
+	if err != nil || full {
+		return full, err
+	}
+	exceeded, err := s.isBlobBudgetExceeded(queuedTx.blobCount())
+	if err != nil {
+		return false, err
+	}
+	if exceeded {
+		s.metrics.blobBudgetQueuedTotal.WithLabelValues(s.service, s.name).Inc()
+	}
+	return exceeded, nil
+}
+
+// isMaxPendingTxsReached reports whether MaxPendingTxs transactions are in flight, always false if it is not configured.
+func (s *Sender) isMaxPendingTxsReached() (bool, error) {
+	maxPendingTxs := s.config().MaxPendingTxs
+	if maxPendingTxs == 0 {
+		return false, nil
+	}
+
+	pendingCount, err := s.pendingTransactionOrm.GetPendingTransactionCountBySenderType(s.ctx, s.senderType)
+	if err != nil {
+		log.Error("failed to get pending transaction count", "sender meta", s.getSenderMeta(), "err", err)
+		return false, fmt.Errorf("failed to get pending transaction count, err: %w", err)
+	}
+	return pendingCount >= maxPendingTxs, nil
+}
+
+func (s *Sender) enqueueTransaction(queuedTx *QueuedTransaction) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	s.queue = append(s.queue, queuedTx)
+	s.metrics.queuedTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+	s.metrics.queueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.queue)))
+	log.Info("in-flight limit reached, transaction queued", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID, "queue length", len(s.queue))
+}
+
+func (s *Sender) peekQueuedTransaction() *QueuedTransaction {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if len(s.queue) == 0 {
+		return nil
+	}
+	return s.queue[0]
+}
+
+func (s *Sender) popQueuedTransaction() {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if len(s.queue) == 0 {
+		return
+	}
+	s.queue = s.queue[1:]
+	s.metrics.queueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.queue)))
+}
+
+// sendQueuedTransaction broadcasts the head of the queue and removes it from the queue once it's sent.
+func (s *Sender) sendQueuedTransaction(queuedTx *QueuedTransaction) (common.Hash, error) {
+	hash, err := s.sendTransaction(queuedTx.ContextID, queuedTx.Target, queuedTx.Value, queuedTx.Data, queuedTx.FallbackGasLimit, queuedTx.Options...)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	s.popQueuedTransaction()
+	log.Info("queued transaction dispatched", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID, "hash", hash.String())
+	return hash, nil
+}
+
+// dispatchQueuedTransactions broadcasts queued transactions while the number of in-flight transactions is below MaxPendingTxs
+// and the blobs of the head of the queue fit in the BlobBudget.
+// A queued transaction which fails to be sent stays at the head of the queue and is sent again after a backoff, unless
+// the error is terminal, e.g. the transaction reverts: it is then dropped and reported as failed through the confirmation
+// channel, so that the caller can handle it like any other failed transaction.
+func (s *Sender) dispatchQueuedTransactions() {
+	if !s.isQueueingEnabled() || s.IsPaused() {
+		return
+	}
+
+	// the confirmations are sent once sendMu is released, so that a slow consumer does not block SendTransaction.
+	for _, cfm := range s.dispatchQueue() {
+		s.confirmCh <- cfm
+	}
+}
+
+// dispatchQueue sends the head of the queue while it may be sent, and returns the failure confirmations of the
+// transactions dropped from the queue.
+func (s *Sender) dispatchQueue() []*Confirmation {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	var failed []*Confirmation
+	for {
+		queuedTx := s.peekQueuedTransaction()
+		if queuedTx == nil || time.Now().Before(queuedTx.retryAt) {
+			return failed
+		}
+
+		full, err := s.isMaxPendingTxsReached()
+		if err != nil || full {
+			return failed
+		}
+		exceeded, err := s.isBlobBudgetExceeded(queuedTx.blobCount())
+		if err != nil {
+			log.Error("failed to check blob budget", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID, "err", err)
+			return failed
+		}
+		if exceeded {
+			return failed
+		}
+
+		_, err = s.sendQueuedTransaction(queuedTx)
+		if err == nil {
+			continue
+		}
+		if !isTerminalSendError(err) {
+			queuedTx.attempts++
+			backoff := queuedTxBackoff(queuedTx.attempts)
+			queuedTx.retryAt = time.Now().Add(backoff)
+			s.metrics.queuedTransactionRetryTotal.WithLabelValues(s.service, s.name).Inc()
+			log.Warn("failed to dispatch queued transaction, retrying", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID,
+				"attempts", queuedTx.attempts, "backoff", backoff, "err", err)
+			return failed
+		}
+
+		log.Error("failed to dispatch queued transaction, dropping it", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID, "err", err)
+		s.popQueuedTransaction()
+		failed = append(failed, &Confirmation{
+			ContextID:    queuedTx.ContextID,
+			IsSuccessful: false,
+			TxStatus:     types.TxStatusConfirmedFailed,
+			SenderType:   s.senderType,
+			RevertReason: revertReasonFromError(err),
+			Metadata:     queuedTx.metadata(),
+		})
+	}
+}
+
+// queuedTxBackoff returns the delay before the next dispatch of a queued transaction which failed attempts times.
+func queuedTxBackoff(attempts int) time.Duration {
+	backoff := queuedTxMinBackoff
+	for i := 1; i < attempts && backoff < queuedTxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > queuedTxMaxBackoff {
+		return queuedTxMaxBackoff
+	}
+	return backoff
+}
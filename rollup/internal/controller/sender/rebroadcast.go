@@ -0,0 +1,38 @@
+package sender
+
+import (
+	"strings"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// rebroadcastPendingTransactions broadcasts again the persisted pending transactions of the sender address, since the
+// node may have dropped them from its mempool while the sender was down. Failures are only logged: a transaction already
+// included is confirmed by the next check and one rejected by the node is escalated from its stored state.
+// It returns the number of rebroadcast transactions and the nonce following the last pending transaction, 0 if there is none.
+func (s *Sender) rebroadcastPendingTransactions() (int, uint64) {
+	var rebroadcast int
+	var nextNonce uint64
+	err := forEachPendingTransaction(s.ctx, s.pendingTransactionOrm, s.senderType, func(txnToRebroadcast *orm.PendingTransaction, tx *gethTypes.Transaction) error {
+		if txnToRebroadcast.SenderAddress != s.auth.From.String() {
+			return nil
+		}
+		nextNonce = tx.Nonce() + 1
+		if err := s.client.SendTransaction(s.ctx, tx); err != nil {
+			if !isAlreadyKnown(err) && !strings.Contains(err.Error(), "nonce too low") {
+				log.Warn("failed to rebroadcast pending transaction", "service", s.service, "name", s.name, "context ID", txnToRebroadcast.ContextID, "hash", tx.Hash().String(), "nonce", tx.Nonce(), "err", err)
+			}
+			return nil
+		}
+		rebroadcast++
+		log.Info("pending transaction rebroadcast", "service", s.service, "name", s.name, "context ID", txnToRebroadcast.ContextID, "hash", tx.Hash().String(), "nonce", tx.Nonce())
+		return nil
+	})
+	if err != nil {
+		log.Error("failed to rebroadcast pending transactions", "service", s.service, "name", s.name, "err", err)
+	}
+	return rebroadcast, nextNonce
+}
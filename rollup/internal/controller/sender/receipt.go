@@ -0,0 +1,111 @@
+package sender
+
+import (
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+)
+
+// DecodedEvent is a receipt log decoded against the bridge contract ABIs.
+type DecodedEvent struct {
+	Name    string
+	Address common.Address
+	Args    map[string]interface{}
+	Log     *gethTypes.Log
+}
+
+// decodeReceiptEvents decodes the receipt logs emitted by the bridge contracts, logs of unknown events are skipped.
+func decodeReceiptEvents(receipt *gethTypes.Receipt) []*DecodedEvent {
+	var events []*DecodedEvent
+	for _, receiptLog := range receipt.Logs {
+		if len(receiptLog.Topics) == 0 {
+			continue
+		}
+		for _, contractABI := range []*abi.ABI{
+			bridgeAbi.ScrollChainABI,
+			bridgeAbi.L1MessageQueueABI,
+			bridgeAbi.L2GasPriceOracleABI,
+			bridgeAbi.L2ScrollMessengerABI,
+			bridgeAbi.L2MessageQueueABI,
+			bridgeAbi.L1GasPriceOracleABI,
+		} {
+			if contractABI == nil {
+				continue
+			}
+			event, err := contractABI.EventByID(receiptLog.Topics[0])
+			if err != nil {
+				continue
+			}
+
+			args := make(map[string]interface{})
+			if len(receiptLog.Data) > 0 {
+				if err := contractABI.UnpackIntoMap(args, event.Name, receiptLog.Data); err != nil {
+					log.Warn("failed to unpack receipt log data", "event", event.Name, "tx hash", receipt.TxHash.String(), "err", err)
+					break
+				}
+			}
+			var indexed abi.Arguments
+			for _, arg := range event.Inputs {
+				if arg.Indexed {
+					indexed = append(indexed, arg)
+				}
+			}
+			if err := abi.ParseTopicsIntoMap(args, indexed, receiptLog.Topics[1:]); err != nil {
+				log.Warn("failed to parse receipt log topics", "event", event.Name, "tx hash", receipt.TxHash.String(), "err", err)
+				break
+			}
+
+			events = append(events, &DecodedEvent{
+				Name:    event.Name,
+				Address: receiptLog.Address,
+				Args:    args,
+				Log:     receiptLog,
+			})
+			break
+		}
+	}
+	return events
+}
+
+// effectiveGasPrice returns the gas price paid by the transaction, computed from the inclusion block base fee
+// when the node doesn't report it in the receipt.
+func effectiveGasPrice(tx *gethTypes.Transaction, receipt *gethTypes.Receipt, inclusionHeader *gethTypes.Header) *big.Int {
+	if receipt.EffectiveGasPrice != nil {
+		return new(big.Int).Set(receipt.EffectiveGasPrice)
+	}
+	if inclusionHeader == nil || inclusionHeader.BaseFee == nil {
+		return tx.GasPrice()
+	}
+	price := new(big.Int).Add(inclusionHeader.BaseFee, tx.GasTipCap())
+	if price.Cmp(tx.GasFeeCap()) > 0 {
+		return tx.GasFeeCap()
+	}
+	return price
+}
+
+// recordConfirmationMetrics records the gas spend of a confirmed transaction and, unless it is a cancellation,
+// the blocks elapsed since the first submission of its context and the number of escalations it went through.
+func (s *Sender) recordConfirmationMetrics(tx *gethTypes.Transaction, receipt *gethTypes.Receipt, gasPrice *big.Int, firstSubmitBlockNumber, replacements uint64) {
+	senderType := s.senderType.String()
+	s.metrics.gasUsedTotal.WithLabelValues(s.service, s.name, senderType).Add(float64(receipt.GasUsed))
+
+	fee := new(big.Float).SetInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed)))
+	feeGwei, _ := fee.Quo(fee, big.NewFloat(params.GWei)).Float64()
+	s.metrics.feeSpentGweiTotal.WithLabelValues(s.service, s.name, senderType).Add(feeGwei)
+
+	if s.isCancellationTx(tx) {
+		return
+	}
+	var blocks uint64
+	if inclusionBlockNumber := receipt.BlockNumber.Uint64(); inclusionBlockNumber > firstSubmitBlockNumber {
+		blocks = inclusionBlockNumber - firstSubmitBlockNumber
+	}
+	s.metrics.blocksToInclusion.WithLabelValues(s.service, s.name, senderType).Observe(float64(blocks))
+	s.metrics.escalationsPerTransaction.WithLabelValues(s.service, s.name, senderType).Observe(float64(replacements))
+}
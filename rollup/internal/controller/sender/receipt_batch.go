@@ -0,0 +1,111 @@
+package sender
+
+import (
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// receiptResult is a receipt fetched ahead of checking a pending transaction, err is ethereum.NotFound for an unknown transaction.
+type receiptResult struct {
+	receipt *gethTypes.Receipt
+	err     error
+}
+
+// batchCall sends elems in JSON-RPC batch requests of ReceiptBatchSize elements,
+// the error of a failed batch request is reported as the error of each of its elements.
+func (s *Sender) batchCall(elems []rpc.BatchElem) {
+	for start := 0; start < len(elems); start += s.config().ReceiptBatchSize {
+		end := start + s.config().ReceiptBatchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		if err := s.rpcClient.BatchCallContext(s.ctx, elems[start:end]); err != nil {
+			for i := start; i < end; i++ {
+				elems[i].Error = err
+			}
+		}
+	}
+}
+
+// batchTransactionReceipts fetches the receipts of the pending transactions with JSON-RPC batch requests instead of one
+// request per transaction, it returns nil when ReceiptBatchSize is not set. With UseBlockReceipts, the receipts of
+// transactions with a known inclusion block are taken from eth_getBlockReceipts, one call per block, and fetched
+// individually if the block is unknown or does not contain them anymore, e.g. after a reorg.
+func (s *Sender) batchTransactionReceipts(transactionsToCheck []orm.PendingTransaction) map[common.Hash]*receiptResult {
+	if s.config().ReceiptBatchSize <= 0 {
+		return nil
+	}
+
+	results := make(map[common.Hash]*receiptResult, len(transactionsToCheck))
+	var hashes []common.Hash
+	var blockHashes []common.Hash
+	hashesByBlock := make(map[common.Hash][]common.Hash)
+	for _, txnToCheck := range transactionsToCheck {
+		hash := common.HexToHash(txnToCheck.Hash)
+		if !s.config().UseBlockReceipts || txnToCheck.InclusionBlockHash == "" {
+			hashes = append(hashes, hash)
+			continue
+		}
+		blockHash := common.HexToHash(txnToCheck.InclusionBlockHash)
+		if _, ok := hashesByBlock[blockHash]; !ok {
+			blockHashes = append(blockHashes, blockHash)
+		}
+		hashesByBlock[blockHash] = append(hashesByBlock[blockHash], hash)
+	}
+
+	if len(blockHashes) > 0 {
+		blockReceipts := make([][]*gethTypes.Receipt, len(blockHashes))
+		elems := make([]rpc.BatchElem, len(blockHashes))
+		for i, blockHash := range blockHashes {
+			elems[i] = rpc.BatchElem{Method: "eth_getBlockReceipts", Args: []interface{}{blockHash}, Result: &blockReceipts[i]}
+		}
+		s.batchCall(elems)
+
+		for i, blockHash := range blockHashes {
+			receiptsByHash := make(map[common.Hash]*gethTypes.Receipt, len(blockReceipts[i]))
+			if elems[i].Error == nil {
+				for _, receipt := range blockReceipts[i] {
+					receiptsByHash[receipt.TxHash] = receipt
+				}
+			}
+			for _, hash := range hashesByBlock[blockHash] {
+				if receipt, ok := receiptsByHash[hash]; ok {
+					results[hash] = &receiptResult{receipt: receipt}
+				} else {
+					hashes = append(hashes, hash)
+				}
+			}
+		}
+	}
+
+	receipts := make([]*gethTypes.Receipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receipts[i]}
+	}
+	s.batchCall(elems)
+
+	for i, hash := range hashes {
+		switch {
+		case elems[i].Error != nil:
+			results[hash] = &receiptResult{err: elems[i].Error}
+		case receipts[i] == nil:
+			results[hash] = &receiptResult{err: ethereum.NotFound}
+		default:
+			results[hash] = &receiptResult{receipt: receipts[i]}
+		}
+	}
+	return results
+}
+
+// transactionReceipt returns the receipt of a transaction from the receipts fetched ahead, or from the node if it was not fetched.
+func (s *Sender) transactionReceipt(receipts map[common.Hash]*receiptResult, hash common.Hash) (*gethTypes.Receipt, error) {
+	if result, ok := receipts[hash]; ok {
+		return result.receipt, result.err
+	}
+	return s.client.TransactionReceipt(s.ctx, hash)
+}
@@ -0,0 +1,73 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// replayBatchSize is the number of recorded transactions loaded at once by forEachPendingTransaction.
+const replayBatchSize = 100
+
+// forEachPendingTransaction calls fn with the decoded pending transactions of senderType, in nonce order, until fn returns an error.
+func forEachPendingTransaction(ctx context.Context, pendingTransactionOrm *orm.PendingTransaction, senderType types.SenderType, fn func(*orm.PendingTransaction, *gethTypes.Transaction) error) error {
+	var minNonce uint64
+	for {
+		txs, err := pendingTransactionOrm.GetPendingTransactionsBySenderType(ctx, senderType, minNonce, replayBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get pending transactions, sender type: %v, err: %w", senderType, err)
+		}
+		if len(txs) == 0 {
+			return nil
+		}
+
+		for i := range txs {
+			tx := &gethTypes.Transaction{}
+			if err := tx.DecodeRLP(rlp.NewStream(bytes.NewReader(txs[i].RLPEncoding), 0)); err != nil {
+				return fmt.Errorf("failed to decode RLP, context ID: %s, hash: %s, err: %w", txs[i].ContextID, txs[i].Hash, err)
+			}
+			minNonce = tx.Nonce() + 1
+
+			if err := fn(&txs[i], tx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// isAlreadyKnown reports whether a broadcast failed because the node already has the transaction.
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(err.Error(), "already known")
+}
+
+// ReplayRecordedTransactions broadcasts the pending transactions recorded by a dry-run sender of senderType, in nonce order.
+// Transactions already known by the node are skipped. The records stay pending, so a regular sender picks
+// them up afterwards to track their confirmation. It returns the number of broadcast transactions.
+func ReplayRecordedTransactions(ctx context.Context, client *ethclient.Client, db *gorm.DB, senderType types.SenderType) (int, error) {
+	var replayed int
+	err := forEachPendingTransaction(ctx, orm.NewPendingTransaction(db), senderType, func(txnToReplay *orm.PendingTransaction, tx *gethTypes.Transaction) error {
+		if err := client.SendTransaction(ctx, tx); err != nil {
+			if isAlreadyKnown(err) {
+				log.Info("recorded transaction already known, skipping", "context ID", txnToReplay.ContextID, "hash", tx.Hash().String(), "nonce", tx.Nonce())
+				return nil
+			}
+			return fmt.Errorf("failed to replay transaction, context ID: %s, hash: %s, nonce: %d, err: %w", txnToReplay.ContextID, tx.Hash().String(), tx.Nonce(), err)
+		}
+
+		replayed++
+		log.Info("recorded transaction replayed", "sender type", senderType, "context ID", txnToReplay.ContextID, "hash", tx.Hash().String(), "nonce", tx.Nonce())
+		return nil
+	})
+	return replayed, err
+}
@@ -0,0 +1,174 @@
+package sender
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+)
+
+// Urgency classifies transactions by how long they can wait for a cheaper base fee.
+type Urgency int
+
+const (
+	// UrgencyUrgent transactions are sent immediately regardless of the base fee.
+	UrgencyUrgent Urgency = iota
+	// UrgencyDeferrable transactions are held while the base fee is above DeferBaseFeeCeiling.
+	UrgencyDeferrable
+)
+
+// String returns a string representation of the Urgency.
+func (u Urgency) String() string {
+	switch u {
+	case UrgencyUrgent:
+		return "UrgencyUrgent"
+	case UrgencyDeferrable:
+		return "UrgencyDeferrable"
+	default:
+		return fmt.Sprintf("Unknown Urgency (%d)", int(u))
+	}
+}
+
+// urgencyOf returns the urgency of the transactions sent by a sender type,
+// gas oracle updates can wait for cheaper blocks while batch commits and finalizations can not.
+func urgencyOf(senderType types.SenderType) Urgency {
+	switch senderType {
+	case types.SenderTypeL1GasOracle, types.SenderTypeL2GasOracle:
+		return UrgencyDeferrable
+	default:
+		return UrgencyUrgent
+	}
+}
+
+// deferredTransaction is a deferrable transaction held until the base fee drops or its max delay elapses.
+type deferredTransaction struct {
+	*QueuedTransaction
+	deferredAt time.Time
+	baseFee    uint64
+}
+
+// DeferredLength returns the number of transactions held by the fee scheduler.
+func (s *Sender) DeferredLength() int {
+	s.deferMu.Lock()
+	defer s.deferMu.Unlock()
+	return len(s.deferred)
+}
+
+// isDeferralEnabled reports whether the transactions of this sender go through the fee scheduler.
+func (s *Sender) isDeferralEnabled() bool {
+	return s.config().DeferBaseFeeCeiling > 0 && urgencyOf(s.senderType) == UrgencyDeferrable
+}
+
+// isDeferralRequired reports whether a new transaction has to be held, either because earlier
+// transactions are still held or because the current base fee is above DeferBaseFeeCeiling.
+func (s *Sender) isDeferralRequired() (bool, uint64, error) {
+	if !s.isDeferralEnabled() {
+		return false, 0, nil
+	}
+
+	_, baseFee, err := s.getBlockNumberAndBaseFee(s.ctx)
+	if err != nil {
+		log.Error("failed to get block number and base fee", "error", err)
+		return false, 0, fmt.Errorf("failed to get block number and base fee, err: %w", err)
+	}
+	return s.DeferredLength() > 0 || baseFee > s.config().DeferBaseFeeCeiling, baseFee, nil
+}
+
+func (s *Sender) deferTransaction(queuedTx *QueuedTransaction, baseFee uint64) {
+	s.deferMu.Lock()
+	defer s.deferMu.Unlock()
+
+	s.deferred = append(s.deferred, &deferredTransaction{QueuedTransaction: queuedTx, deferredAt: time.Now(), baseFee: baseFee})
+	s.metrics.deferredTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+	s.updateDeferredMetrics()
+	log.Info("base fee above ceiling, transaction deferred", "service", s.service, "name", s.name, "context ID", queuedTx.ContextID,
+		"base fee", baseFee, "ceiling", s.config().DeferBaseFeeCeiling, "deferred length", len(s.deferred))
+}
+
+// updateDeferredMetrics updates the deferred queue depth and the projected savings per gas,
+// that is the base fee saved if every held transaction is released at the ceiling. It requires deferMu.
+func (s *Sender) updateDeferredMetrics() {
+	var projectedSavings uint64
+	for _, deferredTx := range s.deferred {
+		if deferredTx.baseFee > s.config().DeferBaseFeeCeiling {
+			projectedSavings += deferredTx.baseFee - s.config().DeferBaseFeeCeiling
+		}
+	}
+	s.metrics.deferredQueueDepth.WithLabelValues(s.service, s.name).Set(float64(len(s.deferred)))
+	s.metrics.deferredProjectedSavings.WithLabelValues(s.service, s.name).Set(float64(projectedSavings))
+}
+
+// releaseDeferredTransactions sends the held transactions, in order, once the base fee falls under
+// DeferBaseFeeCeiling or once the oldest one has been held for MaxDeferSeconds.
+// A released transaction which fails to be sent is reported as failed through the confirmation channel.
+func (s *Sender) releaseDeferredTransactions() {
+	if !s.isDeferralEnabled() || s.IsPaused() || s.DeferredLength() == 0 {
+		return
+	}
+
+	_, baseFee, err := s.getBlockNumberAndBaseFee(s.ctx)
+	if err != nil {
+		log.Error("failed to get block number and base fee", "error", err)
+		return
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	for {
+		s.deferMu.Lock()
+		if len(s.deferred) == 0 {
+			s.deferMu.Unlock()
+			return
+		}
+		deferredTx := s.deferred[0]
+		maxDelayElapsed := s.config().MaxDeferSeconds > 0 && time.Since(deferredTx.deferredAt) >= time.Duration(s.config().MaxDeferSeconds)*time.Second
+		if baseFee > s.config().DeferBaseFeeCeiling && !maxDelayElapsed {
+			s.deferMu.Unlock()
+			return
+		}
+		s.deferred = s.deferred[1:]
+		s.updateDeferredMetrics()
+		s.deferMu.Unlock()
+
+		if baseFee <= s.config().DeferBaseFeeCeiling {
+			if deferredTx.baseFee > baseFee {
+				s.metrics.deferredSavingsTotal.WithLabelValues(s.service, s.name).Add(float64(deferredTx.baseFee - baseFee))
+			}
+		} else {
+			s.metrics.deferredMaxDelayTotal.WithLabelValues(s.service, s.name).Inc()
+		}
+
+		log.Info("deferred transaction released", "service", s.service, "name", s.name, "context ID", deferredTx.ContextID,
+			"base fee", baseFee, "deferred base fee", deferredTx.baseFee, "deferred for", time.Since(deferredTx.deferredAt), "max delay elapsed", maxDelayElapsed)
+
+		if _, err := s.sendDeferredTransaction(deferredTx.QueuedTransaction); err != nil {
+			log.Error("failed to send deferred transaction", "service", s.service, "name", s.name, "context ID", deferredTx.ContextID, "err", err)
+			s.confirmCh <- &Confirmation{
+				ContextID:    deferredTx.ContextID,
+				IsSuccessful: false,
+				TxStatus:     types.TxStatusConfirmedFailed,
+				SenderType:   s.senderType,
+				Metadata:     deferredTx.metadata(),
+			}
+		}
+	}
+}
+
+// sendDeferredTransaction sends a released transaction, going through the queue if required. It requires sendMu.
+func (s *Sender) sendDeferredTransaction(queuedTx *QueuedTransaction) (common.Hash, error) {
+	if s.isQueueingEnabled() {
+		queueingRequired, err := s.isQueueingRequired(queuedTx)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if queueingRequired {
+			s.enqueueTransaction(queuedTx)
+			return common.Hash{}, nil
+		}
+	}
+	return s.sendTransaction(queuedTx.ContextID, queuedTx.Target, queuedTx.Value, queuedTx.Data, queuedTx.FallbackGasLimit, queuedTx.Options...)
+}
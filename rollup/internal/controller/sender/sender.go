@@ -8,9 +8,13 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/holiman/uint256"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -21,6 +25,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types"
 
 	"scroll-tech/rollup/internal/config"
@@ -28,6 +33,8 @@ import (
 	"scroll-tech/rollup/internal/utils"
 )
 
+// The EIP-7702 set code transaction type is deferred: the go-ethereum fork the sender builds on has no SetCodeTx, it
+// is added along with the upgrade to a fork version providing it.
 const (
 	// AccessListTxType type for AccessListTx
 	AccessListTxType = "AccessListTx"
@@ -39,12 +46,62 @@ const (
 	LegacyTxType = "LegacyTx"
 )
 
+// SenderStatus represents a status change of the sender surfaced over the confirmation channel.
+type SenderStatus int
+
+const (
+	// SenderStatusNone indicates the confirmation carries transaction confirmation details rather than a status change.
+	SenderStatusNone SenderStatus = iota
+	// SenderPausedLowBalance indicates the sender stopped issuing new transactions because the account balance is below the threshold.
+	SenderPausedLowBalance
+	// SenderResumed indicates the sender resumed issuing new transactions after being paused.
+	SenderResumed
+	// SenderTransactionStuck indicates a transaction reached the max number of replacements and requires operator action.
+	SenderTransactionStuck
+)
+
+func (s SenderStatus) String() string {
+	switch s {
+	case SenderStatusNone:
+		return "SenderStatusNone"
+	case SenderPausedLowBalance:
+		return "SenderPausedLowBalance"
+	case SenderResumed:
+		return "SenderResumed"
+	case SenderTransactionStuck:
+		return "SenderTransactionStuck"
+	default:
+		return fmt.Sprintf("Unknown SenderStatus (%d)", int32(s))
+	}
+}
+
 // Confirmation struct used to indicate transaction confirmation details
 type Confirmation struct {
 	ContextID    string
 	IsSuccessful bool
 	TxHash       common.Hash
 	SenderType   types.SenderType
+	// TxStatus is the final status of the transaction: TxStatusConfirmed, TxStatusConfirmedFailed or TxStatusExpired,
+	// or TxStatusStuck along with SenderTransactionStuck.
+	TxStatus types.TxStatus
+	// SenderStatus is set when the confirmation notifies a sender status change instead of a transaction confirmation.
+	SenderStatus SenderStatus
+
+	// Receipt is the receipt of the confirmed transaction.
+	Receipt *gethTypes.Receipt
+	// Events are the receipt logs decoded against the bridge contract ABIs.
+	Events []*DecodedEvent
+	// EffectiveGasPrice is the gas price paid per unit of gas.
+	EffectiveGasPrice *big.Int
+	// GasUsed is the amount of gas used by the transaction.
+	GasUsed uint64
+	// CallResults are the results of the calls of an aggregated transaction, see AggregateCall.
+	CallResults []*CallResult
+	// RevertReason is the decoded revert reason of a transaction confirmed failed, empty if it could not be decoded.
+	RevertReason string
+	// Metadata are the labels attached to the transaction with WithMetadata, empty for the transactions recorded
+	// without labels.
+	Metadata map[string]string
 }
 
 // FeeData fee struct used to estimate gas price
@@ -56,27 +113,80 @@ type FeeData struct {
 	accessList gethTypes.AccessList
 
 	gasLimit uint64
+	// gasLimitMultipleNum and gasLimitMultipleDen are the multiple applied to the estimated gas limit, zero when the
+	// gas limit is not estimated.
+	gasLimitMultipleNum uint64
+	gasLimitMultipleDen uint64
+
+	// blobGasFeeCap and sidecar are only set for blob transactions.
+	blobGasFeeCap *big.Int
+	sidecar       *gethTypes.BlobTxSidecar
 }
 
 // Sender Transaction sender to send transaction to l1/l2 geth
 type Sender struct {
-	config     *config.SenderConfig
-	gethClient *gethclient.Client
-	client     *ethclient.Client // The client to retrieve on chain data or send transaction.
-	chainID    *big.Int          // The chain id of the endpoint
-	ctx        context.Context
-	service    string
-	name       string
-	senderType types.SenderType
+	cfg          atomic.Pointer[config.SenderConfig] // The config, its tunable parameters are replaced by UpdateConfig.
+	rpcClient    *rpc.Client
+	gethClient   *gethclient.Client
+	client       *ethclient.Client // The client to retrieve on chain data or send transaction.
+	feeEstimator FeeEstimator      // The estimator of the gas tip cap of DynamicFeeTx transactions.
+	chainID      *big.Int          // The chain id of the endpoint
+	txType       string            // The configured tx type, DynamicFeeTx is downgraded to LegacyTx on chains without EIP-1559.
+	ctx          context.Context
+	service      string
+	name         string
+	senderType   types.SenderType
 
 	auth *bind.TransactOpts
 
 	db                    *gorm.DB
 	pendingTransactionOrm *orm.PendingTransaction
+	nonceReservationOrm   *orm.NonceReservation
 
 	confirmCh chan *Confirmation
 	stopCh    chan struct{}
 
+	// sendMu serializes sending new transactions and dispatching queued ones.
+	sendMu  sync.Mutex
+	queueMu sync.Mutex
+	queue   []*QueuedTransaction
+
+	// nextReservedNonce is the nonce following the highest nonce reserved by ReserveNonces, it requires sendMu.
+	nextReservedNonce uint64
+
+	// blobsInBlock is the number of blobs of the new transactions sent at blobBlockNumber, they require sendMu.
+	blobBlockNumber uint64
+	blobsInBlock    uint64
+
+	deferMu  sync.Mutex
+	deferred []*deferredTransaction
+
+	dependentMu sync.Mutex
+	dependents  []*dependentTransaction
+
+	pausedLowBalance atomic.Bool
+	pausedManually   atomic.Bool
+
+	// aggregateMu guards the calls waiting to be aggregated into a Multicall3 transaction.
+	aggregateMu        sync.Mutex
+	aggregated         []*aggregatedCall
+	lastAggregateBlock uint64
+
+	// adminMu guards the fee estimator and the operator requests below.
+	adminMu           sync.Mutex
+	forcedEscalations map[string]struct{}
+	cancellations     map[string]struct{}
+
+	// escalationMu guards the escalate multiple and the replacement statistics.
+	escalationMu sync.Mutex
+	escalation   *escalationTracker
+
+	// webhook is nil when webhook delivery is disabled.
+	webhook *webhookNotifier
+
+	// accessListCache is nil when access list caching is disabled.
+	accessListCache *accessListCache
+
 	metrics *senderMetrics
 }
 
@@ -86,17 +196,36 @@ func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.Pri
 		return nil, fmt.Errorf("invalid params, EscalateMultipleNum; %v, EscalateMultipleDen: %v", config.EscalateMultipleNum, config.EscalateMultipleDen)
 	}
 
-	rpcClient, err := rpc.Dial(config.Endpoint)
+	if config.MaxGasPrice > 0 && (config.MinGasPrice > config.MaxGasPrice || config.MinGasTipCap > config.MaxGasPrice) {
+		return nil, fmt.Errorf("invalid params, MinGasPrice: %v, MinGasTipCap: %v, MaxGasPrice: %v", config.MinGasPrice, config.MinGasTipCap, config.MaxGasPrice)
+	}
+
+	escalation, err := newEscalationTracker(config.AdaptiveEscalation, config.EscalateMultipleNum, config.EscalateMultipleDen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create escalation tracker, err: %w", err)
+	}
+
+	rpcClient, err := tracing.DialRPC(ctx, config.Endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial eth client, err: %w", err)
 	}
 
 	client := ethclient.NewClient(rpcClient)
+	feeEstimator, err := newFeeEstimator(config.FeeEstimator, rpcClient, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fee estimator, err: %w", err)
+	}
+
 	chainID, err := client.ChainID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chain ID, err: %w", err)
 	}
 
+	txType, err := resolveTxType(ctx, rpcClient, client, config.TxType, service, name)
+	if err != nil {
+		return nil, err
+	}
+
 	auth, err := bind.NewKeyedTransactorWithChainID(priv, chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transactor with chain ID %v, err: %w", chainID, err)
@@ -111,31 +240,75 @@ func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.Pri
 
 	sender := &Sender{
 		ctx:                   ctx,
-		config:                config,
+		rpcClient:             rpcClient,
 		gethClient:            gethclient.New(rpcClient),
 		client:                client,
+		feeEstimator:          feeEstimator,
 		chainID:               chainID,
+		txType:                txType,
 		auth:                  auth,
 		db:                    db,
 		pendingTransactionOrm: orm.NewPendingTransaction(db),
+		nonceReservationOrm:   orm.NewNonceReservation(db),
 		confirmCh:             make(chan *Confirmation, 128),
 		stopCh:                make(chan struct{}),
 		name:                  name,
 		service:               service,
 		senderType:            senderType,
+		forcedEscalations:     make(map[string]struct{}),
+		cancellations:         make(map[string]struct{}),
+		escalation:            escalation,
 	}
+	sender.cfg.Store(config)
 	sender.metrics = initSenderMetrics(reg)
+	sender.metrics.escalateMultipleNum.WithLabelValues(service, name).Set(float64(escalation.multipleNum))
+
+	// never reuse a reserved nonce, even one not sent yet.
+	if sender.nextReservedNonce, err = sender.nonceReservationOrm.GetNextReservedNonce(ctx, auth.From); err != nil {
+		return nil, fmt.Errorf("failed to get next reserved nonce for address %s, err: %w", auth.From.Hex(), err)
+	}
+	auth.Nonce = new(big.Int).SetUint64(sender.maxNonce(nonce))
+
+	sender.webhook = newWebhookNotifier(config.Webhook)
+
+	if config.AccessList != nil && config.AccessList.CacheTTLSeconds > 0 {
+		sender.accessListCache = newAccessListCache(time.Duration(config.AccessList.CacheTTLSeconds) * time.Second)
+	}
+
+	if !config.DryRun {
+		rebroadcast, nextNonce := sender.rebroadcastPendingTransactions()
+		// never reuse the nonce of a persisted pending transaction, even one the node rejected.
+		if nextNonce > sender.auth.Nonce.Uint64() {
+			sender.auth.Nonce = new(big.Int).SetUint64(nextNonce)
+		}
+		if rebroadcast > 0 {
+			log.Info("rebroadcast pending transactions on startup", "service", service, "name", name, "rebroadcast", rebroadcast, "nonce", sender.auth.Nonce.Uint64())
+		}
+	}
 
 	go sender.loop(ctx)
+	if sender.webhook != nil {
+		go sender.webhookLoop()
+	}
 
 	return sender, nil
 }
 
+// config returns the current config of the sender.
+func (s *Sender) config() *config.SenderConfig {
+	return s.cfg.Load()
+}
+
 // GetChainID returns the chain ID associated with the sender.
 func (s *Sender) GetChainID() *big.Int {
 	return s.chainID
 }
 
+// CallContract calls a view function of target at the latest block of the chain of the sender.
+func (s *Sender) CallContract(target common.Address, data []byte) ([]byte, error) {
+	return s.client.CallContract(s.ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+}
+
 // Stop stop the sender module.
 func (s *Sender) Stop() {
 	close(s.stopCh)
@@ -153,44 +326,163 @@ func (s *Sender) SendConfirmation(cfm *Confirmation) {
 	s.confirmCh <- cfm
 }
 
-func (s *Sender) getFeeData(target *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, baseFee uint64) (*FeeData, error) {
-	if s.config.TxType == DynamicFeeTxType {
-		return s.estimateDynamicGas(target, value, data, fallbackGasLimit, baseFee)
+func (s *Sender) getFeeData(target *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, baseFee uint64, overrides StateOverrides) (*FeeData, error) {
+	if s.txType == DynamicFeeTxType {
+		return s.estimateDynamicGas(target, value, data, fallbackGasLimit, baseFee, overrides)
 	}
-	return s.estimateLegacyGas(target, value, data, fallbackGasLimit)
+	return s.estimateLegacyGas(target, value, data, fallbackGasLimit, overrides)
 }
 
 // SendTransaction send a signed L2tL1 transaction.
-func (s *Sender) SendTransaction(contextID string, target *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64) (common.Hash, error) {
+// If MaxPendingTxs is configured and already reached, the transaction is queued and an empty hash is returned with ErrQueued,
+// the hash of the dispatched transaction is then delivered through the confirmation channel.
+// Blob transactions are queued the same way while their blobs exceed the BlobBudget.
+// Deferrable transactions are held the same way while the base fee is above DeferBaseFeeCeiling.
+// Transactions sent WithDependsOn are held the same way until their dependency is pending.
+// The value is transferred along with data, it is accounted for in the MinBalance guard until the transaction confirms,
+// and the transaction is rejected with ErrValueExceedsBudget if it would bring the available balance under MinBalance.
+// Errors reported by the node match ErrNonceTooLow, ErrUnderpriced, ErrInsufficientFunds or ErrEstimationFailed with errors.Is.
+func (s *Sender) SendTransaction(contextID string, target *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, opts ...SendOption) (common.Hash, error) {
 	s.metrics.sendTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+
+	if s.pausedLowBalance.Load() {
+		return common.Hash{}, ErrSenderPausedLowBalance
+	}
+	if s.pausedManually.Load() {
+		return common.Hash{}, ErrSenderPaused
+	}
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	queuedTx := &QueuedTransaction{
+		ContextID:        contextID,
+		Target:           target,
+		Value:            value,
+		Data:             data,
+		FallbackGasLimit: fallbackGasLimit,
+		Options:          opts,
+	}
+
+	if dependsOn := newSendOptions(opts).dependsOn; dependsOn != "" {
+		waitRequired, err := s.isDependencyWaitRequired(dependsOn)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if waitRequired {
+			s.holdDependentTransaction(queuedTx, dependsOn)
+			return common.Hash{}, ErrQueued
+		}
+	}
+
+	deferralRequired, baseFee, err := s.isDeferralRequired()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if deferralRequired {
+		s.deferTransaction(queuedTx, baseFee)
+		return common.Hash{}, ErrQueued
+	}
+
+	if s.isQueueingEnabled() {
+		full, err := s.isQueueingRequired(queuedTx)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if full {
+			s.enqueueTransaction(queuedTx)
+			return common.Hash{}, ErrQueued
+		}
+	}
+
+	return s.sendTransaction(contextID, target, value, data, fallbackGasLimit, opts...)
+}
+
+func (s *Sender) sendTransaction(contextID string, target *common.Address, value *big.Int, data []byte, fallbackGasLimit uint64, opts ...SendOption) (common.Hash, error) {
+	options := newSendOptions(opts)
 	var (
 		feeData *FeeData
 		tx      *gethTypes.Transaction
 		err     error
 	)
 
-	blockNumber, baseFee, err := s.getBlockNumberAndBaseFee(s.ctx)
+	ctx, span := tracing.Start(tracing.Link(s.ctx, options.traceCtx), "sender.send_transaction", "service", s.service, "name", s.name, "context_id", contextID)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	blockNumber, baseFee, err := s.getBlockNumberAndBaseFee(ctx)
 	if err != nil {
 		log.Error("failed to get block number and base fee", "error", err)
 		return common.Hash{}, fmt.Errorf("failed to get block number and base fee, err: %w", err)
 	}
 
-	if feeData, err = s.getFeeData(target, value, data, fallbackGasLimit, baseFee); err != nil {
+	if options.reservedNonce != nil {
+		if err = s.checkReservedNonce(contextID, *options.reservedNonce); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	if value.Sign() > 0 {
+		if err = s.checkValueBudget(value); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	if options.blobSidecar != nil {
+		feeData, err = s.estimateBlobGas(options.blobSidecar, fallbackGasLimit, baseFee)
+	} else {
+		feeData, err = s.getFeeData(target, value, data, fallbackGasLimit, baseFee, options.stateOverrides)
+	}
+	if err != nil {
 		s.metrics.sendTransactionFailureGetFee.WithLabelValues(s.service, s.name).Inc()
 		log.Error("failed to get fee data", "from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "fallback gas limit", fallbackGasLimit, "err", err)
 		return common.Hash{}, fmt.Errorf("failed to get fee data, err: %w", err)
 	}
 
-	if tx, err = s.createAndSendTx(feeData, target, value, data, nil); err != nil {
+	if options.targetInclusionBlocks > 0 && s.txType == DynamicFeeTxType {
+		if err = s.applyTargetInclusionFees(feeData, nil, options.targetInclusionBlocks, baseFee); err != nil {
+			log.Warn("failed to derive target inclusion fees, using the estimated fees", "context ID", contextID, "blocks", options.targetInclusionBlocks, "err", err)
+		}
+	}
+
+	if s.config().SimulateBeforeSend && feeData.sidecar == nil {
+		if err = s.simulateTransaction(feeData, target, value, data, options.stateOverrides); err != nil {
+			s.metrics.sendTransactionFailureSimulation.WithLabelValues(s.service, s.name).Inc()
+			return common.Hash{}, err
+		}
+	}
+
+	if tx, err = s.createAndSendTx(feeData, target, value, data, options.reservedNonce); err != nil {
 		s.metrics.sendTransactionFailureSendTx.WithLabelValues(s.service, s.name).Inc()
 		log.Error("failed to create and send tx (non-resubmit case)", "from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "err", err)
 		return common.Hash{}, fmt.Errorf("failed to create and send transaction, err: %w", err)
 	}
+	span.SetAttributes("tx_hash", tx.Hash().String(), "nonce", tx.Nonce())
 
-	if err = s.pendingTransactionOrm.InsertPendingTransaction(s.ctx, contextID, s.getSenderMeta(), tx, blockNumber); err != nil {
+	err = s.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := s.pendingTransactionOrm.InsertPendingTransaction(ctx, contextID, s.getSenderMeta(), tx, blockNumber, options.deadline, options.confirmations, 0, options.targetInclusionBlocks, feeData.gasLimitMultipleNum, feeData.gasLimitMultipleDen, options.metadata, dbTX); err != nil {
+			return err
+		}
+		if options.reservedNonce != nil {
+			return s.useReservedNonce(tx, dbTX)
+		}
+		return nil
+	})
+	if err != nil {
 		log.Error("failed to insert transaction", "from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "err", err)
 		return common.Hash{}, fmt.Errorf("failed to insert transaction, err: %w", err)
 	}
+	if options.blobSidecar != nil {
+		s.recordSentBlobs(blockNumber, uint64(len(options.blobSidecar.Blobs)))
+	}
+	s.metrics.sentTransactionsTotal.WithLabelValues(s.service, s.name, s.senderType.String()).Inc()
+	s.notifyWebhook(WebhookEventSent, contextID, tx.Hash(), tx.Nonce(), nil, "")
 	return tx.Hash(), nil
 }
 
@@ -200,13 +492,31 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, value
 		txData gethTypes.TxData
 	)
 
-	// this is a resubmit call, override the nonce
+	// this is a resubmit call or a reserved nonce, override the nonce
 	if overrideNonce != nil {
 		nonce = *overrideNonce
 	}
 
-	switch s.config.TxType {
-	case LegacyTxType:
+	switch {
+	case feeData.sidecar != nil:
+		txData = &gethTypes.BlobTx{
+			ChainID:    uint256.MustFromBig(s.chainID),
+			Nonce:      nonce,
+			GasTipCap:  uint256.MustFromBig(feeData.gasTipCap),
+			GasFeeCap:  uint256.MustFromBig(feeData.gasFeeCap),
+			Gas:        feeData.gasLimit,
+			To:         *target,
+			Value:      uint256.MustFromBig(value),
+			Data:       common.CopyBytes(data),
+			AccessList: feeData.accessList,
+			BlobFeeCap: uint256.MustFromBig(feeData.blobGasFeeCap),
+			BlobHashes: feeData.sidecar.BlobHashes(),
+			Sidecar:    feeData.sidecar,
+			V:          new(uint256.Int),
+			R:          new(uint256.Int),
+			S:          new(uint256.Int),
+		}
+	case s.txType == LegacyTxType:
 		// for ganache mock node
 		txData = &gethTypes.LegacyTx{
 			Nonce:    nonce,
@@ -219,7 +529,7 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, value
 			R:        new(big.Int),
 			S:        new(big.Int),
 		}
-	case AccessListTxType:
+	case s.txType == AccessListTxType:
 		txData = &gethTypes.AccessListTx{
 			ChainID:    s.chainID,
 			Nonce:      nonce,
@@ -257,14 +567,16 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, value
 		return nil, err
 	}
 
-	if err = s.client.SendTransaction(s.ctx, tx); err != nil {
+	if s.config().DryRun {
+		log.Info("dry run, transaction recorded but not broadcast", "service", s.service, "name", s.name, "tx hash", tx.Hash().String(), "nonce", tx.Nonce())
+	} else if err = s.client.SendTransaction(s.ctx, tx); err != nil {
 		log.Error("failed to send tx", "tx hash", tx.Hash().String(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
 		// Check if contain nonce, and reset nonce
-		// only reset nonce when it is not from resubmit
+		// only reset nonce when it is not from resubmit or a reserved nonce
 		if strings.Contains(err.Error(), "nonce") && overrideNonce == nil {
 			s.resetNonce(context.Background())
 		}
-		return nil, err
+		return nil, classifyNodeError(err)
 	}
 
 	if feeData.gasTipCap != nil {
@@ -281,7 +593,7 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, value
 
 	s.metrics.currentGasLimit.WithLabelValues(s.service, s.name).Set(float64(feeData.gasLimit))
 
-	// update nonce when it is not from resubmit
+	// update nonce when it is not from resubmit or a reserved nonce
 	if overrideNonce == nil {
 		s.auth.Nonce = big.NewInt(int64(nonce + 1))
 	}
@@ -295,28 +607,58 @@ func (s *Sender) resetNonce(ctx context.Context) {
 		log.Warn("failed to reset nonce", "address", s.auth.From.String(), "err", err)
 		return
 	}
-	s.auth.Nonce = big.NewInt(int64(nonce))
+	s.auth.Nonce = new(big.Int).SetUint64(s.maxNonce(nonce))
 }
 
-func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee uint64) (*gethTypes.Transaction, error) {
-	escalateMultipleNum := new(big.Int).SetUint64(s.config.EscalateMultipleNum)
-	escalateMultipleDen := new(big.Int).SetUint64(s.config.EscalateMultipleDen)
-	maxGasPrice := new(big.Int).SetUint64(s.config.MaxGasPrice)
+// resubmitTransaction resubmits tx with escalated fees. A non-zero targetInclusionBlocks derives the fees
+// for the blocks left in the target inclusion window instead of applying the escalation multiple.
+func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee uint64, targetInclusionBlocks uint64) (*gethTypes.Transaction, error) {
+	feeData := s.escalateFeeData(tx, baseFee)
+	if targetInclusionBlocks > 0 && tx.Type() == gethTypes.DynamicFeeTxType {
+		if err := s.applyTargetInclusionFees(feeData, tx, targetInclusionBlocks, baseFee); err != nil {
+			log.Warn("failed to derive target inclusion fees, using the escalation multiple", "hash", tx.Hash().String(), "blocks", targetInclusionBlocks, "err", err)
+			feeData = s.escalateFeeData(tx, baseFee)
+		}
+	}
+
+	nonce := tx.Nonce()
+	s.metrics.resubmitTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+	tx, err := s.createAndSendTx(feeData, tx.To(), tx.Value(), tx.Data(), &nonce)
+	if err != nil {
+		log.Error("failed to create and send tx (resubmit case)", "from", s.auth.From.String(), "nonce", nonce, "err", err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// escalateFeeData returns the fee data of tx bumped by the escalate multiple, adjusted for the current base fee,
+// raised to MinGasPrice and MinGasTipCap and capped by MaxGasPrice.
+func (s *Sender) escalateFeeData(tx *gethTypes.Transaction, baseFee uint64) *FeeData {
+	if tx.Type() == gethTypes.BlobTxType {
+		return s.escalateBlobFeeData(tx, baseFee)
+	}
+
+	multipleNum, multipleDen := s.EscalateMultiple()
+	escalateMultipleNum := new(big.Int).SetUint64(multipleNum)
+	escalateMultipleDen := new(big.Int).SetUint64(multipleDen)
+	maxGasPrice := new(big.Int).SetUint64(s.config().MaxGasPrice)
 
 	txInfo := map[string]interface{}{
 		"tx_hash": tx.Hash().String(),
-		"tx_type": s.config.TxType,
+		"tx_type": s.txType,
 		"from":    s.auth.From.String(),
 		"nonce":   tx.Nonce(),
 	}
 
 	var feeData FeeData
 	feeData.gasLimit = tx.Gas()
-	switch s.config.TxType {
+	switch s.txType {
 	case LegacyTxType, AccessListTxType: // `LegacyTxType`is for ganache mock node
 		originalGasPrice := tx.GasPrice()
 		gasPrice := new(big.Int).Mul(escalateMultipleNum, originalGasPrice)
 		gasPrice = gasPrice.Div(gasPrice, escalateMultipleDen)
+		// a zero suggested gas price stays zero once escalated, raise it to the floor.
+		gasPrice = raiseToFloor(gasPrice, s.config().MinGasPrice)
 		if gasPrice.Cmp(maxGasPrice) > 0 {
 			gasPrice = maxGasPrice
 		}
@@ -337,6 +679,8 @@ func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee uint64)
 		gasTipCap = gasTipCap.Div(gasTipCap, escalateMultipleDen)
 		gasFeeCap := new(big.Int).Mul(originalGasFeeCap, escalateMultipleNum)
 		gasFeeCap = gasFeeCap.Div(gasFeeCap, escalateMultipleDen)
+		gasTipCap = raiseToFloor(gasTipCap, s.config().MinGasTipCap)
+		gasFeeCap = raiseToFloor(gasFeeCap, s.config().MinGasPrice)
 
 		// adjust for rising basefee
 		adjBaseFee := new(big.Int).SetUint64(baseFee)
@@ -376,15 +720,7 @@ func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee uint64)
 	}
 
 	log.Info("Transaction gas adjustment details", "service", s.service, "name", s.name, "txInfo", txInfo)
-
-	nonce := tx.Nonce()
-	s.metrics.resubmitTransactionTotal.WithLabelValues(s.service, s.name).Inc()
-	tx, err := s.createAndSendTx(&feeData, tx.To(), tx.Value(), tx.Data(), &nonce)
-	if err != nil {
-		log.Error("failed to create and send tx (resubmit case)", "from", s.auth.From.String(), "nonce", nonce, "err", err)
-		return nil, err
-	}
-	return tx, nil
+	return &feeData
 }
 
 // checkPendingTransaction checks the confirmation status of pending transactions against the latest confirmed block number.
@@ -404,12 +740,23 @@ func (s *Sender) checkPendingTransaction() {
 		return
 	}
 
-	confirmed, err := utils.GetLatestConfirmedBlockNumber(s.ctx, s.client, s.config.Confirmations)
-	if err != nil {
-		log.Error("failed to get latest confirmed block number", "confirmations", s.config.Confirmations, "err", err)
+	// latest confirmed block number by confirmation depth, transactions may override the depth of the sender.
+	confirmedByDepth := make(map[rpc.BlockNumber]uint64)
+	if _, err := s.confirmedBlockNumber(confirmedByDepth, nil); err != nil {
+		log.Error("failed to get latest confirmed block number", "confirmations", s.config().Confirmations, "err", err)
 		return
 	}
 
+	// first submission block by context ID, replaced transactions are checked until one of the context is confirmed.
+	firstSubmitBlockNumbers := make(map[string]uint64)
+	for _, txnToCheck := range transactionsToCheck {
+		if first, ok := firstSubmitBlockNumbers[txnToCheck.ContextID]; !ok || txnToCheck.SubmitBlockNumber < first {
+			firstSubmitBlockNumbers[txnToCheck.ContextID] = txnToCheck.SubmitBlockNumber
+		}
+	}
+
+	receipts := s.batchTransactionReceipts(transactionsToCheck)
+
 	for _, txnToCheck := range transactionsToCheck {
 		tx := new(gethTypes.Transaction)
 		if err := tx.DecodeRLP(rlp.NewStream(bytes.NewReader(txnToCheck.RLPEncoding), 0)); err != nil {
@@ -417,12 +764,43 @@ func (s *Sender) checkPendingTransaction() {
 			continue
 		}
 
-		receipt, err := s.client.TransactionReceipt(s.ctx, tx.Hash())
+		receipt, err := s.transactionReceipt(receipts, tx.Hash())
 		if (err == nil) && (receipt != nil) { // tx confirmed.
+			if txnToCheck.InclusionBlockHash != receipt.BlockHash.String() {
+				// Record the inclusion block when first seen, or when the tx was re-included in another block after a reorg.
+				if err := s.pendingTransactionOrm.UpdateInclusionBlockByTxHash(s.ctx, tx.Hash(), receipt.BlockNumber.Uint64(), receipt.BlockHash); err != nil {
+					log.Error("failed to update inclusion block by tx hash", "hash", tx.Hash().String(), "block number", receipt.BlockNumber, "block hash", receipt.BlockHash.String(), "err", err)
+					return
+				}
+			}
+
+			confirmed, err := s.confirmedBlockNumber(confirmedByDepth, txnToCheck.Confirmations)
+			if err != nil {
+				log.Error("failed to get latest confirmed block number", "context ID", txnToCheck.ContextID, "sender meta", s.getSenderMeta(), "err", err)
+				return
+			}
+
 			if receipt.BlockNumber.Uint64() <= confirmed {
-				err := s.db.Transaction(func(dbTX *gorm.DB) error {
-					// Update the status of the transaction to TxStatusConfirmed.
-					if err := s.pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(s.ctx, tx.Hash(), types.TxStatusConfirmed, dbTX); err != nil {
+				// Re-verify the inclusion block at the confirmation depth, the receipt may point to a block that has been reorged out.
+				header, err := s.client.HeaderByNumber(s.ctx, receipt.BlockNumber)
+				if err != nil {
+					log.Error("failed to get inclusion block header", "hash", tx.Hash().String(), "block number", receipt.BlockNumber, "err", err)
+					return
+				}
+				if header.Hash() != receipt.BlockHash {
+					log.Warn("inclusion block of transaction is not canonical, waiting for a new receipt", "hash", tx.Hash().String(), "block number", receipt.BlockNumber, "receipt block hash", receipt.BlockHash.String(), "canonical block hash", header.Hash().String())
+					continue
+				}
+
+				// A confirmed cancellation means the original transaction expired without being included.
+				txStatus := types.TxStatusConfirmed
+				if s.isCancellationTx(tx) {
+					txStatus = types.TxStatusExpired
+				}
+
+				err = s.db.Transaction(func(dbTX *gorm.DB) error {
+					// Update the status of the transaction to TxStatusConfirmed, or TxStatusExpired for cancellations.
+					if err := s.pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(s.ctx, tx.Hash(), txStatus, dbTX); err != nil {
 						log.Error("failed to update transaction status by tx hash", "hash", tx.Hash().String(), "sender meta", s.getSenderMeta(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
 						return err
 					}
@@ -438,16 +816,83 @@ func (s *Sender) checkPendingTransaction() {
 					return
 				}
 
+				var revertReason string
+				if receipt.Status != gethTypes.ReceiptStatusSuccessful {
+					revertReason = s.revertReason(tx, receipt)
+				}
+
+				s.recordReplacementOutcome(txnToCheck.ContextID, tx.Hash(), true)
+
+				gasPrice := effectiveGasPrice(tx, receipt, header)
+				s.recordConfirmationMetrics(tx, receipt, gasPrice, firstSubmitBlockNumbers[txnToCheck.ContextID], txnToCheck.Replacements)
+
 				// send confirm message
+				cfmStatus := txStatus
+				if receipt.Status != gethTypes.ReceiptStatusSuccessful && txStatus == types.TxStatusConfirmed {
+					cfmStatus = types.TxStatusConfirmedFailed
+				}
+				var callResults []*CallResult
+				if IsAggregateContextID(txnToCheck.ContextID) && txStatus == types.TxStatusConfirmed {
+					callResults = s.decodeCallResults(txnToCheck.ContextID, tx, receipt)
+				}
+				webhookEvent := WebhookEventConfirmed
+				if cfmStatus != types.TxStatusConfirmed {
+					webhookEvent = WebhookEventFailed
+				}
+				s.notifyWebhook(webhookEvent, txnToCheck.ContextID, tx.Hash(), tx.Nonce(), nil, cfmStatus.String())
 				s.confirmCh <- &Confirmation{
-					ContextID:    txnToCheck.ContextID,
-					IsSuccessful: receipt.Status == gethTypes.ReceiptStatusSuccessful,
-					TxHash:       tx.Hash(),
-					SenderType:   s.senderType,
+					ContextID:         txnToCheck.ContextID,
+					IsSuccessful:      cfmStatus == types.TxStatusConfirmed,
+					TxStatus:          cfmStatus,
+					TxHash:            tx.Hash(),
+					SenderType:        s.senderType,
+					Receipt:           receipt,
+					Events:            decodeReceiptEvents(receipt),
+					EffectiveGasPrice: gasPrice,
+					GasUsed:           receipt.GasUsed,
+					CallResults:       callResults,
+					RevertReason:      revertReason,
+					Metadata:          txnToCheck.Metadata,
 				}
 			}
+		} else if errors.Is(err, ethereum.NotFound) && txnToCheck.InclusionBlockHash != "" {
+			// The tx has been included before but its receipt disappeared, i.e. the inclusion block was reorged out.
+			s.handleReorgedTransaction(tx, &txnToCheck)
+		} else if txnToCheck.Status == types.TxStatusPending && !s.isCancellationTx(tx) &&
+			((txnToCheck.Deadline != nil && time.Now().UTC().After(*txnToCheck.Deadline)) || s.consumeCancellation(txnToCheck.ContextID)) {
+			log.Warn("transaction deadline exceeded or cancellation requested, cancelling",
+				"service", s.service,
+				"name", s.name,
+				"context ID", txnToCheck.ContextID,
+				"hash", tx.Hash().String(),
+				"nonce", tx.Nonce(),
+				"deadline", txnToCheck.Deadline)
+
+			s.forgetReplacement(txnToCheck.ContextID)
+			cancelTx, err := s.cancelTransaction(tx, baseFee)
+			if err != nil {
+				s.metrics.cancelTransactionFailedTotal.WithLabelValues(s.service, s.name).Inc()
+				log.Error("failed to cancel expired transaction", "context ID", txnToCheck.ContextID, "sender meta", s.getSenderMeta(), "nonce", tx.Nonce(), "err", err)
+				continue
+			}
+
+			err = s.db.Transaction(func(dbTX *gorm.DB) error {
+				if err := s.pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(s.ctx, tx.Hash(), types.TxStatusReplaced, dbTX); err != nil {
+					return fmt.Errorf("failed to update status of transaction with hash %s to TxStatusReplaced, err: %w", tx.Hash().String(), err)
+				}
+				if err := s.pendingTransactionOrm.InsertPendingTransaction(s.ctx, txnToCheck.ContextID, s.getSenderMeta(), cancelTx, blockNumber, txnToCheck.Deadline, txnToCheck.Confirmations, txnToCheck.Replacements, 0, 0, 0, txnToCheck.Metadata, dbTX); err != nil {
+					return fmt.Errorf("failed to insert cancellation transaction with context ID: %s, nonce: %d, hash: %v, err: %w", txnToCheck.ContextID, cancelTx.Nonce(), cancelTx.Hash().String(), err)
+				}
+				return nil
+			})
+			if err != nil {
+				log.Error("db transaction failed after cancelling", "err", err)
+				return
+			}
+			replacedTxHash := tx.Hash()
+			s.notifyWebhook(WebhookEventReplaced, txnToCheck.ContextID, cancelTx.Hash(), cancelTx.Nonce(), &replacedTxHash, "")
 		} else if txnToCheck.Status == types.TxStatusPending && // Only try resubmitting a new transaction based on gas price of the last transaction (status pending) with same ContextID.
-			s.config.EscalateBlocks+txnToCheck.SubmitBlockNumber <= blockNumber {
+			(s.config().EscalateBlocks+txnToCheck.SubmitBlockNumber <= blockNumber || s.consumeForcedEscalation(txnToCheck.ContextID)) {
 			// It's possible that the pending transaction was marked as failed earlier in this loop (e.g., if one of its replacements has already been confirmed).
 			// Therefore, we fetch the current transaction status again for accuracy before proceeding.
 			status, err := s.pendingTransactionOrm.GetTxStatusByTxHash(s.ctx, tx.Hash())
@@ -460,6 +905,14 @@ func (s *Sender) checkPendingTransaction() {
 				continue
 			}
 
+			// the latest replacement, if tx, was not included within EscalateBlocks.
+			s.recordReplacementOutcome(txnToCheck.ContextID, tx.Hash(), false)
+
+			if s.config().MaxReplacements > 0 && txnToCheck.Replacements >= s.config().MaxReplacements {
+				s.markTransactionStuck(tx, &txnToCheck)
+				continue
+			}
+
 			log.Info("resubmit transaction",
 				"service", s.service,
 				"name", s.name,
@@ -468,9 +921,14 @@ func (s *Sender) checkPendingTransaction() {
 				"nonce", tx.Nonce(),
 				"submitBlockNumber", txnToCheck.SubmitBlockNumber,
 				"currentBlockNumber", blockNumber,
-				"escalateBlocks", s.config.EscalateBlocks)
+				"escalateBlocks", s.config().EscalateBlocks)
 
-			if newTx, err := s.resubmitTransaction(tx, baseFee); err != nil {
+			var targetInclusionBlocks uint64
+			if txnToCheck.TargetInclusionBlocks > 0 {
+				targetInclusionBlocks = targetInclusionBlocksLeft(txnToCheck.TargetInclusionBlocks, firstSubmitBlockNumbers[txnToCheck.ContextID], blockNumber)
+			}
+			multipleNum, _ := s.EscalateMultiple()
+			if newTx, err := s.resubmitTransaction(tx, baseFee, targetInclusionBlocks); err != nil {
 				s.metrics.resubmitTransactionFailedTotal.WithLabelValues(s.service, s.name).Inc()
 				log.Error("failed to resubmit transaction", "context ID", txnToCheck.ContextID, "sender meta", s.getSenderMeta(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
 			} else {
@@ -480,7 +938,7 @@ func (s *Sender) checkPendingTransaction() {
 						return fmt.Errorf("failed to update status of transaction with hash %s to TxStatusReplaced, err: %w", tx.Hash().String(), err)
 					}
 					// Record the new transaction that has replaced the original one.
-					if err := s.pendingTransactionOrm.InsertPendingTransaction(s.ctx, txnToCheck.ContextID, s.getSenderMeta(), newTx, blockNumber, dbTX); err != nil {
+					if err := s.pendingTransactionOrm.InsertPendingTransaction(s.ctx, txnToCheck.ContextID, s.getSenderMeta(), newTx, blockNumber, txnToCheck.Deadline, txnToCheck.Confirmations, txnToCheck.Replacements+1, txnToCheck.TargetInclusionBlocks, txnToCheck.GasLimitMultipleNum, txnToCheck.GasLimitMultipleDen, txnToCheck.Metadata, dbTX); err != nil {
 						return fmt.Errorf("failed to insert new pending transaction with context ID: %s, nonce: %d, hash: %v, previous block number: %v, current block number: %v, err: %w", txnToCheck.ContextID, newTx.Nonce(), newTx.Hash().String(), txnToCheck.SubmitBlockNumber, blockNumber, err)
 					}
 					return nil
@@ -489,20 +947,151 @@ func (s *Sender) checkPendingTransaction() {
 					log.Error("db transaction failed after resubmitting", "err", err)
 					return
 				}
+				s.metrics.replacedTransactionsTotal.WithLabelValues(s.service, s.name, s.senderType.String()).Inc()
+				replacedTxHash := tx.Hash()
+				s.notifyWebhook(WebhookEventReplaced, txnToCheck.ContextID, newTx.Hash(), newTx.Nonce(), &replacedTxHash, "")
+				// fees derived for a target inclusion window don't depend on the escalate multiple.
+				if targetInclusionBlocks == 0 {
+					s.recordReplacementSent(txnToCheck.ContextID, newTx.Hash(), multipleNum)
+				}
 			}
 		}
 	}
 }
 
+// confirmedBlockNumber returns the latest block number confirmed at the given depth, nil meaning the depth of the sender.
+// Lookups are cached in confirmedByDepth for the duration of a check.
+func (s *Sender) confirmedBlockNumber(confirmedByDepth map[rpc.BlockNumber]uint64, confirmations *int64) (uint64, error) {
+	depth := s.config().Confirmations
+	if confirmations != nil {
+		depth = rpc.BlockNumber(*confirmations)
+	}
+	if confirmed, ok := confirmedByDepth[depth]; ok {
+		return confirmed, nil
+	}
+	confirmed, err := utils.GetLatestConfirmedBlockNumber(s.ctx, s.client, depth)
+	if err != nil {
+		return 0, err
+	}
+	confirmedByDepth[depth] = confirmed
+	return confirmed, nil
+}
+
+// checkConfirmedTransactions re-verifies the inclusion blocks of recently confirmed transactions,
+// and moves the transactions back to pending if their inclusion blocks have been reorged out.
+func (s *Sender) checkConfirmedTransactions() {
+	if s.config().ReorgCheckBlocks == 0 {
+		return
+	}
+
+	confirmed, err := utils.GetLatestConfirmedBlockNumber(s.ctx, s.client, s.config().Confirmations)
+	if err != nil {
+		log.Error("failed to get latest confirmed block number", "confirmations", s.config().Confirmations, "err", err)
+		return
+	}
+
+	var fromBlockNumber uint64
+	if confirmed > s.config().ReorgCheckBlocks {
+		fromBlockNumber = confirmed - s.config().ReorgCheckBlocks
+	}
+
+	transactionsToCheck, err := s.pendingTransactionOrm.GetConfirmedTransactionsBySenderTypeAfterBlock(s.ctx, s.senderType, fromBlockNumber, 100)
+	if err != nil {
+		log.Error("failed to load confirmed transactions", "sender meta", s.getSenderMeta(), "from block number", fromBlockNumber, "err", err)
+		return
+	}
+
+	for _, txnToCheck := range transactionsToCheck {
+		header, err := s.client.HeaderByNumber(s.ctx, new(big.Int).SetUint64(txnToCheck.InclusionBlockNumber))
+		if err != nil {
+			log.Error("failed to get inclusion block header", "hash", txnToCheck.Hash, "block number", txnToCheck.InclusionBlockNumber, "err", err)
+			return
+		}
+		if header.Hash().String() == txnToCheck.InclusionBlockHash {
+			continue
+		}
+
+		tx := new(gethTypes.Transaction)
+		if err := tx.DecodeRLP(rlp.NewStream(bytes.NewReader(txnToCheck.RLPEncoding), 0)); err != nil {
+			log.Error("failed to decode RLP", "context ID", txnToCheck.ContextID, "sender meta", s.getSenderMeta(), "err", err)
+			continue
+		}
+
+		receipt, err := s.client.TransactionReceipt(s.ctx, tx.Hash())
+		if err == nil && receipt != nil {
+			// The tx was re-included in another block, only the inclusion info needs to be refreshed.
+			if err := s.pendingTransactionOrm.UpdateInclusionBlockByTxHash(s.ctx, tx.Hash(), receipt.BlockNumber.Uint64(), receipt.BlockHash); err != nil {
+				log.Error("failed to update inclusion block by tx hash", "hash", tx.Hash().String(), "block number", receipt.BlockNumber, "block hash", receipt.BlockHash.String(), "err", err)
+				return
+			}
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			log.Error("failed to get transaction receipt", "hash", tx.Hash().String(), "err", err)
+			return
+		}
+
+		s.handleReorgedTransaction(tx, &txnToCheck)
+	}
+}
+
+// handleReorgedTransaction clears the inclusion info of a transaction whose inclusion block has been reorged out,
+// moves it back to pending if it was confirmed, and rebroadcasts it.
+func (s *Sender) handleReorgedTransaction(tx *gethTypes.Transaction, txnToCheck *orm.PendingTransaction) {
+	s.metrics.reorgedTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+	log.Warn("inclusion block of transaction reorged out, rebroadcasting",
+		"service", s.service,
+		"name", s.name,
+		"hash", tx.Hash().String(),
+		"nonce", tx.Nonce(),
+		"status", txnToCheck.Status,
+		"inclusion block number", txnToCheck.InclusionBlockNumber,
+		"inclusion block hash", txnToCheck.InclusionBlockHash)
+
+	err := s.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := s.pendingTransactionOrm.UpdateInclusionBlockByTxHash(s.ctx, tx.Hash(), 0, common.Hash{}, dbTX); err != nil {
+			return err
+		}
+		if txnToCheck.Status == types.TxStatusConfirmed {
+			if err := s.pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(s.ctx, tx.Hash(), types.TxStatusPending, dbTX); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("db transaction failed after detecting reorged transaction", "hash", tx.Hash().String(), "err", err)
+		return
+	}
+
+	if err := s.client.SendTransaction(s.ctx, tx); err != nil {
+		// The tx may still be in the tx pool of the node, e.g. "already known".
+		log.Warn("failed to rebroadcast reorged transaction", "hash", tx.Hash().String(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
+	}
+}
+
 // Loop is the main event loop
 func (s *Sender) loop(ctx context.Context) {
-	checkTick := time.NewTicker(time.Duration(s.config.CheckPendingTime) * time.Second)
+	checkTick := time.NewTicker(time.Duration(s.config().CheckPendingTime) * time.Second)
 	defer checkTick.Stop()
 
+	s.checkBalance()
+
 	for {
 		select {
 		case <-checkTick.C:
+			if s.config().DryRun {
+				// recorded transactions are never broadcast, there is nothing to confirm or escalate.
+				continue
+			}
+			s.checkBalance()
 			s.checkPendingTransaction()
+			s.checkConfirmedTransactions()
+			s.releaseDeferredTransactions()
+			s.releaseDependentTransactions()
+			s.flushAggregatedCalls()
+			s.dispatchQueuedTransactions()
+			s.checkBlobSlots()
 		case <-ctx.Done():
 			return
 		case <-s.stopCh:
@@ -527,7 +1116,7 @@ func (s *Sender) getBlockNumberAndBaseFee(ctx context.Context) (uint64, uint64,
 	}
 
 	var baseFeePerGas uint64
-	if s.config.TxType == DynamicFeeTxType {
+	if s.txType == DynamicFeeTxType {
 		if header.BaseFee != nil {
 			baseFeePerGas = header.BaseFee.Uint64()
 		} else {
@@ -12,12 +12,48 @@ type senderMetrics struct {
 	sendTransactionTotal               *prometheus.CounterVec
 	sendTransactionFailureGetFee       *prometheus.CounterVec
 	sendTransactionFailureSendTx       *prometheus.CounterVec
+	sendTransactionFailureSimulation   *prometheus.CounterVec
 	resubmitTransactionTotal           *prometheus.CounterVec
 	resubmitTransactionFailedTotal     *prometheus.CounterVec
+	reorgedTransactionTotal            *prometheus.CounterVec
+	traceFailedTransactionFailedTotal  *prometheus.CounterVec
+	stuckTransactionTotal              *prometheus.CounterVec
+	cancelTransactionTotal             *prometheus.CounterVec
+	cancelTransactionFailedTotal       *prometheus.CounterVec
+	queuedTransactionTotal             *prometheus.CounterVec
+	queuedTransactionRetryTotal        *prometheus.CounterVec
+	blobBudgetQueuedTotal              *prometheus.CounterVec
+	blobSlotsInUse                     *prometheus.GaugeVec
+	blobSlotsLimit                     *prometheus.GaugeVec
+	aggregatedCallTotal                *prometheus.CounterVec
+	reservedNonceTotal                 *prometheus.CounterVec
+	replacementOutcomeTotal            *prometheus.CounterVec
+	escalateMultipleNum                *prometheus.GaugeVec
+	webhookDroppedTotal                *prometheus.CounterVec
+	webhookFailedTotal                 *prometheus.CounterVec
+	queueDepth                         *prometheus.GaugeVec
+	deferredTransactionTotal           *prometheus.CounterVec
+	deferredMaxDelayTotal              *prometheus.CounterVec
+	deferredSavingsTotal               *prometheus.CounterVec
+	deferredQueueDepth                 *prometheus.GaugeVec
+	dependentQueueDepth                *prometheus.GaugeVec
+	deferredProjectedSavings           *prometheus.GaugeVec
+	accountBalance                     *prometheus.GaugeVec
+	pausedLowBalance                   *prometheus.GaugeVec
+	inFlightValue                      *prometheus.GaugeVec
+	valueBudgetExceededTotal           *prometheus.CounterVec
 	currentGasFeeCap                   *prometheus.GaugeVec
 	currentGasTipCap                   *prometheus.GaugeVec
 	currentGasPrice                    *prometheus.GaugeVec
 	currentGasLimit                    *prometheus.GaugeVec
+
+	// metrics keyed by sender type in addition to service and name.
+	sentTransactionsTotal     *prometheus.CounterVec
+	replacedTransactionsTotal *prometheus.CounterVec
+	gasUsedTotal              *prometheus.CounterVec
+	feeSpentGweiTotal         *prometheus.CounterVec
+	blocksToInclusion         *prometheus.HistogramVec
+	escalationsPerTransaction *prometheus.HistogramVec
 }
 
 var (
@@ -40,6 +76,10 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_send_transaction_send_tx_failure_total",
 				Help: "The total number of sending transactions failure for sending tx.",
 			}, []string{"service", "name"}),
+			sendTransactionFailureSimulation: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_send_transaction_simulation_failure_total",
+				Help: "The total number of sending transactions failure for reverted simulation.",
+			}, []string{"service", "name"}),
 			resubmitTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 				Name: "rollup_sender_send_transaction_resubmit_send_transaction_total",
 				Help: "The total number of resubmit transactions.",
@@ -48,6 +88,114 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_send_transaction_resubmit_send_transaction_failed_total",
 				Help: "The total number of failed resubmit transactions.",
 			}, []string{"service", "name"}),
+			reorgedTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_reorged_transaction_total",
+				Help: "The total number of transactions whose inclusion block was reorged out.",
+			}, []string{"service", "name"}),
+			traceFailedTransactionFailedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_trace_failed_transaction_failed_total",
+				Help: "The total number of failures to trace reverted transactions.",
+			}, []string{"service", "name"}),
+			stuckTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_stuck_transaction_total",
+				Help: "The total number of transactions marked stuck after reaching the max number of replacements.",
+			}, []string{"service", "name"}),
+			reservedNonceTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_reserved_nonce_total",
+				Help: "The total number of nonces reserved by ReserveNonces.",
+			}, []string{"service", "name"}),
+			replacementOutcomeTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_replacement_outcome_total",
+				Help: "The total number of replacements by escalate multiple numerator and outcome, successful when included before the next escalation.",
+			}, []string{"service", "name", "multiple_num", "outcome"}),
+			escalateMultipleNum: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_escalate_multiple_num",
+				Help: "The current numerator of the escalate multiple.",
+			}, []string{"service", "name"}),
+			webhookDroppedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_webhook_dropped_total",
+				Help: "The total number of webhook events dropped because the delivery queue was full.",
+			}, []string{"service", "name"}),
+			webhookFailedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_webhook_failed_total",
+				Help: "The total number of webhook events which failed to be delivered after retries.",
+			}, []string{"service", "name"}),
+			inFlightValue: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_in_flight_value",
+				Help: "The value in wei transferred by the transactions not confirmed yet.",
+			}, []string{"service", "name"}),
+			valueBudgetExceededTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_value_budget_exceeded_total",
+				Help: "The total number of value transfers rejected because the balance left above the minimum balance is too low.",
+			}, []string{"service", "name"}),
+			cancelTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_cancel_transaction_total",
+				Help: "The total number of cancellations of expired transactions.",
+			}, []string{"service", "name"}),
+			cancelTransactionFailedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_cancel_transaction_failed_total",
+				Help: "The total number of failed cancellations of expired transactions.",
+			}, []string{"service", "name"}),
+			queuedTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_queued_transaction_total",
+				Help: "The total number of transactions queued because max pending transactions or the blob budget was reached.",
+			}, []string{"service", "name"}),
+			queuedTransactionRetryTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_queued_transaction_retry_total",
+				Help: "The total number of queued transactions kept in the queue to be sent again after failing to be dispatched.",
+			}, []string{"service", "name"}),
+			blobBudgetQueuedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_blob_budget_queued_total",
+				Help: "The total number of blob transactions queued because their blobs exceeded the blob budget.",
+			}, []string{"service", "name"}),
+			blobSlotsInUse: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_blob_slots_in_use",
+				Help: "The number of blobs carried by the in-flight blob transactions.",
+			}, []string{"service", "name"}),
+			blobSlotsLimit: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_blob_slots_limit",
+				Help: "The maximum number of blobs carried by the in-flight blob transactions.",
+			}, []string{"service", "name"}),
+			aggregatedCallTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_aggregated_call_total",
+				Help: "The total number of calls sent aggregated into Multicall3 transactions.",
+			}, []string{"service", "name"}),
+			deferredTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_deferred_transaction_total",
+				Help: "The total number of transactions deferred because the base fee was above the ceiling.",
+			}, []string{"service", "name"}),
+			deferredMaxDelayTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_deferred_transaction_max_delay_total",
+				Help: "The total number of deferred transactions released because their max delay elapsed.",
+			}, []string{"service", "name"}),
+			deferredSavingsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_deferred_base_fee_savings_total",
+				Help: "The total base fee per gas in wei saved by deferring transactions.",
+			}, []string{"service", "name"}),
+			dependentQueueDepth: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_dependent_queue_depth",
+				Help: "The number of transactions held until their dependency is pending.",
+			}, []string{"service", "name"}),
+			deferredQueueDepth: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_deferred_queue_depth",
+				Help: "The number of transactions held by the fee scheduler.",
+			}, []string{"service", "name"}),
+			deferredProjectedSavings: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_deferred_projected_base_fee_savings",
+				Help: "The base fee per gas in wei saved if all held transactions are released at the ceiling.",
+			}, []string{"service", "name"}),
+			queueDepth: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_queue_depth",
+				Help: "The number of transactions waiting in the sender queue.",
+			}, []string{"service", "name"}),
+			accountBalance: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_account_balance",
+				Help: "The balance of the sender account in wei.",
+			}, []string{"service", "name"}),
+			pausedLowBalance: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_sender_paused_low_balance",
+				Help: "Whether the sender is paused because of low account balance.",
+			}, []string{"service", "name"}),
 			currentGasFeeCap: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
 				Name: "rollup_sender_gas_fee_cap",
 				Help: "The gas fee cap of current transaction.",
@@ -68,6 +216,32 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_check_pending_transaction_total",
 				Help: "The total number of check pending transaction.",
 			}, []string{"service", "name"}),
+			sentTransactionsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_sent_transactions_total",
+				Help: "The total number of new transactions sent, replacements excluded.",
+			}, []string{"service", "name", "sender_type"}),
+			replacedTransactionsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_replaced_transactions_total",
+				Help: "The total number of transactions replaced by an escalated resubmission.",
+			}, []string{"service", "name", "sender_type"}),
+			gasUsedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_gas_used_total",
+				Help: "The total gas used by confirmed transactions.",
+			}, []string{"service", "name", "sender_type"}),
+			feeSpentGweiTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_fee_spent_gwei_total",
+				Help: "The total execution fee in gwei paid by confirmed transactions, blob fees excluded.",
+			}, []string{"service", "name", "sender_type"}),
+			blocksToInclusion: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "rollup_sender_blocks_to_inclusion",
+				Help:    "The number of blocks between the first submission of a transaction and its inclusion.",
+				Buckets: []float64{1, 2, 3, 5, 10, 20, 50, 100, 200, 500},
+			}, []string{"service", "name", "sender_type"}),
+			escalationsPerTransaction: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "rollup_sender_escalations_per_transaction",
+				Help:    "The number of escalations a transaction went through before its inclusion.",
+				Buckets: []float64{0, 1, 2, 3, 5, 8, 13, 21},
+			}, []string{"service", "name", "sender_type"}),
 		}
 	})
 
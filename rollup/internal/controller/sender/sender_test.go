@@ -1,6 +1,9 @@
+//go:build !sqlite
+
 package sender
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"errors"
@@ -8,18 +11,27 @@ import (
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/agiledragon/gomonkey/v2"
+	"github.com/holiman/uint256"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/chaos"
 	"scroll-tech/common/database"
 	"scroll-tech/common/docker"
 	"scroll-tech/common/types"
@@ -103,10 +115,29 @@ func TestSender(t *testing.T) {
 	t.Run("test resubmit non-zero gas price transaction", testResubmitNonZeroGasPriceTransaction)
 	t.Run("test resubmit under priced transaction", testResubmitUnderpricedTransaction)
 	t.Run("test resubmit transaction with rising base fee", testResubmitTransactionWithRisingBaseFee)
+	t.Run("test resubmit blob transaction with rising base fee", testResubmitBlobTransactionWithRisingBaseFee)
 	t.Run("test check pending transaction tx confirmed", testCheckPendingTransactionTxConfirmed)
 	t.Run("test check pending transaction resubmit tx confirmed", testCheckPendingTransactionResubmitTxConfirmed)
 	t.Run("test check pending transaction replaced tx confirmed", testCheckPendingTransactionReplacedTxConfirmed)
 	t.Run("test check pending transaction multiple times with only one transaction pending", testCheckPendingTransactionTxMultipleTimesWithOnlyOneTxPending)
+	t.Run("test simulate before send", testSimulateBeforeSend)
+	t.Run("test check pending transaction inclusion block reorged", testCheckPendingTransactionInclusionBlockReorged)
+	t.Run("test check confirmed transaction inclusion block reorged", testCheckConfirmedTransactionInclusionBlockReorged)
+	t.Run("test max pending transactions queue", testMaxPendingTransactionsQueue)
+	t.Run("test queued transaction retry", testQueuedTransactionRetry)
+	t.Run("test blob budget", testBlobBudget)
+	t.Run("test low balance pause and resume", testLowBalancePauseAndResume)
+	t.Run("test transaction deadline expired", testTransactionDeadlineExpired)
+	t.Run("test defer transaction on high base fee", testDeferTransactionOnHighBaseFee)
+	t.Run("test dry run and replay", testDryRunAndReplay)
+	t.Run("test admin pause and force escalation", testAdminPauseAndForceEscalation)
+	t.Run("test confirmations override", testConfirmationsOverride)
+	t.Run("test stuck transaction circuit breaker", testStuckTransactionCircuitBreaker)
+	t.Run("test rebroadcast pending transactions on startup", testRebroadcastPendingTransactionsOnStartup)
+	t.Run("test reserve nonces", testReserveNonces)
+	t.Run("test depends on", testDependsOn)
+	t.Run("test value budget", testValueBudget)
+	t.Run("test recovery from node faults", testRecoveryFromNodeFaults)
 }
 
 func testNewSender(t *testing.T) {
@@ -184,7 +215,7 @@ func testFallbackGasLimit(t *testing.T) {
 
 		// FallbackGasLimit = 100000
 		patchGuard := gomonkey.ApplyPrivateMethod(s, "estimateGasLimit",
-			func(contract *common.Address, data []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int) (uint64, *gethTypes.AccessList, error) {
+			func(contract *common.Address, data []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int, useAccessList bool, overrides StateOverrides) (uint64, *gethTypes.AccessList, error) {
 				return 0, nil, errors.New("estimateGasLimit error")
 			},
 		)
@@ -220,7 +251,7 @@ func testResubmitZeroGasPriceTransaction(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, tx)
 		// Increase at least 1 wei in gas price, gas tip cap and gas fee cap.
-		_, err = s.resubmitTransaction(tx, 0)
+		_, err = s.resubmitTransaction(tx, 0, 0)
 		assert.NoError(t, err)
 		s.Stop()
 	}
@@ -243,12 +274,40 @@ func testAccessListTransactionGasLimit(t *testing.T) {
 		data, err := l2GasOracleABI.Pack("setL2BaseFee", big.NewInt(2333))
 		assert.NoError(t, err)
 
-		gasLimit, accessList, err := s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), true)
+		gasLimit, accessList, err := s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), true, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(43472), gasLimit)
 		assert.NotNil(t, accessList)
 
-		gasLimit, accessList, err = s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), false)
+		gasLimit, accessList, err = s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), false, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(43949), gasLimit)
+		assert.Nil(t, accessList)
+
+		// state overrides skip the access list generation.
+		overrides := StateOverrides{s.auth.From: {Balance: (*hexutil.Big)(big.NewInt(1e18))}}
+		gasLimit, accessList, err = s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), true, overrides)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(43949), gasLimit)
+		assert.Nil(t, accessList)
+
+		// cached access lists are reused without eth_createAccessList.
+		s.accessListCache = newAccessListCache(time.Minute)
+		gasLimit, accessList, err = s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), true, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(43472), gasLimit)
+		patchGuard := gomonkey.ApplyMethodFunc(s.gethClient, "CreateAccessList", func(_ context.Context, _ ethereum.CallMsg) (*gethTypes.AccessList, uint64, string, error) {
+			return nil, 0, "", errors.New("unexpected access list creation")
+		})
+		cachedGasLimit, cachedAccessList, err := s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), true, nil)
+		patchGuard.Reset()
+		assert.NoError(t, err)
+		assert.Equal(t, gasLimit, cachedGasLimit)
+		assert.Equal(t, accessList, cachedAccessList)
+
+		// access lists are not generated for disabled contracts.
+		s.config().AccessList = &config.AccessListConfig{DisabledContracts: []common.Address{mockL1ContractsAddress}}
+		gasLimit, accessList, err = s.estimateGasLimit(&mockL1ContractsAddress, data, big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(100000000000), big.NewInt(0), true, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(43949), gasLimit)
 		assert.Nil(t, accessList)
@@ -279,7 +338,7 @@ func testResubmitNonZeroGasPriceTransaction(t *testing.T) {
 		tx, err := s.createAndSendTx(feeData, &common.Address{}, big.NewInt(0), nil, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tx)
-		_, err = s.resubmitTransaction(tx, 0)
+		_, err = s.resubmitTransaction(tx, 0, 0)
 		assert.NoError(t, err)
 		s.Stop()
 	}
@@ -307,7 +366,7 @@ func testResubmitUnderpricedTransaction(t *testing.T) {
 		tx, err := s.createAndSendTx(feeData, &common.Address{}, big.NewInt(0), nil, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tx)
-		_, err = s.resubmitTransaction(tx, 0)
+		_, err = s.resubmitTransaction(tx, 0, 0)
 		assert.Error(t, err, "replacement transaction underpriced")
 		s.Stop()
 	}
@@ -329,12 +388,12 @@ func testResubmitTransactionWithRisingBaseFee(t *testing.T) {
 	// bump the basefee by 10x
 	baseFeePerGas *= 10
 	// resubmit and check that the gas fee has been adjusted accordingly
-	newTx, err := s.resubmitTransaction(tx, baseFeePerGas)
+	newTx, err := s.resubmitTransaction(tx, baseFeePerGas, 0)
 	assert.NoError(t, err)
 
-	escalateMultipleNum := new(big.Int).SetUint64(s.config.EscalateMultipleNum)
-	escalateMultipleDen := new(big.Int).SetUint64(s.config.EscalateMultipleDen)
-	maxGasPrice := new(big.Int).SetUint64(s.config.MaxGasPrice)
+	escalateMultipleNum := new(big.Int).SetUint64(s.config().EscalateMultipleNum)
+	escalateMultipleDen := new(big.Int).SetUint64(s.config().EscalateMultipleDen)
+	maxGasPrice := new(big.Int).SetUint64(s.config().MaxGasPrice)
 
 	adjBaseFee := new(big.Int)
 	adjBaseFee.SetUint64(baseFeePerGas)
@@ -350,6 +409,57 @@ func testResubmitTransactionWithRisingBaseFee(t *testing.T) {
 	s.Stop()
 }
 
+func testResubmitBlobTransactionWithRisingBaseFee(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.MaxBlobGasPrice = 10000
+
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+	assert.NoError(t, err)
+	tx := gethTypes.NewTx(&gethTypes.BlobTx{
+		Nonce:      s.auth.Nonce.Uint64(),
+		GasTipCap:  uint256.NewInt(100),
+		GasFeeCap:  uint256.NewInt(1000),
+		Gas:        21000,
+		BlobFeeCap: uint256.NewInt(1000),
+	})
+	baseFeePerGas := uint64(1000)
+	// bump the basefee by 10x
+	baseFeePerGas *= 10
+	// escalate and check that the fees have been adjusted accordingly
+	feeData := s.escalateFeeData(tx, baseFeePerGas)
+
+	escalateMultipleNum := new(big.Int).SetUint64(s.config().EscalateMultipleNum)
+	escalateMultipleDen := new(big.Int).SetUint64(s.config().EscalateMultipleDen)
+	maxGasPrice := new(big.Int).SetUint64(s.config().MaxGasPrice)
+
+	// the blob pool requires a 100% bump of every fee cap.
+	expectedGasTipCap := big.NewInt(200)
+	assert.Equal(t, expectedGasTipCap.Int64(), feeData.gasTipCap.Int64())
+
+	adjBaseFee := new(big.Int)
+	adjBaseFee.SetUint64(baseFeePerGas)
+	adjBaseFee = adjBaseFee.Mul(adjBaseFee, escalateMultipleNum)
+	adjBaseFee = adjBaseFee.Div(adjBaseFee, escalateMultipleDen)
+
+	expectedGasFeeCap := new(big.Int).Add(expectedGasTipCap, adjBaseFee)
+	if expectedGasFeeCap.Cmp(maxGasPrice) > 0 {
+		expectedGasFeeCap = maxGasPrice
+	}
+	assert.Equal(t, expectedGasFeeCap.Int64(), feeData.gasFeeCap.Int64())
+	assert.Equal(t, int64(2000), feeData.blobGasFeeCap.Int64())
+
+	// the blob gas fee cap never exceeds MaxBlobGasPrice.
+	s.config().MaxBlobGasPrice = 1500
+	feeData = s.escalateFeeData(tx, baseFeePerGas)
+	assert.Equal(t, int64(1500), feeData.blobGasFeeCap.Int64())
+	s.Stop()
+}
+
 func testCheckPendingTransactionTxConfirmed(t *testing.T) {
 	for _, txType := range txTypes {
 		sqlDB, err := db.DB()
@@ -370,8 +480,9 @@ func testCheckPendingTransactionTxConfirmed(t *testing.T) {
 		assert.Equal(t, types.TxStatusPending, txs[0].Status)
 		assert.Equal(t, types.SenderTypeCommitBatch, txs[0].SenderType)
 
+		genesisHash := getGenesisHash(t, s)
 		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
-			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), Status: gethTypes.ReceiptStatusSuccessful}, nil
+			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: genesisHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
 		})
 
 		s.checkPendingTransaction()
@@ -407,11 +518,12 @@ func testCheckPendingTransactionResubmitTxConfirmed(t *testing.T) {
 		assert.Equal(t, types.TxStatusPending, txs[0].Status)
 		assert.Equal(t, types.SenderTypeFinalizeBatch, txs[0].SenderType)
 
+		genesisHash := getGenesisHash(t, s)
 		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
 			if hash == originTxHash {
 				return nil, fmt.Errorf("simulated transaction receipt error")
 			}
-			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), Status: gethTypes.ReceiptStatusSuccessful}, nil
+			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: genesisHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
 		})
 
 		// Attempt to resubmit the transaction.
@@ -462,6 +574,7 @@ func testCheckPendingTransactionReplacedTxConfirmed(t *testing.T) {
 		assert.Equal(t, types.TxStatusPending, txs[0].Status)
 		assert.Equal(t, types.SenderTypeL1GasOracle, txs[0].SenderType)
 
+		genesisHash := getGenesisHash(t, s)
 		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
 			var status types.TxStatus
 			status, err = s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), hash)
@@ -473,6 +586,7 @@ func testCheckPendingTransactionReplacedTxConfirmed(t *testing.T) {
 				return &gethTypes.Receipt{
 					TxHash:      hash,
 					BlockNumber: big.NewInt(0),
+					BlockHash:   genesisHash,
 					Status:      gethTypes.ReceiptStatusSuccessful,
 				}, nil
 			}
@@ -548,3 +662,896 @@ func testCheckPendingTransactionTxMultipleTimesWithOnlyOneTxPending(t *testing.T
 		patchGuard.Reset()
 	}
 }
+
+func getGenesisHash(t *testing.T, s *Sender) common.Hash {
+	genesis, err := s.client.HeaderByNumber(context.Background(), big.NewInt(0))
+	assert.NoError(t, err)
+	return genesis.Hash()
+}
+
+func testSimulateBeforeSend(t *testing.T) {
+	for _, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		cfgCopy.SimulateBeforeSend = true
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+
+		// simulation succeeds, transaction is sent.
+		_, err = s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+		assert.NoError(t, err)
+
+		stringType, err := abi.NewType("string", "", nil)
+		assert.NoError(t, err)
+		packed, err := (abi.Arguments{{Type: stringType}}).Pack("caller not sequencer")
+		assert.NoError(t, err)
+		revertData := append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...)
+
+		patchGuard := gomonkey.ApplyMethodFunc(s.client, "PendingCallContract", func(_ context.Context, _ ethereum.CallMsg) ([]byte, error) {
+			return nil, &revertRPCError{data: hexutil.Encode(revertData)}
+		})
+
+		nonce := s.auth.Nonce.Uint64()
+		_, err = s.SendTransaction("test-revert", &common.Address{}, big.NewInt(0), nil, 0)
+		var simErr *SimulationError
+		assert.True(t, errors.As(err, &simErr))
+		assert.Equal(t, "caller not sequencer", simErr.Reason)
+		assert.Equal(t, revertData, simErr.Data)
+		assert.Equal(t, nonce, s.auth.Nonce.Uint64())
+
+		txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 100)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+
+		s.Stop()
+		patchGuard.Reset()
+	}
+}
+
+func testCheckPendingTransactionInclusionBlockReorged(t *testing.T) {
+	for _, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+
+		_, err = s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+		assert.NoError(t, err)
+
+		// The receipt points to a block which is not canonical.
+		reorgedBlockHash := common.HexToHash("0x1")
+		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: reorgedBlockHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
+		})
+
+		s.checkPendingTransaction()
+
+		txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 1)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, types.TxStatusPending, txs[0].Status)
+		assert.Equal(t, uint64(0), txs[0].InclusionBlockNumber)
+		assert.Equal(t, reorgedBlockHash.String(), txs[0].InclusionBlockHash)
+		patchGuard.Reset()
+
+		// The receipt disappeared after the reorg.
+		patchGuard = gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+			return nil, ethereum.NotFound
+		})
+
+		s.checkPendingTransaction()
+
+		txs, err = s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 1)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, types.TxStatusPending, txs[0].Status)
+		assert.Equal(t, "", txs[0].InclusionBlockHash)
+
+		s.Stop()
+		patchGuard.Reset()
+	}
+}
+
+func testCheckConfirmedTransactionInclusionBlockReorged(t *testing.T) {
+	for _, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		cfgCopy.ReorgCheckBlocks = 100
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+
+		txHash, err := s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+		assert.NoError(t, err)
+
+		genesisHash := getGenesisHash(t, s)
+		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: genesisHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
+		})
+
+		s.checkPendingTransaction()
+
+		status, err := s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, types.TxStatusConfirmed, status)
+
+		// Inclusion block is still canonical, nothing changes.
+		s.checkConfirmedTransactions()
+		status, err = s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, types.TxStatusConfirmed, status)
+		patchGuard.Reset()
+
+		// Simulate the inclusion block being reorged out.
+		assert.NoError(t, s.pendingTransactionOrm.UpdateInclusionBlockByTxHash(context.Background(), txHash, 1, common.HexToHash("0x1")))
+		patchGuard = gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+			return nil, ethereum.NotFound
+		})
+
+		s.checkConfirmedTransactions()
+
+		txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 1)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, types.TxStatusPending, txs[0].Status)
+		assert.Equal(t, uint64(0), txs[0].InclusionBlockNumber)
+		assert.Equal(t, "", txs[0].InclusionBlockHash)
+
+		s.Stop()
+		patchGuard.Reset()
+	}
+}
+
+func testMaxPendingTransactionsQueue(t *testing.T) {
+	for _, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		cfgCopy.MaxPendingTxs = 1
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+
+		txHash, err := s.SendTransaction("test-0", &common.Address{}, big.NewInt(0), nil, 0)
+		assert.NoError(t, err)
+		assert.NotEqual(t, common.Hash{}, txHash)
+
+		for i := 1; i <= 2; i++ {
+			txHash, err = s.SendTransaction(fmt.Sprintf("test-%d", i), &common.Address{}, big.NewInt(0), nil, 0)
+			assert.ErrorIs(t, err, ErrQueued)
+			assert.Equal(t, common.Hash{}, txHash)
+		}
+		assert.Equal(t, 2, s.QueueLength())
+
+		// Nothing is dispatched while the first transaction is in flight.
+		s.dispatchQueuedTransactions()
+		assert.Equal(t, 2, s.QueueLength())
+
+		genesisHash := getGenesisHash(t, s)
+		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: genesisHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
+		})
+		s.checkPendingTransaction()
+		patchGuard.Reset()
+
+		s.dispatchQueuedTransactions()
+		assert.Equal(t, 1, s.QueueLength())
+
+		txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 100)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, "test-1", txs[0].ContextID)
+
+		drained := s.Drain()
+		assert.Len(t, drained, 1)
+		assert.Equal(t, "test-2", drained[0].ContextID)
+		assert.Equal(t, 0, s.QueueLength())
+
+		s.Stop()
+	}
+}
+
+func testQueuedTransactionRetry(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.MaxPendingTxs = 1
+	cfgCopy.SimulateBeforeSend = true
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	_, err = s.SendTransaction("test-0", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	_, err = s.SendTransaction("test-1", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrQueued)
+
+	genesisHash := getGenesisHash(t, s)
+	receiptGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+		return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: genesisHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
+	})
+	s.checkPendingTransaction()
+	receiptGuard.Reset()
+
+	// a transient failure keeps the transaction at the head of the queue until its backoff elapses.
+	callGuard := gomonkey.ApplyMethodFunc(s.client, "PendingCallContract", func(_ context.Context, _ ethereum.CallMsg) ([]byte, error) {
+		return nil, errors.New("connection refused")
+	})
+	s.dispatchQueuedTransactions()
+	assert.Equal(t, 1, s.QueueLength())
+	head := s.peekQueuedTransaction()
+	assert.Equal(t, 1, head.attempts)
+	assert.True(t, head.retryAt.After(time.Now()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.queuedTransactionRetryTotal.WithLabelValues(s.service, s.name)))
+	s.dispatchQueuedTransactions()
+	assert.Equal(t, 1, head.attempts)
+	callGuard.Reset()
+
+	// a revert is terminal, the transaction is dropped and reported as failed.
+	stringType, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	packed, err := (abi.Arguments{{Type: stringType}}).Pack("caller not sequencer")
+	assert.NoError(t, err)
+	callGuard = gomonkey.ApplyMethodFunc(s.client, "PendingCallContract", func(_ context.Context, _ ethereum.CallMsg) ([]byte, error) {
+		return nil, &revertRPCError{data: hexutil.Encode(append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...))}
+	})
+	head.retryAt = time.Time{}
+	s.dispatchQueuedTransactions()
+	callGuard.Reset()
+	assert.Equal(t, 0, s.QueueLength())
+
+	// skip the confirmation of test-0.
+	cfm := <-s.ConfirmChan()
+	if cfm.ContextID == "test-0" {
+		cfm = <-s.ConfirmChan()
+	}
+	assert.Equal(t, "test-1", cfm.ContextID)
+	assert.False(t, cfm.IsSuccessful)
+	assert.Equal(t, types.TxStatusConfirmedFailed, cfm.TxStatus)
+	assert.Equal(t, "caller not sequencer", cfm.RevertReason)
+
+	s.Stop()
+}
+
+func testBlobBudget(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.BlobBudget = &config.BlobBudgetConfig{MaxPendingBlobs: 3, MaxBlobsPerBlock: 2}
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	// nothing is held while no blob is in flight, even beyond the budget.
+	exceeded, err := s.isBlobBudgetExceeded(4)
+	assert.NoError(t, err)
+	assert.False(t, exceeded)
+
+	// a replacement shares the blob slots of the replaced transaction.
+	nonce := s.auth.Nonce.Uint64()
+	blobHashes := []common.Hash{{0x01}, {0x02}}
+	for i, gasTipCap := range []uint64{100, 200} {
+		tx := gethTypes.NewTx(&gethTypes.BlobTx{Nonce: nonce, GasTipCap: uint256.NewInt(gasTipCap), GasFeeCap: uint256.NewInt(1000), Gas: 21000, BlobFeeCap: uint256.NewInt(1000), BlobHashes: blobHashes})
+		err = s.pendingTransactionOrm.InsertPendingTransaction(context.Background(), fmt.Sprintf("blob-%d", i), s.getSenderMeta(), tx, 0, nil, nil, uint64(i), 0, 0, 0, nil)
+		assert.NoError(t, err)
+	}
+	pendingBlobs, err := s.pendingBlobCount()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), pendingBlobs)
+	assert.Equal(t, float64(2), testutil.ToFloat64(s.metrics.blobSlotsInUse.WithLabelValues(s.service, s.name)))
+
+	exceeded, err = s.isBlobBudgetExceeded(1)
+	assert.NoError(t, err)
+	assert.False(t, exceeded)
+	exceeded, err = s.isBlobBudgetExceeded(2)
+	assert.NoError(t, err)
+	assert.True(t, exceeded)
+	// transactions without blobs are not held.
+	exceeded, err = s.isBlobBudgetExceeded(0)
+	assert.NoError(t, err)
+	assert.False(t, exceeded)
+
+	// the blobs sent in the current block are paced by MaxBlobsPerBlock.
+	blockNumber, err := s.client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	s.recordSentBlobs(blockNumber, 2)
+	exceeded, err = s.isBlobBudgetExceeded(1)
+	assert.NoError(t, err)
+	assert.True(t, exceeded)
+	s.recordSentBlobs(blockNumber+1, 0)
+	exceeded, err = s.isBlobBudgetExceeded(1)
+	assert.NoError(t, err)
+	assert.False(t, exceeded)
+
+	// a blob transaction beyond the budget is queued.
+	sidecar := &gethTypes.BlobTxSidecar{Blobs: make([]kzg4844.Blob, 2)}
+	txHash, err := s.SendTransaction("blob-2", &common.Address{}, big.NewInt(0), nil, 21000, WithBlobSidecar(sidecar))
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Equal(t, common.Hash{}, txHash)
+	assert.Equal(t, 1, s.QueueLength())
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.blobBudgetQueuedTotal.WithLabelValues(s.service, s.name)))
+
+	// and kept there while the blobs in flight are pending.
+	s.dispatchQueuedTransactions()
+	assert.Equal(t, 1, s.QueueLength())
+	assert.Len(t, s.Drain(), 1)
+
+	s.Stop()
+}
+
+func testLowBalancePauseAndResume(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.MinBalance = new(big.Int).Lsh(big.NewInt(1), 255)
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	s.checkBalance()
+	assert.True(t, s.IsPaused())
+
+	cfm := <-s.ConfirmChan()
+	assert.Equal(t, SenderPausedLowBalance, cfm.SenderStatus)
+	assert.Equal(t, types.SenderTypeCommitBatch, cfm.SenderType)
+
+	_, err = s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrSenderPausedLowBalance)
+
+	s.Stop()
+
+	// resume once the balance is back above the threshold.
+	s.config().MinBalance = big.NewInt(0)
+	s.checkBalance()
+	assert.False(t, s.IsPaused())
+
+	cfm = <-s.ConfirmChan()
+	assert.Equal(t, SenderResumed, cfm.SenderStatus)
+
+	_, err = s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+}
+
+func testTransactionDeadlineExpired(t *testing.T) {
+	for _, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		cfgCopy.EscalateBlocks = 100
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+
+		originTxHash, err := s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0, WithDeadline(time.Now().Add(-time.Minute)))
+		assert.NoError(t, err)
+
+		txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 1)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.NotNil(t, txs[0].Deadline)
+
+		genesisHash := getGenesisHash(t, s)
+		patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+			if hash == originTxHash {
+				return nil, fmt.Errorf("simulated transaction receipt error")
+			}
+			return &gethTypes.Receipt{TxHash: hash, BlockNumber: big.NewInt(0), BlockHash: genesisHash, Status: gethTypes.ReceiptStatusSuccessful}, nil
+		})
+
+		// The deadline has passed, the transaction is replaced by a cancellation even though EscalateBlocks is not reached.
+		s.checkPendingTransaction()
+
+		txs, err = s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 2)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 2)
+		assert.Equal(t, types.TxStatusReplaced, txs[0].Status)
+		assert.Equal(t, types.TxStatusPending, txs[1].Status)
+		assert.Equal(t, txs[0].Nonce, txs[1].Nonce)
+		cancelTx := &gethTypes.Transaction{}
+		assert.NoError(t, cancelTx.DecodeRLP(rlp.NewStream(bytes.NewReader(txs[1].RLPEncoding), 0)))
+		assert.True(t, s.isCancellationTx(cancelTx))
+
+		s.checkPendingTransaction()
+
+		cfm := <-s.ConfirmChan()
+		assert.Equal(t, "test", cfm.ContextID)
+		assert.False(t, cfm.IsSuccessful)
+		assert.Equal(t, types.TxStatusExpired, cfm.TxStatus)
+
+		status, err := s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), cancelTx.Hash())
+		assert.NoError(t, err)
+		assert.Equal(t, types.TxStatusExpired, status)
+
+		s.Stop()
+		patchGuard.Reset()
+	}
+}
+
+func testDeferTransactionOnHighBaseFee(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.DeferBaseFeeCeiling = 100
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeL2GasOracle, db, nil)
+	assert.NoError(t, err)
+
+	baseFee := uint64(1000)
+	patchGuard := gomonkey.ApplyPrivateMethod(s, "getBlockNumberAndBaseFee", func(ctx context.Context) (uint64, uint64, error) {
+		return 0, baseFee, nil
+	})
+	defer patchGuard.Reset()
+
+	txHash, err := s.SendTransaction("test-0", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Equal(t, common.Hash{}, txHash)
+	assert.Equal(t, 1, s.DeferredLength())
+
+	// Held while the base fee stays above the ceiling.
+	s.releaseDeferredTransactions()
+	assert.Equal(t, 1, s.DeferredLength())
+
+	baseFee = 50
+	s.releaseDeferredTransactions()
+	assert.Equal(t, 0, s.DeferredLength())
+
+	txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 100)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, "test-0", txs[0].ContextID)
+
+	// Released regardless of the base fee once the max delay elapsed.
+	baseFee = 1000
+	s.config().MaxDeferSeconds = 1
+	_, err = s.SendTransaction("test-1", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Equal(t, 1, s.DeferredLength())
+	time.Sleep(time.Second)
+	s.releaseDeferredTransactions()
+	assert.Equal(t, 0, s.DeferredLength())
+
+	s.Stop()
+}
+
+func testDryRunAndReplay(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.DryRun = true
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	txHash, err := s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	s.Stop()
+
+	txs, err := s.pendingTransactionOrm.GetPendingTransactionsBySenderType(context.Background(), s.senderType, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, txHash.String(), txs[0].Hash)
+
+	_, _, err = s.client.TransactionByHash(context.Background(), txHash)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+
+	replayed, err := ReplayRecordedTransactions(context.Background(), s.client, db, s.senderType)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+
+	_, _, err = s.client.TransactionByHash(context.Background(), txHash)
+	assert.NoError(t, err)
+}
+
+func testAdminPauseAndForceEscalation(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.EscalateBlocks = 1000
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	s.Pause()
+	assert.True(t, s.IsPaused())
+	_, err = s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrSenderPaused)
+
+	s.Resume()
+	assert.False(t, s.IsPaused())
+	originTxHash, err := s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+
+	patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+		return nil, fmt.Errorf("simulated transaction receipt error")
+	})
+	defer patchGuard.Reset()
+
+	// not escalated before EscalateBlocks elapsed.
+	s.checkPendingTransaction()
+	status, err := s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), originTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusPending, status)
+
+	s.ForceEscalation("test")
+	s.checkPendingTransaction()
+	status, err = s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), originTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusReplaced, status)
+
+	txs, err := s.GetPendingTransactions(10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, types.TxStatusPending, txs[1].Status)
+
+	// the request is consumed by the escalation.
+	s.checkPendingTransaction()
+	txs, err = s.GetPendingTransactions(10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+
+	s.Stop()
+}
+
+func testConfirmationsOverride(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.Confirmations = rpc.BlockNumber(1000)
+	cfgCopy.EscalateBlocks = 100000
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeL2GasOracle, db, nil)
+	assert.NoError(t, err)
+
+	defaultTxHash, err := s.SendTransaction("test-default", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	latestTxHash, err := s.SendTransaction("test-latest", &common.Address{}, big.NewInt(0), nil, 0, WithConfirmations(rpc.LatestBlockNumber))
+	assert.NoError(t, err)
+
+	// both transactions are included at block 4500 while the chain head is at block 5000.
+	header := &gethTypes.Header{Number: big.NewInt(4500), BaseFee: big.NewInt(1), Difficulty: big.NewInt(0)}
+	patches := gomonkey.ApplyMethodFunc(s.client, "BlockNumber", func(_ context.Context) (uint64, error) {
+		return 5000, nil
+	})
+	patches.ApplyMethodFunc(s.client, "HeaderByNumber", func(_ context.Context, _ *big.Int) (*gethTypes.Header, error) {
+		return header, nil
+	})
+	patches.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+		return &gethTypes.Receipt{TxHash: hash, BlockNumber: header.Number, BlockHash: header.Hash(), Status: gethTypes.ReceiptStatusSuccessful}, nil
+	})
+	s.checkPendingTransaction()
+	patches.Reset()
+
+	// only the transaction confirmed at latest is confirmed, the default depth of 1000 blocks is not reached.
+	cfm := <-s.ConfirmChan()
+	assert.Equal(t, "test-latest", cfm.ContextID)
+	assert.True(t, cfm.IsSuccessful)
+
+	status, err := s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), latestTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusConfirmed, status)
+	status, err = s.pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), defaultTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusPending, status)
+
+	s.Stop()
+}
+
+func testStuckTransactionCircuitBreaker(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.EscalateBlocks = 0
+	cfgCopy.MaxReplacements = 2
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	_, err = s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+
+	patchGuard := gomonkey.ApplyMethodFunc(s.client, "TransactionReceipt", func(_ context.Context, hash common.Hash) (*gethTypes.Receipt, error) {
+		return nil, fmt.Errorf("simulated transaction receipt error")
+	})
+	defer patchGuard.Reset()
+
+	s.checkPendingTransaction()
+	s.checkPendingTransaction()
+
+	// the third check trips the circuit breaker instead of resubmitting.
+	s.checkPendingTransaction()
+	cfm := <-s.ConfirmChan()
+	assert.Equal(t, "test", cfm.ContextID)
+	assert.Equal(t, SenderTransactionStuck, cfm.SenderStatus)
+	assert.Equal(t, types.TxStatusStuck, cfm.TxStatus)
+
+	txs, err := s.GetPendingTransactions(100)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 3)
+	assert.Equal(t, types.TxStatusStuck, txs[2].Status)
+	assert.Equal(t, uint64(2), txs[2].Replacements)
+
+	// no further escalation until resumed.
+	s.checkPendingTransaction()
+	txs, err = s.GetPendingTransactions(100)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 3)
+
+	resumed, err := s.ResumeStuckTransaction("test")
+	assert.NoError(t, err)
+	assert.True(t, resumed)
+
+	s.checkPendingTransaction()
+	txs, err = s.GetPendingTransactions(100)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 4)
+	assert.Equal(t, types.TxStatusPending, txs[3].Status)
+	assert.Equal(t, uint64(1), txs[3].Replacements)
+
+	s.Stop()
+}
+
+func testRebroadcastPendingTransactionsOnStartup(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	// a dry-run sender persists the transaction without broadcasting it, as if the node dropped it.
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.DryRun = true
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+	txHash, err := s.SendTransaction("test", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	s.Stop()
+
+	_, _, err = s.client.TransactionByHash(context.Background(), txHash)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+
+	cfgCopy.DryRun = false
+	s, err = NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+
+	tx, _, err := s.client.TransactionByHash(context.Background(), txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Nonce()+1, s.auth.Nonce.Uint64())
+}
+
+func testReserveNonces(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+
+	_, err = s.ReserveNonces("commit", 0)
+	assert.Error(t, err)
+
+	startNonce := s.auth.Nonce.Uint64()
+	commitNonces, err := s.ReserveNonces("commit", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{startNonce, startNonce + 1}, commitNonces)
+	finalizeNonces, err := s.ReserveNonces("finalize", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{startNonce + 2}, finalizeNonces)
+
+	// reserving again for the same context returns the same nonces.
+	nonces, err := s.ReserveNonces("commit", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, commitNonces, nonces)
+	_, err = s.ReserveNonces("commit", 3)
+	assert.Error(t, err)
+
+	_, err = s.SendTransaction("finalize", &common.Address{}, big.NewInt(0), nil, 0, WithReservedNonce(commitNonces[0]))
+	assert.ErrorIs(t, err, ErrNonceNotReserved)
+
+	// reserved nonces are sent in any order.
+	for _, send := range []struct {
+		contextID string
+		nonce     uint64
+	}{{"finalize", finalizeNonces[0]}, {"commit", commitNonces[1]}, {"commit", commitNonces[0]}} {
+		txHash, err := s.SendTransaction(send.contextID, &common.Address{}, big.NewInt(0), nil, 0, WithReservedNonce(send.nonce))
+		assert.NoError(t, err)
+		tx, _, err := s.client.TransactionByHash(context.Background(), txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, send.nonce, tx.Nonce())
+	}
+
+	_, err = s.SendTransaction("finalize", &common.Address{}, big.NewInt(0), nil, 0, WithReservedNonce(finalizeNonces[0]))
+	assert.ErrorIs(t, err, ErrReservedNonceUsed)
+
+	// transactions without a reserved nonce take the nonces following the reserved ones.
+	txHash, err := s.SendTransaction("other", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	tx, _, err := s.client.TransactionByHash(context.Background(), txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, startNonce+3, tx.Nonce())
+	s.Stop()
+
+	// reservations survive a restart.
+	s, err = NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.Equal(t, startNonce+3, s.nextReservedNonce)
+	nonces, err = s.ReserveNonces("commit", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, commitNonces, nonces)
+}
+
+func testDependsOn(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.EscalateBlocks = 1000
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+
+	// the dependency has not been sent yet, the dependent transactions are held in order.
+	hash, err := s.SendTransaction("batch 2", &common.Address{}, big.NewInt(0), nil, 0, WithDependsOn("batch 1"))
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Equal(t, common.Hash{}, hash)
+	_, err = s.SendTransaction("batch 3", &common.Address{}, big.NewInt(0), nil, 0, WithDependsOn("batch 2"))
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Equal(t, 2, s.DependentLength())
+
+	s.releaseDependentTransactions()
+	assert.Equal(t, 2, s.DependentLength())
+
+	parentHash, err := s.SendTransaction("batch 1", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	parent, _, err := s.client.TransactionByHash(context.Background(), parentHash)
+	assert.NoError(t, err)
+
+	// each released transaction satisfies the dependency of the next one.
+	s.releaseDependentTransactions()
+	assert.Equal(t, 0, s.DependentLength())
+	for i, contextID := range []string{"batch 2", "batch 3"} {
+		txs, err := s.pendingTransactionOrm.GetTransactionsBySenderTypeAndContextID(context.Background(), types.SenderTypeCommitBatch, contextID)
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, parent.Nonce()+uint64(i)+1, txs[0].Nonce)
+	}
+
+	// a ready dependency does not hold the transaction.
+	hash, err = s.SendTransaction("batch 4", &common.Address{}, big.NewInt(0), nil, 0, WithDependsOn("batch 3"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, common.Hash{}, hash)
+
+	// a held transaction can be cancelled.
+	_, err = s.SendTransaction("batch 6", &common.Address{}, big.NewInt(0), nil, 0, WithDependsOn("batch 5"))
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.True(t, s.CancelTransaction("batch 6"))
+	assert.Equal(t, 0, s.DependentLength())
+}
+
+func testValueBudget(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.EscalateBlocks = 1000
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+
+	balance, err := s.client.BalanceAt(context.Background(), s.auth.From, nil)
+	assert.NoError(t, err)
+
+	// a value transfer with calldata is persisted with its value and accounted for until it confirms.
+	value := big.NewInt(1000)
+	_, err = s.SendTransaction("relay 1", &common.Address{}, value, []byte{0x01}, 0)
+	assert.NoError(t, err)
+	txs, err := s.pendingTransactionOrm.GetTransactionsBySenderTypeAndContextID(context.Background(), types.SenderTypeCommitBatch, "relay 1")
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, value.String(), txs[0].Value)
+
+	available, err := s.availableBalance()
+	assert.NoError(t, err)
+	assert.True(t, available.Cmp(new(big.Int).Sub(balance, value)) <= 0)
+
+	// a value exceeding the balance left above MinBalance is rejected.
+	s.config().MinBalance = new(big.Int).Sub(available, value)
+	_, err = s.SendTransaction("relay 2", &common.Address{}, new(big.Int).Add(value, big.NewInt(1)), nil, 0)
+	assert.ErrorIs(t, err, ErrValueExceedsBudget)
+
+	_, err = s.SendTransaction("relay 3", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+}
+
+func testRecoveryFromNodeFaults(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proxy, err := chaos.NewProxy(base.L1gethImg.Endpoint())
+	assert.NoError(t, err)
+	defer proxy.Close()
+
+	cfgCopy := *cfg.L1Config.RelayerConfig.SenderConfig
+	cfgCopy.Endpoint = proxy.Endpoint()
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+	startNonce := s.auth.Nonce.Uint64()
+
+	l1Client, err := base.L1Client()
+	assert.NoError(t, err)
+	assertMinedNonce := func(txHash common.Hash, nonce uint64) {
+		tx, _, err := l1Client.TransactionByHash(context.Background(), txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, nonce, tx.Nonce())
+		_, err = bind.WaitMined(context.Background(), l1Client, tx)
+		assert.NoError(t, err)
+	}
+
+	// rejected transactions do not consume the nonce.
+	proxy.Inject(chaos.ReplacementUnderpriced(1), chaos.NonceTooLow(1))
+	_, err = s.SendTransaction("underpriced", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrUnderpriced)
+	_, err = s.SendTransaction("nonce too low", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrNonceTooLow)
+	assert.Equal(t, startNonce, s.auth.Nonce.Uint64())
+
+	// the sender fails while the node is down and recovers once it is back.
+	assert.NoError(t, proxy.Run(context.Background(), &chaos.Scenario{
+		Name: "outage",
+		Steps: []chaos.Step{{
+			Faults: []*chaos.Fault{chaos.DropCalls("", 0)},
+			Action: func() error {
+				_, err := s.SendTransaction("outage", &common.Address{}, big.NewInt(0), nil, 0)
+				assert.Error(t, err)
+				return nil
+			},
+		}, {Clear: true}},
+	}))
+	txHash, err := s.SendTransaction("outage", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	assertMinedNonce(txHash, startNonce)
+
+	// a stale nonce read on reset is corrected on the next rejection of the node, the previous nonce being mined.
+	proxy.Inject(chaos.NonceTooLow(1), chaos.StaleNonce(1))
+	_, err = s.SendTransaction("stale nonce", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrNonceTooLow)
+	assert.Equal(t, startNonce, s.auth.Nonce.Uint64())
+	proxy.Clear()
+	_, err = s.SendTransaction("stale nonce", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.ErrorIs(t, err, ErrNonceTooLow)
+	txHash, err = s.SendTransaction("stale nonce", &common.Address{}, big.NewInt(0), nil, 0)
+	assert.NoError(t, err)
+	assertMinedNonce(txHash, startNonce+1)
+}
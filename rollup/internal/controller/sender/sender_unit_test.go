@@ -0,0 +1,654 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// the tests of the sender without the l1geth and postgres containers, also run alone with `go test -tags sqlite`.
+
+func TestAccessListCache(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	_, ok := newAccessListCacheKey(&to, []byte{0x01})
+	assert.False(t, ok)
+	_, ok = newAccessListCacheKey(nil, []byte{0x01, 0x02, 0x03, 0x04})
+	assert.False(t, ok)
+
+	key, ok := newAccessListCacheKey(&to, []byte{0x01, 0x02, 0x03, 0x04, 0x05})
+	assert.True(t, ok)
+	otherKey, ok := newAccessListCacheKey(&to, []byte{0x01, 0x02, 0x03, 0x05})
+	assert.True(t, ok)
+
+	cache := newAccessListCache(time.Minute)
+	accessList := &gethTypes.AccessList{{Address: common.HexToAddress("0x2")}}
+	cache.set(key, accessList, -100)
+
+	entry, ok := cache.get(key)
+	assert.True(t, ok)
+	assert.Equal(t, accessList, entry.accessList)
+	assert.Equal(t, int64(-100), entry.gasDelta)
+	_, ok = cache.get(otherKey)
+	assert.False(t, ok)
+
+	expired := newAccessListCache(-time.Second)
+	expired.set(key, accessList, -100)
+	_, ok = expired.get(key)
+	assert.False(t, ok)
+}
+
+type revertRPCError struct {
+	data string
+}
+
+func (e *revertRPCError) Error() string { return "execution reverted" }
+
+func (e *revertRPCError) ErrorData() interface{} { return e.data }
+
+func TestUrgencyOf(t *testing.T) {
+	assert.Equal(t, UrgencyDeferrable, urgencyOf(types.SenderTypeL1GasOracle))
+	assert.Equal(t, UrgencyDeferrable, urgencyOf(types.SenderTypeL2GasOracle))
+	assert.Equal(t, UrgencyUrgent, urgencyOf(types.SenderTypeCommitBatch))
+	assert.Equal(t, UrgencyUrgent, urgencyOf(types.SenderTypeFinalizeBatch))
+}
+
+func TestSummarizeTrace(t *testing.T) {
+	reason, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	revertData, err := (abi.Arguments{{Type: reason}}).Pack("batch is already committed")
+	assert.NoError(t, err)
+	revertData = append(crypto.Keccak256([]byte("Error(string)"))[:4], revertData...)
+
+	to := common.HexToAddress("0x2")
+	root := &callFrame{
+		Type:  "CALL",
+		From:  common.HexToAddress("0x1"),
+		Error: "execution reverted",
+		Calls: []callFrame{
+			{Type: "STATICCALL", From: common.HexToAddress("0x1"), To: &to},
+			{Type: "DELEGATECALL", From: common.HexToAddress("0x1"), To: &to, Input: []byte{0x12, 0x34, 0x56, 0x78, 0x9a}, Output: revertData, Error: "execution reverted"},
+		},
+	}
+
+	summary := summarizeTrace(root)
+	assert.Equal(t, 1, summary.Depth)
+	assert.Equal(t, "DELEGATECALL", summary.Type)
+	assert.Equal(t, &to, summary.To)
+	assert.Equal(t, "batch is already committed", summary.RevertReason)
+	assert.Equal(t, hexutil.Bytes{0x12, 0x34, 0x56, 0x78}, summary.Selector)
+}
+
+func TestDecodeReceiptEvents(t *testing.T) {
+	batchHash := common.HexToHash("0x1234")
+	receipt := &gethTypes.Receipt{
+		Logs: []*gethTypes.Log{
+			{
+				Address: common.HexToAddress("0x1"),
+				Topics:  []common.Hash{bridgeAbi.L1CommitBatchEventSignature, common.BigToHash(big.NewInt(10)), batchHash},
+			},
+			{
+				Address: common.HexToAddress("0x2"),
+				Topics:  []common.Hash{common.HexToHash("0xdead")},
+			},
+		},
+	}
+
+	events := decodeReceiptEvents(receipt)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "CommitBatch", events[0].Name)
+	assert.Equal(t, common.HexToAddress("0x1"), events[0].Address)
+	assert.Equal(t, big.NewInt(10), events[0].Args["batchIndex"])
+	assert.Equal(t, [32]byte(batchHash), events[0].Args["batchHash"])
+}
+
+func TestEffectiveGasPrice(t *testing.T) {
+	tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(10)})
+
+	assert.Equal(t, big.NewInt(7), effectiveGasPrice(tx, &gethTypes.Receipt{EffectiveGasPrice: big.NewInt(7)}, nil))
+	assert.Equal(t, big.NewInt(5), effectiveGasPrice(tx, &gethTypes.Receipt{}, &gethTypes.Header{BaseFee: big.NewInt(3)}))
+	assert.Equal(t, big.NewInt(10), effectiveGasPrice(tx, &gethTypes.Receipt{}, &gethTypes.Header{BaseFee: big.NewInt(9)}))
+}
+
+type feeHistoryService struct {
+	rewards [][]*hexutil.Big
+}
+
+func (f *feeHistoryService) FeeHistory(blockCount hexutil.Uint64, lastBlock string, percentiles []float64) (*feeHistoryResult, error) {
+	gasUsedRatio := make([]float64, len(f.rewards))
+	for i := range gasUsedRatio {
+		gasUsedRatio[i] = 0.5
+	}
+	return &feeHistoryResult{OldestBlock: (*hexutil.Big)(big.NewInt(1)), Reward: f.rewards, GasUsedRatio: gasUsedRatio}, nil
+}
+
+func TestFeeHistoryEstimator(t *testing.T) {
+	service := &feeHistoryService{rewards: [][]*hexutil.Big{{(*hexutil.Big)(big.NewInt(100))}, {(*hexutil.Big)(big.NewInt(300))}}}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+	rpcClient := rpc.DialInProc(server)
+
+	_, err := newFeeEstimator(&config.FeeEstimatorConfig{Type: "unknown"}, rpcClient, ethclient.NewClient(rpcClient))
+	assert.Error(t, err)
+
+	estimator, err := newFeeEstimator(&config.FeeEstimatorConfig{Type: FeeHistoryFeeEstimatorType, EWMAAlpha: 0.5}, rpcClient, ethclient.NewClient(rpcClient))
+	assert.NoError(t, err)
+
+	// the first estimation is the plain average.
+	tip, err := estimator.SuggestGasTipCap(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), tip)
+
+	// a spike only moves the estimation by alpha of its size.
+	service.rewards = [][]*hexutil.Big{{(*hexutil.Big)(big.NewInt(1000))}, {(*hexutil.Big)(big.NewInt(1000))}}
+	tip, err = estimator.SuggestGasTipCap(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(600), tip)
+}
+
+type dynamicFeeService struct {
+	baseFee    *big.Int
+	feeHistory bool
+}
+
+func (d *dynamicFeeService) GetBlockByNumber(number string, fullTx bool) (*gethTypes.Header, error) {
+	return &gethTypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0), BaseFee: d.baseFee}, nil
+}
+
+func (d *dynamicFeeService) FeeHistory(blockCount hexutil.Uint64, lastBlock string, percentiles []float64) (*feeHistoryResult, error) {
+	if !d.feeHistory {
+		return nil, errors.New("the method eth_feeHistory does not exist/is not available")
+	}
+	return &feeHistoryResult{OldestBlock: (*hexutil.Big)(big.NewInt(100)), BaseFee: []*hexutil.Big{(*hexutil.Big)(d.baseFee)}, GasUsedRatio: []float64{0.5}}, nil
+}
+
+func TestResolveTxType(t *testing.T) {
+	for _, tc := range []struct {
+		service *dynamicFeeService
+		txType  string
+		want    string
+	}{
+		{&dynamicFeeService{baseFee: big.NewInt(1000), feeHistory: true}, DynamicFeeTxType, DynamicFeeTxType},
+		// the chains without base fee or fee history are sent legacy transactions.
+		{&dynamicFeeService{feeHistory: true}, DynamicFeeTxType, LegacyTxType},
+		{&dynamicFeeService{baseFee: big.NewInt(1000)}, DynamicFeeTxType, LegacyTxType},
+		// the other tx types are not detected.
+		{&dynamicFeeService{}, AccessListTxType, AccessListTxType},
+	} {
+		server := rpc.NewServer()
+		assert.NoError(t, server.RegisterName("eth", tc.service))
+		rpcClient := rpc.DialInProc(server)
+
+		txType, err := resolveTxType(context.Background(), rpcClient, ethclient.NewClient(rpcClient), tc.txType, "test", "resolve_tx_type")
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, txType)
+		server.Stop()
+	}
+}
+
+func TestTargetInclusionFees(t *testing.T) {
+	assert.Equal(t, float64(90), targetInclusionPercentile(1))
+	assert.Equal(t, float64(50), targetInclusionPercentile(5))
+	assert.Equal(t, float64(10), targetInclusionPercentile(20))
+
+	assert.Equal(t, big.NewInt(800), maxBaseFeeAfter(800, 0))
+	assert.Equal(t, big.NewInt(1012), maxBaseFeeAfter(800, 2))
+
+	assert.Equal(t, uint64(5), targetInclusionBlocksLeft(5, 10, 10))
+	assert.Equal(t, uint64(2), targetInclusionBlocksLeft(5, 10, 13))
+	assert.Equal(t, uint64(1), targetInclusionBlocksLeft(5, 10, 20))
+
+	service := &feeHistoryService{rewards: [][]*hexutil.Big{{(*hexutil.Big)(big.NewInt(100))}, {(*hexutil.Big)(big.NewInt(300))}}}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+
+	s := &Sender{ctx: context.Background(), rpcClient: rpc.DialInProc(server)}
+	s.cfg.Store(&config.SenderConfig{MaxGasPrice: 10000})
+
+	// tip is the average reward, the fee cap covers the base fee increase over the window.
+	feeData := &FeeData{}
+	assert.NoError(t, s.applyTargetInclusionFees(feeData, nil, 2, 800))
+	assert.Equal(t, big.NewInt(200), feeData.gasTipCap)
+	assert.Equal(t, big.NewInt(1212), feeData.gasFeeCap)
+
+	// a replacement bumps the original fees by at least txPriceBump.
+	original := gethTypes.NewTx(&gethTypes.DynamicFeeTx{GasTipCap: big.NewInt(1000), GasFeeCap: big.NewInt(2000)})
+	assert.NoError(t, s.applyTargetInclusionFees(feeData, original, 2, 800))
+	assert.Equal(t, big.NewInt(1100), feeData.gasTipCap)
+	assert.Equal(t, big.NewInt(2200), feeData.gasFeeCap)
+
+	// the fee cap is capped by MaxGasPrice.
+	s.config().MaxGasPrice = 1000
+	assert.NoError(t, s.applyTargetInclusionFees(feeData, nil, 2, 800))
+	assert.Equal(t, big.NewInt(200), feeData.gasTipCap)
+	assert.Equal(t, big.NewInt(1000), feeData.gasFeeCap)
+
+	service.rewards = nil
+	assert.Error(t, s.applyTargetInclusionFees(feeData, nil, 2, 800))
+}
+
+func TestEscalationTracker(t *testing.T) {
+	_, err := newEscalationTracker(&config.AdaptiveEscalationConfig{MinMultipleNum: 10, MaxMultipleNum: 15}, 11, 10)
+	assert.Error(t, err)
+	_, err = newEscalationTracker(&config.AdaptiveEscalationConfig{MinMultipleNum: 12, MaxMultipleNum: 11}, 11, 10)
+	assert.Error(t, err)
+
+	// a static multiple records statistics without adapting.
+	tracker, err := newEscalationTracker(nil, 11, 10)
+	assert.NoError(t, err)
+	tracker.replacementSent("a", common.HexToHash("0x1"), 11)
+	_, recorded, adjusted := tracker.replacementDone("a", common.HexToHash("0x1"), false)
+	assert.True(t, recorded)
+	assert.False(t, adjusted)
+	assert.Equal(t, uint64(11), tracker.multipleNum)
+
+	tracker, err = newEscalationTracker(&config.AdaptiveEscalationConfig{MinMultipleNum: 11, MaxMultipleNum: 12, Window: 2}, 11, 10)
+	assert.NoError(t, err)
+
+	// outcomes of an older replacement of the context are ignored.
+	tracker.replacementSent("a", common.HexToHash("0x1"), 11)
+	_, recorded, _ = tracker.replacementDone("a", common.HexToHash("0x2"), true)
+	assert.False(t, recorded)
+
+	// failed replacements raise the multiple up to its maximum.
+	for i := 0; i < 4; i++ {
+		tracker.replacementSent("a", common.HexToHash("0x1"), tracker.multipleNum)
+		tracker.replacementDone("a", common.HexToHash("0x1"), false)
+	}
+	assert.Equal(t, uint64(12), tracker.multipleNum)
+
+	// successful replacements lower the multiple down to its minimum.
+	for i := 0; i < 4; i++ {
+		tracker.replacementSent("a", common.HexToHash("0x1"), tracker.multipleNum)
+		tracker.replacementDone("a", common.HexToHash("0x1"), true)
+	}
+	assert.Equal(t, uint64(11), tracker.multipleNum)
+
+	assert.Equal(t, []ReplacementStat{{MultipleNum: 11, Attempts: 4, Successes: 2}, {MultipleNum: 12, Attempts: 4, Successes: 2}}, tracker.replacementStats())
+}
+
+func TestUpdateConfig(t *testing.T) {
+	tracker, err := newEscalationTracker(nil, 11, 10)
+	assert.NoError(t, err)
+	s := &Sender{service: "test", name: "update_config", escalation: tracker, metrics: initSenderMetrics(nil)}
+	s.cfg.Store(&config.SenderConfig{EscalateBlocks: 3, EscalateMultipleNum: 11, EscalateMultipleDen: 10, MaxGasPrice: 10000, MinGasPrice: 1000, TxType: LegacyTxType})
+
+	// the multiple must escalate and the cap must stay above the floors.
+	assert.Error(t, s.UpdateConfig(&config.SenderConfig{EscalateMultipleNum: 10, EscalateMultipleDen: 10}))
+	assert.Error(t, s.UpdateConfig(&config.SenderConfig{EscalateMultipleNum: 12, EscalateMultipleDen: 10, MaxGasPrice: 500}))
+	assert.Equal(t, uint64(10000), s.config().MaxGasPrice)
+
+	// only the tunable parameters are replaced.
+	assert.NoError(t, s.UpdateConfig(&config.SenderConfig{EscalateBlocks: 5, EscalateMultipleNum: 13, EscalateMultipleDen: 10, MaxGasPrice: 20000, TxType: DynamicFeeTxType}))
+	assert.Equal(t, uint64(5), s.config().EscalateBlocks)
+	assert.Equal(t, uint64(20000), s.config().MaxGasPrice)
+	assert.Equal(t, LegacyTxType, s.config().TxType)
+	num, den := s.EscalateMultiple()
+	assert.Equal(t, uint64(13), num)
+	assert.Equal(t, uint64(10), den)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	assert.Nil(t, newWebhookNotifier(nil))
+
+	var attempts int
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, signWebhookBody([]byte("secret"), body), r.Header.Get(webhookSignatureHeader))
+		// the first delivery attempt fails and is retried.
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received = body
+	}))
+	defer server.Close()
+
+	notifier := newWebhookNotifier(&config.WebhookConfig{URL: server.URL, Secret: "secret", RetryCount: 1})
+	replacedTxHash := common.HexToHash("0x1")
+	event := &WebhookEvent{Event: WebhookEventReplaced, ContextID: "test", TxHash: common.HexToHash("0x2"), Nonce: 3, ReplacedTxHash: &replacedTxHash}
+	assert.NoError(t, notifier.deliver(event))
+	assert.Equal(t, 2, attempts)
+	assert.JSONEq(t, `{"event":"replaced","service":"","name":"","sender_type":"","context_id":"test","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000002",`+
+		`"nonce":3,"replaced_tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000001","timestamp":0}`, string(received))
+
+	// a client error is not retried.
+	badRequest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer badRequest.Close()
+	attempts = 0
+	notifier = newWebhookNotifier(&config.WebhookConfig{URL: badRequest.URL, RetryCount: 1})
+	assert.Error(t, notifier.deliver(event))
+	assert.Equal(t, 1, attempts)
+}
+
+type receiptService struct {
+	receipts      map[common.Hash]*gethTypes.Receipt
+	blockReceipts map[common.Hash][]*gethTypes.Receipt
+	calls         int
+}
+
+func (r *receiptService) GetTransactionReceipt(hash common.Hash) (*gethTypes.Receipt, error) {
+	r.calls++
+	return r.receipts[hash], nil
+}
+
+func (r *receiptService) GetBlockReceipts(blockHash common.Hash) ([]*gethTypes.Receipt, error) {
+	r.calls++
+	receipts, ok := r.blockReceipts[blockHash]
+	if !ok {
+		return nil, errors.New("block not found")
+	}
+	return receipts, nil
+}
+
+func TestBatchTransactionReceipts(t *testing.T) {
+	newReceipt := func(txHash, blockHash common.Hash) *gethTypes.Receipt {
+		return &gethTypes.Receipt{Status: gethTypes.ReceiptStatusSuccessful, Logs: []*gethTypes.Log{}, TxHash: txHash, BlockHash: blockHash, BlockNumber: big.NewInt(1)}
+	}
+	block, reorgedBlock := common.HexToHash("0xb1"), common.HexToHash("0xb2")
+	included, pending, unknown, reorged := common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3"), common.HexToHash("0x4")
+	service := &receiptService{
+		receipts: map[common.Hash]*gethTypes.Receipt{
+			pending: newReceipt(pending, block),
+			reorged: newReceipt(reorged, block),
+		},
+		blockReceipts: map[common.Hash][]*gethTypes.Receipt{
+			block: {newReceipt(included, block)},
+		},
+	}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+
+	s := &Sender{ctx: context.Background(), rpcClient: rpc.DialInProc(server)}
+	s.cfg.Store(&config.SenderConfig{})
+	transactionsToCheck := []orm.PendingTransaction{
+		{Hash: included.String(), InclusionBlockHash: block.String()},
+		{Hash: pending.String()},
+		{Hash: unknown.String()},
+		{Hash: reorged.String(), InclusionBlockHash: reorgedBlock.String()},
+	}
+
+	// batching is disabled without ReceiptBatchSize.
+	assert.Nil(t, s.batchTransactionReceipts(transactionsToCheck))
+
+	s.config().ReceiptBatchSize = 2
+	s.config().UseBlockReceipts = true
+	receipts := s.batchTransactionReceipts(transactionsToCheck)
+	assert.Len(t, receipts, 4)
+	// two block receipts calls, then the three transactions not found in a known block.
+	assert.Equal(t, 5, service.calls)
+
+	receipt, err := s.transactionReceipt(receipts, included)
+	assert.NoError(t, err)
+	assert.Equal(t, included, receipt.TxHash)
+	receipt, err = s.transactionReceipt(receipts, pending)
+	assert.NoError(t, err)
+	assert.Equal(t, pending, receipt.TxHash)
+	_, err = s.transactionReceipt(receipts, unknown)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+	receipt, err = s.transactionReceipt(receipts, reorged)
+	assert.NoError(t, err)
+	assert.Equal(t, block, receipt.BlockHash)
+}
+
+func TestClassifyNodeError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected error
+	}{
+		{errors.New("nonce too low: next nonce 5, tx nonce 4"), ErrNonceTooLow},
+		{errors.New("replacement transaction underpriced"), ErrUnderpriced},
+		{errors.New("max fee per gas less than block base fee"), ErrUnderpriced},
+		{errors.New("insufficient funds for gas * price + value"), ErrInsufficientFunds},
+	}
+	for _, tc := range testCases {
+		err := classifyNodeError(tc.err)
+		assert.ErrorIs(t, err, tc.expected)
+		assert.ErrorIs(t, err, tc.err)
+		assert.Contains(t, err.Error(), tc.err.Error())
+	}
+
+	err := errors.New("connection refused")
+	assert.Equal(t, err, classifyNodeError(err))
+
+	estimationErr := newEstimationError(errors.New("insufficient funds for transfer"))
+	assert.ErrorIs(t, estimationErr, ErrEstimationFailed)
+	assert.ErrorIs(t, estimationErr, ErrInsufficientFunds)
+	assert.Empty(t, estimationErr.RevertReason)
+
+	stringType, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	packed, err := (abi.Arguments{{Type: stringType}}).Pack("batch is already committed")
+	assert.NoError(t, err)
+	estimationErr = newEstimationError(&revertRPCError{data: hexutil.Encode(append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...))})
+	wrapped := fmt.Errorf("failed to get fee data, err: %w", estimationErr)
+	var target *EstimationError
+	assert.True(t, errors.As(wrapped, &target))
+	assert.Equal(t, "batch is already committed", target.RevertReason)
+}
+
+func TestIsTerminalSendError(t *testing.T) {
+	stringType, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	packed, err := (abi.Arguments{{Type: stringType}}).Pack("batch is already committed")
+	assert.NoError(t, err)
+	revertErr := &revertRPCError{data: hexutil.Encode(append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...))}
+
+	simErr := fmt.Errorf("failed to send: %w", &SimulationError{Reason: "batch is already committed", Data: []byte{0x08}, Err: revertErr})
+	assert.True(t, isTerminalSendError(simErr))
+	assert.Equal(t, "batch is already committed", revertReasonFromError(simErr))
+	estimationErr := fmt.Errorf("failed to get fee data, err: %w", newEstimationError(revertErr))
+	assert.True(t, isTerminalSendError(estimationErr))
+	assert.Equal(t, "batch is already committed", revertReasonFromError(estimationErr))
+	assert.True(t, isTerminalSendError(newEstimationError(errors.New("execution reverted"))))
+	assert.True(t, isTerminalSendError(fmt.Errorf("%w, context ID: test", ErrNonceNotReserved)))
+
+	for _, err := range []error{
+		errors.New("connection refused"),
+		fmt.Errorf("failed to create and send transaction, err: %w", classifyNodeError(errors.New("nonce too low"))),
+		classifyNodeError(errors.New("replacement transaction underpriced")),
+		newEstimationError(errors.New("context deadline exceeded")),
+		&SimulationError{Err: errors.New("connection reset by peer")},
+	} {
+		assert.False(t, isTerminalSendError(err), err.Error())
+	}
+}
+
+func TestQueuedTxBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, queuedTxBackoff(1))
+	assert.Equal(t, 2*time.Second, queuedTxBackoff(2))
+	assert.Equal(t, 8*time.Second, queuedTxBackoff(4))
+	assert.Equal(t, queuedTxMaxBackoff, queuedTxBackoff(10))
+	assert.Equal(t, queuedTxMaxBackoff, queuedTxBackoff(1000))
+}
+
+func TestGasPriceFloors(t *testing.T) {
+	assert.Equal(t, big.NewInt(5), raiseToFloor(big.NewInt(5), 0))
+	assert.Equal(t, big.NewInt(7), raiseToFloor(big.NewInt(5), 7))
+	assert.Equal(t, big.NewInt(9), raiseToFloor(big.NewInt(9), 7))
+
+	tracker, err := newEscalationTracker(nil, 11, 10)
+	assert.NoError(t, err)
+	s := &Sender{
+		auth:       &bind.TransactOpts{},
+		txType:     LegacyTxType,
+		escalation: tracker,
+	}
+	s.cfg.Store(&config.SenderConfig{TxType: LegacyTxType, MaxGasPrice: 10000, MinGasPrice: 1000, MinGasTipCap: 100})
+
+	// a zero gas price is raised to the floor on resubmission instead of by a single wei.
+	feeData := s.escalateFeeData(gethTypes.NewTx(&gethTypes.LegacyTx{GasPrice: big.NewInt(0)}), 0)
+	assert.Equal(t, big.NewInt(1000), feeData.gasPrice)
+
+	// a gas price above the floor is escalated as usual.
+	feeData = s.escalateFeeData(gethTypes.NewTx(&gethTypes.LegacyTx{GasPrice: big.NewInt(2000)}), 0)
+	assert.Equal(t, big.NewInt(2200), feeData.gasPrice)
+
+	s.txType = DynamicFeeTxType
+	feeData = s.escalateFeeData(gethTypes.NewTx(&gethTypes.DynamicFeeTx{GasTipCap: big.NewInt(0), GasFeeCap: big.NewInt(0)}), 0)
+	assert.Equal(t, big.NewInt(100), feeData.gasTipCap)
+	assert.Equal(t, big.NewInt(1000), feeData.gasFeeCap)
+
+	// the floors never exceed MaxGasPrice.
+	s.config().MaxGasPrice = 500
+	feeData = s.escalateFeeData(gethTypes.NewTx(&gethTypes.DynamicFeeTx{GasTipCap: big.NewInt(0), GasFeeCap: big.NewInt(0)}), 0)
+	assert.Equal(t, big.NewInt(100), feeData.gasTipCap)
+	assert.Equal(t, big.NewInt(500), feeData.gasFeeCap)
+}
+
+type debugTraceService struct {
+	output []byte
+	err    error
+}
+
+func (d *debugTraceService) TraceTransaction(hash common.Hash, tracerConfig map[string]interface{}) (*callFrame, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &callFrame{Type: "CALL", Output: d.output}, nil
+}
+
+func TestPadGasLimit(t *testing.T) {
+	s := &Sender{senderType: types.SenderTypeCommitBatch}
+	s.cfg.Store(&config.SenderConfig{GasLimitMultiples: map[string]*config.GasLimitMultipleConfig{"commit_batch": {Num: 15, Den: 10}}})
+
+	gasLimit, num, den := s.padGasLimit(100000)
+	assert.Equal(t, []uint64{150000, 15, 10}, []uint64{gasLimit, num, den})
+
+	// the sender types not configured pad the estimate by 20%.
+	s.senderType = types.SenderTypeL1GasOracle
+	gasLimit, num, den = s.padGasLimit(100000)
+	assert.Equal(t, []uint64{120000, 12, 10}, []uint64{gasLimit, num, den})
+}
+
+func TestWithMetadata(t *testing.T) {
+	options := newSendOptions([]SendOption{
+		WithMetadata(map[string]string{"batch_index": "1", "batch_hash": "0x01"}),
+		WithMetadata(map[string]string{"batch_index": "2"}),
+	})
+	assert.Equal(t, map[string]string{"batch_index": "2", "batch_hash": "0x01"}, options.metadata)
+
+	queuedTx := &QueuedTransaction{Options: []SendOption{WithMetadata(map[string]string{"message_nonce": "7"})}}
+	assert.Equal(t, map[string]string{"message_nonce": "7"}, queuedTx.metadata())
+	assert.Nil(t, (&QueuedTransaction{}).metadata())
+}
+
+func TestDecodeCallResults(t *testing.T) {
+	calls := []multicall3Call{
+		{Target: common.HexToAddress("0x1"), AllowFailure: false, CallData: []byte{0x01}},
+		{Target: common.HexToAddress("0x2"), AllowFailure: true, CallData: []byte{0x02}},
+	}
+	data, err := multicall3ABI.Pack("aggregate3", calls)
+	assert.NoError(t, err)
+	unpacked, err := unpackAggregate3Calls(data)
+	assert.NoError(t, err)
+	assert.Equal(t, calls, unpacked)
+
+	output, err := multicall3ABI.Methods["aggregate3"].Outputs.Pack([]multicall3Result{
+		{Success: true, ReturnData: []byte{0xaa}},
+		{Success: false, ReturnData: []byte{0xbb}},
+	})
+	assert.NoError(t, err)
+
+	service := &debugTraceService{output: output}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("debug", service))
+	defer server.Stop()
+	s := &Sender{ctx: context.Background(), rpcClient: rpc.DialInProc(server)}
+
+	tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{Data: data})
+	receipt := &gethTypes.Receipt{Status: gethTypes.ReceiptStatusSuccessful}
+	contextID := multicallContextIDPrefix + "a,b"
+	assert.True(t, IsAggregateContextID(contextID))
+
+	results := s.decodeCallResults(contextID, tx, receipt)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "a", results[0].ContextID)
+	assert.Equal(t, calls[0].Target, results[0].Target)
+	assert.True(t, results[0].Success)
+	assert.Equal(t, []byte{0xaa}, results[0].ReturnData)
+	assert.Equal(t, "b", results[1].ContextID)
+	assert.False(t, results[1].Success)
+	assert.Equal(t, []byte{0xbb}, results[1].ReturnData)
+
+	// without trace, only the calls not allowed to fail are known to have succeeded.
+	service.err = errors.New("debug namespace unavailable")
+	results = s.decodeCallResults(contextID, tx, receipt)
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].Success)
+	assert.False(t, results[1].Success)
+	assert.Nil(t, results[1].ReturnData)
+
+	// a failed aggregated transaction fails every call.
+	results = s.decodeCallResults(contextID, tx, &gethTypes.Receipt{Status: gethTypes.ReceiptStatusFailed})
+	assert.Len(t, results, 2)
+	assert.False(t, results[0].Success)
+	assert.False(t, results[1].Success)
+}
+
+func TestRecordConfirmationMetrics(t *testing.T) {
+	s := &Sender{
+		service:    "test",
+		name:       "confirmation_metrics",
+		senderType: types.SenderTypeFinalizeBatch,
+		auth:       &bind.TransactOpts{From: common.HexToAddress("0x1")},
+		metrics:    initSenderMetrics(nil),
+	}
+	senderType := s.senderType.String()
+
+	tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{To: &common.Address{}, Data: []byte{0x01}})
+	receipt := &gethTypes.Receipt{BlockNumber: big.NewInt(15), GasUsed: 21000}
+	s.recordConfirmationMetrics(tx, receipt, big.NewInt(2*params.GWei), 10, 3)
+
+	assert.Equal(t, float64(21000), testutil.ToFloat64(s.metrics.gasUsedTotal.WithLabelValues(s.service, s.name, senderType)))
+	assert.Equal(t, float64(42000), testutil.ToFloat64(s.metrics.feeSpentGweiTotal.WithLabelValues(s.service, s.name, senderType)))
+
+	histogram := &dto.Metric{}
+	assert.NoError(t, s.metrics.blocksToInclusion.WithLabelValues(s.service, s.name, senderType).(prometheus.Metric).Write(histogram))
+	assert.Equal(t, uint64(1), histogram.GetHistogram().GetSampleCount())
+	assert.Equal(t, float64(5), histogram.GetHistogram().GetSampleSum())
+	assert.NoError(t, s.metrics.escalationsPerTransaction.WithLabelValues(s.service, s.name, senderType).(prometheus.Metric).Write(histogram))
+	assert.Equal(t, float64(3), histogram.GetHistogram().GetSampleSum())
+
+	// cancellations only account for the gas spend.
+	cancelTx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{To: &s.auth.From, Value: big.NewInt(0)})
+	s.recordConfirmationMetrics(cancelTx, receipt, big.NewInt(params.GWei), 10, 1)
+	assert.Equal(t, float64(42000), testutil.ToFloat64(s.metrics.gasUsedTotal.WithLabelValues(s.service, s.name, senderType)))
+	assert.NoError(t, s.metrics.blocksToInclusion.WithLabelValues(s.service, s.name, senderType).(prometheus.Metric).Write(histogram))
+	assert.Equal(t, uint64(1), histogram.GetHistogram().GetSampleCount())
+}
@@ -0,0 +1,113 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// panicSelector is the selector of the solidity builtin `Panic(uint256)` error.
+var panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+// SimulationError is returned when the pre-send eth_call simulation of a transaction reverts.
+type SimulationError struct {
+	// Reason is the decoded revert reason, empty if it could not be decoded.
+	Reason string
+	// Data is the raw revert data returned by the node.
+	Data []byte
+	// Err is the original error returned by eth_call.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *SimulationError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("transaction simulation reverted: %s", e.Reason)
+	}
+	return fmt.Sprintf("transaction simulation failed: %v", e.Err)
+}
+
+// Unwrap returns the original eth_call error.
+func (e *SimulationError) Unwrap() error {
+	return e.Err
+}
+
+// simulateTransaction executes the transaction with eth_call at the pending block, applying the optional state overrides,
+// and returns a *SimulationError if it reverts.
+func (s *Sender) simulateTransaction(feeData *FeeData, target *common.Address, value *big.Int, data []byte, overrides StateOverrides) error {
+	msg := ethereum.CallMsg{
+		From:      s.auth.From,
+		To:        target,
+		Gas:       feeData.gasLimit,
+		GasPrice:  feeData.gasPrice,
+		GasTipCap: feeData.gasTipCap,
+		GasFeeCap: feeData.gasFeeCap,
+		Value:     value,
+		Data:      data,
+	}
+
+	var err error
+	if overrides != nil {
+		_, err = s.callWithOverrides(msg, overrides)
+	} else {
+		_, err = s.client.PendingCallContract(s.ctx, msg)
+	}
+	if err != nil {
+		revertData := revertDataFromError(err)
+		simErr := &SimulationError{
+			Reason: decodeRevertReason(revertData),
+			Data:   revertData,
+			Err:    err,
+		}
+		log.Warn("transaction simulation failed", "service", s.service, "name", s.name, "from", s.auth.From.String(), "to", target, "reason", simErr.Reason, "err", err)
+		return simErr
+	}
+	return nil
+}
+
+// revertDataFromError extracts the revert data attached to a json-rpc error, if any.
+func revertDataFromError(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// decodeRevertReason decodes the solidity builtin `Error(string)` and `Panic(uint256)` errors.
+func decodeRevertReason(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+
+	if bytes.Equal(data[:4], panicSelector) {
+		typ, _ := abi.NewType("uint256", "", nil)
+		unpacked, err := (abi.Arguments{{Type: typ}}).Unpack(data[4:])
+		if err == nil && len(unpacked) == 1 {
+			return fmt.Sprintf("panic: 0x%x", unpacked[0].(*big.Int))
+		}
+	}
+
+	return ""
+}
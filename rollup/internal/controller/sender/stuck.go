@@ -0,0 +1,51 @@
+package sender
+
+import (
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// markTransactionStuck stops escalating a transaction which reached MaxReplacements, and alerts through the confirmation channel.
+// The transaction is still checked for inclusion, an operator resumes its escalation with ResumeStuckTransaction.
+func (s *Sender) markTransactionStuck(tx *gethTypes.Transaction, txnToCheck *orm.PendingTransaction) {
+	log.Error("transaction reached max replacements, marking it stuck",
+		"service", s.service,
+		"name", s.name,
+		"context ID", txnToCheck.ContextID,
+		"hash", tx.Hash().String(),
+		"nonce", tx.Nonce(),
+		"replacements", txnToCheck.Replacements,
+		"max replacements", s.config().MaxReplacements)
+
+	if err := s.pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(s.ctx, tx.Hash(), types.TxStatusStuck); err != nil {
+		log.Error("failed to update transaction status to stuck", "hash", tx.Hash().String(), "err", err)
+		return
+	}
+	s.metrics.stuckTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+
+	s.confirmCh <- &Confirmation{
+		ContextID:    txnToCheck.ContextID,
+		TxHash:       tx.Hash(),
+		TxStatus:     types.TxStatusStuck,
+		SenderType:   s.senderType,
+		SenderStatus: SenderTransactionStuck,
+		Metadata:     txnToCheck.Metadata,
+	}
+}
+
+// ResumeStuckTransaction resumes the escalation of the stuck transaction of contextID with a fresh replacement budget.
+// It returns whether a stuck transaction was found.
+func (s *Sender) ResumeStuckTransaction(contextID string) (bool, error) {
+	resumed, err := s.pendingTransactionOrm.UpdateStuckTransactionAsPendingByContextID(s.ctx, contextID)
+	if err != nil {
+		return false, err
+	}
+	if resumed {
+		log.Info("stuck transaction resumed by operator", "service", s.service, "name", s.name, "context ID", contextID)
+	}
+	return resumed, nil
+}
@@ -0,0 +1,117 @@
+package sender
+
+import (
+	"encoding/json"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// callFrame is a call frame returned by the callTracer of debug_traceTransaction.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Input   hexutil.Bytes   `json:"input,omitempty"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Calls   []callFrame     `json:"calls,omitempty"`
+}
+
+// TraceSummary is the post-mortem summary of a reverted transaction persisted next to its pending_transaction row.
+type TraceSummary struct {
+	// Depth is the depth of the failing call frame, 0 being the top-level call.
+	Depth        int             `json:"depth"`
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Error        string          `json:"error"`
+	RevertReason string          `json:"revert_reason,omitempty"`
+	RevertData   hexutil.Bytes   `json:"revert_data,omitempty"`
+	// Selector is the 4-byte function selector of the failing call.
+	Selector hexutil.Bytes  `json:"selector,omitempty"`
+	GasUsed  hexutil.Uint64 `json:"gas_used"`
+}
+
+// failingFrame returns the innermost failing call frame along the first failing path of the call tree, and its depth.
+func failingFrame(frame *callFrame, depth int) (*callFrame, int) {
+	for i := range frame.Calls {
+		if frame.Calls[i].Error != "" {
+			return failingFrame(&frame.Calls[i], depth+1)
+		}
+	}
+	return frame, depth
+}
+
+// summarizeTrace builds the TraceSummary of a callTracer result.
+func summarizeTrace(root *callFrame) *TraceSummary {
+	frame, depth := failingFrame(root, 0)
+	summary := &TraceSummary{
+		Depth:        depth,
+		Type:         frame.Type,
+		From:         frame.From,
+		To:           frame.To,
+		Error:        frame.Error,
+		RevertReason: decodeRevertReason(frame.Output),
+		RevertData:   frame.Output,
+		GasUsed:      frame.GasUsed,
+	}
+	if len(frame.Input) >= 4 {
+		summary.Selector = frame.Input[:4]
+	}
+	return summary
+}
+
+// traceFailedTransaction traces a reverted transaction with debug_traceTransaction, persists the summary of the failing call frame
+// and returns it. Tracing is best effort, failures are only logged since the endpoint may not expose the debug namespace.
+func (s *Sender) traceFailedTransaction(hash common.Hash) *TraceSummary {
+	var root callFrame
+	if err := s.rpcClient.CallContext(s.ctx, &root, "debug_traceTransaction", hash, map[string]interface{}{"tracer": "callTracer"}); err != nil {
+		s.metrics.traceFailedTransactionFailedTotal.WithLabelValues(s.service, s.name).Inc()
+		log.Warn("failed to trace reverted transaction", "service", s.service, "name", s.name, "hash", hash.String(), "err", err)
+		return nil
+	}
+
+	summary := summarizeTrace(&root)
+	log.Warn("reverted transaction traced", "service", s.service, "name", s.name, "hash", hash.String(), "depth", summary.Depth,
+		"to", summary.To, "selector", summary.Selector, "error", summary.Error, "revert reason", summary.RevertReason)
+
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		log.Error("failed to encode trace summary", "hash", hash.String(), "err", err)
+		return summary
+	}
+	if err := s.pendingTransactionOrm.UpdateFailureTraceByTxHash(s.ctx, hash, string(encoded)); err != nil {
+		log.Error("failed to update failure trace by tx hash", "hash", hash.String(), "err", err)
+	}
+	return summary
+}
+
+// revertReason returns the decoded revert reason of a reverted transaction. It uses the trace of the transaction when
+// TraceFailedTransactions is enabled, and otherwise replays the transaction with eth_call on top of its inclusion block,
+// which reproduces reverts caused by the contract state such as a paused contract. It returns an empty string if the
+// reason can not be decoded.
+func (s *Sender) revertReason(tx *gethTypes.Transaction, receipt *gethTypes.Receipt) string {
+	if s.config().TraceFailedTransactions {
+		if summary := s.traceFailedTransaction(tx.Hash()); summary != nil && summary.RevertReason != "" {
+			return summary.RevertReason
+		}
+	}
+
+	msg := ethereum.CallMsg{
+		From:       s.auth.From,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if _, err := s.client.CallContract(s.ctx, msg, receipt.BlockNumber); err != nil {
+		return decodeRevertReason(revertDataFromError(err))
+	}
+	return ""
+}
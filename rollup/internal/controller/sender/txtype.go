@@ -0,0 +1,57 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// resolveTxType returns the tx type of the transactions sent to the chain of the endpoint. A configured DynamicFeeTx
+// type is downgraded to LegacyTx with a warning on chains without EIP-1559, instead of failing on each transaction.
+func resolveTxType(ctx context.Context, rpcClient *rpc.Client, client *ethclient.Client, txType, service, name string) (string, error) {
+	if txType != DynamicFeeTxType {
+		return txType, nil
+	}
+	supported, reason, err := supportsDynamicFeeTx(ctx, rpcClient, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect dynamic fee transaction support, err: %w", err)
+	}
+	if !supported {
+		log.Warn("chain does not support dynamic fee transactions, downgrading to legacy transactions", "service", service, "name", name,
+			"configured tx type", txType, "reason", reason)
+		return LegacyTxType, nil
+	}
+	return txType, nil
+}
+
+// supportsDynamicFeeTx reports whether the chain supports EIP-1559 dynamic fee transactions: the latest header carries
+// a base fee and eth_feeHistory reports the base fees. The reason of an unsupported chain is returned, the errors are
+// the failures to reach the endpoint.
+func supportsDynamicFeeTx(ctx context.Context, rpcClient *rpc.Client, client *ethclient.Client) (bool, string, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get latest header, err: %w", err)
+	}
+	if header.BaseFee == nil {
+		return false, "no base fee in the latest header", nil
+	}
+
+	var result feeHistoryResult
+	if err = rpcClient.CallContext(ctx, &result, "eth_feeHistory", hexutil.Uint64(1), "latest", []float64{}); err != nil {
+		// a json-rpc error response, e.g. method not found, is a node without the fee market api.
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return false, fmt.Sprintf("eth_feeHistory failed: %v", err), nil
+		}
+		return false, "", fmt.Errorf("failed to get fee history, err: %w", err)
+	}
+	if len(result.BaseFee) == 0 {
+		return false, "no base fee in eth_feeHistory", nil
+	}
+	return true, "", nil
+}
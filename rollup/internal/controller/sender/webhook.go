@@ -0,0 +1,161 @@
+package sender
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// WebhookEventSent is delivered when a new transaction is broadcast.
+	WebhookEventSent = "sent"
+	// WebhookEventReplaced is delivered when a transaction is replaced with escalated fees or cancelled.
+	WebhookEventReplaced = "replaced"
+	// WebhookEventConfirmed is delivered when a transaction is confirmed successfully.
+	WebhookEventConfirmed = "confirmed"
+	// WebhookEventFailed is delivered when a transaction is confirmed reverted or expired.
+	WebhookEventFailed = "failed"
+
+	// webhookSignatureHeader carries the hex encoded HMAC-SHA256 of the request body.
+	webhookSignatureHeader = "X-Scroll-Signature"
+
+	defaultWebhookRetryCount = 3
+	defaultWebhookTimeout    = 5
+	webhookQueueSize         = 1024
+)
+
+// WebhookEvent is the body of a webhook delivery.
+type WebhookEvent struct {
+	Event      string      `json:"event"`
+	Service    string      `json:"service"`
+	Name       string      `json:"name"`
+	SenderType string      `json:"sender_type"`
+	ContextID  string      `json:"context_id"`
+	TxHash     common.Hash `json:"tx_hash"`
+	Nonce      uint64      `json:"nonce"`
+	// ReplacedTxHash is the hash of the transaction replaced by TxHash, only set for replaced events.
+	ReplacedTxHash *common.Hash `json:"replaced_tx_hash,omitempty"`
+	// TxStatus is the final status of the transaction, only set for confirmed and failed events.
+	TxStatus  string `json:"tx_status,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// webhookNotifier delivers the events of a sender to the webhook in order from a background goroutine,
+// so that a slow or unavailable endpoint never blocks sending. Events are dropped when the queue is full.
+type webhookNotifier struct {
+	client *resty.Client
+	url    string
+	secret []byte
+	events chan *WebhookEvent
+}
+
+// newWebhookNotifier creates the webhook notifier configured by cfg, nil cfg disables it.
+func newWebhookNotifier(cfg *config.WebhookConfig) *webhookNotifier {
+	if cfg == nil || cfg.URL == "" {
+		return nil
+	}
+
+	retryCount := cfg.RetryCount
+	if retryCount == 0 {
+		retryCount = defaultWebhookRetryCount
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	client := resty.New()
+	client.SetRetryCount(retryCount)
+	client.SetTimeout(time.Duration(timeout) * time.Second)
+	client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		return err != nil || resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= http.StatusInternalServerError
+	})
+
+	return &webhookNotifier{
+		client: client,
+		url:    cfg.URL,
+		secret: []byte(cfg.Secret),
+		events: make(chan *WebhookEvent, webhookQueueSize),
+	}
+}
+
+// signWebhookBody returns the hex encoded HMAC-SHA256 of body keyed by secret.
+func signWebhookBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver posts an event to the webhook, retrying on network errors, 429 and 5xx responses.
+func (n *webhookNotifier) deliver(event *WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event, err: %w", err)
+	}
+
+	req := n.client.R().SetHeader("Content-Type", "application/json").SetBody(body)
+	if len(n.secret) > 0 {
+		req.SetHeader(webhookSignatureHeader, signWebhookBody(n.secret, body))
+	}
+	resp, err := req.Post(n.url)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook event, err: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("failed to post webhook event, status: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// notifyWebhook queues a lifecycle event for delivery, it is a no-op when the webhook is disabled.
+func (s *Sender) notifyWebhook(event string, contextID string, txHash common.Hash, nonce uint64, replacedTxHash *common.Hash, txStatus string) {
+	if s.webhook == nil {
+		return
+	}
+
+	webhookEvent := &WebhookEvent{
+		Event:          event,
+		Service:        s.service,
+		Name:           s.name,
+		SenderType:     s.senderType.String(),
+		ContextID:      contextID,
+		TxHash:         txHash,
+		Nonce:          nonce,
+		ReplacedTxHash: replacedTxHash,
+		TxStatus:       txStatus,
+		Timestamp:      time.Now().Unix(),
+	}
+	select {
+	case s.webhook.events <- webhookEvent:
+	default:
+		s.metrics.webhookDroppedTotal.WithLabelValues(s.service, s.name).Inc()
+		log.Warn("webhook queue full, event dropped", "service", s.service, "name", s.name, "event", event, "context ID", contextID, "hash", txHash.String())
+	}
+}
+
+// webhookLoop delivers the queued events until the sender is stopped.
+func (s *Sender) webhookLoop() {
+	for {
+		select {
+		case event := <-s.webhook.events:
+			if err := s.webhook.deliver(event); err != nil {
+				s.metrics.webhookFailedTotal.WithLabelValues(s.service, s.name).Inc()
+				log.Warn("failed to deliver webhook event", "service", s.service, "name", s.name, "event", event.Event, "context ID", event.ContextID, "hash", event.TxHash.String(), "err", err)
+			}
+		case <-s.ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// BackfillConfig configures the replay of the ingestion of a watcher over a historical block range.
+type BackfillConfig struct {
+	// Workers is the number of ranges ingested in parallel.
+	Workers int
+	// RangesPerSecond limits the rate at which ranges are started, 0 when not limited.
+	RangesPerSecond float64
+}
+
+// blockRange is a closed range of block numbers.
+type blockRange struct {
+	from, to uint64
+}
+
+// backfill replays ingest over the blocks [from, to] split in ranges of rangeSize blocks. The ranges are ingested by
+// parallel workers, the first failure stops starting new ranges and is returned. ingest must be idempotent, a failed
+// backfill is resumed by running it again.
+func backfill(ctx context.Context, cfg BackfillConfig, from, to, rangeSize uint64, ingest func(ctx context.Context, from, to uint64) error) error {
+	if from > to {
+		return fmt.Errorf("invalid backfill range, from: %v, to: %v", from, to)
+	}
+	if cfg.Workers <= 0 || rangeSize == 0 {
+		return errors.New("backfill workers and range size must be greater than zero")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ranges := make(chan blockRange)
+	go func() {
+		defer close(ranges)
+
+		var tick <-chan time.Time
+		if cfg.RangesPerSecond > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.RangesPerSecond))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for start := from; start <= to; start += rangeSize {
+			end := start + rangeSize - 1
+			if end > to || end < start {
+				end = to
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ranges <- blockRange{from: start, to: end}:
+			}
+			if end == to {
+				return
+			}
+			if tick != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-tick:
+				}
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i := 0; i < cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range ranges {
+				if err := ingest(ctx, r.from, r.to); err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("failed to backfill blocks [%d, %d]: %w", r.from, r.to, err)
+						cancel()
+					})
+					return
+				}
+				log.Info("backfilled blocks", "from", r.from, "to", r.to)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,46 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testBackfill(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		ranges []blockRange
+	)
+	ingest := func(ctx context.Context, from, to uint64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ranges = append(ranges, blockRange{from: from, to: to})
+		return nil
+	}
+
+	// The ranges cover the blocks exactly once, the last one is truncated.
+	assert.NoError(t, backfill(context.Background(), BackfillConfig{Workers: 3}, 5, 29, 10, ingest))
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].from < ranges[j].from })
+	assert.Equal(t, []blockRange{{from: 5, to: 14}, {from: 15, to: 24}, {from: 25, to: 29}}, ranges)
+
+	ranges = nil
+	assert.NoError(t, backfill(context.Background(), BackfillConfig{Workers: 1, RangesPerSecond: 1000}, 7, 7, 10, ingest))
+	assert.Equal(t, []blockRange{{from: 7, to: 7}}, ranges)
+
+	// The first failure stops the backfill.
+	targetErr := errors.New("ingest failure")
+	err := backfill(context.Background(), BackfillConfig{Workers: 2}, 0, 1000, 10, func(ctx context.Context, from, to uint64) error {
+		if from == 50 {
+			return targetErr
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, targetErr)
+
+	assert.Error(t, backfill(context.Background(), BackfillConfig{Workers: 1}, 10, 9, 10, ingest))
+	assert.Error(t, backfill(context.Background(), BackfillConfig{}, 0, 9, 10, ingest))
+}
@@ -3,6 +3,7 @@ package watcher
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,6 +14,7 @@ import (
 	"gorm.io/gorm"
 
 	"scroll-tech/common/forks"
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/encoding/codecv0"
 
@@ -34,7 +36,13 @@ type BatchProposer struct {
 	maxL1CommitCalldataSizePerBatch uint64
 	batchTimeoutSec                 uint64
 	gasCostIncreaseMultiplier       float64
+	maxL1MessageSkipsPerBatch       uint64
+	skipGuard                       *L1MessageSkipGuard
+	withdrawRootVerifier            WithdrawRootVerifier
 	forkMap                         map[uint64]bool
+	dynamicSizing                   *dynamicSizing
+	// pendingConfig is the config passed to UpdateConfig, nil once applied.
+	pendingConfig atomic.Pointer[config.BatchProposerConfig]
 
 	batchProposerCircleTotal           prometheus.Counter
 	proposeBatchFailureTotal           prometheus.Counter
@@ -45,6 +53,12 @@ type BatchProposer struct {
 	batchChunksNum                     prometheus.Gauge
 	batchFirstBlockTimeoutReached      prometheus.Counter
 	batchChunksProposeNotEnoughTotal   prometheus.Counter
+	batchDynamicFillRatio              prometheus.Gauge
+	batchDynamicTimeoutSec             prometheus.Gauge
+	batchDynamicTargetReachedTotal     prometheus.Counter
+	batchL1MessageSkips                prometheus.Gauge
+	batchL1MessageSkipsTotal           prometheus.Counter
+	batchL1MessageSkipHeldTotal        prometheus.Counter
 }
 
 // NewBatchProposer creates a new BatchProposer instance.
@@ -56,6 +70,8 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 		"maxL1CommitCalldataSizePerBatch", cfg.MaxL1CommitCalldataSizePerBatch,
 		"batchTimeoutSec", cfg.BatchTimeoutSec,
 		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
+		"maxL1MessageSkipsPerBatch", cfg.MaxL1MessageSkipsPerBatch,
+		"dynamicSizing", cfg.DynamicSizing != nil,
 		"forkHeights", forkHeights)
 
 	return &BatchProposer{
@@ -69,7 +85,10 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 		maxL1CommitCalldataSizePerBatch: cfg.MaxL1CommitCalldataSizePerBatch,
 		batchTimeoutSec:                 cfg.BatchTimeoutSec,
 		gasCostIncreaseMultiplier:       cfg.GasCostIncreaseMultiplier,
+		maxL1MessageSkipsPerBatch:       cfg.MaxL1MessageSkipsPerBatch,
+		skipGuard:                       NewL1MessageSkipGuard(),
 		forkMap:                         forkMap,
+		dynamicSizing:                   newDynamicSizing(cfg.DynamicSizing, db),
 
 		batchProposerCircleTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "rollup_propose_batch_circle_total",
@@ -107,29 +126,114 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 			Name: "rollup_propose_batch_chunks_propose_not_enough_total",
 			Help: "Total number of batch chunk propose not enough",
 		}),
+		batchDynamicFillRatio: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_dynamic_fill_ratio",
+			Help: "The fraction of the l1 commit gas and calldata size limits targeted by the batch at the current l1 fees",
+		}),
+		batchDynamicTimeoutSec: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_dynamic_timeout_sec",
+			Help: "The batch timeout in seconds at the current l1 fees",
+		}),
+		batchDynamicTargetReachedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_dynamic_target_reached_total",
+			Help: "Total times a batch was proposed on reaching the size targeted at the current l1 fees",
+		}),
+		batchL1MessageSkips: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_l1_message_skips",
+			Help: "The number of L1 messages skipped in the last proposed batch",
+		}),
+		batchL1MessageSkipsTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_l1_message_skips_total",
+			Help: "Total number of L1 messages skipped in the proposed batches",
+		}),
+		batchL1MessageSkipHeldTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_l1_message_skip_held_total",
+			Help: "Total number of batch proposal rounds held for skipping more L1 messages than allowed",
+		}),
+	}
+}
+
+// WithdrawRootVerifier checks a withdraw root stored with the blocks against the chain.
+type WithdrawRootVerifier interface {
+	VerifyWithdrawRoot(ctx context.Context, blockNumber uint64, root common.Hash) error
+}
+
+// SetWithdrawRootVerifier makes the proposer check the withdraw root of the last block of every batch before the batch
+// is proposed, e.g. when the withdraw roots are computed by the l2 watcher. A batch failing the check is not proposed.
+func (p *BatchProposer) SetWithdrawRootVerifier(v WithdrawRootVerifier) {
+	p.withdrawRootVerifier = v
+}
+
+// SetL1MessageSkipGuard replaces the guard holding the batches skipping more L1 messages than allowed, e.g. by a guard
+// shared with the admin api.
+func (p *BatchProposer) SetL1MessageSkipGuard(g *L1MessageSkipGuard) {
+	p.skipGuard = g
+}
+
+// UpdateConfig replaces the limits of the batches proposed from the next TryProposeBatch on, the dynamic sizing is
+// kept.
+func (p *BatchProposer) UpdateConfig(cfg *config.BatchProposerConfig) {
+	p.pendingConfig.Store(cfg)
+}
+
+// applyPendingConfig applies the config passed to UpdateConfig.
+func (p *BatchProposer) applyPendingConfig() {
+	cfg := p.pendingConfig.Swap(nil)
+	if cfg == nil {
+		return
 	}
+	p.maxChunkNumPerBatch = cfg.MaxChunkNumPerBatch
+	p.maxL1CommitGasPerBatch = cfg.MaxL1CommitGasPerBatch
+	p.maxL1CommitCalldataSizePerBatch = cfg.MaxL1CommitCalldataSizePerBatch
+	p.batchTimeoutSec = cfg.BatchTimeoutSec
+	p.gasCostIncreaseMultiplier = cfg.GasCostIncreaseMultiplier
+	p.maxL1MessageSkipsPerBatch = cfg.MaxL1MessageSkipsPerBatch
+	log.Info("batch proposer config updated",
+		"maxChunkNumPerBatch", cfg.MaxChunkNumPerBatch,
+		"maxL1CommitGasPerBatch", cfg.MaxL1CommitGasPerBatch,
+		"maxL1CommitCalldataSizePerBatch", cfg.MaxL1CommitCalldataSizePerBatch,
+		"batchTimeoutSec", cfg.BatchTimeoutSec,
+		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
+		"maxL1MessageSkipsPerBatch", cfg.MaxL1MessageSkipsPerBatch)
 }
 
 // TryProposeBatch tries to propose a new batches.
 func (p *BatchProposer) TryProposeBatch() {
+	p.applyPendingConfig()
 	p.batchProposerCircleTotal.Inc()
+	ctx, span := tracing.Start(p.ctx, "batch_proposer.propose_batch")
+	defer span.End()
+
 	batch, err := p.proposeBatch()
 	if err != nil {
 		p.proposeBatchFailureTotal.Inc()
+		span.RecordError(err)
 		log.Error("proposeBatchChunks failed", "err", err)
 		return
 	}
 	if batch == nil {
 		return
 	}
+	if p.withdrawRootVerifier != nil {
+		lastChunk := batch.Chunks[len(batch.Chunks)-1]
+		lastBlockNumber := lastChunk.Blocks[len(lastChunk.Blocks)-1].Header.Number.Uint64()
+		if err = p.withdrawRootVerifier.VerifyWithdrawRoot(ctx, lastBlockNumber, batch.WithdrawRoot()); err != nil {
+			p.proposeBatchFailureTotal.Inc()
+			span.RecordError(err)
+			log.Error("failed to verify the withdraw root of the batch", "start chunk index", batch.StartChunkIndex, "end chunk index", batch.EndChunkIndex, "err", err)
+			return
+		}
+	}
 	err = p.db.Transaction(func(dbTX *gorm.DB) error {
-		batch, dbErr := p.batchOrm.InsertBatch(p.ctx, batch, dbTX)
+		batch, dbErr := p.batchOrm.InsertBatch(ctx, batch, codecv0.CodecV0Version, dbTX)
 		if dbErr != nil {
 			log.Warn("BatchProposer.updateBatchInfoInDB insert batch failure",
 				"start chunk index", batch.StartChunkIndex, "end chunk index", batch.EndChunkIndex, "error", dbErr)
 			return dbErr
 		}
-		dbErr = p.chunkOrm.UpdateBatchHashInRange(p.ctx, batch.StartChunkIndex, batch.EndChunkIndex, batch.Hash, dbTX)
+		span.SetAttributes("batch_index", batch.Index, "batch_hash", batch.Hash)
+		ctx := tracing.WithCorrelationID(ctx, "batch:"+batch.Hash)
+		dbErr = p.chunkOrm.UpdateBatchHashInRange(ctx, batch.StartChunkIndex, batch.EndChunkIndex, batch.Hash, dbTX)
 		if dbErr != nil {
 			log.Warn("BatchProposer.UpdateBatchHashInRange update the chunk's batch hash failure", "hash", batch.Hash, "error", dbErr)
 			return dbErr
@@ -138,6 +242,7 @@ func (p *BatchProposer) TryProposeBatch() {
 	})
 	if err != nil {
 		p.proposeBatchUpdateInfoFailureTotal.Inc()
+		span.RecordError(err)
 		log.Error("update batch info in db failed", "err", err)
 	}
 }
@@ -175,12 +280,25 @@ func (p *BatchProposer) proposeBatch() (*encoding.Batch, error) {
 		return nil, err
 	}
 
+	// the size targets are soft, a batch reaching them is proposed without waiting for more chunks or the timeout.
+	fillRatio, timeoutRatio, err := p.dynamicSizing.ratios(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetL1CommitGas := uint64(fillRatio * float64(p.maxL1CommitGasPerBatch))
+	targetL1CommitCalldataSize := uint64(fillRatio * float64(p.maxL1CommitCalldataSizePerBatch))
+	batchTimeoutSec := uint64(timeoutRatio * float64(p.batchTimeoutSec))
+	p.batchDynamicFillRatio.Set(fillRatio)
+	p.batchDynamicTimeoutSec.Set(float64(batchTimeoutSec))
+
 	parentDBBatch, err := p.batchOrm.GetLatestBatch(p.ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var batch encoding.Batch
+	var targetReached, skipLimitReached bool
+	var batchL1MessageSkips uint64
 	if parentDBBatch != nil {
 		batch.Index = parentDBBatch.Index + 1
 		parentDABatch, err := codecv0.NewDABatchFromBytes(parentDBBatch.BatchHeader)
@@ -252,14 +370,51 @@ func (p *BatchProposer) proposeBatch() (*encoding.Batch, error) {
 			p.totalL1CommitGas.Set(float64(totalL1CommitCalldataSize))
 			p.totalL1CommitCalldataSize.Set(float64(totalL1CommitGas))
 			p.batchChunksNum.Set(float64(batch.NumChunks()))
+			p.recordL1MessageSkips(&batch, dbChunks)
 			return &batch, nil
 		}
+
+		// the skipped messages are chosen by the sequencer, a batch exceeding the limit with its first chunk is held.
+		chunkL1MessageSkips := skippedL1Messages(chunk, dbChunks[i].TotalL1MessagesPoppedBefore)
+		if p.maxL1MessageSkipsPerBatch != 0 {
+			skipped := batchL1MessageSkips + chunkL1MessageSkips
+			if i == 0 && !p.skipGuard.allow(L1MessageSkipProposalBatch, dbChunks[0].Index, skipped, p.maxL1MessageSkipsPerBatch) {
+				p.batchL1MessageSkipHeldTotal.Inc()
+				return nil, nil
+			}
+			if i != 0 && skipped > p.maxL1MessageSkipsPerBatch {
+				log.Debug("breaking l1 message skip limit in batching",
+					"skipped", skipped,
+					"limit", p.maxL1MessageSkipsPerBatch)
+
+				batch.Chunks = batch.Chunks[:len(batch.Chunks)-1]
+				skipLimitReached = true
+				break
+			}
+		}
+		batchL1MessageSkips += chunkL1MessageSkips
+
+		if p.dynamicSizing != nil && (totalOverEstimateL1CommitGas >= targetL1CommitGas || totalL1CommitCalldataSize >= targetL1CommitCalldataSize) {
+			targetReached = true
+			break
+		}
 	}
 
 	currentTimeSec := uint64(time.Now().Unix())
-	if dbChunks[0].StartBlockTime+p.batchTimeoutSec < currentTimeSec ||
-		batch.NumChunks() == maxChunksThisBatch {
-		if dbChunks[0].StartBlockTime+p.batchTimeoutSec < currentTimeSec {
+	if dbChunks[0].StartBlockTime+batchTimeoutSec < currentTimeSec ||
+		batch.NumChunks() == maxChunksThisBatch || targetReached || skipLimitReached {
+		if skipLimitReached {
+			log.Info("reached maximum number of l1 message skips in batch",
+				"chunk count", batch.NumChunks(),
+				"skipped", batchL1MessageSkips,
+			)
+		} else if targetReached {
+			log.Info("reached target size of batch at current l1 fees",
+				"chunk count", batch.NumChunks(),
+				"fill ratio", fillRatio,
+			)
+			p.batchDynamicTargetReachedTotal.Inc()
+		} else if dbChunks[0].StartBlockTime+batchTimeoutSec < currentTimeSec {
 			log.Warn("first block timeout",
 				"start block number", dbChunks[0].StartBlockNumber,
 				"start block timestamp", dbChunks[0].StartBlockTime,
@@ -285,6 +440,7 @@ func (p *BatchProposer) proposeBatch() (*encoding.Batch, error) {
 		p.totalL1CommitGas.Set(float64(totalL1CommitCalldataSize))
 		p.totalL1CommitCalldataSize.Set(float64(totalL1CommitGas))
 		p.batchChunksNum.Set(float64(batch.NumChunks()))
+		p.recordL1MessageSkips(&batch, dbChunks)
 
 		batch.StartChunkIndex = dbChunks[0].Index
 		batch.EndChunkIndex = dbChunks[batch.NumChunks()-1].Index
@@ -299,6 +455,16 @@ func (p *BatchProposer) proposeBatch() (*encoding.Batch, error) {
 	return nil, nil
 }
 
+// recordL1MessageSkips records the number of L1 messages skipped in the proposed batch of the dbChunks.
+func (p *BatchProposer) recordL1MessageSkips(batch *encoding.Batch, dbChunks []*orm.Chunk) {
+	var skipped uint64
+	for i, chunk := range batch.Chunks {
+		skipped += skippedL1Messages(chunk, dbChunks[i].TotalL1MessagesPoppedBefore)
+	}
+	p.batchL1MessageSkips.Set(float64(skipped))
+	p.batchL1MessageSkipsTotal.Add(float64(skipped))
+}
+
 func (p *BatchProposer) getDAChunks(dbChunks []*orm.Chunk) ([]*encoding.Chunk, error) {
 	chunks := make([]*encoding.Chunk, len(dbChunks))
 	for i, c := range dbChunks {
@@ -220,3 +220,72 @@ func testBatchCommitGasAndCalldataSizeEstimation(t *testing.T) {
 	assert.Equal(t, uint64(258383), batches[0].TotalL1CommitGas)
 	assert.Equal(t, uint64(6035), batches[0].TotalL1CommitCalldataSize)
 }
+
+func testBatchProposerDynamicSizing(t *testing.T) {
+	tests := []struct {
+		name                       string
+		l1Block                    *orm.L1Block
+		expectedBatchesLen         int
+		expectedChunksInFirstBatch uint64 // only be checked when expectedBatchesLen > 0
+	}{
+		{
+			name:               "ExpensiveFees",
+			l1Block:            &orm.L1Block{Number: 1, Hash: "hash1", BaseFee: 1000, BlobBaseFee: 1000},
+			expectedBatchesLen: 0,
+		},
+		{
+			name:                       "CheapFees",
+			l1Block:                    &orm.L1Block{Number: 1, Hash: "hash1", BaseFee: 1, BlobBaseFee: 1000},
+			expectedBatchesLen:         1,
+			expectedChunksInFirstBatch: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupDB(t)
+			defer database.CloseDB(db)
+
+			l2BlockOrm := orm.NewL2Block(db)
+			err := l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+			assert.NoError(t, err)
+			err = orm.NewL1Block(db).InsertL1Blocks(context.Background(), []orm.L1Block{*tt.l1Block})
+			assert.NoError(t, err)
+
+			cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+				MaxBlockNumPerChunk:             1,
+				MaxTxNumPerChunk:                10000,
+				MaxL1CommitGasPerChunk:          50000000000,
+				MaxL1CommitCalldataSizePerChunk: 1000000,
+				MaxRowConsumptionPerChunk:       1000000,
+				ChunkTimeoutSec:                 300,
+				GasCostIncreaseMultiplier:       1.2,
+			}, &params.ChainConfig{}, db, nil)
+			cp.TryProposeChunk() // chunk1 contains block1
+			cp.TryProposeChunk() // chunk2 contains block2
+
+			bp := NewBatchProposer(context.Background(), &config.BatchProposerConfig{
+				MaxChunkNumPerBatch:             10,
+				MaxL1CommitGasPerBatch:          50000000000,
+				MaxL1CommitCalldataSizePerBatch: 1000000,
+				BatchTimeoutSec:                 1 << 40,
+				GasCostIncreaseMultiplier:       1.2,
+				DynamicSizing: &config.DynamicSizingConfig{
+					LowFee:          10,
+					HighFee:         100,
+					MinFillRatio:    1e-9,
+					MinTimeoutRatio: 1,
+				},
+			}, &params.ChainConfig{}, db, nil)
+			bp.TryProposeBatch()
+
+			batchOrm := orm.NewBatch(db)
+			batches, err := batchOrm.GetBatches(context.Background(), map[string]interface{}{}, []string{}, 0)
+			assert.NoError(t, err)
+			assert.Len(t, batches, tt.expectedBatchesLen)
+			if tt.expectedBatchesLen > 0 {
+				assert.Equal(t, tt.expectedChunksInFirstBatch, batches[0].EndChunkIndex-batches[0].StartChunkIndex+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package watcher
+
+import (
+	"fmt"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv0"
+)
+
+// The names of the built-in chunk constraints, and of the other reasons ending a chunk, in the logs and metrics.
+const (
+	chunkConstraintTxNum                = "tx_num"
+	chunkConstraintL1CommitGas          = "l1_commit_gas"
+	chunkConstraintL1CommitCalldataSize = "l1_commit_calldata_size"
+	chunkConstraintRowConsumption       = "row_consumption"
+
+	chunkBoundBlockNum       = "block_num"
+	chunkBoundTimeout        = "timeout"
+	chunkBoundDynamicTarget  = "dynamic_target"
+	chunkBoundL1MessageSkips = "l1_message_skips"
+)
+
+// ChunkConstraint is a limit of the chunks proposed by the ChunkProposer: blocks are appended to a chunk until one more
+// block would make the chunk exceed the limit of a constraint.
+type ChunkConstraint interface {
+	// Name identifies the constraint in the logs and metrics.
+	Name() string
+	// Limit returns the maximum value of the constraint in a chunk.
+	Limit() uint64
+	// Measure returns the value of the constraint for the chunk.
+	Measure(chunk *encoding.Chunk) (uint64, error)
+}
+
+type chunkConstraint struct {
+	name    string
+	limit   uint64
+	measure func(chunk *encoding.Chunk) (uint64, error)
+}
+
+// NewChunkConstraint creates a ChunkConstraint limiting the value measured on the chunks, e.g. for the rules of a new
+// codec version.
+func NewChunkConstraint(name string, limit uint64, measure func(chunk *encoding.Chunk) (uint64, error)) ChunkConstraint {
+	return &chunkConstraint{name: name, limit: limit, measure: measure}
+}
+
+func (c *chunkConstraint) Name() string {
+	return c.name
+}
+
+func (c *chunkConstraint) Limit() uint64 {
+	return c.limit
+}
+
+func (c *chunkConstraint) Measure(chunk *encoding.Chunk) (uint64, error) {
+	return c.measure(chunk)
+}
+
+// defaultChunkConstraints returns the constraints configured by the ChunkProposerConfig.
+func defaultChunkConstraints(maxTxNum, maxL1CommitGas, maxL1CommitCalldataSize, maxRowConsumption uint64, gasCostIncreaseMultiplier float64) []ChunkConstraint {
+	return []ChunkConstraint{
+		NewChunkConstraint(chunkConstraintTxNum, maxTxNum, func(chunk *encoding.Chunk) (uint64, error) {
+			return chunk.NumTransactions(), nil
+		}),
+		NewChunkConstraint(chunkConstraintL1CommitGas, maxL1CommitGas, func(chunk *encoding.Chunk) (uint64, error) {
+			totalL1CommitGas, err := codecv0.EstimateChunkL1CommitGas(chunk)
+			if err != nil {
+				return 0, fmt.Errorf("failed to estimate chunk L1 commit gas: %w", err)
+			}
+			return uint64(gasCostIncreaseMultiplier * float64(totalL1CommitGas)), nil
+		}),
+		NewChunkConstraint(chunkConstraintL1CommitCalldataSize, maxL1CommitCalldataSize, func(chunk *encoding.Chunk) (uint64, error) {
+			totalL1CommitCalldataSize, err := codecv0.EstimateChunkL1CommitCalldataSize(chunk)
+			if err != nil {
+				return 0, fmt.Errorf("failed to estimate chunk L1 commit calldata size: %w", err)
+			}
+			return totalL1CommitCalldataSize, nil
+		}),
+		NewChunkConstraint(chunkConstraintRowConsumption, maxRowConsumption, func(chunk *encoding.Chunk) (uint64, error) {
+			crcMax, err := chunk.CrcMax()
+			if err != nil {
+				return 0, fmt.Errorf("failed to get crc max: %w", err)
+			}
+			return crcMax, nil
+		}),
+	}
+}
+
+// checkChunkConstraints measures the chunk against the constraints, it returns the measured values by constraint name
+// and the first constraint whose limit is exceeded, nil if none.
+func checkChunkConstraints(chunk *encoding.Chunk, constraints []ChunkConstraint) (map[string]uint64, ChunkConstraint, error) {
+	values := make(map[string]uint64, len(constraints))
+	for _, c := range constraints {
+		value, err := c.Measure(chunk)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[c.Name()] = value
+		if value > c.Limit() {
+			return values, c, nil
+		}
+	}
+	return values, nil, nil
+}
@@ -2,7 +2,9 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -12,10 +14,12 @@ import (
 	"gorm.io/gorm"
 
 	"scroll-tech/common/forks"
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/encoding/codecv0"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/events"
 	"scroll-tech/rollup/internal/orm"
 )
 
@@ -28,13 +32,17 @@ type ChunkProposer struct {
 	l2BlockOrm *orm.L2Block
 
 	maxBlockNumPerChunk             uint64
-	maxTxNumPerChunk                uint64
 	maxL1CommitGasPerChunk          uint64
 	maxL1CommitCalldataSizePerChunk uint64
-	maxRowConsumptionPerChunk       uint64
 	chunkTimeoutSec                 uint64
-	gasCostIncreaseMultiplier       float64
+	maxL1MessageSkipsPerChunk       uint64
+	skipGuard                       *L1MessageSkipGuard
+	events                          *events.Bus
 	forkHeights                     []uint64
+	dynamicSizing                   *dynamicSizing
+	constraints                     []ChunkConstraint
+	// pendingConfig is the config passed to UpdateConfig, nil once applied.
+	pendingConfig atomic.Pointer[config.ChunkProposerConfig]
 
 	chunkProposerCircleTotal           prometheus.Counter
 	proposeChunkFailureTotal           prometheus.Counter
@@ -47,6 +55,13 @@ type ChunkProposer struct {
 	chunkBlocksNum                     prometheus.Gauge
 	chunkFirstBlockTimeoutReached      prometheus.Counter
 	chunkBlocksProposeNotEnoughTotal   prometheus.Counter
+	chunkDynamicFillRatio              prometheus.Gauge
+	chunkDynamicTimeoutSec             prometheus.Gauge
+	chunkDynamicTargetReachedTotal     prometheus.Counter
+	chunkBoundTotal                    *prometheus.CounterVec
+	chunkL1MessageSkips                prometheus.Gauge
+	chunkL1MessageSkipsTotal           prometheus.Counter
+	chunkL1MessageSkipHeldTotal        prometheus.Counter
 }
 
 // NewChunkProposer creates a new ChunkProposer instance.
@@ -59,6 +74,8 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 		"maxRowConsumptionPerChunk", cfg.MaxRowConsumptionPerChunk,
 		"chunkTimeoutSec", cfg.ChunkTimeoutSec,
 		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
+		"maxL1MessageSkipsPerChunk", cfg.MaxL1MessageSkipsPerChunk,
+		"dynamicSizing", cfg.DynamicSizing != nil,
 		"forkHeights", forkHeights)
 
 	return &ChunkProposer{
@@ -67,13 +84,15 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 		chunkOrm:                        orm.NewChunk(db),
 		l2BlockOrm:                      orm.NewL2Block(db),
 		maxBlockNumPerChunk:             cfg.MaxBlockNumPerChunk,
-		maxTxNumPerChunk:                cfg.MaxTxNumPerChunk,
 		maxL1CommitGasPerChunk:          cfg.MaxL1CommitGasPerChunk,
 		maxL1CommitCalldataSizePerChunk: cfg.MaxL1CommitCalldataSizePerChunk,
-		maxRowConsumptionPerChunk:       cfg.MaxRowConsumptionPerChunk,
 		chunkTimeoutSec:                 cfg.ChunkTimeoutSec,
-		gasCostIncreaseMultiplier:       cfg.GasCostIncreaseMultiplier,
+		maxL1MessageSkipsPerChunk:       cfg.MaxL1MessageSkipsPerChunk,
+		skipGuard:                       NewL1MessageSkipGuard(),
 		forkHeights:                     forkHeights,
+		dynamicSizing:                   newDynamicSizing(cfg.DynamicSizing, db),
+		constraints: defaultChunkConstraints(cfg.MaxTxNumPerChunk, cfg.MaxL1CommitGasPerChunk, cfg.MaxL1CommitCalldataSizePerChunk,
+			cfg.MaxRowConsumptionPerChunk, cfg.GasCostIncreaseMultiplier),
 
 		chunkProposerCircleTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "rollup_propose_chunk_circle_total",
@@ -119,44 +138,132 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 			Name: "rollup_propose_chunk_blocks_propose_not_enough_total",
 			Help: "Total number of chunk block propose not enough",
 		}),
+		chunkDynamicFillRatio: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_chunk_dynamic_fill_ratio",
+			Help: "The fraction of the l1 commit gas and calldata size limits targeted by the chunk at the current l1 fees",
+		}),
+		chunkDynamicTimeoutSec: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_chunk_dynamic_timeout_sec",
+			Help: "The chunk timeout in seconds at the current l1 fees",
+		}),
+		chunkDynamicTargetReachedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_dynamic_target_reached_total",
+			Help: "Total times a chunk was proposed on reaching the size targeted at the current l1 fees",
+		}),
+		chunkBoundTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_bound_total",
+			Help: "Total number of proposed chunks by the constraint or reason which ended the chunk",
+		}, []string{"constraint"}),
+		chunkL1MessageSkips: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_chunk_l1_message_skips",
+			Help: "The number of L1 messages skipped in the last proposed chunk",
+		}),
+		chunkL1MessageSkipsTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_l1_message_skips_total",
+			Help: "Total number of L1 messages skipped in the proposed chunks",
+		}),
+		chunkL1MessageSkipHeldTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_l1_message_skip_held_total",
+			Help: "Total number of chunk proposal rounds held for skipping more L1 messages than allowed",
+		}),
 	}
 }
 
+// AddConstraint adds a constraint to the proposed chunks, e.g. to enforce the limits of a new codec version.
+func (p *ChunkProposer) AddConstraint(c ChunkConstraint) {
+	p.constraints = append(p.constraints, c)
+}
+
+// SetL1MessageSkipGuard replaces the guard holding the chunks skipping more L1 messages than allowed, e.g. by a guard
+// shared with the admin api.
+func (p *ChunkProposer) SetL1MessageSkipGuard(g *L1MessageSkipGuard) {
+	p.skipGuard = g
+}
+
+// SetEventBus sets the bus the proposed chunks are published on.
+func (p *ChunkProposer) SetEventBus(bus *events.Bus) {
+	p.events = bus
+}
+
+// UpdateConfig replaces the limits of the chunks proposed from the next TryProposeChunk on, the dynamic sizing is kept.
+func (p *ChunkProposer) UpdateConfig(cfg *config.ChunkProposerConfig) {
+	p.pendingConfig.Store(cfg)
+}
+
+// applyPendingConfig applies the config passed to UpdateConfig, the constraints added by AddConstraint are kept.
+func (p *ChunkProposer) applyPendingConfig() {
+	cfg := p.pendingConfig.Swap(nil)
+	if cfg == nil {
+		return
+	}
+	p.maxBlockNumPerChunk = cfg.MaxBlockNumPerChunk
+	p.maxL1CommitGasPerChunk = cfg.MaxL1CommitGasPerChunk
+	p.maxL1CommitCalldataSizePerChunk = cfg.MaxL1CommitCalldataSizePerChunk
+	p.chunkTimeoutSec = cfg.ChunkTimeoutSec
+	p.maxL1MessageSkipsPerChunk = cfg.MaxL1MessageSkipsPerChunk
+	constraints := defaultChunkConstraints(cfg.MaxTxNumPerChunk, cfg.MaxL1CommitGasPerChunk, cfg.MaxL1CommitCalldataSizePerChunk,
+		cfg.MaxRowConsumptionPerChunk, cfg.GasCostIncreaseMultiplier)
+	p.constraints = append(constraints, p.constraints[len(constraints):]...)
+	log.Info("chunk proposer config updated",
+		"maxBlockNumPerChunk", cfg.MaxBlockNumPerChunk,
+		"maxTxNumPerChunk", cfg.MaxTxNumPerChunk,
+		"maxL1CommitGasPerChunk", cfg.MaxL1CommitGasPerChunk,
+		"maxL1CommitCalldataSizePerChunk", cfg.MaxL1CommitCalldataSizePerChunk,
+		"maxRowConsumptionPerChunk", cfg.MaxRowConsumptionPerChunk,
+		"chunkTimeoutSec", cfg.ChunkTimeoutSec,
+		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
+		"maxL1MessageSkipsPerChunk", cfg.MaxL1MessageSkipsPerChunk)
+}
+
 // TryProposeChunk tries to propose a new chunk.
 func (p *ChunkProposer) TryProposeChunk() {
+	p.applyPendingConfig()
 	p.chunkProposerCircleTotal.Inc()
+	ctx, span := tracing.Start(p.ctx, "chunk_proposer.propose_chunk")
+	defer span.End()
+
 	proposedChunk, err := p.proposeChunk()
 	if err != nil {
 		p.proposeChunkFailureTotal.Inc()
+		span.RecordError(err)
 		log.Error("propose new chunk failed", "err", err)
 		return
 	}
 
-	if err := p.updateChunkInfoInDB(proposedChunk); err != nil {
+	if err := p.updateChunkInfoInDB(ctx, proposedChunk); err != nil {
 		p.proposeChunkUpdateInfoFailureTotal.Inc()
+		span.RecordError(err)
 		log.Error("update chunk info in orm failed", "err", err)
 	}
 }
 
-func (p *ChunkProposer) updateChunkInfoInDB(chunk *encoding.Chunk) error {
+func (p *ChunkProposer) updateChunkInfoInDB(ctx context.Context, chunk *encoding.Chunk) error {
 	if chunk == nil {
 		return nil
 	}
 
 	p.proposeChunkUpdateInfoTotal.Inc()
+	var dbChunk *orm.Chunk
 	err := p.db.Transaction(func(dbTX *gorm.DB) error {
-		dbChunk, err := p.chunkOrm.InsertChunk(p.ctx, chunk, dbTX)
+		var err error
+		dbChunk, err = p.chunkOrm.InsertChunk(ctx, chunk, dbTX)
 		if err != nil {
 			log.Warn("ChunkProposer.InsertChunk failed", "err", err)
 			return err
 		}
-		if err := p.l2BlockOrm.UpdateChunkHashInRange(p.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber, dbChunk.Hash, dbTX); err != nil {
+		tracing.SpanFromContext(ctx).SetAttributes("chunk_index", dbChunk.Index, "chunk_hash", dbChunk.Hash)
+		ctx := tracing.WithCorrelationID(ctx, "chunk:"+dbChunk.Hash)
+		if err := p.l2BlockOrm.UpdateChunkHashInRange(ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber, dbChunk.Hash, dbTX); err != nil {
 			log.Error("failed to update chunk_hash for l2_blocks", "chunk hash", dbChunk.Hash, "start block", dbChunk.StartBlockNumber, "end block", dbChunk.EndBlockNumber, "err", err)
 			return err
 		}
 		return nil
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	p.events.Publish(events.Event{Type: events.ChunkProposed, Index: dbChunk.Index, Hash: dbChunk.Hash})
+	return nil
 }
 
 func (p *ChunkProposer) proposeChunk() (*encoding.Chunk, error) {
@@ -181,150 +288,150 @@ func (p *ChunkProposer) proposeChunk() (*encoding.Chunk, error) {
 		return nil, nil
 	}
 
+	totalL1MessagePoppedBefore, err := p.totalL1MessagePoppedBefore()
+	if err != nil {
+		return nil, err
+	}
+
+	// the size targets are soft, a chunk reaching them is proposed without waiting for more blocks or the timeout.
+	fillRatio, timeoutRatio, err := p.dynamicSizing.ratios(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetL1CommitGas := uint64(fillRatio * float64(p.maxL1CommitGasPerChunk))
+	targetL1CommitCalldataSize := uint64(fillRatio * float64(p.maxL1CommitCalldataSizePerChunk))
+	chunkTimeoutSec := uint64(timeoutRatio * float64(p.chunkTimeoutSec))
+	p.chunkDynamicFillRatio.Set(fillRatio)
+	p.chunkDynamicTimeoutSec.Set(float64(chunkTimeoutSec))
+
 	var chunk encoding.Chunk
+	var targetReached bool
 	for i, block := range blocks {
 		chunk.Blocks = append(chunk.Blocks, block)
 
-		crcMax, err := chunk.CrcMax()
+		values, exceeded, err := checkChunkConstraints(&chunk, p.constraints)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get crc max: %w", err)
+			return nil, err
 		}
 
-		totalTxNum := chunk.NumTransactions()
-
-		totalL1CommitCalldataSize, err := codecv0.EstimateChunkL1CommitCalldataSize(&chunk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate chunk L1 commit calldata size: %w", err)
-		}
-
-		totalL1CommitGas, err := codecv0.EstimateChunkL1CommitGas(&chunk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate chunk L1 commit gas: %w", err)
-		}
-
-		totalOverEstimateL1CommitGas := uint64(p.gasCostIncreaseMultiplier * float64(totalL1CommitGas))
-
-		if totalTxNum > p.maxTxNumPerChunk ||
-			totalL1CommitCalldataSize > p.maxL1CommitCalldataSizePerChunk ||
-			totalOverEstimateL1CommitGas > p.maxL1CommitGasPerChunk ||
-			crcMax > p.maxRowConsumptionPerChunk {
+		if exceeded != nil {
 			// Check if the first block breaks hard limits.
 			// If so, it indicates there are bugs in sequencer, manual fix is needed.
 			if i == 0 {
-				if totalTxNum > p.maxTxNumPerChunk {
-					return nil, fmt.Errorf(
-						"the first block exceeds l2 tx number limit; block number: %v, number of transactions: %v, max transaction number limit: %v",
-						block.Header.Number,
-						totalTxNum,
-						p.maxTxNumPerChunk,
-					)
-				}
-
-				if totalOverEstimateL1CommitGas > p.maxL1CommitGasPerChunk {
-					return nil, fmt.Errorf(
-						"the first block exceeds l1 commit gas limit; block number: %v, commit gas: %v, max commit gas limit: %v",
-						block.Header.Number,
-						totalL1CommitGas,
-						p.maxL1CommitGasPerChunk,
-					)
-				}
-
-				if totalL1CommitCalldataSize > p.maxL1CommitCalldataSizePerChunk {
-					return nil, fmt.Errorf(
-						"the first block exceeds l1 commit calldata size limit; block number: %v, calldata size: %v, max calldata size limit: %v",
-						block.Header.Number,
-						totalL1CommitCalldataSize,
-						p.maxL1CommitCalldataSizePerChunk,
-					)
-				}
-
-				if crcMax > p.maxRowConsumptionPerChunk {
-					return nil, fmt.Errorf(
-						"the first block exceeds row consumption limit; block number: %v, crc max: %v, limit: %v",
-						block.Header.Number,
-						crcMax,
-						p.maxRowConsumptionPerChunk,
-					)
-				}
+				return nil, fmt.Errorf(
+					"the first block exceeds %s limit; block number: %v, value: %v, limit: %v",
+					exceeded.Name(),
+					block.Header.Number,
+					values[exceeded.Name()],
+					exceeded.Limit(),
+				)
 			}
 
 			log.Debug("breaking limit condition in chunking",
-				"totalTxNum", totalTxNum,
-				"maxTxNumPerChunk", p.maxTxNumPerChunk,
-				"currentL1CommitCalldataSize", totalL1CommitCalldataSize,
-				"maxL1CommitCalldataSizePerChunk", p.maxL1CommitCalldataSizePerChunk,
-				"currentOverEstimateL1CommitGas", totalOverEstimateL1CommitGas,
-				"maxL1CommitGasPerChunk", p.maxL1CommitGasPerChunk,
-				"chunkRowConsumptionMax", crcMax,
-				"p.maxRowConsumptionPerChunk", p.maxRowConsumptionPerChunk)
+				"constraint", exceeded.Name(),
+				"value", values[exceeded.Name()],
+				"limit", exceeded.Limit())
 
 			chunk.Blocks = chunk.Blocks[:len(chunk.Blocks)-1]
+			return p.proposedChunk(&chunk, exceeded.Name(), totalL1MessagePoppedBefore)
+		}
 
-			crcMax, err := chunk.CrcMax()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get crc max: %w", err)
-			}
-
-			totalL1CommitCalldataSize, err := codecv0.EstimateChunkL1CommitCalldataSize(&chunk)
-			if err != nil {
-				return nil, fmt.Errorf("failed to estimate chunk L1 commit calldata size: %w", err)
+		// the skipped messages are chosen by the sequencer, a chunk exceeding the limit with its first block is held.
+		if p.maxL1MessageSkipsPerChunk != 0 {
+			skipped := skippedL1Messages(&chunk, totalL1MessagePoppedBefore)
+			if i == 0 && !p.skipGuard.allow(L1MessageSkipProposalChunk, block.Header.Number.Uint64(), skipped, p.maxL1MessageSkipsPerChunk) {
+				p.chunkL1MessageSkipHeldTotal.Inc()
+				return nil, nil
 			}
+			if i != 0 && skipped > p.maxL1MessageSkipsPerChunk {
+				log.Debug("breaking l1 message skip limit in chunking",
+					"skipped", skipped,
+					"limit", p.maxL1MessageSkipsPerChunk)
 
-			totalL1CommitGas, err := codecv0.EstimateChunkL1CommitGas(&chunk)
-			if err != nil {
-				return nil, fmt.Errorf("failed to estimate chunk L1 commit gas: %w", err)
+				chunk.Blocks = chunk.Blocks[:len(chunk.Blocks)-1]
+				return p.proposedChunk(&chunk, chunkBoundL1MessageSkips, totalL1MessagePoppedBefore)
 			}
+		}
 
-			p.chunkTxNum.Set(float64(chunk.NumTransactions()))
-			p.chunkEstimateL1CommitGas.Set(float64(totalL1CommitCalldataSize))
-			p.totalL1CommitCalldataSize.Set(float64(totalL1CommitGas))
-			p.maxTxConsumption.Set(float64(crcMax))
-			p.chunkBlocksNum.Set(float64(len(chunk.Blocks)))
-			return &chunk, nil
+		if p.dynamicSizing != nil && (values[chunkConstraintL1CommitGas] >= targetL1CommitGas || values[chunkConstraintL1CommitCalldataSize] >= targetL1CommitCalldataSize) {
+			targetReached = true
+			break
 		}
 	}
 
 	currentTimeSec := uint64(time.Now().Unix())
-	if chunk.Blocks[0].Header.Time+p.chunkTimeoutSec < currentTimeSec ||
-		uint64(len(chunk.Blocks)) == maxBlocksThisChunk {
-		if chunk.Blocks[0].Header.Time+p.chunkTimeoutSec < currentTimeSec {
+	if chunk.Blocks[0].Header.Time+chunkTimeoutSec < currentTimeSec ||
+		uint64(len(chunk.Blocks)) == maxBlocksThisChunk || targetReached {
+		var bound string
+		if targetReached {
+			log.Info("reached target size of chunk at current l1 fees",
+				"start block number", chunk.Blocks[0].Header.Number,
+				"block count", len(chunk.Blocks),
+				"fill ratio", fillRatio,
+			)
+			p.chunkDynamicTargetReachedTotal.Inc()
+			bound = chunkBoundDynamicTarget
+		} else if chunk.Blocks[0].Header.Time+chunkTimeoutSec < currentTimeSec {
 			log.Warn("first block timeout",
 				"block number", chunk.Blocks[0].Header.Number,
 				"block timestamp", chunk.Blocks[0].Header.Time,
 				"current time", currentTimeSec,
 			)
+			bound = chunkBoundTimeout
 		} else {
 			log.Info("reached maximum number of blocks in chunk",
 				"start block number", chunk.Blocks[0].Header.Number,
 				"block count", len(chunk.Blocks),
 			)
-		}
-
-		crcMax, err := chunk.CrcMax()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get crc max: %w", err)
-		}
-
-		totalL1CommitCalldataSize, err := codecv0.EstimateChunkL1CommitCalldataSize(&chunk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate chunk L1 commit calldata size: %w", err)
-		}
-
-		totalL1CommitGas, err := codecv0.EstimateChunkL1CommitGas(&chunk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate chunk L1 commit gas: %w", err)
+			bound = chunkBoundBlockNum
 		}
 
 		p.chunkFirstBlockTimeoutReached.Inc()
-		p.chunkTxNum.Set(float64(chunk.NumTransactions()))
-		p.chunkEstimateL1CommitGas.Set(float64(totalL1CommitCalldataSize))
-		p.totalL1CommitCalldataSize.Set(float64(totalL1CommitGas))
-		p.maxTxConsumption.Set(float64(crcMax))
-		p.chunkBlocksNum.Set(float64(len(chunk.Blocks)))
-		return &chunk, nil
+		return p.proposedChunk(&chunk, bound, totalL1MessagePoppedBefore)
 	}
 
 	log.Debug("pending blocks do not reach one of the constraints or contain a timeout block")
 	p.chunkBlocksProposeNotEnoughTotal.Inc()
 	return nil, nil
 }
+
+// totalL1MessagePoppedBefore returns the number of L1 messages popped before the next chunk.
+func (p *ChunkProposer) totalL1MessagePoppedBefore() (uint64, error) {
+	parentChunk, err := p.chunkOrm.GetLatestChunk(p.ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return parentChunk.TotalL1MessagesPoppedBefore + parentChunk.TotalL1MessagesPoppedInChunk, nil
+}
+
+// proposedChunk records the metrics of the chunk ended by the given constraint or reason and returns it.
+func (p *ChunkProposer) proposedChunk(chunk *encoding.Chunk, bound string, totalL1MessagePoppedBefore uint64) (*encoding.Chunk, error) {
+	crcMax, err := chunk.CrcMax()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crc max: %w", err)
+	}
+
+	totalL1CommitCalldataSize, err := codecv0.EstimateChunkL1CommitCalldataSize(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate chunk L1 commit calldata size: %w", err)
+	}
+
+	totalL1CommitGas, err := codecv0.EstimateChunkL1CommitGas(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate chunk L1 commit gas: %w", err)
+	}
+
+	p.chunkBoundTotal.WithLabelValues(bound).Inc()
+	p.chunkTxNum.Set(float64(chunk.NumTransactions()))
+	p.chunkEstimateL1CommitGas.Set(float64(totalL1CommitGas))
+	p.totalL1CommitCalldataSize.Set(float64(totalL1CommitCalldataSize))
+	p.maxTxConsumption.Set(float64(crcMax))
+	p.chunkBlocksNum.Set(float64(len(chunk.Blocks)))
+	skipped := skippedL1Messages(chunk, totalL1MessagePoppedBefore)
+	p.chunkL1MessageSkips.Set(float64(skipped))
+	p.chunkL1MessageSkipsTotal.Add(float64(skipped))
+	return chunk, nil
+}
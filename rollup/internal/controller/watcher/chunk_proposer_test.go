@@ -198,3 +198,122 @@ func testChunkProposerLimits(t *testing.T) {
 		})
 	}
 }
+
+func testChunkProposerDynamicSizing(t *testing.T) {
+	tests := []struct {
+		name                       string
+		l1Block                    *orm.L1Block
+		minFillRatio               float64
+		minTimeoutRatio            float64
+		expectedChunksLen          int
+		expectedBlocksInFirstChunk int // only be checked when expectedChunksLen > 0
+	}{
+		{
+			name:              "NoL1Block",
+			minFillRatio:      1e-9,
+			minTimeoutRatio:   1,
+			expectedChunksLen: 0,
+		},
+		{
+			name:              "ExpensiveFees",
+			l1Block:           &orm.L1Block{Number: 1, Hash: "hash1", BaseFee: 1000},
+			minFillRatio:      1e-9,
+			minTimeoutRatio:   1e-12,
+			expectedChunksLen: 0,
+		},
+		{
+			name:                       "CheapFeesTargetReached",
+			l1Block:                    &orm.L1Block{Number: 1, Hash: "hash1", BaseFee: 1},
+			minFillRatio:               1e-9,
+			minTimeoutRatio:            1,
+			expectedChunksLen:          1,
+			expectedBlocksInFirstChunk: 1,
+		},
+		{
+			name:                       "CheapFeesTimeoutReached",
+			l1Block:                    &orm.L1Block{Number: 1, Hash: "hash1", BaseFee: 1},
+			minFillRatio:               1,
+			minTimeoutRatio:            1e-12,
+			expectedChunksLen:          1,
+			expectedBlocksInFirstChunk: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupDB(t)
+			defer database.CloseDB(db)
+
+			l2BlockOrm := orm.NewL2Block(db)
+			err := l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+			assert.NoError(t, err)
+			if tt.l1Block != nil {
+				err = orm.NewL1Block(db).InsertL1Blocks(context.Background(), []orm.L1Block{*tt.l1Block})
+				assert.NoError(t, err)
+			}
+
+			cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+				MaxBlockNumPerChunk:             10,
+				MaxTxNumPerChunk:                10000,
+				MaxL1CommitGasPerChunk:          50000000000,
+				MaxL1CommitCalldataSizePerChunk: 1000000,
+				MaxRowConsumptionPerChunk:       1000000,
+				ChunkTimeoutSec:                 1 << 40,
+				GasCostIncreaseMultiplier:       1.2,
+				DynamicSizing: &config.DynamicSizingConfig{
+					LowFee:          10,
+					HighFee:         100,
+					MinFillRatio:    tt.minFillRatio,
+					MinTimeoutRatio: tt.minTimeoutRatio,
+				},
+			}, &params.ChainConfig{}, db, nil)
+			cp.TryProposeChunk()
+
+			chunkOrm := orm.NewChunk(db)
+			chunks, err := chunkOrm.GetChunksGEIndex(context.Background(), 0, 0)
+			assert.NoError(t, err)
+			assert.Len(t, chunks, tt.expectedChunksLen)
+
+			if len(chunks) > 0 {
+				blockOrm := orm.NewL2Block(db)
+				chunkHashes, err := blockOrm.GetChunkHashes(context.Background(), tt.expectedBlocksInFirstChunk)
+				assert.NoError(t, err)
+				assert.Len(t, chunkHashes, tt.expectedBlocksInFirstChunk)
+				firstChunkHash := chunks[0].Hash
+				for _, chunkHash := range chunkHashes {
+					assert.Equal(t, firstChunkHash, chunkHash)
+				}
+			}
+		})
+	}
+}
+
+func testChunkProposerConstraint(t *testing.T) {
+	db := setupDB(t)
+	defer database.CloseDB(db)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	err := l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+	assert.NoError(t, err)
+
+	cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+		MaxBlockNumPerChunk:             100,
+		MaxTxNumPerChunk:                10000,
+		MaxL1CommitGasPerChunk:          50000000000,
+		MaxL1CommitCalldataSizePerChunk: 1000000,
+		MaxRowConsumptionPerChunk:       1000000,
+		ChunkTimeoutSec:                 1000000000000,
+		GasCostIncreaseMultiplier:       1.2,
+	}, &params.ChainConfig{}, db, nil)
+
+	// The added constraint ends the chunk before the built-in limits are reached.
+	cp.AddConstraint(NewChunkConstraint("block_count", 1, func(chunk *encoding.Chunk) (uint64, error) {
+		return uint64(len(chunk.Blocks)), nil
+	}))
+	cp.TryProposeChunk()
+
+	chunks, err := orm.NewChunk(db).GetChunksGEIndex(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, uint64(1), chunks[0].EndBlockNumber-chunks[0].StartBlockNumber+1)
+}
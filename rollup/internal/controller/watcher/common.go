@@ -1,3 +1,11 @@
 package watcher
 
 const contractEventsBlocksFetchLimit = int64(10)
+
+// blocksBehind returns the number of confirmed blocks above the processed height.
+func blocksBehind(confirmed, processed uint64) uint64 {
+	if confirmed <= processed {
+		return 0
+	}
+	return confirmed - processed
+}
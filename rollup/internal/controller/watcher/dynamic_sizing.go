@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// dynamicSizing derives the fill ratio of the size limits and the ratio of the timeout of a proposer from the fees of
+// the latest L1 block. A nil dynamicSizing keeps the configured limits and timeout.
+type dynamicSizing struct {
+	cfg        *config.DynamicSizingConfig
+	l1BlockOrm *orm.L1Block
+}
+
+func newDynamicSizing(cfg *config.DynamicSizingConfig, db *gorm.DB) *dynamicSizing {
+	if cfg == nil {
+		return nil
+	}
+	return &dynamicSizing{cfg: cfg, l1BlockOrm: orm.NewL1Block(db)}
+}
+
+// ratios returns the fill ratio and the timeout ratio for the current L1 fees, both are 1 when dynamic sizing is
+// disabled or when no L1 block is known yet.
+func (d *dynamicSizing) ratios(ctx context.Context) (float64, float64, error) {
+	if d == nil {
+		return 1, 1, nil
+	}
+
+	l1Block, err := d.l1BlockOrm.GetLatestL1Block(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get latest L1 block: %w", err)
+	}
+	if l1Block == nil {
+		log.Warn("no L1 block to adapt proposal sizes, using the configured limits")
+		return 1, 1, nil
+	}
+
+	fee := l1Block.BaseFee
+	fillRatio, timeoutRatio := dynamicSizingRatios(d.cfg, fee)
+	log.Debug("adapted proposal sizes to L1 fees", "l1 block", l1Block.Number, "fee", fee, "fill ratio", fillRatio, "timeout ratio", timeoutRatio)
+	return fillRatio, timeoutRatio, nil
+}
+
+// dynamicSizingRatios interpolates the fill ratio and the timeout ratio linearly between their minimum at LowFee and 1
+// at HighFee.
+func dynamicSizingRatios(cfg *config.DynamicSizingConfig, fee uint64) (float64, float64) {
+	level := 1.0
+	if fee <= cfg.LowFee {
+		level = 0
+	} else if fee < cfg.HighFee {
+		level = float64(fee-cfg.LowFee) / float64(cfg.HighFee-cfg.LowFee)
+	}
+	return cfg.MinFillRatio + level*(1-cfg.MinFillRatio), cfg.MinTimeoutRatio + level*(1-cfg.MinTimeoutRatio)
+}
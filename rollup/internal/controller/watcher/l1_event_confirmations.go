@@ -0,0 +1,194 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// l1EventClass is a class of contract events acted on at its own confirmations.
+type l1EventClass struct {
+	name          string
+	confirmations rpc.BlockNumber
+	address       common.Address
+	topics        []common.Hash
+	// messages is set for the class of the L1 messages, which are unwound on a reorg.
+	messages bool
+
+	// lookaheadHeight is the last block whose events of the class were processed above the processed height of the
+	// watcher, 0 when the class is not ahead. lookaheadHash is the hash of that block.
+	lookaheadHeight uint64
+	lookaheadHash   common.Hash
+}
+
+// UseEventConfirmations acts on the L1 messages and on the rollup events at their own confirmations instead of the
+// watcher confirmations. The processed height of the watcher follows the class confirmed last, the events of the
+// other class are processed ahead of it and processed again, idempotently, once every class confirms their blocks.
+func (w *L1WatcherClient) UseEventConfirmations(messages, rollupEvents rpc.BlockNumber) {
+	w.eventClasses = []*l1EventClass{
+		{
+			name:          "l1_messages",
+			confirmations: messages,
+			address:       w.messageQueueAddress,
+			topics:        []common.Hash{bridgeAbi.L1QueueTransactionEventSignature},
+			messages:      true,
+		},
+		{
+			name:          "rollup_events",
+			confirmations: rollupEvents,
+			address:       w.scrollChainAddress,
+			topics:        []common.Hash{bridgeAbi.L1CommitBatchEventSignature, bridgeAbi.L1FinalizeBatchEventSignature},
+		},
+	}
+
+	// The L1 messages processed ahead are stored, the watcher resumes from the last block processed for every class.
+	processed, err := w.l1ProcessedBlockOrm.GetLatestL1ProcessedBlockHeight(w.ctx)
+	if err != nil {
+		log.Warn("Failed to fetch the latest L1 processed block height from db", "err", err)
+		return
+	}
+	if processed >= w.startHeight && processed < w.processedMsgHeight {
+		w.processedMsgHeight = processed
+	}
+}
+
+// confirmedHeight returns the confirmed height of the confirmations, derived from the subscription when possible.
+func (w *L1WatcherClient) confirmedHeight(ctx context.Context, confirmations rpc.BlockNumber) (uint64, error) {
+	if height, ok := w.subscription.confirmedHeight(confirmations); ok {
+		return height, nil
+	}
+	return utils.GetLatestConfirmedBlockNumber(ctx, w.client, confirmations)
+}
+
+// contractEventsHeight returns the height up to which every contract event is confirmed, along with the confirmed
+// height of each event class.
+func (w *L1WatcherClient) contractEventsHeight(ctx context.Context) (uint64, []uint64, error) {
+	if len(w.eventClasses) == 0 {
+		height, err := w.confirmedHeight(ctx, w.confirmations)
+		return height, nil, err
+	}
+
+	heights := make([]uint64, len(w.eventClasses))
+	for i, class := range w.eventClasses {
+		height, err := w.confirmedHeight(ctx, class.confirmations)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to get the confirmed height of the %s: %w", class.name, err)
+		}
+		heights[i] = height
+	}
+	height := heights[0]
+	for _, h := range heights[1:] {
+		if h < height {
+			height = h
+		}
+	}
+	return height, heights, nil
+}
+
+// fetchLookaheadEvents processes the events of each class confirmed above the processed height of the watcher.
+func (w *L1WatcherClient) fetchLookaheadEvents(heights []uint64) error {
+	for i, class := range w.eventClasses {
+		if err := w.fetchLookahead(class, heights[i]); err != nil {
+			return fmt.Errorf("failed to fetch the %s ahead: %w", class.name, err)
+		}
+	}
+	return nil
+}
+
+func (w *L1WatcherClient) fetchLookahead(class *l1EventClass, height uint64) error {
+	// The watcher caught up with the class, its blocks are processed again from the processed height.
+	if class.lookaheadHeight <= w.processedMsgHeight {
+		class.lookaheadHeight = 0
+	}
+	from := w.processedMsgHeight + 1
+	if class.lookaheadHeight != 0 {
+		from = class.lookaheadHeight + 1
+	}
+
+	for ; from <= height; from += uint64(contractEventsBlocksFetchLimit) {
+		to := from + uint64(contractEventsBlocksFetchLimit) - 1
+		if to > height {
+			to = height
+		}
+
+		fromHeader, err := w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(from))
+		if err != nil {
+			log.Warn("Failed to get block", "height", from, "err", err)
+			return err
+		}
+		if class.lookaheadHeight != 0 && fromHeader.ParentHash != class.lookaheadHash {
+			return w.unwindLookahead(class, from)
+		}
+		toHeader := fromHeader
+		if to != from {
+			if toHeader, err = w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(to)); err != nil {
+				log.Warn("Failed to get block", "height", to, "err", err)
+				return err
+			}
+		}
+
+		logs, err := w.filterLogs(w.ctx, geth.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{class.address},
+			Topics:    [][]common.Hash{class.topics},
+		})
+		if err != nil {
+			log.Warn("Failed to get event logs", "err", err)
+			return err
+		}
+		sentMessageEvents, rollupEvents, err := w.parseBridgeEventLogs(logs)
+		if err != nil {
+			log.Error("Failed to parse emitted events log", "err", err)
+			return err
+		}
+		if len(rollupEvents) > 0 {
+			updated, err := w.updateRollupStatuses(w.ctx, rollupEvents)
+			if err != nil {
+				return err
+			}
+			if !updated {
+				return nil
+			}
+		}
+		if err = w.l1MessageOrm.InsertMissingL1Messages(w.ctx, sentMessageEvents); err != nil {
+			return err
+		}
+
+		class.lookaheadHeight, class.lookaheadHash = to, toHeader.Hash()
+		w.metrics.l1WatcherFetchContractEventLookaheadHeight.WithLabelValues(class.name).Set(float64(to))
+	}
+	return nil
+}
+
+// unwindLookahead unwinds the L1 messages processed ahead when the block number does not chain to the last block
+// processed ahead, the next FetchContractEvent processes them again from the processed height of the watcher.
+// The rollup statuses only move forward, the re-emitted rollup events apply again.
+func (w *L1WatcherClient) unwindLookahead(class *l1EventClass, number uint64) error {
+	log.Warn("L1 reorg detected above the processed height, unwinding the contract events processed ahead", "class", class.name,
+		"block number", number, "lookahead height", class.lookaheadHeight, "processed height", w.processedMsgHeight)
+	if class.messages {
+		if err := w.l1MessageOrm.DeleteL1MessagesAfterHeight(w.ctx, w.processedMsgHeight); err != nil {
+			return fmt.Errorf("failed to unwind the l1 messages processed after block %d: %w", w.processedMsgHeight, err)
+		}
+	}
+	w.metrics.l1WatcherReorgTotal.Inc()
+	w.metrics.l1WatcherReorgDepth.Set(float64(number - 1 - w.processedMsgHeight))
+	class.lookaheadHeight = 0
+	return nil
+}
+
+// resetLookahead forgets the events processed ahead, e.g. once they were unwound by a reorg.
+func (w *L1WatcherClient) resetLookahead() {
+	for _, class := range w.eventClasses {
+		class.lookaheadHeight = 0
+	}
+}
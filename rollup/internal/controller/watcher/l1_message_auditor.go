@@ -0,0 +1,268 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// ContractCaller calls the contracts of a chain at a given block.
+type ContractCaller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// L1MessageAuditor cross-checks the stored L1 messages with the L1MessageQueue contract at the latest block processed
+// by the L1 watcher: the next queue index and the hashes of the latest messages of the window. An audit changing the
+// audit state is stored, the message relaying is halted while the latest stored audit diverged.
+type L1MessageAuditor struct {
+	ctx context.Context
+	cfg *config.L1MessageAuditConfig
+
+	client              ContractCaller
+	messageQueueAddress common.Address
+	messageQueueABI     *abi.ABI
+
+	l1MessageOrm        *orm.L1Message
+	l1ProcessedBlockOrm *orm.L1ProcessedBlock
+	l1MessageAuditOrm   *orm.L1MessageAudit
+
+	auditsTotal              *prometheus.CounterVec
+	auditFailuresTotal       prometheus.Counter
+	diverged                 prometheus.Gauge
+	localNextQueueIndex      prometheus.Gauge
+	onchainNextQueueIndex    prometheus.Gauge
+	latestAuditedBlockNumber prometheus.Gauge
+}
+
+// NewL1MessageAuditor creates a new L1MessageAuditor instance.
+func NewL1MessageAuditor(ctx context.Context, cfg *config.L1MessageAuditConfig, client ContractCaller, messageQueueAddress common.Address, db *gorm.DB, reg prometheus.Registerer) *L1MessageAuditor {
+	log.Info("new l1 message auditor", "windowSize", cfg.WindowSize, "messageQueueAddress", messageQueueAddress.Hex())
+
+	return &L1MessageAuditor{
+		ctx:                 ctx,
+		cfg:                 cfg,
+		client:              client,
+		messageQueueAddress: messageQueueAddress,
+		messageQueueABI:     bridgeAbi.L1MessageQueueABI,
+		l1MessageOrm:        orm.NewL1Message(db),
+		l1ProcessedBlockOrm: orm.NewL1ProcessedBlock(db),
+		l1MessageAuditOrm:   orm.NewL1MessageAudit(db),
+
+		auditsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_l1_message_audits_total",
+			Help: "The total number of audits of the stored l1 messages against the message queue contract, by result",
+		}, []string{"result"}),
+		auditFailuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_message_audit_failures_total",
+			Help: "The total number of l1 message audits which failed to complete, e.g. on l1 endpoint errors",
+		}),
+		diverged: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_message_audit_diverged",
+			Help: "Whether the stored l1 messages diverge from the message queue contract, the message relaying is halted while it is 1",
+		}),
+		localNextQueueIndex: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_message_audit_local_next_queue_index",
+			Help: "The queue index following the stored l1 messages at the latest audited block",
+		}),
+		onchainNextQueueIndex: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_message_audit_onchain_next_queue_index",
+			Help: "The next queue index of the message queue contract at the latest audited block",
+		}),
+		latestAuditedBlockNumber: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_message_audit_latest_block_number",
+			Help: "The latest l1 block the stored l1 messages were audited at",
+		}),
+	}
+}
+
+// TryAuditL1Messages audits the stored L1 messages at the latest block processed by the L1 watcher, and stores the
+// audit if its result differs from the latest stored audit.
+func (a *L1MessageAuditor) TryAuditL1Messages() {
+	height, err := a.l1ProcessedBlockOrm.GetLatestL1ProcessedBlockHeight(a.ctx)
+	if err != nil {
+		log.Error("failed to get latest l1 processed block height", "err", err)
+		return
+	}
+	if height == 0 {
+		return
+	}
+
+	audit, err := a.audit(height)
+	if err != nil {
+		a.auditFailuresTotal.Inc()
+		log.Warn("failed to audit l1 messages", "height", height, "err", err)
+		return
+	}
+	a.localNextQueueIndex.Set(float64(audit.LocalNextIndex))
+	a.onchainNextQueueIndex.Set(float64(audit.OnchainNextIndex))
+	a.latestAuditedBlockNumber.Set(float64(height))
+	if audit.Diverged {
+		a.auditsTotal.WithLabelValues("diverged").Inc()
+		a.diverged.Set(1)
+		log.Error("stored l1 messages diverge from the message queue contract, message relaying is halted", "height", height,
+			"local next index", audit.LocalNextIndex, "onchain next index", audit.OnchainNextIndex,
+			"divergent queue index", audit.DivergentQueueIndex, "reason", audit.Reason)
+	} else {
+		a.auditsTotal.WithLabelValues("consistent").Inc()
+		a.diverged.Set(0)
+	}
+
+	latest, err := a.l1MessageAuditOrm.GetLatestL1MessageAudit(a.ctx)
+	if err != nil {
+		log.Error("failed to get latest l1 message audit", "err", err)
+		return
+	}
+	// the first audits are not stored as long as the messages are consistent, nothing is halted.
+	if (latest == nil && !audit.Diverged) || (latest != nil && latest.Diverged == audit.Diverged) {
+		return
+	}
+	if err = a.l1MessageAuditOrm.InsertL1MessageAudit(a.ctx, audit); err != nil {
+		log.Error("failed to insert l1 message audit", "height", height, "diverged", audit.Diverged, "err", err)
+		return
+	}
+	if !audit.Diverged {
+		log.Info("stored l1 messages consistent with the message queue contract again, message relaying is resumed", "height", height,
+			"next index", audit.LocalNextIndex)
+	}
+}
+
+// audit compares the stored L1 messages with the message queue contract at the block.
+func (a *L1MessageAuditor) audit(height uint64) (*orm.L1MessageAudit, error) {
+	blockNumber := new(big.Int).SetUint64(height)
+	onchainNext, err := a.nextCrossDomainMessageIndex(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	localNext, err := a.l1MessageOrm.GetNextQueueIndex(a.ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &orm.L1MessageAudit{L1BlockNumber: height, LocalNextIndex: localNext, OnchainNextIndex: onchainNext}
+	if localNext != onchainNext {
+		audit.Diverged = true
+		audit.DivergentQueueIndex = localNext
+		if onchainNext < localNext {
+			audit.DivergentQueueIndex = onchainNext
+		}
+		audit.Reason = fmt.Sprintf("next queue index mismatch, local: %d, onchain: %d", localNext, onchainNext)
+		return audit, nil
+	}
+
+	start := uint64(0)
+	if localNext > a.cfg.WindowSize {
+		start = localNext - a.cfg.WindowSize
+	}
+	messages, err := a.l1MessageOrm.GetL1MessagesInQueueRange(a.ctx, start, localNext, height)
+	if err != nil {
+		return nil, err
+	}
+	onchainHashes := make([]common.Hash, 0, localNext-start)
+	for queueIndex := start; queueIndex < localNext; queueIndex++ {
+		hash, err := a.crossDomainMessageHash(queueIndex, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		onchainHashes = append(onchainHashes, hash)
+	}
+
+	if queueIndex, reason := compareL1Messages(start, messages, onchainHashes); reason != "" {
+		audit.Diverged = true
+		audit.DivergentQueueIndex = queueIndex
+		audit.Reason = reason
+	}
+	return audit, nil
+}
+
+// compareL1Messages compares the stored messages with the hashes of the messages of the contract from the start
+// queue index, it returns the first divergent queue index along with the reason, an empty reason if they match.
+func compareL1Messages(start uint64, messages []*orm.L1Message, onchainHashes []common.Hash) (uint64, string) {
+	next := 0
+	for i, onchainHash := range onchainHashes {
+		queueIndex := start + uint64(i)
+		if next >= len(messages) || messages[next].QueueIndex != queueIndex {
+			return queueIndex, "message missing in the db"
+		}
+		msg := messages[next]
+		next++
+		if next < len(messages) && messages[next].QueueIndex == queueIndex {
+			return queueIndex, "duplicated message in the db"
+		}
+
+		calldata := common.FromHex(msg.Calldata)
+		if common.BytesToHash(crypto.Keccak256(calldata)) != common.HexToHash(msg.MsgHash) {
+			return queueIndex, "stored message hash does not match the stored calldata"
+		}
+		value, ok := new(big.Int).SetString(msg.Value, 10)
+		if !ok {
+			return queueIndex, fmt.Sprintf("invalid stored message value: %q", msg.Value)
+		}
+		target := common.HexToAddress(msg.Target)
+		txHash := gethTypes.NewTx(&gethTypes.L1MessageTx{
+			QueueIndex: msg.QueueIndex,
+			Gas:        msg.GasLimit,
+			To:         &target,
+			Value:      value,
+			Data:       calldata,
+			Sender:     common.HexToAddress(msg.Sender),
+		}).Hash()
+		if txHash != onchainHash {
+			return queueIndex, fmt.Sprintf("message hash mismatch, local: %s, onchain: %s", txHash.Hex(), onchainHash.Hex())
+		}
+	}
+	return 0, ""
+}
+
+func (a *L1MessageAuditor) nextCrossDomainMessageIndex(blockNumber *big.Int) (uint64, error) {
+	output, err := a.call(blockNumber, "nextCrossDomainMessageIndex")
+	if err != nil {
+		return 0, err
+	}
+	index, ok := output[0].(*big.Int)
+	if !ok || !index.IsUint64() {
+		return 0, fmt.Errorf("unexpected nextCrossDomainMessageIndex result: %v", output[0])
+	}
+	return index.Uint64(), nil
+}
+
+func (a *L1MessageAuditor) crossDomainMessageHash(queueIndex uint64, blockNumber *big.Int) (common.Hash, error) {
+	output, err := a.call(blockNumber, "getCrossDomainMessage", new(big.Int).SetUint64(queueIndex))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, ok := output[0].([32]byte)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("unexpected getCrossDomainMessage result: %v", output[0])
+	}
+	return hash, nil
+}
+
+func (a *L1MessageAuditor) call(blockNumber *big.Int, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := a.messageQueueABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s, err: %w", method, err)
+	}
+	output, err := a.client.CallContract(a.ctx, ethereum.CallMsg{To: &a.messageQueueAddress, Data: data}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s, block number: %v, err: %w", method, blockNumber, err)
+	}
+	result, err := a.messageQueueABI.Unpack(method, output)
+	if err != nil || len(result) == 0 {
+		return nil, fmt.Errorf("failed to unpack %s, err: %v", method, err)
+	}
+	return result, nil
+}
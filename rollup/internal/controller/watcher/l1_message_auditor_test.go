@@ -0,0 +1,69 @@
+package watcher
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+func TestCompareL1Messages(t *testing.T) {
+	newMessage := func(queueIndex uint64) *orm.L1Message {
+		calldata := []byte{0x01, 0x02, byte(queueIndex)}
+		return &orm.L1Message{
+			QueueIndex: queueIndex,
+			MsgHash:    common.BytesToHash(crypto.Keccak256(calldata)).Hex(),
+			GasLimit:   100000,
+			Sender:     common.HexToAddress("0x01").Hex(),
+			Target:     common.HexToAddress("0x02").Hex(),
+			Value:      "1000",
+			Calldata:   common.Bytes2Hex(calldata),
+		}
+	}
+	onchainHash := func(queueIndex uint64) common.Hash {
+		target := common.HexToAddress("0x02")
+		return gethTypes.NewTx(&gethTypes.L1MessageTx{
+			QueueIndex: queueIndex,
+			Gas:        100000,
+			To:         &target,
+			Value:      big.NewInt(1000),
+			Data:       []byte{0x01, 0x02, byte(queueIndex)},
+			Sender:     common.HexToAddress("0x01"),
+		}).Hash()
+	}
+	onchainHashes := []common.Hash{onchainHash(5), onchainHash(6), onchainHash(7)}
+
+	_, reason := compareL1Messages(5, []*orm.L1Message{newMessage(5), newMessage(6), newMessage(7)}, onchainHashes)
+	assert.Empty(t, reason)
+
+	queueIndex, reason := compareL1Messages(5, []*orm.L1Message{newMessage(5), newMessage(7)}, onchainHashes)
+	assert.Equal(t, uint64(6), queueIndex)
+	assert.Equal(t, "message missing in the db", reason)
+
+	queueIndex, reason = compareL1Messages(5, []*orm.L1Message{newMessage(5), newMessage(6), newMessage(6), newMessage(7)}, onchainHashes)
+	assert.Equal(t, uint64(6), queueIndex)
+	assert.Equal(t, "duplicated message in the db", reason)
+
+	// the calldata no longer matches the stored message hash.
+	altered := newMessage(7)
+	altered.Calldata = common.Bytes2Hex([]byte{0x03})
+	queueIndex, reason = compareL1Messages(5, []*orm.L1Message{newMessage(5), newMessage(6), altered}, onchainHashes)
+	assert.Equal(t, uint64(7), queueIndex)
+	assert.Equal(t, "stored message hash does not match the stored calldata", reason)
+
+	// a consistently stored message differing from the contract.
+	altered = newMessage(6)
+	altered.Value = "1001"
+	queueIndex, reason = compareL1Messages(5, []*orm.L1Message{newMessage(5), altered, newMessage(7)}, onchainHashes)
+	assert.Equal(t, uint64(6), queueIndex)
+	assert.Contains(t, reason, "message hash mismatch")
+
+	altered.Value = "not a number"
+	_, reason = compareL1Messages(5, []*orm.L1Message{newMessage(5), altered, newMessage(7)}, onchainHashes)
+	assert.Contains(t, reason, "invalid stored message value")
+}
@@ -0,0 +1,114 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/utils"
+)
+
+// The kinds of proposals held by the L1MessageSkipGuard.
+const (
+	L1MessageSkipProposalChunk = "chunk"
+	L1MessageSkipProposalBatch = "batch"
+)
+
+// HeldL1MessageSkip is a proposal held for skipping more L1 messages than allowed.
+type HeldL1MessageSkip struct {
+	Kind string `json:"kind"`
+	// Start is the first block number of a chunk, or the first chunk index of a batch.
+	Start     uint64    `json:"start"`
+	Skipped   uint64    `json:"skipped"`
+	Limit     uint64    `json:"limit"`
+	HeldSince time.Time `json:"held_since"`
+	// Approved is true once approved on the admin api.
+	Approved bool `json:"approved"`
+}
+
+// L1MessageSkipGuard holds the chunks and batches skipping more L1 messages than allowed until an operator approves
+// them, so that deposits are not censored by accident. At most one proposal of each kind is held, the proposers retry
+// the held proposal until it is approved.
+type L1MessageSkipGuard struct {
+	mu   sync.Mutex
+	held map[string]*HeldL1MessageSkip
+}
+
+// NewL1MessageSkipGuard creates a new L1MessageSkipGuard instance.
+func NewL1MessageSkipGuard() *L1MessageSkipGuard {
+	return &L1MessageSkipGuard{held: make(map[string]*HeldL1MessageSkip)}
+}
+
+// Held returns the held proposals.
+func (g *L1MessageSkipGuard) Held() []HeldL1MessageSkip {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	held := make([]HeldL1MessageSkip, 0, len(g.held))
+	for _, h := range g.held {
+		held = append(held, *h)
+	}
+	sort.Slice(held, func(i, j int) bool { return held[i].Kind < held[j].Kind })
+	return held
+}
+
+// Approve lets the held proposal of the kind starting at start skip its L1 messages once. The approval covers the
+// skipped messages held at the time, a proposal skipping more is held again.
+func (g *L1MessageSkipGuard) Approve(kind string, start uint64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	h, ok := g.held[kind]
+	if !ok || h.Start != start {
+		return fmt.Errorf("no %s proposal starting at %d is held", kind, start)
+	}
+	h.Approved = true
+	log.Warn("l1 message skips approved", "kind", kind, "start", start, "skipped", h.Skipped, "limit", h.Limit)
+	return nil
+}
+
+// allow returns whether the proposal of the kind starting at start may skip skipped L1 messages, it holds the proposal
+// when skipped exceeds limit and it is not approved. The held proposal is released by the next proposal of the kind
+// starting elsewhere, once the approved proposal is made.
+func (g *L1MessageSkipGuard) allow(kind string, start, skipped, limit uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	h, ok := g.held[kind]
+	if skipped <= limit {
+		delete(g.held, kind)
+		return true
+	}
+	if ok && h.Start == start && skipped <= h.Skipped {
+		return h.Approved
+	}
+	g.held[kind] = &HeldL1MessageSkip{
+		Kind:      kind,
+		Start:     start,
+		Skipped:   skipped,
+		Limit:     limit,
+		HeldSince: utils.NowUTC(),
+	}
+	log.Error("proposal held for skipping too many l1 messages, approve it on the admin api to propose it",
+		"kind", kind, "start", start, "skipped", skipped, "limit", limit)
+	return false
+}
+
+// skippedL1Messages returns the number of L1 messages skipped in the chunk, the messages popped by the chunk which it
+// does not include.
+func skippedL1Messages(chunk *encoding.Chunk, totalL1MessagePoppedBefore uint64) uint64 {
+	var included uint64
+	for _, block := range chunk.Blocks {
+		for _, txData := range block.Transactions {
+			if txData.Type == gethTypes.L1MessageTxType {
+				included++
+			}
+		}
+	}
+	return chunk.NumL1Messages(totalL1MessagePoppedBefore) - included
+}
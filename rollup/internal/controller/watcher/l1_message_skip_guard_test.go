@@ -0,0 +1,57 @@
+package watcher
+
+import (
+	"testing"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+)
+
+func TestSkippedL1Messages(t *testing.T) {
+	newBlock := func(queueIndexes ...uint64) *encoding.Block {
+		block := &encoding.Block{Transactions: []*gethTypes.TransactionData{{Type: gethTypes.LegacyTxType}}}
+		for _, queueIndex := range queueIndexes {
+			block.Transactions = append(block.Transactions, &gethTypes.TransactionData{Type: gethTypes.L1MessageTxType, Nonce: queueIndex})
+		}
+		return block
+	}
+
+	// the messages 12 and 13 are skipped, block 1 pops nothing.
+	chunk := &encoding.Chunk{Blocks: []*encoding.Block{newBlock(10, 11), newBlock(), newBlock(14)}}
+	assert.Equal(t, uint64(2), skippedL1Messages(chunk, 10))
+	// the message 10 is skipped before the chunk.
+	assert.Equal(t, uint64(3), skippedL1Messages(chunk, 9))
+	assert.Equal(t, uint64(0), skippedL1Messages(&encoding.Chunk{Blocks: []*encoding.Block{newBlock()}}, 10))
+}
+
+func TestL1MessageSkipGuard(t *testing.T) {
+	g := NewL1MessageSkipGuard()
+	assert.True(t, g.allow(L1MessageSkipProposalChunk, 100, 3, 3))
+	assert.Empty(t, g.Held())
+
+	// the proposal is held until approved.
+	assert.False(t, g.allow(L1MessageSkipProposalChunk, 100, 5, 3))
+	assert.False(t, g.allow(L1MessageSkipProposalChunk, 100, 5, 3))
+	assert.Error(t, g.Approve(L1MessageSkipProposalChunk, 101))
+	assert.Error(t, g.Approve(L1MessageSkipProposalBatch, 100))
+	held := g.Held()
+	assert.Len(t, held, 1)
+	assert.Equal(t, HeldL1MessageSkip{Kind: L1MessageSkipProposalChunk, Start: 100, Skipped: 5, Limit: 3, HeldSince: held[0].HeldSince}, held[0])
+
+	assert.NoError(t, g.Approve(L1MessageSkipProposalChunk, 100))
+	assert.True(t, g.allow(L1MessageSkipProposalChunk, 100, 5, 3))
+	assert.True(t, g.allow(L1MessageSkipProposalChunk, 100, 4, 3))
+	// the approval does not cover more skipped messages.
+	assert.False(t, g.allow(L1MessageSkipProposalChunk, 100, 6, 3))
+	assert.False(t, g.Held()[0].Approved)
+
+	// the kinds are held independently, a proposal starting elsewhere releases the held one.
+	assert.False(t, g.allow(L1MessageSkipProposalBatch, 7, 10, 3))
+	assert.Len(t, g.Held(), 2)
+	assert.True(t, g.allow(L1MessageSkipProposalChunk, 101, 0, 3))
+	held = g.Held()
+	assert.Len(t, held, 1)
+	assert.Equal(t, L1MessageSkipProposalBatch, held[0].Kind)
+}
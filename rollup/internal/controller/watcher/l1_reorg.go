@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// maxL1ReorgDepth is the deepest L1 reorg unwound automatically, a deeper one requires a manual rollback.
+const maxL1ReorgDepth = 64
+
+// detectReorg checks the parent hash of the block number against the hash recorded when the contract events of its
+// parent were processed. On a mismatch the contract events processed since the common ancestor with the canonical
+// chain are unwound, and the next FetchContractEvent re-emits them from the canonical chain.
+// The rollup statuses only move forward, the re-emitted rollup events apply again.
+func (w *L1WatcherClient) detectReorg(number uint64, parentHash common.Hash) (bool, error) {
+	if number == 0 {
+		return false, nil
+	}
+	parent, err := w.l1ProcessedBlockOrm.GetL1ProcessedBlock(w.ctx, number-1)
+	if err != nil {
+		return false, err
+	}
+	if parent == nil || parent.Hash == parentHash.String() {
+		return false, nil
+	}
+
+	ancestor, err := w.findCommonAncestor(parent.Number)
+	if err != nil {
+		return false, err
+	}
+	log.Warn("L1 reorg detected, unwinding the processed contract events", "block number", number, "parent hash", parentHash.String(),
+		"processed parent hash", parent.Hash, "common ancestor", ancestor, "processed height", w.processedMsgHeight)
+
+	err = w.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := w.l1MessageOrm.DeleteL1MessagesAfterHeight(w.ctx, ancestor, dbTX); err != nil {
+			return err
+		}
+		return w.l1ProcessedBlockOrm.DeleteL1ProcessedBlocksAfter(w.ctx, ancestor, dbTX)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to unwind the contract events processed after block %d: %w", ancestor, err)
+	}
+
+	w.metrics.l1WatcherReorgTotal.Inc()
+	w.metrics.l1WatcherReorgDepth.Set(float64(number - 1 - ancestor))
+	w.processedMsgHeight = ancestor
+	w.resetLookahead()
+	w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
+	return true, nil
+}
+
+// findCommonAncestor returns the last processed block below number which is still in the canonical chain.
+func (w *L1WatcherClient) findCommonAncestor(number uint64) (uint64, error) {
+	blocks, err := w.l1ProcessedBlockOrm.GetL1ProcessedBlocksBefore(w.ctx, number, maxL1ReorgDepth)
+	if err != nil {
+		return 0, err
+	}
+	for _, block := range blocks {
+		if number-block.Number > maxL1ReorgDepth {
+			break
+		}
+		header, err := w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(block.Number))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get L1 block %d: %w", block.Number, err)
+		}
+		if header.Hash().String() == block.Hash {
+			return block.Number, nil
+		}
+	}
+	return 0, fmt.Errorf("no processed L1 block in the canonical chain within %d blocks below block %d, the reorg requires a manual rollback", maxL1ReorgDepth, number)
+}
+
+// recordProcessedBlock records the hash of the last block of a processed range, and forgets the blocks too deep to be
+// reorged.
+func (w *L1WatcherClient) recordProcessedBlock(header *gethTypes.Header, number uint64) error {
+	if err := w.l1ProcessedBlockOrm.InsertL1ProcessedBlock(w.ctx, &orm.L1ProcessedBlock{Number: number, Hash: header.Hash().String()}); err != nil {
+		return err
+	}
+	if number <= maxL1ReorgDepth {
+		return nil
+	}
+	return w.l1ProcessedBlockOrm.DeleteL1ProcessedBlocksBefore(w.ctx, number-maxL1ReorgDepth)
+}
+
+// canonicalL1Blocks returns the L1 blocks to insert along with the header of blockHeight: when its parent hash does not
+// match the stored parent block, the canonical parents are fetched until one matches the stored block or no block is
+// stored, so that InsertL1Blocks replaces the reorged blocks.
+func (w *L1WatcherClient) canonicalL1Blocks(blockHeight uint64, header *gethTypes.Header) ([]orm.L1Block, error) {
+	blocks := []orm.L1Block{newL1Block(blockHeight, header)}
+	for number := blockHeight; number > 0; number-- {
+		stored, err := w.l1BlockOrm.GetL1Blocks(w.ctx, map[string]interface{}{"number = ?": number - 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(stored) == 0 || stored[0].Hash == header.ParentHash.String() {
+			break
+		}
+		if blockHeight-number >= maxL1ReorgDepth {
+			return nil, fmt.Errorf("stored L1 blocks diverge from the canonical chain for more than %d blocks below block %d, the reorg requires a manual rollback", maxL1ReorgDepth, blockHeight)
+		}
+
+		header, err = w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(number-1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get L1 block %d: %w", number-1, err)
+		}
+		if header == nil {
+			return nil, fmt.Errorf("received nil L1 block %d", number-1)
+		}
+		blocks = append([]orm.L1Block{newL1Block(number-1, header)}, blocks...)
+	}
+
+	if len(blocks) > 1 {
+		log.Warn("L1 reorg detected, replacing the stored L1 blocks", "from", blocks[0].Number, "to", blockHeight)
+		w.metrics.l1WatcherReorgTotal.Inc()
+		w.metrics.l1WatcherReorgDepth.Set(float64(len(blocks) - 1))
+	}
+	return blocks, nil
+}
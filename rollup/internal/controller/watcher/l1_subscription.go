@@ -0,0 +1,197 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	geth "github.com/scroll-tech/go-ethereum"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// l1SubscriptionRetryInterval is the delay before reconnecting a dropped subscription.
+	l1SubscriptionRetryInterval = 10 * time.Second
+	// contractEventsPollingInterval is the interval of eth_getLogs polling while the subscription is down.
+	contractEventsPollingInterval = 10 * time.Second
+)
+
+// l1Subscription follows the L1 heads and the logs watched by the L1 watcher through eth_subscribe. While it is
+// connected, the watcher takes the confirmed height from the latest head and skips eth_getLogs for the ranges without
+// notified logs. Once it drops the watcher polls with eth_getLogs, which backfills the blocks missed meanwhile, until
+// it reconnects. An endpoint without websocket support keeps the watcher polling.
+type l1Subscription struct {
+	endpoint string
+	query    geth.FilterQuery
+
+	mu sync.Mutex
+	// from is the first block whose logs are covered by the subscription, 0 while disconnected.
+	from uint64
+	head *gethTypes.Header
+	// logBlocks holds the numbers of the blocks with notified logs, including removed ones, which are not processed yet.
+	logBlocks map[uint64]struct{}
+
+	metrics *l1WatcherMetrics
+}
+
+func newL1Subscription(endpoint string, query geth.FilterQuery, metrics *l1WatcherMetrics) *l1Subscription {
+	return &l1Subscription{
+		endpoint:  endpoint,
+		query:     query,
+		logBlocks: make(map[uint64]struct{}),
+		metrics:   metrics,
+	}
+}
+
+// run keeps the subscription connected until ctx is done, or until the endpoint turns out not to support it.
+func (s *l1Subscription) run(ctx context.Context) {
+	for {
+		err := s.subscribe(ctx)
+		s.reset()
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			log.Warn("l1 endpoint does not support subscriptions, polling contract events", "err", err)
+			return
+		}
+		log.Warn("l1 subscription dropped, polling contract events until reconnected", "err", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l1SubscriptionRetryInterval):
+		}
+		s.metrics.l1WatcherSubscriptionReconnectTotal.Inc()
+	}
+}
+
+// subscribe follows the heads and the logs until the subscription drops.
+func (s *l1Subscription) subscribe(ctx context.Context) error {
+	client, err := ethclient.DialContext(ctx, s.endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	heads := make(chan *gethTypes.Header, 16)
+	headSub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer headSub.Unsubscribe()
+
+	logs := make(chan gethTypes.Log, 128)
+	logSub, err := client.SubscribeFilterLogs(ctx, s.query, logs)
+	if err != nil {
+		return err
+	}
+	defer logSub.Unsubscribe()
+
+	log.Info("l1 subscription connected", "endpoint", s.endpoint)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-headSub.Err():
+			return err
+		case err = <-logSub.Err():
+			return err
+		case head := <-heads:
+			s.onHead(head)
+		case vLog := <-logs:
+			s.onLog(vLog)
+		}
+	}
+}
+
+func (s *l1Subscription) onHead(head *gethTypes.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The logs of the first head may have been emitted before the log subscription started.
+	if s.from == 0 {
+		s.from = head.Number.Uint64() + 1
+		s.metrics.l1WatcherSubscriptionConnected.Set(1)
+	}
+	s.head = head
+}
+
+func (s *l1Subscription) onLog(vLog gethTypes.Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logBlocks[vLog.BlockNumber] = struct{}{}
+}
+
+func (s *l1Subscription) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.from = 0
+	s.head = nil
+	s.logBlocks = make(map[uint64]struct{})
+	s.metrics.l1WatcherSubscriptionConnected.Set(0)
+}
+
+// isConnected reports whether the subscription currently follows the heads, false for a nil subscription.
+func (s *l1Subscription) isConnected() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.from != 0
+}
+
+// confirmedHeight returns the confirmed height derived from the latest head, false when it cannot be derived locally,
+// i.e. the subscription is down or confirmations is a block tag requiring a request.
+func (s *l1Subscription) confirmedHeight(confirmations rpc.BlockNumber) (uint64, bool) {
+	if s == nil || confirmations.Int64() < 0 {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.from == 0 || s.head == nil {
+		return 0, false
+	}
+	number := s.head.Number.Uint64()
+	if number < uint64(confirmations.Int64()) {
+		return 0, true
+	}
+	return number - uint64(confirmations.Int64()), true
+}
+
+// covers reports whether the logs of the blocks [from, to] were all followed by the subscription, and whether any of
+// them were notified. The latest head is not covered, its logs may still be on their way.
+func (s *l1Subscription) covers(from, to uint64) (bool, bool) {
+	if s == nil {
+		return false, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.from == 0 || s.head == nil || from < s.from || to >= s.head.Number.Uint64() {
+		return false, false
+	}
+	for number := range s.logBlocks {
+		if number >= from && number <= to {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// prune forgets the notified logs up to the processed block height.
+func (s *l1Subscription) prune(processed uint64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for number := range s.logBlocks {
+		if number <= processed {
+			delete(s.logBlocks, number)
+		}
+	}
+}
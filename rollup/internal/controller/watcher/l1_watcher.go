@@ -3,7 +3,9 @@ package watcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	geth "github.com/scroll-tech/go-ethereum"
@@ -17,8 +19,10 @@ import (
 	"gorm.io/gorm"
 
 	"scroll-tech/common/types"
+	cutils "scroll-tech/common/utils"
 
 	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/controller/quorum"
 	"scroll-tech/rollup/internal/orm"
 	"scroll-tech/rollup/internal/utils"
 )
@@ -31,14 +35,18 @@ type rollupEvent struct {
 
 // L1WatcherClient will listen for smart contract events from Eth L1.
 type L1WatcherClient struct {
-	ctx          context.Context
-	client       *ethclient.Client
-	l1MessageOrm *orm.L1Message
-	l1BlockOrm   *orm.L1Block
-	batchOrm     *orm.Batch
+	ctx                 context.Context
+	client              *ethclient.Client
+	db                  *gorm.DB
+	l1MessageOrm        *orm.L1Message
+	l1BlockOrm          *orm.L1Block
+	l1ProcessedBlockOrm *orm.L1ProcessedBlock
+	batchOrm            *orm.Batch
 
 	// The number of new blocks to wait for a block to be confirmed
 	confirmations rpc.BlockNumber
+	// eventClasses is nil when every contract event waits for confirmations.
+	eventClasses []*l1EventClass
 
 	messageQueueAddress common.Address
 	messageQueueABI     *abi.ABI
@@ -46,11 +54,18 @@ type L1WatcherClient struct {
 	scrollChainAddress common.Address
 	scrollChainABI     *abi.ABI
 
+	startHeight uint64
 	// The height of the block that the watcher has retrieved event logs
 	processedMsgHeight uint64
 	// The height of the block that the watcher has retrieved header rlp
 	processedBlockHeight uint64
 
+	// subscription is nil when the watcher only polls contract events.
+	subscription *l1Subscription
+	// l1Quorum is nil when the contract events are read from client only.
+	l1Quorum     *quorum.Client
+	lastPolledAt time.Time
+
 	metrics *l1WatcherMetrics
 }
 
@@ -77,12 +92,14 @@ func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeig
 	}
 
 	return &L1WatcherClient{
-		ctx:           ctx,
-		client:        client,
-		l1MessageOrm:  l1MessageOrm,
-		l1BlockOrm:    l1BlockOrm,
-		batchOrm:      orm.NewBatch(db),
-		confirmations: confirmations,
+		ctx:                 ctx,
+		client:              client,
+		db:                  db,
+		l1MessageOrm:        l1MessageOrm,
+		l1BlockOrm:          l1BlockOrm,
+		l1ProcessedBlockOrm: orm.NewL1ProcessedBlock(db),
+		batchOrm:            orm.NewBatch(db),
+		confirmations:       confirmations,
 
 		messageQueueAddress: messageQueueAddress,
 		messageQueueABI:     bridgeAbi.L1MessageQueueABI,
@@ -90,6 +107,7 @@ func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeig
 		scrollChainAddress: scrollChainAddress,
 		scrollChainABI:     bridgeAbi.ScrollChainABI,
 
+		startHeight:          startHeight,
 		processedMsgHeight:   uint64(savedHeight),
 		processedBlockHeight: savedL1BlockHeight,
 		metrics:              initL1WatcherMetrics(reg),
@@ -102,6 +120,11 @@ func (w *L1WatcherClient) ProcessedBlockHeight() uint64 {
 	return w.processedBlockHeight
 }
 
+// ProcessedMsgHeight get processedMsgHeight
+func (w *L1WatcherClient) ProcessedMsgHeight() uint64 {
+	return w.processedMsgHeight
+}
+
 // Confirmations get confirmations
 // Currently only use for unit test
 func (w *L1WatcherClient) Confirmations() rpc.BlockNumber {
@@ -114,6 +137,32 @@ func (w *L1WatcherClient) SetConfirmations(confirmations rpc.BlockNumber) {
 	w.confirmations = confirmations
 }
 
+// HeaderLag returns the number of confirmed L1 blocks above the latest stored L1 block header.
+func (w *L1WatcherClient) HeaderLag(ctx context.Context) (uint64, error) {
+	confirmed, err := utils.GetLatestConfirmedBlockNumber(ctx, w.client, w.confirmations)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get confirmed block number: %w", err)
+	}
+	stored, err := w.l1BlockOrm.GetLatestL1BlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return blocksBehind(confirmed, stored), nil
+}
+
+// EventLag returns the number of confirmed L1 blocks above the latest block with processed contract events.
+func (w *L1WatcherClient) EventLag(ctx context.Context) (uint64, error) {
+	confirmed, _, err := w.contractEventsHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get confirmed block number: %w", err)
+	}
+	processed, err := w.l1ProcessedBlockOrm.GetLatestL1ProcessedBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return blocksBehind(confirmed, processed), nil
+}
+
 // FetchBlockHeader pull latest L1 blocks and save in DB
 func (w *L1WatcherClient) FetchBlockHeader(blockHeight uint64) error {
 	w.metrics.l1WatcherFetchBlockHeaderTotal.Inc()
@@ -130,19 +179,13 @@ func (w *L1WatcherClient) FetchBlockHeader(blockHeight uint64) error {
 		return errors.New("received nil block")
 	}
 
-	var baseFee uint64
-	if block.BaseFee != nil {
-		baseFee = block.BaseFee.Uint64()
-	}
-
-	l1Block := orm.L1Block{
-		Number:          blockHeight,
-		Hash:            block.Hash().String(),
-		BaseFee:         baseFee,
-		GasOracleStatus: int16(types.GasOraclePending),
+	l1Blocks, err := w.canonicalL1Blocks(blockHeight, block)
+	if err != nil {
+		log.Warn("Failed to fetch the canonical L1 blocks", "blockHeight", blockHeight, "err", err)
+		return err
 	}
 
-	err = w.l1BlockOrm.InsertL1Blocks(w.ctx, []orm.L1Block{l1Block})
+	err = w.l1BlockOrm.InsertL1Blocks(w.ctx, l1Blocks)
 	if err != nil {
 		log.Warn("Failed to insert L1 block to db", "blockHeight", blockHeight, "err", err)
 		return err
@@ -154,17 +197,90 @@ func (w *L1WatcherClient) FetchBlockHeader(blockHeight uint64) error {
 	return nil
 }
 
+func newL1Block(number uint64, header *gethTypes.Header) orm.L1Block {
+	var baseFee uint64
+	if header.BaseFee != nil {
+		baseFee = header.BaseFee.Uint64()
+	}
+
+	var blobBaseFee uint64
+	if header.ExcessBlobGas != nil {
+		blobBaseFee = cutils.CalcBlobFee(*header.ExcessBlobGas).Uint64()
+	}
+
+	return orm.L1Block{
+		Number:          number,
+		Hash:            header.Hash().String(),
+		BaseFee:         baseFee,
+		BlobBaseFee:     blobBaseFee,
+		GasOracleStatus: int16(types.GasOraclePending),
+	}
+}
+
+// SubscribeContractEvents follows the L1 heads and the contract event logs through the websocket endpoint until ctx
+// is done, FetchContractEvent then only requests the logs of the blocks with notified events. FetchContractEvent can be
+// called frequently, it polls every contractEventsPollingInterval while the subscription is down.
+func (w *L1WatcherClient) SubscribeContractEvents(ctx context.Context, endpoint string) {
+	w.subscription = newL1Subscription(endpoint, w.contractEventsQuery(nil, nil), w.metrics)
+	go w.subscription.run(ctx)
+}
+
+// UseQuorum reads the contract events through the quorum of L1 endpoints, the events feed the finalization.
+func (w *L1WatcherClient) UseQuorum(l1Quorum *quorum.Client) {
+	w.l1Quorum = l1Quorum
+}
+
+// filterLogs reads the contract event logs through the quorum when it is set.
+func (w *L1WatcherClient) filterLogs(ctx context.Context, query geth.FilterQuery) ([]gethTypes.Log, error) {
+	if w.l1Quorum != nil {
+		return w.l1Quorum.FilterLogs(ctx, query)
+	}
+	return w.client.FilterLogs(ctx, query)
+}
+
+// contractEventsQuery returns the filter of the watched contract events in the blocks [from, to].
+func (w *L1WatcherClient) contractEventsQuery(from, to *big.Int) geth.FilterQuery {
+	query := geth.FilterQuery{
+		FromBlock: from, // inclusive
+		ToBlock:   to,   // inclusive
+		Addresses: []common.Address{
+			w.scrollChainAddress,
+			w.messageQueueAddress,
+		},
+		Topics: make([][]common.Hash, 1),
+	}
+	query.Topics[0] = make([]common.Hash, 3)
+	query.Topics[0][0] = bridgeAbi.L1QueueTransactionEventSignature
+	query.Topics[0][1] = bridgeAbi.L1CommitBatchEventSignature
+	query.Topics[0][2] = bridgeAbi.L1FinalizeBatchEventSignature
+	return query
+}
+
 // FetchContractEvent pull latest event logs from given contract address and save in DB
 func (w *L1WatcherClient) FetchContractEvent() error {
+	if w.subscription != nil && !w.subscription.isConnected() {
+		if time.Since(w.lastPolledAt) < contractEventsPollingInterval {
+			return nil
+		}
+		w.lastPolledAt = time.Now()
+	}
+
 	defer func() {
 		log.Info("l1 watcher fetchContractEvent", "w.processedMsgHeight", w.processedMsgHeight)
 	}()
-	blockHeight, err := utils.GetLatestConfirmedBlockNumber(w.ctx, w.client, w.confirmations)
+	blockHeight, classHeights, err := w.contractEventsHeight(w.ctx)
 	if err != nil {
 		log.Error("failed to get block number", "err", err)
 		return err
 	}
+	if err = w.fetchContractEvents(blockHeight); err != nil {
+		return err
+	}
+	return w.fetchLookaheadEvents(classHeights)
+}
 
+// fetchContractEvents processes the contract events of the blocks up to blockHeight.
+func (w *L1WatcherClient) fetchContractEvents(blockHeight uint64) error {
 	fromBlock := int64(w.processedMsgHeight) + 1
 	toBlock := int64(blockHeight)
 
@@ -176,28 +292,53 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 			to = toBlock
 		}
 
-		// warning: uint int conversion...
-		query := geth.FilterQuery{
-			FromBlock: big.NewInt(from), // inclusive
-			ToBlock:   big.NewInt(to),   // inclusive
-			Addresses: []common.Address{
-				w.scrollChainAddress,
-				w.messageQueueAddress,
-			},
-			Topics: make([][]common.Hash, 1),
+		fromHeader, err := w.client.HeaderByNumber(w.ctx, big.NewInt(from))
+		if err != nil {
+			log.Warn("Failed to get block", "height", from, "err", err)
+			return err
+		}
+		reorged, err := w.detectReorg(uint64(from), fromHeader.ParentHash)
+		if err != nil {
+			log.Error("Failed to handle L1 reorg", "height", from, "err", err)
+			return err
+		}
+		if reorged {
+			return nil
+		}
+		// The header is fetched before the logs, a reorg in between is detected by the next range.
+		toHeader := fromHeader
+		if to != from {
+			if toHeader, err = w.client.HeaderByNumber(w.ctx, big.NewInt(to)); err != nil {
+				log.Warn("Failed to get block", "height", to, "err", err)
+				return err
+			}
 		}
-		query.Topics[0] = make([]common.Hash, 3)
-		query.Topics[0][0] = bridgeAbi.L1QueueTransactionEventSignature
-		query.Topics[0][1] = bridgeAbi.L1CommitBatchEventSignature
-		query.Topics[0][2] = bridgeAbi.L1FinalizeBatchEventSignature
 
-		logs, err := w.client.FilterLogs(w.ctx, query)
+		// The subscription notified no event in these blocks.
+		if covered, notified := w.subscription.covers(uint64(from), uint64(to)); covered && !notified {
+			if err = w.recordProcessedBlock(toHeader, uint64(to)); err != nil {
+				return err
+			}
+			w.processedMsgHeight = uint64(to)
+			w.metrics.l1WatcherFetchContractEventSkippedTotal.Inc()
+			w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(to))
+			continue
+		}
+
+		// warning: uint int conversion...
+		query := w.contractEventsQuery(big.NewInt(from), big.NewInt(to))
+
+		logs, err := w.filterLogs(w.ctx, query)
 		if err != nil {
 			log.Warn("Failed to get event logs", "err", err)
 			return err
 		}
 		if len(logs) == 0 {
+			if err = w.recordProcessedBlock(toHeader, uint64(to)); err != nil {
+				return err
+			}
 			w.processedMsgHeight = uint64(to)
+			w.subscription.prune(w.processedMsgHeight)
 			w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(to))
 			continue
 		}
@@ -216,42 +357,23 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 		log.Info("L1 events types", "SentMessageCount", sentMessageCount, "RollupEventCount", rollupEventCount)
 
 		// use rollup event to update rollup results db status
-		var batchHashes []string
-		for _, event := range rollupEvents {
-			batchHashes = append(batchHashes, event.batchHash.String())
-		}
-		statuses, err := w.batchOrm.GetRollupStatusByHashList(w.ctx, batchHashes)
+		updated, err := w.updateRollupStatuses(w.ctx, rollupEvents)
 		if err != nil {
-			log.Error("Failed to GetRollupStatusByHashList", "err", err)
 			return err
 		}
-		if len(statuses) != len(batchHashes) {
-			log.Error("RollupStatus.Length mismatch with batchHashes.Length", "RollupStatus.Length", len(statuses), "batchHashes.Length", len(batchHashes))
+		if !updated {
 			return nil
 		}
 
-		for index, event := range rollupEvents {
-			batchHash := event.batchHash.String()
-			status := statuses[index]
-			// only update when db status is before event status
-			if event.status > status {
-				if event.status == types.RollupFinalized {
-					err = w.batchOrm.UpdateFinalizeTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status)
-				} else if event.status == types.RollupCommitted {
-					err = w.batchOrm.UpdateCommitTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status)
-				}
-				if err != nil {
-					log.Error("Failed to update Rollup/Finalize TxHash and Status", "err", err)
-					return err
-				}
-			}
+		if err = w.saveL1Messages(sentMessageEvents); err != nil {
+			return err
 		}
 
-		if err = w.l1MessageOrm.SaveL1Messages(w.ctx, sentMessageEvents); err != nil {
+		if err = w.recordProcessedBlock(toHeader, uint64(to)); err != nil {
 			return err
 		}
-
 		w.processedMsgHeight = uint64(to)
+		w.subscription.prune(w.processedMsgHeight)
 		w.metrics.l1WatcherFetchContractEventSuccessTotal.Inc()
 		w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
 	}
@@ -259,6 +381,80 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 	return nil
 }
 
+// saveL1Messages stores the L1 messages, the ones already processed ahead at the L1 message confirmations are skipped.
+func (w *L1WatcherClient) saveL1Messages(messages []*orm.L1Message) error {
+	if len(w.eventClasses) == 0 {
+		return w.l1MessageOrm.SaveL1Messages(w.ctx, messages)
+	}
+	return w.l1MessageOrm.InsertMissingL1Messages(w.ctx, messages)
+}
+
+// updateRollupStatuses moves the rollup statuses of the batches forward to the statuses of the rollup events, it
+// returns false without updating when some batches are not found.
+func (w *L1WatcherClient) updateRollupStatuses(ctx context.Context, rollupEvents []rollupEvent) (bool, error) {
+	var batchHashes []string
+	for _, event := range rollupEvents {
+		batchHashes = append(batchHashes, event.batchHash.String())
+	}
+	statuses, err := w.batchOrm.GetRollupStatusByHashList(ctx, batchHashes)
+	if err != nil {
+		log.Error("Failed to GetRollupStatusByHashList", "err", err)
+		return false, err
+	}
+	if len(statuses) != len(batchHashes) {
+		log.Error("RollupStatus.Length mismatch with batchHashes.Length", "RollupStatus.Length", len(statuses), "batchHashes.Length", len(batchHashes))
+		return false, nil
+	}
+
+	for index, event := range rollupEvents {
+		batchHash := event.batchHash.String()
+		status := statuses[index]
+		// only update when db status is before event status
+		if event.status > status {
+			if event.status == types.RollupFinalized {
+				err = w.batchOrm.UpdateFinalizeTxHashAndRollupStatus(ctx, batchHash, event.txHash.String(), event.status)
+			} else if event.status == types.RollupCommitted {
+				err = w.batchOrm.UpdateCommitTxHashAndRollupStatus(ctx, batchHash, event.txHash.String(), event.status)
+			}
+			if err != nil {
+				log.Error("Failed to update Rollup/Finalize TxHash and Status", "err", err)
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// Backfill replays the ingestion of the contract events of the blocks [from, to], e.g. when an event was missed. The
+// L1 messages already stored are kept and the rollup statuses only move forward, so a range can be backfilled more
+// than once. The progress of the watcher is not changed.
+func (w *L1WatcherClient) Backfill(ctx context.Context, cfg BackfillConfig, from, to uint64) error {
+	return backfill(ctx, cfg, from, to, uint64(contractEventsBlocksFetchLimit), w.backfillContractEvents)
+}
+
+func (w *L1WatcherClient) backfillContractEvents(ctx context.Context, from, to uint64) error {
+	logs, err := w.filterLogs(ctx, w.contractEventsQuery(new(big.Int).SetUint64(from), new(big.Int).SetUint64(to)))
+	if err != nil {
+		return fmt.Errorf("failed to get event logs: %w", err)
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+
+	sentMessageEvents, rollupEvents, err := w.parseBridgeEventLogs(logs)
+	if err != nil {
+		return fmt.Errorf("failed to parse emitted events log: %w", err)
+	}
+	updated, err := w.updateRollupStatuses(ctx, rollupEvents)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("some batches of the rollup events are not stored")
+	}
+	return w.l1MessageOrm.InsertMissingL1Messages(ctx, sentMessageEvents)
+}
+
 func (w *L1WatcherClient) parseBridgeEventLogs(logs []gethTypes.Log) ([]*orm.L1Message, []rollupEvent, error) {
 	// Need use contract abi to parse event Log
 	// Can only be tested after we have our contracts set up
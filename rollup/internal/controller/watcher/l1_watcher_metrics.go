@@ -15,6 +15,12 @@ type l1WatcherMetrics struct {
 	l1WatcherFetchContractEventProcessedBlockHeight prometheus.Gauge
 	l1WatcherFetchContractEventSentEventsTotal      prometheus.Counter
 	l1WatcherFetchContractEventRollupEventsTotal    prometheus.Counter
+	l1WatcherFetchContractEventSkippedTotal         prometheus.Counter
+	l1WatcherFetchContractEventLookaheadHeight      *prometheus.GaugeVec
+	l1WatcherSubscriptionConnected                  prometheus.Gauge
+	l1WatcherSubscriptionReconnectTotal             prometheus.Counter
+	l1WatcherReorgTotal                             prometheus.Counter
+	l1WatcherReorgDepth                             prometheus.Gauge
 }
 
 var (
@@ -53,6 +59,30 @@ func initL1WatcherMetrics(reg prometheus.Registerer) *l1WatcherMetrics {
 				Name: "rollup_l1_watcher_fetch_block_contract_event_rollup_event_total",
 				Help: "The current processed block height of l1 watcher fetch contract rollup event",
 			}),
+			l1WatcherFetchContractEventSkippedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_fetch_block_contract_event_skipped_total",
+				Help: "The total number of l1 watcher eth_getLogs requests skipped as the subscription notified no log",
+			}),
+			l1WatcherFetchContractEventLookaheadHeight: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_fetch_block_contract_event_lookahead_height",
+				Help: "The last block whose events of the class were processed above the processed block height of l1 watcher fetch contract event",
+			}, []string{"class"}),
+			l1WatcherSubscriptionConnected: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_subscription_connected",
+				Help: "Whether the l1 watcher follows l1 heads and logs through eth_subscribe",
+			}),
+			l1WatcherSubscriptionReconnectTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_subscription_reconnect_total",
+				Help: "The total number of l1 watcher subscription reconnections",
+			}),
+			l1WatcherReorgTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_reorg_total",
+				Help: "The total number of l1 reorgs detected and unwound by the l1 watcher",
+			}),
+			l1WatcherReorgDepth: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_reorg_depth",
+				Help: "The number of blocks unwound by the last l1 reorg detected by the l1 watcher",
+			}),
 		}
 	})
 	return l1WatcherMetric
@@ -141,6 +141,12 @@ func testL1WatcherClientFetchContractEvent(t *testing.T) {
 	})
 	defer patchGuard.Reset()
 
+	// The mocked headers do not chain to the processed blocks.
+	var l1ProcessedBlockOrm *orm.L1ProcessedBlock
+	patchGuard.ApplyMethodFunc(l1ProcessedBlockOrm, "GetL1ProcessedBlock", func(context.Context, uint64) (*orm.L1ProcessedBlock, error) {
+		return nil, nil
+	})
+
 	convey.Convey("filter logs failure", t, func() {
 		targetErr := errors.New("call filter failure")
 		patchGuard.ApplyMethodFunc(c, "FilterLogs", func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
@@ -258,6 +264,132 @@ func testL1WatcherClientFetchContractEvent(t *testing.T) {
 	})
 }
 
+func testL1WatcherClientReorg(t *testing.T) {
+	watcher, db := setupL1Watcher(t)
+	defer database.CloseDB(db)
+
+	header := func(number uint64, fork bool) *types.Header {
+		h := &types.Header{Number: new(big.Int).SetUint64(number), BaseFee: big.NewInt(100)}
+		if fork {
+			h.Extra = []byte("fork")
+		}
+		return h
+	}
+	var c *ethclient.Client
+	patchGuard := gomonkey.ApplyMethodFunc(c, "HeaderByNumber", func(ctx context.Context, height *big.Int) (*types.Header, error) {
+		return header(height.Uint64(), false), nil
+	})
+	defer patchGuard.Reset()
+
+	ctx := context.Background()
+	for _, block := range []*types.Header{header(1000, false), header(1010, false), header(1020, true)} {
+		assert.NoError(t, watcher.recordProcessedBlock(block, block.Number.Uint64()))
+	}
+	assert.NoError(t, watcher.l1MessageOrm.SaveL1Messages(ctx, []*orm.L1Message{
+		{QueueIndex: 100000, MsgHash: "0x1005", Height: 1005, Calldata: "0x", Layer1Hash: "0x1005"},
+		{QueueIndex: 100001, MsgHash: "0x1015", Height: 1015, Calldata: "0x", Layer1Hash: "0x1015"},
+	}))
+	watcher.processedMsgHeight = 1020
+
+	// The canonical block 1021 does not chain to the processed fork block 1020, the events are unwound to block 1010.
+	reorged, err := watcher.detectReorg(1021, header(1020, false).Hash())
+	assert.NoError(t, err)
+	assert.True(t, reorged)
+	assert.Equal(t, uint64(1010), watcher.ProcessedMsgHeight())
+
+	var count int64
+	assert.NoError(t, db.Model(&orm.L1Message{}).Where("queue_index >= ?", 100000).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	block, err := watcher.l1ProcessedBlockOrm.GetL1ProcessedBlock(ctx, 1020)
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+
+	// The canonical chain is followed again from the common ancestor.
+	reorged, err = watcher.detectReorg(1011, header(1010, false).Hash())
+	assert.NoError(t, err)
+	assert.False(t, reorged)
+
+	// A reorg deeper than the processed blocks is not unwound.
+	assert.NoError(t, watcher.recordProcessedBlock(header(1011, true), 1011))
+	patchGuard.ApplyMethodFunc(c, "HeaderByNumber", func(ctx context.Context, height *big.Int) (*types.Header, error) {
+		return header(height.Uint64(), true), nil
+	})
+	_, err = watcher.detectReorg(1012, header(1011, false).Hash())
+	assert.Error(t, err)
+}
+
+func testL1WatcherClientEventConfirmations(t *testing.T) {
+	watcher, db := setupL1Watcher(t)
+	defer database.CloseDB(db)
+
+	headers := make(map[uint64]*types.Header)
+	var header func(number uint64) *types.Header
+	header = func(number uint64) *types.Header {
+		if h, ok := headers[number]; ok {
+			return h
+		}
+		h := &types.Header{Number: new(big.Int).SetUint64(number), BaseFee: big.NewInt(100)}
+		if number > 0 {
+			h.ParentHash = header(number - 1).Hash()
+		}
+		headers[number] = h
+		return h
+	}
+	var c *ethclient.Client
+	patchGuard := gomonkey.ApplyMethodFunc(c, "HeaderByNumber", func(ctx context.Context, height *big.Int) (*types.Header, error) {
+		return header(height.Uint64()), nil
+	})
+	defer patchGuard.Reset()
+	var queries []ethereum.FilterQuery
+	patchGuard.ApplyMethodFunc(c, "FilterLogs", func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+		queries = append(queries, q)
+		return nil, nil
+	})
+
+	// The l1 messages are confirmed 5 blocks above the rollup events, which are acted on at the processed height.
+	watcher.UseEventConfirmations(rpc.BlockNumber(0), rpc.BlockNumber(5))
+	watcher.processedMsgHeight = 2000
+	heights := []uint64{2010, 2005}
+	assert.NoError(t, watcher.fetchContractEvents(2005))
+	assert.Equal(t, uint64(2005), watcher.ProcessedMsgHeight())
+	assert.NoError(t, watcher.fetchLookaheadEvents(heights))
+	messages, rollupEvents := watcher.eventClasses[0], watcher.eventClasses[1]
+	assert.Equal(t, uint64(2010), messages.lookaheadHeight)
+	assert.Equal(t, uint64(0), rollupEvents.lookaheadHeight)
+	last := queries[len(queries)-1]
+	assert.Equal(t, []common.Address{watcher.messageQueueAddress}, last.Addresses)
+	assert.Equal(t, uint64(2006), last.FromBlock.Uint64())
+
+	// The lookahead resumes from the last block processed ahead.
+	queries = nil
+	assert.NoError(t, watcher.fetchLookaheadEvents([]uint64{2012, 2005}))
+	assert.Equal(t, uint64(2012), messages.lookaheadHeight)
+	assert.Len(t, queries, 1)
+	assert.Equal(t, uint64(2011), queries[0].FromBlock.Uint64())
+
+	// The l1 messages processed ahead are unwound when the next block does not chain to the last block processed ahead.
+	ctx := context.Background()
+	assert.NoError(t, watcher.l1MessageOrm.SaveL1Messages(ctx, []*orm.L1Message{
+		{QueueIndex: 200000, MsgHash: "0x2003", Height: 2003, Calldata: "0x", Layer1Hash: "0x2003"},
+		{QueueIndex: 200001, MsgHash: "0x2008", Height: 2008, Calldata: "0x", Layer1Hash: "0x2008"},
+	}))
+	messages.lookaheadHash = common.HexToHash("0x2012")
+	assert.NoError(t, watcher.fetchLookaheadEvents([]uint64{2014, 2005}))
+	assert.Equal(t, uint64(0), messages.lookaheadHeight)
+	var count int64
+	assert.NoError(t, db.Model(&orm.L1Message{}).Where("queue_index >= ?", 200000).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// The watcher catching up with the class processes its blocks again from the processed height.
+	assert.NoError(t, watcher.fetchLookaheadEvents([]uint64{2014, 2005}))
+	assert.Equal(t, uint64(2014), messages.lookaheadHeight)
+	watcher.processedMsgHeight = 2014
+	queries = nil
+	assert.NoError(t, watcher.fetchLookaheadEvents([]uint64{2014, 2014}))
+	assert.Equal(t, uint64(0), messages.lookaheadHeight)
+	assert.Empty(t, queries)
+}
+
 func testParseBridgeEventLogsL1QueueTransactionEventSignature(t *testing.T) {
 	watcher, db := setupL1Watcher(t)
 	defer database.CloseDB(db)
@@ -387,3 +519,48 @@ func testParseBridgeEventLogsL1FinalizeBatchEventSignature(t *testing.T) {
 		assert.Equal(t, rollupEvents[0].status, commonTypes.RollupFinalized)
 	})
 }
+
+func testL1SubscriptionCoverage(t *testing.T) {
+	var subscription *l1Subscription
+	// A nil subscription covers nothing, the watcher polls.
+	covered, _ := subscription.covers(1, 10)
+	assert.False(t, covered)
+	_, ok := subscription.confirmedHeight(6)
+	assert.False(t, ok)
+
+	subscription = newL1Subscription("", ethereum.FilterQuery{}, initL1WatcherMetrics(nil))
+	subscription.onHead(&types.Header{Number: big.NewInt(100)})
+	subscription.onHead(&types.Header{Number: big.NewInt(120)})
+	subscription.onLog(types.Log{BlockNumber: 105})
+	assert.True(t, subscription.isConnected())
+
+	height, ok := subscription.confirmedHeight(6)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(114), height)
+	_, ok = subscription.confirmedHeight(rpc.FinalizedBlockNumber)
+	assert.False(t, ok)
+
+	// The blocks up to the first head and the latest head are not covered.
+	covered, _ = subscription.covers(100, 104)
+	assert.False(t, covered)
+	covered, _ = subscription.covers(111, 120)
+	assert.False(t, covered)
+
+	covered, notified := subscription.covers(101, 104)
+	assert.True(t, covered)
+	assert.False(t, notified)
+	covered, notified = subscription.covers(101, 110)
+	assert.True(t, covered)
+	assert.True(t, notified)
+
+	subscription.prune(110)
+	covered, notified = subscription.covers(101, 110)
+	assert.True(t, covered)
+	assert.False(t, notified)
+
+	// A dropped subscription covers nothing until it reconnects.
+	subscription.reset()
+	assert.False(t, subscription.isConnected())
+	covered, _ = subscription.covers(101, 104)
+	assert.False(t, covered)
+}
@@ -2,10 +2,12 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/prometheus/client_golang/prometheus"
+	geth "github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
@@ -16,13 +18,18 @@ import (
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/tracing"
 	"scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/encoding/codecv0"
 
 	bridgeAbi "scroll-tech/rollup/abi"
 	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
 )
 
+// ErrWithdrawRootMismatch is returned by VerifyWithdrawRoot when a withdraw root differs from the root of the chain.
+var ErrWithdrawRootMismatch = errors.New("withdraw root mismatch")
+
 // L2WatcherClient provide APIs which support others to subscribe to various event from l2geth
 type L2WatcherClient struct {
 	ctx context.Context
@@ -30,7 +37,15 @@ type L2WatcherClient struct {
 
 	*ethclient.Client
 
-	l2BlockOrm *orm.L2Block
+	db                     *gorm.DB
+	l2BlockOrm             *orm.L2Block
+	l2WatcherCheckpointOrm *orm.L2WatcherCheckpoint
+
+	// checkpoint is the last stored block, nil until loaded.
+	checkpoint *orm.L2WatcherCheckpoint
+
+	// blocksStored notifies that new blocks were stored, it does not block the watcher when nobody listens.
+	blocksStored chan struct{}
 
 	confirmations rpc.BlockNumber
 
@@ -38,6 +53,10 @@ type L2WatcherClient struct {
 	messageQueueABI      *abi.ABI
 	withdrawTrieRootSlot common.Hash
 
+	// withdrawTrie is the withdraw trie at the checkpoint, nil until loaded or when the withdraw roots are read.
+	computeWithdrawRoot bool
+	withdrawTrie        *withdrawTrie
+
 	metrics *l2WatcherMetrics
 }
 
@@ -47,7 +66,11 @@ func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmat
 		ctx:    ctx,
 		Client: client,
 
-		l2BlockOrm: orm.NewL2Block(db),
+		db:                     db,
+		l2BlockOrm:             orm.NewL2Block(db),
+		l2WatcherCheckpointOrm: orm.NewL2WatcherCheckpoint(db),
+
+		blocksStored: make(chan struct{}, 1),
 
 		confirmations: confirmations,
 
@@ -61,14 +84,202 @@ func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmat
 
 const blockTracesFetchLimit = uint64(10)
 
+// withdrawTrieRebuildBlocks is the number of blocks of the AppendMessage events fetched at once to rebuild the withdraw trie.
+const withdrawTrieRebuildBlocks = uint64(10000)
+
+// EnableWithdrawTrie makes the watcher compute the withdraw roots of the blocks from the AppendMessage events, on the
+// withdraw trie persisted with the checkpoint, instead of reading the root of every block. It must be called before
+// the checkpoint is loaded.
+func (w *L2WatcherClient) EnableWithdrawTrie() {
+	w.computeWithdrawRoot = true
+}
+
+// VerifyWithdrawRoot checks the withdraw root of the block against the root of the L2MessageQueue contract at the block.
+func (w *L2WatcherClient) VerifyWithdrawRoot(ctx context.Context, blockNumber uint64, root common.Hash) error {
+	chainRoot, err := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to get withdrawRoot: %w, number: %v", err, blockNumber)
+	}
+	if common.BytesToHash(chainRoot) != root {
+		w.metrics.withdrawRootMismatchTotal.Inc()
+		return fmt.Errorf("%w, number: %v, root: %v, chain root: %v", ErrWithdrawRootMismatch, blockNumber, root.String(), common.BytesToHash(chainRoot).String())
+	}
+	return nil
+}
+
+// Lag returns the number of confirmed L2 blocks above the latest stored L2 block.
+func (w *L2WatcherClient) Lag(ctx context.Context) (uint64, error) {
+	confirmed, err := utils.GetLatestConfirmedBlockNumber(ctx, w.Client, w.confirmations)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get confirmed block number: %w", err)
+	}
+	stored, err := w.l2BlockOrm.GetL2BlocksLatestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return blocksBehind(confirmed, stored), nil
+}
+
+// BlocksStored returns a channel receiving a notification after new blocks are stored, the notifications sent while
+// the previous one is not received yet are merged.
+func (w *L2WatcherClient) BlocksStored() <-chan struct{} {
+	return w.blocksStored
+}
+
+// VerifyCheckpoint loads the checkpoint of the stored blocks and verifies that its block is still in the chain, so
+// that the watcher does not extend a chain which was reorged while it was stopped.
+func (w *L2WatcherClient) VerifyCheckpoint() error {
+	checkpoint, err := w.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+	if checkpoint.BlockHash == "" {
+		return nil
+	}
+
+	header, err := w.HeaderByNumber(w.ctx, new(big.Int).SetUint64(checkpoint.BlockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to get the block of the checkpoint: %w, number: %v", err, checkpoint.BlockNumber)
+	}
+	if header.Hash().String() != checkpoint.BlockHash {
+		return fmt.Errorf("checkpoint block hash does not match the chain, number: %v, checkpoint hash: %v, chain hash: %v", checkpoint.BlockNumber, checkpoint.BlockHash, header.Hash().String())
+	}
+	log.Info("verified l2 watcher checkpoint", "number", checkpoint.BlockNumber, "hash", checkpoint.BlockHash)
+	return nil
+}
+
+// loadCheckpoint returns the checkpoint of the stored blocks. Without a saved checkpoint, e.g. on the first run after
+// an upgrade, it is initialized from the latest stored block.
+func (w *L2WatcherClient) loadCheckpoint() (*orm.L2WatcherCheckpoint, error) {
+	if w.checkpoint != nil {
+		return w.checkpoint, nil
+	}
+
+	checkpoint, err := w.l2WatcherCheckpointOrm.GetL2WatcherCheckpoint(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+	heightInDB, err := w.l2BlockOrm.GetL2BlocksLatestHeight(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint == nil {
+		checkpoint = &orm.L2WatcherCheckpoint{BlockNumber: heightInDB}
+		if heightInDB > 0 {
+			blocks, err := w.l2BlockOrm.GetL2BlocksInRange(w.ctx, heightInDB, heightInDB)
+			if err != nil {
+				return nil, err
+			}
+			if len(blocks) != 1 {
+				return nil, fmt.Errorf("latest l2 block not found, number: %v", heightInDB)
+			}
+			checkpoint.BlockHash = blocks[0].Header.Hash().String()
+			if err = w.l2WatcherCheckpointOrm.SaveL2WatcherCheckpoint(w.ctx, checkpoint); err != nil {
+				return nil, err
+			}
+			log.Info("initialized l2 watcher checkpoint from the latest stored block", "number", checkpoint.BlockNumber, "hash", checkpoint.BlockHash)
+		}
+	} else if heightInDB != checkpoint.BlockNumber {
+		return nil, fmt.Errorf("stored l2 blocks do not match the checkpoint, latest stored block: %v, checkpoint block: %v", heightInDB, checkpoint.BlockNumber)
+	}
+
+	if w.computeWithdrawRoot {
+		trie, err := w.loadWithdrawTrie(checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		w.withdrawTrie = trie
+	}
+
+	w.checkpoint = checkpoint
+	return checkpoint, nil
+}
+
+// loadWithdrawTrie restores the withdraw trie at the checkpoint. Without a persisted frontier, e.g. on the first run
+// computing the withdraw roots, it is rebuilt once from the AppendMessage events up to the checkpoint block and
+// checked against the root of the chain.
+func (w *L2WatcherClient) loadWithdrawTrie(checkpoint *orm.L2WatcherCheckpoint) (*withdrawTrie, error) {
+	if len(checkpoint.WithdrawTrieFrontier) > 0 {
+		return newWithdrawTrie(checkpoint.WithdrawTrieNextIndex, checkpoint.WithdrawTrieFrontier)
+	}
+
+	trie := &withdrawTrie{}
+	for from := uint64(0); from <= checkpoint.BlockNumber; from += withdrawTrieRebuildBlocks {
+		to := from + withdrawTrieRebuildBlocks - 1
+		if to > checkpoint.BlockNumber {
+			to = checkpoint.BlockNumber
+		}
+		events, err := w.getAppendMessageEvents(w.ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if err = trie.appendMessageHash(event.index, event.messageHash); err != nil {
+				return nil, fmt.Errorf("failed to rebuild withdraw trie: %w, number: %v", err, event.blockNumber)
+			}
+		}
+	}
+	if checkpoint.BlockNumber > 0 {
+		if err := w.VerifyWithdrawRoot(w.ctx, checkpoint.BlockNumber, trie.root()); err != nil {
+			return nil, fmt.Errorf("failed to verify rebuilt withdraw trie: %w", err)
+		}
+	}
+
+	checkpoint.WithdrawTrieNextIndex = trie.nextMessageIndex
+	checkpoint.WithdrawTrieFrontier = trie.frontier()
+	if err := w.l2WatcherCheckpointOrm.UpdateWithdrawTrie(w.ctx, checkpoint.BlockNumber, checkpoint.WithdrawTrieNextIndex, checkpoint.WithdrawTrieFrontier); err != nil {
+		return nil, err
+	}
+	log.Info("rebuilt withdraw trie", "number", checkpoint.BlockNumber, "next message index", trie.nextMessageIndex, "root", trie.root().String())
+	return trie, nil
+}
+
+// appendMessageEvent is an AppendMessage event of the L2MessageQueue contract.
+type appendMessageEvent struct {
+	blockNumber uint64
+	blockHash   common.Hash
+	index       uint64
+	messageHash common.Hash
+}
+
+// getAppendMessageEvents returns the AppendMessage events of the blocks [from, to], in order.
+func (w *L2WatcherClient) getAppendMessageEvents(ctx context.Context, from, to uint64) ([]appendMessageEvent, error) {
+	logs, err := w.FilterLogs(ctx, geth.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{w.messageQueueAddress},
+		Topics:    [][]common.Hash{{bridgeAbi.L2AppendMessageEventSignature}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter AppendMessage events: %w, from: %v, to: %v", err, from, to)
+	}
+
+	events := make([]appendMessageEvent, 0, len(logs))
+	for _, vLog := range logs {
+		event := bridgeAbi.L2AppendMessageEvent{}
+		if err = utils.UnpackLog(w.messageQueueABI, &event, "AppendMessage", vLog); err != nil {
+			return nil, fmt.Errorf("failed to unpack AppendMessage event: %w, number: %v", err, vLog.BlockNumber)
+		}
+		events = append(events, appendMessageEvent{
+			blockNumber: vLog.BlockNumber,
+			blockHash:   vLog.BlockHash,
+			index:       event.Index.Uint64(),
+			messageHash: event.MessageHash,
+		})
+	}
+	return events, nil
+}
+
 // TryFetchRunningMissingBlocks attempts to fetch and store block traces for any missing blocks.
 func (w *L2WatcherClient) TryFetchRunningMissingBlocks(blockHeight uint64) {
 	w.metrics.fetchRunningMissingBlocksTotal.Inc()
-	heightInDB, err := w.l2BlockOrm.GetL2BlocksLatestHeight(w.ctx)
+	checkpoint, err := w.loadCheckpoint()
 	if err != nil {
-		log.Error("failed to GetL2BlocksLatestHeight", "err", err)
+		log.Error("failed to load l2 watcher checkpoint", "err", err)
 		return
 	}
+	heightInDB := checkpoint.BlockNumber
 
 	// Fetch and store block traces for missing blocks
 	for from := heightInDB + 1; from <= blockHeight; from += blockTracesFetchLimit {
@@ -123,44 +334,133 @@ func txsToTxsData(txs gethTypes.Transactions) []*gethTypes.TransactionData {
 	return txsData
 }
 
-func (w *L2WatcherClient) getAndStoreBlocks(ctx context.Context, from, to uint64) error {
+func (w *L2WatcherClient) getAndStoreBlocks(ctx context.Context, from, to uint64) (err error) {
+	ctx, span := tracing.Start(ctx, "l2_watcher.get_and_store_blocks", "from", from, "to", to)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	trie := w.withdrawTrie.copy()
+	blocks, err := w.getBlocks(ctx, from, to, trie)
+	if err != nil {
+		return err
+	}
+
+	if len(blocks) > 0 {
+		// The blocks must extend the stored ones, a reorg or a concurrent writer would otherwise duplicate or skip blocks.
+		parentHash := w.checkpoint.BlockHash
+		for _, block := range blocks {
+			if parentHash != "" && block.Header.ParentHash.String() != parentHash {
+				return fmt.Errorf("fetched block does not extend the stored blocks. number: %v, parent hash: %v, expected: %v", block.Header.Number, block.Header.ParentHash.String(), parentHash)
+			}
+			parentHash = block.Header.Hash().String()
+
+			blockL1CommitCalldataSize, err := codecv0.EstimateBlockL1CommitCalldataSize(block)
+			if err != nil {
+				return fmt.Errorf("failed to estimate block L1 commit calldata size: %v", err)
+			}
+			w.metrics.rollupL2BlockL1CommitCalldataSize.Set(float64(blockL1CommitCalldataSize))
+		}
+
+		checkpoint := &orm.L2WatcherCheckpoint{BlockNumber: to, BlockHash: parentHash}
+		if trie != nil {
+			checkpoint.WithdrawTrieNextIndex = trie.nextMessageIndex
+			checkpoint.WithdrawTrieFrontier = trie.frontier()
+		}
+		err = w.db.Transaction(func(dbTX *gorm.DB) error {
+			if err := w.l2BlockOrm.InsertL2Blocks(ctx, blocks, dbTX); err != nil {
+				return fmt.Errorf("failed to batch insert BlockTraces: %v", err)
+			}
+			return w.l2WatcherCheckpointOrm.SaveL2WatcherCheckpoint(ctx, checkpoint, dbTX)
+		})
+		if err != nil {
+			return err
+		}
+		w.checkpoint = checkpoint
+		w.withdrawTrie = trie
+
+		select {
+		case w.blocksStored <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Backfill replays the ingestion of the blocks [from, to], e.g. to fill the rows of a new column or table. The blocks
+// already stored are kept so a range can be backfilled more than once. The blocks following the checkpoint are left
+// to the running watcher.
+func (w *L2WatcherClient) Backfill(ctx context.Context, cfg BackfillConfig, from, to uint64) error {
+	checkpoint, err := w.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+	if to > checkpoint.BlockNumber {
+		return fmt.Errorf("blocks after the l2 watcher checkpoint are not backfilled, to: %v, checkpoint block: %v", to, checkpoint.BlockNumber)
+	}
+	return backfill(ctx, cfg, from, to, blockTracesFetchLimit, w.backfillBlocks)
+}
+
+func (w *L2WatcherClient) backfillBlocks(ctx context.Context, from, to uint64) error {
+	blocks, err := w.getBlocks(ctx, from, to, nil)
+	if err != nil {
+		return err
+	}
+	return w.l2BlockOrm.InsertMissingL2Blocks(ctx, blocks)
+}
+
+// getBlocks fetches the blocks [from, to]. Their withdraw roots are computed by appending their AppendMessage events
+// to trie, or read from the chain when trie is nil.
+func (w *L2WatcherClient) getBlocks(ctx context.Context, from, to uint64, trie *withdrawTrie) ([]*encoding.Block, error) {
+	var events []appendMessageEvent
+	if trie != nil {
+		var err error
+		if events, err = w.getAppendMessageEvents(ctx, from, to); err != nil {
+			return nil, err
+		}
+	}
+
 	var blocks []*encoding.Block
 	for number := from; number <= to; number++ {
 		log.Debug("retrieving block", "height", number)
 		block, err := w.GetBlockByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
 		if err != nil {
-			return fmt.Errorf("failed to GetBlockByNumberOrHash: %v. number: %v", err, number)
+			return nil, fmt.Errorf("failed to GetBlockByNumberOrHash: %v. number: %v", err, number)
 		}
 		if block.RowConsumption == nil {
-			return fmt.Errorf("fetched block does not contain RowConsumption. number: %v", number)
+			return nil, fmt.Errorf("fetched block does not contain RowConsumption. number: %v", number)
 		}
 
 		log.Info("retrieved block", "height", block.Header().Number, "hash", block.Header().Hash().String())
 
-		withdrawRoot, err3 := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, big.NewInt(int64(number)))
-		if err3 != nil {
-			return fmt.Errorf("failed to get withdrawRoot: %v. number: %v", err3, number)
+		var withdrawRoot common.Hash
+		if trie != nil {
+			for len(events) > 0 && events[0].blockNumber == number {
+				// the events are filtered separately from the blocks, they may belong to a reorged block.
+				if events[0].blockHash != block.Header().Hash() {
+					return nil, fmt.Errorf("AppendMessage event does not belong to the fetched block. number: %v, event block hash: %v, block hash: %v", number, events[0].blockHash.String(), block.Header().Hash().String())
+				}
+				if err = trie.appendMessageHash(events[0].index, events[0].messageHash); err != nil {
+					return nil, fmt.Errorf("failed to append message to withdraw trie: %w, number: %v", err, number)
+				}
+				events = events[1:]
+			}
+			withdrawRoot = trie.root()
+		} else {
+			root, err3 := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, big.NewInt(int64(number)))
+			if err3 != nil {
+				return nil, fmt.Errorf("failed to get withdrawRoot: %v. number: %v", err3, number)
+			}
+			withdrawRoot = common.BytesToHash(root)
 		}
 		blocks = append(blocks, &encoding.Block{
 			Header:         block.Header(),
 			Transactions:   txsToTxsData(block.Transactions()),
-			WithdrawRoot:   common.BytesToHash(withdrawRoot),
+			WithdrawRoot:   withdrawRoot,
 			RowConsumption: block.RowConsumption,
 		})
 	}
-
-	if len(blocks) > 0 {
-		for _, block := range blocks {
-			blockL1CommitCalldataSize, err := codecv0.EstimateBlockL1CommitCalldataSize(block)
-			if err != nil {
-				return fmt.Errorf("failed to estimate block L1 commit calldata size: %v", err)
-			}
-			w.metrics.rollupL2BlockL1CommitCalldataSize.Set(float64(blockL1CommitCalldataSize))
-		}
-		if err := w.l2BlockOrm.InsertL2Blocks(w.ctx, blocks); err != nil {
-			return fmt.Errorf("failed to batch insert BlockTraces: %v", err)
-		}
-	}
-
-	return nil
+	return blocks, nil
 }
@@ -12,6 +12,7 @@ type l2WatcherMetrics struct {
 	fetchRunningMissingBlocksHeight   prometheus.Gauge
 	rollupL2BlocksFetchedGap          prometheus.Gauge
 	rollupL2BlockL1CommitCalldataSize prometheus.Gauge
+	withdrawRootMismatchTotal         prometheus.Counter
 }
 
 var (
@@ -38,6 +39,10 @@ func initL2WatcherMetrics(reg prometheus.Registerer) *l2WatcherMetrics {
 				Name: "rollup_l2_block_l1_commit_calldata_size",
 				Help: "The l1 commitBatch calldata size of the l2 block",
 			}),
+			withdrawRootMismatchTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_withdraw_root_mismatch_total",
+				Help: "The total number of computed withdraw roots which differ from the root of the chain",
+			}),
 		}
 	})
 	return l2WatcherMetric
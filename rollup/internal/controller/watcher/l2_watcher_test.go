@@ -52,6 +52,25 @@ func testFetchRunningMissingBlocks(t *testing.T) {
 		return err == nil && fetchedHeight == latestHeight
 	})
 	assert.True(t, ok)
+
+	// The checkpoint is saved along with the blocks and matches the chain on restart.
+	fetchedHeight, err := l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	checkpoint, err := orm.NewL2WatcherCheckpoint(db).GetL2WatcherCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, checkpoint)
+	assert.Equal(t, fetchedHeight, checkpoint.BlockNumber)
+	header, err := l2Cli.HeaderByNumber(context.Background(), new(big.Int).SetUint64(fetchedHeight))
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash().String(), checkpoint.BlockHash)
+
+	wc := prepareWatcherClient(l2Cli, db, address)
+	assert.NoError(t, wc.VerifyCheckpoint())
+
+	// A checkpoint diverging from the chain is rejected.
+	assert.NoError(t, orm.NewL2WatcherCheckpoint(db).SaveL2WatcherCheckpoint(context.Background(), &orm.L2WatcherCheckpoint{BlockNumber: fetchedHeight + 1, BlockHash: common.Hash{}.String()}))
+	wc = prepareWatcherClient(l2Cli, db, address)
+	assert.Error(t, wc.VerifyCheckpoint())
 }
 
 func prepareWatcherClient(l2Cli *ethclient.Client, db *gorm.DB, contractAddr common.Address) *L2WatcherClient {
@@ -0,0 +1,156 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+const skippedTransactionsFetchLimit = uint64(100)
+
+// skippedTransaction is the response of scroll_getSkippedTransaction, only the fields of L1 messages are kept.
+type skippedTransaction struct {
+	Hash            common.Hash     `json:"hash"`
+	Type            hexutil.Uint64  `json:"type"`
+	To              *common.Address `json:"to"`
+	Value           *hexutil.Big    `json:"value"`
+	Gas             hexutil.Uint64  `json:"gas"`
+	Input           hexutil.Bytes   `json:"input"`
+	Sender          *common.Address `json:"sender,omitempty"`
+	QueueIndex      *hexutil.Uint64 `json:"queueIndex,omitempty"`
+	SkipReason      string          `json:"skipReason"`
+	SkipBlockNumber *hexutil.Big    `json:"skipBlockNumber"`
+	SkipBlockHash   *common.Hash    `json:"skipBlockHash,omitempty"`
+}
+
+// SkippedL1MessageWatcher persists the L1 messages skipped by l2geth, e.g. because their execution exceeds the
+// circuit capacity, so that they can be listed and replayed with another gas limit.
+type SkippedL1MessageWatcher struct {
+	ctx    context.Context
+	client *rpc.Client
+
+	skippedL1MessageOrm *orm.SkippedL1Message
+
+	// nextSkipIndex is the next skip index to fetch, skipped L2 transactions are not stored so it is kept in memory
+	// once loaded from the database.
+	nextSkipIndex *uint64
+
+	fetchSkippedL1MessagesTotal prometheus.Counter
+	skippedL1MessagesTotal      prometheus.Counter
+}
+
+// NewSkippedL1MessageWatcher creates a new SkippedL1MessageWatcher instance.
+func NewSkippedL1MessageWatcher(ctx context.Context, client *rpc.Client, db *gorm.DB, reg prometheus.Registerer) *SkippedL1MessageWatcher {
+	return &SkippedL1MessageWatcher{
+		ctx:                 ctx,
+		client:              client,
+		skippedL1MessageOrm: orm.NewSkippedL1Message(db),
+
+		fetchSkippedL1MessagesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l2_watcher_fetch_skipped_l1_messages_total",
+			Help: "The total number of l2 watcher fetch skipped l1 messages attempts",
+		}),
+		skippedL1MessagesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l2_watcher_skipped_l1_messages_total",
+			Help: "The total number of skipped l1 messages persisted by the l2 watcher",
+		}),
+	}
+}
+
+// TryFetchSkippedL1Messages fetches the transactions skipped by l2geth since the last fetch and stores the L1 messages.
+func (w *SkippedL1MessageWatcher) TryFetchSkippedL1Messages() {
+	w.fetchSkippedL1MessagesTotal.Inc()
+
+	if w.nextSkipIndex == nil {
+		nextSkipIndex, err := w.skippedL1MessageOrm.GetNextSkipIndex(w.ctx)
+		if err != nil {
+			log.Error("failed to get next skip index", "err", err)
+			return
+		}
+		w.nextSkipIndex = &nextSkipIndex
+	}
+
+	var numSkipped uint64
+	if err := w.client.CallContext(w.ctx, &numSkipped, "scroll_getNumSkippedTransactions"); err != nil {
+		log.Error("failed to get number of skipped transactions", "err", err)
+		return
+	}
+
+	for from := *w.nextSkipIndex; from < numSkipped; from += skippedTransactionsFetchLimit {
+		to := from + skippedTransactionsFetchLimit - 1
+		if to >= numSkipped {
+			to = numSkipped - 1
+		}
+
+		if err := w.getAndStoreSkippedL1Messages(from, to); err != nil {
+			log.Error("failed to get and store skipped l1 messages", "from", from, "to", to, "err", err)
+			return
+		}
+		*w.nextSkipIndex = to + 1
+	}
+}
+
+func (w *SkippedL1MessageWatcher) getAndStoreSkippedL1Messages(from, to uint64) error {
+	var hashes []common.Hash
+	if err := w.client.CallContext(w.ctx, &hashes, "scroll_getSkippedTransactionHashes", from, to); err != nil {
+		return fmt.Errorf("failed to get skipped transaction hashes, err: %w", err)
+	}
+
+	var messages []*orm.SkippedL1Message
+	for i, hash := range hashes {
+		var tx *skippedTransaction
+		if err := w.client.CallContext(w.ctx, &tx, "scroll_getSkippedTransaction", hash); err != nil {
+			return fmt.Errorf("failed to get skipped transaction, hash: %v, err: %w", hash.Hex(), err)
+		}
+		if tx == nil || uint8(tx.Type) != gethTypes.L1MessageTxType || tx.QueueIndex == nil {
+			continue
+		}
+
+		message := &orm.SkippedL1Message{
+			QueueIndex: uint64(*tx.QueueIndex),
+			SkipIndex:  from + uint64(i),
+			TxHash:     tx.Hash.Hex(),
+			GasLimit:   uint64(tx.Gas),
+			Calldata:   common.Bytes2Hex(tx.Input),
+			Value:      "0",
+			SkipReason: tx.SkipReason,
+			Status:     int16(types.SkippedL1MessageStatusSkipped),
+		}
+		if tx.Sender != nil {
+			message.Sender = tx.Sender.Hex()
+		}
+		if tx.To != nil {
+			message.Target = tx.To.Hex()
+		}
+		if tx.Value != nil {
+			message.Value = tx.Value.ToInt().String()
+		}
+		if tx.SkipBlockNumber != nil {
+			message.SkipBlockNumber = tx.SkipBlockNumber.ToInt().Uint64()
+		}
+		if tx.SkipBlockHash != nil {
+			message.SkipBlockHash = tx.SkipBlockHash.Hex()
+		}
+		messages = append(messages, message)
+
+		log.Warn("l1 message skipped by l2geth", "queue index", message.QueueIndex, "tx hash", message.TxHash, "reason", message.SkipReason, "block number", message.SkipBlockNumber)
+	}
+
+	if err := w.skippedL1MessageOrm.InsertSkippedL1Messages(w.ctx, messages); err != nil {
+		return fmt.Errorf("failed to insert skipped l1 messages, err: %w", err)
+	}
+	w.skippedL1MessagesTotal.Add(float64(len(messages)))
+	return nil
+}
@@ -105,17 +105,24 @@ func TestFunction(t *testing.T) {
 	t.Run("TestStartWatcher", testFetchContractEvent)
 	t.Run("TestL1WatcherClientFetchBlockHeader", testL1WatcherClientFetchBlockHeader)
 	t.Run("TestL1WatcherClientFetchContractEvent", testL1WatcherClientFetchContractEvent)
+	t.Run("TestL1WatcherClientReorg", testL1WatcherClientReorg)
+	t.Run("TestL1WatcherClientEventConfirmations", testL1WatcherClientEventConfirmations)
 	t.Run("TestParseBridgeEventLogsL1QueueTransactionEventSignature", testParseBridgeEventLogsL1QueueTransactionEventSignature)
 	t.Run("TestParseBridgeEventLogsL1CommitBatchEventSignature", testParseBridgeEventLogsL1CommitBatchEventSignature)
 	t.Run("TestParseBridgeEventLogsL1FinalizeBatchEventSignature", testParseBridgeEventLogsL1FinalizeBatchEventSignature)
+	t.Run("TestL1SubscriptionCoverage", testL1SubscriptionCoverage)
 
 	// Run l2 watcher test cases.
 	t.Run("TestFetchRunningMissingBlocks", testFetchRunningMissingBlocks)
+	t.Run("TestBackfill", testBackfill)
 
 	// Run chunk proposer test cases.
 	t.Run("TestChunkProposerLimits", testChunkProposerLimits)
+	t.Run("TestChunkProposerDynamicSizing", testChunkProposerDynamicSizing)
+	t.Run("TestChunkProposerConstraint", testChunkProposerConstraint)
 
 	// Run chunk proposer test cases.
 	t.Run("TestBatchProposerLimits", testBatchProposerLimits)
 	t.Run("TestBatchCommitGasAndCalldataSizeEstimation", testBatchCommitGasAndCalldataSizeEstimation)
+	t.Run("TestBatchProposerDynamicSizing", testBatchProposerDynamicSizing)
 }
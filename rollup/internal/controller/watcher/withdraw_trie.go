@@ -0,0 +1,94 @@
+package watcher
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+)
+
+// withdrawTrieHeight is the maximum height of the append only merkle tree of the L2MessageQueue contract.
+const withdrawTrieHeight = 40
+
+// withdrawTrieZeroHashes are the roots of the empty subtrees of every height.
+var withdrawTrieZeroHashes [withdrawTrieHeight]common.Hash
+
+func init() {
+	for height := 1; height < withdrawTrieHeight; height++ {
+		withdrawTrieZeroHashes[height] = crypto.Keccak256Hash(withdrawTrieZeroHashes[height-1][:], withdrawTrieZeroHashes[height-1][:])
+	}
+}
+
+// withdrawTrie is the withdraw trie of the L2MessageQueue contract. Like the contract, only its frontier is kept: the
+// last left node of every height, which is all that appending a message and computing the root require.
+type withdrawTrie struct {
+	nextMessageIndex uint64
+	branches         [withdrawTrieHeight]common.Hash
+}
+
+// newWithdrawTrie restores the withdraw trie of nextMessageIndex messages from its frontier, an empty frontier is the
+// empty trie.
+func newWithdrawTrie(nextMessageIndex uint64, frontier []byte) (*withdrawTrie, error) {
+	t := &withdrawTrie{nextMessageIndex: nextMessageIndex}
+	if len(frontier) == 0 && nextMessageIndex == 0 {
+		return t, nil
+	}
+	if len(frontier) != withdrawTrieHeight*common.HashLength {
+		return nil, fmt.Errorf("invalid withdraw trie frontier length: %v, next message index: %v", len(frontier), nextMessageIndex)
+	}
+	for height := range t.branches {
+		t.branches[height] = common.BytesToHash(frontier[height*common.HashLength : (height+1)*common.HashLength])
+	}
+	return t, nil
+}
+
+// appendMessageHash appends the hash of the message of index, as L2MessageQueue.appendMessage does. The messages must
+// be appended in order.
+func (t *withdrawTrie) appendMessageHash(index uint64, messageHash common.Hash) error {
+	if index != t.nextMessageIndex {
+		return fmt.Errorf("unexpected withdraw trie message index: %v, expected: %v", index, t.nextMessageIndex)
+	}
+
+	hash := messageHash
+	height := 0
+	for current := index; current != 0; current >>= 1 {
+		if current%2 == 0 {
+			// a left child, its right sibling is empty until the next message.
+			t.branches[height] = hash
+			hash = crypto.Keccak256Hash(hash[:], withdrawTrieZeroHashes[height][:])
+		} else {
+			hash = crypto.Keccak256Hash(t.branches[height][:], hash[:])
+		}
+		height++
+	}
+	t.branches[height] = hash
+	t.nextMessageIndex++
+	return nil
+}
+
+// root returns the withdraw root, the zero hash before the first message.
+func (t *withdrawTrie) root() common.Hash {
+	if t.nextMessageIndex == 0 {
+		return common.Hash{}
+	}
+	return t.branches[bits.Len64(t.nextMessageIndex-1)]
+}
+
+// frontier returns the branches of the trie, to be restored with newWithdrawTrie.
+func (t *withdrawTrie) frontier() []byte {
+	frontier := make([]byte, 0, withdrawTrieHeight*common.HashLength)
+	for _, branch := range t.branches {
+		frontier = append(frontier, branch[:]...)
+	}
+	return frontier
+}
+
+// copy returns a copy of the trie, nil if the trie is nil.
+func (t *withdrawTrie) copy() *withdrawTrie {
+	if t == nil {
+		return nil
+	}
+	cpy := *t
+	return &cpy
+}
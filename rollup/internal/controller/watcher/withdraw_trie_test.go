@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"math/big"
+	"math/bits"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fullWithdrawRoot computes the withdraw root of the message hashes from all the leaves of the trie.
+func fullWithdrawRoot(hashes []common.Hash) common.Hash {
+	if len(hashes) == 0 {
+		return common.Hash{}
+	}
+	nodes := make([]common.Hash, 1<<bits.Len64(uint64(len(hashes)-1)))
+	copy(nodes, hashes)
+	for len(nodes) > 1 {
+		for i := 0; i < len(nodes)/2; i++ {
+			nodes[i] = crypto.Keccak256Hash(nodes[2*i][:], nodes[2*i+1][:])
+		}
+		nodes = nodes[:len(nodes)/2]
+	}
+	return nodes[0]
+}
+
+func TestWithdrawTrie(t *testing.T) {
+	trie, err := newWithdrawTrie(0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{}, trie.root())
+
+	var hashes []common.Hash
+	for i := uint64(0); i < 33; i++ {
+		hash := crypto.Keccak256Hash(new(big.Int).SetUint64(i).Bytes())
+		hashes = append(hashes, hash)
+		require.NoError(t, trie.appendMessageHash(i, hash))
+		assert.Equal(t, fullWithdrawRoot(hashes), trie.root(), "messages: %v", len(hashes))
+
+		// the trie restored from its frontier appends the next messages alike.
+		restored, err := newWithdrawTrie(trie.nextMessageIndex, trie.frontier())
+		require.NoError(t, err)
+		assert.Equal(t, trie, restored)
+	}
+
+	// the messages are appended in order.
+	assert.Error(t, trie.appendMessageHash(32, common.Hash{}))
+	assert.Error(t, trie.appendMessageHash(34, common.Hash{}))
+
+	// a copy is appended to independently.
+	cpy := trie.copy()
+	require.NoError(t, cpy.appendMessageHash(33, common.Hash{0x01}))
+	assert.Equal(t, fullWithdrawRoot(hashes), trie.root())
+	assert.Nil(t, (*withdrawTrie)(nil).copy())
+
+	_, err = newWithdrawTrie(1, []byte{0x01})
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/utils"
+)
+
+// Type is the type of a lifecycle event.
+type Type string
+
+// The lifecycle events of chunks and batches.
+const (
+	// ChunkProposed is published once a chunk is stored by the chunk proposer.
+	ChunkProposed Type = "chunk_proposed"
+	// BatchCommitted is published once a commit transaction is confirmed on L1, for the last batch it commits.
+	BatchCommitted Type = "batch_committed"
+	// ProofReceived is published once the proof of a batch is verified by the coordinator.
+	ProofReceived Type = "proof_received"
+	// BatchFinalized is published once a finalize transaction is confirmed on L1, for the last batch it finalizes.
+	BatchFinalized Type = "batch_finalized"
+	// WithdrawRootUpdated is published along with BatchFinalized, the withdraw root of the batch is finalized on L1.
+	WithdrawRootUpdated Type = "withdraw_root_updated"
+)
+
+// Event is a lifecycle event of a chunk or a batch.
+type Event struct {
+	Type Type `json:"type"`
+	// Index and Hash identify the chunk of ChunkProposed and the batch otherwise.
+	Index uint64 `json:"index"`
+	Hash  string `json:"hash"`
+	// TxHash is the L1 transaction of BatchCommitted, BatchFinalized and WithdrawRootUpdated.
+	TxHash string `json:"tx_hash,omitempty"`
+	// WithdrawRoot is the withdraw root of WithdrawRootUpdated.
+	WithdrawRoot string    `json:"withdraw_root,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// Bus delivers the lifecycle events published by the proposers and the relayer to the subscribed subsystems, so that
+// they don't poll the database. Publishing never blocks, the events are dropped for subscribers whose buffer is full.
+// A nil Bus drops every event.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+
+	publishedTotal *prometheus.CounterVec
+	droppedTotal   *prometheus.CounterVec
+}
+
+type subscriber struct {
+	types map[Type]bool
+	ch    chan Event
+}
+
+// NewBus creates a new Bus instance.
+func NewBus(reg prometheus.Registerer) *Bus {
+	return &Bus{
+		subscribers: make(map[*subscriber]struct{}),
+		publishedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_events_published_total",
+			Help: "The total number of published lifecycle events.",
+		}, []string{"type"}),
+		droppedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_events_dropped_total",
+			Help: "The total number of lifecycle events dropped for a subscriber whose buffer is full.",
+		}, []string{"type"}),
+	}
+}
+
+// Subscribe returns a channel receiving the events of the types, every type when none is given, until ctx is done.
+// The channel is buffered with size events and closed once ctx is done.
+func (b *Bus) Subscribe(ctx context.Context, size int, types ...Type) <-chan Event {
+	s := &subscriber{ch: make(chan Event, size)}
+	if len(types) > 0 {
+		s.types = make(map[Type]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[s] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, s)
+		close(s.ch)
+		b.mu.Unlock()
+	}()
+	return s.ch
+}
+
+// Publish delivers the event to the subscribers of its type, the time is set when it is zero.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = utils.NowUTC()
+	}
+	b.publishedTotal.WithLabelValues(string(event.Type)).Inc()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subscribers {
+		if s.types != nil && !s.types[event.Type] {
+			continue
+		}
+		select {
+		case s.ch <- event:
+		default:
+			b.droppedTotal.WithLabelValues(string(event.Type)).Inc()
+			log.Warn("lifecycle event dropped, the subscriber buffer is full", "type", event.Type, "index", event.Index, "hash", event.Hash)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	// a nil bus drops every event.
+	var nilBus *Bus
+	nilBus.Publish(Event{Type: ChunkProposed})
+
+	b := NewBus(prometheus.NewRegistry())
+	ctx, cancel := context.WithCancel(context.Background())
+	all := b.Subscribe(ctx, 3)
+	finalized := b.Subscribe(context.Background(), 1, BatchFinalized, WithdrawRootUpdated)
+
+	b.Publish(Event{Type: ChunkProposed, Index: 1, Hash: "0x01"})
+	b.Publish(Event{Type: BatchFinalized, Index: 2, Hash: "0x02"})
+	// the buffer of finalized is full, the event is dropped for it only.
+	b.Publish(Event{Type: WithdrawRootUpdated, Index: 2, Hash: "0x02", WithdrawRoot: "0x03"})
+
+	event := <-all
+	assert.Equal(t, ChunkProposed, event.Type)
+	assert.Equal(t, uint64(1), event.Index)
+	assert.False(t, event.Time.IsZero())
+	assert.Equal(t, BatchFinalized, (<-all).Type)
+	assert.Equal(t, "0x03", (<-all).WithdrawRoot)
+	assert.Equal(t, BatchFinalized, (<-finalized).Type)
+	assert.Empty(t, finalized)
+
+	// the channel is closed once the context is done.
+	cancel()
+	_, ok := <-all
+	assert.False(t, ok)
+	b.Publish(Event{Type: BatchFinalized})
+	assert.Len(t, finalized, 1)
+}
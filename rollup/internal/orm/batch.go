@@ -13,6 +13,7 @@ import (
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 )
@@ -32,6 +33,7 @@ type Batch struct {
 	WithdrawRoot    string `json:"withdraw_root" gorm:"column:withdraw_root"`
 	ParentBatchHash string `json:"parent_batch_hash" gorm:"column:parent_batch_hash"`
 	BatchHeader     []byte `json:"batch_header" gorm:"column:batch_header"`
+	CodecVersion    int16  `json:"codec_version" gorm:"column:codec_version"`
 
 	// proof
 	ChunkProofsStatus int16      `json:"chunk_proofs_status" gorm:"column:chunk_proofs_status;default:1"`
@@ -48,6 +50,9 @@ type Batch struct {
 	FinalizeTxHash string     `json:"finalize_tx_hash" gorm:"column:finalize_tx_hash;default:NULL"`
 	FinalizedAt    *time.Time `json:"finalized_at" gorm:"column:finalized_at;default:NULL"`
 
+	// data availability
+	DAVerificationStatus int16 `json:"da_verification_status" gorm:"column:da_verification_status;default:1"`
+
 	// gas oracle
 	OracleStatus int16  `json:"oracle_status" gorm:"column:oracle_status;default:1"`
 	OracleTxHash string `json:"oracle_tx_hash" gorm:"column:oracle_tx_hash;default:NULL"`
@@ -55,6 +60,7 @@ type Batch struct {
 	// metadata
 	TotalL1CommitGas          uint64         `json:"total_l1_commit_gas" gorm:"column:total_l1_commit_gas;default:0"`
 	TotalL1CommitCalldataSize uint64         `json:"total_l1_commit_calldata_size" gorm:"column:total_l1_commit_calldata_size;default:0"`
+	Version                   int64          `json:"version" gorm:"column:version;default:0"`
 	CreatedAt                 time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt                 time.Time      `json:"updated_at" gorm:"column:updated_at"`
 	DeletedAt                 gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
@@ -212,6 +218,19 @@ func (o *Batch) GetFailedAndPendingBatches(ctx context.Context, limit int) ([]*B
 	return batches, nil
 }
 
+// GetFailedAndPendingBatchCount retrieves the number of batches with failed or pending status.
+func (o *Batch) GetFailedAndPendingBatchCount(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("rollup_status = ? OR rollup_status = ?", types.RollupCommitFailed, types.RollupPending)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.GetFailedAndPendingBatchCount error: %w", err)
+	}
+	return uint64(count), nil
+}
+
 // GetBatchByIndex retrieves the batch by the given index.
 func (o *Batch) GetBatchByIndex(ctx context.Context, index uint64) (*Batch, error) {
 	db := o.db.WithContext(ctx)
@@ -225,39 +244,23 @@ func (o *Batch) GetBatchByIndex(ctx context.Context, index uint64) (*Batch, erro
 	return &batch, nil
 }
 
-// InsertBatch inserts a new batch into the database.
-func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, dbTX ...*gorm.DB) (*Batch, error) {
+// InsertBatch inserts a new batch encoded with the given codec version into the database.
+func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVersion uint8, dbTX ...*gorm.DB) (*Batch, error) {
 	if batch == nil {
 		return nil, errors.New("invalid args: batch is nil")
 	}
 
-	daBatch, err := codecv0.NewDABatch(batch)
-	if err != nil {
-		log.Error("failed to create new DA batch",
-			"index", batch.Index, "total l1 message popped before", batch.TotalL1MessagePoppedBefore,
-			"parent hash", batch.ParentBatchHash, "number of chunks", len(batch.Chunks), "err", err)
-		return nil, err
-	}
-
-	totalL1CommitGas, err := codecv0.EstimateBatchL1CommitGas(batch)
+	batchMeta, err := getBatchMeta(batch, codecVersion)
 	if err != nil {
-		log.Error("failed to estimate batch L1 commit gas",
+		log.Error("failed to get batch meta",
 			"index", batch.Index, "total l1 message popped before", batch.TotalL1MessagePoppedBefore,
-			"parent hash", batch.ParentBatchHash, "number of chunks", len(batch.Chunks), "err", err)
-		return nil, fmt.Errorf("Batch.InsertBatch error: %w", err)
-	}
-
-	totalL1CommitCalldataSize, err := codecv0.EstimateBatchL1CommitCalldataSize(batch)
-	if err != nil {
-		log.Error("failed to estimate batch L1 commit calldata size",
-			"index", batch.Index, "total l1 message popped before", batch.TotalL1MessagePoppedBefore,
-			"parent hash", batch.ParentBatchHash, "number of chunks", len(batch.Chunks), "err", err)
+			"parent hash", batch.ParentBatchHash, "number of chunks", len(batch.Chunks), "codec version", codecVersion, "err", err)
 		return nil, fmt.Errorf("Batch.InsertBatch error: %w", err)
 	}
 
 	newBatch := Batch{
 		Index:                     batch.Index,
-		Hash:                      daBatch.Hash().Hex(),
+		Hash:                      batchMeta.hash.Hex(),
 		StartChunkHash:            batch.StartChunkHash.Hex(),
 		StartChunkIndex:           batch.StartChunkIndex,
 		EndChunkHash:              batch.EndChunkHash.Hex(),
@@ -265,13 +268,14 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, dbTX ...
 		StateRoot:                 batch.StateRoot().Hex(),
 		WithdrawRoot:              batch.WithdrawRoot().Hex(),
 		ParentBatchHash:           batch.ParentBatchHash.Hex(),
-		BatchHeader:               daBatch.Encode(),
+		BatchHeader:               batchMeta.batchHeader,
+		CodecVersion:              int16(codecVersion),
 		ChunkProofsStatus:         int16(types.ChunkProofsStatusPending),
 		ProvingStatus:             int16(types.ProvingTaskUnassigned),
 		RollupStatus:              int16(types.RollupPending),
 		OracleStatus:              int16(types.GasOraclePending),
-		TotalL1CommitGas:          totalL1CommitGas,
-		TotalL1CommitCalldataSize: totalL1CommitCalldataSize,
+		TotalL1CommitGas:          batchMeta.totalL1CommitGas,
+		TotalL1CommitCalldataSize: batchMeta.totalL1CommitCalldataSize,
 	}
 
 	db := o.db
@@ -288,6 +292,41 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, dbTX ...
 	return &newBatch, nil
 }
 
+// batchMeta is the encoding dependent metadata of a batch.
+type batchMeta struct {
+	hash                      common.Hash
+	batchHeader               []byte
+	totalL1CommitGas          uint64
+	totalL1CommitCalldataSize uint64
+}
+
+// getBatchMeta encodes the batch header of batch with the given codec version and estimates its commit cost.
+func getBatchMeta(batch *encoding.Batch, codecVersion uint8) (*batchMeta, error) {
+	switch codecVersion {
+	case codecv0.CodecV0Version:
+		daBatch, err := codecv0.NewDABatch(batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new DA batch: %w", err)
+		}
+		totalL1CommitGas, err := codecv0.EstimateBatchL1CommitGas(batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate batch L1 commit gas: %w", err)
+		}
+		totalL1CommitCalldataSize, err := codecv0.EstimateBatchL1CommitCalldataSize(batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate batch L1 commit calldata size: %w", err)
+		}
+		return &batchMeta{
+			hash:                      daBatch.Hash(),
+			batchHeader:               daBatch.Encode(),
+			totalL1CommitGas:          totalL1CommitGas,
+			totalL1CommitCalldataSize: totalL1CommitCalldataSize,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported codec version: %d", codecVersion)
+	}
+}
+
 // UpdateL2GasOracleStatusAndOracleTxHash updates the L2 gas oracle status and transaction hash for a batch.
 func (o *Batch) UpdateL2GasOracleStatusAndOracleTxHash(ctx context.Context, hash string, status types.GasOracleStatus, txHash string) error {
 	updateFields := make(map[string]interface{})
@@ -308,6 +347,7 @@ func (o *Batch) UpdateL2GasOracleStatusAndOracleTxHash(ctx context.Context, hash
 func (o *Batch) UpdateProvingStatus(ctx context.Context, hash string, status types.ProvingStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["proving_status"] = int(status)
+	updateFields["version"] = gorm.Expr("version + 1")
 
 	switch status {
 	case types.ProvingTaskAssigned:
@@ -332,14 +372,28 @@ func (o *Batch) UpdateProvingStatus(ctx context.Context, hash string, status typ
 	return nil
 }
 
+// UpdateDAVerificationStatus updates the da verification status of a batch.
+func (o *Batch) UpdateDAVerificationStatus(ctx context.Context, hash string, status types.DAVerificationStatus) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Update("da_verification_status", int(status)).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateDAVerificationStatus error: %w, batch hash: %v, status: %v", err, hash, status.String())
+	}
+	return nil
+}
+
 // UpdateRollupStatus updates the rollup status of a batch.
 func (o *Batch) UpdateRollupStatus(ctx context.Context, hash string, status types.RollupStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["rollup_status"] = int(status)
+	updateFields["version"] = gorm.Expr("version + 1")
 
 	switch status {
 	case types.RollupCommitted:
 		updateFields["committed_at"] = utils.NowUTC()
+		updateFields["da_verification_status"] = int(types.DAVerificationPending)
 	case types.RollupFinalized:
 		updateFields["finalized_at"] = utils.NowUTC()
 	}
@@ -359,15 +413,21 @@ func (o *Batch) UpdateRollupStatus(ctx context.Context, hash string, status type
 }
 
 // UpdateCommitTxHashAndRollupStatus updates the commit transaction hash and rollup status for a batch.
-func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash string, commitTxHash string, status types.RollupStatus) error {
+func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash string, commitTxHash string, status types.RollupStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["commit_tx_hash"] = commitTxHash
 	updateFields["rollup_status"] = int(status)
+	updateFields["version"] = gorm.Expr("version + 1")
 	if status == types.RollupCommitted {
 		updateFields["committed_at"] = utils.NowUTC()
+		updateFields["da_verification_status"] = int(types.DAVerificationPending)
 	}
 
-	db := o.db.WithContext(ctx)
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("hash", hash)
 
@@ -378,15 +438,20 @@ func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash stri
 }
 
 // UpdateFinalizeTxHashAndRollupStatus updates the finalize transaction hash and rollup status for a batch.
-func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash string, finalizeTxHash string, status types.RollupStatus) error {
+func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash string, finalizeTxHash string, status types.RollupStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["finalize_tx_hash"] = finalizeTxHash
 	updateFields["rollup_status"] = int(status)
+	updateFields["version"] = gorm.Expr("version + 1")
 	if status == types.RollupFinalized {
 		updateFields["finalized_at"] = time.Now()
 	}
 
-	db := o.db.WithContext(ctx)
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("hash", hash)
 
@@ -396,6 +461,126 @@ func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash st
 	return nil
 }
 
+// CompareAndSwapCommitTxHashAndRollupStatus updates the commit transaction hash and rollup status of a batch, only if
+// the batch did not change since it was read, e.g. reverted to be proposed again. Otherwise nothing is updated and a
+// types.StatusConflictError is returned. The version of the given batch is bumped on success.
+func (o *Batch) CompareAndSwapCommitTxHashAndRollupStatus(ctx context.Context, batch *Batch, commitTxHash string, status types.RollupStatus) error {
+	updateFields := make(map[string]interface{})
+	updateFields["commit_tx_hash"] = commitTxHash
+	updateFields["rollup_status"] = int(status)
+	if status == types.RollupCommitted {
+		updateFields["committed_at"] = utils.NowUTC()
+		updateFields["da_verification_status"] = int(types.DAVerificationPending)
+	}
+
+	if err := o.compareAndSwap(ctx, o.db, batch.Hash, batch.Version, updateFields); err != nil {
+		return fmt.Errorf("Batch.CompareAndSwapCommitTxHashAndRollupStatus error: %w, batch hash: %v, status: %v, commitTxHash: %v", err, batch.Hash, status.String(), commitTxHash)
+	}
+	batch.Version++
+	return nil
+}
+
+// CompareAndSwapFinalizeTxHashAndRollupStatus updates the finalize transaction hash and rollup status of a batch, only
+// if the batch is still at the given version. Otherwise nothing is updated and a types.StatusConflictError is returned.
+func (o *Batch) CompareAndSwapFinalizeTxHashAndRollupStatus(ctx context.Context, hash string, version int64, finalizeTxHash string, status types.RollupStatus) error {
+	updateFields := make(map[string]interface{})
+	updateFields["finalize_tx_hash"] = finalizeTxHash
+	updateFields["rollup_status"] = int(status)
+	if status == types.RollupFinalized {
+		updateFields["finalized_at"] = time.Now()
+	}
+
+	if err := o.compareAndSwap(ctx, o.db, hash, version, updateFields); err != nil {
+		return fmt.Errorf("Batch.CompareAndSwapFinalizeTxHashAndRollupStatus error: %w, batch hash: %v, status: %v, finalizeTxHash: %v", err, hash, status.String(), finalizeTxHash)
+	}
+	return nil
+}
+
+// compareAndSwap updates the fields of a batch and bumps its version, only if the batch is still at the given version.
+func (o *Batch) compareAndSwap(ctx context.Context, db *gorm.DB, hash string, version int64, updateFields map[string]interface{}) error {
+	updateFields["version"] = gorm.Expr("version + 1")
+
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ? AND version = ?", hash, version)
+
+	result := db.Updates(updateFields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &types.StatusConflictError{Table: o.TableName(), Hash: hash, Version: version}
+	}
+	return nil
+}
+
+// DeleteBatchesGEIndex soft deletes the batches whose index is greater than or equal to the given index,
+// so that the batch proposer proposes them again on top of the remaining latest batch.
+func (o *Batch) DeleteBatchesGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("index >= ?", index)
+
+	if err := db.Delete(&Batch{}).Error; err != nil {
+		return fmt.Errorf("Batch.DeleteBatchesGEIndex error: %w, index: %v", err, index)
+	}
+	return nil
+}
+
+// ResetUncommittedBatchesGEIndex resets the batches whose index is greater than or equal to the given index and whose
+// commit is in flight or failed to pending, so that the relayer commits them again. It returns the number of reset batches.
+func (o *Batch) ResetUncommittedBatchesGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) (int64, error) {
+	updateFields := make(map[string]interface{})
+	updateFields["rollup_status"] = int(types.RollupPending)
+	updateFields["commit_tx_hash"] = ""
+	updateFields["committed_at"] = nil
+	updateFields["version"] = gorm.Expr("version + 1")
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("index >= ?", index)
+	db = db.Where("rollup_status IN ?", []int{int(types.RollupCommitting), int(types.RollupCommitFailed)})
+
+	result := db.Updates(updateFields)
+	if result.Error != nil {
+		return 0, fmt.Errorf("Batch.ResetUncommittedBatchesGEIndex error: %w, index: %v", result.Error, index)
+	}
+	return result.RowsAffected, nil
+}
+
+// ResetProof drops the proof of a batch and its proving attempts, so that the coordinator assigns it again.
+func (o *Batch) ResetProof(ctx context.Context, hash string, dbTX ...*gorm.DB) error {
+	updateFields := make(map[string]interface{})
+	updateFields["proving_status"] = int(types.ProvingTaskUnassigned)
+	updateFields["proof"] = nil
+	updateFields["prover_assigned_at"] = nil
+	updateFields["proved_at"] = nil
+	updateFields["total_attempts"] = 0
+	updateFields["active_attempts"] = 0
+	updateFields["version"] = gorm.Expr("version + 1")
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.ResetProof error: %w, batch hash: %v", err, hash)
+	}
+	return nil
+}
+
 // UpdateProofByHash updates the batch proof by hash.
 // for unit test.
 func (o *Batch) UpdateProofByHash(ctx context.Context, hash string, proof *message.BatchProof, proofTimeSec uint64) error {
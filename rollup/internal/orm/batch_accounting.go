@@ -0,0 +1,131 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BatchAccounting represents the L1 fees paid to commit and finalize a batch and the L2 fees collected in its blocks.
+// The fees are decimal strings in wei.
+type BatchAccounting struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	// batch
+	BatchIndex uint64 `json:"batch_index" gorm:"column:batch_index"`
+	BatchHash  string `json:"batch_hash" gorm:"column:batch_hash"`
+
+	// l1 fees, the fees of a transaction are split evenly between the batches it commits or finalizes
+	CommitTxHash   string `json:"commit_tx_hash" gorm:"column:commit_tx_hash"`
+	CommitFee      string `json:"commit_fee" gorm:"column:commit_fee"`
+	CommitBlobFee  string `json:"commit_blob_fee" gorm:"column:commit_blob_fee"`
+	FinalizeTxHash string `json:"finalize_tx_hash" gorm:"column:finalize_tx_hash;default:''"`
+	FinalizeFee    string `json:"finalize_fee" gorm:"column:finalize_fee;default:0"`
+
+	// l2 fees
+	L2Fee string `json:"l2_fee" gorm:"column:l2_fee"`
+	// Margin is the L2 fee minus the L1 fees, negative for an unprofitable batch.
+	Margin string `json:"margin" gorm:"column:margin"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewBatchAccounting creates a new BatchAccounting database instance.
+func NewBatchAccounting(db *gorm.DB) *BatchAccounting {
+	return &BatchAccounting{db: db}
+}
+
+// TableName returns the table name for the BatchAccounting model.
+func (*BatchAccounting) TableName() string {
+	return "batch_accounting"
+}
+
+// GetLatestBatchAccounting retrieves the accounting of the batch with the highest index, nil if none is stored.
+func (o *BatchAccounting) GetLatestBatchAccounting(ctx context.Context) (*BatchAccounting, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchAccounting{})
+	db = db.Order("batch_index DESC")
+
+	var accounting BatchAccounting
+	if err := db.First(&accounting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("BatchAccounting.GetLatestBatchAccounting error: %w", err)
+	}
+	return &accounting, nil
+}
+
+// GetUnfinalizedBatchAccountings retrieves at most limit accountings of batches whose finalize fee is not recorded yet.
+// The returned accountings are sorted in ascending order by their batch index.
+func (o *BatchAccounting) GetUnfinalizedBatchAccountings(ctx context.Context, limit int) ([]*BatchAccounting, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchAccounting{})
+	db = db.Where("finalize_tx_hash = ''")
+	db = db.Order("batch_index ASC")
+	db = db.Limit(limit)
+
+	var accountings []*BatchAccounting
+	if err := db.Find(&accountings).Error; err != nil {
+		return nil, fmt.Errorf("BatchAccounting.GetUnfinalizedBatchAccountings error: %w", err)
+	}
+	return accountings, nil
+}
+
+// GetBatchAccountings retrieves the accountings of the last limit batches, sorted in descending order by batch index.
+func (o *BatchAccounting) GetBatchAccountings(ctx context.Context, limit int) ([]*BatchAccounting, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchAccounting{})
+	db = db.Order("batch_index DESC")
+	db = db.Limit(limit)
+
+	var accountings []*BatchAccounting
+	if err := db.Find(&accountings).Error; err != nil {
+		return nil, fmt.Errorf("BatchAccounting.GetBatchAccountings error: %w", err)
+	}
+	return accountings, nil
+}
+
+// InsertBatchAccounting inserts the accounting of a committed batch, an accounting already stored is kept.
+func (o *BatchAccounting) InsertBatchAccounting(ctx context.Context, accounting *BatchAccounting) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchAccounting{})
+	db = db.Clauses(clause.OnConflict{DoNothing: true})
+
+	if err := db.Create(accounting).Error; err != nil {
+		return fmt.Errorf("BatchAccounting.InsertBatchAccounting error: %w, batch index: %v, batch hash: %v", err, accounting.BatchIndex, accounting.BatchHash)
+	}
+	return nil
+}
+
+// UpdateFinalizeFeeAndMargin records the finalize fee of a finalized batch along with its final margin.
+func (o *BatchAccounting) UpdateFinalizeFeeAndMargin(ctx context.Context, batchHash string, finalizeTxHash string, finalizeFee string, margin string) error {
+	updateFields := make(map[string]interface{})
+	updateFields["finalize_tx_hash"] = finalizeTxHash
+	updateFields["finalize_fee"] = finalizeFee
+	updateFields["margin"] = margin
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchAccounting{})
+	db = db.Where("batch_hash = ?", batchHash)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("BatchAccounting.UpdateFinalizeFeeAndMargin error: %w, batch hash: %v, finalizeTxHash: %v", err, batchHash, finalizeTxHash)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The actions of the batch overrides.
+const (
+	// BatchOverrideActionRecommit resets the batch and the later uncommitted batches to be committed again.
+	BatchOverrideActionRecommit = "recommit"
+	// BatchOverrideActionInvalidateProof drops the proof of the batch to be proven again.
+	BatchOverrideActionInvalidateProof = "invalidate_proof"
+	// BatchOverrideActionSkipCommit marks the batch committed by a transaction sent outside of the relayer.
+	BatchOverrideActionSkipCommit = "skip_commit"
+	// BatchOverrideActionSkipFinalize marks the batch finalized by a transaction sent outside of the relayer.
+	BatchOverrideActionSkipFinalize = "skip_finalize"
+)
+
+// BatchOverride is the audit record of a manual override of the statuses of a batch.
+type BatchOverride struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID         uint64 `json:"id" gorm:"column:id;primaryKey"`
+	BatchIndex uint64 `json:"batch_index" gorm:"column:batch_index"`
+	BatchHash  string `json:"batch_hash" gorm:"column:batch_hash"`
+	Action     string `json:"action" gorm:"column:action"`
+	Reason     string `json:"reason" gorm:"column:reason"`
+	Operator   string `json:"operator" gorm:"column:operator"`
+	// PreviousRollupStatus and PreviousProvingStatus are the statuses of the batch before the override.
+	PreviousRollupStatus  int16 `json:"previous_rollup_status" gorm:"column:previous_rollup_status"`
+	PreviousProvingStatus int16 `json:"previous_proving_status" gorm:"column:previous_proving_status"`
+	// TxHash is the transaction which committed or finalized a skipped batch, empty for the other actions.
+	TxHash string `json:"tx_hash" gorm:"column:tx_hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewBatchOverride creates a new BatchOverride database instance.
+func NewBatchOverride(db *gorm.DB) *BatchOverride {
+	return &BatchOverride{db: db}
+}
+
+// TableName returns the table name for the BatchOverride model.
+func (*BatchOverride) TableName() string {
+	return "batch_override"
+}
+
+// GetBatchOverridesByIndex retrieves the overrides of the batch index, the earliest first.
+func (o *BatchOverride) GetBatchOverridesByIndex(ctx context.Context, batchIndex uint64) ([]*BatchOverride, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchOverride{})
+	db = db.Where("batch_index = ?", batchIndex)
+	db = db.Order("id ASC")
+
+	var overrides []*BatchOverride
+	if err := db.Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("BatchOverride.GetBatchOverridesByIndex error: %w, batch index: %v", err, batchIndex)
+	}
+	return overrides, nil
+}
+
+// InsertBatchOverride inserts an override.
+func (o *BatchOverride) InsertBatchOverride(ctx context.Context, override *BatchOverride, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&BatchOverride{})
+
+	if err := db.Create(override).Error; err != nil {
+		return fmt.Errorf("BatchOverride.InsertBatchOverride error: %w, batch index: %v, action: %v", err, override.BatchIndex, override.Action)
+	}
+	return nil
+}
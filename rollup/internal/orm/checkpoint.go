@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Checkpoint represents the progress of a service recorded on shutdown, which is verified on the next startup.
+type Checkpoint struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Service string `json:"service" gorm:"column:service"`
+
+	// progress
+	L1BlockNumber uint64 `json:"l1_block_number" gorm:"column:l1_block_number"`
+	L2BlockNumber uint64 `json:"l2_block_number" gorm:"column:l2_block_number"`
+	ChunkIndex    uint64 `json:"chunk_index" gorm:"column:chunk_index"`
+	ChunkHash     string `json:"chunk_hash" gorm:"column:chunk_hash"`
+	BatchIndex    uint64 `json:"batch_index" gorm:"column:batch_index"`
+	BatchHash     string `json:"batch_hash" gorm:"column:batch_hash"`
+
+	// CleanShutdown is false while the service runs, the checkpoint of a service which did not shut down gracefully
+	// is not trusted.
+	CleanShutdown bool `json:"clean_shutdown" gorm:"column:clean_shutdown"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewCheckpoint creates a new Checkpoint database instance.
+func NewCheckpoint(db *gorm.DB) *Checkpoint {
+	return &Checkpoint{db: db}
+}
+
+// TableName returns the table name for the Checkpoint model.
+func (*Checkpoint) TableName() string {
+	return "checkpoint"
+}
+
+// GetCheckpoint retrieves the checkpoint of a service, nil if the service never recorded one.
+func (o *Checkpoint) GetCheckpoint(ctx context.Context, service string) (*Checkpoint, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Checkpoint{})
+	db = db.Where("service = ?", service)
+
+	var checkpoint Checkpoint
+	if err := db.First(&checkpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Checkpoint.GetCheckpoint error: %w, service: %v", err, service)
+	}
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint inserts the checkpoint of its service or replaces the existing one.
+func (o *Checkpoint) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	updateFields := make(map[string]interface{})
+	updateFields["l1_block_number"] = checkpoint.L1BlockNumber
+	updateFields["l2_block_number"] = checkpoint.L2BlockNumber
+	updateFields["chunk_index"] = checkpoint.ChunkIndex
+	updateFields["chunk_hash"] = checkpoint.ChunkHash
+	updateFields["batch_index"] = checkpoint.BatchIndex
+	updateFields["batch_hash"] = checkpoint.BatchHash
+	updateFields["clean_shutdown"] = checkpoint.CleanShutdown
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Checkpoint{})
+	db = db.Where("service = ?", checkpoint.Service)
+
+	result := db.Updates(updateFields)
+	if result.Error != nil {
+		return fmt.Errorf("Checkpoint.SaveCheckpoint error: %w, service: %v", result.Error, checkpoint.Service)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	db = o.db.WithContext(ctx)
+	db = db.Model(&Checkpoint{})
+	if err := db.Create(checkpoint).Error; err != nil {
+		return fmt.Errorf("Checkpoint.SaveCheckpoint error: %w, service: %v", err, checkpoint.Service)
+	}
+	return nil
+}
+
+// UpdateCleanShutdown updates whether the checkpoint of a service was recorded by a graceful shutdown.
+func (o *Checkpoint) UpdateCleanShutdown(ctx context.Context, service string, cleanShutdown bool) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Checkpoint{})
+	db = db.Where("service = ?", service)
+
+	if err := db.Update("clean_shutdown", cleanShutdown).Error; err != nil {
+		return fmt.Errorf("Checkpoint.UpdateCleanShutdown error: %w, service: %v, clean shutdown: %v", err, service, cleanShutdown)
+	}
+	return nil
+}
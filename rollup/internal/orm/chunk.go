@@ -48,6 +48,7 @@ type Chunk struct {
 	TotalL2TxNum              uint64         `json:"total_l2_tx_num" gorm:"column:total_l2_tx_num"`
 	TotalL1CommitCalldataSize uint64         `json:"total_l1_commit_calldata_size" gorm:"column:total_l1_commit_calldata_size"`
 	TotalL1CommitGas          uint64         `json:"total_l1_commit_gas" gorm:"column:total_l1_commit_gas"`
+	Version                   int64          `json:"version" gorm:"column:version;default:0"`
 	CreatedAt                 time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt                 time.Time      `json:"updated_at" gorm:"column:updated_at"`
 	DeletedAt                 gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
@@ -226,6 +227,7 @@ func (o *Chunk) InsertChunk(ctx context.Context, chunk *encoding.Chunk, dbTX ...
 func (o *Chunk) UpdateProvingStatus(ctx context.Context, hash string, status types.ProvingStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["proving_status"] = int(status)
+	updateFields["version"] = gorm.Expr("version + 1")
 
 	switch status {
 	case types.ProvingTaskAssigned:
@@ -250,6 +252,40 @@ func (o *Chunk) UpdateProvingStatus(ctx context.Context, hash string, status typ
 	return nil
 }
 
+// CompareAndSwapProvingStatus updates the proving status of a chunk, only if the chunk is still at the given version.
+// Otherwise nothing is updated and a types.StatusConflictError is returned.
+func (o *Chunk) CompareAndSwapProvingStatus(ctx context.Context, hash string, version int64, status types.ProvingStatus, dbTX ...*gorm.DB) error {
+	updateFields := make(map[string]interface{})
+	updateFields["proving_status"] = int(status)
+	updateFields["version"] = gorm.Expr("version + 1")
+
+	switch status {
+	case types.ProvingTaskAssigned:
+		updateFields["prover_assigned_at"] = time.Now()
+	case types.ProvingTaskUnassigned:
+		updateFields["prover_assigned_at"] = nil
+	case types.ProvingTaskVerified:
+		updateFields["proved_at"] = time.Now()
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash = ? AND version = ?", hash, version)
+
+	result := db.Updates(updateFields)
+	if result.Error != nil {
+		return fmt.Errorf("Chunk.CompareAndSwapProvingStatus error: %w, chunk hash: %v, status: %v", result.Error, hash, status.String())
+	}
+	if result.RowsAffected == 0 {
+		return &types.StatusConflictError{Table: o.TableName(), Hash: hash, Version: version}
+	}
+	return nil
+}
+
 // UpdateBatchHashInRange updates the batch_hash for chunks within the specified range (inclusive).
 // The range is closed, i.e., it includes both start and end indices.
 func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, endIndex uint64, batchHash string, dbTX ...*gorm.DB) error {
@@ -261,7 +297,10 @@ func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, e
 	db = db.Model(&Chunk{})
 	db = db.Where("index >= ? AND index <= ?", startIndex, endIndex)
 
-	if err := db.Update("batch_hash", batchHash).Error; err != nil {
+	updateFields := make(map[string]interface{})
+	updateFields["batch_hash"] = batchHash
+	updateFields["version"] = gorm.Expr("version + 1")
+	if err := db.Updates(updateFields).Error; err != nil {
 		return fmt.Errorf("Chunk.UpdateBatchHashInRange error: %w, start index: %v, end index: %v, batch hash: %v", err, startIndex, endIndex, batchHash)
 	}
 	return nil
@@ -2,6 +2,7 @@ package orm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,9 +16,10 @@ type L1Block struct {
 	db *gorm.DB `gorm:"column:-"`
 
 	// block
-	Number  uint64 `json:"number" gorm:"column:number"`
-	Hash    string `json:"hash" gorm:"column:hash"`
-	BaseFee uint64 `json:"base_fee" gorm:"column:base_fee"`
+	Number      uint64 `json:"number" gorm:"column:number"`
+	Hash        string `json:"hash" gorm:"column:hash"`
+	BaseFee     uint64 `json:"base_fee" gorm:"column:base_fee"`
+	BlobBaseFee uint64 `json:"blob_base_fee" gorm:"column:blob_base_fee"`
 
 	// oracle
 	GasOracleStatus int16  `json:"oracle_status" gorm:"column:oracle_status;default:1"`
@@ -52,6 +54,22 @@ func (o *L1Block) GetLatestL1BlockHeight(ctx context.Context) (uint64, error) {
 	return maxNumber, nil
 }
 
+// GetLatestL1Block get the latest l1 block, nil if there is none
+func (o *L1Block) GetLatestL1Block(ctx context.Context) (*L1Block, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1Block{})
+	db = db.Order("number DESC")
+
+	var l1Block L1Block
+	if err := db.First(&l1Block).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L1Block.GetLatestL1Block error: %w", err)
+	}
+	return &l1Block, nil
+}
+
 // GetL1Blocks get the l1 blocks
 func (o *L1Block) GetL1Blocks(ctx context.Context, fields map[string]interface{}) ([]L1Block, error) {
 	db := o.db.WithContext(ctx)
@@ -3,6 +3,8 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/log"
@@ -31,6 +33,10 @@ type L1Message struct {
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
 }
 
+// l1MessageColumns are the columns moved from l1_message to l1_message_archive, the columns added to l1_message must be added here too.
+const l1MessageColumns = `queue_index, msg_hash, height, gas_limit, sender, target, value, calldata, layer1_hash, layer2_hash, status,
+	created_at, updated_at, deleted_at`
+
 // NewL1Message create an L1MessageOrm instance
 func NewL1Message(db *gorm.DB) *L1Message {
 	return &L1Message{db: db}
@@ -41,12 +47,14 @@ func (*L1Message) TableName() string {
 	return "l1_message"
 }
 
-// GetLayer1LatestWatchedHeight returns latest height stored in the table
+// GetLayer1LatestWatchedHeight returns latest height stored in the table or archived
 func (m *L1Message) GetLayer1LatestWatchedHeight() (int64, error) {
 	// @note It's not correct, since we may don't have message in some blocks.
 	// But it will only be called at start, some redundancy is acceptable.
 	var maxHeight sql.NullInt64
-	result := m.db.Model(&L1Message{}).Select("MAX(height)").Scan(&maxHeight)
+	result := m.db.Raw(`SELECT GREATEST(
+		(SELECT MAX(height) FROM l1_message WHERE deleted_at IS NULL),
+		(SELECT MAX(height) FROM l1_message_archive WHERE deleted_at IS NULL))`).Scan(&maxHeight)
 	if result.Error != nil {
 		return -1, result.Error
 	}
@@ -56,21 +64,154 @@ func (m *L1Message) GetLayer1LatestWatchedHeight() (int64, error) {
 	return -1, nil
 }
 
+// GetNextQueueIndex returns the queue index following the stored or archived layer1 messages emitted up to the given
+// height, 0 if there is none.
+func (m *L1Message) GetNextQueueIndex(ctx context.Context, height uint64) (uint64, error) {
+	var maxQueueIndex sql.NullInt64
+	err := m.db.WithContext(ctx).Raw(`SELECT GREATEST(
+		(SELECT MAX(queue_index) FROM l1_message WHERE height <= ? AND deleted_at IS NULL),
+		(SELECT MAX(queue_index) FROM l1_message_archive WHERE height <= ? AND deleted_at IS NULL))`, height, height).Scan(&maxQueueIndex).Error
+	if err != nil {
+		return 0, fmt.Errorf("L1Message.GetNextQueueIndex error: %w, height: %v", err, height)
+	}
+	if !maxQueueIndex.Valid {
+		return 0, nil
+	}
+	return uint64(maxQueueIndex.Int64) + 1, nil
+}
+
+// GetL1MessagesInQueueRange retrieves the stored or archived layer1 messages with a queue index in [start, end),
+// emitted up to the given height. The returned messages are sorted in ascending order by their queue index.
+func (m *L1Message) GetL1MessagesInQueueRange(ctx context.Context, start, end, height uint64) ([]*L1Message, error) {
+	var messages []*L1Message
+	err := m.db.WithContext(ctx).Raw(`SELECT * FROM l1_message WHERE queue_index >= ? AND queue_index < ? AND height <= ? AND deleted_at IS NULL
+		UNION ALL SELECT * FROM l1_message_archive WHERE queue_index >= ? AND queue_index < ? AND height <= ? AND deleted_at IS NULL
+		ORDER BY queue_index ASC`, start, end, height, start, end, height).Scan(&messages).Error
+	if err != nil {
+		return nil, fmt.Errorf("L1Message.GetL1MessagesInQueueRange error: %w, start: %v, end: %v, height: %v", err, start, end, height)
+	}
+	return messages, nil
+}
+
 // SaveL1Messages batch save a list of layer1 messages
 func (m *L1Message) SaveL1Messages(ctx context.Context, messages []*L1Message) error {
 	if len(messages) == 0 {
 		return nil
 	}
 
-	err := m.db.WithContext(ctx).Create(&messages).Error
+	queueIndices := make([]uint64, 0, len(messages))
+	for _, msg := range messages {
+		queueIndices = append(queueIndices, msg.QueueIndex)
+	}
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the queue indexes are unique across the partitions and the archive through l1_message_queue_index.
+		values, args := queueIndexValues(queueIndices)
+		if err := tx.Exec(`INSERT INTO l1_message_queue_index (queue_index) VALUES `+values, args...).Error; err != nil {
+			return err
+		}
+		return tx.Create(&messages).Error
+	})
 	if err != nil {
-		queueIndices := make([]uint64, 0, len(messages))
 		heights := make([]uint64, 0, len(messages))
 		for _, msg := range messages {
-			queueIndices = append(queueIndices, msg.QueueIndex)
 			heights = append(heights, msg.Height)
 		}
 		log.Error("failed to insert l1Messages", "queueIndices", queueIndices, "heights", heights, "err", err)
 	}
 	return err
 }
+
+// InsertMissingL1Messages inserts the layer1 messages, messages already stored or archived are ignored.
+func (m *L1Message) InsertMissingL1Messages(ctx context.Context, messages []*L1Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	queueIndices := make([]uint64, 0, len(messages))
+	for _, msg := range messages {
+		queueIndices = append(queueIndices, msg.QueueIndex)
+	}
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the queue indexes of the stored or archived messages are taken already, the others are claimed atomically.
+		var claimedQueueIndices []uint64
+		values, args := queueIndexValues(queueIndices)
+		err := tx.Raw(`INSERT INTO l1_message_queue_index (queue_index) VALUES `+values+`
+			ON CONFLICT DO NOTHING RETURNING queue_index`, args...).Scan(&claimedQueueIndices).Error
+		if err != nil {
+			return err
+		}
+		claimed := make(map[uint64]struct{}, len(claimedQueueIndices))
+		for _, queueIndex := range claimedQueueIndices {
+			claimed[queueIndex] = struct{}{}
+		}
+		missing := make([]*L1Message, 0, len(claimed))
+		for _, msg := range messages {
+			if _, ok := claimed[msg.QueueIndex]; ok {
+				missing = append(missing, msg)
+				// a message given twice is inserted once.
+				delete(claimed, msg.QueueIndex)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		return tx.Model(&L1Message{}).Create(&missing).Error
+	})
+	if err != nil {
+		return fmt.Errorf("L1Message.InsertMissingL1Messages error: %w, first queue index: %v", err, messages[0].QueueIndex)
+	}
+	return nil
+}
+
+// queueIndexValues returns the VALUES rows inserting the queue indexes into l1_message_queue_index, and their arguments.
+func queueIndexValues(queueIndices []uint64) (string, []interface{}) {
+	rows := make([]string, 0, len(queueIndices))
+	args := make([]interface{}, 0, len(queueIndices))
+	for _, queueIndex := range queueIndices {
+		rows = append(rows, "(?)")
+		args = append(args, queueIndex)
+	}
+	return strings.Join(rows, ", "), args
+}
+
+// DeleteL1MessagesAfterHeight soft deletes the layer1 messages emitted in blocks higher than the given height,
+// their queue indexes are released so that the messages emitted again after a reorg can be stored.
+func (m *L1Message) DeleteL1MessagesAfterHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	deleteMessages := func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM l1_message_queue_index WHERE queue_index IN (
+			SELECT queue_index FROM l1_message WHERE height > ? AND deleted_at IS NULL)`, height).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&L1Message{}).Where("height > ?", height).Delete(&L1Message{}).Error
+	}
+
+	var err error
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		err = deleteMessages(dbTX[0].WithContext(ctx))
+	} else {
+		err = m.db.WithContext(ctx).Transaction(deleteMessages)
+	}
+	if err != nil {
+		return fmt.Errorf("L1Message.DeleteL1MessagesAfterHeight error: %w, height: %v", err, height)
+	}
+	return nil
+}
+
+// ArchiveL1MessagesCreatedBefore moves up to limit layer1 messages created before the given time to l1_message_archive, only the
+// messages below the given queue index, i.e. the ones already finalized on layer2, and the deleted messages are archived.
+// The messages have no primary key, they are identified by their partition and row location. It returns the number of archived messages.
+func (m *L1Message) ArchiveL1MessagesCreatedBefore(ctx context.Context, before time.Time, queueIndex uint64, limit int) (int64, error) {
+	db := m.db.WithContext(ctx)
+	result := db.Exec(`WITH archived AS (
+		DELETE FROM l1_message WHERE (tableoid, ctid) IN (
+			SELECT tableoid, ctid FROM l1_message
+			WHERE created_at < ? AND (queue_index < ? OR deleted_at IS NOT NULL)
+			ORDER BY created_at LIMIT ?
+		) RETURNING `+l1MessageColumns+`
+	) INSERT INTO l1_message_archive (`+l1MessageColumns+`) SELECT `+l1MessageColumns+` FROM archived`, before, queueIndex, limit)
+	if result.Error != nil {
+		return 0, fmt.Errorf("L1Message.ArchiveL1MessagesCreatedBefore error: %w, before: %v, queue index: %v", result.Error, before, queueIndex)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,67 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// L1MessageAudit is the result of an audit of the stored layer1 messages against the L1 message queue contract, only
+// the audits which changed the audit state are stored.
+type L1MessageAudit struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID               uint64 `json:"id" gorm:"column:id;primaryKey"`
+	L1BlockNumber    uint64 `json:"l1_block_number" gorm:"column:l1_block_number"`
+	LocalNextIndex   uint64 `json:"local_next_index" gorm:"column:local_next_index"`
+	OnchainNextIndex uint64 `json:"onchain_next_index" gorm:"column:onchain_next_index"`
+	// Diverged halts the message relaying until an audit finds the stored messages consistent again.
+	Diverged            bool   `json:"diverged" gorm:"column:diverged"`
+	DivergentQueueIndex uint64 `json:"divergent_queue_index" gorm:"column:divergent_queue_index"`
+	Reason              string `json:"reason" gorm:"column:reason"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL1MessageAudit creates a new L1MessageAudit database instance.
+func NewL1MessageAudit(db *gorm.DB) *L1MessageAudit {
+	return &L1MessageAudit{db: db}
+}
+
+// TableName returns the table name for the L1MessageAudit model.
+func (*L1MessageAudit) TableName() string {
+	return "l1_message_audit"
+}
+
+// GetLatestL1MessageAudit retrieves the latest stored audit, nil if the stored messages were never audited.
+func (o *L1MessageAudit) GetLatestL1MessageAudit(ctx context.Context) (*L1MessageAudit, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1MessageAudit{})
+	db = db.Order("id DESC")
+
+	var audit L1MessageAudit
+	if err := db.First(&audit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L1MessageAudit.GetLatestL1MessageAudit error: %w", err)
+	}
+	return &audit, nil
+}
+
+// InsertL1MessageAudit inserts an audit.
+func (o *L1MessageAudit) InsertL1MessageAudit(ctx context.Context, audit *L1MessageAudit) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1MessageAudit{})
+
+	if err := db.Create(audit).Error; err != nil {
+		return fmt.Errorf("L1MessageAudit.InsertL1MessageAudit error: %w, l1 block number: %v, diverged: %v", err, audit.L1BlockNumber, audit.Diverged)
+	}
+	return nil
+}
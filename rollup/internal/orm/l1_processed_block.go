@@ -0,0 +1,130 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// L1ProcessedBlock is the hash of the last block of an L1 range whose contract events were processed by the L1 watcher.
+type L1ProcessedBlock struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Number uint64 `json:"number" gorm:"column:number"`
+	Hash   string `json:"hash" gorm:"column:hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL1ProcessedBlock creates a new L1ProcessedBlock database instance.
+func NewL1ProcessedBlock(db *gorm.DB) *L1ProcessedBlock {
+	return &L1ProcessedBlock{db: db}
+}
+
+// TableName returns the table name for the L1ProcessedBlock model.
+func (*L1ProcessedBlock) TableName() string {
+	return "l1_processed_block"
+}
+
+// GetL1ProcessedBlock retrieves the processed block with the given number, nil if it does not exist.
+func (o *L1ProcessedBlock) GetL1ProcessedBlock(ctx context.Context, number uint64) (*L1ProcessedBlock, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1ProcessedBlock{})
+	db = db.Where("number = ?", number)
+
+	var block L1ProcessedBlock
+	if err := db.First(&block).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L1ProcessedBlock.GetL1ProcessedBlock error: %w, number: %v", err, number)
+	}
+	return &block, nil
+}
+
+// GetLatestL1ProcessedBlockHeight returns the number of the latest processed block, 0 if there is none.
+func (o *L1ProcessedBlock) GetLatestL1ProcessedBlockHeight(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1ProcessedBlock{})
+	db = db.Select("COALESCE(MAX(number), 0)")
+
+	var maxNumber uint64
+	if err := db.Row().Scan(&maxNumber); err != nil {
+		return 0, fmt.Errorf("L1ProcessedBlock.GetLatestL1ProcessedBlockHeight error: %w", err)
+	}
+	return maxNumber, nil
+}
+
+// GetL1ProcessedBlocksBefore retrieves at most limit processed blocks with a number lower than the given one.
+// The returned blocks are sorted in descending order by their number.
+func (o *L1ProcessedBlock) GetL1ProcessedBlocksBefore(ctx context.Context, number uint64, limit int) ([]*L1ProcessedBlock, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1ProcessedBlock{})
+	db = db.Where("number < ?", number)
+	db = db.Order("number DESC")
+	db = db.Limit(limit)
+
+	var blocks []*L1ProcessedBlock
+	if err := db.Find(&blocks).Error; err != nil {
+		return nil, fmt.Errorf("L1ProcessedBlock.GetL1ProcessedBlocksBefore error: %w, number: %v", err, number)
+	}
+	return blocks, nil
+}
+
+// InsertL1ProcessedBlock inserts a processed block, the stored blocks with a number not lower than it are soft deleted
+// as they are processed again.
+func (o *L1ProcessedBlock) InsertL1ProcessedBlock(ctx context.Context, block *L1ProcessedBlock) error {
+	return o.db.Transaction(func(tx *gorm.DB) error {
+		db := tx.WithContext(ctx)
+		db = db.Model(&L1ProcessedBlock{})
+		db = db.Where("number >= ?", block.Number)
+		if err := db.Delete(&L1ProcessedBlock{}).Error; err != nil {
+			return fmt.Errorf("L1ProcessedBlock.InsertL1ProcessedBlock error: soft deleting blocks failed, block numbers starting from: %v, error: %w", block.Number, err)
+		}
+
+		db = tx.WithContext(ctx)
+		db = db.Model(&L1ProcessedBlock{})
+		if err := db.Create(block).Error; err != nil {
+			return fmt.Errorf("L1ProcessedBlock.InsertL1ProcessedBlock error: %w, number: %v, hash: %v", err, block.Number, block.Hash)
+		}
+		return nil
+	})
+}
+
+// DeleteL1ProcessedBlocksAfter soft deletes the processed blocks with a number higher than the given one.
+func (o *L1ProcessedBlock) DeleteL1ProcessedBlocksAfter(ctx context.Context, number uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1ProcessedBlock{})
+	db = db.Where("number > ?", number)
+
+	if err := db.Delete(&L1ProcessedBlock{}).Error; err != nil {
+		return fmt.Errorf("L1ProcessedBlock.DeleteL1ProcessedBlocksAfter error: %w, number: %v", err, number)
+	}
+	return nil
+}
+
+// DeleteL1ProcessedBlocksBefore soft deletes the processed blocks with a number lower than the given one.
+func (o *L1ProcessedBlock) DeleteL1ProcessedBlocksBefore(ctx context.Context, number uint64) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1ProcessedBlock{})
+	db = db.Where("number < ?", number)
+
+	if err := db.Delete(&L1ProcessedBlock{}).Error; err != nil {
+		return fmt.Errorf("L1ProcessedBlock.DeleteL1ProcessedBlocksBefore error: %w, number: %v", err, number)
+	}
+	return nil
+}
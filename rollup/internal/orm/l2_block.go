@@ -10,6 +10,7 @@ import (
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"scroll-tech/common/types/encoding"
 )
@@ -184,25 +185,64 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 }
 
 // InsertL2Blocks inserts l2 blocks into the "l2_block" table.
-func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block) error {
+func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block, dbTX ...*gorm.DB) error {
+	l2Blocks, err := newL2Blocks(blocks)
+	if err != nil {
+		return err
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+
+	if err := db.Create(&l2Blocks).Error; err != nil {
+		return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
+	}
+	return nil
+}
+
+// InsertMissingL2Blocks inserts l2 blocks into the "l2_block" table, blocks already stored are ignored.
+func (o *L2Block) InsertMissingL2Blocks(ctx context.Context, blocks []*encoding.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	l2Blocks, err := newL2Blocks(blocks)
+	if err != nil {
+		return err
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Clauses(clause.OnConflict{DoNothing: true})
+
+	if err := db.Create(&l2Blocks).Error; err != nil {
+		return fmt.Errorf("L2Block.InsertMissingL2Blocks error: %w, first block number: %v", err, l2Blocks[0].Number)
+	}
+	return nil
+}
+
+func newL2Blocks(blocks []*encoding.Block) ([]L2Block, error) {
 	var l2Blocks []L2Block
 	for _, block := range blocks {
 		header, err := json.Marshal(block.Header)
 		if err != nil {
 			log.Error("failed to marshal block header", "hash", block.Header.Hash().String(), "err", err)
-			return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
+			return nil, fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
 		}
 
 		txs, err := json.Marshal(block.Transactions)
 		if err != nil {
 			log.Error("failed to marshal transactions", "hash", block.Header.Hash().String(), "err", err)
-			return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
+			return nil, fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
 		}
 
 		rc, err := json.Marshal(block.RowConsumption)
 		if err != nil {
 			log.Error("failed to marshal RowConsumption", "hash", block.Header.Hash().String(), "err", err)
-			return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
+			return nil, fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
 		}
 
 		l2Block := L2Block{
@@ -220,14 +260,7 @@ func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block)
 		}
 		l2Blocks = append(l2Blocks, l2Block)
 	}
-
-	db := o.db.WithContext(ctx)
-	db = db.Model(&L2Block{})
-
-	if err := db.Create(&l2Blocks).Error; err != nil {
-		return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
-	}
-	return nil
+	return l2Blocks, nil
 }
 
 // UpdateChunkHashInRange updates the chunk_hash of block tx within the specified range (inclusive).
@@ -0,0 +1,94 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// L2WatcherCheckpoint is the last L2 block stored by the L2 watcher, it is saved in the same transaction as the blocks.
+type L2WatcherCheckpoint struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	BlockHash   string `json:"block_hash" gorm:"column:block_hash"`
+	// WithdrawTrieNextIndex and WithdrawTrieFrontier are the withdraw trie at the block, the frontier is nil when the
+	// withdraw roots are not computed.
+	WithdrawTrieNextIndex uint64 `json:"withdraw_trie_next_index" gorm:"column:withdraw_trie_next_index"`
+	WithdrawTrieFrontier  []byte `json:"withdraw_trie_frontier" gorm:"column:withdraw_trie_frontier"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL2WatcherCheckpoint creates a new L2WatcherCheckpoint database instance.
+func NewL2WatcherCheckpoint(db *gorm.DB) *L2WatcherCheckpoint {
+	return &L2WatcherCheckpoint{db: db}
+}
+
+// TableName returns the table name for the L2WatcherCheckpoint model.
+func (*L2WatcherCheckpoint) TableName() string {
+	return "l2_watcher_checkpoint"
+}
+
+// GetL2WatcherCheckpoint retrieves the checkpoint of the L2 watcher, nil if none is saved.
+func (o *L2WatcherCheckpoint) GetL2WatcherCheckpoint(ctx context.Context) (*L2WatcherCheckpoint, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2WatcherCheckpoint{})
+	db = db.Order("block_number DESC")
+
+	var checkpoint L2WatcherCheckpoint
+	if err := db.First(&checkpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L2WatcherCheckpoint.GetL2WatcherCheckpoint error: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// SaveL2WatcherCheckpoint saves the checkpoint of the L2 watcher, the previous checkpoints are soft deleted.
+func (o *L2WatcherCheckpoint) SaveL2WatcherCheckpoint(ctx context.Context, checkpoint *L2WatcherCheckpoint, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2WatcherCheckpoint{})
+	db = db.Where("block_number < ?", checkpoint.BlockNumber)
+	if err := db.Delete(&L2WatcherCheckpoint{}).Error; err != nil {
+		return fmt.Errorf("L2WatcherCheckpoint.SaveL2WatcherCheckpoint error: %w, block number: %v", err, checkpoint.BlockNumber)
+	}
+
+	db = o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2WatcherCheckpoint{})
+	if err := db.Create(checkpoint).Error; err != nil {
+		return fmt.Errorf("L2WatcherCheckpoint.SaveL2WatcherCheckpoint error: %w, block number: %v, block hash: %v", err, checkpoint.BlockNumber, checkpoint.BlockHash)
+	}
+	return nil
+}
+
+// UpdateWithdrawTrie updates the withdraw trie of the checkpoint of the block number.
+func (o *L2WatcherCheckpoint) UpdateWithdrawTrie(ctx context.Context, blockNumber, nextIndex uint64, frontier []byte) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2WatcherCheckpoint{})
+	db = db.Where("block_number = ?", blockNumber)
+
+	updateFields := map[string]interface{}{
+		"withdraw_trie_next_index": nextIndex,
+		"withdraw_trie_frontier":   frontier,
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("L2WatcherCheckpoint.UpdateWithdrawTrie error: %w, block number: %v", err, blockNumber)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// NonceReservation represents a nonce of a sender account reserved for a context in the database.
+type NonceReservation struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID            uint   `json:"id" gorm:"id;primaryKey"`
+	ContextID     string `json:"context_id" gorm:"context_id"`
+	SenderAddress string `json:"sender_address" gorm:"sender_address"`
+	Nonce         uint64 `json:"nonce" gorm:"nonce"`
+	// Hash is the hash of the transaction sent with the nonce, empty while the reservation is unused.
+	Hash      string         `json:"hash" gorm:"hash"`
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// TableName returns the table name for the NonceReservation model.
+func (*NonceReservation) TableName() string {
+	return "nonce_reservation"
+}
+
+// NewNonceReservation returns a new instance of NonceReservation.
+func NewNonceReservation(db *gorm.DB) *NonceReservation {
+	return &NonceReservation{db: db}
+}
+
+// GetNonceReservationsByContextID retrieves the nonces reserved for a context by a sender address, ordered by nonce.
+func (o *NonceReservation) GetNonceReservationsByContextID(ctx context.Context, senderAddress common.Address, contextID string) ([]NonceReservation, error) {
+	var reservations []NonceReservation
+	db := o.db.WithContext(ctx)
+	db = db.Model(&NonceReservation{})
+	db = db.Where("sender_address = ?", senderAddress.String())
+	db = db.Where("context_id = ?", contextID)
+	db = db.Order("nonce asc")
+	if err := db.Find(&reservations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get nonce reservations by context ID, sender address: %s, context ID: %s, err: %w", senderAddress, contextID, err)
+	}
+	return reservations, nil
+}
+
+// GetNonceReservation retrieves the reservation of a nonce of a sender address, nil if the nonce is not reserved.
+func (o *NonceReservation) GetNonceReservation(ctx context.Context, senderAddress common.Address, nonce uint64) (*NonceReservation, error) {
+	var reservation NonceReservation
+	db := o.db.WithContext(ctx)
+	db = db.Model(&NonceReservation{})
+	db = db.Where("sender_address = ?", senderAddress.String())
+	db = db.Where("nonce = ?", nonce)
+	if err := db.First(&reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get nonce reservation, sender address: %s, nonce: %d, err: %w", senderAddress, nonce, err)
+	}
+	return &reservation, nil
+}
+
+// GetNextReservedNonce returns the nonce following the highest reserved nonce of a sender address, 0 if none is reserved.
+func (o *NonceReservation) GetNextReservedNonce(ctx context.Context, senderAddress common.Address) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&NonceReservation{})
+	db = db.Where("sender_address = ?", senderAddress.String())
+	db = db.Select("COALESCE(MAX(nonce) + 1, 0)")
+
+	var nextNonce uint64
+	if err := db.Row().Scan(&nextNonce); err != nil {
+		return 0, fmt.Errorf("failed to get next reserved nonce, sender address: %s, err: %w", senderAddress, err)
+	}
+	return nextNonce, nil
+}
+
+// InsertNonceReservations reserves the given nonces of a sender address for a context.
+func (o *NonceReservation) InsertNonceReservations(ctx context.Context, contextID string, senderAddress common.Address, nonces []uint64, dbTX ...*gorm.DB) error {
+	if len(nonces) == 0 {
+		return nil
+	}
+
+	reservations := make([]NonceReservation, len(nonces))
+	for i, nonce := range nonces {
+		reservations[i] = NonceReservation{
+			ContextID:     contextID,
+			SenderAddress: senderAddress.String(),
+			Nonce:         nonce,
+		}
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&NonceReservation{})
+	if err := db.Create(&reservations).Error; err != nil {
+		return fmt.Errorf("failed to InsertNonceReservations, context ID: %s, sender address: %s, error: %w", contextID, senderAddress, err)
+	}
+	return nil
+}
+
+// UpdateNonceReservationAsUsed records the hash of the transaction sent with a reserved nonce.
+// It returns whether an unused reservation of the nonce was found.
+func (o *NonceReservation) UpdateNonceReservationAsUsed(ctx context.Context, senderAddress common.Address, nonce uint64, hash common.Hash, dbTX ...*gorm.DB) (bool, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&NonceReservation{})
+	db = db.Where("sender_address = ?", senderAddress.String())
+	db = db.Where("nonce = ?", nonce)
+	db = db.Where("hash = ?", "")
+
+	result := db.Update("hash", hash.String())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to UpdateNonceReservationAsUsed, sender address: %s, nonce: %d, txHash: %s, error: %w", senderAddress, nonce, hash, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
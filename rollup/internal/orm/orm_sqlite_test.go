@@ -0,0 +1,267 @@
+//go:build sqlite
+
+package orm
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+)
+
+// the tests of the ORMs without a postgres container, run with `go test -tags sqlite`.
+//
+// The sqlite tables are created from the gorm models, the postgres migrations do not apply to sqlite. Only the tables
+// below are supported, TestSQLiteSchemaDrift checks that their columns match the migrations. The other tables, the
+// partitions and archive tables of pending_transaction and l1_message, the partial unique indexes, the functions and
+// the defaults of the migrations are not created, their queries must be tested against postgres in orm_test.go.
+
+// sqliteModels are the models of the tables created in sqlite.
+var sqliteModels = []interface{}{&PendingTransaction{}, &Chunk{}, &Batch{}}
+
+// sqliteTables are the tables created in sqlite without a gorm model, by the statements creating them.
+var sqliteTables = map[string]string{
+	"pending_transaction_hash": "CREATE TABLE pending_transaction_hash (hash VARCHAR NOT NULL PRIMARY KEY, created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)",
+}
+
+// sqliteMissingColumns are the columns of the migrations left out of the rollup models, as only the coordinator uses them.
+var sqliteMissingColumns = map[string]map[string]bool{
+	"chunk": {"active_attempts": true, "total_attempts": true},
+	"batch": {"active_attempts": true, "total_attempts": true},
+}
+
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := database.InitSQLiteDB(sqliteModels...)
+	assert.NoError(t, err)
+	for _, statement := range sqliteTables {
+		assert.NoError(t, db.Exec(statement).Error)
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, database.CloseDB(db))
+	})
+	return db
+}
+
+func TestSQLitePendingTransactionOrm(t *testing.T) {
+	pendingTransactionOrm := NewPendingTransaction(setupSQLiteDB(t))
+
+	tx0 := gethTypes.NewTx(&gethTypes.DynamicFeeTx{
+		Nonce:      0,
+		To:         &common.Address{},
+		Data:       []byte{},
+		Gas:        21000,
+		AccessList: gethTypes.AccessList{},
+		Value:      big.NewInt(0),
+		ChainID:    big.NewInt(1),
+		GasTipCap:  big.NewInt(0),
+		GasFeeCap:  big.NewInt(1),
+		V:          big.NewInt(0),
+		R:          big.NewInt(0),
+		S:          big.NewInt(0),
+	})
+	senderMeta := &SenderMeta{
+		Name:    "testName",
+		Service: "testService",
+		Address: common.HexToAddress("0x1"),
+		Type:    types.SenderTypeCommitBatch,
+	}
+
+	err := pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx0, 0, nil, nil, 0, 0, 0, 0, nil)
+	assert.NoError(t, err)
+
+	txs, err := pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), senderMeta.Type, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, tx0.Hash().String(), txs[0].Hash)
+
+	err = pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(context.Background(), tx0.Hash(), types.TxStatusConfirmed)
+	assert.NoError(t, err)
+	status, err := pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), tx0.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusConfirmed, status)
+
+	txs, err = pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), senderMeta.Type, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 0)
+}
+
+func TestSQLiteCompareAndSwapOrm(t *testing.T) {
+	db := setupSQLiteDB(t)
+	chunkOrm := NewChunk(db)
+
+	chunk := &Chunk{Index: 0, Hash: "0x01", ProvingStatus: int16(types.ProvingTaskUnassigned)}
+	assert.NoError(t, db.Create(chunk).Error)
+
+	assert.NoError(t, chunkOrm.CompareAndSwapProvingStatus(context.Background(), chunk.Hash, 0, types.ProvingTaskAssigned))
+	err := chunkOrm.CompareAndSwapProvingStatus(context.Background(), chunk.Hash, 0, types.ProvingTaskVerified)
+	assert.ErrorIs(t, err, types.ErrStatusConflict)
+	assert.NoError(t, chunkOrm.CompareAndSwapProvingStatus(context.Background(), chunk.Hash, 1, types.ProvingTaskVerified))
+}
+
+func TestSQLiteSchemaDrift(t *testing.T) {
+	db := setupSQLiteDB(t)
+	migrated := migratedColumns(t, filepath.Join("..", "..", "..", "database", "migrate", "migrations"))
+
+	var tables []string
+	for _, model := range sqliteModels {
+		stmt := &gorm.Statement{DB: db}
+		assert.NoError(t, stmt.Parse(model))
+		tables = append(tables, stmt.Schema.Table)
+	}
+	for table := range sqliteTables {
+		tables = append(tables, table)
+	}
+
+	for _, table := range tables {
+		columnTypes, err := db.Migrator().ColumnTypes(table)
+		assert.NoError(t, err)
+		var columns []string
+		for _, columnType := range columnTypes {
+			columns = append(columns, columnType.Name())
+		}
+		sort.Strings(columns)
+		assert.NotEmpty(t, migrated[table], "table %s is not created by the migrations", table)
+
+		var expected []string
+		for _, column := range migrated[table] {
+			if !sqliteMissingColumns[table][column] {
+				expected = append(expected, column)
+			}
+		}
+		assert.Equal(t, expected, columns, "the sqlite columns of %s differ from the migrations", table)
+	}
+}
+
+var (
+	sqlDollarQuoted    = regexp.MustCompile(`(?s)\$\$.*?\$\$`)
+	sqlLineComment     = regexp.MustCompile(`--[^\n]*`)
+	sqlStringLiteral   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlCreateTable     = regexp.MustCompile(`^create table (?:if not exists )?(\w+) ?\(`)
+	sqlAlterTable      = regexp.MustCompile(`^alter table (?:if exists )?(?:only )?(\w+) (.*)$`)
+	sqlDropTable       = regexp.MustCompile(`^drop table (?:if exists )?(.*)$`)
+	sqlRenameTable     = regexp.MustCompile(`^rename to (\w+)$`)
+	sqlAddColumn       = regexp.MustCompile(`^add column (?:if not exists )?(\w+)`)
+	sqlDropColumn      = regexp.MustCompile(`^drop column (?:if exists )?(\w+)`)
+	sqlRenameColumn    = regexp.MustCompile(`^rename column (\w+) to (\w+)$`)
+	sqlTableConstraint = map[string]bool{"primary": true, "unique": true, "constraint": true, "foreign": true, "check": true, "exclude": true}
+)
+
+// migratedColumns replays the create table, alter table and drop table statements of the up migrations of dir, in
+// order, and returns the sorted columns of the resulting tables.
+func migratedColumns(t *testing.T, dir string) map[string][]string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	assert.NoError(t, err)
+	sort.Strings(files)
+
+	tables := make(map[string]map[string]bool)
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Clean(file))
+		assert.NoError(t, err)
+		up := string(content)
+		if i := strings.Index(up, "+goose Down"); i >= 0 {
+			up = up[:i]
+		}
+		up = sqlDollarQuoted.ReplaceAllString(up, "")
+		up = sqlLineComment.ReplaceAllString(up, "")
+		up = sqlStringLiteral.ReplaceAllString(up, "''")
+
+		for _, statement := range strings.Split(up, ";") {
+			statement = strings.ToLower(strings.Join(strings.Fields(statement), " "))
+			if m := sqlCreateTable.FindStringSubmatch(statement); m != nil {
+				columns := make(map[string]bool)
+				for _, item := range splitTopLevel(enclosed(statement[len(m[0])-1:])) {
+					words := strings.Fields(item)
+					switch {
+					case len(words) == 0 || sqlTableConstraint[words[0]]:
+					case words[0] == "like" && len(words) > 1:
+						for column := range tables[words[1]] {
+							columns[column] = true
+						}
+					default:
+						columns[strings.Trim(words[0], `"`)] = true
+					}
+				}
+				tables[m[1]] = columns
+			} else if m := sqlAlterTable.FindStringSubmatch(statement); m != nil {
+				if r := sqlRenameTable.FindStringSubmatch(m[2]); r != nil {
+					tables[r[1]] = tables[m[1]]
+					delete(tables, m[1])
+					continue
+				}
+				for _, clause := range splitTopLevel(m[2]) {
+					clause = strings.TrimSpace(clause)
+					if c := sqlAddColumn.FindStringSubmatch(clause); c != nil && tables[m[1]] != nil {
+						tables[m[1]][c[1]] = true
+					} else if c := sqlDropColumn.FindStringSubmatch(clause); c != nil {
+						delete(tables[m[1]], c[1])
+					} else if c := sqlRenameColumn.FindStringSubmatch(clause); c != nil && tables[m[1]] != nil {
+						delete(tables[m[1]], c[1])
+						tables[m[1]][c[2]] = true
+					}
+				}
+			} else if m := sqlDropTable.FindStringSubmatch(statement); m != nil {
+				for _, table := range strings.Split(m[1], ",") {
+					delete(tables, strings.Fields(table)[0])
+				}
+			}
+		}
+	}
+
+	result := make(map[string][]string, len(tables))
+	for table, columns := range tables {
+		for column := range columns {
+			result[table] = append(result[table], column)
+		}
+		sort.Strings(result[table])
+	}
+	return result
+}
+
+// enclosed returns the content of the parenthesis opening s.
+func enclosed(s string) string {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i]
+			}
+		}
+	}
+	return s
+}
+
+// splitTopLevel splits s on the commas out of parenthesis.
+func splitTopLevel(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, s[start:])
+}
@@ -1,11 +1,17 @@
+//go:build !sqlite
+
 package orm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -28,6 +34,9 @@ var (
 	chunkOrm              *Chunk
 	batchOrm              *Batch
 	pendingTransactionOrm *PendingTransaction
+	nonceReservationOrm   *NonceReservation
+	skippedL1MessageOrm   *SkippedL1Message
+	checkpointOrm         *Checkpoint
 
 	block1     *encoding.Block
 	block2     *encoding.Block
@@ -65,6 +74,9 @@ func setupEnv(t *testing.T) {
 	chunkOrm = NewChunk(db)
 	l2BlockOrm = NewL2Block(db)
 	pendingTransactionOrm = NewPendingTransaction(db)
+	nonceReservationOrm = NewNonceReservation(db)
+	skippedL1MessageOrm = NewSkippedL1Message(db)
+	checkpointOrm = NewCheckpoint(db)
 
 	templateBlockTrace, err := os.ReadFile("../../../common/testdata/blockTrace_02.json")
 	assert.NoError(t, err)
@@ -105,10 +117,14 @@ func TestL1BlockOrm(t *testing.T) {
 
 	l1BlockOrm := NewL1Block(db)
 
+	latestBlock, err := l1BlockOrm.GetLatestL1Block(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, latestBlock)
+
 	// mock blocks
 	block1 := L1Block{Number: 1, Hash: "hash1"}
 	block2 := L1Block{Number: 2, Hash: "hash2"}
-	block3 := L1Block{Number: 3, Hash: "hash3"}
+	block3 := L1Block{Number: 3, Hash: "hash3", BaseFee: 100, BlobBaseFee: 10}
 	block2AfterReorg := L1Block{Number: 2, Hash: "hash2-reorg"}
 
 	err = l1BlockOrm.InsertL1Blocks(context.Background(), []L1Block{block1, block2, block3})
@@ -118,6 +134,13 @@ func TestL1BlockOrm(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(3), height)
 
+	latestBlock, err = l1BlockOrm.GetLatestL1Block(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, latestBlock)
+	assert.Equal(t, "hash3", latestBlock.Hash)
+	assert.Equal(t, uint64(100), latestBlock.BaseFee)
+	assert.Equal(t, uint64(10), latestBlock.BlobBaseFee)
+
 	blocks, err := l1BlockOrm.GetL1Blocks(context.Background(), map[string]interface{}{})
 	assert.NoError(t, err)
 	assert.Len(t, blocks, 3)
@@ -239,7 +262,7 @@ func TestBatchOrm(t *testing.T) {
 		EndChunkIndex:              0,
 		EndChunkHash:               chunkHash1,
 	}
-	batch1, err := batchOrm.InsertBatch(context.Background(), batch)
+	batch1, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 	hash1 := batch1.Hash
 
@@ -260,7 +283,7 @@ func TestBatchOrm(t *testing.T) {
 		EndChunkIndex:              1,
 		EndChunkHash:               chunkHash2,
 	}
-	batch2, err := batchOrm.InsertBatch(context.Background(), batch)
+	batch2, err := batchOrm.InsertBatch(context.Background(), batch, codecv0.CodecV0Version)
 	assert.NoError(t, err)
 	hash2 := batch2.Hash
 
@@ -281,6 +304,9 @@ func TestBatchOrm(t *testing.T) {
 	pendingBatches, err := batchOrm.GetFailedAndPendingBatches(context.Background(), 100)
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(pendingBatches))
+	pendingCount, err := batchOrm.GetFailedAndPendingBatchCount(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), pendingCount)
 
 	rollupStatus, err := batchOrm.GetRollupStatusByHashList(context.Background(), []string{batchHash1, batchHash2})
 	assert.NoError(t, err)
@@ -317,6 +343,13 @@ func TestBatchOrm(t *testing.T) {
 	assert.NotNil(t, updatedBatch)
 	assert.Equal(t, "commitTxHash", updatedBatch.CommitTxHash)
 	assert.Equal(t, types.RollupCommitted, types.RollupStatus(updatedBatch.RollupStatus))
+	assert.Equal(t, types.DAVerificationPending, types.DAVerificationStatus(updatedBatch.DAVerificationStatus))
+
+	err = batchOrm.UpdateDAVerificationStatus(context.Background(), batchHash2, types.DAVerificationMismatch)
+	assert.NoError(t, err)
+	updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, types.DAVerificationMismatch, types.DAVerificationStatus(updatedBatch.DAVerificationStatus))
 
 	err = batchOrm.UpdateFinalizeTxHashAndRollupStatus(context.Background(), batchHash2, "finalizeTxHash", types.RollupFinalizeFailed)
 	assert.NoError(t, err)
@@ -328,6 +361,63 @@ func TestBatchOrm(t *testing.T) {
 	assert.Equal(t, types.RollupFinalizeFailed, types.RollupStatus(updatedBatch.RollupStatus))
 }
 
+func TestCompareAndSwapOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	dbChunk, err := chunkOrm.InsertChunk(context.Background(), chunk1)
+	assert.NoError(t, err)
+	assert.NoError(t, chunkOrm.CompareAndSwapProvingStatus(context.Background(), dbChunk.Hash, 0, types.ProvingTaskAssigned))
+	err = chunkOrm.CompareAndSwapProvingStatus(context.Background(), dbChunk.Hash, 0, types.ProvingTaskVerified)
+	assert.ErrorIs(t, err, types.ErrStatusConflict)
+
+	batch1, err := batchOrm.InsertBatch(context.Background(), &encoding.Batch{
+		Index:           1,
+		Chunks:          []*encoding.Chunk{chunk1},
+		StartChunkIndex: 0,
+		StartChunkHash:  chunkHash1,
+		EndChunkIndex:   0,
+		EndChunkHash:    chunkHash1,
+	}, codecv0.CodecV0Version)
+	assert.NoError(t, err)
+	batch2, err := batchOrm.InsertBatch(context.Background(), &encoding.Batch{
+		Index:           2,
+		ParentBatchHash: common.HexToHash(batch1.Hash),
+		Chunks:          []*encoding.Chunk{chunk2},
+		StartChunkIndex: 1,
+		StartChunkHash:  chunkHash2,
+		EndChunkIndex:   1,
+		EndChunkHash:    chunkHash2,
+	}, codecv0.CodecV0Version)
+	assert.NoError(t, err)
+
+	// a status transition since the batch was read fails its commit.
+	staleBatch2 := *batch2
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), batch2.Hash, types.RollupCommitFailed))
+	err = batchOrm.CompareAndSwapCommitTxHashAndRollupStatus(context.Background(), &staleBatch2, "commitTxHash", types.RollupCommitting)
+	var conflictErr *types.StatusConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, batch2.Hash, conflictErr.Hash)
+	assert.Equal(t, int64(0), staleBatch2.Version)
+
+	dbBatch2, err := batchOrm.GetBatchByIndex(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, types.RollupCommitFailed, types.RollupStatus(dbBatch2.RollupStatus))
+	assert.Equal(t, int64(1), dbBatch2.Version)
+
+	assert.NoError(t, batchOrm.CompareAndSwapCommitTxHashAndRollupStatus(context.Background(), batch1, "commitTxHash", types.RollupCommitting))
+	assert.Equal(t, int64(1), batch1.Version)
+	assert.NoError(t, batchOrm.CompareAndSwapCommitTxHashAndRollupStatus(context.Background(), dbBatch2, "commitTxHash", types.RollupCommitting))
+	assert.Equal(t, int64(2), dbBatch2.Version)
+
+	// a reverted batch can not be finalized.
+	assert.NoError(t, batchOrm.DeleteBatchesGEIndex(context.Background(), 2))
+	err = batchOrm.CompareAndSwapFinalizeTxHashAndRollupStatus(context.Background(), batch2.Hash, dbBatch2.Version, "finalizeTxHash", types.RollupFinalizing)
+	assert.ErrorIs(t, err, types.ErrStatusConflict)
+	assert.NoError(t, batchOrm.CompareAndSwapFinalizeTxHashAndRollupStatus(context.Background(), batch1.Hash, batch1.Version, "finalizeTxHash", types.RollupFinalizing))
+}
+
 func TestTransactionOrm(t *testing.T) {
 	sqlDB, err := db.DB()
 	assert.NoError(t, err)
@@ -339,7 +429,7 @@ func TestTransactionOrm(t *testing.T) {
 		Data:       []byte{},
 		Gas:        21000,
 		AccessList: gethTypes.AccessList{},
-		Value:      big.NewInt(0),
+		Value:      big.NewInt(100),
 		ChainID:    big.NewInt(1),
 		GasTipCap:  big.NewInt(0),
 		GasFeeCap:  big.NewInt(1),
@@ -353,7 +443,7 @@ func TestTransactionOrm(t *testing.T) {
 		Data:       []byte{},
 		Gas:        42000,
 		AccessList: gethTypes.AccessList{},
-		Value:      big.NewInt(0),
+		Value:      big.NewInt(150),
 		ChainID:    big.NewInt(1),
 		GasTipCap:  big.NewInt(1),
 		GasFeeCap:  big.NewInt(2),
@@ -368,10 +458,10 @@ func TestTransactionOrm(t *testing.T) {
 		Type:    types.SenderTypeCommitBatch,
 	}
 
-	err = pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx0, 0)
+	err = pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx0, 0, nil, nil, 0, 0, 0, 0, nil)
 	assert.NoError(t, err)
 
-	err = pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx1, 0)
+	err = pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx1, 0, nil, nil, 3, 5, 12, 10, map[string]string{"batch_index": "5", "batch_hash": "0x05"})
 	assert.NoError(t, err)
 
 	err = pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(context.Background(), tx0.Hash(), types.TxStatusReplaced)
@@ -390,10 +480,89 @@ func TestTransactionOrm(t *testing.T) {
 	assert.Equal(t, senderMeta.Service, txs[1].SenderService)
 	assert.Equal(t, senderMeta.Address.String(), txs[1].SenderAddress)
 	assert.Equal(t, senderMeta.Type, txs[1].SenderType)
+	assert.Equal(t, uint64(3), txs[1].Replacements)
+	assert.Equal(t, uint64(5), txs[1].TargetInclusionBlocks)
+	assert.Equal(t, uint64(12), txs[1].GasLimitMultipleNum)
+	assert.Equal(t, uint64(10), txs[1].GasLimitMultipleDen)
+	assert.Equal(t, map[string]string{"batch_index": "5", "batch_hash": "0x05"}, txs[1].Metadata)
+	assert.Equal(t, map[string]string{}, txs[0].Metadata)
+	assert.Equal(t, "150", txs[1].Value)
+
+	// the transactions are matched by a subset of their labels.
+	txs, err = pendingTransactionOrm.GetTransactionsBySenderTypeAndLabels(context.Background(), senderMeta.Type, map[string]string{"batch_index": "5"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, tx1.Hash().String(), txs[0].Hash)
+	txs, err = pendingTransactionOrm.GetTransactionsBySenderTypeAndLabels(context.Background(), types.SenderTypeFinalizeBatch, map[string]string{"batch_index": "5"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 0)
+	txs, err = pendingTransactionOrm.GetTransactionsByLabels(context.Background(), map[string]string{"batch_index": "5", "batch_hash": "0x06"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 0)
+	_, err = pendingTransactionOrm.GetTransactionsByLabels(context.Background(), nil, 10)
+	assert.Error(t, err)
+
+	// each nonce is counted once, with the largest value of its transactions.
+	value, err := pendingTransactionOrm.GetInFlightValueBySenderType(context.Background(), senderMeta.Type)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(150), value)
+
+	txs, err = pendingTransactionOrm.GetPendingTransactionsBySenderType(context.Background(), senderMeta.Type, 0, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, tx1.Hash().String(), txs[0].Hash)
+
+	txs, err = pendingTransactionOrm.GetTransactionsBySenderTypeAndContextID(context.Background(), senderMeta.Type, "test")
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, tx1.Hash().String(), txs[0].Hash)
+	assert.Equal(t, tx0.Hash().String(), txs[1].Hash)
+
+	txs, err = pendingTransactionOrm.GetPendingTransactionsBySenderType(context.Background(), senderMeta.Type, 1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 0)
+
+	err = pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(context.Background(), tx1.Hash(), types.TxStatusStuck)
+	assert.NoError(t, err)
+	count, err := pendingTransactionOrm.GetPendingTransactionCountBySenderType(context.Background(), senderMeta.Type)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	resumed, err := pendingTransactionOrm.UpdateStuckTransactionAsPendingByContextID(context.Background(), "test")
+	assert.NoError(t, err)
+	assert.True(t, resumed)
+	resumed, err = pendingTransactionOrm.UpdateStuckTransactionAsPendingByContextID(context.Background(), "test")
+	assert.NoError(t, err)
+	assert.False(t, resumed)
+
+	txs, err = pendingTransactionOrm.GetPendingTransactionsBySenderType(context.Background(), senderMeta.Type, 0, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, uint64(0), txs[0].Replacements)
 
 	err = pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(context.Background(), tx1.Hash(), types.TxStatusConfirmed)
 	assert.NoError(t, err)
 
+	err = pendingTransactionOrm.UpdateInclusionBlockByTxHash(context.Background(), tx1.Hash(), 10, common.HexToHash("0x10"))
+	assert.NoError(t, err)
+
+	txs, err = pendingTransactionOrm.GetConfirmedTransactionsBySenderTypeAfterBlock(context.Background(), senderMeta.Type, 9, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, uint64(10), txs[0].InclusionBlockNumber)
+	assert.Equal(t, common.HexToHash("0x10").String(), txs[0].InclusionBlockHash)
+
+	err = pendingTransactionOrm.UpdateFailureTraceByTxHash(context.Background(), tx1.Hash(), `{"depth":1}`)
+	assert.NoError(t, err)
+	txs, err = pendingTransactionOrm.GetConfirmedTransactionsBySenderTypeAfterBlock(context.Background(), senderMeta.Type, 9, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, `{"depth":1}`, txs[0].FailureTrace)
+
+	txs, err = pendingTransactionOrm.GetConfirmedTransactionsBySenderTypeAfterBlock(context.Background(), senderMeta.Type, 10, 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 0)
+
 	txs, err = pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), senderMeta.Type, 2)
 	assert.NoError(t, err)
 	assert.Len(t, txs, 1)
@@ -405,7 +574,359 @@ func TestTransactionOrm(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, txs, 0)
 
+	value, err = pendingTransactionOrm.GetInFlightValueBySenderType(context.Background(), senderMeta.Type)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(0), value)
+
 	status, err := pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), tx0.Hash())
 	assert.NoError(t, err)
 	assert.Equal(t, types.TxStatusConfirmedFailed, status)
 }
+
+func TestNonceReservationOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	address := common.HexToAddress("0x1")
+	nextNonce, err := nonceReservationOrm.GetNextReservedNonce(context.Background(), address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), nextNonce)
+
+	assert.NoError(t, nonceReservationOrm.InsertNonceReservations(context.Background(), "commit", address, []uint64{3, 4}))
+	assert.NoError(t, nonceReservationOrm.InsertNonceReservations(context.Background(), "finalize", address, []uint64{5}))
+	// a nonce can only be reserved once.
+	assert.Error(t, nonceReservationOrm.InsertNonceReservations(context.Background(), "other", address, []uint64{5}))
+
+	nextNonce, err = nonceReservationOrm.GetNextReservedNonce(context.Background(), address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), nextNonce)
+
+	reservations, err := nonceReservationOrm.GetNonceReservationsByContextID(context.Background(), address, "commit")
+	assert.NoError(t, err)
+	assert.Len(t, reservations, 2)
+	assert.Equal(t, uint64(3), reservations[0].Nonce)
+	assert.Equal(t, uint64(4), reservations[1].Nonce)
+
+	reservation, err := nonceReservationOrm.GetNonceReservation(context.Background(), address, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, reservation)
+
+	hash := common.HexToHash("0x2")
+	used, err := nonceReservationOrm.UpdateNonceReservationAsUsed(context.Background(), address, 5, hash)
+	assert.NoError(t, err)
+	assert.True(t, used)
+	used, err = nonceReservationOrm.UpdateNonceReservationAsUsed(context.Background(), address, 5, hash)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	reservation, err = nonceReservationOrm.GetNonceReservation(context.Background(), address, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "finalize", reservation.ContextID)
+	assert.Equal(t, hash.String(), reservation.Hash)
+}
+
+func TestSkippedL1MessageOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	nextSkipIndex, err := skippedL1MessageOrm.GetNextSkipIndex(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), nextSkipIndex)
+
+	messages := []*SkippedL1Message{
+		{QueueIndex: 10, SkipIndex: 0, TxHash: common.HexToHash("0x10").Hex(), Value: "0", GasLimit: 100000, SkipReason: "row consumption overflow", Status: int16(types.SkippedL1MessageStatusSkipped)},
+		{QueueIndex: 12, SkipIndex: 2, TxHash: common.HexToHash("0x12").Hex(), Value: "0", GasLimit: 100000, SkipReason: "row consumption overflow", Status: int16(types.SkippedL1MessageStatusSkipped)},
+	}
+	assert.NoError(t, skippedL1MessageOrm.InsertSkippedL1Messages(context.Background(), messages))
+	// messages already stored are ignored.
+	assert.NoError(t, skippedL1MessageOrm.InsertSkippedL1Messages(context.Background(), messages[:1]))
+
+	nextSkipIndex, err = skippedL1MessageOrm.GetNextSkipIndex(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), nextSkipIndex)
+
+	err = skippedL1MessageOrm.UpdateReplayTxHashAndStatus(context.Background(), 12, common.HexToHash("0x1212").Hex(), 500000, types.SkippedL1MessageStatusReplaying)
+	assert.NoError(t, err)
+
+	all, err := skippedL1MessageOrm.GetSkippedL1Messages(context.Background(), types.SkippedL1MessageStatusUndefined, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, uint64(10), all[0].QueueIndex)
+	assert.Equal(t, uint64(12), all[1].QueueIndex)
+
+	skipped, err := skippedL1MessageOrm.GetSkippedL1Messages(context.Background(), types.SkippedL1MessageStatusSkipped, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, uint64(10), skipped[0].QueueIndex)
+
+	message, err := skippedL1MessageOrm.GetSkippedL1MessageByQueueIndex(context.Background(), 12)
+	assert.NoError(t, err)
+	assert.Equal(t, types.SkippedL1MessageStatusReplaying, types.SkippedL1MessageStatus(message.Status))
+	assert.Equal(t, common.HexToHash("0x1212").Hex(), message.ReplayTxHash)
+	assert.Equal(t, uint64(500000), message.ReplayGasLimit)
+
+	message, err = skippedL1MessageOrm.GetSkippedL1MessageByQueueIndex(context.Background(), 11)
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+}
+
+func TestCheckpointOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	checkpoint, err := checkpointOrm.GetCheckpoint(context.Background(), "rollup_relayer")
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	err = checkpointOrm.SaveCheckpoint(context.Background(), &Checkpoint{Service: "rollup_relayer", L2BlockNumber: 10, ChunkIndex: 1, ChunkHash: "0x01", CleanShutdown: true})
+	assert.NoError(t, err)
+	err = checkpointOrm.SaveCheckpoint(context.Background(), &Checkpoint{Service: "event_watcher", L1BlockNumber: 100})
+	assert.NoError(t, err)
+
+	// an existing checkpoint is replaced.
+	err = checkpointOrm.SaveCheckpoint(context.Background(), &Checkpoint{Service: "rollup_relayer", L2BlockNumber: 20, ChunkIndex: 2, ChunkHash: "0x02", BatchIndex: 1, BatchHash: "0x11", CleanShutdown: true})
+	assert.NoError(t, err)
+
+	checkpoint, err = checkpointOrm.GetCheckpoint(context.Background(), "rollup_relayer")
+	assert.NoError(t, err)
+	assert.NotNil(t, checkpoint)
+	assert.Equal(t, uint64(20), checkpoint.L2BlockNumber)
+	assert.Equal(t, uint64(2), checkpoint.ChunkIndex)
+	assert.Equal(t, "0x02", checkpoint.ChunkHash)
+	assert.Equal(t, uint64(1), checkpoint.BatchIndex)
+	assert.Equal(t, "0x11", checkpoint.BatchHash)
+	assert.True(t, checkpoint.CleanShutdown)
+
+	assert.NoError(t, checkpointOrm.UpdateCleanShutdown(context.Background(), "rollup_relayer", false))
+	checkpoint, err = checkpointOrm.GetCheckpoint(context.Background(), "rollup_relayer")
+	assert.NoError(t, err)
+	assert.False(t, checkpoint.CleanShutdown)
+
+	checkpoint, err = checkpointOrm.GetCheckpoint(context.Background(), "event_watcher")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), checkpoint.L1BlockNumber)
+	assert.False(t, checkpoint.CleanShutdown)
+}
+
+func TestL1ProcessedBlockOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	l1ProcessedBlockOrm := NewL1ProcessedBlock(db)
+
+	block, err := l1ProcessedBlockOrm.GetL1ProcessedBlock(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+
+	for _, block := range []*L1ProcessedBlock{{Number: 10, Hash: "hash10"}, {Number: 20, Hash: "hash20"}, {Number: 30, Hash: "hash30"}} {
+		assert.NoError(t, l1ProcessedBlockOrm.InsertL1ProcessedBlock(context.Background(), block))
+	}
+
+	// processing block 20 again replaces the blocks from 20.
+	err = l1ProcessedBlockOrm.InsertL1ProcessedBlock(context.Background(), &L1ProcessedBlock{Number: 20, Hash: "hash20-reorg"})
+	assert.NoError(t, err)
+
+	blocks, err := l1ProcessedBlockOrm.GetL1ProcessedBlocksBefore(context.Background(), 100, 10)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, "hash20-reorg", blocks[0].Hash)
+	assert.Equal(t, "hash10", blocks[1].Hash)
+	height, err := l1ProcessedBlockOrm.GetLatestL1ProcessedBlockHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), height)
+
+	assert.NoError(t, l1ProcessedBlockOrm.DeleteL1ProcessedBlocksAfter(context.Background(), 10))
+	block, err = l1ProcessedBlockOrm.GetL1ProcessedBlock(context.Background(), 20)
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+
+	assert.NoError(t, l1ProcessedBlockOrm.DeleteL1ProcessedBlocksBefore(context.Background(), 20))
+	blocks, err = l1ProcessedBlockOrm.GetL1ProcessedBlocksBefore(context.Background(), 100, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+}
+
+func TestL2WatcherCheckpointOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	l2WatcherCheckpointOrm := NewL2WatcherCheckpoint(db)
+
+	checkpoint, err := l2WatcherCheckpointOrm.GetL2WatcherCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	assert.NoError(t, l2WatcherCheckpointOrm.SaveL2WatcherCheckpoint(context.Background(), &L2WatcherCheckpoint{BlockNumber: 10, BlockHash: "hash10"}))
+
+	// the checkpoint is saved in the transaction of the blocks, a rolled back transaction keeps the previous one.
+	err = db.Transaction(func(dbTX *gorm.DB) error {
+		if err := l2WatcherCheckpointOrm.SaveL2WatcherCheckpoint(context.Background(), &L2WatcherCheckpoint{BlockNumber: 20, BlockHash: "hash20"}, dbTX); err != nil {
+			return err
+		}
+		return errors.New("insert blocks failed")
+	})
+	assert.Error(t, err)
+
+	checkpoint, err = l2WatcherCheckpointOrm.GetL2WatcherCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, checkpoint)
+	assert.Equal(t, uint64(10), checkpoint.BlockNumber)
+	assert.Equal(t, "hash10", checkpoint.BlockHash)
+
+	assert.NoError(t, l2WatcherCheckpointOrm.SaveL2WatcherCheckpoint(context.Background(), &L2WatcherCheckpoint{BlockNumber: 20, BlockHash: "hash20"}))
+	checkpoint, err = l2WatcherCheckpointOrm.GetL2WatcherCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), checkpoint.BlockNumber)
+	assert.Equal(t, "hash20", checkpoint.BlockHash)
+	assert.Nil(t, checkpoint.WithdrawTrieFrontier)
+
+	frontier := bytes.Repeat([]byte{0x01}, 64)
+	assert.NoError(t, l2WatcherCheckpointOrm.UpdateWithdrawTrie(context.Background(), 20, 3, frontier))
+	checkpoint, err = l2WatcherCheckpointOrm.GetL2WatcherCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), checkpoint.WithdrawTrieNextIndex)
+	assert.Equal(t, frontier, checkpoint.WithdrawTrieFrontier)
+}
+
+func TestBatchAccountingOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	batchAccountingOrm := NewBatchAccounting(db)
+
+	latest, err := batchAccountingOrm.GetLatestBatchAccounting(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+
+	for i := uint64(1); i <= 2; i++ {
+		assert.NoError(t, batchAccountingOrm.InsertBatchAccounting(context.Background(), &BatchAccounting{
+			BatchIndex:    i,
+			BatchHash:     fmt.Sprintf("hash%d", i),
+			CommitTxHash:  "commitTxHash",
+			CommitFee:     "100",
+			CommitBlobFee: "10",
+			L2Fee:         "200",
+			Margin:        "90",
+		}))
+	}
+	// an accounting already stored is kept.
+	assert.NoError(t, batchAccountingOrm.InsertBatchAccounting(context.Background(), &BatchAccounting{BatchIndex: 2, BatchHash: "hash2", CommitTxHash: "commitTxHash", CommitFee: "0", CommitBlobFee: "0", L2Fee: "0", Margin: "0"}))
+
+	latest, err = batchAccountingOrm.GetLatestBatchAccounting(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), latest.BatchIndex)
+	assert.Equal(t, "200", latest.L2Fee)
+	assert.Equal(t, "0", latest.FinalizeFee)
+
+	assert.NoError(t, batchAccountingOrm.UpdateFinalizeFeeAndMargin(context.Background(), "hash1", "finalizeTxHash", "150", "-60"))
+
+	unfinalized, err := batchAccountingOrm.GetUnfinalizedBatchAccountings(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, unfinalized, 1)
+	assert.Equal(t, "hash2", unfinalized[0].BatchHash)
+
+	accountings, err := batchAccountingOrm.GetBatchAccountings(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, accountings, 2)
+	assert.Equal(t, uint64(2), accountings[0].BatchIndex)
+	assert.Equal(t, "finalizeTxHash", accountings[1].FinalizeTxHash)
+	assert.Equal(t, "150", accountings[1].FinalizeFee)
+	assert.Equal(t, "-60", accountings[1].Margin)
+}
+
+func TestArchiveOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	senderMeta := &SenderMeta{Name: "testName", Service: "testService", Address: common.HexToAddress("0x1"), Type: types.SenderTypeCommitBatch}
+	var hashes []common.Hash
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{Nonce: nonce, To: &common.Address{}, Gas: 21000, Value: big.NewInt(0), ChainID: big.NewInt(1), GasTipCap: big.NewInt(0), GasFeeCap: big.NewInt(1), V: big.NewInt(0), R: big.NewInt(0), S: big.NewInt(0)})
+		assert.NoError(t, pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx, 0, nil, nil, 0, 0, 0, 0, nil))
+		hashes = append(hashes, tx.Hash())
+	}
+	assert.NoError(t, pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(context.Background(), hashes[0], types.TxStatusConfirmed))
+
+	// only the confirmed transaction is archived.
+	archived, err := pendingTransactionOrm.ArchiveTransactionsCreatedBefore(context.Background(), time.Now().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), archived)
+	status, err := pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), hashes[0])
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusUnknown, status)
+	status, err = pendingTransactionOrm.GetTxStatusByTxHash(context.Background(), hashes[1])
+	assert.NoError(t, err)
+	assert.Equal(t, types.TxStatusPending, status)
+
+	// the hashes stay unique across the partitions and the archive.
+	tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{Nonce: 0, To: &common.Address{}, Gas: 21000, Value: big.NewInt(0), ChainID: big.NewInt(1), GasTipCap: big.NewInt(0), GasFeeCap: big.NewInt(1), V: big.NewInt(0), R: big.NewInt(0), S: big.NewInt(0)})
+	assert.Error(t, pendingTransactionOrm.InsertPendingTransaction(context.Background(), "test", senderMeta, tx, 0, nil, nil, 0, 0, 0, 0, nil))
+
+	l1MessageOrm := NewL1Message(db)
+	newL1Message := func(queueIndex uint64) *L1Message {
+		return &L1Message{QueueIndex: queueIndex, MsgHash: fmt.Sprintf("hash%d", queueIndex), Height: 10 + queueIndex, Calldata: "0x", Layer1Hash: "0x"}
+	}
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), []*L1Message{newL1Message(0), newL1Message(1)}))
+
+	// only the messages below the queue index are archived.
+	archived, err = l1MessageOrm.ArchiveL1MessagesCreatedBefore(context.Background(), time.Now().Add(time.Hour), 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), archived)
+
+	// the archived and stored messages are not inserted again.
+	assert.NoError(t, l1MessageOrm.InsertMissingL1Messages(context.Background(), []*L1Message{newL1Message(0), newL1Message(1), newL1Message(2)}))
+	var count int64
+	assert.NoError(t, db.Model(&L1Message{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	// the queue indexes stay unique across the partitions and the archive, until the messages are deleted.
+	assert.Error(t, l1MessageOrm.SaveL1Messages(context.Background(), []*L1Message{newL1Message(0)}))
+	assert.Error(t, l1MessageOrm.SaveL1Messages(context.Background(), []*L1Message{newL1Message(2)}))
+	assert.NoError(t, l1MessageOrm.DeleteL1MessagesAfterHeight(context.Background(), 11))
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), []*L1Message{newL1Message(2)}))
+
+	height, err := l1MessageOrm.GetLayer1LatestWatchedHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), height)
+
+	// the empty partitions of the past months are dropped.
+	partitionedTable := NewPartitionedTable(db, "l1_message")
+	assert.NoError(t, partitionedTable.CreateMonthlyPartitions(context.Background(), time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 15, 0, 0, 0, 0, time.UTC)))
+	dropped, err := partitionedTable.DropEmptyPartitionsBefore(context.Background(), time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"l1_message_p202001", "l1_message_p202002"}, dropped)
+}
+
+func TestBatchOverrideOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	batchOverrideOrm := NewBatchOverride(db)
+
+	for _, action := range []string{BatchOverrideActionRecommit, BatchOverrideActionSkipCommit} {
+		assert.NoError(t, batchOverrideOrm.InsertBatchOverride(context.Background(), &BatchOverride{
+			BatchIndex:           1,
+			BatchHash:            "hash1",
+			Action:               action,
+			Reason:               "incident",
+			Operator:             "operator",
+			PreviousRollupStatus: int16(types.RollupCommitting),
+		}))
+	}
+	assert.NoError(t, batchOverrideOrm.InsertBatchOverride(context.Background(), &BatchOverride{BatchIndex: 2, BatchHash: "hash2", Action: BatchOverrideActionInvalidateProof, Reason: "incident", Operator: "operator"}))
+
+	overrides, err := batchOverrideOrm.GetBatchOverridesByIndex(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 2)
+	assert.Equal(t, BatchOverrideActionRecommit, overrides[0].Action)
+	assert.Equal(t, BatchOverrideActionSkipCommit, overrides[1].Action)
+	assert.Equal(t, int16(types.RollupCommitting), overrides[1].PreviousRollupStatus)
+}
@@ -0,0 +1,76 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// partitionMonthLayout is the layout of the month suffix of the monthly partitions, see create_monthly_partition.
+const partitionMonthLayout = "200601"
+
+// PartitionedTable maintains the monthly partitions of a table partitioned by created_at. The queries of the table
+// itself span its partitions, the partitions only need to be created ahead of the rows and dropped once archived.
+type PartitionedTable struct {
+	db *gorm.DB
+
+	name string
+}
+
+// NewPartitionedTable creates a new PartitionedTable instance of the given table.
+func NewPartitionedTable(db *gorm.DB, name string) *PartitionedTable {
+	return &PartitionedTable{db: db, name: name}
+}
+
+// CreateMonthlyPartitions creates the missing partitions of the months from the month of from to the month of to.
+func (o *PartitionedTable) CreateMonthlyPartitions(ctx context.Context, from, to time.Time) error {
+	db := o.db.WithContext(ctx)
+	err := db.Exec(`SELECT create_monthly_partition(?, month_start::DATE)
+		FROM generate_series(date_trunc('month', ?::TIMESTAMP), date_trunc('month', ?::TIMESTAMP), INTERVAL '1 month') AS month_start`,
+		o.name, from.UTC(), to.UTC()).Error
+	if err != nil {
+		return fmt.Errorf("PartitionedTable.CreateMonthlyPartitions error: %w, table: %v, from: %v, to: %v", err, o.name, from, to)
+	}
+	return nil
+}
+
+// DropEmptyPartitionsBefore drops the empty monthly partitions of the months which ended before the given time, the
+// partitions still holding rows, e.g. the rows not archived yet, are kept. It returns the dropped partitions.
+func (o *PartitionedTable) DropEmptyPartitionsBefore(ctx context.Context, before time.Time) ([]string, error) {
+	db := o.db.WithContext(ctx)
+
+	var partitions []string
+	err := db.Raw(`SELECT child.relname FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = ? ORDER BY child.relname`, o.name).Scan(&partitions).Error
+	if err != nil {
+		return nil, fmt.Errorf("PartitionedTable.DropEmptyPartitionsBefore error: %w, table: %v", err, o.name)
+	}
+
+	var dropped []string
+	for _, partition := range partitions {
+		// the default partition has no month suffix.
+		month, err := time.Parse(partitionMonthLayout, strings.TrimPrefix(partition, o.name+"_p"))
+		if err != nil || month.AddDate(0, 1, 0).After(before) {
+			continue
+		}
+
+		var notEmpty bool
+		if err := db.Raw("SELECT EXISTS (SELECT 1 FROM ?)", clause.Table{Name: partition}).Scan(&notEmpty).Error; err != nil {
+			return dropped, fmt.Errorf("PartitionedTable.DropEmptyPartitionsBefore error: %w, partition: %v", err, partition)
+		}
+		if notEmpty {
+			continue
+		}
+		if err := db.Exec("DROP TABLE IF EXISTS ?", clause.Table{Name: partition}).Error; err != nil {
+			return dropped, fmt.Errorf("PartitionedTable.DropEmptyPartitionsBefore error: %w, partition: %v", err, partition)
+		}
+		dropped = append(dropped, partition)
+	}
+	return dropped, nil
+}
@@ -3,7 +3,10 @@ package orm
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
@@ -25,25 +28,39 @@ type SenderMeta struct {
 type PendingTransaction struct {
 	db *gorm.DB `gorm:"column:-"`
 
-	ID                uint             `json:"id" gorm:"id;primaryKey"`
-	ContextID         string           `json:"context_id" gorm:"context_id"`
-	Hash              string           `json:"hash" gorm:"hash"`
-	ChainID           uint64           `json:"chain_id" gorm:"chain_id"`
-	Type              uint8            `json:"type" gorm:"type"`
-	GasTipCap         uint64           `json:"gas_tip_cap" gorm:"gas_tip_cap"`
-	GasFeeCap         uint64           `json:"gas_fee_cap" gorm:"gas_fee_cap"`
-	GasLimit          uint64           `json:"gas_limit" gorm:"gas_limit"`
-	Nonce             uint64           `json:"nonce" gorm:"nonce"`
-	SubmitBlockNumber uint64           `json:"submit_block_number" gorm:"submit_block_number"`
-	Status            types.TxStatus   `json:"status" gorm:"status"`
-	RLPEncoding       []byte           `json:"rlp_encoding" gorm:"rlp_encoding"`
-	SenderName        string           `json:"sender_name" gorm:"sender_name"`
-	SenderService     string           `json:"sender_service" gorm:"sender_service"`
-	SenderAddress     string           `json:"sender_address" gorm:"sender_address"`
-	SenderType        types.SenderType `json:"sender_type" gorm:"sender_type"`
-	CreatedAt         time.Time        `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt         time.Time        `json:"updated_at" gorm:"column:updated_at"`
-	DeletedAt         gorm.DeletedAt   `json:"deleted_at" gorm:"column:deleted_at"`
+	ID                   uint             `json:"id" gorm:"id;primaryKey"`
+	ContextID            string           `json:"context_id" gorm:"context_id"`
+	Hash                 string           `json:"hash" gorm:"hash"`
+	ChainID              uint64           `json:"chain_id" gorm:"chain_id"`
+	Type                 uint8            `json:"type" gorm:"type"`
+	GasTipCap            uint64           `json:"gas_tip_cap" gorm:"gas_tip_cap"`
+	GasFeeCap            uint64           `json:"gas_fee_cap" gorm:"gas_fee_cap"`
+	GasLimit             uint64           `json:"gas_limit" gorm:"gas_limit"`
+	Value                string           `json:"value" gorm:"value"`
+	Nonce                uint64           `json:"nonce" gorm:"nonce"`
+	SubmitBlockNumber    uint64           `json:"submit_block_number" gorm:"submit_block_number"`
+	Status               types.TxStatus   `json:"status" gorm:"status"`
+	RLPEncoding          []byte           `json:"rlp_encoding" gorm:"rlp_encoding"`
+	SenderName           string           `json:"sender_name" gorm:"sender_name"`
+	SenderService        string           `json:"sender_service" gorm:"sender_service"`
+	SenderAddress        string           `json:"sender_address" gorm:"sender_address"`
+	SenderType           types.SenderType `json:"sender_type" gorm:"sender_type"`
+	InclusionBlockNumber uint64           `json:"inclusion_block_number" gorm:"inclusion_block_number"`
+	InclusionBlockHash   string           `json:"inclusion_block_hash" gorm:"inclusion_block_hash"`
+	Deadline             *time.Time       `json:"deadline" gorm:"deadline"`
+	Confirmations        *int64           `json:"confirmations" gorm:"confirmations"`
+	Replacements         uint64           `json:"replacements" gorm:"replacements"`
+	// TargetInclusionBlocks is the number of blocks the transaction should be included within, 0 if unset.
+	TargetInclusionBlocks uint64 `json:"target_inclusion_blocks" gorm:"target_inclusion_blocks"`
+	// GasLimitMultipleNum and GasLimitMultipleDen are the multiple applied to the estimated gas limit, 0 if not estimated.
+	GasLimitMultipleNum uint64 `json:"gas_limit_multiple_num" gorm:"gas_limit_multiple_num"`
+	GasLimitMultipleDen uint64 `json:"gas_limit_multiple_den" gorm:"gas_limit_multiple_den"`
+	// Metadata are the key/value labels attached by the caller of the sender, carried over to the replacements.
+	Metadata     map[string]string `json:"metadata" gorm:"column:metadata;serializer:json"`
+	FailureTrace string            `json:"failure_trace" gorm:"failure_trace"`
+	CreatedAt    time.Time         `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt    time.Time         `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt    gorm.DeletedAt    `json:"deleted_at" gorm:"column:deleted_at"`
 }
 
 // TableName returns the table name for the Transaction model.
@@ -51,6 +68,13 @@ func (*PendingTransaction) TableName() string {
 	return "pending_transaction"
 }
 
+// pendingTransactionColumns are the columns moved from pending_transaction to pending_transaction_archive,
+// the columns added to pending_transaction must be added here too.
+const pendingTransactionColumns = `id, context_id, hash, status, rlp_encoding, chain_id, type, gas_tip_cap, gas_fee_cap, gas_limit, nonce,
+	submit_block_number, sender_name, sender_service, sender_address, sender_type, created_at, updated_at, deleted_at,
+	inclusion_block_number, inclusion_block_hash, deadline, failure_trace, confirmations, replacements, target_inclusion_blocks,
+	value, gas_limit_multiple_num, gas_limit_multiple_den, metadata`
+
 // NewPendingTransaction returns a new instance of PendingTransaction.
 func NewPendingTransaction(db *gorm.DB) *PendingTransaction {
 	return &PendingTransaction{db: db}
@@ -69,13 +93,13 @@ func (o *PendingTransaction) GetTxStatusByTxHash(ctx context.Context, hash commo
 	return status, nil
 }
 
-// GetPendingOrReplacedTransactionsBySenderType retrieves pending or replaced transactions filtered by sender type, ordered by nonce, then gas_fee_cap (gas_price in legacy tx), and limited to a specified count.
+// GetPendingOrReplacedTransactionsBySenderType retrieves pending, replaced or stuck transactions filtered by sender type, ordered by nonce, then gas_fee_cap (gas_price in legacy tx), and limited to a specified count.
 func (o *PendingTransaction) GetPendingOrReplacedTransactionsBySenderType(ctx context.Context, senderType types.SenderType, limit int) ([]PendingTransaction, error) {
 	var transactions []PendingTransaction
 	db := o.db.WithContext(ctx)
 	db = db.Model(&PendingTransaction{})
 	db = db.Where("sender_type = ?", senderType)
-	db = db.Where("status = ? OR status = ?", types.TxStatusPending, types.TxStatusReplaced)
+	db = db.Where("status IN ?", []types.TxStatus{types.TxStatusPending, types.TxStatusReplaced, types.TxStatusStuck})
 	db = db.Order("nonce asc")
 	db = db.Order("gas_fee_cap asc")
 	db = db.Limit(limit)
@@ -85,8 +109,133 @@ func (o *PendingTransaction) GetPendingOrReplacedTransactionsBySenderType(ctx co
 	return transactions, nil
 }
 
+// GetPendingTransactionsBySenderType retrieves pending transactions with a nonce not lower than minNonce filtered by sender type, ordered by nonce.
+func (o *PendingTransaction) GetPendingTransactionsBySenderType(ctx context.Context, senderType types.SenderType, minNonce uint64, limit int) ([]PendingTransaction, error) {
+	var transactions []PendingTransaction
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("sender_type = ?", senderType)
+	db = db.Where("status = ?", types.TxStatusPending)
+	db = db.Where("nonce >= ?", minNonce)
+	db = db.Order("nonce asc")
+	db = db.Limit(limit)
+	if err := db.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending transactions by sender type, error: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetTransactionsBySenderTypeAndContextID retrieves all the transactions of a context filtered by sender type, latest first.
+func (o *PendingTransaction) GetTransactionsBySenderTypeAndContextID(ctx context.Context, senderType types.SenderType, contextID string) ([]PendingTransaction, error) {
+	var transactions []PendingTransaction
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("sender_type = ?", senderType)
+	db = db.Where("context_id = ?", contextID)
+	db = db.Order("id desc")
+	if err := db.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transactions by sender type and context ID, context ID: %s, error: %w", contextID, err)
+	}
+	return transactions, nil
+}
+
+// GetTransactionsBySenderTypeAndLabels retrieves the transactions filtered by sender type whose metadata contains all the given labels,
+// latest first, and limited to a specified count.
+func (o *PendingTransaction) GetTransactionsBySenderTypeAndLabels(ctx context.Context, senderType types.SenderType, labels map[string]string, limit int) ([]PendingTransaction, error) {
+	return o.getTransactionsByLabels(ctx, &senderType, labels, limit)
+}
+
+// GetTransactionsByLabels retrieves the transactions of any sender type whose metadata contains all the given labels, latest first,
+// and limited to a specified count.
+func (o *PendingTransaction) GetTransactionsByLabels(ctx context.Context, labels map[string]string, limit int) ([]PendingTransaction, error) {
+	return o.getTransactionsByLabels(ctx, nil, labels, limit)
+}
+
+func (o *PendingTransaction) getTransactionsByLabels(ctx context.Context, senderType *types.SenderType, labels map[string]string, limit int) ([]PendingTransaction, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("at least one label is required")
+	}
+	containedLabels, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels, error: %w", err)
+	}
+
+	var transactions []PendingTransaction
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	if senderType != nil {
+		db = db.Where("sender_type = ?", *senderType)
+	}
+	db = db.Where("metadata @> ?", string(containedLabels))
+	db = db.Order("id desc")
+	db = db.Limit(limit)
+	if err := db.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transactions by labels, labels: %v, error: %w", labels, err)
+	}
+	return transactions, nil
+}
+
+// GetPendingTransactionCountBySenderType retrieves the number of pending or stuck transactions filtered by sender type.
+func (o *PendingTransaction) GetPendingTransactionCountBySenderType(ctx context.Context, senderType types.SenderType) (uint64, error) {
+	var count int64
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("sender_type = ?", senderType)
+	db = db.Where("status IN ?", []types.TxStatus{types.TxStatusPending, types.TxStatusStuck})
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to get pending transaction count by sender type, error: %w", err)
+	}
+	return uint64(count), nil
+}
+
+// GetInFlightValueBySenderType retrieves the value in wei transferred by the pending, replaced or stuck transactions filtered by sender type,
+// counting each nonce once since only one of its transactions can be included.
+func (o *PendingTransaction) GetInFlightValueBySenderType(ctx context.Context, senderType types.SenderType) (*big.Int, error) {
+	inFlight := o.db.WithContext(ctx)
+	inFlight = inFlight.Model(&PendingTransaction{})
+	inFlight = inFlight.Select("MAX(value) AS value")
+	inFlight = inFlight.Where("sender_type = ?", senderType)
+	inFlight = inFlight.Where("status IN ?", []types.TxStatus{types.TxStatusPending, types.TxStatusReplaced, types.TxStatusStuck})
+	inFlight = inFlight.Group("nonce")
+
+	var value string
+	db := o.db.WithContext(ctx)
+	db = db.Table("(?) AS in_flight", inFlight)
+	db = db.Select("COALESCE(SUM(value), 0)::TEXT")
+	if err := db.Row().Scan(&value); err != nil {
+		return nil, fmt.Errorf("failed to get in-flight value by sender type, error: %w", err)
+	}
+	total, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse in-flight value: %s", value)
+	}
+	return total, nil
+}
+
+// GetConfirmedTransactionsBySenderTypeAfterBlock retrieves confirmed transactions filtered by sender type whose inclusion block number is greater than the given block number, ordered by nonce.
+func (o *PendingTransaction) GetConfirmedTransactionsBySenderTypeAfterBlock(ctx context.Context, senderType types.SenderType, blockNumber uint64, limit int) ([]PendingTransaction, error) {
+	var transactions []PendingTransaction
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("sender_type = ?", senderType)
+	db = db.Where("status = ?", types.TxStatusConfirmed)
+	db = db.Where("inclusion_block_number > ?", blockNumber)
+	db = db.Order("nonce asc")
+	db = db.Limit(limit)
+	if err := db.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get confirmed transactions by sender type after block, block number: %v, error: %w", blockNumber, err)
+	}
+	return transactions, nil
+}
+
 // InsertPendingTransaction creates a new pending transaction record and stores it in the database.
-func (o *PendingTransaction) InsertPendingTransaction(ctx context.Context, contextID string, senderMeta *SenderMeta, tx *gethTypes.Transaction, submitBlockNumber uint64, dbTX ...*gorm.DB) error {
+// The deadline is optional, nil means the transaction never expires.
+// The confirmations override is optional, nil means the transaction is confirmed at the sender's default depth.
+// The replacements is the number of times the context has been resubmitted, zero for a new transaction.
+// The targetInclusionBlocks is the inclusion window of the transaction, zero if unset.
+// The gasLimitMultipleNum and gasLimitMultipleDen are the multiple applied to the estimated gas limit, zero if not estimated.
+// The metadata is optional, nil means the transaction has no labels.
+func (o *PendingTransaction) InsertPendingTransaction(ctx context.Context, contextID string, senderMeta *SenderMeta, tx *gethTypes.Transaction, submitBlockNumber uint64, deadline *time.Time, confirmations *int64, replacements, targetInclusionBlocks, gasLimitMultipleNum, gasLimitMultipleDen uint64, metadata map[string]string, dbTX ...*gorm.DB) error {
 	rlp := new(bytes.Buffer)
 	if err := tx.EncodeRLP(rlp); err != nil {
 		return fmt.Errorf("failed to encode rlp, err: %w", err)
@@ -100,6 +249,7 @@ func (o *PendingTransaction) InsertPendingTransaction(ctx context.Context, conte
 		GasFeeCap:         tx.GasFeeCap().Uint64(),
 		GasTipCap:         tx.GasTipCap().Uint64(),
 		GasLimit:          tx.Gas(),
+		Value:             tx.Value().String(),
 		Nonce:             tx.Nonce(),
 		SubmitBlockNumber: submitBlockNumber,
 		Status:            types.TxStatusPending,
@@ -108,15 +258,35 @@ func (o *PendingTransaction) InsertPendingTransaction(ctx context.Context, conte
 		SenderAddress:     senderMeta.Address.String(),
 		SenderService:     senderMeta.Service,
 		SenderType:        senderMeta.Type,
+		Deadline:          deadline,
+		Confirmations:     confirmations,
+		Replacements:      replacements,
+
+		TargetInclusionBlocks: targetInclusionBlocks,
+		GasLimitMultipleNum:   gasLimitMultipleNum,
+		GasLimitMultipleDen:   gasLimitMultipleDen,
+		Metadata:              metadata,
+	}
+	if newTransaction.Metadata == nil {
+		// stored as an empty object rather than a json null, so that the labels can always be matched.
+		newTransaction.Metadata = map[string]string{}
 	}
 
-	db := o.db
+	insertTransaction := func(tx *gorm.DB) error {
+		// the hashes are unique across the partitions and the archive through pending_transaction_hash.
+		if err := tx.Exec("INSERT INTO pending_transaction_hash (hash) VALUES (?)", newTransaction.Hash).Error; err != nil {
+			return err
+		}
+		return tx.Model(&PendingTransaction{}).Create(newTransaction).Error
+	}
+
+	var err error
 	if len(dbTX) > 0 && dbTX[0] != nil {
-		db = dbTX[0]
+		err = insertTransaction(dbTX[0].WithContext(ctx))
+	} else {
+		err = o.db.WithContext(ctx).Transaction(insertTransaction)
 	}
-	db = db.WithContext(ctx)
-	db = db.Model(&PendingTransaction{})
-	if err := db.Create(newTransaction).Error; err != nil {
+	if err != nil {
 		return fmt.Errorf("failed to InsertTransaction, error: %w", err)
 	}
 	return nil
@@ -153,3 +323,84 @@ func (o *PendingTransaction) UpdateOtherTransactionsAsFailedByNonce(ctx context.
 	}
 	return nil
 }
+
+// UpdateInclusionBlockByTxHash updates the number and hash of the block a transaction was included in, based on the transaction hash.
+// An empty block hash and zero block number clear the inclusion info, e.g. after the inclusion block was reorged out.
+func (o *PendingTransaction) UpdateInclusionBlockByTxHash(ctx context.Context, hash common.Hash, blockNumber uint64, blockHash common.Hash, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("hash = ?", hash.String())
+
+	updateFields := map[string]interface{}{
+		"inclusion_block_number": blockNumber,
+		"inclusion_block_hash":   "",
+	}
+	if blockHash != (common.Hash{}) {
+		updateFields["inclusion_block_hash"] = blockHash.String()
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("failed to UpdateInclusionBlockByTxHash, txHash: %s, block number: %v, block hash: %s, error: %w", hash, blockNumber, blockHash, err)
+	}
+	return nil
+}
+
+// UpdateFailureTraceByTxHash stores the trace summary of a reverted transaction based on the transaction hash.
+func (o *PendingTransaction) UpdateFailureTraceByTxHash(ctx context.Context, hash common.Hash, trace string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("hash = ?", hash.String())
+	if err := db.Update("failure_trace", trace).Error; err != nil {
+		return fmt.Errorf("failed to UpdateFailureTraceByTxHash, txHash: %s, error: %w", hash, err)
+	}
+	return nil
+}
+
+// UpdateStuckTransactionAsPendingByContextID moves the stuck transaction of a context back to pending with a fresh replacement budget.
+// It returns whether a stuck transaction was found.
+func (o *PendingTransaction) UpdateStuckTransactionAsPendingByContextID(ctx context.Context, contextID string, dbTX ...*gorm.DB) (bool, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("context_id = ?", contextID)
+	db = db.Where("status = ?", types.TxStatusStuck)
+
+	updateFields := map[string]interface{}{
+		"status":       types.TxStatusPending,
+		"replacements": 0,
+	}
+	result := db.Updates(updateFields)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to UpdateStuckTransactionAsPendingByContextID, context ID: %s, error: %w", contextID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ArchiveTransactionsCreatedBefore moves up to limit transactions created before the given time which reached a final status, or were deleted,
+// to pending_transaction_archive. It returns the number of archived transactions.
+func (o *PendingTransaction) ArchiveTransactionsCreatedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	finalStatuses := []types.TxStatus{types.TxStatusConfirmed, types.TxStatusConfirmedFailed, types.TxStatusExpired}
+
+	db := o.db.WithContext(ctx)
+	result := db.Exec(`WITH archived AS (
+		DELETE FROM pending_transaction WHERE (id, created_at) IN (
+			SELECT id, created_at FROM pending_transaction
+			WHERE created_at < ? AND (status IN ? OR deleted_at IS NOT NULL)
+			ORDER BY created_at LIMIT ?
+		) RETURNING `+pendingTransactionColumns+`
+	) INSERT INTO pending_transaction_archive (`+pendingTransactionColumns+`) SELECT `+pendingTransactionColumns+` FROM archived`, before, finalStatuses, limit)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to ArchiveTransactionsCreatedBefore, before: %v, error: %w", before, result.Error)
+	}
+	return result.RowsAffected, nil
+}
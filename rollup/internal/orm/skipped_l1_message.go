@@ -0,0 +1,140 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/types"
+)
+
+// SkippedL1Message represents an L1 message skipped by the L2 sequencer, e.g. because its execution exceeds the circuit
+// capacity, along with the status of its replay.
+type SkippedL1Message struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	// message
+	QueueIndex uint64 `json:"queue_index" gorm:"column:queue_index"`
+	// SkipIndex is the index of the message in the skipped transactions of l2geth.
+	SkipIndex uint64 `json:"skip_index" gorm:"column:skip_index"`
+	TxHash    string `json:"tx_hash" gorm:"column:tx_hash"`
+	Sender    string `json:"sender" gorm:"column:sender"`
+	Target    string `json:"target" gorm:"column:target"`
+	Value     string `json:"value" gorm:"column:value"`
+	GasLimit  uint64 `json:"gas_limit" gorm:"column:gas_limit"`
+	Calldata  string `json:"calldata" gorm:"column:calldata"`
+
+	// skip
+	SkipReason      string `json:"skip_reason" gorm:"column:skip_reason"`
+	SkipBlockNumber uint64 `json:"skip_block_number" gorm:"column:skip_block_number"`
+	SkipBlockHash   string `json:"skip_block_hash" gorm:"column:skip_block_hash"`
+
+	// replay
+	Status         int16  `json:"status" gorm:"column:status;default:1"`
+	ReplayGasLimit uint64 `json:"replay_gas_limit" gorm:"column:replay_gas_limit"`
+	ReplayTxHash   string `json:"replay_tx_hash" gorm:"column:replay_tx_hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewSkippedL1Message creates a new SkippedL1Message database instance.
+func NewSkippedL1Message(db *gorm.DB) *SkippedL1Message {
+	return &SkippedL1Message{db: db}
+}
+
+// TableName returns the table name for the SkippedL1Message model.
+func (*SkippedL1Message) TableName() string {
+	return "skipped_l1_message"
+}
+
+// GetNextSkipIndex returns the skip index following the highest stored skip index, 0 if no message is stored.
+func (o *SkippedL1Message) GetNextSkipIndex(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Select("COALESCE(MAX(skip_index) + 1, 0)")
+
+	var nextSkipIndex uint64
+	if err := db.Row().Scan(&nextSkipIndex); err != nil {
+		return 0, fmt.Errorf("SkippedL1Message.GetNextSkipIndex error: %w", err)
+	}
+	return nextSkipIndex, nil
+}
+
+// GetSkippedL1Messages retrieves at most limit skipped L1 messages following offset, filtered by status unless it is
+// SkippedL1MessageStatusUndefined. The returned messages are sorted in ascending order by their queue index.
+func (o *SkippedL1Message) GetSkippedL1Messages(ctx context.Context, status types.SkippedL1MessageStatus, offset int, limit int) ([]*SkippedL1Message, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	if status != types.SkippedL1MessageStatusUndefined {
+		db = db.Where("status = ?", status)
+	}
+	db = db.Order("queue_index ASC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var messages []*SkippedL1Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("SkippedL1Message.GetSkippedL1Messages error: %w, status: %v", err, status.String())
+	}
+	return messages, nil
+}
+
+// GetSkippedL1MessageByQueueIndex retrieves the skipped L1 message with the given queue index, nil if it does not exist.
+func (o *SkippedL1Message) GetSkippedL1MessageByQueueIndex(ctx context.Context, queueIndex uint64) (*SkippedL1Message, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	var message SkippedL1Message
+	if err := db.First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("SkippedL1Message.GetSkippedL1MessageByQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	return &message, nil
+}
+
+// InsertSkippedL1Messages inserts the skipped L1 messages into the database, messages already stored are ignored.
+func (o *SkippedL1Message) InsertSkippedL1Messages(ctx context.Context, messages []*SkippedL1Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Clauses(clause.OnConflict{DoNothing: true})
+
+	if err := db.Create(&messages).Error; err != nil {
+		return fmt.Errorf("SkippedL1Message.InsertSkippedL1Messages error: %w, first queue index: %v", err, messages[0].QueueIndex)
+	}
+	return nil
+}
+
+// UpdateReplayTxHashAndStatus updates the replay transaction hash, gas limit and status of a skipped L1 message.
+func (o *SkippedL1Message) UpdateReplayTxHashAndStatus(ctx context.Context, queueIndex uint64, replayTxHash string, replayGasLimit uint64, status types.SkippedL1MessageStatus) error {
+	updateFields := make(map[string]interface{})
+	updateFields["replay_tx_hash"] = replayTxHash
+	updateFields["replay_gas_limit"] = replayGasLimit
+	updateFields["status"] = int(status)
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("SkippedL1Message.UpdateReplayTxHashAndStatus error: %w, queue index: %v, status: %v, replayTxHash: %v", err, queueIndex, status.String(), replayTxHash)
+	}
+	return nil
+}
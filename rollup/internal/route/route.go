@@ -0,0 +1,32 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/api"
+	"scroll-tech/rollup/internal/middleware"
+)
+
+// AdminRoute register route for the sender admin api
+func AdminRoute(router *gin.Engine, cfg *config.AdminAPIConfig, adminController *api.AdminController) {
+	router.Use(gin.Recovery())
+
+	r := router.Group("admin/v1")
+	r.Use(middleware.AdminAuthMiddleware(cfg.AuthToken))
+	{
+		r.GET("/senders", adminController.ListSenders)
+		r.GET("/senders/:service/:name/transactions", adminController.ListTransactions)
+		r.POST("/senders/:service/:name/escalate", adminController.Escalate)
+		r.POST("/senders/:service/:name/cancel", adminController.Cancel)
+		r.POST("/senders/:service/:name/resume_stuck", adminController.ResumeStuck)
+		r.POST("/senders/:service/:name/pause", adminController.Pause)
+		r.POST("/senders/:service/:name/resume", adminController.Resume)
+		r.POST("/senders/:service/:name/fee_estimator", adminController.SetFeeEstimator)
+		r.GET("/skipped_l1_messages", adminController.ListSkippedL1Messages)
+		r.GET("/l1_message_skips", adminController.ListHeldL1MessageSkips)
+		r.POST("/l1_message_skips/approve", adminController.ApproveL1MessageSkips)
+		r.GET("/log_levels", adminController.GetLogLevels)
+		r.POST("/log_levels", adminController.SetLogLevels)
+	}
+}